@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// backpressureHeader is the trailer a service sets to "high" when it is
+// close to its concurrency limit.
+const backpressureHeader = "x-backpressure"
+
+// backpressureRetryAfter is how long clients are asked to wait before
+// retrying a request the gateway shed.
+const backpressureRetryAfter = 5 * time.Second
+
+// backpressureInterceptor turns a response carrying x-backpressure: high
+// into an Unavailable error with a RetryInfo detail, so the handler answers
+// 503 and the client backs off. Only that response is shed. The instance
+// that sent it may be the only busy one, and later calls are balanced over
+// every instance of the service.
+//
+// The call has already been handled by then, so a shed create has still
+// created its product.
+func backpressureInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var trailer metadata.MD
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+	if values := trailer.Get(backpressureHeader); len(values) == 0 || values[0] != "high" {
+		return err
+	}
+	st := status.Newf(codes.Unavailable, "%s is shedding load", method)
+	withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(backpressureRetryAfter)})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// shedLoad answers 503 with Retry-After if err asks the caller to retry
+// later, and reports whether it did.
+func shedLoad(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && st.Code() == codes.Unavailable {
+			seconds := (info.RetryDelay.AsDuration() + time.Second - 1) / time.Second
+			w.Header().Set("Retry-After", strconv.Itoa(int(seconds)))
+			http.Error(w, st.Message(), http.StatusServiceUnavailable)
+			return true
+		}
+	}
+	return false
+}
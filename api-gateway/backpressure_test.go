@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"api-gateway/pkg/servicetest"
+	pb "api-gateway/proto/gen/proto"
+	sharedgrpc "shared/grpc"
+)
+
+func TestBackpressuredResponseIsShed(t *testing.T) {
+	var high atomic.Bool
+	reportLoad := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if high.Load() {
+			grpc.SetTrailer(ctx, metadata.Pairs(backpressureHeader, "high"))
+		}
+		return resp, err
+	}
+	userService, productService := servicetest.NewFakeUserService(), servicetest.NewFakeProductService()
+	srv := servicetest.NewServer(userService, productService, grpc.UnaryInterceptor(reportLoad))
+	t.Cleanup(srv.Close)
+	sd = &ServiceDiscovery{pool: sharedgrpc.New(4, 0, append(srv.DialOptions(), grpc.WithChainUnaryInterceptor(backpressureInterceptor))...)}
+	t.Cleanup(func() { sd.pool.Close() })
+	products = newProductCache()
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	productService.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+
+	high.Store(true)
+	for _, path := range []string{"/api/products/7", "/api/users/1", "/api/purchases/user/1/product/7"} {
+		rec := serve(http.MethodGet, path, "")
+		if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "5" {
+			t.Errorf("GET %s under backpressure = %d with Retry-After %q, want 503 and 5", path, rec.Code, rec.Header().Get("Retry-After"))
+		}
+	}
+
+	// Only the responses that carried the trailer are shed; the next ones
+	// are served as usual.
+	high.Store(false)
+	for _, path := range []string{"/api/products/7", "/api/users/1", "/api/purchases/user/1/product/7"} {
+		if rec := serve(http.MethodGet, path, ""); rec.Code != http.StatusOK {
+			t.Errorf("GET %s after backpressure = %d, want 200: %s", path, rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// invalidationDiscoveryInterval is how often the gateway looks for new
+// products-service instances to receive cache invalidations from.
+const invalidationDiscoveryInterval = 10 * time.Second
+
+// productCacheTTL bounds how long an entry is served, in case an
+// invalidation is lost without the stream closing.
+const productCacheTTL = 5 * time.Minute
+
+// productCacheMaxEntries caps the cache. Once full, an arbitrary entry is
+// evicted for each new one.
+const productCacheMaxEntries = 10000
+
+// invalidationDedupWindow is how many recent sequence numbers the cache
+// remembers to recognise an invalidation it has already applied.
+const invalidationDedupWindow = 1024
+
+// productCache holds GetProduct results so repeat lookups skip the
+// products-service round trip. Entries are evicted by the invalidation
+// messages products-service instances stream to the gateway.
+//
+// Every instance relays every change from the shared outbox, so one open
+// stream is enough to hear of all of them. The gateway streams from every
+// healthy instance anyway, so losing one does not empty the cache. While no
+// stream is open the cache is bypassed, and it is flushed when the first
+// stream opens again.
+type productCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedProduct
+	now     func() time.Time
+
+	// generation changes with every accepted invalidation and flush. A
+	// fetch that started in an earlier generation may have read a product
+	// that has since changed, so its result is not cached.
+	generation uint64
+
+	// seen holds the sequence numbers applied above seenFloor. The same
+	// change arrives once on every stream, possibly out of order.
+	seen      map[int64]bool
+	seenFloor int64
+
+	// streaming are the addresses with an open invalidation stream.
+	streaming map[string]bool
+}
+
+type cachedProduct struct {
+	product   *pb.Product
+	expiresAt time.Time
+}
+
+func newProductCache() *productCache {
+	return &productCache{
+		entries:   make(map[string]cachedProduct),
+		now:       time.Now,
+		seen:      make(map[int64]bool),
+		streaming: make(map[string]bool),
+	}
+}
+
+// connected reports whether an invalidation stream is open. The caller must
+// hold c.mu.
+func (c *productCache) connected() bool {
+	return len(c.streaming) > 0
+}
+
+// get returns the cached product and, on a miss, the generation to pass to
+// set once the product has been fetched.
+func (c *productCache) get(id string) (*pb.Product, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.connected() {
+		return nil, c.generation, false
+	}
+	entry, ok := c.entries[id]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, c.generation, false
+	}
+	return entry.product, c.generation, true
+}
+
+// set caches product unless an invalidation arrived since generation was
+// returned by get.
+func (c *productCache) set(product *pb.Product, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation || !c.connected() {
+		return
+	}
+	if _, ok := c.entries[product.Id]; !ok && len(c.entries) >= productCacheMaxEntries {
+		for id := range c.entries {
+			delete(c.entries, id)
+			break
+		}
+	}
+	c.entries[product.Id] = cachedProduct{product: product, expiresAt: c.now().Add(productCacheTTL)}
+}
+
+func (c *productCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked()
+}
+
+func (c *productCache) flushLocked() {
+	c.entries = make(map[string]cachedProduct)
+	c.generation++
+}
+
+// streamOpened records that address is streaming invalidations. If it is the
+// only open stream, anything may have changed while the gateway was not
+// listening, so the cache is flushed.
+func (c *productCache) streamOpened(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected() {
+		c.flushLocked()
+	}
+	c.streaming[address] = true
+}
+
+// streamClosed records that the invalidation stream from address closed.
+func (c *productCache) streamClosed(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.streaming, address)
+	if !c.connected() {
+		c.flushLocked()
+	}
+}
+
+// apply evicts the entry named by msg. Messages already applied are ignored,
+// as are messages older than the cached entry.
+func (c *productCache) apply(msg *pb.CacheInvalidation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if msg.FlushAll {
+		c.flushLocked()
+		return
+	}
+	if msg.Sequence <= c.seenFloor || c.seen[msg.Sequence] {
+		return
+	}
+	c.markSeen(msg.Sequence)
+
+	id, ok := strings.CutPrefix(msg.Key, "product:")
+	if !ok {
+		return
+	}
+	entry, ok := c.entries[id]
+	if ok && msg.UpdatedAt != nil && entry.product.UpdatedAt != nil && msg.UpdatedAt.AsTime().Before(entry.product.UpdatedAt.AsTime()) {
+		return
+	}
+	// A GetProduct for id may be in flight and about to cache what it read
+	// before this update.
+	c.generation++
+	delete(c.entries, id)
+}
+
+// markSeen remembers sequence, forgetting the oldest half of the window once
+// it is full. The caller must hold c.mu.
+func (c *productCache) markSeen(sequence int64) {
+	c.seen[sequence] = true
+	if len(c.seen) <= invalidationDedupWindow {
+		return
+	}
+	sequences := make([]int64, 0, len(c.seen))
+	for seq := range c.seen {
+		sequences = append(sequences, seq)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for _, seq := range sequences[:len(sequences)/2] {
+		delete(c.seen, seq)
+	}
+	c.seenFloor = sequences[len(sequences)/2-1]
+}
+
+// watchProductInvalidations keeps an invalidation stream open to every
+// healthy products-service instance registered in Consul.
+func (sd *ServiceDiscovery) watchProductInvalidations(cache *productCache) {
+	var mu sync.Mutex
+	watching := make(map[string]bool)
+
+	for {
+		addresses, err := sd.healthyAddresses("products-service")
+		if err != nil {
+			log.Printf("Failed to discover products-service for cache invalidation: %v", err)
+		}
+		for _, address := range addresses {
+			mu.Lock()
+			if watching[address] {
+				mu.Unlock()
+				continue
+			}
+			watching[address] = true
+			mu.Unlock()
+
+			go func(address string) {
+				sd.streamInvalidations(address, cache)
+				mu.Lock()
+				delete(watching, address)
+				mu.Unlock()
+			}(address)
+		}
+		time.Sleep(invalidationDiscoveryInterval)
+	}
+}
+
+func (sd *ServiceDiscovery) streamInvalidations(address string, cache *productCache) {
+	// The instance is dialed by address, but its certificate names the
+	// service.
+	conn, err := grpc.Dial(address, append(sd.dialOptions(), grpc.WithAuthority("products-service"))...)
+	if err != nil {
+		log.Printf("Failed to connect to products-service at %s: %v", address, err)
+		return
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewProductServiceClient(conn).WatchCacheInvalidations(context.Background(), &pb.WatchCacheInvalidationsRequest{})
+	if err != nil {
+		log.Printf("Failed to watch cache invalidations from %s: %v", address, err)
+		return
+	}
+
+	cache.streamOpened(address)
+	log.Printf("Watching cache invalidations from %s", address)
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			log.Printf("Cache invalidation stream from %s closed: %v", address, err)
+			cache.streamClosed(address)
+			return
+		}
+		cache.apply(msg)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// newConnectedCache returns a cache streaming invalidations from a single
+// instance, with a clock the test controls.
+func newConnectedCache(now *time.Time) *productCache {
+	c := newProductCache()
+	c.now = func() time.Time { return *now }
+	c.streamOpened("a:50052")
+	return c
+}
+
+// fetch caches product the way getProductHandler does.
+func fetch(c *productCache, product *pb.Product) {
+	_, generation, _ := c.get(product.Id)
+	c.set(product, generation)
+}
+
+func TestProductCacheSkipsSetAfterInvalidation(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+
+	_, generation, ok := c.get("1")
+	if ok {
+		t.Fatal("hit on an empty cache")
+	}
+	// The product changes on another replica while GetProduct is in flight.
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 1, Source: "b"})
+	c.set(&pb.Product{Id: "1", Name: "stale"}, generation)
+
+	if product, _, ok := c.get("1"); ok {
+		t.Errorf("cached %q read before the invalidation", product.Name)
+	}
+}
+
+func TestProductCacheExpiresEntries(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+	fetch(c, &pb.Product{Id: "1"})
+
+	now = now.Add(productCacheTTL - time.Second)
+	if _, _, ok := c.get("1"); !ok {
+		t.Fatal("miss before the TTL")
+	}
+	now = now.Add(time.Second)
+	if _, _, ok := c.get("1"); ok {
+		t.Error("hit after the TTL")
+	}
+}
+
+func TestProductCacheCapsEntries(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+	for i := 0; i < productCacheMaxEntries+10; i++ {
+		fetch(c, &pb.Product{Id: fmt.Sprint(i)})
+	}
+	if got := len(c.entries); got != productCacheMaxEntries {
+		t.Errorf("len(entries) = %d, want %d", got, productCacheMaxEntries)
+	}
+	if _, _, ok := c.get(fmt.Sprint(productCacheMaxEntries + 9)); !ok {
+		t.Error("the newest entry was evicted")
+	}
+}
+
+func TestProductCacheBypassedWithoutStreams(t *testing.T) {
+	c := newProductCache()
+	fetch(c, &pb.Product{Id: "1"})
+	if _, _, ok := c.get("1"); ok {
+		t.Fatal("cache used before any stream opened")
+	}
+
+	c.streamOpened("a:50052")
+	c.streamOpened("b:50052")
+	fetch(c, &pb.Product{Id: "1"})
+	if _, _, ok := c.get("1"); !ok {
+		t.Fatal("miss with both streams open")
+	}
+
+	// b relays every change a did, so losing a loses nothing.
+	c.streamClosed("a:50052")
+	if _, _, ok := c.get("1"); !ok {
+		t.Error("entry dropped although the stream from b is still open")
+	}
+	c.streamOpened("a:50052")
+	if _, _, ok := c.get("1"); !ok {
+		t.Error("entry dropped when a reconnected while b was open")
+	}
+
+	c.streamClosed("a:50052")
+	c.streamClosed("b:50052")
+	if _, _, ok := c.get("1"); ok {
+		t.Error("cache used with no stream open")
+	}
+	c.streamOpened("b:50052")
+	if _, _, ok := c.get("1"); ok {
+		t.Error("entry survived a period with no stream open")
+	}
+}
+
+func TestProductCacheApplyOrdering(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+	updatedAt := timestamppb.New(now)
+	fetch(c, &pb.Product{Id: "1", UpdatedAt: updatedAt})
+
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 5, Source: "b", UpdatedAt: timestamppb.New(now.Add(-time.Minute))})
+	if _, _, ok := c.get("1"); !ok {
+		t.Fatal("evicted by an invalidation older than the entry")
+	}
+
+	fetch(c, &pb.Product{Id: "1", UpdatedAt: updatedAt})
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 5, Source: "a", UpdatedAt: timestamppb.New(now.Add(time.Minute))})
+	if _, _, ok := c.get("1"); !ok {
+		t.Fatal("evicted by a sequence number already seen from another instance")
+	}
+
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 6, Source: "b", UpdatedAt: timestamppb.New(now.Add(time.Minute))})
+	if _, _, ok := c.get("1"); ok {
+		t.Error("not evicted by a newer invalidation")
+	}
+}
+
+func TestProductCacheAppliesLateSequences(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+	fetch(c, &pb.Product{Id: "1"})
+	fetch(c, &pb.Product{Id: "2"})
+
+	// Instance a relays 8 before instance b relays 7.
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 8, Source: "a"})
+	c.apply(&pb.CacheInvalidation{Key: "product:2", Sequence: 7, Source: "b"})
+	if _, _, ok := c.get("2"); ok {
+		t.Error("invalidation 7 ignored because 8 arrived first")
+	}
+}
+
+func TestProductCacheDedupWindow(t *testing.T) {
+	now := time.Now()
+	c := newConnectedCache(&now)
+	for seq := int64(1); seq <= 3*invalidationDedupWindow; seq++ {
+		c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: seq, Source: "a"})
+	}
+	if len(c.seen) > invalidationDedupWindow {
+		t.Errorf("remembering %d sequence numbers, want at most %d", len(c.seen), invalidationDedupWindow)
+	}
+
+	fetch(c, &pb.Product{Id: "1"})
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 3 * invalidationDedupWindow, Source: "b"})
+	c.apply(&pb.CacheInvalidation{Key: "product:1", Sequence: 1, Source: "b"})
+	if _, _, ok := c.get("1"); !ok {
+		t.Error("evicted by an invalidation already applied")
+	}
+}
+
+// fakeOutbox stands in for the product_outbox table that every
+// products-service instance relays.
+type fakeOutbox struct {
+	mu       sync.Mutex
+	sequence int64
+	watchers map[chan *pb.CacheInvalidation]bool
+}
+
+func (o *fakeOutbox) watch() chan *pb.CacheInvalidation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ch := make(chan *pb.CacheInvalidation, 16)
+	o.watchers[ch] = true
+	return ch
+}
+
+func (o *fakeOutbox) unwatch(ch chan *pb.CacheInvalidation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.watchers, ch)
+}
+
+// changed records a change to product id, as a write on any instance does.
+func (o *fakeOutbox) changed(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sequence++
+	for ch := range o.watchers {
+		ch <- &pb.CacheInvalidation{Key: "product:" + id, Sequence: o.sequence}
+	}
+}
+
+// fakeProductsInstance is one products-service replica.
+type fakeProductsInstance struct {
+	pb.UnimplementedProductServiceServer
+	name   string
+	outbox *fakeOutbox
+}
+
+func (f *fakeProductsInstance) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
+	return &pb.ProductResponse{Product: &pb.Product{Id: req.Id, Name: "read from " + f.name}}, nil
+}
+
+func (f *fakeProductsInstance) WatchCacheInvalidations(req *pb.WatchCacheInvalidationsRequest, stream pb.ProductService_WatchCacheInvalidationsServer) error {
+	ch := f.outbox.watch()
+	defer f.outbox.unwatch(ch)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-ch:
+			msg = proto.Clone(msg).(*pb.CacheInvalidation)
+			msg.Source = f.name
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startProductsInstance serves a replica on a local port and returns its
+// address and a function that stops it.
+func startProductsInstance(t *testing.T, name string, outbox *fakeOutbox) (string, func()) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterProductServiceServer(srv, &fakeProductsInstance{name: name, outbox: outbox})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), srv.Stop
+}
+
+// fetchFrom caches product id the way getProductHandler does, reading it
+// from the instance at address.
+func fetchFrom(t *testing.T, c *productCache, address, id string) {
+	t.Helper()
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, generation, _ := c.get(id)
+	res, err := pb.NewProductServiceClient(conn).GetProduct(context.Background(), &pb.GetProductRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.set(res.Product, generation)
+	if _, _, ok := c.get(id); !ok {
+		t.Fatalf("product %s read from %s was not cached", id, address)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func openStreams(c *productCache) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.streaming)
+}
+
+func TestInvalidationReachesEveryGateway(t *testing.T) {
+	outbox := &fakeOutbox{watchers: make(map[chan *pb.CacheInvalidation]bool)}
+	addressA, stopA := startProductsInstance(t, "a", outbox)
+	addressB, _ := startProductsInstance(t, "b", outbox)
+
+	// Two gateway instances, each streaming from both replicas.
+	gateways := []*productCache{newProductCache(), newProductCache()}
+	discovery := &ServiceDiscovery{}
+	for _, c := range gateways {
+		go discovery.streamInvalidations(addressA, c)
+		go discovery.streamInvalidations(addressB, c)
+	}
+	for i, c := range gateways {
+		c := c
+		waitFor(t, fmt.Sprintf("gateway %d to open both streams", i+1), func() bool { return openStreams(c) == 2 })
+	}
+
+	// The first gateway caches product 1 as read from replica b, the second
+	// as read from replica a. The product then changes on replica a.
+	fetchFrom(t, gateways[0], addressB, "1")
+	fetchFrom(t, gateways[1], addressA, "1")
+	fetchFrom(t, gateways[0], addressB, "2")
+	outbox.changed("1")
+	for i, c := range gateways {
+		c := c
+		waitFor(t, fmt.Sprintf("gateway %d to evict product 1", i+1), func() bool {
+			_, _, ok := c.get("1")
+			return !ok
+		})
+	}
+	if _, _, ok := gateways[0].get("2"); !ok {
+		t.Error("product 2 was evicted by a change to product 1")
+	}
+
+	// With replica a gone, changes still arrive through b.
+	stopA()
+	waitFor(t, "the stream from replica a to close", func() bool { return openStreams(gateways[0]) == 1 })
+	if _, _, ok := gateways[0].get("2"); !ok {
+		t.Fatal("product 2 was evicted when one of two streams closed")
+	}
+	outbox.changed("2")
+	waitFor(t, "product 2 to be evicted through replica b", func() bool {
+		_, _, ok := gateways[0].get("2")
+		return !ok
+	})
+}
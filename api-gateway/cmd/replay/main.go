@@ -0,0 +1,226 @@
+// Command replay sends the requests in a service's journal to a target
+// server and compares the latencies and status codes with the recorded ones.
+//
+//	go run ./cmd/replay -journal /var/lib/journal -target localhost:50052 -speed 2
+//
+// -speed scales the recorded gaps between requests: 1 keeps the original
+// timing, 2 replays twice as fast and 0 sends requests as fast as
+// -concurrency allows. Requests are sent as recorded, except that fields
+// masked at record time keep their masked values.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/pkg/journal"
+	pb "api-gateway/proto/gen/proto"
+)
+
+// rawCodec passes already-serialized messages through unchanged. It keeps
+// the "proto" name so the server decodes them as usual.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec cannot marshal %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec cannot unmarshal into %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// metadataFlag collects repeated -metadata key=value flags.
+type metadataFlag map[string]string
+
+func (m metadataFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m metadataFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("metadata %q is not key=value", value)
+	}
+	m[strings.ToLower(k)] = v
+	return nil
+}
+
+type result struct {
+	recorded, replayed         time.Duration
+	recordedCode, replayedCode codes.Code
+}
+
+func main() {
+	journalPath := flag.String("journal", "", "journal file or directory to replay")
+	target := flag.String("target", "", "address of the server to replay against")
+	speed := flag.Float64("speed", 1, "replay speed relative to the recording; 0 sends as fast as possible")
+	concurrency := flag.Int("concurrency", 16, "maximum requests in flight")
+	timeout := flag.Duration("timeout", 10*time.Second, "per-request timeout")
+	apiKey := flag.String("api-key", "", "replace the recorded x-api-key with this key")
+	overrides := metadataFlag{}
+	flag.Var(overrides, "metadata", "key=value to set on every request; may be repeated")
+	flag.Parse()
+
+	if *journalPath == "" || *target == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *speed < 0 || *concurrency < 1 {
+		log.Fatal("-speed must not be negative and -concurrency must be positive")
+	}
+	if *apiKey != "" {
+		overrides["x-api-key"] = *apiKey
+	}
+
+	entries, err := journal.ReadAll(*journalPath)
+	if err != nil {
+		log.Fatalf("Failed to read journal: %v", err)
+	}
+	if len(entries) == 0 {
+		log.Fatalf("No journal entries found in %s", *journalPath)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.AsTime().Before(entries[j].StartedAt.AsTime())
+	})
+
+	conn, err := grpc.NewClient(*target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})),
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", *target, err)
+	}
+	defer conn.Close()
+
+	log.Printf("Replaying %d requests against %s", len(entries), *target)
+	results := replay(conn, entries, *speed, *concurrency, *timeout, overrides)
+	report(os.Stdout, results)
+}
+
+func replay(conn *grpc.ClientConn, entries []*pb.JournalEntry, speed float64, concurrency int, timeout time.Duration, overrides map[string]string) []result {
+	results := make([]result, len(entries))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	first := entries[0].StartedAt.AsTime()
+	start := time.Now()
+	for i, entry := range entries {
+		if speed > 0 {
+			offset := time.Duration(float64(entry.StartedAt.AsTime().Sub(first)) / speed)
+			time.Sleep(time.Until(start.Add(offset)))
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, entry *pb.JournalEntry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = send(conn, entry, timeout, overrides)
+		}(i, entry)
+	}
+	wg.Wait()
+	return results
+}
+
+func send(conn *grpc.ClientConn, entry *pb.JournalEntry, timeout time.Duration, overrides map[string]string) result {
+	md := metadata.New(entry.Metadata)
+	for k, v := range overrides {
+		md.Set(k, v)
+	}
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), md), timeout)
+	defer cancel()
+
+	req, resp := entry.Request, []byte(nil)
+	start := time.Now()
+	err := conn.Invoke(ctx, entry.Method, &req, &resp)
+	return result{
+		recorded:     entry.Latency.AsDuration(),
+		replayed:     time.Since(start),
+		recordedCode: codes.Code(entry.Code),
+		replayedCode: status.Code(err),
+	}
+}
+
+func report(w *os.File, results []result) {
+	recorded := make([]time.Duration, len(results))
+	replayed := make([]time.Duration, len(results))
+	type codeCount struct{ recorded, replayed int }
+	byCode := make(map[codes.Code]*codeCount)
+	mismatches := 0
+	for i, r := range results {
+		recorded[i], replayed[i] = r.recorded, r.replayed
+		for _, c := range []codes.Code{r.recordedCode, r.replayedCode} {
+			if byCode[c] == nil {
+				byCode[c] = &codeCount{}
+			}
+		}
+		byCode[r.recordedCode].recorded++
+		byCode[r.replayedCode].replayed++
+		if r.recordedCode != r.replayedCode {
+			mismatches++
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LATENCY\tRECORDED\tREPLAYED")
+	for _, p := range []float64{50, 90, 99} {
+		fmt.Fprintf(tw, "p%.0f\t%v\t%v\n", p, percentile(recorded, p), percentile(replayed, p))
+	}
+	fmt.Fprintln(tw, "\t\t")
+	fmt.Fprintln(tw, "CODE\tRECORDED\tREPLAYED")
+	codeList := make([]codes.Code, 0, len(byCode))
+	for c := range byCode {
+		codeList = append(codeList, c)
+	}
+	sort.Slice(codeList, func(i, j int) bool { return codeList[i] < codeList[j] })
+	for _, c := range codeList {
+		fmt.Fprintf(tw, "%v\t%d\t%d\n", c, byCode[c].recorded, byCode[c].replayed)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d of %d requests returned a different code than recorded\n", mismatches, len(results))
+}
+
+// percentile returns the p-th percentile of durations by the nearest-rank
+// method. It sorts durations in place.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := int(math.Ceil(p/100*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank]
+}
@@ -1,12 +1,14 @@
 module api-gateway
 
-go 1.21
+go 1.22
 
 require (
 	github.com/gorilla/mux v1.8.0
 	github.com/hashicorp/consul/api v1.25.1
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237
 	google.golang.org/grpc v1.64.0
 	google.golang.org/protobuf v1.34.2
+	shared v0.0.0
 )
 
 require (
@@ -26,5 +28,6 @@ require (
 	golang.org/x/net v0.22.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
 )
+
+replace shared => ../shared
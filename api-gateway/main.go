@@ -7,29 +7,51 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"api-gateway/pkg/client"
+	"api-gateway/pkg/consulresolver"
 	pb "api-gateway/proto/gen/proto"
 	consulapi "github.com/hashicorp/consul/api"
+	sharedgrpc "shared/grpc"
 )
 
 type ServiceDiscovery struct {
-	consul      *consulapi.Client
-	mu          sync.RWMutex
-	connections map[string]*grpc.ClientConn
+	consul *consulapi.Client
+	// pool holds the connections to each service. Requests share them
+	// round-robin, so a busy service's calls are spread over several
+	// connections rather than queued on one.
+	pool *sharedgrpc.ConnectionPool
+	// creds secures connections to the services. Nil means plaintext.
+	creds  credentials.TransportCredentials
+	apiKey string
 }
 
+// Connection pool defaults, overridden by GRPC_POOL_SIZE and
+// GRPC_POOL_IDLE_TIMEOUT.
+const (
+	defaultPoolSize        = 4
+	defaultPoolIdleTimeout = 5 * time.Minute
+)
+
 type UserPurchaseData struct {
-	User    *pb.User    `json:"user"`
+	User    client.User `json:"user"`
 	Product *pb.Product `json:"product"`
 }
 
 var sd *ServiceDiscovery
+var products *productCache
 
 func main() {
 	// Initialize service discovery
@@ -43,24 +65,66 @@ func main() {
 		log.Fatalf("Failed to create consul client: %v", err)
 	}
 
+	creds, err := clientCredentials()
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
 	sd = &ServiceDiscovery{
-		consul:      consul,
-		connections: make(map[string]*grpc.ClientConn),
+		consul: consul,
+		creds:  creds,
+		apiKey: os.Getenv("API_KEY"),
 	}
+	poolSize, poolIdleTimeout, err := poolConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure the connection pool: %v", err)
+	}
+	// Instances are discovered and balanced by the Consul resolver, which
+	// prefers instances that are not degraded.
+	sd.pool = sharedgrpc.New(poolSize, poolIdleTimeout, append(sd.dialOptions(),
+		grpc.WithResolvers(consulresolver.NewBuilder(consul)),
+		grpc.WithDefaultServiceConfig(consulresolver.ServiceConfig),
+		grpc.WithChainUnaryInterceptor(backpressureInterceptor),
+	)...)
+	defer sd.pool.Close()
+
+	if spec := os.Getenv("GATEWAY_TIMEOUTS"); spec != "" {
+		if timeouts, err = parseRouteTimeouts(spec); err != nil {
+			log.Fatalf("Failed to parse GATEWAY_TIMEOUTS: %v", err)
+		}
+	}
+
+	products = newProductCache()
+	go sd.watchProductInvalidations(products)
 
 	// Wait for services to be ready
 	log.Println("Waiting for services to register with Consul...")
 	time.Sleep(15 * time.Second)
 
+	r := newRouter()
+
+	log.Println("API Gateway listening on port 8080...")
+	if err := http.ListenAndServe(":8080", r); err != nil {
+		log.Fatalf("Failed to start API Gateway: %v", err)
+	}
+}
+
+// newRouter returns the gateway's HTTP routes.
+func newRouter() *mux.Router {
 	r := mux.NewRouter()
 
 	// User routes
 	r.HandleFunc("/api/users", createUserHandler).Methods("POST")
 	r.HandleFunc("/api/users/{id}", getUserHandler).Methods("GET")
+	r.HandleFunc("/api/users/{id}/preferences", getPreferencesHandler).Methods("GET")
+	r.HandleFunc("/api/users/{id}/preferences/{key}", setPreferenceHandler).Methods("PUT")
 
 	// Product routes
 	r.HandleFunc("/api/products", createProductHandler).Methods("POST")
 	r.HandleFunc("/api/products/{id}", getProductHandler).Methods("GET")
+	r.HandleFunc("/api/products/{id}/qrcode", getProductQRCodeHandler).Methods("GET")
+
+	// Cart routes
+	r.HandleFunc("/api/cart/total", calculateCartTotalHandler).Methods("POST")
 
 	// Composite endpoint
 	r.HandleFunc("/api/purchases/user/{userId}/product/{productId}", getPurchaseDataHandler).Methods("GET")
@@ -68,22 +132,51 @@ func main() {
 	// Health check endpoint
 	r.HandleFunc("/health", healthHandler).Methods("GET")
 
-	log.Println("API Gateway listening on port 8080...")
-	if err := http.ListenAndServe(":8080", r); err != nil {
-		log.Fatalf("Failed to start API Gateway: %v", err)
+	return r
+}
+
+// poolConfigFromEnv reads GRPC_POOL_SIZE, the most connections opened to each
+// service, and GRPC_POOL_IDLE_TIMEOUT, after which an unused connection is
+// closed.
+func poolConfigFromEnv() (int, time.Duration, error) {
+	size, idleTimeout := defaultPoolSize, defaultPoolIdleTimeout
+	if value := os.Getenv("GRPC_POOL_SIZE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("GRPC_POOL_SIZE=%q is not a positive integer", value)
+		}
+		size = n
 	}
+	if value := os.Getenv("GRPC_POOL_IDLE_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, 0, fmt.Errorf("GRPC_POOL_IDLE_TIMEOUT=%q is not a duration", value)
+		}
+		idleTimeout = d
+	}
+	return size, idleTimeout, nil
 }
 
-func (sd *ServiceDiscovery) getServiceConnection(serviceName string) (*grpc.ClientConn, error) {
-	sd.mu.Lock()
-	defer sd.mu.Unlock()
+// serviceTarget is the gRPC target the Consul resolver resolves to the
+// instances of serviceName.
+func serviceTarget(serviceName string) string {
+	return fmt.Sprintf("%s:///%s", consulresolver.Scheme, serviceName)
+}
 
-	// Check if we already have a connection
-	if conn, exists := sd.connections[serviceName]; exists {
-		return conn, nil
+// getServiceConnection acquires a pooled connection to serviceName, waiting
+// for one until ctx is done. The caller must pass it to release when its
+// calls are finished.
+func (sd *ServiceDiscovery) getServiceConnection(ctx context.Context, serviceName string) (conn *grpc.ClientConn, release func(), err error) {
+	conn, err = sd.pool.Acquire(ctx, serviceTarget(serviceName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to service %s: %w", serviceName, err)
 	}
+	return conn, func() { sd.pool.Release(conn) }, nil
+}
 
-	// Discover service
+// healthyAddresses returns the host:port of every healthy instance of
+// serviceName registered in Consul.
+func (sd *ServiceDiscovery) healthyAddresses(serviceName string) ([]string, error) {
 	services, _, err := sd.consul.Health().Service(serviceName, "", true, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover service %s: %w", serviceName, err)
@@ -93,35 +186,86 @@ func (sd *ServiceDiscovery) getServiceConnection(serviceName string) (*grpc.Clie
 		return nil, fmt.Errorf("no healthy instances of service %s found", serviceName)
 	}
 
-	// Use first healthy instance
-	service := services[0].Service
-	address := fmt.Sprintf("%s:%d", service.Address, service.Port)
+	addresses := make([]string, 0, len(services))
+	for _, entry := range services {
+		addresses = append(addresses, fmt.Sprintf("%s:%d", entry.Service.Address, entry.Service.Port))
+	}
+	return addresses, nil
+}
 
-	// Create gRPC connection
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to service %s at %s: %w", serviceName, address, err)
+func (sd *ServiceDiscovery) dialOptions() []grpc.DialOption {
+	creds := sd.creds
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if sd.apiKey != "" {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(apiKeyInterceptor(sd.apiKey)),
+			grpc.WithStreamInterceptor(apiKeyStreamInterceptor(sd.apiKey)),
+		)
 	}
+	return opts
+}
+
+// apiKeyInterceptor attaches the gateway's API key to every outgoing call.
+func apiKeyInterceptor(apiKey string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
 
-	sd.connections[serviceName] = conn
-	log.Printf("Connected to %s at %s", serviceName, address)
-	return conn, nil
+func apiKeyStreamInterceptor(apiKey string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
 }
 
-func getUsersClient() (pb.UserServiceClient, error) {
-	conn, err := sd.getServiceConnection("users-service")
+// getUsersClient returns a client for users-service on a pooled
+// connection, and the func that returns the connection to the pool.
+func getUsersClient(ctx context.Context) (*client.UsersClient, func(), error) {
+	conn, release, err := sd.getServiceConnection(ctx, "users-service")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return pb.NewUserServiceClient(conn), nil
+	return client.NewUsersClientFromConn(conn), release, nil
 }
 
-func getProductsClient() (pb.ProductServiceClient, error) {
-	conn, err := sd.getServiceConnection("products-service")
+// getProductsClient returns a client for products-service like
+// getUsersClient. The handlers call its Raw methods, since they pass the
+// proto messages through to the response.
+func getProductsClient(ctx context.Context) (*client.ProductsClient, func(), error) {
+	conn, release, err := sd.getServiceConnection(ctx, "products-service")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	return client.NewProductsClientFromConn(conn), release, nil
+}
+
+// httpStatusFromGRPC maps a gRPC error onto the closest HTTP status code.
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
 	}
-	return pb.NewProductServiceClient(conn), nil
 }
 
 // Health check handler
@@ -136,11 +280,15 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 
 // User Handlers
 func createUserHandler(w http.ResponseWriter, r *http.Request) {
-	client, err := getUsersClient()
+	ctx, cancel, budget := newRequestBudget(r, "users")
+	defer cancel()
+
+	users, release, err := getUsersClient(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
 		return
 	}
+	defer release()
 
 	var req pb.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -148,46 +296,123 @@ func createUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := client.CreateUser(context.Background(), &req)
+	user, err := users.CreateUser(ctx, req.Name, req.Email)
 	if err != nil {
+		if budget.timedOut(w, "users-service", err) || shedLoad(w, err) {
+			return
+		}
 		log.Printf("Error creating user: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(res.User)
+	json.NewEncoder(w).Encode(user)
 }
 
 func getUserHandler(w http.ResponseWriter, r *http.Request) {
-	client, err := getUsersClient()
+	ctx, cancel, budget := newRequestBudget(r, "users")
+	defer cancel()
+
+	users, release, err := getUsersClient(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
 		return
 	}
+	defer release()
 
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	res, err := client.GetUser(context.Background(), &pb.GetUserRequest{Id: id})
+	user, err := users.GetUser(ctx, id)
 	if err != nil {
+		if budget.timedOut(w, "users-service", err) || shedLoad(w, err) {
+			return
+		}
 		log.Printf("Error getting user: %v", err)
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(res.User)
+	json.NewEncoder(w).Encode(user)
+}
+
+func setPreferenceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, budget := newRequestBudget(r, "preferences")
+	defer cancel()
+
+	users, release, err := getUsersClient(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	defer release()
+
+	vars := mux.Vars(r)
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := users.SetPreference(ctx, vars["id"], vars["key"], body.Value); err != nil {
+		if budget.timedOut(w, "users-service", err) || shedLoad(w, err) {
+			return
+		}
+		log.Printf("Error setting preference: %v", err)
+		http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, budget := newRequestBudget(r, "preferences")
+	defer cancel()
+
+	users, release, err := getUsersClient(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	defer release()
+
+	vars := mux.Vars(r)
+	var keys []string
+	if list := r.URL.Query().Get("keys"); list != "" {
+		keys = strings.Split(list, ",")
+	}
+
+	preferences, err := users.GetPreferences(ctx, vars["id"], keys...)
+	if err != nil {
+		if budget.timedOut(w, "users-service", err) || shedLoad(w, err) {
+			return
+		}
+		log.Printf("Error getting preferences: %v", err)
+		http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preferences)
 }
 
 // Product Handlers
 func createProductHandler(w http.ResponseWriter, r *http.Request) {
-	client, err := getProductsClient()
+	ctx, cancel, budget := newRequestBudget(r, "products")
+	defer cancel()
+
+	productsClient, release, err := getProductsClient(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
 		return
 	}
+	defer release()
 
 	var req pb.CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -195,47 +420,144 @@ func createProductHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := client.CreateProduct(context.Background(), &req)
+	res, err := productsClient.Raw().CreateProduct(ctx, &req)
 	if err != nil {
+		if budget.timedOut(w, "products-service", err) || shedLoad(w, err) {
+			return
+		}
 		log.Printf("Error creating product: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	// A double-submitted form gets the product created the first time.
+	if res.Duplicate {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
 	json.NewEncoder(w).Encode(res.Product)
 }
 
 func getProductHandler(w http.ResponseWriter, r *http.Request) {
-	client, err := getProductsClient()
+	ctx, cancel, budget := newRequestBudget(r, "products")
+	defer cancel()
+
+	productsClient, release, err := getProductsClient(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
 		return
 	}
+	defer release()
 
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	res, err := client.GetProduct(context.Background(), &pb.GetProductRequest{Id: id})
+	product, generation, ok := products.get(id)
+	if ok {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(product)
+		return
+	}
+
+	res, err := productsClient.Raw().GetProduct(ctx, &pb.GetProductRequest{Id: id})
 	if err != nil {
+		if budget.timedOut(w, "products-service", err) || shedLoad(w, err) {
+			return
+		}
 		log.Printf("Error getting product: %v", err)
 		http.Error(w, "Product not found", http.StatusNotFound)
 		return
 	}
+	products.set(res.Product, generation)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(res.Product)
 }
 
+func getProductQRCodeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, budget := newRequestBudget(r, "products")
+	defer cancel()
+
+	productsClient, release, err := getProductsClient(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	defer release()
+
+	vars := mux.Vars(r)
+	req := &pb.GetProductQRCodeRequest{ProductId: vars["id"]}
+	if size := r.URL.Query().Get("size"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			http.Error(w, "Invalid size", http.StatusBadRequest)
+			return
+		}
+		req.Size = int32(n)
+	}
+	if r.URL.Query().Get("format") == "svg" {
+		req.Format = pb.QRFormat_QR_FORMAT_SVG
+	}
+
+	res, err := productsClient.Raw().GetProductQRCode(ctx, req)
+	if err != nil {
+		if budget.timedOut(w, "products-service", err) || shedLoad(w, err) {
+			return
+		}
+		log.Printf("Error getting product QR code: %v", err)
+		http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", res.ContentType)
+	w.Write(res.ImageData)
+}
+
+// Cart Handlers
+func calculateCartTotalHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, budget := newRequestBudget(r, "cart")
+	defer cancel()
+
+	productsClient, release, err := getProductsClient(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Service unavailable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	defer release()
+
+	var req pb.CalculateCartTotalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := productsClient.Raw().CalculateCartTotal(ctx, &req)
+	if err != nil {
+		if budget.timedOut(w, "products-service", err) || shedLoad(w, err) {
+			return
+		}
+		log.Printf("Error calculating cart total: %v", err)
+		http.Error(w, status.Convert(err).Message(), httpStatusFromGRPC(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
 // Fixed composite endpoint with proper service discovery
 func getPurchaseDataHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel, budget := newRequestBudget(r, "purchase")
+	defer cancel()
+
 	vars := mux.Vars(r)
 	userId := vars["userId"]
 	productId := vars["productId"]
 
 	var wg sync.WaitGroup
-	var user *pb.User
+	var user client.User
 	var product *pb.Product
 	var userErr, productErr error
 
@@ -244,28 +566,25 @@ func getPurchaseDataHandler(w http.ResponseWriter, r *http.Request) {
 	// Fetch user data
 	go func() {
 		defer wg.Done()
-		client, err := getUsersClient()
+		users, release, err := getUsersClient(ctx)
 		if err != nil {
 			userErr = err
 			return
 		}
-		res, err := client.GetUser(context.Background(), &pb.GetUserRequest{Id: userId})
-		if err != nil {
-			userErr = err
-			return
-		}
-		user = res.User
+		defer release()
+		user, userErr = users.GetUser(ctx, userId)
 	}()
 
 	// Fetch product data
 	go func() {
 		defer wg.Done()
-		client, err := getProductsClient()
+		productsClient, release, err := getProductsClient(ctx)
 		if err != nil {
 			productErr = err
 			return
 		}
-		res, err := client.GetProduct(context.Background(), &pb.GetProductRequest{Id: productId})
+		defer release()
+		res, err := productsClient.Raw().GetProduct(ctx, &pb.GetProductRequest{Id: productId})
 		if err != nil {
 			productErr = err
 			return
@@ -275,6 +594,11 @@ func getPurchaseDataHandler(w http.ResponseWriter, r *http.Request) {
 
 	wg.Wait()
 
+	if budget.timedOut(w, "users-service", userErr) || budget.timedOut(w, "products-service", productErr) ||
+		shedLoad(w, userErr) || shedLoad(w, productErr) {
+		return
+	}
+
 	if userErr != nil {
 		log.Printf("Error getting user: %v", userErr)
 		http.Error(w, "User not found", http.StatusNotFound)
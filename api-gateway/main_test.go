@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api-gateway/pkg/servicetest"
+	pb "api-gateway/proto/gen/proto"
+	sharedgrpc "shared/grpc"
+)
+
+// newFakeBackends serves in-memory fakes of both services and points the
+// gateway at them.
+func newFakeBackends(t *testing.T) (*servicetest.FakeUserService, *servicetest.FakeProductService) {
+	t.Helper()
+	userService, productService := servicetest.NewFakeUserService(), servicetest.NewFakeProductService()
+	srv := servicetest.NewServer(userService, productService)
+	t.Cleanup(srv.Close)
+
+	sd = &ServiceDiscovery{pool: sharedgrpc.New(4, 0, srv.DialOptions()...)}
+	t.Cleanup(func() { sd.pool.Close() })
+	products = newProductCache()
+	return userService, productService
+}
+
+func serve(method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	newRouter().ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestGetPurchaseData(t *testing.T) {
+	userService, productService := newFakeBackends(t)
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	productService.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+
+	rec := serve(http.MethodGet, "/api/purchases/user/1/product/7", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got struct {
+		User    struct{ Name string } `json:"user"`
+		Product struct{ Name string } `json:"product"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.User.Name != "Pema Sherpa" || got.Product.Name != "Mug" {
+		t.Errorf("got user %q and product %q, want Pema Sherpa and Mug", got.User.Name, got.Product.Name)
+	}
+}
+
+func TestGetPurchaseDataMissingProduct(t *testing.T) {
+	userService, _ := newFakeBackends(t)
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+
+	if rec := serve(http.MethodGet, "/api/purchases/user/1/product/7", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestGetPurchaseDataTimesOut(t *testing.T) {
+	userService, productService := newFakeBackends(t)
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	productService.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+	userService.Delay(time.Second)
+
+	saved := timeouts
+	t.Cleanup(func() { timeouts = saved })
+	var err error
+	if timeouts, err = parseRouteTimeouts("purchase=20ms"); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serve(http.MethodGet, "/api/purchases/user/1/product/7", ""); rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	userService, _ := newFakeBackends(t)
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+
+	if rec := serve(http.MethodPut, "/api/users/1/preferences/theme", `{"value":"dark"}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("PUT status = %d, want 204: %s", rec.Code, rec.Body)
+	}
+	rec := serve(http.MethodGet, "/api/users/1/preferences?keys=theme", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got["theme"] != "dark" {
+		t.Errorf("preferences = %v, want theme=dark", got)
+	}
+	if n := len(userService.Requests()); n != 2 {
+		t.Errorf("users-service got %d requests, want 2", n)
+	}
+}
+
+func TestHandlersReleaseConnections(t *testing.T) {
+	userService, productService := newFakeBackends(t)
+	userService.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	productService.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+
+	for i := 0; i < 3; i++ {
+		if rec := serve(http.MethodGet, "/api/purchases/user/1/product/7", ""); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if rec := serve(http.MethodGet, "/api/users/2", ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404: %s", rec.Code, rec.Body)
+		}
+	}
+	// Every request released its connections, and later requests reused
+	// them: one connection per service, none held.
+	if stats := sd.pool.Stats(); stats != (sharedgrpc.PoolStats{Idle: 2}) {
+		t.Errorf("pool stats = %+v, want 2 idle connections", stats)
+	}
+}
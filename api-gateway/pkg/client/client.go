@@ -0,0 +1,204 @@
+// Package client wraps the UserService and ProductService gRPC APIs in plain
+// Go types, so callers do not have to dial, build requests or interpret
+// status codes themselves.
+//
+//	users, err := client.NewUsersClient("consul://users-service", client.WithAPIKey(key))
+//	if err != nil {
+//		return err
+//	}
+//	defer users.Close()
+//
+//	user, err := users.CreateUser(ctx, "Ada", "ada@example.com")
+//	if errors.Is(err, client.ErrAlreadyExists) {
+//		// the email is taken
+//	}
+//
+// A target is either a host:port address or "consul://<service>", which is
+// resolved through the Consul agent named by CONSUL_HTTP_ADDR and balanced
+// across passing instances, preferring ones that are not degraded.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/pkg/consulresolver"
+)
+
+// DefaultTimeout bounds calls whose context has no deadline.
+const DefaultTimeout = 5 * time.Second
+
+// Errors returned by the clients wrap one of these, so they can be matched
+// with errors.Is.
+var (
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrNotFound          = errors.New("not found")
+	ErrAlreadyExists     = errors.New("already exists")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrUnauthenticated   = errors.New("unauthenticated")
+	ErrResourceExhausted = errors.New("resource exhausted")
+	ErrUnavailable       = errors.New("unavailable")
+	ErrDeadlineExceeded  = errors.New("deadline exceeded")
+)
+
+var sentinels = map[codes.Code]error{
+	codes.InvalidArgument:   ErrInvalidArgument,
+	codes.NotFound:          ErrNotFound,
+	codes.AlreadyExists:     ErrAlreadyExists,
+	codes.PermissionDenied:  ErrPermissionDenied,
+	codes.Unauthenticated:   ErrUnauthenticated,
+	codes.ResourceExhausted: ErrResourceExhausted,
+	codes.Unavailable:       ErrUnavailable,
+	codes.DeadlineExceeded:  ErrDeadlineExceeded,
+}
+
+// Error is a failed call. It unwraps to the sentinel for its code, if there
+// is one, and still carries the gRPC status for status.Code and friends.
+type Error struct {
+	Status *status.Status
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status.Code(), e.Status.Message())
+}
+
+func (e *Error) Unwrap() error {
+	return sentinels[e.Status.Code()]
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	return e.Status
+}
+
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return &Error{Status: st}
+}
+
+// Option configures a client.
+type Option func(*options)
+
+type options struct {
+	timeout            time.Duration
+	tls                *tls.Config
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	dialOptions        []grpc.DialOption
+}
+
+// WithTimeout sets the deadline applied to calls whose context has none.
+// Zero disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithTLS dials with TLS instead of plaintext.
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) { o.tls = config }
+}
+
+// WithAPIKey sends key in the x-api-key metadata of every call.
+func WithAPIKey(key string) Option {
+	return WithMetadata("x-api-key", func(context.Context) string { return key })
+}
+
+// WithMetadata sets header on every call to the value returned by value, e.g.
+// a request id taken from the context. Empty values are not sent.
+func WithMetadata(header string, value func(context.Context) string) Option {
+	return func(o *options) {
+		o.unaryInterceptors = append(o.unaryInterceptors, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			if v := value(ctx); v != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, header, v)
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+		o.streamInterceptors = append(o.streamInterceptors, func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if v := value(ctx); v != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, header, v)
+			}
+			return streamer(ctx, desc, cc, method, opts...)
+		})
+	}
+}
+
+// WithUnaryInterceptor adds a unary client interceptor. Interceptors run in
+// the order they are given.
+func WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) Option {
+	return func(o *options) { o.unaryInterceptors = append(o.unaryInterceptors, interceptor) }
+}
+
+// WithStreamInterceptor adds a stream client interceptor.
+func WithStreamInterceptor(interceptor grpc.StreamClientInterceptor) Option {
+	return func(o *options) { o.streamInterceptors = append(o.streamInterceptors, interceptor) }
+}
+
+// WithDialOptions passes extra options to grpc.Dial, e.g. a context dialer
+// for servicetest.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) { o.dialOptions = append(o.dialOptions, opts...) }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// dial connects to target, which is host:port or consul://<service>.
+func dial(target string, o *options) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(o.unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(o.streamInterceptors...),
+	}
+
+	if service, ok := strings.CutPrefix(target, consulresolver.Scheme+"://"); ok {
+		consul, err := consulapi.NewClient(consulapi.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create consul client: %w", err)
+		}
+		target = fmt.Sprintf("%s:///%s", consulresolver.Scheme, strings.TrimPrefix(service, "/"))
+		dialOptions = append(dialOptions,
+			grpc.WithResolvers(consulresolver.NewBuilder(consul)),
+			grpc.WithDefaultServiceConfig(consulresolver.ServiceConfig),
+		)
+	}
+
+	conn, err := grpc.Dial(target, append(dialOptions, o.dialOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", target, err)
+	}
+	return conn, nil
+}
+
+// withTimeout applies the default deadline if ctx has none.
+func (o *options) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
@@ -0,0 +1,157 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/pkg/client"
+	"api-gateway/pkg/servicetest"
+	pb "api-gateway/proto/gen/proto"
+)
+
+// dialFakes serves the fakes and returns a connection to them.
+func dialFakes(t testing.TB, users *servicetest.FakeUserService, products *servicetest.FakeProductService) *grpc.ClientConn {
+	t.Helper()
+	srv := servicetest.NewServer(users, products)
+	t.Cleanup(srv.Close)
+	conn, err := srv.Dial(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestUsersClient(t *testing.T) {
+	users := client.NewUsersClientFromConn(dialFakes(t, servicetest.NewFakeUserService(), nil))
+	ctx := context.Background()
+
+	created, err := users.CreateUser(ctx, "Pema Sherpa", "pema@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := users.GetUser(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != created {
+		t.Errorf("GetUser = %+v, want %+v", got, created)
+	}
+
+	_, err = users.CreateUser(ctx, "Pema Sherpa", "pema@example.com")
+	if !errors.Is(err, client.ErrAlreadyExists) || status.Code(err) != codes.AlreadyExists {
+		t.Errorf("duplicate CreateUser: %v, want ErrAlreadyExists", err)
+	}
+	if _, err := users.GetUser(ctx, "missing"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetUser(missing): %v, want ErrNotFound", err)
+	}
+
+	if err := users.SetPreference(ctx, created.ID, "theme", "dark"); err != nil {
+		t.Fatal(err)
+	}
+	preferences, err := users.GetPreferences(ctx, created.ID, "theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preferences["theme"] != "dark" {
+		t.Errorf("GetPreferences = %v, want theme=dark", preferences)
+	}
+}
+
+func TestProductsClientCartTotal(t *testing.T) {
+	fake := servicetest.NewFakeProductService()
+	fake.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+	fake.SeedDiscountCode("TENOFF", 10)
+	fake.SeedTaxRule("GB", "", "*", 0.2, "VAT")
+	products := client.NewProductsClientFromConn(dialFakes(t, nil, fake))
+
+	items := []client.CartItem{{ProductID: "7", Quantity: 2}}
+	total, err := products.CalculateCartTotal(context.Background(), items, "TENOFF", client.Location{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client.CartTotal{Currency: "USD", Subtotal: 19, Discount: 1.9, Total: 17.1}
+	if total != want {
+		t.Errorf("CalculateCartTotal = %+v, want %+v", total, want)
+	}
+	// The tax is on the discounted amount.
+	total, err = products.CalculateCartTotal(context.Background(), items, "TENOFF", client.Location{CountryCode: "GB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = client.CartTotal{Currency: "USD", Subtotal: 19, Discount: 1.9, Tax: 3.42, Total: 20.52}
+	if total != want {
+		t.Errorf("CalculateCartTotal in GB = %+v, want %+v", total, want)
+	}
+	if _, err := products.GetProduct(context.Background(), "8"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetProduct(8): %v, want ErrNotFound", err)
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	fake := servicetest.NewFakeUserService()
+	fake.Delay(time.Second)
+	users := client.NewUsersClientFromConn(dialFakes(t, fake, nil), client.WithTimeout(20*time.Millisecond))
+
+	if _, err := users.GetUser(context.Background(), "1"); !errors.Is(err, client.ErrDeadlineExceeded) {
+		t.Errorf("GetUser past the default timeout: %v, want ErrDeadlineExceeded", err)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	srv := servicetest.NewServer(servicetest.NewFakeUserService(), nil)
+	t.Cleanup(srv.Close)
+
+	var sent []string
+	users, err := client.NewUsersClient("bufnet",
+		client.WithAPIKey("secret"),
+		client.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			sent = md.Get("x-api-key")
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		client.WithDialOptions(srv.DialOptions()...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer users.Close()
+
+	if _, err := users.CreateUser(context.Background(), "Pema Sherpa", "pema@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != "secret" {
+		t.Errorf("x-api-key = %v, want [secret]", sent)
+	}
+}
+
+func Example() {
+	fake := servicetest.NewFakeUserService()
+	srv := servicetest.NewServer(fake, nil)
+	defer srv.Close()
+
+	users, err := client.NewUsersClient("bufnet", client.WithDialOptions(srv.DialOptions()...))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer users.Close()
+
+	ctx := context.Background()
+	if _, err := users.CreateUser(ctx, "Ada", "ada@example.com"); err != nil {
+		log.Fatal(err)
+	}
+	_, err = users.CreateUser(ctx, "Ada", "ada@example.com")
+	if errors.Is(err, client.ErrAlreadyExists) {
+		fmt.Println("the email is taken")
+	}
+	// Output: the email is taken
+}
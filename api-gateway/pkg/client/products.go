@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// Product is an item for sale. Prices are in the shop's single currency.
+type Product struct {
+	ID          string
+	Name        string
+	Description string
+	Price       float64
+	UpdatedAt   time.Time
+}
+
+func productFromProto(p *pb.Product) Product {
+	product := Product{ID: p.GetId(), Name: p.GetName(), Description: p.GetDescription(), Price: p.GetPrice()}
+	if p.GetUpdatedAt() != nil {
+		product.UpdatedAt = p.UpdatedAt.AsTime()
+	}
+	return product
+}
+
+// CartItem is a quantity of one product in a cart.
+type CartItem struct {
+	ProductID string
+	Quantity  int
+}
+
+// Location is where a buyer is, as ISO 3166-1 country and region codes.
+// The zero Location leaves a cart untaxed.
+type Location struct {
+	CountryCode string
+	RegionCode  string
+}
+
+// CartTotal is the priced result of CalculateCartTotal.
+type CartTotal struct {
+	Currency string
+	Subtotal float64
+	Discount float64
+	Tax      float64
+	Total    float64
+}
+
+// productRPCs lists the ProductService methods ProductsClient wraps. The
+// assertion below breaks the build if the proto changes under them.
+type productRPCs interface {
+	CreateProduct(ctx context.Context, in *pb.CreateProductRequest, opts ...grpc.CallOption) (*pb.ProductResponse, error)
+	GetProduct(ctx context.Context, in *pb.GetProductRequest, opts ...grpc.CallOption) (*pb.ProductResponse, error)
+	CalculateCartTotal(ctx context.Context, in *pb.CalculateCartTotalRequest, opts ...grpc.CallOption) (*pb.CalculateCartTotalResponse, error)
+	SearchProductsByTags(ctx context.Context, in *pb.SearchProductsByTagsRequest, opts ...grpc.CallOption) (*pb.ListProductsResponse, error)
+}
+
+var _ productRPCs = pb.ProductServiceClient(nil)
+
+// ProductsClient calls ProductService.
+type ProductsClient struct {
+	opts *options
+	conn *grpc.ClientConn
+	// owned is false when conn was passed in and must not be closed.
+	owned bool
+	rpc   pb.ProductServiceClient
+}
+
+// NewProductsClient dials target.
+func NewProductsClient(target string, opts ...Option) (*ProductsClient, error) {
+	o := newOptions(opts)
+	conn, err := dial(target, o)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductsClient{opts: o, conn: conn, owned: true, rpc: pb.NewProductServiceClient(conn)}, nil
+}
+
+// NewProductsClientFromConn uses an existing connection, which Close leaves
+// open. Dial options in opts are ignored.
+func NewProductsClientFromConn(conn *grpc.ClientConn, opts ...Option) *ProductsClient {
+	return &ProductsClient{opts: newOptions(opts), conn: conn, rpc: pb.NewProductServiceClient(conn)}
+}
+
+// Close closes the connection if the client dialed it.
+func (c *ProductsClient) Close() error {
+	if !c.owned {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Raw returns the generated client, for methods not wrapped here.
+func (c *ProductsClient) Raw() pb.ProductServiceClient {
+	return c.rpc
+}
+
+func (c *ProductsClient) CreateProduct(ctx context.Context, name string, price float64) (Product, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	res, err := c.rpc.CreateProduct(ctx, &pb.CreateProductRequest{Name: name, Price: price})
+	if err != nil {
+		return Product{}, translate(err)
+	}
+	return productFromProto(res.Product), nil
+}
+
+func (c *ProductsClient) GetProduct(ctx context.Context, id string) (Product, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	res, err := c.rpc.GetProduct(ctx, &pb.GetProductRequest{Id: id})
+	if err != nil {
+		return Product{}, translate(err)
+	}
+	return productFromProto(res.Product), nil
+}
+
+// CalculateCartTotal prices items, applying discountCode if it is not empty
+// and the tax of the buyer's location.
+func (c *ProductsClient) CalculateCartTotal(ctx context.Context, items []CartItem, discountCode string, buyer Location) (CartTotal, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	req := &pb.CalculateCartTotalRequest{DiscountCode: discountCode, CountryCode: buyer.CountryCode, RegionCode: buyer.RegionCode}
+	for _, item := range items {
+		req.Items = append(req.Items, &pb.CartItem{ProductId: item.ProductID, Quantity: int32(item.Quantity)})
+	}
+	res, err := c.rpc.CalculateCartTotal(ctx, req)
+	if err != nil {
+		return CartTotal{}, translate(err)
+	}
+	return CartTotal{
+		Currency: res.Total.GetCurrencyCode(),
+		Subtotal: res.Subtotal.GetAmount(),
+		Discount: res.DiscountAmount.GetAmount(),
+		Tax:      res.TaxAmount.GetAmount(),
+		Total:    res.Total.GetAmount(),
+	}, nil
+}
+
+// SearchProductsByTags returns products carrying all of tags, or any of them
+// if matchAny is set.
+func (c *ProductsClient) SearchProductsByTags(ctx context.Context, tags []string, matchAny bool) ([]Product, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	req := &pb.SearchProductsByTagsRequest{Tags: tags, Operator: pb.TagOperator_TAG_OPERATOR_AND}
+	if matchAny {
+		req.Operator = pb.TagOperator_TAG_OPERATOR_OR
+	}
+	res, err := c.rpc.SearchProductsByTags(ctx, req)
+	if err != nil {
+		return nil, translate(err)
+	}
+	products := make([]Product, len(res.Products))
+	for i, p := range res.Products {
+		products[i] = productFromProto(p)
+	}
+	return products, nil
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// User is a user of the shop. It encodes to the same JSON as the proto
+// message.
+type User struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+func userFromProto(u *pb.User) User {
+	return User{ID: u.GetId(), Name: u.GetName(), Email: u.GetEmail()}
+}
+
+// userRPCs lists the UserService methods UsersClient wraps. The assertion
+// below breaks the build if the proto changes under them.
+type userRPCs interface {
+	CreateUser(ctx context.Context, in *pb.CreateUserRequest, opts ...grpc.CallOption) (*pb.UserResponse, error)
+	GetUser(ctx context.Context, in *pb.GetUserRequest, opts ...grpc.CallOption) (*pb.UserResponse, error)
+	SetPreference(ctx context.Context, in *pb.SetPreferenceRequest, opts ...grpc.CallOption) (*pb.SetPreferenceResponse, error)
+	GetPreferences(ctx context.Context, in *pb.GetPreferencesRequest, opts ...grpc.CallOption) (*pb.GetPreferencesResponse, error)
+}
+
+var _ userRPCs = pb.UserServiceClient(nil)
+
+// UsersClient calls UserService.
+type UsersClient struct {
+	opts *options
+	conn *grpc.ClientConn
+	// owned is false when conn was passed in and must not be closed.
+	owned bool
+	rpc   pb.UserServiceClient
+}
+
+// NewUsersClient dials target.
+func NewUsersClient(target string, opts ...Option) (*UsersClient, error) {
+	o := newOptions(opts)
+	conn, err := dial(target, o)
+	if err != nil {
+		return nil, err
+	}
+	return &UsersClient{opts: o, conn: conn, owned: true, rpc: pb.NewUserServiceClient(conn)}, nil
+}
+
+// NewUsersClientFromConn uses an existing connection, which Close leaves
+// open. Dial options in opts are ignored.
+func NewUsersClientFromConn(conn *grpc.ClientConn, opts ...Option) *UsersClient {
+	return &UsersClient{opts: newOptions(opts), conn: conn, rpc: pb.NewUserServiceClient(conn)}
+}
+
+// Close closes the connection if the client dialed it.
+func (c *UsersClient) Close() error {
+	if !c.owned {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Raw returns the generated client, for methods not wrapped here.
+func (c *UsersClient) Raw() pb.UserServiceClient {
+	return c.rpc
+}
+
+func (c *UsersClient) CreateUser(ctx context.Context, name, email string) (User, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	res, err := c.rpc.CreateUser(ctx, &pb.CreateUserRequest{Name: name, Email: email})
+	if err != nil {
+		return User{}, translate(err)
+	}
+	return userFromProto(res.User), nil
+}
+
+func (c *UsersClient) GetUser(ctx context.Context, id string) (User, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	res, err := c.rpc.GetUser(ctx, &pb.GetUserRequest{Id: id})
+	if err != nil {
+		return User{}, translate(err)
+	}
+	return userFromProto(res.User), nil
+}
+
+func (c *UsersClient) SetPreference(ctx context.Context, userID, key, value string) error {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	_, err := c.rpc.SetPreference(ctx, &pb.SetPreferenceRequest{UserId: userID, Key: key, Value: value})
+	return translate(err)
+}
+
+// GetPreferences returns the user's preferences, or only the given keys if
+// any are passed.
+func (c *UsersClient) GetPreferences(ctx context.Context, userID string, keys ...string) (map[string]string, error) {
+	ctx, cancel := c.opts.withTimeout(ctx)
+	defer cancel()
+	res, err := c.rpc.GetPreferences(ctx, &pb.GetPreferencesRequest{UserId: userID, Keys: keys})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return res.Preferences, nil
+}
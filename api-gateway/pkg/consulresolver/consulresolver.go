@@ -0,0 +1,218 @@
+// Package consulresolver resolves gRPC targets such as
+// "consul:///products-service" to the passing instances registered in
+// Consul, and balances across them preferring instances that are not
+// degraded and weighting canaries. Dial with
+// grpc.WithResolvers(NewBuilder(consul)) and
+// grpc.WithDefaultServiceConfig(ServiceConfig).
+package consulresolver
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the dial target scheme served by the resolver.
+const Scheme = "consul"
+
+// BalancerName is the load balancing policy registered by this package.
+const BalancerName = "prefer_healthy"
+
+// ServiceConfig selects the BalancerName policy.
+const ServiceConfig = `{"loadBalancingConfig":[{"` + BalancerName + `":{}}]}`
+
+// DegradedTagPrefix marks a Consul tag set by a service that is up but
+// degraded, e.g. "degraded:db-slow".
+const DegradedTagPrefix = "degraded:"
+
+// StableWeight is the weight of an instance that is not a canary. A canary,
+// registered with "canary": "true" service metadata, is weighted by its
+// "weight" metadata instead, so a canary of weight 10 next to one stable
+// instance takes 10% of the requests.
+const StableWeight = 90
+
+// defaultCanaryWeight is used for a canary without a valid weight.
+const defaultCanaryWeight = 10
+
+// consulRetryInterval is how long the resolver waits after a failed query.
+const consulRetryInterval = 5 * time.Second
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(BalancerName, preferHealthyPickerBuilder{}, base.Config{}))
+}
+
+// NewBuilder returns a resolver builder that resolves a service name to its
+// passing instances in consul and keeps the list current with blocking
+// queries.
+func NewBuilder(consul *consulapi.Client) resolver.Builder {
+	return &consulResolverBuilder{consul: consul}
+}
+
+type consulResolverBuilder struct {
+	consul *consulapi.Client
+}
+
+func (b *consulResolverBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *consulResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &consulResolver{consul: b.consul, service: target.Endpoint(), cc: cc, cancel: cancel}
+	go r.watch(ctx)
+	return r, nil
+}
+
+type consulResolver struct {
+	consul  *consulapi.Client
+	service string
+	cc      resolver.ClientConn
+	cancel  context.CancelFunc
+}
+
+type (
+	degradedKey struct{}
+	weightKey   struct{}
+)
+
+func (r *consulResolver) watch(ctx context.Context) {
+	var index uint64
+	for {
+		opts := (&consulapi.QueryOptions{WaitIndex: index}).WithContext(ctx)
+		entries, meta, err := r.consul.Health().Service(r.service, "", true, opts)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.cc.ReportError(fmt.Errorf("failed to discover service %s: %w", r.service, err))
+			select {
+			case <-time.After(consulRetryInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		index = meta.LastIndex
+
+		addresses := make([]resolver.Address, 0, len(entries))
+		for _, entry := range entries {
+			// The flag and weight are address attributes rather than
+			// balancer attributes so that a change replaces the subconn and
+			// the picker sees the new value.
+			addresses = append(addresses, resolver.Address{
+				Addr: fmt.Sprintf("%s:%d", entry.Service.Address, entry.Service.Port),
+				Attributes: attributes.New(degradedKey{}, isDegraded(entry.Service.Tags)).
+					WithValue(weightKey{}, instanceWeight(entry.Service.Meta)),
+			})
+		}
+		if len(addresses) == 0 {
+			r.cc.ReportError(fmt.Errorf("no healthy instances of service %s found", r.service))
+			continue
+		}
+		r.cc.UpdateState(resolver.State{Addresses: addresses})
+	}
+}
+
+func (r *consulResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *consulResolver) Close() {
+	r.cancel()
+}
+
+func isDegraded(tags []string) bool {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, DegradedTagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceWeight returns the weight of an instance with the given Consul
+// service metadata.
+func instanceWeight(meta map[string]string) int {
+	if meta["canary"] != "true" {
+		return StableWeight
+	}
+	weight, err := strconv.Atoi(meta["weight"])
+	if err != nil || weight < 1 {
+		return defaultCanaryWeight
+	}
+	return weight
+}
+
+// preferHealthyPickerBuilder sends requests to instances that are not
+// degraded, falling back to degraded ones only when nothing else is ready.
+// Requests go round-robin unless the instances have different weights, as
+// when one is a canary, and are then picked at random by weight.
+type preferHealthyPickerBuilder struct{}
+
+func (preferHealthyPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	var healthy, degraded []balancer.SubConn
+	weights := make(map[balancer.SubConn]int, len(info.ReadySCs))
+	for sc, sci := range info.ReadySCs {
+		if d, _ := sci.Address.Attributes.Value(degradedKey{}).(bool); d {
+			degraded = append(degraded, sc)
+		} else {
+			healthy = append(healthy, sc)
+		}
+		weights[sc], _ = sci.Address.Attributes.Value(weightKey{}).(int)
+	}
+	if len(healthy) == 0 {
+		healthy = degraded
+	}
+	if len(healthy) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	for _, sc := range healthy[1:] {
+		if weights[sc] != weights[healthy[0]] {
+			return newWeightedPicker(healthy, weights)
+		}
+	}
+	return &roundRobinPicker{subConns: healthy}
+}
+
+type roundRobinPicker struct {
+	subConns []balancer.SubConn
+	next     atomic.Uint32
+}
+
+func (p *roundRobinPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	n := p.next.Add(1)
+	return balancer.PickResult{SubConn: p.subConns[int(n)%len(p.subConns)]}, nil
+}
+
+// weightedPicker picks each subconn with probability proportional to its
+// weight.
+type weightedPicker struct {
+	subConns []balancer.SubConn
+	// cumulative[i] is the total weight of subConns[:i+1].
+	cumulative []int
+}
+
+func newWeightedPicker(subConns []balancer.SubConn, weights map[balancer.SubConn]int) *weightedPicker {
+	p := &weightedPicker{subConns: subConns, cumulative: make([]int, len(subConns))}
+	total := 0
+	for i, sc := range subConns {
+		total += weights[sc]
+		p.cumulative[i] = total
+	}
+	return p
+}
+
+func (p *weightedPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	n := rand.Intn(p.cumulative[len(p.cumulative)-1])
+	i := sort.SearchInts(p.cumulative, n+1)
+	return balancer.PickResult{SubConn: p.subConns[i]}, nil
+}
@@ -0,0 +1,322 @@
+package consulresolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+)
+
+// countingBackend is a gRPC server that only serves health checks and
+// counts the calls it gets.
+type countingBackend struct {
+	srv   *grpc.Server
+	port  int
+	calls atomic.Int64
+}
+
+func newCountingBackend(t *testing.T) *countingBackend {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &countingBackend{port: lis.Addr().(*net.TCPAddr).Port}
+	b.srv = grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		b.calls.Add(1)
+		return handler(ctx, req)
+	}))
+	grpc_health_v1.RegisterHealthServer(b.srv, health.NewServer())
+	go b.srv.Serve(lis)
+	t.Cleanup(b.srv.Stop)
+	return b
+}
+
+// fakeConsul answers the health endpoint's blocking queries with whatever
+// instances were last set.
+type fakeConsul struct {
+	mu        sync.Mutex
+	index     uint64
+	changed   chan struct{}
+	instances []*consulapi.ServiceEntry
+}
+
+func (c *fakeConsul) set(instances ...*consulapi.ServiceEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.index++
+	c.instances = instances
+	if c.changed != nil {
+		close(c.changed)
+	}
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	index, changed := c.index, c.changed
+	c.mu.Unlock()
+	if wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); wait == index {
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(c.index, 10))
+	json.NewEncoder(w).Encode(c.instances)
+}
+
+func instance(b *countingBackend, tags ...string) *consulapi.ServiceEntry {
+	return &consulapi.ServiceEntry{Service: &consulapi.AgentService{Address: "127.0.0.1", Port: b.port, Tags: tags}}
+}
+
+func dialConsul(t *testing.T, c *fakeConsul) grpc_health_v1.HealthClient {
+	t.Helper()
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	client, err := consulapi.NewClient(&consulapi.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.Dial(Scheme+":///products-service",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithResolvers(NewBuilder(client)),
+		grpc.WithDefaultServiceConfig(ServiceConfig),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func check(client grpc_health_v1.HealthClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+	return err
+}
+
+func TestResolverPrefersHealthyInstances(t *testing.T) {
+	healthy, degraded := newCountingBackend(t), newCountingBackend(t)
+	consul := &fakeConsul{}
+	consul.set(instance(healthy), instance(degraded, "degraded:db-slow"))
+	client := dialConsul(t, consul)
+
+	// Wait for both subconns to be ready, so the picker has a choice.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := check(client); err != nil {
+			t.Fatal(err)
+		}
+		if healthy.calls.Load() > 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	before := degraded.calls.Load()
+
+	for i := 0; i < 100; i++ {
+		if err := check(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := degraded.calls.Load() - before; got != 0 {
+		t.Errorf("degraded instance got %d of 100 calls, want none", got)
+	}
+
+	// With the healthy instance gone, the degraded one takes over.
+	consul.set(instance(degraded, "degraded:db-slow"))
+	healthy.srv.Stop()
+	deadline = time.Now().Add(5 * time.Second)
+	for check(client) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("no call succeeded after the healthy instance stopped")
+		}
+	}
+	before = degraded.calls.Load()
+	for i := 0; i < 20; i++ {
+		if err := check(client); err != nil {
+			t.Fatalf("call %d with only the degraded instance left: %v", i, err)
+		}
+	}
+	if got := degraded.calls.Load() - before; got != 20 {
+		t.Errorf("degraded instance got %d of 20 calls, want all", got)
+	}
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+type pickerInstance struct {
+	name     string
+	degraded bool
+	meta     map[string]string
+}
+
+func buildPicker(instances ...pickerInstance) balancer.Picker {
+	info := base.PickerBuildInfo{ReadySCs: make(map[balancer.SubConn]base.SubConnInfo)}
+	for _, in := range instances {
+		info.ReadySCs[&fakeSubConn{name: in.name}] = base.SubConnInfo{Address: resolver.Address{
+			Addr:       in.name,
+			Attributes: attributes.New(degradedKey{}, in.degraded).WithValue(weightKey{}, instanceWeight(in.meta)),
+		}}
+	}
+	return preferHealthyPickerBuilder{}.Build(info)
+}
+
+// shares picks n times and returns the fraction of picks each pickerInstance got.
+func shares(t *testing.T, p balancer.Picker, n int) map[string]float64 {
+	t.Helper()
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		res, err := p.Pick(balancer.PickInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[res.SubConn.(*fakeSubConn).name]++
+	}
+	got := make(map[string]float64, len(counts))
+	for name, count := range counts {
+		got[name] = float64(count) / float64(n)
+	}
+	return got
+}
+
+func canary(weight string) map[string]string {
+	return map[string]string{"canary": "true", "weight": weight}
+}
+
+func TestCanaryWeighting(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []pickerInstance
+		want      map[string]float64
+	}{
+		{
+			"one canary of weight 10",
+			[]pickerInstance{{name: "stable"}, {name: "canary", meta: canary("10")}},
+			map[string]float64{"stable": 0.9, "canary": 0.1},
+		},
+		{
+			"two stable and a canary of weight 20",
+			[]pickerInstance{{name: "stable-1"}, {name: "stable-2"}, {name: "canary", meta: canary("20")}},
+			map[string]float64{"stable-1": 0.45, "stable-2": 0.45, "canary": 0.1},
+		},
+		{
+			"canary without a valid weight",
+			[]pickerInstance{{name: "stable"}, {name: "canary", meta: canary("lots")}},
+			map[string]float64{"stable": 0.9, "canary": 0.1},
+		},
+		{
+			"degraded stable pickerInstance is skipped",
+			[]pickerInstance{{name: "stable", degraded: true}, {name: "canary", meta: canary("10")}},
+			map[string]float64{"canary": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shares(t, buildPicker(tt.instances...), 200000)
+			for name, want := range tt.want {
+				// Within ±5% of the expected share.
+				if math.Abs(got[name]-want) > want*0.05 {
+					t.Errorf("%s got %.4f of the requests, want %.4f ±5%%", name, got[name], want)
+				}
+			}
+			for name := range got {
+				if _, ok := tt.want[name]; !ok {
+					t.Errorf("%s got %.4f of the requests, want none", name, got[name])
+				}
+			}
+		})
+	}
+}
+
+func TestEqualWeightsRoundRobin(t *testing.T) {
+	p := buildPicker(pickerInstance{name: "a"}, pickerInstance{name: "b"}, pickerInstance{name: "c"})
+	if _, ok := p.(*roundRobinPicker); !ok {
+		t.Fatalf("picker is %T, want round robin", p)
+	}
+	got := shares(t, p, 300)
+	for _, name := range []string{"a", "b", "c"} {
+		if got[name] != 1.0/3 {
+			t.Errorf("%s got %v of the requests, want exactly a third", name, got[name])
+		}
+	}
+}
+
+func TestAllDegraded(t *testing.T) {
+	got := shares(t, buildPicker(pickerInstance{name: "a", degraded: true}, pickerInstance{name: "b", degraded: true}), 100)
+	if got["a"] != 0.5 || got["b"] != 0.5 {
+		t.Errorf("shares = %v, want degraded instances used when nothing else is ready", got)
+	}
+}
+
+func TestNoReadyInstances(t *testing.T) {
+	_, err := buildPicker().Pick(balancer.PickInfo{})
+	if !errors.Is(err, balancer.ErrNoSubConnAvailable) {
+		t.Errorf("err = %v, want ErrNoSubConnAvailable", err)
+	}
+}
+
+func TestInstanceWeight(t *testing.T) {
+	tests := []struct {
+		meta map[string]string
+		want int
+	}{
+		{nil, StableWeight},
+		{map[string]string{"weight": "10"}, StableWeight},
+		{map[string]string{"canary": "false", "weight": "10"}, StableWeight},
+		{canary("25"), 25},
+		{canary("0"), defaultCanaryWeight},
+		{canary("-5"), defaultCanaryWeight},
+		{canary(""), defaultCanaryWeight},
+	}
+	for _, tt := range tests {
+		if got := instanceWeight(tt.meta); got != tt.want {
+			t.Errorf("instanceWeight(%v) = %d, want %d", tt.meta, got, tt.want)
+		}
+	}
+}
+
+func TestIsDegraded(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"v1", "grpc"}, false},
+		{[]string{"v1", "canary"}, false},
+		{[]string{"grpc", "degraded:db-slow"}, true},
+		{[]string{"v1", "degraded:redis-down"}, true},
+		{[]string{"not-degraded:db"}, false},
+	}
+	for _, tt := range tests {
+		if got := isDegraded(tt.tags); got != tt.want {
+			t.Errorf("isDegraded(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
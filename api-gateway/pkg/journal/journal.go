@@ -0,0 +1,104 @@
+// Package journal reads the request journals written by the services when
+// JOURNAL_DIR is set. See internal/journal in either service for the format.
+package journal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// maxRecordSize guards against reading a corrupt length prefix.
+const maxRecordSize = 64 << 20
+
+// Reader reads journal entries from a stream.
+type Reader struct {
+	r      *bufio.Reader
+	header [4]byte
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next entry, or io.EOF once the stream ends. A record cut
+// short by a crash mid-write is reported as io.ErrUnexpectedEOF.
+func (r *Reader) Next() (*pb.JournalEntry, error) {
+	if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(r.header[:])
+	if size > maxRecordSize {
+		return nil, fmt.Errorf("journal record of %d bytes exceeds the %d byte limit", size, maxRecordSize)
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(r.r, record); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	entry := &pb.JournalEntry{}
+	if err := proto.Unmarshal(record, entry); err != nil {
+		return nil, fmt.Errorf("decode journal record: %w", err)
+	}
+	return entry, nil
+}
+
+// Files returns the journal files at path, which is either a single file or
+// a directory of journal-*.bin files. Directory contents are returned oldest
+// first, which their names sort into.
+func Files(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "journal-*.bin"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadAll reads every entry from the journal files at path. A truncated
+// final record in a file is skipped.
+func ReadAll(path string) ([]*pb.JournalEntry, error) {
+	files, err := Files(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*pb.JournalEntry
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		r := NewReader(f)
+		for {
+			entry, err := r.Next()
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			entries = append(entries, entry)
+		}
+		f.Close()
+	}
+	return entries, nil
+}
@@ -0,0 +1,181 @@
+package servicetest
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// validateAddress checks address the way the service does, except that any
+// two-letter country code is accepted and postal codes are not checked.
+func validateAddress(address *pb.UserAddress) error {
+	address.StateCode = strings.ToUpper(strings.TrimSpace(address.StateCode))
+	address.CountryCode = strings.ToUpper(strings.TrimSpace(address.CountryCode))
+	address.PostalCode = strings.ToUpper(strings.TrimSpace(address.PostalCode))
+	if address.CountryCode == "UK" {
+		address.CountryCode = "GB"
+	}
+	if strings.TrimSpace(address.Line1) == "" {
+		return status.Error(codes.InvalidArgument, "line1 is required")
+	}
+	if strings.TrimSpace(address.City) == "" {
+		return status.Error(codes.InvalidArgument, "city is required")
+	}
+	if len(address.CountryCode) != 2 {
+		return status.Errorf(codes.InvalidArgument, "country_code %q is not an ISO 3166-1 alpha-2 code", address.CountryCode)
+	}
+	return nil
+}
+
+// findAddress returns one of the user's addresses. f.mu must be held.
+func (f *FakeUserService) findAddress(userID, addressID string) (*pb.UserAddress, error) {
+	if _, ok := f.users[userID]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", userID)
+	}
+	address, ok := f.addresses[addressID]
+	if !ok || address.UserId != userID {
+		return nil, status.Errorf(codes.NotFound, "address %s not found", addressID)
+	}
+	return address, nil
+}
+
+// setDefaultAddress makes address the only default of its user. f.mu must
+// be held.
+func (f *FakeUserService) setDefaultAddress(address *pb.UserAddress) {
+	for _, other := range f.addresses {
+		if other.UserId == address.UserId {
+			other.IsDefault = false
+		}
+	}
+	address.IsDefault = true
+}
+
+// AddUserAddress never geocodes, so addresses have no coordinates.
+func (f *FakeUserService) AddUserAddress(ctx context.Context, req *pb.AddUserAddressRequest) (*pb.UserAddressResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	address := &pb.UserAddress{
+		UserId:      req.UserId,
+		Line1:       req.Line1,
+		Line2:       req.Line2,
+		City:        req.City,
+		StateCode:   req.StateCode,
+		CountryCode: req.CountryCode,
+		PostalCode:  req.PostalCode,
+	}
+	if err := validateAddress(address); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	first := true
+	for _, other := range f.addresses {
+		if other.UserId == req.UserId {
+			first = false
+		}
+	}
+	f.nextAddressID++
+	address.Id = strconv.Itoa(f.nextAddressID)
+	f.addresses[address.Id] = address
+	if first || req.IsDefault {
+		f.setDefaultAddress(address)
+	}
+	return &pb.UserAddressResponse{Address: proto.Clone(address).(*pb.UserAddress)}, nil
+}
+
+func (f *FakeUserService) GetUserAddresses(ctx context.Context, req *pb.GetUserAddressesRequest) (*pb.GetUserAddressesResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	res := &pb.GetUserAddressesResponse{}
+	for _, address := range f.addresses {
+		if address.UserId == req.UserId {
+			res.Addresses = append(res.Addresses, proto.Clone(address).(*pb.UserAddress))
+		}
+	}
+	sort.Slice(res.Addresses, func(i, j int) bool {
+		a, b := res.Addresses[i], res.Addresses[j]
+		if a.IsDefault != b.IsDefault {
+			return a.IsDefault
+		}
+		x, _ := strconv.Atoi(a.Id)
+		y, _ := strconv.Atoi(b.Id)
+		return x < y
+	})
+	return res, nil
+}
+
+func (f *FakeUserService) UpdateUserAddress(ctx context.Context, req *pb.UpdateUserAddressRequest) (*pb.UserAddressResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	update := &pb.UserAddress{
+		Line1:       req.Line1,
+		Line2:       req.Line2,
+		City:        req.City,
+		StateCode:   req.StateCode,
+		CountryCode: req.CountryCode,
+		PostalCode:  req.PostalCode,
+	}
+	if err := validateAddress(update); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	address, err := f.findAddress(req.UserId, req.AddressId)
+	if err != nil {
+		return nil, err
+	}
+	address.Line1, address.Line2, address.City = update.Line1, update.Line2, update.City
+	address.StateCode, address.CountryCode, address.PostalCode = update.StateCode, update.CountryCode, update.PostalCode
+	address.Latitude, address.Longitude = nil, nil
+	return &pb.UserAddressResponse{Address: proto.Clone(address).(*pb.UserAddress)}, nil
+}
+
+func (f *FakeUserService) DeleteUserAddress(ctx context.Context, req *pb.DeleteUserAddressRequest) (*pb.DeleteUserAddressResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.findAddress(req.UserId, req.AddressId); err != nil {
+		return nil, err
+	}
+	delete(f.addresses, req.AddressId)
+	return &pb.DeleteUserAddressResponse{}, nil
+}
+
+func (f *FakeUserService) SetDefaultAddress(ctx context.Context, req *pb.SetDefaultAddressRequest) (*pb.UserAddressResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	address, err := f.findAddress(req.UserId, req.AddressId)
+	if err != nil {
+		return nil, err
+	}
+	f.setDefaultAddress(address)
+	return &pb.UserAddressResponse{Address: proto.Clone(address).(*pb.UserAddress)}, nil
+}
@@ -0,0 +1,158 @@
+package servicetest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// SampleCatalogCSV is a catalog in the format ImportProductsFromURL reads.
+// Its last row has an invalid price, so an import of it creates three
+// products and fails one row.
+const SampleCatalogCSV = `name,description,price
+Espresso Cup,Porcelain cup for a double shot,8.50
+Pour-Over Kettle,"Gooseneck kettle, 1 litre",42.00
+Burr Grinder,,129.99
+Milk Jug,Stainless steel,not-a-price
+`
+
+// NewCatalogServer starts an HTTPS server that serves SampleCatalogCSV at
+// /catalog.csv, for testing imports. Its certificate is self-signed, so
+// clients must use the server's Client; pass it to
+// FakeProductService.SetImportClient. The caller must Close the server.
+func NewCatalogServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/catalog.csv", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		io.WriteString(w, SampleCatalogCSV)
+	})
+	return httptest.NewTLSServer(mux)
+}
+
+// SetImportClient sets the client ImportProductsFromURL fetches catalogs
+// with, such as a NewCatalogServer's Client. It defaults to
+// http.DefaultClient.
+func (f *FakeProductService) SetImportClient(client *http.Client) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.importClient = client
+}
+
+type catalogRow struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	err         error
+}
+
+// ImportProductsFromURL fetches the whole catalog before importing it, and
+// allows any HTTPS URL rather than only IMPORT_ALLOWED_DOMAINS. It sends a
+// single progress update at the end.
+func (f *FakeProductService) ImportProductsFromURL(req *pb.ImportProductsFromURLRequest, stream pb.ProductService_ImportProductsFromURLServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || u.Scheme != "https" {
+		return status.Errorf(codes.InvalidArgument, "import URL must use https: %q", req.Url)
+	}
+
+	f.mu.Lock()
+	client := f.importClient
+	f.mu.Unlock()
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpReq, err := http.NewRequestWithContext(stream.Context(), http.MethodGet, req.Url, nil)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid import URL %q", req.Url)
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to fetch catalog: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return status.Errorf(codes.FailedPrecondition, "catalog URL returned %s", resp.Status)
+	}
+
+	var rows []catalogRow
+	switch req.Format {
+	case pb.ImportFormat_IMPORT_FORMAT_CSV:
+		rows, err = parseCatalogCSV(resp.Body)
+	case pb.ImportFormat_IMPORT_FORMAT_JSON:
+		err = json.NewDecoder(resp.Body).Decode(&rows)
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported import format %v", req.Format)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid catalog: %v", err)
+	}
+
+	progress := &pb.ImportProgressUpdate{}
+	f.mu.Lock()
+	for i, row := range rows {
+		progress.Processed++
+		if row.err == nil && strings.TrimSpace(row.Name) == "" {
+			row.err = fmt.Errorf("name is required")
+		}
+		if row.err != nil {
+			progress.Failed++
+			progress.Errors = append(progress.Errors, &pb.ImportError{Row: int32(i + 1), Message: row.err.Error()})
+			continue
+		}
+		if req.DryRun {
+			continue
+		}
+		product := &pb.Product{Id: f.newID(), Name: strings.TrimSpace(row.Name), Description: row.Description, Price: row.Price, UpdatedAt: timestamppb.Now()}
+		f.products[product.Id] = product
+		f.emit(pb.ProductEventType_PRODUCT_CREATED, product)
+		progress.Created++
+	}
+	f.mu.Unlock()
+	return stream.Send(progress)
+}
+
+// parseCatalogCSV reads a CSV catalog with a header row naming its name,
+// description and price columns.
+func parseCatalogCSV(r io.Reader) ([]catalogRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("catalog is empty")
+	}
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	rows := make([]catalogRow, len(records)-1)
+	for i, record := range records[1:] {
+		field := func(column string) string {
+			if j, ok := columns[column]; ok && j < len(record) {
+				return strings.TrimSpace(record[j])
+			}
+			return ""
+		}
+		rows[i] = catalogRow{Name: field("name"), Description: field("description")}
+		if rows[i].Price, err = strconv.ParseFloat(field("price"), 64); err != nil {
+			rows[i].err = fmt.Errorf("invalid price %q", field("price"))
+		}
+	}
+	return rows, nil
+}
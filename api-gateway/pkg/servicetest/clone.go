@@ -0,0 +1,66 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// CloneProduct copies the product's FAQ and tags, like the service.
+func (f *FakeProductService) CloneProduct(ctx context.Context, req *pb.CloneProductRequest) (*pb.CloneProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.NewName)
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_name is required")
+	}
+	if req.AdjustPriceByPercent < -50 || req.AdjustPriceByPercent > 100 {
+		return nil, status.Error(codes.InvalidArgument, "adjust_price_by_percent must be between -50 and 100")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	source, ok := f.products[req.SourceProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.SourceProductId)
+	}
+	if name == source.Name {
+		return nil, status.Error(codes.InvalidArgument, "new_name must differ from the source product's name")
+	}
+	clone := proto.Clone(source).(*pb.Product)
+	clone.Id = f.newID()
+	clone.Name = name
+	clone.Price = math.Round(source.Price*(100+req.AdjustPriceByPercent)) / 100
+	clone.Status = pb.ProductStatus_PRODUCT_STATUS_ACTIVE
+	clone.ReviewStatus = pb.ReviewStatus_REVIEW_STATUS_PENDING
+	clone.UpdatedAt = timestamppb.Now()
+	f.products[clone.Id] = clone
+
+	if slugs, ok := f.productTags[source.Id]; ok {
+		tags := make(map[string]bool, len(slugs))
+		for slug := range slugs {
+			tags[slug] = true
+		}
+		f.productTags[clone.Id] = tags
+	}
+	for _, faq := range f.faqs[source.Id] {
+		f.nextFAQID++
+		copied := proto.Clone(faq).(*pb.ProductFAQ)
+		copied.Id = fmt.Sprint(f.nextFAQID)
+		copied.ProductId = clone.Id
+		copied.CreatedAt = timestamppb.Now()
+		f.faqs[clone.Id] = append(f.faqs[clone.Id], copied)
+	}
+	f.recordVersion(clone, "1.0.0")
+	f.emit(pb.ProductEventType_PRODUCT_CREATED, clone)
+	return &pb.CloneProductResponse{NewProductId: clone.Id}, nil
+}
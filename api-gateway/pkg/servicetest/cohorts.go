@@ -0,0 +1,164 @@
+package servicetest
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// SetRegisteredAt backdates a user's registration, which decides their
+// cohort. Users otherwise register when they are created or seeded.
+func (f *FakeUserService) SetRegisteredAt(userID string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registeredAt[userID] = at
+}
+
+func (f *FakeUserService) CreateCohort(ctx context.Context, req *pb.CreateCohortRequest) (*pb.CohortResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_date and end_date are required")
+	}
+	start, end := req.StartDate.AsTime(), req.EndDate.AsTime()
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "start_date must be before end_date")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cohort := range f.cohorts {
+		if cohort.Name == name {
+			return nil, status.Errorf(codes.AlreadyExists, "cohort %q already exists", name)
+		}
+		if start.Before(cohort.EndDate.AsTime()) && cohort.StartDate.AsTime().Before(end) {
+			return nil, status.Error(codes.FailedPrecondition, "cohort overlaps an existing cohort")
+		}
+	}
+	f.nextCohortID++
+	cohort := &pb.Cohort{Id: strconv.Itoa(f.nextCohortID), Name: name, StartDate: req.StartDate, EndDate: req.EndDate}
+	f.cohorts[cohort.Id] = cohort
+	return &pb.CohortResponse{Cohort: proto.Clone(cohort).(*pb.Cohort)}, nil
+}
+
+// inCohort reports whether the user registered within cohort. f.mu must be
+// held.
+func (f *FakeUserService) inCohort(userID string, cohort *pb.Cohort) bool {
+	at := f.registeredAt[userID]
+	return !at.Before(cohort.StartDate.AsTime()) && at.Before(cohort.EndDate.AsTime())
+}
+
+func (f *FakeUserService) GetUserCohort(ctx context.Context, req *pb.GetUserCohortRequest) (*pb.GetUserCohortResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	for _, cohort := range f.cohorts {
+		if f.inCohort(req.UserId, cohort) {
+			return &pb.GetUserCohortResponse{
+				CohortId:    cohort.Id,
+				CohortName:  cohort.Name,
+				CohortStart: proto.Clone(cohort.StartDate).(*timestamppb.Timestamp),
+				CohortEnd:   proto.Clone(cohort.EndDate).(*timestamppb.Timestamp),
+			}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "no cohort covers the registration of user %s", req.UserId)
+}
+
+// cohortMembers returns the ids of the cohort's users in order of
+// registration. f.mu must be held.
+func (f *FakeUserService) cohortMembers(cohortID string) ([]string, error) {
+	cohort, ok := f.cohorts[cohortID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "cohort %s not found", cohortID)
+	}
+	var members []string
+	for id := range f.users {
+		if f.inCohort(id, cohort) {
+			members = append(members, id)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := f.registeredAt[members[i]], f.registeredAt[members[j]]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		x, _ := strconv.Atoi(members[i])
+		y, _ := strconv.Atoi(members[j])
+		return x < y
+	})
+	return members, nil
+}
+
+// ListCohortMembers uses plain offsets as page tokens, like ListUsers.
+func (f *FakeUserService) ListCohortMembers(ctx context.Context, req *pb.ListCohortMembersRequest) (*pb.ListCohortMembersResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 50
+	}
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	members, err := f.cohortMembers(req.CohortId)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.ListCohortMembersResponse{}
+	if offset < len(members) {
+		members = members[offset:]
+	} else {
+		members = nil
+	}
+	if len(members) > pageSize {
+		members = members[:pageSize]
+		res.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	for _, id := range members {
+		res.Users = append(res.Users, proto.Clone(f.users[id]).(*pb.User))
+	}
+	return res, nil
+}
+
+func (f *FakeUserService) GetCohortStats(ctx context.Context, req *pb.GetCohortStatsRequest) (*pb.GetCohortStatsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	members, err := f.cohortMembers(req.CohortId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCohortStatsResponse{Size: int64(len(members))}, nil
+}
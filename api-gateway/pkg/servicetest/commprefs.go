@@ -0,0 +1,97 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+var (
+	communicationChannels = map[string]bool{"EMAIL": true, "SMS": true, "PUSH": true}
+	communicationTypes    = map[string]bool{"marketing": true, "transactional": true, "alerts": true}
+)
+
+type communicationKey struct {
+	userID, channel, kind string
+}
+
+func validateCommunicationKind(channel, kind string) error {
+	if !communicationChannels[channel] {
+		return status.Errorf(codes.InvalidArgument, "unknown channel %q", channel)
+	}
+	if !communicationTypes[kind] {
+		return status.Errorf(codes.InvalidArgument, "unknown communication type %q", kind)
+	}
+	return nil
+}
+
+// setCommunication records a preference. f.mu must be held.
+func (f *FakeUserService) setCommunication(key communicationKey, subscribed bool) *pb.CommunicationPreference {
+	pref := &pb.CommunicationPreference{
+		UserId:     key.userID,
+		Channel:    key.channel,
+		Type:       key.kind,
+		Subscribed: subscribed,
+		UpdatedAt:  timestamppb.Now(),
+	}
+	f.communication[key] = pref
+	return proto.Clone(pref).(*pb.CommunicationPreference)
+}
+
+func (f *FakeUserService) UpdateCommunicationPreference(ctx context.Context, req *pb.UpdateCommunicationPreferenceRequest) (*pb.UpdateCommunicationPreferenceResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if err := validateCommunicationKind(req.Channel, req.Type); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	pref := f.setCommunication(communicationKey{req.UserId, req.Channel, req.Type}, req.Subscribed)
+	return &pb.UpdateCommunicationPreferenceResponse{Preference: pref}, nil
+}
+
+// GenerateUnsubscribeToken returns opaque tokens that never expire, rather
+// than the service's signed ones.
+func (f *FakeUserService) GenerateUnsubscribeToken(ctx context.Context, req *pb.GenerateUnsubscribeTokenRequest) (*pb.GenerateUnsubscribeTokenResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if err := validateCommunicationKind(req.Channel, req.Type); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	token := fmt.Sprintf("unsubscribe-%d", len(f.unsubscribeTokens)+1)
+	f.unsubscribeTokens[token] = communicationKey{req.UserId, req.Channel, req.Type}
+	return &pb.GenerateUnsubscribeTokenResponse{Token: token}, nil
+}
+
+func (f *FakeUserService) UnsubscribeByToken(ctx context.Context, req *pb.UnsubscribeByTokenRequest) (*pb.UnsubscribeByTokenResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key, ok := f.unsubscribeTokens[req.Token]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired unsubscribe token")
+	}
+	if _, ok := f.users[key.userID]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", key.userID)
+	}
+	f.setCommunication(key, false)
+	return &pb.UnsubscribeByTokenResponse{Channel: key.channel, Type: key.kind}, nil
+}
@@ -0,0 +1,94 @@
+package servicetest
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// consentTypes are the consent types the service accepts by default.
+var consentTypes = map[string]bool{
+	"marketing_emails":    true,
+	"analytics_tracking":  true,
+	"third_party_sharing": true,
+}
+
+func (f *FakeUserService) RecordConsent(ctx context.Context, req *pb.RecordConsentRequest) (*pb.RecordConsentResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if !consentTypes[req.ConsentType] {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown consent type %q", req.ConsentType)
+	}
+	version := strings.TrimSpace(req.Version)
+	if version == "" {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+	if req.IpAddress != "" && net.ParseIP(req.IpAddress) == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ip_address %q", req.IpAddress)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	for _, consent := range f.consents {
+		if consent.UserId == req.UserId && consent.ConsentType == req.ConsentType && consent.Version == version && consent.WithdrawnAt == nil {
+			return &pb.RecordConsentResponse{ConsentId: consent.Id}, nil
+		}
+	}
+	consent := &pb.UserConsent{
+		Id:          strconv.Itoa(len(f.consents) + 1),
+		UserId:      req.UserId,
+		ConsentType: req.ConsentType,
+		Version:     version,
+		ConsentedAt: timestamppb.Now(),
+		IpAddress:   req.IpAddress,
+	}
+	f.consents = append(f.consents, consent)
+	return &pb.RecordConsentResponse{ConsentId: consent.Id}, nil
+}
+
+func (f *FakeUserService) WithdrawConsent(ctx context.Context, req *pb.WithdrawConsentRequest) (*pb.WithdrawConsentResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, consent := range f.consents {
+		if consent.Id == req.ConsentId {
+			if consent.WithdrawnAt == nil {
+				consent.WithdrawnAt = timestamppb.Now()
+			}
+			return &pb.WithdrawConsentResponse{}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "consent %s not found", req.ConsentId)
+}
+
+func (f *FakeUserService) GetConsentHistory(ctx context.Context, req *pb.GetConsentHistoryRequest) (*pb.GetConsentHistoryResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	res := &pb.GetConsentHistoryResponse{}
+	for i := len(f.consents) - 1; i >= 0; i-- {
+		if f.consents[i].UserId == req.UserId {
+			res.Consents = append(res.Consents, proto.Clone(f.consents[i]).(*pb.UserConsent))
+		}
+	}
+	return res, nil
+}
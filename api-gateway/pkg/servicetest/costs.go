@@ -0,0 +1,129 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+func (f *FakeProductService) SetProductCost(ctx context.Context, req *pb.SetProductCostRequest) (*pb.SetProductCostResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Cost == nil || req.Cost.Amount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "cost must not be negative")
+	}
+	if req.Cost.CurrencyCode != "" && req.Cost.CurrencyCode != currency {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported currency %q", req.Cost.CurrencyCode)
+	}
+	effectiveFrom := timestamppb.Now()
+	if req.EffectiveFrom != nil {
+		effectiveFrom = req.EffectiveFrom
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	from := effectiveFrom.AsTime()
+	costs := f.costs[req.ProductId]
+	for _, cost := range costs {
+		if cost.EffectiveFrom.AsTime().Equal(from) {
+			return nil, status.Errorf(codes.AlreadyExists, "product %s already has a cost effective from %s", req.ProductId, from.Format(time.RFC3339))
+		}
+	}
+	f.nextCostID++
+	cost := &pb.ProductCost{
+		Id:            fmt.Sprint(f.nextCostID),
+		ProductId:     req.ProductId,
+		SupplierCost:  money(req.Cost.Amount),
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     "anonymous",
+		CreatedAt:     timestamppb.Now(),
+	}
+	costs = append(costs, cost)
+	sort.Slice(costs, func(i, j int) bool { return costs[i].EffectiveFrom.AsTime().Before(costs[j].EffectiveFrom.AsTime()) })
+	for i := range costs {
+		costs[i].EffectiveTo = nil
+		if i+1 < len(costs) {
+			costs[i].EffectiveTo = costs[i+1].EffectiveFrom
+		}
+	}
+	f.costs[req.ProductId] = costs
+	return &pb.SetProductCostResponse{Cost: proto.Clone(cost).(*pb.ProductCost)}, nil
+}
+
+// GetProductMargin takes the sale price from the product's versions, like
+// the service.
+func (f *FakeProductService) GetProductMargin(ctx context.Context, req *pb.GetProductMarginRequest) (*pb.GetProductMarginResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	at := time.Now()
+	salePrice := product.Price
+	if req.At != nil {
+		at = req.At.AsTime()
+		if versions := f.versions[req.ProductId]; len(versions) > 0 {
+			salePrice = versions[0].Price.Amount
+			for _, version := range versions {
+				if !version.CreatedAt.AsTime().After(at) {
+					salePrice = version.Price.Amount
+				}
+			}
+		}
+	}
+	var cost *pb.ProductCost
+	for _, c := range f.costs[req.ProductId] {
+		if !c.EffectiveFrom.AsTime().After(at) && (c.EffectiveTo == nil || c.EffectiveTo.AsTime().After(at)) {
+			cost = c
+		}
+	}
+	if cost == nil {
+		return nil, status.Errorf(codes.NotFound, "product %s has no cost effective at %s", req.ProductId, at.Format(time.RFC3339))
+	}
+
+	margin := roundCents(salePrice - cost.SupplierCost.Amount)
+	res := &pb.GetProductMarginResponse{
+		SalePrice:   money(salePrice),
+		Cost:        money(cost.SupplierCost.Amount),
+		GrossMargin: margin,
+	}
+	if salePrice != 0 {
+		res.GrossMarginPercent = margin / salePrice * 100
+	}
+	return res, nil
+}
+
+func (f *FakeProductService) GetProductCostHistory(req *pb.GetProductCostHistoryRequest, stream pb.ProductService_GetProductCostHistoryServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	costs := make([]*pb.ProductCost, len(f.costs[req.ProductId]))
+	for i, cost := range f.costs[req.ProductId] {
+		costs[i] = proto.Clone(cost).(*pb.ProductCost)
+	}
+	f.mu.Unlock()
+	for _, cost := range costs {
+		if err := stream.Send(&pb.ProductCostResponse{Cost: cost}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package servicetest_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"api-gateway/pkg/servicetest"
+	pb "api-gateway/proto/gen/proto"
+)
+
+func Example() {
+	users := servicetest.NewFakeUserService()
+	users.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	srv := servicetest.NewServer(users, nil)
+	defer srv.Close()
+
+	conn, err := srv.Dial(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	res, err := pb.NewUserServiceClient(conn).GetUser(context.Background(), &pb.GetUserRequest{Id: "1"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(res.User.Name)
+	// Output: Pema Sherpa
+}
+
+func ExampleFakeUserService_FailNextWith() {
+	users := servicetest.NewFakeUserService()
+	users.Seed(&pb.User{Id: "1", Name: "Pema Sherpa", Email: "pema@example.com"})
+	srv := servicetest.NewServer(users, nil)
+	defer srv.Close()
+
+	conn, err := srv.Dial(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUserServiceClient(conn)
+
+	users.FailNextWith(codes.Unavailable)
+	for i := 0; i < 2; i++ {
+		_, err := client.GetUser(context.Background(), &pb.GetUserRequest{Id: "1"})
+		fmt.Println(status.Code(err))
+	}
+	fmt.Println(len(users.Requests()), "requests")
+	// Output:
+	// Unavailable
+	// OK
+	// 2 requests
+}
+
+func ExampleFakeProductService_Delay() {
+	products := servicetest.NewFakeProductService()
+	products.Seed(&pb.Product{Id: "7", Name: "Mug", Price: 9.5})
+	srv := servicetest.NewServer(nil, products)
+	defer srv.Close()
+
+	conn, err := srv.Dial(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	products.Delay(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pb.NewProductServiceClient(conn).GetProduct(ctx, &pb.GetProductRequest{Id: "7"})
+	fmt.Println(status.Code(err))
+	// Output: DeadlineExceeded
+}
+
+func ExampleFakeProductService_SeedTaxRule() {
+	products := servicetest.NewFakeProductService()
+	products.Seed(&pb.Product{Id: "7", Name: "Novel", Price: 12.5})
+	products.SeedTaxRule("CA", "", "*", 0.05, "GST")
+	products.SeedTaxRule("CA", "ON", "*", 0.13, "HST")
+	srv := servicetest.NewServer(nil, products)
+	defer srv.Close()
+
+	conn, err := srv.Dial(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewProductServiceClient(conn)
+
+	for _, region := range []string{"ON", "BC"} {
+		res, err := client.CalculateTax(context.Background(), &pb.CalculateTaxRequest{ProductId: "7", Quantity: 2, CountryCode: "CA", RegionCode: region})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(region, res.TaxName, res.TaxAmount.Amount)
+	}
+	// Output:
+	// ON HST 3.25
+	// BC GST 1.25
+}
@@ -0,0 +1,182 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// touchProduct bumps a product's updated_at and tells watchers, as the
+// service does when its FAQ changes. f.mu must be held.
+func (f *FakeProductService) touchProduct(id string) error {
+	product, ok := f.products[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "product %s not found", id)
+	}
+	product.UpdatedAt = timestamppb.Now()
+	f.emit(pb.ProductEventType_PRODUCT_UPDATED, product)
+	return nil
+}
+
+// findFAQ returns the position of a FAQ entry of a product. f.mu must be
+// held.
+func (f *FakeProductService) findFAQ(productID, faqID string) (int, error) {
+	if _, ok := f.products[productID]; !ok {
+		return 0, status.Errorf(codes.NotFound, "product %s not found", productID)
+	}
+	for i, faq := range f.faqs[productID] {
+		if faq.Id == faqID {
+			return i, nil
+		}
+	}
+	return 0, status.Errorf(codes.NotFound, "FAQ %s not found for product %s", faqID, productID)
+}
+
+// renumberFAQs sets the order_index of a product's entries from their
+// position. f.mu must be held.
+func (f *FakeProductService) renumberFAQs(productID string) {
+	for i, faq := range f.faqs[productID] {
+		faq.OrderIndex = int32(i)
+	}
+}
+
+// AddProductFAQ records every entry as created by "anonymous" and does not
+// limit the length or number of entries.
+func (f *FakeProductService) AddProductFAQ(ctx context.Context, req *pb.AddProductFAQRequest) (*pb.ProductFAQResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	question, answer := strings.TrimSpace(req.Question), strings.TrimSpace(req.Answer)
+	if question == "" || answer == "" {
+		return nil, status.Error(codes.InvalidArgument, "question and answer are required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.touchProduct(req.ProductId); err != nil {
+		return nil, err
+	}
+	f.nextFAQID++
+	faq := &pb.ProductFAQ{
+		Id:         fmt.Sprint(f.nextFAQID),
+		ProductId:  req.ProductId,
+		Question:   question,
+		Answer:     answer,
+		OrderIndex: int32(len(f.faqs[req.ProductId])),
+		CreatedBy:  "anonymous",
+		CreatedAt:  timestamppb.Now(),
+	}
+	f.faqs[req.ProductId] = append(f.faqs[req.ProductId], faq)
+	return &pb.ProductFAQResponse{Faq: proto.Clone(faq).(*pb.ProductFAQ)}, nil
+}
+
+func (f *FakeProductService) UpdateProductFAQ(ctx context.Context, req *pb.UpdateProductFAQRequest) (*pb.ProductFAQResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	question, answer := strings.TrimSpace(req.Question), strings.TrimSpace(req.Answer)
+	if question == "" && answer == "" {
+		return nil, status.Error(codes.InvalidArgument, "question or answer is required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.findFAQ(req.ProductId, req.FaqId)
+	if err != nil {
+		return nil, err
+	}
+	faq := f.faqs[req.ProductId][i]
+	if question != "" {
+		faq.Question = question
+	}
+	if answer != "" {
+		faq.Answer = answer
+	}
+	f.touchProduct(req.ProductId)
+	return &pb.ProductFAQResponse{Faq: proto.Clone(faq).(*pb.ProductFAQ)}, nil
+}
+
+func (f *FakeProductService) DeleteProductFAQ(ctx context.Context, req *pb.DeleteProductFAQRequest) (*pb.DeleteProductFAQResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.findFAQ(req.ProductId, req.FaqId)
+	if err != nil {
+		return nil, err
+	}
+	faqs := f.faqs[req.ProductId]
+	f.faqs[req.ProductId] = append(faqs[:i], faqs[i+1:]...)
+	f.renumberFAQs(req.ProductId)
+	f.touchProduct(req.ProductId)
+	return &pb.DeleteProductFAQResponse{}, nil
+}
+
+func (f *FakeProductService) ReorderProductFAQs(ctx context.Context, req *pb.ReorderProductFAQsRequest) (*pb.ReorderProductFAQsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	byID := make(map[string]*pb.ProductFAQ)
+	for _, faq := range f.faqs[req.ProductId] {
+		byID[faq.Id] = faq
+	}
+	if len(req.FaqIdOrder) != len(byID) {
+		return nil, status.Errorf(codes.InvalidArgument, "faq_id_order must list all %d FAQ entries of product %s", len(byID), req.ProductId)
+	}
+	reordered := make([]*pb.ProductFAQ, len(req.FaqIdOrder))
+	for i, id := range req.FaqIdOrder {
+		faq, ok := byID[id]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "FAQ id %q is unknown or repeated", id)
+		}
+		delete(byID, id)
+		reordered[i] = faq
+	}
+	f.faqs[req.ProductId] = reordered
+	f.renumberFAQs(req.ProductId)
+	f.touchProduct(req.ProductId)
+
+	res := &pb.ReorderProductFAQsResponse{Faqs: make([]*pb.ProductFAQ, len(reordered))}
+	for i, faq := range reordered {
+		res.Faqs[i] = proto.Clone(faq).(*pb.ProductFAQ)
+	}
+	return res, nil
+}
+
+func (f *FakeProductService) ListProductFAQs(req *pb.ListProductFAQsRequest, stream pb.ProductService_ListProductFAQsServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		f.mu.Unlock()
+		return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	faqs := make([]*pb.ProductFAQ, len(f.faqs[req.ProductId]))
+	for i, faq := range f.faqs[req.ProductId] {
+		faqs[i] = proto.Clone(faq).(*pb.ProductFAQ)
+	}
+	f.mu.Unlock()
+	for _, faq := range faqs {
+		if err := stream.Send(&pb.ProductFAQResponse{Faq: faq}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package servicetest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// FeedBaseURL is the storefront URL the fake's Google Shopping feed links
+// products under.
+const FeedBaseURL = "http://localhost:8080"
+
+type feedItem struct {
+	XMLName      xml.Name `xml:"item"`
+	ID           string   `xml:"g:id"`
+	Title        string   `xml:"g:title"`
+	Description  string   `xml:"g:description"`
+	Link         string   `xml:"g:link"`
+	ImageLink    string   `xml:"g:image_link"`
+	Availability string   `xml:"g:availability"`
+	Price        string   `xml:"g:price"`
+	Condition    string   `xml:"g:condition"`
+	Brand        string   `xml:"g:brand,omitempty"`
+}
+
+// ExportGoogleShoppingFeed sends the whole feed as one chunk and does not
+// truncate long titles or descriptions.
+func (f *FakeProductService) ExportGoogleShoppingFeed(req *pb.ExportGoogleShoppingFeedRequest, stream pb.ProductService_ExportGoogleShoppingFeedServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	var items []feedItem
+	for _, product := range f.products {
+		if !visible(product, false) || product.ImageUrl == "" {
+			continue
+		}
+		description := product.Description
+		if description == "" {
+			description = product.Name
+		}
+		items = append(items, feedItem{
+			ID:           product.Id,
+			Title:        product.Name,
+			Description:  description,
+			Link:         FeedBaseURL + "/products/" + product.Id,
+			ImageLink:    product.ImageUrl,
+			Availability: "in_stock",
+			Price:        fmt.Sprintf("%.2f USD", product.Price),
+			Condition:    "new",
+			Brand:        product.Brand,
+		})
+	}
+	f.mu.Unlock()
+	sort.Slice(items, func(i, j int) bool {
+		a, _ := strconv.Atoi(items[i].ID)
+		b, _ := strconv.Atoi(items[j].ID)
+		return a < b
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + `<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">` + "\n<channel>\n")
+	fmt.Fprintf(&buf, "<title>Products</title>\n<link>%s</link>\n<description>Product catalog</description>\n", FeedBaseURL)
+	encoder := xml.NewEncoder(&buf)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	buf.WriteString("\n</channel>\n</rss>\n")
+	return stream.Send(&pb.ExportChunk{Data: buf.Bytes()})
+}
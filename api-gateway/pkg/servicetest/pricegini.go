@@ -0,0 +1,50 @@
+package servicetest
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// GetCatalogPriceGini computes the coefficient exactly on every call,
+// without sampling or caching.
+func (f *FakeProductService) GetCatalogPriceGini(ctx context.Context, req *pb.GetCatalogPriceGiniRequest) (*pb.GetCatalogPriceGiniResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	var prices []float64
+	for _, product := range f.products {
+		if visible(product, false) {
+			prices = append(prices, product.Price)
+		}
+	}
+	f.mu.Unlock()
+	sort.Float64s(prices)
+
+	res := &pb.GetCatalogPriceGiniResponse{
+		Percentiles: make(map[int32]float64),
+		ComputedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	n := float64(len(prices))
+	var sum, weighted float64
+	for i, price := range prices {
+		sum += price
+		weighted += float64(i+1) * price
+	}
+	if sum > 0 {
+		res.GiniCoefficient = 2*weighted/(n*sum) - (n+1)/n
+	}
+	if len(prices) > 0 {
+		for _, p := range []int32{10, 25, 50, 75, 90, 95, 99} {
+			rank := float64(p) / 100 * (n - 1)
+			lower := int(math.Floor(rank))
+			upper := int(math.Min(float64(lower+1), n-1))
+			res.Percentiles[p] = prices[lower] + (rank-float64(lower))*(prices[upper]-prices[lower])
+		}
+	}
+	return res, nil
+}
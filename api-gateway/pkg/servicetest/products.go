@@ -0,0 +1,949 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+const currency = "USD"
+
+// FakeProductService is an in-memory ProductService.
+type FakeProductService struct {
+	// See FakeUserService for why the unsafe interface is embedded.
+	pb.UnsafeProductServiceServer
+	faults
+
+	mu            sync.Mutex
+	nextID        int
+	sequence      int64
+	products      map[string]*pb.Product
+	discountCodes map[string]float64
+	export        []byte
+	watchers      map[chan *pb.ProductEvent]struct{}
+	nextAlertID   int
+	priceAlerts   map[string]*pb.PriceAlert
+	tags          map[string]*pb.Tag
+	productTags   map[string]map[string]bool
+	embeddings    map[string][]float32
+	// taxRules are the rules added by SeedTaxRule, in order.
+	taxRules     []taxRule
+	importClient *http.Client
+	// faqs holds each product's FAQ entries in order.
+	nextFAQID int
+	faqs      map[string][]*pb.ProductFAQ
+	// versions holds each product's versions, oldest first.
+	nextVersionID int
+	versions      map[string][]*pb.ProductVersion
+	// costs holds each product's costs by effective_from.
+	nextCostID int
+	costs      map[string][]*pb.ProductCost
+	// purchaseLimits holds each product's purchase limit.
+	purchaseLimits map[string]*pb.PurchaseLimit
+}
+
+var _ pb.ProductServiceServer = (*FakeProductService)(nil)
+
+func NewFakeProductService() *FakeProductService {
+	return &FakeProductService{
+		products:       make(map[string]*pb.Product),
+		discountCodes:  make(map[string]float64),
+		watchers:       make(map[chan *pb.ProductEvent]struct{}),
+		priceAlerts:    make(map[string]*pb.PriceAlert),
+		tags:           make(map[string]*pb.Tag),
+		productTags:    make(map[string]map[string]bool),
+		embeddings:     make(map[string][]float32),
+		faqs:           make(map[string][]*pb.ProductFAQ),
+		versions:       make(map[string][]*pb.ProductVersion),
+		costs:          make(map[string][]*pb.ProductCost),
+		purchaseLimits: make(map[string]*pb.PurchaseLimit),
+	}
+}
+
+// Seed adds products as if they had been created. Products without an id are
+// given one. Seeded products are approved; ReviewProduct changes that.
+func (f *FakeProductService) Seed(products ...*pb.Product) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, product := range products {
+		product = proto.Clone(product).(*pb.Product)
+		if product.Id == "" {
+			product.Id = f.newID()
+		}
+		product.ReviewStatus = pb.ReviewStatus_REVIEW_STATUS_APPROVED
+		if product.UpdatedAt == nil {
+			product.UpdatedAt = timestamppb.Now()
+		}
+		f.products[product.Id] = product
+	}
+}
+
+// SeedDiscountCode makes code take percentOff percent off a cart. Codes never
+// expire or run out.
+func (f *FakeProductService) SeedDiscountCode(code string, percentOff float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.discountCodes[code] = percentOff
+}
+
+// SetExport sets the bytes ExportProductsParquet streams back.
+func (f *FakeProductService) SetExport(data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.export = data
+}
+
+func (f *FakeProductService) newID() string {
+	f.nextID++
+	return fmt.Sprint(f.nextID)
+}
+
+func (f *FakeProductService) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.ProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product := &pb.Product{Id: f.newID(), Name: req.Name, Description: req.Description, Brand: req.Brand, ImageUrl: req.ImageUrl, Price: req.Price, UpdatedAt: timestamppb.Now(), ReviewStatus: pb.ReviewStatus_REVIEW_STATUS_APPROVED}
+	f.products[product.Id] = product
+	f.recordVersion(product, "1.0.0")
+
+	f.emit(pb.ProductEventType_PRODUCT_CREATED, product)
+	return &pb.ProductResponse{Product: proto.Clone(product).(*pb.Product)}, nil
+}
+
+// emit sends an event to every watcher. f.mu must be held.
+func (f *FakeProductService) emit(eventType pb.ProductEventType, product *pb.Product) {
+	f.sequence++
+	event := &pb.ProductEvent{
+		Type:       eventType,
+		Product:    proto.Clone(product).(*pb.Product),
+		OccurredAt: timestamppb.Now(),
+		Sequence:   f.sequence,
+	}
+	for watcher := range f.watchers {
+		select {
+		case watcher <- event:
+		default:
+		}
+	}
+}
+
+func (f *FakeProductService) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.Id)
+	}
+	return &pb.ProductResponse{Product: proto.Clone(product).(*pb.Product)}, nil
+}
+
+func (f *FakeProductService) CalculateCartTotal(ctx context.Context, req *pb.CalculateCartTotalRequest) (*pb.CalculateCartTotalResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cart must contain at least one item")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.CalculateCartTotalResponse{}
+	var subtotal float64
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity for product %s must be positive", item.ProductId)
+		}
+		product, ok := f.products[item.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "product %s not found", item.ProductId)
+		}
+		if product.Status == pb.ProductStatus_PRODUCT_STATUS_ARCHIVED {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is archived", item.ProductId)
+		}
+		lineTotal := roundCents(product.Price * float64(item.Quantity))
+		subtotal += lineTotal
+		res.LineItems = append(res.LineItems, &pb.LineItem{
+			ProductId: item.ProductId,
+			Name:      product.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: money(product.Price),
+			Total:     money(lineTotal),
+		})
+	}
+
+	var discount float64
+	if req.DiscountCode != "" {
+		percentOff, ok := f.discountCodes[req.DiscountCode]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "discount code %q not found", req.DiscountCode)
+		}
+		discount = math.Min(roundCents(subtotal*percentOff/100), subtotal)
+	}
+
+	var tax float64
+	if req.CountryCode != "" {
+		country, region, err := jurisdiction(req.CountryCode, req.RegionCode)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range res.LineItems {
+			tax += item.Total.Amount * f.taxRuleFor(item.ProductId, country, region).rate
+		}
+		if subtotal > 0 {
+			tax = roundCents(roundCents(tax) * (subtotal - discount) / subtotal)
+		}
+	}
+
+	res.Subtotal = money(subtotal)
+	res.DiscountAmount = money(discount)
+	res.TaxAmount = money(tax)
+	res.Total = money(subtotal - discount + tax)
+	return res, nil
+}
+
+func (f *FakeProductService) WatchProducts(req *pb.WatchProductsRequest, stream pb.ProductService_WatchProductsServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	events := f.watch()
+	defer f.unwatch(events)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *FakeProductService) WatchCacheInvalidations(req *pb.WatchCacheInvalidationsRequest, stream pb.ProductService_WatchCacheInvalidationsServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	events := f.watch()
+	defer f.unwatch(events)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			msg := &pb.CacheInvalidation{
+				Key:       "product:" + event.Product.GetId(),
+				UpdatedAt: event.Product.GetUpdatedAt(),
+				Sequence:  event.Sequence,
+				Source:    "servicetest",
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SyncProductCatalog reads the history and starts watching under one lock,
+// so the two never overlap. The fake never deletes products, and its
+// history reports every product as updated.
+func (f *FakeProductService) SyncProductCatalog(req *pb.SyncProductCatalogRequest, stream pb.ProductService_SyncProductCatalogServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	events := make(chan *pb.ProductEvent, 64)
+	var history []*pb.Product
+	f.mu.Lock()
+	for _, product := range f.products {
+		if req.Since == nil || !product.UpdatedAt.AsTime().Before(req.Since.AsTime()) {
+			history = append(history, proto.Clone(product).(*pb.Product))
+		}
+	}
+	f.watchers[events] = struct{}{}
+	f.mu.Unlock()
+	defer f.unwatch(events)
+	sort.Slice(history, func(i, j int) bool {
+		a, b := history[i].UpdatedAt.AsTime(), history[j].UpdatedAt.AsTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		x, _ := strconv.Atoi(history[i].Id)
+		y, _ := strconv.Atoi(history[j].Id)
+		return x < y
+	})
+
+	var sequence int64
+	send := func(eventType pb.ProductEventType, product *pb.Product) error {
+		sequence++
+		return stream.Send(&pb.SyncEvent{EventType: eventType.String(), Product: product, SequenceNumber: sequence})
+	}
+	for _, product := range history {
+		if err := send(pb.ProductEventType_PRODUCT_UPDATED, product); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if event.Type == pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED {
+				continue
+			}
+			if err := send(event.Type, event.Product); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *FakeProductService) ExportProductsParquet(req *pb.ExportProductsParquetRequest, stream pb.ProductService_ExportProductsParquetServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	data := f.export
+	f.mu.Unlock()
+	if len(data) == 0 {
+		return nil
+	}
+	return stream.Send(&pb.ExportChunk{Data: data})
+}
+
+// GetProductQRCode returns a placeholder image naming the product and size
+// rather than a real QR code.
+func (f *FakeProductService) GetProductQRCode(ctx context.Context, req *pb.GetProductQRCodeRequest) (*pb.GetProductQRCodeResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	contentType := "image/png"
+	if req.Format == pb.QRFormat_QR_FORMAT_SVG {
+		contentType = "image/svg+xml"
+	}
+	data := []byte(fmt.Sprintf("qrcode:%s:%d", req.ProductId, req.Size))
+	return &pb.GetProductQRCodeResponse{ImageData: data, ContentType: contentType}, nil
+}
+
+// CreatePriceAlert stores the alert. Like the real service, it is triggered
+// straight away if the product's price already meets the target, but no
+// event is published for it.
+func (f *FakeProductService) CreatePriceAlert(ctx context.Context, req *pb.CreatePriceAlertRequest) (*pb.PriceAlertResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.TargetPrice.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "target_price must be positive")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	f.nextAlertID++
+	alert := &pb.PriceAlert{
+		Id:          fmt.Sprint(f.nextAlertID),
+		UserId:      req.UserId,
+		ProductId:   req.ProductId,
+		TargetPrice: money(req.TargetPrice.Amount),
+		Triggered:   product.Price <= req.TargetPrice.Amount,
+		CreatedAt:   timestamppb.Now(),
+	}
+	f.priceAlerts[alert.Id] = alert
+	return &pb.PriceAlertResponse{PriceAlert: proto.Clone(alert).(*pb.PriceAlert)}, nil
+}
+
+func (f *FakeProductService) DeletePriceAlert(ctx context.Context, req *pb.DeletePriceAlertRequest) (*pb.DeletePriceAlertResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.priceAlerts[req.Id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "price alert %s not found", req.Id)
+	}
+	delete(f.priceAlerts, req.Id)
+	return &pb.DeletePriceAlertResponse{}, nil
+}
+
+func (f *FakeProductService) ListPriceAlerts(ctx context.Context, req *pb.ListPriceAlertsRequest) (*pb.ListPriceAlertsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.UserId == "" && req.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id or product_id is required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.ListPriceAlertsResponse{}
+	for _, alert := range f.priceAlerts {
+		if (req.UserId != "" && alert.UserId != req.UserId) ||
+			(req.ProductId != "" && alert.ProductId != req.ProductId) ||
+			(alert.Triggered && !req.IncludeTriggered) {
+			continue
+		}
+		res.PriceAlerts = append(res.PriceAlerts, proto.Clone(alert).(*pb.PriceAlert))
+	}
+	sort.Slice(res.PriceAlerts, func(i, j int) bool {
+		a, _ := strconv.Atoi(res.PriceAlerts[i].Id)
+		b, _ := strconv.Atoi(res.PriceAlerts[j].Id)
+		return a < b
+	})
+	return res, nil
+}
+
+func (f *FakeProductService) GetPriceAlertStats(ctx context.Context, req *pb.GetPriceAlertStatsRequest) (*pb.GetPriceAlertStatsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var targets []float64
+	for _, alert := range f.priceAlerts {
+		if alert.ProductId == req.ProductId && !alert.Triggered {
+			targets = append(targets, alert.TargetPrice.Amount)
+		}
+	}
+	res := &pb.GetPriceAlertStatsResponse{ActiveCount: int64(len(targets))}
+	if len(targets) == 0 {
+		return res, nil
+	}
+	sort.Float64s(targets)
+	var sum float64
+	for _, target := range targets {
+		sum += target
+	}
+	median := targets[len(targets)/2]
+	if len(targets)%2 == 0 {
+		median = (targets[len(targets)/2-1] + median) / 2
+	}
+	res.MinTarget = money(targets[0])
+	res.MaxTarget = money(targets[len(targets)-1])
+	res.MeanTarget = money(sum / float64(len(targets)))
+	res.MedianTarget = money(median)
+	return res, nil
+}
+
+func (f *FakeProductService) SetProductTags(ctx context.Context, req *pb.SetProductTagsRequest) (*pb.SetProductTagsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	slugs := make(map[string]bool)
+	res := &pb.SetProductTagsResponse{}
+	for _, name := range req.Tags {
+		slug := slugify(name)
+		if slug == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tag %q", name)
+		}
+		if slugs[slug] {
+			continue
+		}
+		slugs[slug] = true
+		tag, ok := f.tags[slug]
+		if !ok {
+			tag = &pb.Tag{Id: fmt.Sprint(len(f.tags) + 1), Name: strings.TrimSpace(name), Slug: slug}
+			f.tags[slug] = tag
+		}
+		res.Tags = append(res.Tags, proto.Clone(tag).(*pb.Tag))
+	}
+	f.productTags[req.ProductId] = slugs
+	return res, nil
+}
+
+// SearchProductsByTags sees tag changes immediately, unlike the real service
+// which searches a periodically refreshed copy.
+func (f *FakeProductService) SearchProductsByTags(ctx context.Context, req *pb.SearchProductsByTagsRequest) (*pb.ListProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if len(req.Tags) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one tag is required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.ListProductsResponse{}
+	for id, slugs := range f.productTags {
+		matches := req.Operator == pb.TagOperator_TAG_OPERATOR_AND
+		for _, name := range req.Tags {
+			if req.Operator == pb.TagOperator_TAG_OPERATOR_AND {
+				matches = matches && slugs[slugify(name)]
+			} else {
+				matches = matches || slugs[slugify(name)]
+			}
+		}
+		if matches && visible(f.products[id], req.IncludeArchived) {
+			res.Products = append(res.Products, proto.Clone(f.products[id]).(*pb.Product))
+		}
+	}
+	sort.Slice(res.Products, func(i, j int) bool {
+		a, _ := strconv.Atoi(res.Products[i].Id)
+		b, _ := strconv.Atoi(res.Products[j].Id)
+		return a < b
+	})
+	return res, nil
+}
+
+// slugify matches the products service: lowercase, words joined by hyphens.
+func slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// ListProductsByDateRange treats a product's updated_at as its creation time,
+// since the fake never updates products. Page tokens are plain ids.
+func (f *FakeProductService) ListProductsByDateRange(ctx context.Context, req *pb.ListProductsByDateRangeRequest) (*pb.ListProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.From != nil && req.To != nil && req.From.AsTime().After(req.To.AsTime()) {
+		return nil, status.Error(codes.InvalidArgument, "from must not be after to")
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 50
+	}
+	afterID := 0
+	if req.PageToken != "" {
+		var err error
+		if afterID, err = strconv.Atoi(req.PageToken); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []*pb.Product
+	for _, product := range f.products {
+		id, _ := strconv.Atoi(product.Id)
+		created := product.UpdatedAt.AsTime()
+		if id <= afterID || !visible(product, req.IncludeArchived) ||
+			(req.From != nil && created.Before(req.From.AsTime())) ||
+			(req.To != nil && !created.Before(req.To.AsTime())) {
+			continue
+		}
+		matched = append(matched, proto.Clone(product).(*pb.Product))
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, _ := strconv.Atoi(matched[i].Id)
+		b, _ := strconv.Atoi(matched[j].Id)
+		return a < b
+	})
+	res := &pb.ListProductsResponse{Products: matched}
+	if len(matched) > pageSize {
+		res.Products = matched[:pageSize]
+		res.NextPageToken = matched[pageSize-1].Id
+	}
+	return res, nil
+}
+
+func (f *FakeProductService) ArchiveProduct(ctx context.Context, req *pb.ArchiveProductRequest) (*pb.ProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	return f.setStatus(req.Id, pb.ProductStatus_PRODUCT_STATUS_ARCHIVED)
+}
+
+func (f *FakeProductService) UnarchiveProduct(ctx context.Context, req *pb.UnarchiveProductRequest) (*pb.ProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	return f.setStatus(req.Id, pb.ProductStatus_PRODUCT_STATUS_ACTIVE)
+}
+
+// visible reports whether listings and searches return product: only active,
+// approved products are, unless includeArchived is set.
+func visible(product *pb.Product, includeArchived bool) bool {
+	return listed(product, includeArchived, false)
+}
+
+// listed is visible for ListProducts, which can also return products that
+// have not been approved.
+func listed(product *pb.Product, includeArchived, includePending bool) bool {
+	return (includeArchived || product.Status == pb.ProductStatus_PRODUCT_STATUS_ACTIVE) &&
+		(includePending || product.ReviewStatus == pb.ReviewStatus_REVIEW_STATUS_APPROVED)
+}
+
+// BulkUpdateProductStatus keeps no status change log.
+func (f *FakeProductService) BulkUpdateProductStatus(ctx context.Context, req *pb.BulkUpdateProductStatusRequest) (*pb.BulkUpdateProductStatusResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if _, ok := pb.ProductStatus_name[int32(req.NewStatus)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status %v", req.NewStatus)
+	}
+	if len(req.ProductIds) == 0 || len(req.ProductIds) > 1000 {
+		return nil, status.Error(codes.InvalidArgument, "product_ids must have between 1 and 1000 ids")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.BulkUpdateProductStatusResponse{}
+	for _, id := range req.ProductIds {
+		product, ok := f.products[id]
+		switch {
+		case !ok:
+			res.FailedIds = append(res.FailedIds, id)
+		case product.Status != req.NewStatus:
+			product.Status = req.NewStatus
+			f.emit(pb.ProductEventType_PRODUCT_UPDATED, product)
+			res.UpdatedCount++
+		}
+	}
+	return res, nil
+}
+
+func (f *FakeProductService) setStatus(id string, productStatus pb.ProductStatus) (*pb.ProductResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", id)
+	}
+	product.Status = productStatus
+	f.emit(pb.ProductEventType_PRODUCT_UPDATED, product)
+	return &pb.ProductResponse{Product: proto.Clone(product).(*pb.Product)}, nil
+}
+
+// ListProducts pages through products by id. Page tokens are plain ids.
+func (f *FakeProductService) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 50
+	}
+	afterID := 0
+	if req.PageToken != "" {
+		var err error
+		if afterID, err = strconv.Atoi(req.PageToken); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []*pb.Product
+	for _, product := range f.products {
+		id, _ := strconv.Atoi(product.Id)
+		if id <= afterID || !listed(product, req.IncludeArchived, req.IncludePending) {
+			continue
+		}
+		matched = append(matched, proto.Clone(product).(*pb.Product))
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, _ := strconv.Atoi(matched[i].Id)
+		b, _ := strconv.Atoi(matched[j].Id)
+		return a < b
+	})
+	res := &pb.ListProductsResponse{Products: matched}
+	if len(matched) > pageSize {
+		res.Products = matched[:pageSize]
+		res.NextPageToken = matched[pageSize-1].Id
+	}
+	return res, nil
+}
+
+func (f *FakeProductService) UpsertProductEmbedding(ctx context.Context, req *pb.UpsertProductEmbeddingRequest) (*pb.UpsertProductEmbeddingResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if len(req.Vector) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "vector must have between 1 and 16000 dimensions")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	f.embeddings[req.ProductId] = append([]float32(nil), req.Vector...)
+	return &pb.UpsertProductEmbeddingResponse{}, nil
+}
+
+func (f *FakeProductService) GetSimilarProducts(ctx context.Context, req *pb.GetSimilarProductsRequest) (*pb.GetSimilarProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	target, ok := f.embeddings[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s has no embedding", req.ProductId)
+	}
+	res := &pb.GetSimilarProductsResponse{}
+	for id, embedding := range f.embeddings {
+		product := f.products[id]
+		if id == req.ProductId || len(embedding) != len(target) || !visible(product, false) {
+			continue
+		}
+		if similarity := cosineSimilarity(target, embedding); similarity >= req.MinSimilarity {
+			res.Products = append(res.Products, &pb.SimilarProduct{Product: proto.Clone(product).(*pb.Product), Similarity: similarity})
+		}
+	}
+	sort.Slice(res.Products, func(i, j int) bool { return res.Products[i].Similarity > res.Products[j].Similarity })
+	if len(res.Products) > limit {
+		res.Products = res.Products[:limit]
+	}
+	return res, nil
+}
+
+// GetTagSimilarProducts sees tag changes immediately and does not cache its
+// results, unlike the real service.
+func (f *FakeProductService) GetTagSimilarProducts(ctx context.Context, req *pb.GetTagSimilarProductsRequest) (*pb.GetTagSimilarProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	limit := int(req.Limit)
+	if limit == 0 || limit > 100 {
+		limit = 10
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	target := f.productTags[req.ProductId]
+	res := &pb.GetTagSimilarProductsResponse{}
+	for id, slugs := range f.productTags {
+		product := f.products[id]
+		if id == req.ProductId || !visible(product, false) {
+			continue
+		}
+		shared := 0
+		for slug := range slugs {
+			if target[slug] {
+				shared++
+			}
+		}
+		if shared > 0 {
+			score := float64(shared) / float64(len(slugs)+len(target)-shared)
+			res.Products = append(res.Products, &pb.ScoredProduct{Product: proto.Clone(product).(*pb.Product), JaccardScore: score})
+		}
+	}
+	sort.Slice(res.Products, func(i, j int) bool {
+		if res.Products[i].JaccardScore != res.Products[j].JaccardScore {
+			return res.Products[i].JaccardScore > res.Products[j].JaccardScore
+		}
+		a, _ := strconv.Atoi(res.Products[i].Product.Id)
+		b, _ := strconv.Atoi(res.Products[j].Product.Id)
+		return a < b
+	})
+	if len(res.Products) > limit {
+		res.Products = res.Products[:limit]
+	}
+	return res, nil
+}
+
+// FuzzySearchProducts scores names with trigramSimilarity, which follows
+// pg_trgm's similarity but not its word_similarity.
+func (f *FakeProductService) FuzzySearchProducts(ctx context.Context, req *pb.FuzzySearchProductsRequest) (*pb.FuzzySearchProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	threshold := math.Min(math.Max(req.SimilarityThreshold, 0.1), 1)
+	if req.SimilarityThreshold == 0 {
+		threshold = 0.3
+	}
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.FuzzySearchProductsResponse{}
+	for _, product := range f.products {
+		if !visible(product, req.IncludeArchived) {
+			continue
+		}
+		if similarity := trigramSimilarity(product.Name, query); similarity >= threshold {
+			res.Products = append(res.Products, &pb.ProductSearchResult{Product: proto.Clone(product).(*pb.Product), Similarity: similarity})
+		}
+	}
+	sort.Slice(res.Products, func(i, j int) bool { return res.Products[i].Similarity > res.Products[j].Similarity })
+	if len(res.Products) > limit {
+		res.Products = res.Products[:limit]
+	}
+	return res, nil
+}
+
+// trigramSimilarity is the share of trigrams a and b have in common, where
+// each lowercased word is padded with two spaces in front and one behind.
+func trigramSimilarity(a, b string) float64 {
+	trigrams := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			padded := []rune("  " + word + " ")
+			for i := 0; i+3 <= len(padded); i++ {
+				set[string(padded[i:i+3])] = true
+			}
+		}
+		return set
+	}
+	ta, tb := trigrams(a), trigrams(b)
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	if total := len(ta) + len(tb) - shared; total > 0 {
+		return float64(shared) / float64(total)
+	}
+	return 0
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func (f *FakeProductService) watch() chan *pb.ProductEvent {
+	events := make(chan *pb.ProductEvent, 64)
+	f.mu.Lock()
+	f.watchers[events] = struct{}{}
+	f.mu.Unlock()
+	return events
+}
+
+func (f *FakeProductService) unwatch(events chan *pb.ProductEvent) {
+	f.mu.Lock()
+	delete(f.watchers, events)
+	f.mu.Unlock()
+}
+
+func money(amount float64) *pb.Money {
+	return &pb.Money{CurrencyCode: currency, Amount: roundCents(amount)}
+}
+
+// GetAlternativeProducts does not cache its results, so price changes show
+// immediately.
+func (f *FakeProductService) GetAlternativeProducts(ctx context.Context, req *pb.GetAlternativeProductsRequest) (*pb.GetAlternativeProductsResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.CheaperCount < 0 || req.PricierCount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "cheaper_count and pricier_count must not be negative")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	target, ok := f.products[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	res := &pb.GetAlternativeProductsResponse{}
+	for _, product := range f.products {
+		if !visible(product, false) {
+			continue
+		}
+		switch {
+		case product.Price < target.Price:
+			res.Cheaper = append(res.Cheaper, proto.Clone(product).(*pb.Product))
+		case product.Price > target.Price:
+			res.Pricier = append(res.Pricier, proto.Clone(product).(*pb.Product))
+		}
+	}
+	res.Cheaper = nearestByPrice(res.Cheaper, target.Price, int(req.CheaperCount))
+	res.Pricier = nearestByPrice(res.Pricier, target.Price, int(req.PricierCount))
+	return res, nil
+}
+
+// nearestByPrice sorts products by distance from price, ties by id, and keeps
+// the first count of them, at most 100.
+func nearestByPrice(products []*pb.Product, price float64, count int) []*pb.Product {
+	sort.Slice(products, func(i, j int) bool {
+		a, b := math.Abs(products[i].Price-price), math.Abs(products[j].Price-price)
+		if a != b {
+			return a < b
+		}
+		x, _ := strconv.Atoi(products[i].Id)
+		y, _ := strconv.Atoi(products[j].Id)
+		return x < y
+	})
+	if count > 100 {
+		count = 100
+	}
+	if len(products) > count {
+		products = products[:count]
+	}
+	return products
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,51 @@
+package servicetest
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+func (f *FakeProductService) SetPurchaseLimit(ctx context.Context, req *pb.SetPurchaseLimitRequest) (*pb.SetPurchaseLimitResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.MaxQuantityPerUser < 1 {
+		return nil, status.Error(codes.InvalidArgument, "max_quantity_per_user must be positive")
+	}
+	if req.WindowHours < 1 || req.WindowHours > 365*24 {
+		return nil, status.Error(codes.InvalidArgument, "window_hours must be between 1 and 8760")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.products[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	limit := &pb.PurchaseLimit{
+		ProductId:          req.ProductId,
+		MaxQuantityPerUser: req.MaxQuantityPerUser,
+		WindowHours:        req.WindowHours,
+		UpdatedAt:          timestamppb.Now(),
+	}
+	f.purchaseLimits[req.ProductId] = limit
+	return &pb.SetPurchaseLimitResponse{Limit: proto.Clone(limit).(*pb.PurchaseLimit)}, nil
+}
+
+func (f *FakeProductService) DeletePurchaseLimit(ctx context.Context, req *pb.DeletePurchaseLimitRequest) (*pb.DeletePurchaseLimitResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.purchaseLimits[req.ProductId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s has no purchase limit", req.ProductId)
+	}
+	delete(f.purchaseLimits, req.ProductId)
+	return &pb.DeletePurchaseLimitResponse{}, nil
+}
@@ -0,0 +1,34 @@
+package servicetest
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// ReviewProduct does not check roles, as the fake has no authentication,
+// and sends no webhook.
+func (f *FakeProductService) ReviewProduct(ctx context.Context, req *pb.ReviewProductRequest) (*pb.ReviewProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if _, ok := pb.ReviewStatus_name[int32(req.Status)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported review status %v", req.Status)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	if product.ReviewStatus != req.Status {
+		product.ReviewStatus = req.Status
+		f.emit(pb.ProductEventType_PRODUCT_REVIEWED, product)
+	}
+	return &pb.ReviewProductResponse{Product: proto.Clone(product).(*pb.Product)}, nil
+}
@@ -0,0 +1,88 @@
+package servicetest
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// taxRule is a tax rule seeded with SeedTaxRule.
+type taxRule struct {
+	country, region, category string
+	rate                      float64
+	name                      string
+}
+
+// SeedTaxRule adds a tax rule like a row of the service's tax_rule_sets.
+// region may be empty for the whole country and category is a tag slug or
+// * for every product; rate is a fraction.
+func (f *FakeProductService) SeedTaxRule(country, region, category string, rate float64, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.taxRules = append(f.taxRules, taxRule{strings.ToUpper(country), strings.ToUpper(region), category, rate, name})
+}
+
+func jurisdiction(countryCode, regionCode string) (country, region string, err error) {
+	country = strings.ToUpper(strings.TrimSpace(countryCode))
+	if len(country) != 2 {
+		return "", "", status.Errorf(codes.InvalidArgument, "country_code %q is not an ISO 3166-1 alpha-2 code", countryCode)
+	}
+	return country, strings.ToUpper(strings.TrimSpace(regionCode)), nil
+}
+
+// taxRuleFor resolves rules like the service: region beats country and a
+// tag beats the wildcard. f.mu must be held.
+func (f *FakeProductService) taxRuleFor(productID, country, region string) taxRule {
+	best, bestScore := taxRule{name: "exempt"}, -1
+	for _, rule := range f.taxRules {
+		if rule.country != country || (rule.region != "" && rule.region != region) {
+			continue
+		}
+		score := 0
+		if rule.region != "" {
+			score += 2
+		}
+		if rule.category != "*" {
+			if !f.productTags[productID][rule.category] {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// CalculateTax prices the tax on a product with the seeded rules. Nothing is
+// cached.
+func (f *FakeProductService) CalculateTax(ctx context.Context, req *pb.CalculateTaxRequest) (*pb.CalculateTaxResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Quantity <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+	country, region, err := jurisdiction(req.CountryCode, req.RegionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.ProductId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+	}
+	rule := f.taxRuleFor(req.ProductId, country, region)
+	return &pb.CalculateTaxResponse{
+		TaxAmount: money(product.Price * float64(req.Quantity) * rule.rate),
+		TaxRate:   rule.rate,
+		TaxName:   rule.name,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package servicetest
+
+import (
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// GetUserTimeline only knows when the user was created, as the fake keeps no
+// audit log; it sends a single user.create event when that is in range.
+func (f *FakeUserService) GetUserTimeline(req *pb.GetUserTimelineRequest, stream pb.UserService_GetUserTimelineServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+	if req.From != nil && req.To != nil && req.To.AsTime().Before(req.From.AsTime()) {
+		return status.Error(codes.InvalidArgument, "to must not be before from")
+	}
+
+	f.mu.Lock()
+	user, ok := f.users[req.UserId]
+	createdAt := f.registeredAt[req.UserId]
+	f.mu.Unlock()
+	if !ok {
+		return status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	if req.From != nil && createdAt.Before(req.From.AsTime()) || req.To != nil && !createdAt.Before(req.To.AsTime()) {
+		return nil
+	}
+	if len(req.EventTypes) > 0 && !slices.Contains(req.EventTypes, "user.create") {
+		return nil
+	}
+	return stream.Send(&pb.TimelineEvent{
+		Timestamp:     timestamppb.New(createdAt),
+		EventType:     "user.create",
+		Summary:       "Account created",
+		Details:       map[string]string{"name": user.Name, "email": user.Email},
+		SourceService: "users-service",
+	})
+}
@@ -0,0 +1,111 @@
+package servicetest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type totpEnrollment struct {
+	secret   []byte
+	lastStep int64
+	// backupCodes holds the unused backup codes, normalized.
+	backupCodes map[string]bool
+}
+
+// TOTPCode returns the code an authenticator enrolled with secret, as
+// returned by EnrollTOTP, shows at t.
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+	return totpCode(key, t.Unix()/30), nil
+}
+
+func totpCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func normalizeBackupCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// EnrollTOTP keeps secrets and backup codes in the clear, and always uses
+// the issuer "web303".
+func (f *FakeUserService) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, ok := f.users[req.UserId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	enrollment := &totpEnrollment{secret: make([]byte, 20), backupCodes: make(map[string]bool)}
+	rand.Read(enrollment.secret)
+	res := &pb.EnrollTOTPResponse{Secret: totpEncoding.EncodeToString(enrollment.secret)}
+	res.QrCodeUri = "otpauth://totp/" + url.PathEscape("web303:"+user.Email) + "?" + url.Values{
+		"secret": {res.Secret}, "issuer": {"web303"}, "algorithm": {"SHA1"}, "digits": {"6"}, "period": {"30"},
+	}.Encode()
+	for i := 0; i < 10; i++ {
+		raw := make([]byte, 6)
+		rand.Read(raw)
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		res.BackupCodes = append(res.BackupCodes, code[:5]+"-"+code[5:])
+		enrollment.backupCodes[code] = true
+	}
+	f.totp[req.UserId] = enrollment
+	return res, nil
+}
+
+func (f *FakeUserService) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPRequest) (*pb.VerifyTOTPResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	enrollment, ok := f.totp[req.UserId]
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %s has not enrolled in TOTP", req.UserId)
+	}
+	code := strings.TrimSpace(req.Code)
+	if backupCode := normalizeBackupCode(code); enrollment.backupCodes[backupCode] {
+		delete(enrollment.backupCodes, backupCode)
+		return &pb.VerifyTOTPResponse{Valid: true}, nil
+	}
+	current := time.Now().Unix() / 30
+	for step := current - 1; step <= current+1; step++ {
+		if step > enrollment.lastStep && totpCode(enrollment.secret, step) == code {
+			enrollment.lastStep = step
+			return &pb.VerifyTOTPResponse{Valid: true}, nil
+		}
+	}
+	return &pb.VerifyTOTPResponse{}, nil
+}
@@ -0,0 +1,468 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// FakeUserService is an in-memory UserService.
+type FakeUserService struct {
+	// Embedding the unsafe interface rather than UnimplementedUserServiceServer
+	// makes the build fail when the proto gains a method the fake lacks.
+	pb.UnsafeUserServiceServer
+	faults
+
+	mu          sync.Mutex
+	nextID      int
+	users       map[string]*pb.User
+	preferences map[string]map[string]string
+	// socialAccounts maps a provider account to the id of its user.
+	socialAccounts map[socialAccount]string
+	// registeredAt holds when each user was created, for cohorts.
+	registeredAt map[string]time.Time
+	nextCohortID int
+	cohorts      map[string]*pb.Cohort
+	// addresses are keyed by address id.
+	nextAddressID int
+	addresses     map[string]*pb.UserAddress
+	// totp holds the TOTP enrollment of each enrolled user.
+	totp map[string]*totpEnrollment
+	// consents are in the order they were recorded.
+	consents []*pb.UserConsent
+	// communication holds users' communication preferences, and
+	// unsubscribeTokens what each generated token unsubscribes from.
+	communication     map[communicationKey]*pb.CommunicationPreference
+	unsubscribeTokens map[string]communicationKey
+}
+
+type socialAccount struct {
+	provider       string
+	providerUserID string
+}
+
+var _ pb.UserServiceServer = (*FakeUserService)(nil)
+
+func NewFakeUserService() *FakeUserService {
+	return &FakeUserService{
+		users:             make(map[string]*pb.User),
+		preferences:       make(map[string]map[string]string),
+		socialAccounts:    make(map[socialAccount]string),
+		registeredAt:      make(map[string]time.Time),
+		cohorts:           make(map[string]*pb.Cohort),
+		addresses:         make(map[string]*pb.UserAddress),
+		totp:              make(map[string]*totpEnrollment),
+		communication:     make(map[communicationKey]*pb.CommunicationPreference),
+		unsubscribeTokens: make(map[string]communicationKey),
+	}
+}
+
+// Seed adds users as if they had been created now. Users without an id are
+// given one.
+func (f *FakeUserService) Seed(users ...*pb.User) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, user := range users {
+		user = proto.Clone(user).(*pb.User)
+		if user.Id == "" {
+			user.Id = f.newID()
+		}
+		f.users[user.Id] = user
+		f.registeredAt[user.Id] = time.Now()
+	}
+}
+
+// SeedPreferences sets preferences for a user.
+func (f *FakeUserService) SeedPreferences(userID string, preferences map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.preferences[userID] == nil {
+		f.preferences[userID] = make(map[string]string)
+	}
+	for key, value := range preferences {
+		f.preferences[userID][key] = value
+	}
+}
+
+// SeedSocialAccount links a provider account to a user.
+func (f *FakeUserService) SeedSocialAccount(userID, provider, providerUserID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.socialAccounts[socialAccount{provider: provider, providerUserID: providerUserID}] = userID
+}
+
+func (f *FakeUserService) newID() string {
+	f.nextID++
+	return fmt.Sprint(f.nextID)
+}
+
+func (f *FakeUserService) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, user := range f.users {
+		if user.Email == req.Email {
+			return nil, status.Errorf(codes.AlreadyExists, "user with email %s already exists", req.Email)
+		}
+	}
+	user := &pb.User{Id: f.newID(), Name: req.Name, Email: req.Email}
+	f.users[user.Id] = user
+	f.registeredAt[user.Id] = time.Now()
+	return &pb.UserResponse{User: proto.Clone(user).(*pb.User)}, nil
+}
+
+func (f *FakeUserService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, ok := f.users[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.Id)
+	}
+	return &pb.UserResponse{User: proto.Clone(user).(*pb.User)}, nil
+}
+
+func (f *FakeUserService) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if len(req.Ids) > 200 {
+		return nil, status.Errorf(codes.InvalidArgument, "at most 200 ids may be requested at once, got %d", len(req.Ids))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &pb.BatchGetUsersResponse{}
+	seen := make(map[string]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, ok := f.users[id]; ok {
+			res.Users = append(res.Users, proto.Clone(user).(*pb.User))
+		} else {
+			res.MissingIds = append(res.MissingIds, id)
+		}
+	}
+	return res, nil
+}
+
+func (f *FakeUserService) SetPreference(ctx context.Context, req *pb.SetPreferenceRequest) (*pb.SetPreferenceResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	if f.preferences[req.UserId] == nil {
+		f.preferences[req.UserId] = make(map[string]string)
+	}
+	f.preferences[req.UserId][req.Key] = req.Value
+	return &pb.SetPreferenceResponse{}, nil
+}
+
+func (f *FakeUserService) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.GetPreferencesResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	preferences := make(map[string]string)
+	for key, value := range f.preferences[req.UserId] {
+		preferences[key] = value
+	}
+	if len(req.Keys) > 0 {
+		selected := make(map[string]string, len(req.Keys))
+		for _, key := range req.Keys {
+			if value, ok := preferences[key]; ok {
+				selected[key] = value
+			}
+		}
+		preferences = selected
+	}
+	return &pb.GetPreferencesResponse{Preferences: preferences}, nil
+}
+
+// ListUsers orders created_desc by id, since the fake does not track
+// creation times and ids only grow. Page tokens are plain offsets.
+func (f *FakeUserService) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.OrderBy != "" && req.OrderBy != "name_asc" && req.OrderBy != "created_desc" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported order_by %q: must be name_asc or created_desc", req.OrderBy)
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 50
+	}
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []*pb.User
+	prefix := strings.ToLower(req.NamePrefix)
+	for _, user := range f.users {
+		if strings.HasPrefix(strings.ToLower(user.Name), prefix) {
+			matched = append(matched, proto.Clone(user).(*pb.User))
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, _ := strconv.Atoi(matched[i].Id)
+		b, _ := strconv.Atoi(matched[j].Id)
+		switch {
+		case req.OrderBy == "name_asc" && matched[i].Name != matched[j].Name:
+			return matched[i].Name < matched[j].Name
+		case req.OrderBy == "created_desc":
+			return a > b
+		}
+		return a < b
+	})
+	res := &pb.ListUsersResponse{}
+	if offset < len(matched) {
+		matched = matched[offset:]
+	} else {
+		matched = nil
+	}
+	if len(matched) > pageSize {
+		matched = matched[:pageSize]
+		res.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	res.Users = matched
+	return res, nil
+}
+
+// LinkSocialAccount links the provider account whose id is the
+// authorization code, so no OAuth provider is needed.
+func (f *FakeUserService) LinkSocialAccount(ctx context.Context, req *pb.LinkSocialAccountRequest) (*pb.LinkSocialAccountResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.AuthorizationCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "authorization_code is required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	account := socialAccount{provider: req.Provider, providerUserID: req.AuthorizationCode}
+	for linked, userID := range f.socialAccounts {
+		if linked == account && userID != req.UserId {
+			return nil, status.Errorf(codes.AlreadyExists, "this %s account is linked to another user", req.Provider)
+		}
+		if linked.provider == req.Provider && linked != account && userID == req.UserId {
+			return nil, status.Errorf(codes.AlreadyExists, "user %s already has a %s account linked; unlink it first", req.UserId, req.Provider)
+		}
+	}
+	f.socialAccounts[account] = req.UserId
+	return &pb.LinkSocialAccountResponse{Linked: true}, nil
+}
+
+func (f *FakeUserService) UnlinkSocialAccount(ctx context.Context, req *pb.UnlinkSocialAccountRequest) (*pb.UnlinkSocialAccountResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[req.UserId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+	}
+	for account, userID := range f.socialAccounts {
+		if account.provider == req.Provider && userID == req.UserId {
+			delete(f.socialAccounts, account)
+			return &pb.UnlinkSocialAccountResponse{Unlinked: true}, nil
+		}
+	}
+	return &pb.UnlinkSocialAccountResponse{}, nil
+}
+
+func (f *FakeUserService) FindUserBySocialAccount(ctx context.Context, req *pb.FindUserBySocialAccountRequest) (*pb.UserResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userID, ok := f.socialAccounts[socialAccount{provider: req.Provider, providerUserID: req.ProviderUserId}]
+	user, found := f.users[userID]
+	if !ok || !found {
+		return nil, status.Errorf(codes.NotFound, "no user is linked to %s account %s", req.Provider, req.ProviderUserId)
+	}
+	return &pb.UserResponse{User: proto.Clone(user).(*pb.User)}, nil
+}
+
+// duplicateKey is the key FindDuplicateUsers groups users by. FUZZY_NAME
+// groups names that are equal ignoring case and surrounding space, as the fake
+// has no trigram matching.
+func duplicateKey(strategy pb.DuplicateStrategy, user *pb.User) string {
+	email := strings.ToLower(strings.TrimSpace(user.Email))
+	local, domain, _ := strings.Cut(email, "@")
+	switch strategy {
+	case pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE:
+		if domain == "gmail.com" || domain == "googlemail.com" {
+			return strings.ReplaceAll(local, ".", "") + "@gmail.com"
+		}
+		return email
+	case pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP:
+		local, _, _ = strings.Cut(local, "+")
+		return local + "@" + domain
+	default:
+		return strings.ToLower(strings.TrimSpace(user.Name))
+	}
+}
+
+// FindDuplicateUsers sends the groups ordered by their canonical user's id.
+func (f *FakeUserService) FindDuplicateUsers(req *pb.FindDuplicateUsersRequest, stream pb.UserService_FindDuplicateUsersServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+	if _, ok := pb.DuplicateStrategy_name[int32(req.Strategy)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unsupported strategy %v", req.Strategy)
+	}
+
+	f.mu.Lock()
+	users := make([]*pb.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, proto.Clone(user).(*pb.User))
+	}
+	f.mu.Unlock()
+	sort.Slice(users, func(i, j int) bool {
+		a, _ := strconv.Atoi(users[i].Id)
+		b, _ := strconv.Atoi(users[j].Id)
+		return a < b
+	})
+
+	groups := make(map[string]*pb.DuplicateUserGroup)
+	var order []*pb.DuplicateUserGroup
+	for _, user := range users {
+		key := duplicateKey(req.Strategy, user)
+		if key == "" || key == "@" {
+			continue
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &pb.DuplicateUserGroup{CanonicalUserId: user.Id, SimilarityReason: fmt.Sprintf("users match %s", key)}
+			groups[key] = group
+			order = append(order, group)
+			continue
+		}
+		group.DuplicateIds = append(group.DuplicateIds, user.Id)
+	}
+	for _, group := range order {
+		if len(group.DuplicateIds) == 0 {
+			continue
+		}
+		if err := stream.Send(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FakeUserService) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.CanonicalId == req.DuplicateId {
+		return nil, status.Error(codes.InvalidArgument, "canonical_id and duplicate_id must differ")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	canonical, ok := f.users[req.CanonicalId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.CanonicalId)
+	}
+	if _, ok := f.users[req.DuplicateId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", req.DuplicateId)
+	}
+	for account, userID := range f.socialAccounts {
+		if userID != req.DuplicateId {
+			continue
+		}
+		for other, otherID := range f.socialAccounts {
+			if otherID == req.CanonicalId && other.provider == account.provider {
+				return nil, status.Errorf(codes.FailedPrecondition, "both users have %s accounts linked; unlink one first", account.provider)
+			}
+		}
+	}
+
+	res := &pb.MergeUsersResponse{User: proto.Clone(canonical).(*pb.User)}
+	for account, userID := range f.socialAccounts {
+		if userID == req.DuplicateId {
+			f.socialAccounts[account] = req.CanonicalId
+			res.SocialAccountsMoved++
+		}
+	}
+	if f.preferences[req.CanonicalId] == nil {
+		f.preferences[req.CanonicalId] = make(map[string]string)
+	}
+	for key, value := range f.preferences[req.DuplicateId] {
+		if _, ok := f.preferences[req.CanonicalId][key]; !ok {
+			f.preferences[req.CanonicalId][key] = value
+			res.PreferencesMoved++
+		}
+	}
+	for _, address := range f.addresses {
+		if address.UserId == req.DuplicateId {
+			address.UserId = req.CanonicalId
+			address.IsDefault = false
+		}
+	}
+	for _, consent := range f.consents {
+		if consent.UserId == req.DuplicateId {
+			consent.UserId = req.CanonicalId
+		}
+	}
+	for key, pref := range f.communication {
+		if key.userID != req.DuplicateId {
+			continue
+		}
+		delete(f.communication, key)
+		into := communicationKey{req.CanonicalId, key.channel, key.kind}
+		if existing, ok := f.communication[into]; ok {
+			existing.Subscribed = existing.Subscribed && pref.Subscribed
+		} else {
+			pref.UserId = req.CanonicalId
+			f.communication[into] = pref
+		}
+	}
+	delete(f.totp, req.DuplicateId)
+	delete(f.preferences, req.DuplicateId)
+	delete(f.users, req.DuplicateId)
+	delete(f.registeredAt, req.DuplicateId)
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "api-gateway/proto/gen/proto"
+)
+
+// recordVersion snapshots product as version. f.mu must be held.
+func (f *FakeProductService) recordVersion(product *pb.Product, version string) {
+	snapshot, _ := protojson.Marshal(product)
+	f.nextVersionID++
+	f.versions[product.Id] = append(f.versions[product.Id], &pb.ProductVersion{
+		Id:           fmt.Sprint(f.nextVersionID),
+		ProductId:    product.Id,
+		Version:      version,
+		Name:         product.Name,
+		Price:        &pb.Money{CurrencyCode: currency, Amount: product.Price},
+		SnapshotJson: string(snapshot),
+		CreatedAt:    timestamppb.Now(),
+		CreatedBy:    "anonymous",
+	})
+}
+
+// nextPatch returns version with its patch number incremented.
+func nextPatch(version string) string {
+	parts := strings.Split(version, ".")
+	patch, _ := strconv.Atoi(parts[2])
+	return fmt.Sprintf("%s.%s.%d", parts[0], parts[1], patch+1)
+}
+
+// UpdateProduct versions products the way the service does: seeded products
+// have no versions until their first change, which records their seeded
+// state as 1.0.0.
+func (f *FakeProductService) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.ProductResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	product, ok := f.products[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %s not found", req.Id)
+	}
+	updated := proto.Clone(product).(*pb.Product)
+	if req.Name != nil {
+		updated.Name = *req.Name
+	}
+	if req.Price != nil {
+		updated.Price = *req.Price
+	}
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+	if proto.Equal(updated, product) {
+		return &pb.ProductResponse{Product: proto.Clone(product).(*pb.Product)}, nil
+	}
+
+	if len(f.versions[product.Id]) == 0 {
+		f.recordVersion(product, "1.0.0")
+	}
+	versions := f.versions[product.Id]
+	updated.UpdatedAt = timestamppb.Now()
+	f.products[product.Id] = updated
+	f.recordVersion(updated, nextPatch(versions[len(versions)-1].Version))
+	f.emit(pb.ProductEventType_PRODUCT_UPDATED, updated)
+	return &pb.ProductResponse{Product: proto.Clone(updated).(*pb.Product)}, nil
+}
+
+func (f *FakeProductService) GetProductVersion(ctx context.Context, req *pb.GetProductVersionRequest) (*pb.ProductVersionResponse, error) {
+	if err := f.before(ctx, req); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions := f.versions[req.ProductId]
+	if req.Version == "latest" && len(versions) > 0 {
+		return &pb.ProductVersionResponse{Version: proto.Clone(versions[len(versions)-1]).(*pb.ProductVersion)}, nil
+	}
+	for _, version := range versions {
+		if version.Version == strings.TrimPrefix(req.Version, "v") {
+			return &pb.ProductVersionResponse{Version: proto.Clone(version).(*pb.ProductVersion)}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "version %s of product %s not found", req.Version, req.ProductId)
+}
+
+func (f *FakeProductService) ListProductVersions(req *pb.ListProductVersionsRequest, stream pb.ProductService_ListProductVersionsServer) error {
+	if err := f.before(stream.Context(), req); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	versions := make([]*pb.ProductVersion, 0, len(f.versions[req.ProductId]))
+	for i := len(f.versions[req.ProductId]) - 1; i >= 0; i-- {
+		versions = append(versions, proto.Clone(f.versions[req.ProductId][i]).(*pb.ProductVersion))
+	}
+	f.mu.Unlock()
+	for _, version := range versions {
+		if err := stream.Send(&pb.ProductVersionResponse{Version: version}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
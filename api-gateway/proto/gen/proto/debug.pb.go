@@ -0,0 +1,427 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v3.21.12
+// source: proto/debug.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetDebugInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDebugInfoRequest) Reset() {
+	*x = GetDebugInfoRequest{}
+	mi := &file_proto_debug_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDebugInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDebugInfoRequest) ProtoMessage() {}
+
+func (x *GetDebugInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_debug_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDebugInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetDebugInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_debug_proto_rawDescGZIP(), []int{0}
+}
+
+type DBPoolStats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	MaxOpenConnections int32                  `protobuf:"varint,1,opt,name=max_open_connections,json=maxOpenConnections,proto3" json:"max_open_connections,omitempty"`
+	OpenConnections    int32                  `protobuf:"varint,2,opt,name=open_connections,json=openConnections,proto3" json:"open_connections,omitempty"`
+	InUse              int32                  `protobuf:"varint,3,opt,name=in_use,json=inUse,proto3" json:"in_use,omitempty"`
+	Idle               int32                  `protobuf:"varint,4,opt,name=idle,proto3" json:"idle,omitempty"`
+	WaitCount          int64                  `protobuf:"varint,5,opt,name=wait_count,json=waitCount,proto3" json:"wait_count,omitempty"`
+	WaitDuration       *durationpb.Duration   `protobuf:"bytes,6,opt,name=wait_duration,json=waitDuration,proto3" json:"wait_duration,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DBPoolStats) Reset() {
+	*x = DBPoolStats{}
+	mi := &file_proto_debug_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DBPoolStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DBPoolStats) ProtoMessage() {}
+
+func (x *DBPoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_debug_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DBPoolStats.ProtoReflect.Descriptor instead.
+func (*DBPoolStats) Descriptor() ([]byte, []int) {
+	return file_proto_debug_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DBPoolStats) GetMaxOpenConnections() int32 {
+	if x != nil {
+		return x.MaxOpenConnections
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetOpenConnections() int32 {
+	if x != nil {
+		return x.OpenConnections
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetInUse() int32 {
+	if x != nil {
+		return x.InUse
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetIdle() int32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetWaitCount() int64 {
+	if x != nil {
+		return x.WaitCount
+	}
+	return 0
+}
+
+func (x *DBPoolStats) GetWaitDuration() *durationpb.Duration {
+	if x != nil {
+		return x.WaitDuration
+	}
+	return nil
+}
+
+type GetDebugInfoResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	GoroutineCount   int32                  `protobuf:"varint,1,opt,name=goroutine_count,json=goroutineCount,proto3" json:"goroutine_count,omitempty"`
+	MemoryAllocMb    float64                `protobuf:"fixed64,2,opt,name=memory_alloc_mb,json=memoryAllocMb,proto3" json:"memory_alloc_mb,omitempty"`
+	DbPoolStats      *DBPoolStats           `protobuf:"bytes,3,opt,name=db_pool_stats,json=dbPoolStats,proto3" json:"db_pool_stats,omitempty"`
+	ConsulRegistered bool                   `protobuf:"varint,4,opt,name=consul_registered,json=consulRegistered,proto3" json:"consul_registered,omitempty"`
+	FeatureFlags     map[string]string      `protobuf:"bytes,5,rep,name=feature_flags,json=featureFlags,proto3" json:"feature_flags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	UptimeSeconds    int64                  `protobuf:"varint,6,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	GoVersion        string                 `protobuf:"bytes,7,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetDebugInfoResponse) Reset() {
+	*x = GetDebugInfoResponse{}
+	mi := &file_proto_debug_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDebugInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDebugInfoResponse) ProtoMessage() {}
+
+func (x *GetDebugInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_debug_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDebugInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetDebugInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_debug_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetDebugInfoResponse) GetGoroutineCount() int32 {
+	if x != nil {
+		return x.GoroutineCount
+	}
+	return 0
+}
+
+func (x *GetDebugInfoResponse) GetMemoryAllocMb() float64 {
+	if x != nil {
+		return x.MemoryAllocMb
+	}
+	return 0
+}
+
+func (x *GetDebugInfoResponse) GetDbPoolStats() *DBPoolStats {
+	if x != nil {
+		return x.DbPoolStats
+	}
+	return nil
+}
+
+func (x *GetDebugInfoResponse) GetConsulRegistered() bool {
+	if x != nil {
+		return x.ConsulRegistered
+	}
+	return false
+}
+
+func (x *GetDebugInfoResponse) GetFeatureFlags() map[string]string {
+	if x != nil {
+		return x.FeatureFlags
+	}
+	return nil
+}
+
+func (x *GetDebugInfoResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *GetDebugInfoResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+type GetDeploymentInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeploymentInfoRequest) Reset() {
+	*x = GetDeploymentInfoRequest{}
+	mi := &file_proto_debug_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeploymentInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeploymentInfoRequest) ProtoMessage() {}
+
+func (x *GetDeploymentInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_debug_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeploymentInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetDeploymentInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_debug_proto_rawDescGZIP(), []int{3}
+}
+
+type GetDeploymentInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsCanary      bool                   `protobuf:"varint,1,opt,name=is_canary,json=isCanary,proto3" json:"is_canary,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeploymentInfoResponse) Reset() {
+	*x = GetDeploymentInfoResponse{}
+	mi := &file_proto_debug_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeploymentInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeploymentInfoResponse) ProtoMessage() {}
+
+func (x *GetDeploymentInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_debug_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeploymentInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetDeploymentInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_debug_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDeploymentInfoResponse) GetIsCanary() bool {
+	if x != nil {
+		return x.IsCanary
+	}
+	return false
+}
+
+func (x *GetDeploymentInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetDeploymentInfoResponse) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+var File_proto_debug_proto protoreflect.FileDescriptor
+
+const file_proto_debug_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/debug.proto\x12\x05debug\x1a\x1egoogle/protobuf/duration.proto\"\x15\n" +
+	"\x13GetDebugInfoRequest\"\xf4\x01\n" +
+	"\vDBPoolStats\x120\n" +
+	"\x14max_open_connections\x18\x01 \x01(\x05R\x12maxOpenConnections\x12)\n" +
+	"\x10open_connections\x18\x02 \x01(\x05R\x0fopenConnections\x12\x15\n" +
+	"\x06in_use\x18\x03 \x01(\x05R\x05inUse\x12\x12\n" +
+	"\x04idle\x18\x04 \x01(\x05R\x04idle\x12\x1d\n" +
+	"\n" +
+	"wait_count\x18\x05 \x01(\x03R\twaitCount\x12>\n" +
+	"\rwait_duration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\fwaitDuration\"\xa7\x03\n" +
+	"\x14GetDebugInfoResponse\x12'\n" +
+	"\x0fgoroutine_count\x18\x01 \x01(\x05R\x0egoroutineCount\x12&\n" +
+	"\x0fmemory_alloc_mb\x18\x02 \x01(\x01R\rmemoryAllocMb\x126\n" +
+	"\rdb_pool_stats\x18\x03 \x01(\v2\x12.debug.DBPoolStatsR\vdbPoolStats\x12+\n" +
+	"\x11consul_registered\x18\x04 \x01(\bR\x10consulRegistered\x12R\n" +
+	"\rfeature_flags\x18\x05 \x03(\v2-.debug.GetDebugInfoResponse.FeatureFlagsEntryR\ffeatureFlags\x12%\n" +
+	"\x0euptime_seconds\x18\x06 \x01(\x03R\ruptimeSeconds\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\a \x01(\tR\tgoVersion\x1a?\n" +
+	"\x11FeatureFlagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1a\n" +
+	"\x18GetDeploymentInfoRequest\"j\n" +
+	"\x19GetDeploymentInfoResponse\x12\x1b\n" +
+	"\tis_canary\x18\x01 \x01(\bR\bisCanary\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight2\xaf\x01\n" +
+	"\fDebugService\x12G\n" +
+	"\fGetDebugInfo\x12\x1a.debug.GetDebugInfoRequest\x1a\x1b.debug.GetDebugInfoResponse\x12V\n" +
+	"\x11GetDeploymentInfo\x12\x1f.debug.GetDeploymentInfoRequest\x1a .debug.GetDeploymentInfoResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+
+var (
+	file_proto_debug_proto_rawDescOnce sync.Once
+	file_proto_debug_proto_rawDescData []byte
+)
+
+func file_proto_debug_proto_rawDescGZIP() []byte {
+	file_proto_debug_proto_rawDescOnce.Do(func() {
+		file_proto_debug_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_debug_proto_rawDesc), len(file_proto_debug_proto_rawDesc)))
+	})
+	return file_proto_debug_proto_rawDescData
+}
+
+var file_proto_debug_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_debug_proto_goTypes = []any{
+	(*GetDebugInfoRequest)(nil),       // 0: debug.GetDebugInfoRequest
+	(*DBPoolStats)(nil),               // 1: debug.DBPoolStats
+	(*GetDebugInfoResponse)(nil),      // 2: debug.GetDebugInfoResponse
+	(*GetDeploymentInfoRequest)(nil),  // 3: debug.GetDeploymentInfoRequest
+	(*GetDeploymentInfoResponse)(nil), // 4: debug.GetDeploymentInfoResponse
+	nil,                               // 5: debug.GetDebugInfoResponse.FeatureFlagsEntry
+	(*durationpb.Duration)(nil),       // 6: google.protobuf.Duration
+}
+var file_proto_debug_proto_depIdxs = []int32{
+	6, // 0: debug.DBPoolStats.wait_duration:type_name -> google.protobuf.Duration
+	1, // 1: debug.GetDebugInfoResponse.db_pool_stats:type_name -> debug.DBPoolStats
+	5, // 2: debug.GetDebugInfoResponse.feature_flags:type_name -> debug.GetDebugInfoResponse.FeatureFlagsEntry
+	0, // 3: debug.DebugService.GetDebugInfo:input_type -> debug.GetDebugInfoRequest
+	3, // 4: debug.DebugService.GetDeploymentInfo:input_type -> debug.GetDeploymentInfoRequest
+	2, // 5: debug.DebugService.GetDebugInfo:output_type -> debug.GetDebugInfoResponse
+	4, // 6: debug.DebugService.GetDeploymentInfo:output_type -> debug.GetDeploymentInfoResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_proto_debug_proto_init() }
+func file_proto_debug_proto_init() {
+	if File_proto_debug_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_debug_proto_rawDesc), len(file_proto_debug_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_debug_proto_goTypes,
+		DependencyIndexes: file_proto_debug_proto_depIdxs,
+		MessageInfos:      file_proto_debug_proto_msgTypes,
+	}.Build()
+	File_proto_debug_proto = out.File
+	file_proto_debug_proto_goTypes = nil
+	file_proto_debug_proto_depIdxs = nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultRouteTimeout applies to routes without an entry in GATEWAY_TIMEOUTS.
+const defaultRouteTimeout = 1 * time.Second
+
+// routeTimeouts holds the per-route backend deadlines configured through
+// GATEWAY_TIMEOUTS, e.g. "purchase=2s,default=1s".
+type routeTimeouts struct {
+	routes   map[string]time.Duration
+	fallback time.Duration
+}
+
+var timeouts = &routeTimeouts{routes: map[string]time.Duration{}, fallback: defaultRouteTimeout}
+
+func parseRouteTimeouts(spec string) (*routeTimeouts, error) {
+	t := &routeTimeouts{routes: make(map[string]time.Duration), fallback: defaultRouteTimeout}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid GATEWAY_TIMEOUTS entry %q", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout for route %q: %q", route, value)
+		}
+		route = strings.TrimSpace(route)
+		if route == "default" {
+			t.fallback = d
+		} else {
+			t.routes[route] = d
+		}
+	}
+	return t, nil
+}
+
+func (t *routeTimeouts) forRoute(route string) time.Duration {
+	if d, ok := t.routes[route]; ok {
+		return d
+	}
+	return t.fallback
+}
+
+// requestBudget tracks the time a request has been allowed for its backend
+// calls. The deadline travels to the backends as grpc-timeout.
+type requestBudget struct {
+	route   string
+	started time.Time
+	timeout time.Duration
+}
+
+func newRequestBudget(r *http.Request, route string) (context.Context, context.CancelFunc, *requestBudget) {
+	b := &requestBudget{route: route, started: time.Now(), timeout: timeouts.forRoute(route)}
+	ctx, cancel := context.WithTimeout(r.Context(), b.timeout)
+	return ctx, cancel, b
+}
+
+// timedOut writes a 504 naming the dependency if err is a deadline error, and
+// reports whether it did.
+func (b *requestBudget) timedOut(w http.ResponseWriter, dependency string, err error) bool {
+	if status.Code(err) != codes.DeadlineExceeded && !errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	elapsed := time.Since(b.started)
+	log.Printf("Route %s timed out waiting for %s after %v", b.route, dependency, elapsed)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":      "backend timed out",
+		"dependency": dependency,
+		"elapsed_ms": elapsed.Milliseconds(),
+		"budget_ms":  b.timeout.Milliseconds(),
+	})
+	return true
+}
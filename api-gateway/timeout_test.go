@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+
+	pb "api-gateway/proto/gen/proto"
+	sharedgrpc "shared/grpc"
+)
+
+// slowUserService answers nothing until the caller gives up, and reports the
+// error of the context it was handed.
+type slowUserService struct {
+	pb.UnimplementedUserServiceServer
+	done chan error
+}
+
+func (s *slowUserService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
+	select {
+	case <-ctx.Done():
+		s.done <- ctx.Err()
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		s.done <- nil
+		return &pb.UserResponse{User: &pb.User{Id: req.Id}}, nil
+	}
+}
+
+// useUsersBackend serves backend on a local port and points the gateway's
+// users-service connection at it.
+func useUsersBackend(t *testing.T, backend pb.UserServiceServer) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterUserServiceServer(srv, backend)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	discovery := &ServiceDiscovery{}
+	discovery.pool = sharedgrpc.New(1, 0, append(discovery.dialOptions(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "tcp", lis.Addr().String())
+		}),
+	)...)
+	t.Cleanup(func() { discovery.pool.Close() })
+
+	saved := sd
+	sd = discovery
+	t.Cleanup(func() { sd = saved })
+}
+
+func TestParseRouteTimeouts(t *testing.T) {
+	got, err := parseRouteTimeouts(" purchase=2s, default=300ms ,users=150ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for route, want := range map[string]time.Duration{"purchase": 2 * time.Second, "users": 150 * time.Millisecond, "cart": 300 * time.Millisecond} {
+		if d := got.forRoute(route); d != want {
+			t.Errorf("forRoute(%q) = %v, want %v", route, d, want)
+		}
+	}
+
+	for _, spec := range []string{"purchase", "purchase=soon", "purchase=-1s", "purchase=0s"} {
+		if _, err := parseRouteTimeouts(spec); err == nil {
+			t.Errorf("parseRouteTimeouts(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestRouteTimeoutCancelsSlowBackend(t *testing.T) {
+	backend := &slowUserService{done: make(chan error, 1)}
+	useUsersBackend(t, backend)
+
+	saved := timeouts
+	t.Cleanup(func() { timeouts = saved })
+	var err error
+	if timeouts, err = parseRouteTimeouts("users=200ms"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/users/1", nil), map[string]string{"id": "1"})
+	rec := httptest.NewRecorder()
+	started := time.Now()
+	getUserHandler(rec, req)
+	elapsed := time.Since(started)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want 504: %s", rec.Code, rec.Body)
+	}
+	if elapsed > time.Second {
+		t.Errorf("handler returned after %v, want about 200ms", elapsed)
+	}
+	var body struct {
+		Error      string `json:"error"`
+		Dependency string `json:"dependency"`
+		ElapsedMs  int64  `json:"elapsed_ms"`
+		BudgetMs   int64  `json:"budget_ms"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error != "backend timed out" || body.Dependency != "users-service" || body.BudgetMs != 200 || body.ElapsedMs < 200 {
+		t.Errorf("body = %+v, want a users-service timeout after a 200ms budget", body)
+	}
+
+	// The deadline must reach the backend, so it stops working on a request
+	// nobody is waiting for.
+	select {
+	case err := <-backend.done:
+		if err == nil {
+			t.Error("backend finished the call; its context was never cancelled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("backend still running 2s after the gateway gave up")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"shared/tlsconfig"
+)
+
+// clientCredentials returns the credentials the gateway dials the services
+// with. When TLS_CA_FILE is set, which it must be once the services set
+// TLS_CERT_FILE, connections use TLS and verify the services' certificates
+// against that CA. They are limited by TLS_MIN_VERSION, TLS_CIPHER_SUITES
+// and TLS_CURVE_PREFERENCES as the services' servers are, so both ends
+// agree. A certificate must name the service, e.g. users-service, since
+// that is the authority the gateway dials. Without a CA the gateway dials
+// in plaintext.
+func clientCredentials() (credentials.TransportCredentials, error) {
+	caFile := os.Getenv("TLS_CA_FILE")
+	if caFile == "" {
+		log.Println("TLS_CA_FILE not set, dialing services without TLS")
+		return insecure.NewCredentials(), nil
+	}
+	config, err := tlsconfig.BuildTLSConfig(tlsconfig.FromEnv())
+	if err != nil {
+		return nil, err
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificates: %w", err)
+	}
+	config.RootCAs = x509.NewCertPool()
+	if !config.RootCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return credentials.NewTLS(config), nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"shared/tlsconfig"
+)
+
+// serveTLS runs a gRPC server with the services' TLS settings and a
+// self-signed certificate for name, and writes the certificate to a file
+// for TLS_CA_FILE. It returns the server's address and the file.
+func serveTLS(t *testing.T, name string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := tlsconfig.BuildTLSConfig(tlsconfig.FromEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Certificates = []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(config)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), caFile
+}
+
+// checkHealth calls the server at addr as the gateway would call
+// users-service.
+func checkHealth(t *testing.T, addr string) error {
+	t.Helper()
+	creds, err := clientCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd := &ServiceDiscovery{creds: creds}
+	conn, err := grpc.Dial("passthrough:///"+addr, append(sd.dialOptions(), grpc.WithAuthority("users-service"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestClientTLS(t *testing.T) {
+	addr, caFile := serveTLS(t, "users-service")
+	t.Setenv("TLS_CA_FILE", caFile)
+	if err := checkHealth(t, addr); err != nil {
+		t.Errorf("call over TLS: %v", err)
+	}
+}
+
+func TestClientTLSWithTLS13Minimum(t *testing.T) {
+	// Both ends read TLS_MIN_VERSION, so raising it keeps them agreeing.
+	t.Setenv("TLS_MIN_VERSION", tlsconfig.TLS13)
+	addr, caFile := serveTLS(t, "users-service")
+	t.Setenv("TLS_CA_FILE", caFile)
+	if err := checkHealth(t, addr); err != nil {
+		t.Errorf("call over TLS 1.3: %v", err)
+	}
+}
+
+func TestClientTLSRejected(t *testing.T) {
+	t.Run("plaintext", func(t *testing.T) {
+		addr, _ := serveTLS(t, "users-service")
+		t.Setenv("TLS_CA_FILE", "")
+		if err := checkHealth(t, addr); status.Code(err) != codes.Unavailable {
+			t.Errorf("plaintext call to a TLS server: err = %v, want Unavailable", err)
+		}
+	})
+	t.Run("certificate for another service", func(t *testing.T) {
+		addr, caFile := serveTLS(t, "products-service")
+		t.Setenv("TLS_CA_FILE", caFile)
+		if err := checkHealth(t, addr); status.Code(err) != codes.Unavailable {
+			t.Errorf("err = %v, want Unavailable", err)
+		}
+	})
+}
+
+func TestClientCredentialsErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, caFile := range map[string]string{"missing file": filepath.Join(dir, "missing.pem"), "no certificates": empty} {
+		t.Setenv("TLS_CA_FILE", caFile)
+		if _, err := clientCredentials(); err == nil {
+			t.Errorf("%s: clientCredentials succeeded", name)
+		}
+	}
+
+	t.Setenv("TLS_CA_FILE", empty)
+	t.Setenv("TLS_MIN_VERSION", "TLS11")
+	if _, err := clientCredentials(); err == nil {
+		t.Error("TLS_MIN_VERSION=TLS11 accepted")
+	}
+}
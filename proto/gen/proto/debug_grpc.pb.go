@@ -0,0 +1,159 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/debug.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DebugService_GetDebugInfo_FullMethodName      = "/debug.DebugService/GetDebugInfo"
+	DebugService_GetDeploymentInfo_FullMethodName = "/debug.DebugService/GetDeploymentInfo"
+)
+
+// DebugServiceClient is the client API for DebugService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DebugServiceClient interface {
+	GetDebugInfo(ctx context.Context, in *GetDebugInfoRequest, opts ...grpc.CallOption) (*GetDebugInfoResponse, error)
+	GetDeploymentInfo(ctx context.Context, in *GetDeploymentInfoRequest, opts ...grpc.CallOption) (*GetDeploymentInfoResponse, error)
+}
+
+type debugServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDebugServiceClient(cc grpc.ClientConnInterface) DebugServiceClient {
+	return &debugServiceClient{cc}
+}
+
+func (c *debugServiceClient) GetDebugInfo(ctx context.Context, in *GetDebugInfoRequest, opts ...grpc.CallOption) (*GetDebugInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDebugInfoResponse)
+	err := c.cc.Invoke(ctx, DebugService_GetDebugInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugServiceClient) GetDeploymentInfo(ctx context.Context, in *GetDeploymentInfoRequest, opts ...grpc.CallOption) (*GetDeploymentInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeploymentInfoResponse)
+	err := c.cc.Invoke(ctx, DebugService_GetDeploymentInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DebugServiceServer is the server API for DebugService service.
+// All implementations must embed UnimplementedDebugServiceServer
+// for forward compatibility.
+type DebugServiceServer interface {
+	GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error)
+	GetDeploymentInfo(context.Context, *GetDeploymentInfoRequest) (*GetDeploymentInfoResponse, error)
+	mustEmbedUnimplementedDebugServiceServer()
+}
+
+// UnimplementedDebugServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDebugServiceServer struct{}
+
+func (UnimplementedDebugServiceServer) GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDebugInfo not implemented")
+}
+func (UnimplementedDebugServiceServer) GetDeploymentInfo(context.Context, *GetDeploymentInfoRequest) (*GetDeploymentInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeploymentInfo not implemented")
+}
+func (UnimplementedDebugServiceServer) mustEmbedUnimplementedDebugServiceServer() {}
+func (UnimplementedDebugServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeDebugServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DebugServiceServer will
+// result in compilation errors.
+type UnsafeDebugServiceServer interface {
+	mustEmbedUnimplementedDebugServiceServer()
+}
+
+func RegisterDebugServiceServer(s grpc.ServiceRegistrar, srv DebugServiceServer) {
+	// If the following call panics, it indicates UnimplementedDebugServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DebugService_ServiceDesc, srv)
+}
+
+func _DebugService_GetDebugInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDebugInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).GetDebugInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebugService_GetDebugInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).GetDebugInfo(ctx, req.(*GetDebugInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebugService_GetDeploymentInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeploymentInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServiceServer).GetDeploymentInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DebugService_GetDeploymentInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServiceServer).GetDeploymentInfo(ctx, req.(*GetDeploymentInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DebugService_ServiceDesc is the grpc.ServiceDesc for DebugService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DebugService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "debug.DebugService",
+	HandlerType: (*DebugServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDebugInfo",
+			Handler:    _DebugService_GetDebugInfo_Handler,
+		},
+		{
+			MethodName: "GetDeploymentInfo",
+			Handler:    _DebugService_GetDeploymentInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/debug.proto",
+}
@@ -0,0 +1,263 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v3.21.12
+// source: proto/selftest.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SelfTestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTestRequest) Reset() {
+	*x = SelfTestRequest{}
+	mi := &file_proto_selftest_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTestRequest) ProtoMessage() {}
+
+func (x *SelfTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_selftest_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTestRequest.ProtoReflect.Descriptor instead.
+func (*SelfTestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_selftest_proto_rawDescGZIP(), []int{0}
+}
+
+type SelfTestCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ok            bool                   `protobuf:"varint,2,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Latency       *durationpb.Duration   `protobuf:"bytes,4,opt,name=latency,proto3" json:"latency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTestCheck) Reset() {
+	*x = SelfTestCheck{}
+	mi := &file_proto_selftest_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTestCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTestCheck) ProtoMessage() {}
+
+func (x *SelfTestCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_selftest_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTestCheck.ProtoReflect.Descriptor instead.
+func (*SelfTestCheck) Descriptor() ([]byte, []int) {
+	return file_proto_selftest_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SelfTestCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SelfTestCheck) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *SelfTestCheck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SelfTestCheck) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+type SelfTestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Checks        []*SelfTestCheck       `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTestResponse) Reset() {
+	*x = SelfTestResponse{}
+	mi := &file_proto_selftest_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTestResponse) ProtoMessage() {}
+
+func (x *SelfTestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_selftest_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTestResponse.ProtoReflect.Descriptor instead.
+func (*SelfTestResponse) Descriptor() ([]byte, []int) {
+	return file_proto_selftest_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SelfTestResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *SelfTestResponse) GetChecks() []*SelfTestCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *SelfTestResponse) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+var File_proto_selftest_proto protoreflect.FileDescriptor
+
+const file_proto_selftest_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/selftest.proto\x12\bselftest\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x11\n" +
+	"\x0fSelfTestRequest\"~\n" +
+	"\rSelfTestCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02ok\x18\x02 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x123\n" +
+	"\alatency\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\alatency\"\x92\x01\n" +
+	"\x10SelfTestResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12/\n" +
+	"\x06checks\x18\x02 \x03(\v2\x17.selftest.SelfTestCheckR\x06checks\x12=\n" +
+	"\fcompleted_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt2T\n" +
+	"\x0fSelfTestService\x12A\n" +
+	"\bSelfTest\x12\x19.selftest.SelfTestRequest\x1a\x1a.selftest.SelfTestResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+
+var (
+	file_proto_selftest_proto_rawDescOnce sync.Once
+	file_proto_selftest_proto_rawDescData []byte
+)
+
+func file_proto_selftest_proto_rawDescGZIP() []byte {
+	file_proto_selftest_proto_rawDescOnce.Do(func() {
+		file_proto_selftest_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_selftest_proto_rawDesc), len(file_proto_selftest_proto_rawDesc)))
+	})
+	return file_proto_selftest_proto_rawDescData
+}
+
+var file_proto_selftest_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_selftest_proto_goTypes = []any{
+	(*SelfTestRequest)(nil),       // 0: selftest.SelfTestRequest
+	(*SelfTestCheck)(nil),         // 1: selftest.SelfTestCheck
+	(*SelfTestResponse)(nil),      // 2: selftest.SelfTestResponse
+	(*durationpb.Duration)(nil),   // 3: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_proto_selftest_proto_depIdxs = []int32{
+	3, // 0: selftest.SelfTestCheck.latency:type_name -> google.protobuf.Duration
+	1, // 1: selftest.SelfTestResponse.checks:type_name -> selftest.SelfTestCheck
+	4, // 2: selftest.SelfTestResponse.completed_at:type_name -> google.protobuf.Timestamp
+	0, // 3: selftest.SelfTestService.SelfTest:input_type -> selftest.SelfTestRequest
+	2, // 4: selftest.SelfTestService.SelfTest:output_type -> selftest.SelfTestResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_proto_selftest_proto_init() }
+func file_proto_selftest_proto_init() {
+	if File_proto_selftest_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_selftest_proto_rawDesc), len(file_proto_selftest_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_selftest_proto_goTypes,
+		DependencyIndexes: file_proto_selftest_proto_depIdxs,
+		MessageInfos:      file_proto_selftest_proto_msgTypes,
+	}.Build()
+	File_proto_selftest_proto = out.File
+	file_proto_selftest_proto_goTypes = nil
+	file_proto_selftest_proto_depIdxs = nil
+}
@@ -0,0 +1,121 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/selftest.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SelfTestService_SelfTest_FullMethodName = "/selftest.SelfTestService/SelfTest"
+)
+
+// SelfTestServiceClient is the client API for SelfTestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SelfTestServiceClient interface {
+	SelfTest(ctx context.Context, in *SelfTestRequest, opts ...grpc.CallOption) (*SelfTestResponse, error)
+}
+
+type selfTestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSelfTestServiceClient(cc grpc.ClientConnInterface) SelfTestServiceClient {
+	return &selfTestServiceClient{cc}
+}
+
+func (c *selfTestServiceClient) SelfTest(ctx context.Context, in *SelfTestRequest, opts ...grpc.CallOption) (*SelfTestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelfTestResponse)
+	err := c.cc.Invoke(ctx, SelfTestService_SelfTest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SelfTestServiceServer is the server API for SelfTestService service.
+// All implementations must embed UnimplementedSelfTestServiceServer
+// for forward compatibility.
+type SelfTestServiceServer interface {
+	SelfTest(context.Context, *SelfTestRequest) (*SelfTestResponse, error)
+	mustEmbedUnimplementedSelfTestServiceServer()
+}
+
+// UnimplementedSelfTestServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSelfTestServiceServer struct{}
+
+func (UnimplementedSelfTestServiceServer) SelfTest(context.Context, *SelfTestRequest) (*SelfTestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelfTest not implemented")
+}
+func (UnimplementedSelfTestServiceServer) mustEmbedUnimplementedSelfTestServiceServer() {}
+func (UnimplementedSelfTestServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeSelfTestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SelfTestServiceServer will
+// result in compilation errors.
+type UnsafeSelfTestServiceServer interface {
+	mustEmbedUnimplementedSelfTestServiceServer()
+}
+
+func RegisterSelfTestServiceServer(s grpc.ServiceRegistrar, srv SelfTestServiceServer) {
+	// If the following call panics, it indicates UnimplementedSelfTestServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SelfTestService_ServiceDesc, srv)
+}
+
+func _SelfTestService_SelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelfTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SelfTestServiceServer).SelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SelfTestService_SelfTest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SelfTestServiceServer).SelfTest(ctx, req.(*SelfTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SelfTestService_ServiceDesc is the grpc.ServiceDesc for SelfTestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SelfTestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "selftest.SelfTestService",
+	HandlerType: (*SelfTestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SelfTest",
+			Handler:    _SelfTestService_SelfTest_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/selftest.proto",
+}
@@ -0,0 +1,365 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v3.21.12
+// source: proto/transaction.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BeginTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTransactionRequest) Reset() {
+	*x = BeginTransactionRequest{}
+	mi := &file_proto_transaction_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTransactionRequest) ProtoMessage() {}
+
+func (x *BeginTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTransactionRequest.ProtoReflect.Descriptor instead.
+func (*BeginTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BeginTransactionRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type BeginTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTransactionResponse) Reset() {
+	*x = BeginTransactionResponse{}
+	mi := &file_proto_transaction_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTransactionResponse) ProtoMessage() {}
+
+func (x *BeginTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTransactionResponse.ProtoReflect.Descriptor instead.
+func (*BeginTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BeginTransactionResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *BeginTransactionResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type CommitTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitTransactionRequest) Reset() {
+	*x = CommitTransactionRequest{}
+	mi := &file_proto_transaction_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitTransactionRequest) ProtoMessage() {}
+
+func (x *CommitTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CommitTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommitTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type CommitTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitTransactionResponse) Reset() {
+	*x = CommitTransactionResponse{}
+	mi := &file_proto_transaction_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitTransactionResponse) ProtoMessage() {}
+
+func (x *CommitTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitTransactionResponse.ProtoReflect.Descriptor instead.
+func (*CommitTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{3}
+}
+
+type RollbackTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackTransactionRequest) Reset() {
+	*x = RollbackTransactionRequest{}
+	mi := &file_proto_transaction_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackTransactionRequest) ProtoMessage() {}
+
+func (x *RollbackTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackTransactionRequest.ProtoReflect.Descriptor instead.
+func (*RollbackTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RollbackTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type RollbackTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackTransactionResponse) Reset() {
+	*x = RollbackTransactionResponse{}
+	mi := &file_proto_transaction_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackTransactionResponse) ProtoMessage() {}
+
+func (x *RollbackTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transaction_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackTransactionResponse.ProtoReflect.Descriptor instead.
+func (*RollbackTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_transaction_proto_rawDescGZIP(), []int{5}
+}
+
+var File_proto_transaction_proto protoreflect.FileDescriptor
+
+const file_proto_transaction_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/transaction.proto\x12\vtransaction\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"N\n" +
+	"\x17BeginTransactionRequest\x123\n" +
+	"\atimeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"|\n" +
+	"\x18BeginTransactionResponse\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"A\n" +
+	"\x18CommitTransactionRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\"\x1b\n" +
+	"\x19CommitTransactionResponse\"C\n" +
+	"\x1aRollbackTransactionRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\"\x1d\n" +
+	"\x1bRollbackTransactionResponse2\xc3\x02\n" +
+	"\x12TransactionService\x12_\n" +
+	"\x10BeginTransaction\x12$.transaction.BeginTransactionRequest\x1a%.transaction.BeginTransactionResponse\x12b\n" +
+	"\x11CommitTransaction\x12%.transaction.CommitTransactionRequest\x1a&.transaction.CommitTransactionResponse\x12h\n" +
+	"\x13RollbackTransaction\x12'.transaction.RollbackTransactionRequest\x1a(.transaction.RollbackTransactionResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+
+var (
+	file_proto_transaction_proto_rawDescOnce sync.Once
+	file_proto_transaction_proto_rawDescData []byte
+)
+
+func file_proto_transaction_proto_rawDescGZIP() []byte {
+	file_proto_transaction_proto_rawDescOnce.Do(func() {
+		file_proto_transaction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_transaction_proto_rawDesc), len(file_proto_transaction_proto_rawDesc)))
+	})
+	return file_proto_transaction_proto_rawDescData
+}
+
+var file_proto_transaction_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_transaction_proto_goTypes = []any{
+	(*BeginTransactionRequest)(nil),     // 0: transaction.BeginTransactionRequest
+	(*BeginTransactionResponse)(nil),    // 1: transaction.BeginTransactionResponse
+	(*CommitTransactionRequest)(nil),    // 2: transaction.CommitTransactionRequest
+	(*CommitTransactionResponse)(nil),   // 3: transaction.CommitTransactionResponse
+	(*RollbackTransactionRequest)(nil),  // 4: transaction.RollbackTransactionRequest
+	(*RollbackTransactionResponse)(nil), // 5: transaction.RollbackTransactionResponse
+	(*durationpb.Duration)(nil),         // 6: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),       // 7: google.protobuf.Timestamp
+}
+var file_proto_transaction_proto_depIdxs = []int32{
+	6, // 0: transaction.BeginTransactionRequest.timeout:type_name -> google.protobuf.Duration
+	7, // 1: transaction.BeginTransactionResponse.expires_at:type_name -> google.protobuf.Timestamp
+	0, // 2: transaction.TransactionService.BeginTransaction:input_type -> transaction.BeginTransactionRequest
+	2, // 3: transaction.TransactionService.CommitTransaction:input_type -> transaction.CommitTransactionRequest
+	4, // 4: transaction.TransactionService.RollbackTransaction:input_type -> transaction.RollbackTransactionRequest
+	1, // 5: transaction.TransactionService.BeginTransaction:output_type -> transaction.BeginTransactionResponse
+	3, // 6: transaction.TransactionService.CommitTransaction:output_type -> transaction.CommitTransactionResponse
+	5, // 7: transaction.TransactionService.RollbackTransaction:output_type -> transaction.RollbackTransactionResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_transaction_proto_init() }
+func file_proto_transaction_proto_init() {
+	if File_proto_transaction_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_transaction_proto_rawDesc), len(file_proto_transaction_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_transaction_proto_goTypes,
+		DependencyIndexes: file_proto_transaction_proto_depIdxs,
+		MessageInfos:      file_proto_transaction_proto_msgTypes,
+	}.Build()
+	File_proto_transaction_proto = out.File
+	file_proto_transaction_proto_goTypes = nil
+	file_proto_transaction_proto_depIdxs = nil
+}
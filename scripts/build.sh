@@ -25,7 +25,7 @@ generate_proto_files() {
     # Generate Go code
     protoc --go_out=./proto/gen --go_opt=paths=source_relative \
            --go-grpc_out=./proto/gen --go-grpc_opt=paths=source_relative \
-           proto/*.proto
+           proto/*.proto proto/v2/*.proto
 
     echo "✅ Proto files generated"
 }
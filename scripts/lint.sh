@@ -0,0 +1,16 @@
+#!/bin/bash
+set -e
+
+# Run the custom static checks over every Go module, for use in CI.
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+GORMSPRINTF="$ROOT/bin/gormsprintf"
+
+echo "🔧 Building gormsprintf..."
+(cd "$ROOT/tools/gormsprintf" && go build -o "$GORMSPRINTF" .)
+
+for service in api-gateway services/users-service services/products-service shared; do
+    echo "🔍 Linting $service..."
+    (cd "$ROOT/$service" && "$GORMSPRINTF" ./...)
+done
+
+echo "✅ Lint passed"
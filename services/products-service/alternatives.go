@@ -0,0 +1,95 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    maxAlternativeProducts = 100
+    // alternativesCacheTTL is how long GetAlternativeProducts serves a
+    // result before asking the database again, so price changes can take
+    // that long to show.
+    alternativesCacheTTL = time.Minute
+)
+
+// GetAlternativeProducts returns the active products priced nearest below
+// and above the given product, nearest first, for suggesting alternatives.
+// Fewer are returned when there are not enough.
+func (s *server) GetAlternativeProducts(ctx context.Context, req *pb.GetAlternativeProductsRequest) (*pb.GetAlternativeProductsResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    if req.CheaperCount < 0 || req.PricierCount < 0 {
+        return nil, status.Error(codes.InvalidArgument, "cheaper_count and pricier_count must not be negative")
+    }
+    cheaperCount, pricierCount := int(req.CheaperCount), int(req.PricierCount)
+    if cheaperCount > maxAlternativeProducts {
+        cheaperCount = maxAlternativeProducts
+    }
+    if pricierCount > maxAlternativeProducts {
+        pricierCount = maxAlternativeProducts
+    }
+
+    cacheKey := fmt.Sprintf("alternatives:%d:%d:%d", productID, cheaperCount, pricierCount)
+    if data, ok := s.alternatives.Get(ctx, cacheKey); ok {
+        var res pb.GetAlternativeProductsResponse
+        if err := proto.Unmarshal(data, &res); err == nil {
+            return &res, nil
+        }
+    }
+
+    var product Product
+    if err := s.db.WithContext(ctx).First(&product, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return nil, err
+    }
+
+    res := &pb.GetAlternativeProductsResponse{}
+    if res.Cheaper, err = s.productsByPrice(ctx, "price < ?", "price DESC, id", product.Price, cheaperCount); err != nil {
+        return nil, err
+    }
+    if res.Pricier, err = s.productsByPrice(ctx, "price > ?", "price, id", product.Price, pricierCount); err != nil {
+        return nil, err
+    }
+    if data, err := proto.Marshal(res); err == nil {
+        s.alternatives.Set(ctx, cacheKey, data, alternativesCacheTTL)
+    }
+    return res, nil
+}
+
+// productsByPrice returns up to limit active products whose price matches
+// condition, in the given order.
+func (s *server) productsByPrice(ctx context.Context, condition, order string, price float64, limit int) ([]*pb.Product, error) {
+    if limit == 0 {
+        return nil, nil
+    }
+    var products []Product
+    err := s.db.WithContext(ctx).
+        Where(condition, price).
+        Where("status = ? AND review_status = ?", productStatusActive, reviewStatusApproved).
+        Order(order).
+        Limit(limit).
+        Find(&products).Error
+    if err != nil {
+        return nil, err
+    }
+    res := make([]*pb.Product, len(products))
+    for i := range products {
+        res[i] = products[i].toProto()
+    }
+    return res, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// pricedRows returns active products with the given names and prices, with
+// ids counting up from 1.
+func pricedRows(products ...interface{}) *sqlmock.Rows {
+    rows := sqlmock.NewRows([]string{"id", "name", "price", "status"})
+    for i := 0; i < len(products); i += 2 {
+        rows.AddRow(i/2+1, products[i], products[i+1], productStatusActive)
+    }
+    return rows
+}
+
+func productNames(products []*pb.Product) []string {
+    var names []string
+    for _, p := range products {
+        names = append(names, p.Name)
+    }
+    return names
+}
+
+func TestGetAlternativeProductsRejectsBadRequests(t *testing.T) {
+    s := &server{}
+    for _, req := range []*pb.GetAlternativeProductsRequest{
+        {ProductId: "mug", CheaperCount: 1},
+        {ProductId: "1", CheaperCount: -1},
+        {ProductId: "1", PricierCount: -1},
+    } {
+        if _, err := s.GetAlternativeProducts(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("GetAlternativeProducts(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestGetAlternativeProductsReturnsFewerWhenThereAreNotEnough(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, alternatives: NewMemoryCache(time.Hour)}
+    ctx := context.Background()
+
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1`).WithArgs(7).
+        WillReturnRows(pricedRows("Mug", 12.5))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE price < \$1 AND \(status = \$2 AND review_status = \$3\) .* ORDER BY price DESC, id LIMIT 3`).
+        WithArgs(12.5, productStatusActive, reviewStatusApproved).
+        WillReturnRows(pricedRows("Cup", 9.0))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE price > \$1 AND \(status = \$2 AND review_status = \$3\) .* ORDER BY price, id LIMIT 2`).
+        WithArgs(12.5, productStatusActive, reviewStatusApproved).
+        WillReturnRows(pricedRows())
+
+    req := &pb.GetAlternativeProductsRequest{ProductId: "7", CheaperCount: 3, PricierCount: 2}
+    res, err := s.GetAlternativeProducts(ctx, req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := productNames(res.Cheaper); !slices.Equal(got, []string{"Cup"}) || len(res.Pricier) != 0 {
+        t.Errorf("got cheaper %v and pricier %v, want only Cup", got, productNames(res.Pricier))
+    }
+
+    // The same request is answered from the cache, without queries.
+    cached, err := s.GetAlternativeProducts(ctx, req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := productNames(cached.Cheaper); !slices.Equal(got, []string{"Cup"}) {
+        t.Errorf("cached response has cheaper %v, want Cup", got)
+    }
+}
+
+func TestGetAlternativeProductsSkipsZeroCountsAndCapsLargeOnes(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, alternatives: NewMemoryCache(time.Hour)}
+
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1`).WithArgs(7).
+        WillReturnRows(pricedRows("Mug", 12.5))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE price > \$1 .* LIMIT 100`).
+        WillReturnRows(pricedRows("Teapot", 30.0, "Kettle", 40.0))
+
+    res, err := s.GetAlternativeProducts(context.Background(), &pb.GetAlternativeProductsRequest{ProductId: "7", PricierCount: 1000})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Cheaper != nil || !slices.Equal(productNames(res.Pricier), []string{"Teapot", "Kettle"}) {
+        t.Errorf("got cheaper %v and pricier %v", productNames(res.Cheaper), productNames(res.Pricier))
+    }
+}
+
+func TestGetAlternativeProductsOfMissingProduct(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(pricedRows())
+    _, err := (&server{db: db, alternatives: NewMemoryCache(time.Hour)}).GetAlternativeProducts(context.Background(), &pb.GetAlternativeProductsRequest{ProductId: "9", CheaperCount: 1})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("alternatives of a missing product = %v, want NotFound", err)
+    }
+}
+
+func TestGetAlternativeProductsWithDatabase(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db, alternatives: NewMemoryCache(time.Hour)}
+    ids := make(map[string]string)
+    for _, p := range []struct {
+        name   string
+        price  float64
+        status string
+    }{
+        {"Spoon", 2, productStatusActive},
+        {"Cup", 9, productStatusActive},
+        {"Old Cup", 11, productStatusArchived},
+        {"Mug", 12.5, productStatusActive},
+        {"Twin Mug", 12.5, productStatusActive},
+        {"Teapot", 30, productStatusActive},
+    } {
+        product := Product{Name: p.name, Price: p.price, Status: p.status}
+        if err := db.Create(&product).Error; err != nil {
+            t.Fatal(err)
+        }
+        ids[p.name] = product.toProto().Id
+    }
+
+    for _, tt := range []struct {
+        product                    string
+        cheaperCount, pricierCount int32
+        cheaper, pricier           []string
+    }{
+        // Archived products and products at the same price are left out.
+        {"Mug", 5, 5, []string{"Cup", "Spoon"}, []string{"Teapot"}},
+        {"Mug", 1, 0, []string{"Cup"}, nil},
+        {"Spoon", 2, 2, nil, []string{"Cup", "Mug"}},
+        {"Teapot", 3, 3, []string{"Mug", "Twin Mug", "Cup"}, nil},
+    } {
+        res, err := s.GetAlternativeProducts(context.Background(), &pb.GetAlternativeProductsRequest{
+            ProductId: ids[tt.product], CheaperCount: tt.cheaperCount, PricierCount: tt.pricierCount,
+        })
+        if err != nil {
+            t.Fatal(err)
+        }
+        if !slices.Equal(productNames(res.Cheaper), tt.cheaper) || !slices.Equal(productNames(res.Pricier), tt.pricier) {
+            t.Errorf("%s (%d, %d): got %v and %v, want %v and %v", tt.product, tt.cheaperCount, tt.pricierCount,
+                productNames(res.Cheaper), productNames(res.Pricier), tt.cheaper, tt.pricier)
+        }
+    }
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// Values of Product.Status. Only active products are listed or searched by
+// default; inactive ones are taken down temporarily, archived ones for good.
+const (
+    productStatusActive   = "active"
+    productStatusInactive = "inactive"
+    productStatusArchived = "archived"
+)
+
+var productStatuses = map[string]pb.ProductStatus{
+    productStatusActive:   pb.ProductStatus_PRODUCT_STATUS_ACTIVE,
+    productStatusInactive: pb.ProductStatus_PRODUCT_STATUS_INACTIVE,
+    productStatusArchived: pb.ProductStatus_PRODUCT_STATUS_ARCHIVED,
+}
+
+// maxBulkStatusProducts caps the product_ids of one BulkUpdateProductStatus.
+const maxBulkStatusProducts = 1000
+
+// StatusChangeLog records a change of a product's status, and why it was
+// made.
+type StatusChangeLog struct {
+    ID        uint      `gorm:"primaryKey"`
+    ProductID uint      `gorm:"not null;index"`
+    OldStatus string    `gorm:"type:varchar(16);not null"`
+    NewStatus string    `gorm:"type:varchar(16);not null"`
+    Reason    string    `gorm:"type:text;not null;default:''"`
+    ChangedBy string    `gorm:"not null"`
+    ChangedAt time.Time `gorm:"not null"`
+}
+
+// productStatusName returns the Product.Status value for a ProductStatus.
+func productStatusName(productStatus pb.ProductStatus) (string, bool) {
+    for name, value := range productStatuses {
+        if value == productStatus {
+            return name, true
+        }
+    }
+    return "", false
+}
+
+// visibleProducts leaves out products that are not active, unless
+// includeArchived is set.
+func visibleProducts(query *gorm.DB, includeArchived bool) *gorm.DB {
+    if includeArchived {
+        return query
+    }
+    return query.Where("status = ?", productStatusActive)
+}
+
+// logStatusChanges records the change of each product to newStatus in
+// status_change_logs, and in the audit log.
+func logStatusChanges(ctx context.Context, tx *gorm.DB, products []Product, newStatus, reason string) error {
+    if len(products) == 0 {
+        return nil
+    }
+    now := time.Now()
+    logs := make([]StatusChangeLog, len(products))
+    for i, product := range products {
+        logs[i] = StatusChangeLog{
+            ProductID: product.ID,
+            OldStatus: product.Status,
+            NewStatus: newStatus,
+            Reason:    reason,
+            ChangedBy: actorFromContext(ctx),
+            ChangedAt: now,
+        }
+        if err := recordAudit(ctx, tx, "update", "product", product.ID, map[string]string{"status": newStatus}); err != nil {
+            return err
+        }
+    }
+    return tx.Create(&logs).Error
+}
+
+// ArchiveProduct hides a product from ListProducts. Unlike deletion it keeps
+// the product readable by id and can be undone with UnarchiveProduct.
+func (s *server) ArchiveProduct(ctx context.Context, req *pb.ArchiveProductRequest) (*pb.ProductResponse, error) {
+    return s.setProductStatus(ctx, req.Id, productStatusArchived)
+}
+
+func (s *server) UnarchiveProduct(ctx context.Context, req *pb.UnarchiveProductRequest) (*pb.ProductResponse, error) {
+    return s.setProductStatus(ctx, req.Id, productStatusActive)
+}
+
+func (s *server) setProductStatus(ctx context.Context, id, productStatus string) (*pb.ProductResponse, error) {
+    productID, err := strconv.ParseUint(id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", id)
+    }
+    var product Product
+    err = s.inRequestTransaction(ctx, func(tx *gorm.DB) error {
+        var current Product
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").Where("id = ?", productID).Take(&current).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", id)
+            }
+            return err
+        }
+        if current.Status != productStatus {
+            if err := tx.Model(&Product{}).Where("id = ?", productID).Update("status", productStatus).Error; err != nil {
+                return err
+            }
+            if err := logStatusChanges(ctx, tx, []Product{current}, productStatus, ""); err != nil {
+                return err
+            }
+        }
+        if err := tx.First(&product, productID).Error; err != nil {
+            return err
+        }
+        if current.Status == productStatus {
+            return nil
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.ProductResponse{Product: product.toProto()}, nil
+}
+
+// ListProducts pages through products by id, leaving out inactive and
+// archived ones unless include_archived is set, and ones that have not been
+// approved unless include_pending is set by a moderator.
+func (s *server) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+    p, err := s.parsePage(req.PageSize, req.PageToken)
+    if err != nil {
+        return nil, err
+    }
+    if req.IncludePending && !mayReview(ctx) {
+        return nil, status.Error(codes.PermissionDenied, "include_pending requires the moderator role")
+    }
+    return p.list(ctx, reviewedProducts(visibleProducts(s.db.WithContext(ctx), req.IncludeArchived), req.IncludePending))
+}
+
+// BulkUpdateProductStatus sets the status of many products in one UPDATE,
+// logging each change with the reason given. Ids that are invalid or name no
+// product are returned as failed_ids; products already in new_status are
+// left alone and not counted.
+func (s *server) BulkUpdateProductStatus(ctx context.Context, req *pb.BulkUpdateProductStatusRequest) (*pb.BulkUpdateProductStatusResponse, error) {
+    newStatus, ok := productStatusName(req.NewStatus)
+    if !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unknown status %v", req.NewStatus)
+    }
+    if len(req.ProductIds) == 0 || len(req.ProductIds) > maxBulkStatusProducts {
+        return nil, status.Errorf(codes.InvalidArgument, "product_ids must have between 1 and %d ids", maxBulkStatusProducts)
+    }
+
+    var invalid []string
+    requested := make(map[uint]string, len(req.ProductIds))
+    ids := make([]uint, 0, len(req.ProductIds))
+    for _, id := range req.ProductIds {
+        productID, err := strconv.ParseUint(id, 10, 64)
+        if err != nil {
+            invalid = append(invalid, id)
+            continue
+        }
+        if _, seen := requested[uint(productID)]; !seen {
+            requested[uint(productID)] = id
+            ids = append(ids, uint(productID))
+        }
+    }
+
+    res := &pb.BulkUpdateProductStatusResponse{}
+    err := s.inRequestTransaction(ctx, func(tx *gorm.DB) error {
+        res.UpdatedCount, res.FailedIds = 0, invalid
+        if len(ids) == 0 {
+            return nil
+        }
+        var current []Product
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").Where("id IN ?", ids).Order("id").Find(&current).Error; err != nil {
+            return err
+        }
+        found := make(map[uint]bool, len(current))
+        var changing []Product
+        var changingIDs []uint
+        for _, product := range current {
+            found[product.ID] = true
+            if product.Status != newStatus {
+                changing = append(changing, product)
+                changingIDs = append(changingIDs, product.ID)
+            }
+        }
+        for _, id := range ids {
+            if !found[id] {
+                res.FailedIds = append(res.FailedIds, requested[id])
+            }
+        }
+        if len(changing) == 0 {
+            return nil
+        }
+
+        result := tx.Model(&Product{}).Where("id IN ?", changingIDs).Update("status", newStatus)
+        if result.Error != nil {
+            return result.Error
+        }
+        res.UpdatedCount = int32(result.RowsAffected)
+        if err := logStatusChanges(ctx, tx, changing, newStatus, req.Reason); err != nil {
+            return err
+        }
+        var updated []Product
+        if err := tx.Where("id IN ?", changingIDs).Order("id").Find(&updated).Error; err != nil {
+            return err
+        }
+        for i := range updated {
+            if err := recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &updated[i]); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return res, nil
+}
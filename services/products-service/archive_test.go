@@ -0,0 +1,294 @@
+package main
+
+import (
+    "context"
+    "slices"
+    "strconv"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+func statusRow(id uint, name, productStatus string) *sqlmock.Rows {
+    now := time.Now()
+    return sqlmock.NewRows([]string{"id", "name", "price", "status", "created_at", "updated_at"}).
+        AddRow(id, name, 12.5, productStatus, now, now)
+}
+
+func lockedStatusRows(products ...interface{}) *sqlmock.Rows {
+    rows := sqlmock.NewRows([]string{"id", "status"})
+    for i := 0; i < len(products); i += 2 {
+        rows.AddRow(products[i], products[i+1])
+    }
+    return rows
+}
+
+func TestArchiveProductWritesOutboxEvent(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // The audit entry, the status change log, and the outbox event that
+    // tells watchers and caches are written in the same transaction as the
+    // update.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id","status" FROM "products" WHERE id = \$1 .* FOR UPDATE`).WithArgs(42).
+        WillReturnRows(lockedStatusRows(42, "active"))
+    mock.ExpectExec(`UPDATE "products" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+        WithArgs("archived", sqlmock.AnyArg(), 42).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    expectAudit(mock, "update", "product")
+    mock.ExpectQuery(`INSERT INTO "status_change_logs"`).
+        WithArgs(42, "active", "archived", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(statusRow(42, "Mug", "archived"))
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int32(pb.ProductEventType_PRODUCT_UPDATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.ArchiveProduct(context.Background(), &pb.ArchiveProductRequest{Id: "42"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Product.Status != pb.ProductStatus_PRODUCT_STATUS_ARCHIVED {
+        t.Errorf("archived product has status %v", res.Product.Status)
+    }
+}
+
+func TestArchiveArchivedProductChangesNothing(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id","status" FROM "products"`).WillReturnRows(lockedStatusRows(42, "archived"))
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(statusRow(42, "Mug", "archived"))
+    mock.ExpectCommit()
+
+    if _, err := (&server{db: db}).ArchiveProduct(context.Background(), &pb.ArchiveProductRequest{Id: "42"}); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUnarchiveMissingProduct(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id","status" FROM "products"`).WithArgs(9).WillReturnRows(lockedStatusRows())
+    mock.ExpectRollback()
+
+    _, err := (&server{db: db}).UnarchiveProduct(context.Background(), &pb.UnarchiveProductRequest{Id: "9"})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("UnarchiveProduct(9) = %v, want NotFound", err)
+    }
+}
+
+func TestBulkUpdateProductStatusRejectsBadRequests(t *testing.T) {
+    s := &server{}
+    tooMany := make([]string, maxBulkStatusProducts+1)
+    for i := range tooMany {
+        tooMany[i] = strconv.Itoa(i + 1)
+    }
+    for name, req := range map[string]*pb.BulkUpdateProductStatusRequest{
+        "no ids":     {NewStatus: pb.ProductStatus_PRODUCT_STATUS_ARCHIVED},
+        "too many":   {NewStatus: pb.ProductStatus_PRODUCT_STATUS_ARCHIVED, ProductIds: tooMany},
+        "bad status": {NewStatus: pb.ProductStatus(42), ProductIds: []string{"1"}},
+    } {
+        if _, err := s.BulkUpdateProductStatus(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: BulkUpdateProductStatus = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestBulkUpdateProductStatus(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // Product 2 is already inactive and 3 does not exist, so only 1 and 4
+    // change, in one UPDATE, each with an audit entry, a log row and an
+    // outbox event.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id","status" FROM "products" WHERE id IN \(\$1,\$2,\$3,\$4\) .* FOR UPDATE`).
+        WithArgs(1, 2, 3, 4).
+        WillReturnRows(lockedStatusRows(1, "active", 2, "inactive", 4, "archived"))
+    mock.ExpectExec(`UPDATE "products" SET "status"=\$1,"updated_at"=\$2 WHERE id IN \(\$3,\$4\)`).
+        WithArgs("inactive", sqlmock.AnyArg(), 1, 4).
+        WillReturnResult(sqlmock.NewResult(0, 2))
+    expectAudit(mock, "update", "product")
+    expectAudit(mock, "update", "product")
+    mock.ExpectQuery(`INSERT INTO "status_change_logs"`).
+        WithArgs(1, "active", "inactive", "recalled", sqlmock.AnyArg(), sqlmock.AnyArg(),
+            4, "archived", "inactive", "recalled", sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE id IN \(\$1,\$2\)`).WithArgs(1, 4).
+        WillReturnRows(statusRow(1, "Mug", "inactive").AddRow(4, "Kettle", 40, "inactive", time.Now(), time.Now()))
+    for i := 0; i < 2; i++ {
+        mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+            WithArgs(int32(pb.ProductEventType_PRODUCT_UPDATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+            WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    }
+    mock.ExpectCommit()
+
+    res, err := s.BulkUpdateProductStatus(context.Background(), &pb.BulkUpdateProductStatusRequest{
+        ProductIds: []string{"1", "2", "mug", "3", "4", "1"},
+        NewStatus:  pb.ProductStatus_PRODUCT_STATUS_INACTIVE,
+        Reason:     "recalled",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.UpdatedCount != 2 || !slices.Equal(res.FailedIds, []string{"mug", "3"}) {
+        t.Errorf("got %d updated and failed ids %v, want 2 and [mug 3]", res.UpdatedCount, res.FailedIds)
+    }
+}
+
+func TestBulkUpdateProductStatusWithOnlyInvalidIDs(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectCommit()
+    res, err := (&server{db: db}).BulkUpdateProductStatus(context.Background(), &pb.BulkUpdateProductStatusRequest{
+        ProductIds: []string{"mug"},
+        NewStatus:  pb.ProductStatus_PRODUCT_STATUS_ARCHIVED,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.UpdatedCount != 0 || !slices.Equal(res.FailedIds, []string{"mug"}) {
+        t.Errorf("got %d updated and failed ids %v", res.UpdatedCount, res.FailedIds)
+    }
+}
+
+func TestListProductsHidesArchived(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    ctx := context.Background()
+
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE status = \$1 AND review_status = \$2 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 11`).
+        WithArgs("active", "approved").
+        WillReturnRows(statusRow(1, "Mug", "active"))
+    if _, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 10}); err != nil {
+        t.Fatal(err)
+    }
+
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE review_status = \$1 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 11`).
+        WithArgs("approved").
+        WillReturnRows(statusRow(1, "Mug", "active").AddRow(2, "Kettle", 40, "archived", time.Now(), time.Now()))
+    res, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 10, IncludeArchived: true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Products) != 2 || res.Products[1].Status != pb.ProductStatus_PRODUCT_STATUS_ARCHIVED {
+        t.Errorf("got %v, want both products with Kettle archived", res.Products)
+    }
+}
+
+func TestCalculateCartTotalRejectsArchivedProducts(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(statusRow(42, "Mug", "archived"))
+    mock.ExpectRollback()
+
+    _, err := (&server{db: db}).CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items: []*pb.CartItem{{ProductId: "42", Quantity: 1}},
+    })
+    if status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("cart with an archived product = %v, want FailedPrecondition", err)
+    }
+}
+
+func TestBulkUpdateProductStatusWithDatabase(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &Tag{}, &ProductTag{}, &StatusChangeLog{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateTagBitmaps(db); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateProductNameTrigramIndex(db); err != nil {
+        t.Skipf("pg_trgm is not available: %v", err)
+    }
+    s := &server{db: db}
+    ctx := context.Background()
+
+    ids := map[string]string{}
+    for _, name := range []string{"Blue Mug", "Red Mug", "Green Mug"} {
+        product := Product{Name: name, Price: 10, Status: productStatusActive}
+        if err := db.Create(&product).Error; err != nil {
+            t.Fatal(err)
+        }
+        ids[name] = product.toProto().Id
+        if _, err := s.SetProductTags(ctx, &pb.SetProductTagsRequest{ProductId: ids[name], Tags: []string{"mugs"}}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := refreshTagBitmaps(ctx, db); err != nil {
+        t.Fatal(err)
+    }
+    for name, newStatus := range map[string]pb.ProductStatus{
+        "Red Mug":   pb.ProductStatus_PRODUCT_STATUS_ARCHIVED,
+        "Green Mug": pb.ProductStatus_PRODUCT_STATUS_INACTIVE,
+    } {
+        res, err := s.BulkUpdateProductStatus(ctx, &pb.BulkUpdateProductStatusRequest{
+            ProductIds: []string{ids[name]},
+            NewStatus:  newStatus,
+            Reason:     "out of stock",
+        })
+        if err != nil {
+            t.Fatal(err)
+        }
+        if res.UpdatedCount != 1 || len(res.FailedIds) != 0 {
+            t.Errorf("%s: got %d updated and failed ids %v", name, res.UpdatedCount, res.FailedIds)
+        }
+    }
+
+    var logs []StatusChangeLog
+    if err := db.Order("product_id").Find(&logs).Error; err != nil {
+        t.Fatal(err)
+    }
+    if len(logs) != 2 || logs[0].OldStatus != productStatusActive || logs[0].NewStatus != productStatusArchived ||
+        logs[1].NewStatus != productStatusInactive || logs[1].Reason != "out of stock" {
+        t.Errorf("got status change logs %+v", logs)
+    }
+
+    names := func(products []*pb.Product) []string {
+        var names []string
+        for _, p := range products {
+            names = append(names, p.Name)
+        }
+        slices.Sort(names)
+        return names
+    }
+    for _, includeArchived := range []bool{false, true} {
+        want := []string{"Blue Mug"}
+        if includeArchived {
+            want = []string{"Blue Mug", "Green Mug", "Red Mug"}
+        }
+        byDate, err := s.ListProductsByDateRange(ctx, &pb.ListProductsByDateRangeRequest{PageSize: 10, IncludeArchived: includeArchived})
+        if err != nil {
+            t.Fatal(err)
+        }
+        byTags, err := s.SearchProductsByTags(ctx, &pb.SearchProductsByTagsRequest{Tags: []string{"mugs"}, IncludeArchived: includeArchived})
+        if err != nil {
+            t.Fatal(err)
+        }
+        fuzzy, err := s.FuzzySearchProducts(ctx, &pb.FuzzySearchProductsRequest{Query: "mug", IncludeArchived: includeArchived})
+        if err != nil {
+            t.Fatal(err)
+        }
+        var fuzzyProducts []*pb.Product
+        for _, match := range fuzzy.Products {
+            fuzzyProducts = append(fuzzyProducts, match.Product)
+        }
+        for search, got := range map[string][]string{
+            "ListProductsByDateRange": names(byDate.Products),
+            "SearchProductsByTags":    names(byTags.Products),
+            "FuzzySearchProducts":     names(fuzzyProducts),
+        } {
+            if !slices.Equal(got, want) {
+                t.Errorf("%s with include_archived %v = %v, want %v", search, includeArchived, got, want)
+            }
+        }
+    }
+}
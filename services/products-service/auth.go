@@ -0,0 +1,304 @@
+package main
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/reflection/grpc_reflection_v1"
+    "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+// apiKeyHeader is the metadata key callers put their API key in.
+const apiKeyHeader = "x-api-key"
+
+type role int
+
+const (
+    roleReadOnly role = iota + 1
+    roleReadWrite
+    roleModerator
+    roleAdmin
+)
+
+var roleNames = map[string]role{
+    "readonly":  roleReadOnly,
+    "readwrite": roleReadWrite,
+    "moderator": roleModerator,
+    "admin":     roleAdmin,
+}
+
+func (r role) String() string {
+    for name, value := range roleNames {
+        if value == r {
+            return name
+        }
+    }
+    return "none"
+}
+
+// methodPolicies lists the minimum role needed for every RPC. Methods that
+// are not listed are denied.
+var methodPolicies = map[string]role{
+    pb.ProductService_CreateProduct_FullMethodName:            roleReadWrite,
+    pb.ProductService_GetProduct_FullMethodName:               roleReadOnly,
+    pb.ProductService_CalculateCartTotal_FullMethodName:       roleReadWrite,
+    pb.ProductService_WatchProducts_FullMethodName:            roleReadOnly,
+    pb.ProductService_ExportProductsParquet_FullMethodName:    roleReadOnly,
+    pb.ProductService_WatchCacheInvalidations_FullMethodName:  roleReadOnly,
+    pb.ProductService_GetProductQRCode_FullMethodName:         roleReadOnly,
+    pb.ProductService_CreatePriceAlert_FullMethodName:         roleReadWrite,
+    pb.ProductService_DeletePriceAlert_FullMethodName:         roleReadWrite,
+    pb.ProductService_ListPriceAlerts_FullMethodName:          roleReadOnly,
+    pb.ProductService_GetPriceAlertStats_FullMethodName:       roleReadOnly,
+    pb.ProductService_SetProductTags_FullMethodName:           roleReadWrite,
+    pb.ProductService_SearchProductsByTags_FullMethodName:     roleReadOnly,
+    pb.ProductService_ListProductsByDateRange_FullMethodName:  roleReadOnly,
+    pb.ProductService_CalculateTax_FullMethodName:             roleReadOnly,
+    pb.ProductService_ListProducts_FullMethodName:             roleReadOnly,
+    pb.ProductService_ArchiveProduct_FullMethodName:           roleReadWrite,
+    pb.ProductService_UnarchiveProduct_FullMethodName:         roleReadWrite,
+    pb.ProductService_UpsertProductEmbedding_FullMethodName:   roleReadWrite,
+    pb.ProductService_GetSimilarProducts_FullMethodName:       roleReadOnly,
+    pb.ProductService_FuzzySearchProducts_FullMethodName:      roleReadOnly,
+    pb.ProductService_ImportProductsFromURL_FullMethodName:    roleAdmin,
+    pb.ProductService_GetAlternativeProducts_FullMethodName:   roleReadOnly,
+    pb.ProductService_BulkUpdateProductStatus_FullMethodName:  roleReadWrite,
+    pb.ProductService_ExportGoogleShoppingFeed_FullMethodName: roleReadOnly,
+    pb.ProductService_AddProductFAQ_FullMethodName:            roleReadWrite,
+    pb.ProductService_UpdateProductFAQ_FullMethodName:         roleReadWrite,
+    pb.ProductService_DeleteProductFAQ_FullMethodName:         roleReadWrite,
+    pb.ProductService_ReorderProductFAQs_FullMethodName:       roleReadWrite,
+    pb.ProductService_ListProductFAQs_FullMethodName:          roleReadOnly,
+    pb.ProductService_UpdateProduct_FullMethodName:            roleReadWrite,
+    pb.ProductService_GetProductVersion_FullMethodName:        roleReadOnly,
+    pb.ProductService_ListProductVersions_FullMethodName:      roleReadOnly,
+    pb.ProductService_ReviewProduct_FullMethodName:            roleModerator,
+    pb.ProductService_GetTagSimilarProducts_FullMethodName:    roleReadOnly,
+    pb.ProductService_GetCatalogPriceGini_FullMethodName:      roleReadOnly,
+    pb.ProductService_SyncProductCatalog_FullMethodName:       roleReadOnly,
+    pb.ProductService_SetProductCost_FullMethodName:           roleAdmin,
+    pb.ProductService_GetProductMargin_FullMethodName:         roleReadWrite,
+    pb.ProductService_GetProductCostHistory_FullMethodName:    roleReadWrite,
+    pb.ProductService_CloneProduct_FullMethodName:             roleAdmin,
+    pb.ProductService_SetPurchaseLimit_FullMethodName:         roleAdmin,
+    pb.ProductService_DeletePurchaseLimit_FullMethodName:      roleAdmin,
+    pbv2.ProductService_CreateProduct_FullMethodName:          roleReadWrite,
+    pbv2.ProductService_GetProduct_FullMethodName:             roleReadOnly,
+    pb.SelfTestService_SelfTest_FullMethodName:                roleAdmin,
+    pb.DrainService_Drain_FullMethodName:                      roleAdmin,
+    pb.QuotaService_GetQuotaUsage_FullMethodName:              roleReadOnly,
+    pb.BackfillService_ListBackfills_FullMethodName:           roleAdmin,
+    pb.SnapshotService_SnapshotData_FullMethodName:            roleAdmin,
+    pb.SnapshotService_RestoreData_FullMethodName:             roleAdmin,
+    pb.TransactionService_BeginTransaction_FullMethodName:     roleReadWrite,
+    pb.TransactionService_CommitTransaction_FullMethodName:    roleReadWrite,
+    pb.TransactionService_RollbackTransaction_FullMethodName:  roleReadWrite,
+    pb.AuditService_GetAuditLog_FullMethodName:                roleAdmin,
+    pb.DebugService_GetDebugInfo_FullMethodName:               roleAdmin,
+    pb.DebugService_GetDeploymentInfo_FullMethodName:          roleAdmin,
+    // Reflection lists the v1 and v2 services for tools such as grpcurl.
+    grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName:      roleReadOnly,
+    grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: roleReadOnly,
+}
+
+// publicMethods skip authentication entirely; Consul's health checks do not
+// carry an API key.
+var publicMethods = map[string]bool{
+    grpc_health_v1.Health_Check_FullMethodName: true,
+    grpc_health_v1.Health_Watch_FullMethodName: true,
+}
+
+// defaultTenant owns keys that do not name a tenant, and every call when
+// authentication is disabled.
+const defaultTenant = "default"
+
+type roleContextKey struct{}
+
+type actorContextKey struct{}
+
+type tenantContextKey struct{}
+
+func roleFromContext(ctx context.Context) (role, bool) {
+    r, ok := ctx.Value(roleContextKey{}).(role)
+    return r, ok
+}
+
+// anonymousActor is the actor of calls made without an API key, which is
+// every call when authentication is disabled.
+const anonymousActor = "anonymous"
+
+// actorFromContext identifies the caller that made a request.
+func actorFromContext(ctx context.Context) string {
+    if actor, ok := ctx.Value(actorContextKey{}).(string); ok {
+        return actor
+    }
+    return anonymousActor
+}
+
+// keyActor identifies an API key without revealing it, so the actor can be
+// stored and logged.
+func keyActor(key string) string {
+    sum := sha256.Sum256([]byte(key))
+    return "key:" + hex.EncodeToString(sum[:])[:12]
+}
+
+func tenantFromContext(ctx context.Context) string {
+    if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok {
+        return tenant
+    }
+    return defaultTenant
+}
+
+// apiKey is what an API key grants.
+type apiKey struct {
+    tenant string
+    role   role
+}
+
+type authenticator struct {
+    keys map[string]apiKey
+    // anonymousModerator gives anonymous callers the moderator role when
+    // authentication is disabled, for local development. It is set by
+    // ANONYMOUS_MODERATOR.
+    anonymousModerator bool
+}
+
+// loadAPIKeys parses API_KEYS_JSON, e.g. {"key1":"admin","key2":"acme:readonly"}.
+// A role may be prefixed with the tenant the key belongs to.
+// A nil map means authentication is disabled and admin methods are denied.
+func loadAPIKeys() (map[string]apiKey, error) {
+    raw := os.Getenv("API_KEYS_JSON")
+    if raw == "" {
+        return nil, nil
+    }
+
+    var names map[string]string
+    if err := json.Unmarshal([]byte(raw), &names); err != nil {
+        return nil, fmt.Errorf("invalid API_KEYS_JSON: %w", err)
+    }
+
+    keys := make(map[string]apiKey, len(names))
+    for key, value := range names {
+        tenant, name, found := strings.Cut(value, ":")
+        if !found {
+            tenant, name = defaultTenant, value
+        }
+        r, ok := roleNames[name]
+        if !ok {
+            return nil, fmt.Errorf("unknown role %q in API_KEYS_JSON", name)
+        }
+        if tenant == "" {
+            return nil, fmt.Errorf("empty tenant in API_KEYS_JSON value %q", value)
+        }
+        keys[key] = apiKey{tenant: tenant, role: r}
+    }
+    return keys, nil
+}
+
+// authenticate resolves the caller's role, actor and tenant from its API key
+// and stores them in the returned context. Anonymous callers have no role,
+// unless anonymousModerator is set.
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+    if publicMethods[method] {
+        return ctx, nil
+    }
+    if a.keys == nil {
+        if a.anonymousModerator {
+            ctx = context.WithValue(ctx, roleContextKey{}, roleModerator)
+        }
+        return ctx, nil
+    }
+
+    md, _ := metadata.FromIncomingContext(ctx)
+    values := md.Get(apiKeyHeader)
+    if len(values) == 0 {
+        return nil, status.Error(codes.Unauthenticated, "missing API key")
+    }
+    key, ok := a.keys[values[0]]
+    if !ok {
+        return nil, status.Error(codes.Unauthenticated, "invalid API key")
+    }
+    ctx = context.WithValue(ctx, roleContextKey{}, key.role)
+    ctx = context.WithValue(ctx, actorContextKey{}, keyActor(values[0]))
+    return context.WithValue(ctx, tenantContextKey{}, key.tenant), nil
+}
+
+// authorize checks the caller's role against methodPolicies. Without
+// API_KEYS_JSON every caller is anonymous, so moderator and admin methods,
+// and methods without a policy, are denied rather than opened to everyone.
+// ANONYMOUS_MODERATOR opens the moderator methods only.
+func (a *authenticator) authorize(ctx context.Context, method string) error {
+    if publicMethods[method] {
+        return nil
+    }
+
+    required, ok := methodPolicies[method]
+    if !ok {
+        return status.Errorf(codes.PermissionDenied, "method %s is not permitted", method)
+    }
+    if a.keys == nil {
+        r, ok := roleFromContext(ctx)
+        if required >= roleModerator && (!ok || r < required) {
+            return status.Errorf(codes.PermissionDenied, "%s needs a %s API key, and API_KEYS_JSON is not set", method, required)
+        }
+        return nil
+    }
+    r, _ := roleFromContext(ctx)
+    if r < required {
+        return status.Errorf(codes.PermissionDenied, "role %s may not call %s", r, method)
+    }
+    return nil
+}
+
+func (a *authenticator) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    ctx, err := a.authenticate(ctx, info.FullMethod)
+    if err != nil {
+        return nil, err
+    }
+    return handler(ctx, req)
+}
+
+func (a *authenticator) unaryAuthzInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    if err := a.authorize(ctx, info.FullMethod); err != nil {
+        return nil, err
+    }
+    return handler(ctx, req)
+}
+
+func (a *authenticator) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+    if err != nil {
+        return err
+    }
+    return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+}
+
+func (a *authenticator) streamAuthzInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+        return err
+    }
+    return handler(srv, ss)
+}
+
+// contextServerStream overrides the context of a wrapped server stream.
+type contextServerStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+    return s.ctx
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+func TestAuthorizePolicyMatrix(t *testing.T) {
+    auth := &authenticator{keys: map[string]apiKey{
+        "ro":  {tenant: defaultTenant, role: roleReadOnly},
+        "rw":  {tenant: defaultTenant, role: roleReadWrite},
+        "mod": {tenant: defaultTenant, role: roleModerator},
+        "adm": {tenant: defaultTenant, role: roleAdmin},
+    }}
+    methods := []struct {
+        method string
+        want   role
+    }{
+        {pb.ProductService_GetProduct_FullMethodName, roleReadOnly},
+        {pb.ProductService_CreateProduct_FullMethodName, roleReadWrite},
+        {pb.ProductService_CalculateCartTotal_FullMethodName, roleReadWrite},
+        {pb.ProductService_WatchProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_ExportProductsParquet_FullMethodName, roleReadOnly},
+        {pb.ProductService_WatchCacheInvalidations_FullMethodName, roleReadOnly},
+        {pb.ProductService_GetProductQRCode_FullMethodName, roleReadOnly},
+        {pb.ProductService_CreatePriceAlert_FullMethodName, roleReadWrite},
+        {pb.ProductService_DeletePriceAlert_FullMethodName, roleReadWrite},
+        {pb.ProductService_ListPriceAlerts_FullMethodName, roleReadOnly},
+        {pb.ProductService_GetPriceAlertStats_FullMethodName, roleReadOnly},
+        {pb.ProductService_SetProductTags_FullMethodName, roleReadWrite},
+        {pb.ProductService_SearchProductsByTags_FullMethodName, roleReadOnly},
+        {pb.ProductService_GetAlternativeProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_ListProductsByDateRange_FullMethodName, roleReadOnly},
+        {pb.ProductService_CalculateTax_FullMethodName, roleReadOnly},
+        {pb.ProductService_ListProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_ArchiveProduct_FullMethodName, roleReadWrite},
+        {pb.ProductService_UnarchiveProduct_FullMethodName, roleReadWrite},
+        {pb.ProductService_BulkUpdateProductStatus_FullMethodName, roleReadWrite},
+        {pb.ProductService_ExportGoogleShoppingFeed_FullMethodName, roleReadOnly},
+        {pb.ProductService_AddProductFAQ_FullMethodName, roleReadWrite},
+        {pb.ProductService_UpdateProductFAQ_FullMethodName, roleReadWrite},
+        {pb.ProductService_DeleteProductFAQ_FullMethodName, roleReadWrite},
+        {pb.ProductService_ReorderProductFAQs_FullMethodName, roleReadWrite},
+        {pb.ProductService_ListProductFAQs_FullMethodName, roleReadOnly},
+        {pb.ProductService_UpdateProduct_FullMethodName, roleReadWrite},
+        {pb.ProductService_GetProductVersion_FullMethodName, roleReadOnly},
+        {pb.ProductService_ListProductVersions_FullMethodName, roleReadOnly},
+        {pb.ProductService_ReviewProduct_FullMethodName, roleModerator},
+        {pb.ProductService_GetTagSimilarProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_GetCatalogPriceGini_FullMethodName, roleReadOnly},
+        {pb.ProductService_SyncProductCatalog_FullMethodName, roleReadOnly},
+        {pb.ProductService_SetProductCost_FullMethodName, roleAdmin},
+        {pb.ProductService_GetProductMargin_FullMethodName, roleReadWrite},
+        {pb.ProductService_GetProductCostHistory_FullMethodName, roleReadWrite},
+        {pb.ProductService_CloneProduct_FullMethodName, roleAdmin},
+        {pb.ProductService_SetPurchaseLimit_FullMethodName, roleAdmin},
+        {pb.ProductService_DeletePurchaseLimit_FullMethodName, roleAdmin},
+        {pb.ProductService_UpsertProductEmbedding_FullMethodName, roleReadWrite},
+        {pb.ProductService_GetSimilarProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_FuzzySearchProducts_FullMethodName, roleReadOnly},
+        {pb.ProductService_ImportProductsFromURL_FullMethodName, roleAdmin},
+        {pbv2.ProductService_GetProduct_FullMethodName, roleReadOnly},
+        {pbv2.ProductService_CreateProduct_FullMethodName, roleReadWrite},
+        {pb.QuotaService_GetQuotaUsage_FullMethodName, roleReadOnly},
+        {pb.SelfTestService_SelfTest_FullMethodName, roleAdmin},
+        {pb.DrainService_Drain_FullMethodName, roleAdmin},
+        {pb.AuditService_GetAuditLog_FullMethodName, roleAdmin},
+        {pb.DebugService_GetDebugInfo_FullMethodName, roleAdmin},
+        {pb.DebugService_GetDeploymentInfo_FullMethodName, roleAdmin},
+        {pb.BackfillService_ListBackfills_FullMethodName, roleAdmin},
+        {pb.SnapshotService_SnapshotData_FullMethodName, roleAdmin},
+        {pb.SnapshotService_RestoreData_FullMethodName, roleAdmin},
+        {pb.TransactionService_BeginTransaction_FullMethodName, roleReadWrite},
+        {pb.TransactionService_CommitTransaction_FullMethodName, roleReadWrite},
+        {pb.TransactionService_RollbackTransaction_FullMethodName, roleReadWrite},
+    }
+    for _, key := range []string{"ro", "rw", "mod", "adm"} {
+        ctx, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, key)), "")
+        if err != nil {
+            t.Fatalf("authenticate(%s): %v", key, err)
+        }
+        r, _ := roleFromContext(ctx)
+        for _, m := range methods {
+            err := auth.authorize(ctx, m.method)
+            if allowed := r >= m.want; allowed != (err == nil) {
+                t.Errorf("role %s calling %s: err = %v, want allowed = %t", r, m.method, err, allowed)
+            } else if err != nil && status.Code(err) != codes.PermissionDenied {
+                t.Errorf("role %s calling %s: code = %s, want PermissionDenied", r, m.method, status.Code(err))
+            }
+        }
+        if err := auth.authorize(ctx, "/products.ProductService/NoSuchMethod"); status.Code(err) != codes.PermissionDenied {
+            t.Errorf("role %s calling an unlisted method: err = %v, want PermissionDenied", r, err)
+        }
+    }
+}
+
+func TestAuthenticateRejectsBadKeys(t *testing.T) {
+    auth := &authenticator{keys: map[string]apiKey{"good": {tenant: defaultTenant, role: roleReadOnly}}}
+    tests := []struct {
+        name string
+        md   metadata.MD
+    }{
+        {"missing", metadata.MD{}},
+        {"unknown", metadata.Pairs(apiKeyHeader, "bad")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), tt.md), pb.ProductService_GetProduct_FullMethodName)
+            if status.Code(err) != codes.Unauthenticated {
+                t.Errorf("err = %v, want Unauthenticated", err)
+            }
+        })
+    }
+
+    // Health checks carry no key.
+    if _, err := auth.authenticate(context.Background(), grpc_health_v1.Health_Check_FullMethodName); err != nil {
+        t.Errorf("health check without a key: %v", err)
+    }
+}
+
+func TestAuthorizeWithoutKeys(t *testing.T) {
+    auth := &authenticator{}
+    tests := []struct {
+        method string
+        want   codes.Code
+    }{
+        {grpc_health_v1.Health_Check_FullMethodName, codes.OK},
+        {pb.ProductService_GetProduct_FullMethodName, codes.OK},
+        {pb.ProductService_CreateProduct_FullMethodName, codes.OK},
+        {pb.ProductService_ReviewProduct_FullMethodName, codes.PermissionDenied},
+        {pb.DrainService_Drain_FullMethodName, codes.PermissionDenied},
+        {"/products.ProductService/NoSuchMethod", codes.PermissionDenied},
+    }
+    for _, tt := range tests {
+        ctx, err := auth.authenticate(context.Background(), tt.method)
+        if err != nil {
+            t.Fatalf("authenticate(%s): %v", tt.method, err)
+        }
+        if got := status.Code(auth.authorize(ctx, tt.method)); got != tt.want {
+            t.Errorf("%s: code = %s, want %s", tt.method, got, tt.want)
+        }
+    }
+}
+
+func TestAnonymousModeratorWithoutKeys(t *testing.T) {
+    auth := &authenticator{anonymousModerator: true}
+    for method, want := range map[string]codes.Code{
+        pb.ProductService_ReviewProduct_FullMethodName: codes.OK,
+        pb.DrainService_Drain_FullMethodName:           codes.PermissionDenied,
+    } {
+        ctx, err := auth.authenticate(context.Background(), method)
+        if err != nil {
+            t.Fatalf("authenticate(%s): %v", method, err)
+        }
+        if !mayReview(ctx) {
+            t.Errorf("%s: anonymous caller may not review", method)
+        }
+        if got := status.Code(auth.authorize(ctx, method)); got != want {
+            t.Errorf("%s: code = %s, want %s", method, got, want)
+        }
+    }
+}
+
+func TestLoadAPIKeysWithTenants(t *testing.T) {
+    t.Setenv("API_KEYS_JSON", `{"k1":"admin","k2":"acme:readwrite"}`)
+    keys, err := loadAPIKeys()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if keys["k1"] != (apiKey{tenant: defaultTenant, role: roleAdmin}) || keys["k2"] != (apiKey{tenant: "acme", role: roleReadWrite}) {
+        t.Errorf("keys = %+v", keys)
+    }
+
+    auth := &authenticator{keys: keys}
+    ctx, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, "k2")), pb.ProductService_CreateProduct_FullMethodName)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if tenant := tenantFromContext(ctx); tenant != "acme" {
+        t.Errorf("tenant = %q, want acme", tenant)
+    }
+    if tenant := tenantFromContext(context.Background()); tenant != defaultTenant {
+        t.Errorf("tenant without a key = %q, want %q", tenant, defaultTenant)
+    }
+
+    for _, raw := range []string{`{"k1":":admin"}`, `{"k1":"acme:owner"}`} {
+        t.Setenv("API_KEYS_JSON", raw)
+        if _, err := loadAPIKeys(); err == nil {
+            t.Errorf("loadAPIKeys accepted %s", raw)
+        }
+    }
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/backfill"
+)
+
+const (
+    defaultBackfillBatchSize = 500
+    defaultBackfillPause     = 100 * time.Millisecond
+)
+
+// registerBackfills adds the data migrations in progress. Each job's name
+// keys its saved progress, so jobs are never renamed; remove a job only once
+// it is done everywhere.
+func registerBackfills(runner *backfill.Runner) {
+    // products.price_cents will replace the float price column. Rows created
+    // since it was added have it set by createProduct; this fills in the rest.
+    runner.RegisterBackfill("products.price_cents", "products", func(tx *gorm.DB, ids []uint64) error {
+        return tx.Model(&Product{}).Unscoped().
+            Where("id IN ? AND price_cents IS NULL", ids).
+            UpdateColumn("price_cents", gorm.Expr("ROUND(price * 100)::bigint")).Error
+    })
+}
+
+type backfillServer struct {
+    pb.UnimplementedBackfillServiceServer
+    runner *backfill.Runner
+}
+
+// ListBackfills reports the progress of every registered backfill job.
+func (s *backfillServer) ListBackfills(ctx context.Context, req *pb.ListBackfillsRequest) (*pb.ListBackfillsResponse, error) {
+    jobs, err := s.runner.List(ctx)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.ListBackfillsResponse{Backfills: make([]*pb.Backfill, len(jobs))}
+    for i, job := range jobs {
+        res.Backfills[i] = &pb.Backfill{
+            Name:      job.Name,
+            Table:     job.Table,
+            LastId:    job.LastID,
+            Processed: job.Processed,
+            Done:      job.Done,
+        }
+        if !job.StartedAt.IsZero() {
+            res.Backfills[i].StartedAt = timestamppb.New(job.StartedAt)
+            res.Backfills[i].UpdatedAt = timestamppb.New(job.UpdatedAt)
+        }
+        if job.CompletedAt != nil {
+            res.Backfills[i].CompletedAt = timestamppb.New(*job.CompletedAt)
+        }
+    }
+    return res, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    pb "products-service/proto/gen/proto"
+    "shared/backfill"
+)
+
+// TestPriceCentsBackfill fills price_cents for rows written before the
+// column existed and leaves dual-written rows alone.
+func TestPriceCentsBackfill(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &backfill.Progress{}); err != nil {
+        t.Fatal(err)
+    }
+    prices := []float64{12.5, 0.1, 19.99, 3}
+    for _, price := range prices {
+        if err := db.Omit("PriceCents").Create(&Product{Name: "Mug", Price: price}).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+    kept := int64(4200)
+    if err := db.Create(&Product{Name: "Kettle", Price: 40, PriceCents: &kept}).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    runner := backfill.NewRunner(db, 2, 0)
+    registerBackfills(runner)
+    ctx := context.Background()
+    if err := runner.Run(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    var products []Product
+    if err := db.Order("id").Find(&products).Error; err != nil {
+        t.Fatal(err)
+    }
+    want := []int64{1250, 10, 1999, 300, 4200}
+    for i, p := range products {
+        if p.PriceCents == nil || *p.PriceCents != want[i] {
+            t.Errorf("product %d (price %v) has price_cents %v, want %d", p.ID, p.Price, p.PriceCents, want[i])
+        }
+    }
+
+    res, err := (&backfillServer{runner: runner}).ListBackfills(ctx, &pb.ListBackfillsRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Backfills) != 1 || !res.Backfills[0].Done || res.Backfills[0].Processed != 5 || res.Backfills[0].CompletedAt == nil {
+        t.Errorf("ListBackfills = %v, want products.price_cents done after 5 rows", res.Backfills)
+    }
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+    "context"
+
+    pb "products-service/proto/gen/proto"
+    "shared/canary"
+)
+
+// GetDeploymentInfo reports whether this instance is a canary, and its
+// weight if it is.
+func (d *debugServer) GetDeploymentInfo(ctx context.Context, req *pb.GetDeploymentInfoRequest) (*pb.GetDeploymentInfoResponse, error) {
+    res := &pb.GetDeploymentInfoResponse{IsCanary: d.canary.IsCanary, Version: canary.Version()}
+    if d.canary.IsCanary {
+        res.Weight = int32(d.canary.Weight)
+    }
+    return res, nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "math"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// defaultCurrency is the currency all catalog prices are stored in.
+const defaultCurrency = "USD"
+
+type DiscountType string
+
+const (
+    DiscountTypePercentage DiscountType = "PERCENTAGE"
+    DiscountTypeFixed      DiscountType = "FIXED"
+)
+
+type DiscountCode struct {
+    gorm.Model
+    Code          string `gorm:"uniqueIndex"`
+    Type          DiscountType
+    Value         float64
+    MinOrderValue float64
+    ExpiresAt     *time.Time
+    MaxUses       int // zero means unlimited
+    CurrentUses   int
+}
+
+// discountFor returns the amount the code takes off the given subtotal,
+// never more than the subtotal itself.
+func (d *DiscountCode) discountFor(subtotal float64) float64 {
+    var amount float64
+    switch d.Type {
+    case DiscountTypePercentage:
+        amount = subtotal * d.Value / 100
+    case DiscountTypeFixed:
+        amount = d.Value
+    }
+    return math.Min(roundCents(amount), subtotal)
+}
+
+func (s *server) CalculateCartTotal(ctx context.Context, req *pb.CalculateCartTotalRequest) (*pb.CalculateCartTotalResponse, error) {
+    if len(req.Items) == 0 {
+        return nil, status.Error(codes.InvalidArgument, "cart must contain at least one item")
+    }
+    var country, region string
+    if req.CountryCode != "" {
+        var err error
+        if country, region, err = jurisdiction(req.CountryCode, req.RegionCode); err != nil {
+            return nil, err
+        }
+    }
+    for _, item := range req.Items {
+        if item.VariantId != "" {
+            return nil, status.Errorf(codes.InvalidArgument, "product variants are not supported (variant %s)", item.VariantId)
+        }
+        if item.Quantity <= 0 {
+            return nil, status.Errorf(codes.InvalidArgument, "quantity for product %s must be positive", item.ProductId)
+        }
+    }
+
+    var res *pb.CalculateCartTotalResponse
+    err := s.inTransaction(ctx, func(tx *gorm.DB) error {
+        lineItems, subtotal, err := priceCartItems(tx, req.Items)
+        if err != nil {
+            return err
+        }
+
+        var discount float64
+        if req.DiscountCode != "" {
+            if discount, err = redeemDiscountCode(tx, req.DiscountCode, subtotal); err != nil {
+                return err
+            }
+        }
+
+        var tax float64
+        if country != "" {
+            if tax, err = s.cartTax(ctx, tx, lineItems, country, region); err != nil {
+                return err
+            }
+            // The discount lowers every line's taxable amount by the same
+            // share.
+            if subtotal > 0 {
+                tax = roundCents(tax * (subtotal - discount) / subtotal)
+            }
+        }
+
+        res = &pb.CalculateCartTotalResponse{
+            LineItems:      lineItems,
+            Subtotal:       money(subtotal),
+            DiscountAmount: money(discount),
+            TaxAmount:      money(tax),
+            Total:          money(subtotal - discount + tax),
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func priceCartItems(tx *gorm.DB, items []*pb.CartItem) ([]*pb.LineItem, float64, error) {
+    lineItems := make([]*pb.LineItem, 0, len(items))
+    var subtotal float64
+    for _, item := range items {
+        id, err := strconv.ParseUint(item.ProductId, 10, 64)
+        if err != nil {
+            return nil, 0, status.Errorf(codes.InvalidArgument, "invalid product id %q", item.ProductId)
+        }
+
+        var product Product
+        if err := tx.First(&product, id).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return nil, 0, status.Errorf(codes.NotFound, "product %s not found", item.ProductId)
+            }
+            return nil, 0, err
+        }
+        if product.Status == productStatusArchived {
+            return nil, 0, status.Errorf(codes.FailedPrecondition, "product %s is archived", item.ProductId)
+        }
+        if product.ReviewStatus != reviewStatusApproved {
+            return nil, 0, status.Errorf(codes.FailedPrecondition, "product %s has not been approved", item.ProductId)
+        }
+
+        lineTotal := roundCents(product.Price * float64(item.Quantity))
+        subtotal += lineTotal
+        lineItems = append(lineItems, &pb.LineItem{
+            ProductId: item.ProductId,
+            Name:      product.Name,
+            Quantity:  item.Quantity,
+            UnitPrice: money(product.Price),
+            Total:     money(lineTotal),
+        })
+    }
+    return lineItems, roundCents(subtotal), nil
+}
+
+// cartTax returns the tax on the priced line items, each at the rule
+// CalculateTax applies to its product in country and region.
+func (s *server) cartTax(ctx context.Context, tx *gorm.DB, lineItems []*pb.LineItem, country, region string) (float64, error) {
+    var tax float64
+    for _, item := range lineItems {
+        // priceCartItems has already parsed and found the product.
+        id, _ := strconv.ParseUint(item.ProductId, 10, 64)
+        rule, err := s.taxRuleFor(ctx, tx, uint(id), country, region)
+        if err != nil {
+            return 0, err
+        }
+        tax += item.Total.Amount * rule.Rate
+    }
+    return roundCents(tax), nil
+}
+
+// checkUsable returns why the code cannot be applied to an order of
+// subtotal at now, or nil if it can.
+func (d *DiscountCode) checkUsable(subtotal float64, now time.Time) error {
+    if d.ExpiresAt != nil && !d.ExpiresAt.After(now) {
+        return status.Errorf(codes.FailedPrecondition, "discount code %q has expired", d.Code)
+    }
+    if d.MaxUses > 0 && d.CurrentUses >= d.MaxUses {
+        return status.Errorf(codes.FailedPrecondition, "discount code %q has reached its usage limit", d.Code)
+    }
+    if subtotal < d.MinOrderValue {
+        return status.Errorf(codes.FailedPrecondition, "discount code %q requires a minimum order value of %.2f", d.Code, d.MinOrderValue)
+    }
+    return nil
+}
+
+// redeemDiscountCode validates the code and records one use of it. The row is
+// locked with SELECT ... FOR UPDATE until the surrounding transaction ends, so
+// concurrent carts cannot push current_uses past max_uses.
+func redeemDiscountCode(tx *gorm.DB, code string, subtotal float64) (float64, error) {
+    var discount DiscountCode
+    err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&discount).Error
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return 0, status.Errorf(codes.NotFound, "discount code %q not found", code)
+        }
+        return 0, err
+    }
+    if err := discount.checkUsable(subtotal, time.Now()); err != nil {
+        return 0, err
+    }
+    if err := tx.Model(&discount).Update("current_uses", gorm.Expr("current_uses + 1")).Error; err != nil {
+        return 0, err
+    }
+    return discount.discountFor(subtotal), nil
+}
+
+func money(amount float64) *pb.Money {
+    return &pb.Money{CurrencyCode: defaultCurrency, Amount: roundCents(amount)}
+}
+
+func roundCents(amount float64) float64 {
+    return math.Round(amount*100) / 100
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestDiscountCodeCheckUsable(t *testing.T) {
+    now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+    past, future := now.Add(-time.Second), now.Add(time.Hour)
+    tests := []struct {
+        name     string
+        code     DiscountCode
+        subtotal float64
+        want     codes.Code
+    }{
+        {"valid", DiscountCode{MaxUses: 5, CurrentUses: 4, MinOrderValue: 10, ExpiresAt: &future}, 10, codes.OK},
+        {"no expiry and unlimited uses", DiscountCode{CurrentUses: 1000}, 1, codes.OK},
+        {"expired", DiscountCode{ExpiresAt: &past}, 10, codes.FailedPrecondition},
+        {"expires now", DiscountCode{ExpiresAt: &now}, 10, codes.FailedPrecondition},
+        {"uses exhausted", DiscountCode{MaxUses: 3, CurrentUses: 3}, 10, codes.FailedPrecondition},
+        {"below minimum order value", DiscountCode{MinOrderValue: 50}, 49.99, codes.FailedPrecondition},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.code.Code = "SAVE"
+            if got := status.Code(tt.code.checkUsable(tt.subtotal, now)); got != tt.want {
+                t.Errorf("checkUsable(%v) = %v, want %v", tt.subtotal, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestDiscountCodeDiscountFor(t *testing.T) {
+    tests := []struct {
+        name     string
+        code     DiscountCode
+        subtotal float64
+        want     float64
+    }{
+        {"percentage", DiscountCode{Type: DiscountTypePercentage, Value: 10}, 59.99, 6},
+        {"percentage rounds to cents", DiscountCode{Type: DiscountTypePercentage, Value: 15}, 19.99, 3},
+        {"whole order", DiscountCode{Type: DiscountTypePercentage, Value: 100}, 42.5, 42.5},
+        {"fixed", DiscountCode{Type: DiscountTypeFixed, Value: 5}, 20, 5},
+        {"fixed above subtotal", DiscountCode{Type: DiscountTypeFixed, Value: 25}, 20, 20},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.code.discountFor(tt.subtotal); got != tt.want {
+                t.Errorf("discountFor(%v) = %v, want %v", tt.subtotal, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCalculateCartTotalRedeemsDiscountCode(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // The code row is locked before it is checked, and the use is counted
+    // in the same transaction.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "review_status"}).AddRow(1, "Mug", 12.5, reviewStatusApproved))
+    mock.ExpectQuery(`SELECT \* FROM "discount_codes" WHERE code = \$1 .* FOR UPDATE`).
+        WithArgs("ONCE").
+        WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "value", "max_uses", "current_uses"}).
+            AddRow(1, "ONCE", "FIXED", 5, 1, 0))
+    mock.ExpectExec(`UPDATE "discount_codes" SET "current_uses"=current_uses \+ 1`).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    res, err := s.CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items:        []*pb.CartItem{{ProductId: "1", Quantity: 2}},
+        DiscountCode: "ONCE",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Subtotal.Amount != 25 || res.DiscountAmount.Amount != 5 || res.Total.Amount != 20 {
+        t.Errorf("subtotal, discount, total = %v, %v, %v, want 25, 5, 20",
+            res.Subtotal.Amount, res.DiscountAmount.Amount, res.Total.Amount)
+    }
+}
+
+func TestCalculateCartTotalExhaustedDiscountCode(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // The last use was taken while this cart waited for the lock: the code
+    // is rejected and no use is recorded.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "review_status"}).AddRow(1, "Mug", 12.5, reviewStatusApproved))
+    mock.ExpectQuery(`SELECT \* FROM "discount_codes" .* FOR UPDATE`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "value", "max_uses", "current_uses"}).
+            AddRow(1, "ONCE", "FIXED", 5, 1, 1))
+    mock.ExpectRollback()
+
+    _, err := s.CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items:        []*pb.CartItem{{ProductId: "1", Quantity: 2}},
+        DiscountCode: "ONCE",
+    })
+    if status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("exhausted code: err = %v, want FailedPrecondition", err)
+    }
+}
+
+func TestCalculateCartTotalUnknownDiscountCode(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "review_status"}).AddRow(1, "Mug", 12.5, reviewStatusApproved))
+    mock.ExpectQuery(`SELECT \* FROM "discount_codes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectRollback()
+
+    _, err := s.CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items:        []*pb.CartItem{{ProductId: "1", Quantity: 1}},
+        DiscountCode: "NOPE",
+    })
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("unknown code: err = %v, want NotFound", err)
+    }
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+    "log"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "gorm.io/gorm"
+)
+
+// defaultCatalogMetricsInterval is used when CATALOG_METRICS_INTERVAL is not
+// set.
+const defaultCatalogMetricsInterval = 10 * time.Minute
+
+// catalogMetricsBatchSize is how many prices are read from the database at
+// once while aggregating the price distribution.
+const catalogMetricsBatchSize = 1000
+
+// priceBuckets are the upper bounds of price_distribution_histogram's
+// buckets; +Inf is implicit.
+var priceBuckets = []float64{0, 5, 10, 25, 50, 100, 250, 500, 1000, 5000}
+
+var catalogProductCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+    Name: "catalog_product_count",
+    Help: "Number of products by status, refreshed periodically.",
+}, []string{"status"})
+
+// priceDistribution is a snapshot of the prices of all products, taken by
+// startCatalogMetricsCollector.
+type priceDistribution struct {
+    count   uint64
+    sum     float64
+    buckets map[float64]uint64
+}
+
+// newPriceDistribution returns an empty distribution. Every bucket is
+// present, so buckets no price falls in are still exported.
+func newPriceDistribution() *priceDistribution {
+    d := &priceDistribution{buckets: make(map[float64]uint64, len(priceBuckets))}
+    for _, bound := range priceBuckets {
+        d.buckets[bound] = 0
+    }
+    return d
+}
+
+// observe adds a price to every bucket it falls in, as Prometheus buckets
+// are cumulative.
+func (d *priceDistribution) observe(price float64) {
+    d.count++
+    d.sum += price
+    for _, bound := range priceBuckets {
+        if price <= bound {
+            d.buckets[bound]++
+        }
+    }
+}
+
+// priceDistributionCollector exports the latest price distribution as
+// price_distribution_histogram. It is a snapshot rather than a
+// prometheus.Histogram, which would count every product again on each
+// aggregation.
+type priceDistributionCollector struct {
+    desc *prometheus.Desc
+
+    mu     sync.Mutex
+    latest *priceDistribution
+}
+
+var priceDistributionHistogram = &priceDistributionCollector{
+    desc: prometheus.NewDesc(
+        "price_distribution_histogram",
+        "Distribution of the prices of all products, refreshed periodically.",
+        nil,
+        prometheus.Labels{"currency": defaultCurrency},
+    ),
+    latest: newPriceDistribution(),
+}
+
+func init() {
+    prometheus.MustRegister(catalogProductCount, priceDistributionHistogram)
+}
+
+func (c *priceDistributionCollector) Describe(ch chan<- *prometheus.Desc) {
+    ch <- c.desc
+}
+
+func (c *priceDistributionCollector) Collect(ch chan<- prometheus.Metric) {
+    c.mu.Lock()
+    latest := c.latest
+    c.mu.Unlock()
+    ch <- prometheus.MustNewConstHistogram(c.desc, latest.count, latest.sum, latest.buckets)
+}
+
+func (c *priceDistributionCollector) set(d *priceDistribution) {
+    c.mu.Lock()
+    c.latest = d
+    c.mu.Unlock()
+}
+
+// aggregatePrices reads the price of every product in batches.
+func aggregatePrices(db *gorm.DB) (*priceDistribution, error) {
+    distribution := newPriceDistribution()
+    var products []Product
+    result := db.Select("id", "price").FindInBatches(&products, catalogMetricsBatchSize, func(tx *gorm.DB, batch int) error {
+        for _, product := range products {
+            distribution.observe(product.Price)
+        }
+        return nil
+    })
+    return distribution, result.Error
+}
+
+// countProductsByStatus sets catalog_product_count, reporting 0 for statuses
+// no product has.
+func countProductsByStatus(db *gorm.DB) error {
+    var counts []struct {
+        Status string
+        Count  int64
+    }
+    if err := db.Model(&Product{}).Select("status, COUNT(*) AS count").Group("status").Scan(&counts).Error; err != nil {
+        return err
+    }
+    for name := range productStatuses {
+        catalogProductCount.WithLabelValues(strings.ToUpper(name)).Set(0)
+    }
+    for _, c := range counts {
+        catalogProductCount.WithLabelValues(strings.ToUpper(c.Status)).Set(float64(c.Count))
+    }
+    return nil
+}
+
+// startCatalogMetricsCollector refreshes price_distribution_histogram and
+// catalog_product_count every interval. A failed refresh is logged and the
+// metrics keep their last values.
+func startCatalogMetricsCollector(db *gorm.DB, interval time.Duration) {
+    go func() {
+        for {
+            if distribution, err := aggregatePrices(db); err != nil {
+                log.Printf("Failed to aggregate product prices for metrics: %v", err)
+            } else {
+                priceDistributionHistogram.set(distribution)
+            }
+            if err := countProductsByStatus(db); err != nil {
+                log.Printf("Failed to count products by status for metrics: %v", err)
+            }
+            time.Sleep(interval)
+        }
+    }()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+    "math"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAggregatePricesFillsCumulativeBuckets(t *testing.T) {
+    db, mock := newMockDB(t)
+    // A full first batch makes FindInBatches read a second one after the
+    // last id.
+    first := sqlmock.NewRows([]string{"id", "price"})
+    for id := 1; id <= catalogMetricsBatchSize; id++ {
+        first.AddRow(id, 7.5)
+    }
+    mock.ExpectQuery(`SELECT "id","price" FROM "products" WHERE "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT 1000`).
+        WillReturnRows(first)
+    mock.ExpectQuery(`SELECT "id","price" FROM "products" WHERE "products"."id" > \$1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT 1000`).
+        WithArgs(catalogMetricsBatchSize).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).
+            AddRow(1001, 0).
+            AddRow(1002, 5).
+            AddRow(1003, 120).
+            AddRow(1004, 9999.99))
+
+    d, err := aggregatePrices(db)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if wantSum := 7.5*catalogMetricsBatchSize + 5 + 120 + 9999.99; d.count != catalogMetricsBatchSize+4 || math.Abs(d.sum-wantSum) > 1e-6 {
+        t.Errorf("got count %d and sum %v", d.count, d.sum)
+    }
+    // Each bucket counts every price at or below its bound. 9999.99 is in
+    // none of them, only in the implicit +Inf bucket.
+    for bound, want := range map[float64]uint64{
+        0: 1, 5: 2, 10: 1002, 25: 1002, 50: 1002, 100: 1002, 250: 1003, 1000: 1003, 5000: 1003,
+    } {
+        if got := d.buckets[bound]; got != want {
+            t.Errorf("bucket %v = %d, want %d", bound, got, want)
+        }
+    }
+}
+
+func TestPriceDistributionCollectorExportsSnapshot(t *testing.T) {
+    c := &priceDistributionCollector{
+        desc:   prometheus.NewDesc("price_distribution_histogram", "help", nil, prometheus.Labels{"currency": defaultCurrency}),
+        latest: newPriceDistribution(),
+    }
+    registry := prometheus.NewPedanticRegistry()
+    registry.MustRegister(c)
+
+    d := newPriceDistribution()
+    for _, price := range []float64{3, 40} {
+        d.observe(price)
+    }
+    // Setting the same distribution twice must not double-count, as a
+    // prometheus.Histogram would.
+    c.set(d)
+    c.set(d)
+
+    families, err := registry.Gather()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(families) != 1 || len(families[0].Metric) != 1 {
+        t.Fatalf("gathered %v, want one histogram", families)
+    }
+    metric := families[0].Metric[0]
+    histogram := metric.GetHistogram()
+    if histogram.GetSampleCount() != 2 || histogram.GetSampleSum() != 43 {
+        t.Errorf("got count %d and sum %v, want 2 and 43", histogram.GetSampleCount(), histogram.GetSampleSum())
+    }
+    if len(metric.Label) != 1 || metric.Label[0].GetName() != "currency" || metric.Label[0].GetValue() != "USD" {
+        t.Errorf("got labels %v, want currency=USD", metric.Label)
+    }
+    if len(histogram.Bucket) != len(priceBuckets) {
+        t.Errorf("got %d buckets, want %d", len(histogram.Bucket), len(priceBuckets))
+    }
+}
+
+func TestCountProductsByStatusReportsMissingStatusesAsZero(t *testing.T) {
+    db, mock := newMockDB(t)
+    catalogProductCount.WithLabelValues("INACTIVE").Set(12)
+    mock.ExpectQuery(`SELECT status, COUNT\(\*\) AS count FROM "products" WHERE "products"."deleted_at" IS NULL GROUP BY "status"`).
+        WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow("active", 40).AddRow("archived", 2))
+
+    if err := countProductsByStatus(db); err != nil {
+        t.Fatal(err)
+    }
+    for status, want := range map[string]float64{"ACTIVE": 40, "INACTIVE": 0, "ARCHIVED": 2} {
+        if got := testutil.ToFloat64(catalogProductCount.WithLabelValues(status)); got != want {
+            t.Errorf("catalog_product_count{status=%q} = %v, want %v", status, got, want)
+        }
+    }
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+    "context"
+    "log"
+    "strconv"
+    "time"
+
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// syncBatchSize is how many products a catalog sync reads from the database
+// at once.
+const syncBatchSize = 500
+
+// syncCatchUpMargin bounds how long a transaction that changes a product
+// takes to commit. updated_at and the outbox row's created_at are stamped
+// before the commit, so a change can become visible that long after the
+// time it carries.
+const syncCatchUpMargin = time.Minute
+
+// syncChangedAt orders products by their last change, including deletion.
+const syncChangedAt = "COALESCE(deleted_at, updated_at)"
+
+// syncedProduct is the last state of a product a sync sent.
+type syncedProduct struct {
+    updatedAt time.Time
+    deleted   bool
+}
+
+// catalogSync is the state of one SyncProductCatalog stream.
+type catalogSync struct {
+    s              *server
+    stream         pb.ProductService_SyncProductCatalogServer
+    since          time.Time
+    includeDeleted bool
+    sequence       int64
+
+    // live reads the outbox from shortly before the history was read, so
+    // changes committed on any instance while it was read are not missed.
+    live outboxCursor
+    // recent holds what the history sent for each product changed shortly
+    // before or while it was read, so the same change replayed from the
+    // outbox is not sent twice. It is dropped once live passes historyEnd,
+    // the newest outbox row when the history finished, since later rows
+    // hold changes the history never saw.
+    recent     map[uint]syncedProduct
+    recentFrom time.Time
+    historyEnd uint64
+}
+
+// send streams a change.
+func (c *catalogSync) send(eventType pb.ProductEventType, product *pb.Product, deletedAt *time.Time) error {
+    c.sequence++
+    event := &pb.SyncEvent{EventType: eventType.String(), Product: product, SequenceNumber: c.sequence}
+    if deletedAt != nil {
+        event.DeletedAt = timestamppb.New(*deletedAt)
+    }
+    return c.stream.Send(event)
+}
+
+// catchUp streams the history and positions the outbox cursor where the
+// live phase takes over.
+func (c *catalogSync) catchUp(ctx context.Context) error {
+    db := c.s.db.WithContext(ctx)
+    handoverAt := time.Now().Add(-syncCatchUpMargin)
+    var after uint64
+    err := db.Model(&OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Where("created_at < ?", handoverAt).Scan(&after).Error
+    if err != nil {
+        return err
+    }
+    c.live = outboxCursor{db: c.s.db, now: time.Now, after: after}
+    c.recent = make(map[uint]syncedProduct)
+    c.recentFrom = handoverAt.Add(-syncCatchUpMargin)
+
+    if err := c.history(ctx); err != nil {
+        return err
+    }
+    c.historyEnd, err = newestOutboxID(db)
+    return err
+}
+
+// history streams the products changed at or after since, oldest change
+// first, paging through them by their change time and id. Deleted products
+// are read even without include_deleted, so that changes to them replayed
+// from the outbox are recognised as stale.
+func (c *catalogSync) history(ctx context.Context) error {
+    cursorAt, cursorID := c.since, uint(0)
+    for {
+        var products []Product
+        err := c.s.db.WithContext(ctx).Unscoped().
+            Where("("+syncChangedAt+", id) > (?, ?)", cursorAt, cursorID).
+            Order(syncChangedAt + ", id").
+            Limit(syncBatchSize).
+            Find(&products).Error
+        if err != nil {
+            return err
+        }
+        for i := range products {
+            product := &products[i]
+            eventType := pb.ProductEventType_PRODUCT_UPDATED
+            changedAt := product.UpdatedAt
+            var deletedAt *time.Time
+            switch {
+            case product.DeletedAt.Valid:
+                eventType = pb.ProductEventType_PRODUCT_DELETED
+                deletedAt = &product.DeletedAt.Time
+                changedAt = *deletedAt
+            case product.CreatedAt.After(c.since):
+                eventType = pb.ProductEventType_PRODUCT_CREATED
+            }
+            cursorAt, cursorID = changedAt, product.ID
+            if !changedAt.Before(c.recentFrom) {
+                c.recent[product.ID] = syncedProduct{updatedAt: product.UpdatedAt, deleted: deletedAt != nil}
+            }
+            if deletedAt != nil && !c.includeDeleted {
+                continue
+            }
+            if err := c.send(eventType, product.toProto(), deletedAt); err != nil {
+                return err
+            }
+        }
+        if len(products) < syncBatchSize {
+            return nil
+        }
+    }
+}
+
+// poll streams the changes committed since the last poll, in outbox order,
+// skipping those the history already sent. Timestamps are compared to the
+// microsecond, which is as precise as the database stores them.
+func (c *catalogSync) poll(ctx context.Context) error {
+    rows, err := c.live.next(ctx)
+    if err != nil {
+        return err
+    }
+    for i := range rows {
+        row := &rows[i]
+        if row.Type == pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED {
+            continue
+        }
+        event, err := row.productEvent()
+        if err != nil {
+            log.Printf("Skipping unreadable outbox row %d: %v", row.ID, err)
+            continue
+        }
+        updatedAt := event.Product.UpdatedAt.AsTime()
+        if updatedAt.Before(c.since) {
+            continue
+        }
+        if c.recent != nil {
+            id, err := strconv.ParseUint(event.Product.Id, 10, 64)
+            if err != nil {
+                continue
+            }
+            if last, ok := c.recent[uint(id)]; ok && (last.deleted || updatedAt.Sub(last.updatedAt) < time.Microsecond) {
+                continue
+            }
+        }
+        if err := c.send(event.Type, event.Product, nil); err != nil {
+            return err
+        }
+    }
+    if c.live.after >= c.historyEnd {
+        c.recent = nil
+    }
+    return nil
+}
+
+// SyncProductCatalog keeps an external copy of the catalog up to date. It
+// first streams every product changed since since, oldest change first, and
+// then streams changes as they are committed. Events are numbered from 1
+// without gaps, and a product's change is sent once even when it falls on
+// the switch from history to live changes.
+//
+// Live changes are read from the outbox shared by every instance, from
+// shortly before the history was read, so changes committed anywhere while
+// the history streams are not missed. Deletions happen outside the API, so
+// they are only found in the history, and only with include_deleted.
+func (s *server) SyncProductCatalog(req *pb.SyncProductCatalogRequest, stream pb.ProductService_SyncProductCatalogServer) error {
+    ctx := stream.Context()
+    c := &catalogSync{s: s, stream: stream, includeDeleted: req.IncludeDeleted}
+    if req.Since != nil {
+        c.since = req.Since.AsTime()
+    }
+    if err := c.catchUp(ctx); err != nil {
+        return err
+    }
+
+    ticker := time.NewTicker(outboxPollInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-ticker.C:
+        }
+        if err := c.poll(ctx); err != nil {
+            return err
+        }
+    }
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+    "shared/fakedata"
+)
+
+type syncStream = fakeServerStream[pb.SyncEvent]
+
+func syncProductRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "name", "price", "created_at", "updated_at", "deleted_at"})
+}
+
+// outboxProduct marshals product as an outbox row stores it.
+func outboxProduct(t *testing.T, product Product) []byte {
+    t.Helper()
+    data, err := proto.Marshal(product.toProto())
+    if err != nil {
+        t.Fatal(err)
+    }
+    return data
+}
+
+// syncedEvents drains what stream has been sent so far.
+func syncedEvents(stream *syncStream) []*pb.SyncEvent {
+    var events []*pb.SyncEvent
+    for {
+        select {
+        case event := <-stream.sent:
+            events = append(events, event)
+        default:
+            return events
+        }
+    }
+}
+
+func TestCatalogSyncSendsEachChangeOnce(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    now := time.Now().Truncate(time.Microsecond)
+    since := now.Add(-time.Hour)
+    stream := newFakeServerStream[pb.SyncEvent](context.Background())
+    c := &catalogSync{s: s, stream: stream, since: since}
+
+    // Product 1 changed long ago, product 2 while the history was read, and
+    // product 4 was deleted just before.
+    mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM "product_outbox" WHERE created_at < \$1`).
+        WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(10))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE \(COALESCE\(deleted_at, updated_at\), id\) > \(\$1, \$2\) ORDER BY COALESCE\(deleted_at, updated_at\), id LIMIT 500`).
+        WithArgs(since, 0).
+        WillReturnRows(syncProductRows().
+            AddRow(1, "Mug", 12.5, since.Add(-time.Hour), since.Add(time.Minute), nil).
+            AddRow(4, "Kettle", 40, since.Add(-time.Hour), now.Add(-time.Second), now).
+            AddRow(2, "Teapot", 30, since.Add(-time.Hour), now, nil))
+    mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM "product_outbox"`).
+        WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(12))
+    if err := c.catchUp(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    // Another instance creates product 3 once the history has been sent.
+    var created capturedBytes
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int64(pb.ProductEventType_PRODUCT_CREATED), &created, sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+    mock.ExpectCommit()
+    other := &server{db: db}
+    req := fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t))
+    if _, err := other.CreateProduct(context.Background(), req); err != nil {
+        t.Fatal(err)
+    }
+
+    // The outbox replays the changes to products 2 and 4 that the history
+    // already covered, then the new product.
+    teapot := outboxProduct(t, Product{Model: gorm.Model{ID: 2, UpdatedAt: now.Add(123 * time.Nanosecond)}, Name: "Teapot", Price: 30})
+    kettle := outboxProduct(t, Product{Model: gorm.Model{ID: 4, UpdatedAt: now.Add(-time.Second)}, Name: "Kettle", Price: 40})
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox" WHERE id > \$1 ORDER BY id`).WithArgs(10).
+        WillReturnRows(outboxRows().
+            AddRow(11, int64(pb.ProductEventType_PRODUCT_UPDATED), teapot, now).
+            AddRow(12, int64(pb.ProductEventType_PRODUCT_UPDATED), kettle, now).
+            AddRow(13, int64(pb.ProductEventType_PRODUCT_CREATED), created.data, now))
+    if err := c.poll(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    if c.recent != nil {
+        t.Error("the history's products are still remembered after the handover")
+    }
+
+    // Later changes to a product the history sent are streamed.
+    teapot = outboxProduct(t, Product{Model: gorm.Model{ID: 2, UpdatedAt: now.Add(time.Second)}, Name: "Teapot", Price: 25})
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox" WHERE id > \$1 ORDER BY id`).WithArgs(13).
+        WillReturnRows(outboxRows().AddRow(14, int64(pb.ProductEventType_PRODUCT_UPDATED), teapot, now.Add(time.Second)))
+    if err := c.poll(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    want := []struct {
+        eventType string
+        id        string
+        name      string
+    }{
+        {"PRODUCT_UPDATED", "1", "Mug"},
+        {"PRODUCT_UPDATED", "2", "Teapot"},
+        {"PRODUCT_CREATED", "3", req.Name},
+        {"PRODUCT_UPDATED", "2", "Teapot"},
+    }
+    events := syncedEvents(stream)
+    if len(events) != len(want) {
+        t.Fatalf("got %d events, want %d: %v", len(events), len(want), events)
+    }
+    for i, event := range events {
+        if event.SequenceNumber != int64(i+1) {
+            t.Errorf("event %d has sequence number %d", i+1, event.SequenceNumber)
+        }
+        if w := want[i]; event.EventType != w.eventType || event.Product.Id != w.id || event.Product.Name != w.name {
+            t.Errorf("event %d = %s %s %q, want %s %s %q", i+1, event.EventType, event.Product.Id, event.Product.Name, w.eventType, w.id, w.name)
+        }
+    }
+    if events[3].Product.Price != 25 {
+        t.Errorf("last event has price %v, want the updated 25", events[3].Product.Price)
+    }
+}
+
+func TestCatalogSyncReportsDeletions(t *testing.T) {
+    db, mock := newMockDB(t)
+    now := time.Now().Truncate(time.Microsecond)
+    stream := newFakeServerStream[pb.SyncEvent](context.Background())
+    c := &catalogSync{s: &server{db: db}, stream: stream, includeDeleted: true}
+
+    mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM "product_outbox"`).
+        WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0))
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(syncProductRows().AddRow(4, "Kettle", 40, now.Add(-time.Hour), now.Add(-time.Minute), now))
+    mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM "product_outbox"`).
+        WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0))
+    if err := c.catchUp(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    events := syncedEvents(stream)
+    if len(events) != 1 || events[0].EventType != "PRODUCT_DELETED" || !events[0].DeletedAt.AsTime().Equal(now) {
+        t.Errorf("events = %v, want product 4 deleted at %v", events, now)
+    }
+}
+
+// TestSyncProductCatalogAcrossInstances starts a sync on one instance of a
+// catalog that already has products, creates a product on another, and
+// checks it is streamed exactly once.
+func TestSyncProductCatalogAcrossInstances(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &ProductVersion{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    a := &server{db: db}
+    b := &server{db: db}
+    gen := fakedata.NewGenerator(t)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    for i := 0; i < 3; i++ {
+        if _, err := a.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](gen)); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    stream := newFakeServerStream[pb.SyncEvent](ctx)
+    done := make(chan error, 1)
+    go func() {
+        done <- a.SyncProductCatalog(&pb.SyncProductCatalogRequest{Since: timestamppb.New(time.Now().Add(-time.Hour))}, stream)
+    }()
+    for i := 0; i < 3; i++ {
+        stream.next(t)
+    }
+
+    created, err := b.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](gen))
+    if err != nil {
+        t.Fatal(err)
+    }
+    event := stream.next(t)
+    if event.Product.Id != created.Product.Id || event.EventType != "PRODUCT_CREATED" || event.SequenceNumber != 4 {
+        t.Errorf("got %s %s #%d, want the created product %s as event 4", event.EventType, event.Product.Id, event.SequenceNumber, created.Product.Id)
+    }
+    // Give the sync a few polls to send the product again, which it must not.
+    time.Sleep(5 * outboxPollInterval)
+    if extra := syncedEvents(stream); len(extra) != 0 {
+        t.Errorf("got %d more events, want none: %v", len(extra), extra)
+    }
+
+    cancel()
+    if err := <-done; err != nil {
+        t.Fatal(err)
+    }
+}
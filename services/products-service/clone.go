@@ -0,0 +1,105 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// Bounds of CloneProduct's adjust_price_by_percent.
+const (
+    minClonePriceAdjustment = -50
+    maxClonePriceAdjustment = 100
+)
+
+// CloneProduct creates a product from an existing one, with its FAQ and
+// tags. The image URL is shared rather than the image copied. The clone's
+// price is the source's scaled by adjust_price_by_percent, and it waits for
+// review whoever creates it.
+func (s *server) CloneProduct(ctx context.Context, req *pb.CloneProductRequest) (*pb.CloneProductResponse, error) {
+    sourceID, err := strconv.ParseUint(req.SourceProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.SourceProductId)
+    }
+    name := strings.TrimSpace(req.NewName)
+    if name == "" {
+        return nil, status.Error(codes.InvalidArgument, "new_name is required")
+    }
+    if err := validateProductName(name); err != nil {
+        return nil, err
+    }
+    adjustment := req.AdjustPriceByPercent
+    if math.IsNaN(adjustment) || adjustment < minClonePriceAdjustment || adjustment > maxClonePriceAdjustment {
+        return nil, status.Errorf(codes.InvalidArgument, "adjust_price_by_percent must be between %d and %d", minClonePriceAdjustment, maxClonePriceAdjustment)
+    }
+
+    var clone Product
+    err = s.inRequestTransaction(ctx, func(tx *gorm.DB) error {
+        var source Product
+        if err := tx.First(&source, sourceID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.SourceProductId)
+            }
+            return err
+        }
+        if name == source.Name {
+            return status.Error(codes.InvalidArgument, "new_name must differ from the source product's name")
+        }
+        cents := int64(math.Round(float64(centsFromPrice(source.Price)) * (100 + adjustment) / 100))
+        if cents > maxPriceCents {
+            return status.Errorf(codes.InvalidArgument, "the adjusted price of %d cents exceeds the maximum of %d", cents, maxPriceCents)
+        }
+        clone = Product{
+            Name:         name,
+            Price:        priceFromCents(cents),
+            PriceCents:   &cents,
+            Description:  source.Description,
+            Brand:        source.Brand,
+            ImageURL:     source.ImageURL,
+            Status:       productStatusActive,
+            ReviewStatus: reviewStatusPending,
+            CreatedBy:    actorFromContext(ctx),
+        }
+        if err := tx.Create(&clone).Error; err != nil {
+            return err
+        }
+        err := tx.Exec(`
+            INSERT INTO product_tags (product_id, tag_id)
+            SELECT ?, tag_id FROM product_tags WHERE product_id = ?`, clone.ID, source.ID).Error
+        if err != nil {
+            return err
+        }
+        err = tx.Exec(`
+            INSERT INTO product_faqs (product_id, question, answer, order_index, created_by, created_at)
+            SELECT ?, question, answer, order_index, ?, now() FROM product_faqs WHERE product_id = ?`,
+            clone.ID, clone.CreatedBy, source.ID).Error
+        if err != nil {
+            return err
+        }
+        if _, err := recordProductVersion(ctx, tx, &clone, firstVersion); err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "clone", "product", clone.ID, map[string]interface{}{
+            "source_product_id":       source.ID,
+            "name":                    name,
+            "price_cents":             cents,
+            "adjust_price_by_percent": adjustment,
+        }); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_CREATED, &clone)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.CloneProductResponse{NewProductId: fmt.Sprint(clone.ID)}, nil
+}
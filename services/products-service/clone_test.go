@@ -0,0 +1,152 @@
+package main
+
+import (
+    "context"
+    "math"
+    "slices"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+func TestCloneProductRejectsBadRequests(t *testing.T) {
+    // No statement is expected: these are rejected before the source is read.
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for _, req := range []*pb.CloneProductRequest{
+        {SourceProductId: "x", NewName: "Big Mug"},
+        {SourceProductId: "7", NewName: "  "},
+        {SourceProductId: "7", NewName: "Big Mug", AdjustPriceByPercent: -50.5},
+        {SourceProductId: "7", NewName: "Big Mug", AdjustPriceByPercent: 101},
+        {SourceProductId: "7", NewName: "Big Mug", AdjustPriceByPercent: math.NaN()},
+    } {
+        if _, err := s.CloneProduct(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("CloneProduct(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestCloneProductNeedsANewName(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Mug", 20))
+    mock.ExpectRollback()
+
+    _, err := s.CloneProduct(context.Background(), &pb.CloneProductRequest{SourceProductId: "7", NewName: " Mug "})
+    if status.Code(err) != codes.InvalidArgument {
+        t.Errorf("err = %v, want InvalidArgument", err)
+    }
+}
+
+func TestCloneProductWritesOneTransaction(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Mug", 19.99))
+    // 19.99 raised by 12.5% is 22.48875, rounded to 22.49.
+    mock.ExpectQuery(`INSERT INTO "products"`).
+        WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "Big Mug", 22.49, 2249, productStatusActive, "", "", "", reviewStatusPending, "anonymous", sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+    mock.ExpectExec(`INSERT INTO product_tags \(product_id, tag_id\)\s+SELECT \$1, tag_id FROM product_tags WHERE product_id = \$2`).
+        WithArgs(8, 7).
+        WillReturnResult(sqlmock.NewResult(0, 2))
+    mock.ExpectExec(`INSERT INTO product_faqs .* FROM product_faqs WHERE product_id = \$3`).
+        WithArgs(8, "anonymous", 7).
+        WillReturnResult(sqlmock.NewResult(0, 3))
+    expectProductVersion(mock)
+    expectAudit(mock, "clone", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int64(pb.ProductEventType_PRODUCT_CREATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.CloneProduct(context.Background(), &pb.CloneProductRequest{SourceProductId: "7", NewName: "Big Mug", AdjustPriceByPercent: 12.5})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.NewProductId != "8" {
+        t.Errorf("new product id = %s, want 8", res.NewProductId)
+    }
+}
+
+// TestCloneProductWithDatabase clones a product with tags and FAQ entries
+// and checks every copy.
+func TestCloneProductWithDatabase(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &ProductVersion{}, &ProductFAQ{}, &Tag{}, &ProductTag{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db}
+    ctx := context.Background()
+    source := Product{Name: "Mug", Price: 12.5, Description: "A stoneware mug.", Brand: "Druk", ImageURL: "https://images.example.com/mug.png", Status: productStatusActive, ReviewStatus: reviewStatusApproved}
+    if err := db.Create(&source).Error; err != nil {
+        t.Fatal(err)
+    }
+    sourceID := source.toProto().Id
+    if _, err := s.SetProductTags(ctx, &pb.SetProductTagsRequest{ProductId: sourceID, Tags: []string{"kitchen", "sale"}}); err != nil {
+        t.Fatal(err)
+    }
+    questions := []string{"Is it dishwasher safe?", "Is it microwave safe?", "How big is it?"}
+    for _, question := range questions {
+        if _, err := s.AddProductFAQ(ctx, &pb.AddProductFAQRequest{ProductId: sourceID, Question: question, Answer: "Yes."}); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    res, err := s.CloneProduct(ctx, &pb.CloneProductRequest{SourceProductId: sourceID, NewName: "Travel Mug", AdjustPriceByPercent: -10})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var clone Product
+    if err := db.First(&clone, res.NewProductId).Error; err != nil {
+        t.Fatal(err)
+    }
+    if clone.Name != "Travel Mug" || clone.Price != 11.25 || clone.PriceCents == nil || *clone.PriceCents != 1125 {
+        t.Errorf("clone is %q at %v, want Travel Mug at 11.25", clone.Name, clone.Price)
+    }
+    if clone.Description != source.Description || clone.Brand != source.Brand || clone.ImageURL != source.ImageURL {
+        t.Errorf("clone has %q, %q, %q, want the source's description, brand and image URL", clone.Description, clone.Brand, clone.ImageURL)
+    }
+    if clone.ReviewStatus != reviewStatusPending {
+        t.Errorf("clone review status = %s, want pending", clone.ReviewStatus)
+    }
+
+    stream := &faqStream{}
+    if err := s.ListProductFAQs(&pb.ListProductFAQsRequest{ProductId: res.NewProductId}, stream); err != nil {
+        t.Fatal(err)
+    }
+    var cloned []string
+    for _, faq := range stream.faqs {
+        cloned = append(cloned, faq.Question)
+    }
+    if !slices.Equal(cloned, questions) {
+        t.Errorf("clone's FAQ = %v, want %v", cloned, questions)
+    }
+
+    var slugs []string
+    err = db.Table("tags").Joins("JOIN product_tags ON product_tags.tag_id = tags.id").
+        Where("product_tags.product_id = ?", clone.ID).Order("slug").Pluck("slug", &slugs).Error
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !slices.Equal(slugs, []string{"kitchen", "sale"}) {
+        t.Errorf("clone's tags = %v, want kitchen and sale", slugs)
+    }
+
+    // The source is untouched.
+    var faqs int64
+    if err := db.Model(&ProductFAQ{}).Where("product_id = ?", source.ID).Count(&faqs).Error; err != nil || faqs != 3 {
+        t.Errorf("source has %d FAQ entries (%v), want 3", faqs, err)
+    }
+    var versions int64
+    if err := db.Model(&ProductVersion{}).Where("product_id = ?", clone.ID).Count(&versions).Error; err != nil || versions != 1 {
+        t.Errorf("clone has %d versions (%v), want its first", versions, err)
+    }
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// ProductCost is what the supplier charges for a product from EffectiveFrom
+// until EffectiveTo, or indefinitely while EffectiveTo is nil. A product's
+// costs do not overlap: each runs until the next one takes effect.
+type ProductCost struct {
+    ID                uint      `gorm:"primaryKey"`
+    ProductID         uint      `gorm:"not null;uniqueIndex:idx_product_costs_effective_from,priority:1"`
+    SupplierCostCents int64     `gorm:"not null"`
+    Currency          string    `gorm:"type:char(3);not null"`
+    EffectiveFrom     time.Time `gorm:"not null;uniqueIndex:idx_product_costs_effective_from,priority:2"`
+    EffectiveTo       *time.Time
+    CreatedBy         string `gorm:"not null"`
+    CreatedAt         time.Time
+}
+
+func (c *ProductCost) toProto() *pb.ProductCost {
+    cost := &pb.ProductCost{
+        Id:            fmt.Sprint(c.ID),
+        ProductId:     fmt.Sprint(c.ProductID),
+        SupplierCost:  &pb.Money{CurrencyCode: c.Currency, Amount: priceFromCents(c.SupplierCostCents)},
+        EffectiveFrom: timestamppb.New(c.EffectiveFrom),
+        CreatedBy:     c.CreatedBy,
+        CreatedAt:     timestamppb.New(c.CreatedAt),
+    }
+    if c.EffectiveTo != nil {
+        cost.EffectiveTo = timestamppb.New(*c.EffectiveTo)
+    }
+    return cost
+}
+
+// findCostAt returns the product's cost effective at, or NotFound if there
+// is none then.
+func findCostAt(tx *gorm.DB, productID uint, at time.Time) (*ProductCost, error) {
+    var cost ProductCost
+    err := tx.Where("product_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", productID, at, at).
+        Take(&cost).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, status.Errorf(codes.NotFound, "product %d has no cost effective at %s", productID, at.Format(time.RFC3339))
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &cost, nil
+}
+
+// salePriceAt returns the product's price at, from its versions. Before its
+// first version the product had the price that version recorded, and a
+// product that was never versioned has always had its current price.
+func salePriceAt(tx *gorm.DB, product *Product, at time.Time) (float64, error) {
+    var version ProductVersion
+    err := tx.Where("product_id = ? AND created_at <= ?", product.ID, at).
+        Order("created_at DESC, " + newestVersionsFirst).
+        Take(&version).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        err = tx.Where("product_id = ?", product.ID).Order("created_at, major, minor, patch").Take(&version).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return product.Price, nil
+        }
+    }
+    if err != nil {
+        return 0, err
+    }
+    return version.Price, nil
+}
+
+// SetProductCost records the supplier cost of a product from effective_from,
+// which defaults to now. The cost runs until the product's next recorded
+// cost takes effect, and the cost before it now ends at effective_from.
+func (s *server) SetProductCost(ctx context.Context, req *pb.SetProductCostRequest) (*pb.SetProductCostResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    if req.Cost == nil || req.Cost.Amount < 0 {
+        return nil, status.Error(codes.InvalidArgument, "cost must not be negative")
+    }
+    if req.Cost.CurrencyCode != "" && req.Cost.CurrencyCode != defaultCurrency {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported currency %q", req.Cost.CurrencyCode)
+    }
+    costCents, err := v1PriceCents(req.Cost.Amount)
+    if err != nil {
+        return nil, err
+    }
+    cost := ProductCost{
+        ProductID:         uint(productID),
+        SupplierCostCents: costCents,
+        Currency:          defaultCurrency,
+        EffectiveFrom:     time.Now(),
+        CreatedBy:         actorFromContext(ctx),
+    }
+    if req.EffectiveFrom != nil {
+        cost.EffectiveFrom = req.EffectiveFrom.AsTime()
+    }
+
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        // Locking the product serializes changes to its cost periods.
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&Product{}, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        var existing int64
+        if err := tx.Model(&ProductCost{}).Where("product_id = ? AND effective_from = ?", productID, cost.EffectiveFrom).Count(&existing).Error; err != nil {
+            return err
+        }
+        if existing > 0 {
+            return status.Errorf(codes.AlreadyExists, "product %s already has a cost effective from %s", req.ProductId, cost.EffectiveFrom.Format(time.RFC3339))
+        }
+
+        var next ProductCost
+        err := tx.Where("product_id = ? AND effective_from > ?", productID, cost.EffectiveFrom).Order("effective_from").Take(&next).Error
+        if err == nil {
+            cost.EffectiveTo = &next.EffectiveFrom
+        } else if !errors.Is(err, gorm.ErrRecordNotFound) {
+            return err
+        }
+        err = tx.Model(&ProductCost{}).
+            Where("product_id = ? AND effective_from < ? AND (effective_to IS NULL OR effective_to > ?)", productID, cost.EffectiveFrom, cost.EffectiveFrom).
+            Update("effective_to", cost.EffectiveFrom).Error
+        if err != nil {
+            return err
+        }
+        if err := tx.Create(&cost).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "set_cost", "product", productID, map[string]interface{}{
+            "cost_cents":     cost.SupplierCostCents,
+            "effective_from": cost.EffectiveFrom,
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.SetProductCostResponse{Cost: cost.toProto()}, nil
+}
+
+// GetProductMargin returns the product's gross margin at a time, which
+// defaults to now, using the cost effective and the price the product had
+// then. The percentage is 0 for a free product.
+func (s *server) GetProductMargin(ctx context.Context, req *pb.GetProductMarginRequest) (*pb.GetProductMarginResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    db := s.db.WithContext(ctx)
+    var product Product
+    if err := db.First(&product, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return nil, err
+    }
+
+    at := time.Now()
+    salePrice := product.Price
+    if req.At != nil {
+        at = req.At.AsTime()
+        if salePrice, err = salePriceAt(db, &product, at); err != nil {
+            return nil, err
+        }
+    }
+    cost, err := findCostAt(db, product.ID, at)
+    if err != nil {
+        return nil, err
+    }
+
+    saleCents := centsFromPrice(salePrice)
+    marginCents := saleCents - cost.SupplierCostCents
+    res := &pb.GetProductMarginResponse{
+        SalePrice:   money(priceFromCents(saleCents)),
+        Cost:        money(priceFromCents(cost.SupplierCostCents)),
+        GrossMargin: priceFromCents(marginCents),
+    }
+    if saleCents != 0 {
+        res.GrossMarginPercent = float64(marginCents) / float64(saleCents) * 100
+    }
+    return res, nil
+}
+
+// GetProductCostHistory streams every cost recorded for a product, oldest
+// first.
+func (s *server) GetProductCostHistory(req *pb.GetProductCostHistoryRequest, stream pb.ProductService_GetProductCostHistoryServer) error {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    var costs []ProductCost
+    if err := s.db.WithContext(stream.Context()).Where("product_id = ?", productID).Order("effective_from").Find(&costs).Error; err != nil {
+        return err
+    }
+    for i := range costs {
+        if err := stream.Send(&pb.ProductCostResponse{Cost: costs[i].toProto()}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
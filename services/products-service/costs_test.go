@@ -0,0 +1,193 @@
+package main
+
+import (
+    "context"
+    "math"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+    "shared/fakedata"
+)
+
+func costRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "product_id", "supplier_cost_cents", "currency", "effective_from", "effective_to", "created_by", "created_at"})
+}
+
+func TestSetProductCostRejectsBadRequests(t *testing.T) {
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for _, req := range []*pb.SetProductCostRequest{
+        {ProductId: "x", Cost: money(1)},
+        {ProductId: "1"},
+        {ProductId: "1", Cost: money(-1)},
+        {ProductId: "1", Cost: &pb.Money{Amount: math.NaN()}},
+        {ProductId: "1", Cost: &pb.Money{Amount: 1.999}},
+        {ProductId: "1", Cost: &pb.Money{CurrencyCode: "EUR", Amount: 1}},
+    } {
+        if _, err := s.SetProductCost(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("SetProductCost(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestSetProductCostEndsAtTheNextCost(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+    mar := feb.AddDate(0, 1, 0)
+
+    // A cost is backdated between one from January, which it now ends, and
+    // one from March, where it ends itself.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id" FROM "products" .* FOR UPDATE`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "product_costs" WHERE product_id = \$1 AND effective_from = \$2`).
+        WithArgs(7, feb).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+    mock.ExpectQuery(`SELECT \* FROM "product_costs" WHERE product_id = \$1 AND effective_from > \$2 ORDER BY effective_from`).
+        WithArgs(7, feb).
+        WillReturnRows(costRows().AddRow(3, 7, 900, "USD", mar, nil, "key:abc", mar))
+    mock.ExpectExec(`UPDATE "product_costs" SET "effective_to"=\$1 WHERE product_id = \$2 AND effective_from < \$3 AND \(effective_to IS NULL OR effective_to > \$4\)`).
+        WithArgs(feb, 7, feb, feb).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`INSERT INTO "product_costs"`).
+        WithArgs(7, 1050, "USD", feb, mar, sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+    expectAudit(mock, "set_cost", "product")
+    mock.ExpectCommit()
+
+    res, err := s.SetProductCost(context.Background(), &pb.SetProductCostRequest{ProductId: "7", Cost: money(10.5), EffectiveFrom: timestamppb.New(feb)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !res.Cost.EffectiveTo.AsTime().Equal(mar) || res.Cost.SupplierCost.Amount != 10.5 {
+        t.Errorf("cost = %v, want 10.5 until %v", res.Cost, mar)
+    }
+}
+
+func TestSetProductCostRejectsASecondCostAtTheSameTime(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "product_costs"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+    mock.ExpectRollback()
+
+    _, err := s.SetProductCost(context.Background(), &pb.SetProductCostRequest{ProductId: "7", Cost: money(10), EffectiveFrom: timestamppb.Now()})
+    if status.Code(err) != codes.AlreadyExists {
+        t.Errorf("err = %v, want AlreadyExists", err)
+    }
+}
+
+func TestGetProductMargin(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Mug", 20))
+    mock.ExpectQuery(`SELECT \* FROM "product_costs" WHERE product_id = \$1 AND effective_from <= \$2 AND \(effective_to IS NULL OR effective_to > \$3\)`).
+        WillReturnRows(costRows().AddRow(3, 7, 1250, "USD", time.Now().Add(-time.Hour), nil, "key:abc", time.Now()))
+
+    res, err := s.GetProductMargin(context.Background(), &pb.GetProductMarginRequest{ProductId: "7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.SalePrice.Amount != 20 || res.Cost.Amount != 12.5 || res.GrossMargin != 7.5 || res.GrossMarginPercent != 37.5 {
+        t.Errorf("margin = %v, want 20 - 12.5 = 7.5 (37.5%%)", res)
+    }
+}
+
+func TestGetProductMarginWithoutACost(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Mug", 20))
+    mock.ExpectQuery(`SELECT \* FROM "product_costs"`).WillReturnRows(costRows())
+
+    if _, err := s.GetProductMargin(context.Background(), &pb.GetProductMarginRequest{ProductId: "7"}); status.Code(err) != codes.NotFound {
+        t.Errorf("err = %v, want NotFound", err)
+    }
+}
+
+// TestCostEffectivity records costs out of order and checks which one, and
+// which sale price, each point in time gets.
+func TestCostEffectivity(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &ProductVersion{}, &ProductCost{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db, recent: newRecentCreates(time.Second)}
+    ctx := context.Background()
+    created, err := s.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t), fakedata.WithPrice(20)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    id := created.Product.Id
+
+    jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+    feb, mar := jan.AddDate(0, 1, 0), jan.AddDate(0, 2, 0)
+    for _, c := range []struct {
+        from time.Time
+        cost float64
+    }{{jan, 10}, {mar, 14}, {feb, 12}} {
+        if _, err := s.SetProductCost(ctx, &pb.SetProductCostRequest{ProductId: id, Cost: money(c.cost), EffectiveFrom: timestamppb.New(c.from)}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    _, err = s.SetProductCost(ctx, &pb.SetProductCostRequest{ProductId: id, Cost: money(11), EffectiveFrom: timestamppb.New(feb)})
+    if status.Code(err) != codes.AlreadyExists {
+        t.Errorf("second cost from February: err = %v, want AlreadyExists", err)
+    }
+
+    // The periods chain without overlapping, oldest first.
+    stream := newFakeServerStream[pb.ProductCostResponse](ctx)
+    if err := s.GetProductCostHistory(&pb.GetProductCostHistoryRequest{ProductId: id}, stream); err != nil {
+        t.Fatal(err)
+    }
+    wantPeriods := []struct {
+        from, to time.Time
+        cost     float64
+    }{{jan, feb, 10}, {feb, mar, 12}, {mar, time.Time{}, 14}}
+    for i, want := range wantPeriods {
+        got := stream.next(t).Cost
+        to := time.Time{}
+        if got.EffectiveTo != nil {
+            to = got.EffectiveTo.AsTime()
+        }
+        if !got.EffectiveFrom.AsTime().Equal(want.from) || !to.Equal(want.to) || got.SupplierCost.Amount != want.cost {
+            t.Errorf("cost %d = %v from %v to %v, want %v from %v to %v", i+1, got.SupplierCost.Amount, got.EffectiveFrom.AsTime(), to, want.cost, want.from, want.to)
+        }
+    }
+
+    // A price change is reflected from when it was made, and the margin at
+    // a boundary uses the cost that starts there.
+    beforeChange := time.Now()
+    price := 24.0
+    if _, err := s.UpdateProduct(ctx, &pb.UpdateProductRequest{Id: id, Price: &price}); err != nil {
+        t.Fatal(err)
+    }
+    for _, c := range []struct {
+        at         time.Time
+        sale, cost float64
+    }{
+        {jan.Add(time.Hour), 20, 10},
+        {feb, 20, 12},
+        {feb.Add(-time.Microsecond), 20, 10},
+        {beforeChange, 20, 14},
+        {time.Now(), 24, 14},
+    } {
+        res, err := s.GetProductMargin(ctx, &pb.GetProductMarginRequest{ProductId: id, At: timestamppb.New(c.at)})
+        if err != nil {
+            t.Fatalf("margin at %v: %v", c.at, err)
+        }
+        if res.SalePrice.Amount != c.sale || res.Cost.Amount != c.cost || res.GrossMargin != c.sale-c.cost {
+            t.Errorf("margin at %v = %v, want %v - %v", c.at, res, c.sale, c.cost)
+        }
+    }
+    if _, err := s.GetProductMargin(ctx, &pb.GetProductMarginRequest{ProductId: id, At: timestamppb.New(jan.Add(-time.Hour))}); status.Code(err) != codes.NotFound {
+        t.Errorf("margin before the first cost: err = %v, want NotFound", err)
+    }
+}
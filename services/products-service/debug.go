@@ -0,0 +1,65 @@
+package main
+
+import (
+    "context"
+    "strconv"
+    "time"
+
+    consulapi "github.com/hashicorp/consul/api"
+    "google.golang.org/protobuf/types/known/durationpb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/canary"
+    "shared/debug"
+)
+
+// debugConsulTimeout bounds GetDebugInfo's registration check, so an
+// unreachable Consul agent does not hold up the rest of the report.
+const debugConsulTimeout = 2 * time.Second
+
+// debugServer reports the runtime state of this instance for diagnosing
+// incidents.
+type debugServer struct {
+    pb.UnimplementedDebugServiceServer
+    db     *gorm.DB
+    consul *consulapi.Client
+    // flags are the settings that switch behaviour on or off, by name.
+    flags  map[string]bool
+    canary canary.Config
+}
+
+// GetDebugInfo reports the goroutine count, heap usage, connection pool,
+// Consul registration and switches of this instance.
+func (d *debugServer) GetDebugInfo(ctx context.Context, req *pb.GetDebugInfoRequest) (*pb.GetDebugInfoResponse, error) {
+    info, err := debug.Read(d.db)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.GetDebugInfoResponse{
+        GoroutineCount: int32(info.Goroutines),
+        MemoryAllocMb:  info.MemoryAllocMB,
+        UptimeSeconds:  int64(info.Uptime.Seconds()),
+        GoVersion:      info.GoVersion,
+        FeatureFlags:   make(map[string]string, len(d.flags)),
+        DbPoolStats: &pb.DBPoolStats{
+            MaxOpenConnections: int32(info.DBPool.MaxOpenConnections),
+            OpenConnections:    int32(info.DBPool.OpenConnections),
+            InUse:              int32(info.DBPool.InUse),
+            Idle:               int32(info.DBPool.Idle),
+            WaitCount:          info.DBPool.WaitCount,
+            WaitDuration:       durationpb.New(info.DBPool.WaitDuration),
+        },
+    }
+    for name, on := range d.flags {
+        res.FeatureFlags[name] = strconv.FormatBool(on)
+    }
+
+    // An error means the agent could not be asked, which is reported as not
+    // registered: nothing can discover the instance through it either.
+    ctx, cancel := context.WithTimeout(ctx, debugConsulTimeout)
+    defer cancel()
+    service, _, err := d.consul.Agent().Service(instanceID(), (&consulapi.QueryOptions{}).WithContext(ctx))
+    res.ConsulRegistered = err == nil && service != nil
+    return res, nil
+}
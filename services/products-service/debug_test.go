@@ -0,0 +1,82 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    consulapi "github.com/hashicorp/consul/api"
+    "google.golang.org/protobuf/proto"
+
+    pb "products-service/proto/gen/proto"
+    "shared/canary"
+)
+
+// fakeConsulAgent serves the Consul agent's service endpoint, knowing only
+// the services in registered.
+func fakeConsulAgent(t *testing.T, registered ...string) *consulapi.Client {
+    t.Helper()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        for _, id := range registered {
+            if r.URL.Path == "/v1/agent/service/"+id {
+                json.NewEncoder(w).Encode(consulapi.AgentService{ID: id, Service: serviceName})
+                return
+            }
+        }
+        http.NotFound(w, r)
+    }))
+    t.Cleanup(srv.Close)
+    client, err := consulapi.NewClient(&consulapi.Config{Address: srv.URL})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return client
+}
+
+func TestGetDebugInfo(t *testing.T) {
+    db, _ := newMockDB(t)
+    d := &debugServer{db: db, consul: fakeConsulAgent(t, instanceID()), flags: map[string]bool{"redis": true, "allow_restore": false}}
+
+    res, err := d.GetDebugInfo(context.Background(), &pb.GetDebugInfoRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.GoroutineCount < 1 {
+        t.Errorf("goroutine count = %d, want at least 1", res.GoroutineCount)
+    }
+    if !res.ConsulRegistered {
+        t.Error("registered instance reported as not registered")
+    }
+    if res.DbPoolStats == nil || res.GoVersion == "" {
+        t.Errorf("pool stats %v and Go version %q, want both set", res.DbPoolStats, res.GoVersion)
+    }
+    if res.FeatureFlags["redis"] != "true" || res.FeatureFlags["allow_restore"] != "false" {
+        t.Errorf("feature flags = %v", res.FeatureFlags)
+    }
+
+    d.consul = fakeConsulAgent(t)
+    if res, err := d.GetDebugInfo(context.Background(), &pb.GetDebugInfoRequest{}); err != nil || res.ConsulRegistered {
+        t.Errorf("unregistered instance: registered = %v (%v), want false", res.GetConsulRegistered(), err)
+    }
+}
+
+func TestGetDeploymentInfo(t *testing.T) {
+    t.Setenv("SERVICE_VERSION", "1.4.2")
+    for _, tt := range []struct {
+        canary canary.Config
+        want   *pb.GetDeploymentInfoResponse
+    }{
+        {canary.Config{Weight: canary.DefaultWeight}, &pb.GetDeploymentInfoResponse{Version: "1.4.2"}},
+        {canary.Config{IsCanary: true, Weight: 25}, &pb.GetDeploymentInfoResponse{IsCanary: true, Weight: 25, Version: "1.4.2"}},
+    } {
+        res, err := (&debugServer{canary: tt.canary}).GetDeploymentInfo(context.Background(), &pb.GetDeploymentInfoRequest{})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if !proto.Equal(res, tt.want) {
+            t.Errorf("%+v: got %v, want %v", tt.canary, res, tt.want)
+        }
+    }
+}
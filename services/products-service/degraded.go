@@ -0,0 +1,90 @@
+package main
+
+import (
+    "log"
+    "slices"
+    "sync"
+    "time"
+
+    consulapi "github.com/hashicorp/consul/api"
+
+    pb "products-service/proto/gen/proto"
+    "shared/canary"
+)
+
+// degradedTagPrefix marks a Consul tag naming why an instance is degraded.
+const degradedTagPrefix = "degraded:"
+
+// defaultDBSlowThreshold is used when DB_SLOW_THRESHOLD is not set.
+const defaultDBSlowThreshold = 500 * time.Millisecond
+
+// minDegradationUpdateInterval rate-limits re-registrations with the Consul
+// agent. A change made sooner is picked up by a later self-test run.
+const minDegradationUpdateInterval = 30 * time.Second
+
+// degradationReporter advertises in Consul that this instance works but
+// slowly or without a soft dependency. Degraded instances stay in rotation;
+// the gateway only prefers other instances over them.
+type degradationReporter struct {
+    consul      *consulapi.Client
+    canary      canary.Config
+    dbSlow      time.Duration
+    minInterval time.Duration
+
+    mu          sync.Mutex
+    current     []string
+    lastUpdated time.Time
+}
+
+func newDegradationReporter(consul *consulapi.Client, canaryConfig canary.Config) *degradationReporter {
+    return &degradationReporter{
+        consul:      consul,
+        canary:      canaryConfig,
+        dbSlow:      getEnvDuration("DB_SLOW_THRESHOLD", defaultDBSlowThreshold),
+        minInterval: minDegradationUpdateInterval,
+    }
+}
+
+// reasons derives degradation reasons from a self-test result. A failing
+// database makes the instance unhealthy rather than degraded, and is left to
+// the health check.
+func (d *degradationReporter) reasons(res *pb.SelfTestResponse) []string {
+    var reasons []string
+    for _, check := range res.Checks {
+        switch {
+        case check.Name == "database" && check.Ok && check.Latency.AsDuration() > d.dbSlow:
+            reasons = append(reasons, "db-slow")
+        case check.Name == "redis" && !check.Ok:
+            reasons = append(reasons, "redis-down")
+        }
+    }
+    return reasons
+}
+
+// registration describes the instance with its current degradation reasons.
+func (d *degradationReporter) registration() *consulapi.AgentServiceRegistration {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return serviceRegistration(d.current, d.canary)
+}
+
+// update re-registers the instance if its degradation reasons changed and
+// the last re-registration was long enough ago.
+func (d *degradationReporter) update(reasons []string) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if slices.Equal(reasons, d.current) || time.Since(d.lastUpdated) < d.minInterval {
+        return
+    }
+    if err := d.consul.Agent().ServiceRegister(serviceRegistration(reasons, d.canary)); err != nil {
+        log.Printf("Failed to update degradation tags in Consul: %v", err)
+        return
+    }
+    if len(reasons) > 0 {
+        log.Printf("Marked %s degraded in Consul: %v", serviceName, reasons)
+    } else {
+        log.Printf("Cleared %s degradation in Consul", serviceName)
+    }
+    d.current = reasons
+    d.lastUpdated = time.Now()
+}
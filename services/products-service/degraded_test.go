@@ -0,0 +1,90 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+
+    consulapi "github.com/hashicorp/consul/api"
+    "google.golang.org/protobuf/types/known/durationpb"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// recordRegistrations serves the Consul agent's register endpoint and
+// records the tags of each registration.
+func recordRegistrations(t *testing.T) (*consulapi.Client, func() [][]string) {
+    t.Helper()
+    var mu sync.Mutex
+    var tags [][]string
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var registration consulapi.AgentServiceRegistration
+        if r.URL.Path != "/v1/agent/service/register" || json.NewDecoder(r.Body).Decode(&registration) != nil {
+            http.Error(w, "unexpected request", http.StatusBadRequest)
+            return
+        }
+        mu.Lock()
+        tags = append(tags, registration.Tags)
+        mu.Unlock()
+    }))
+    t.Cleanup(srv.Close)
+    client, err := consulapi.NewClient(&consulapi.Config{Address: srv.URL})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return client, func() [][]string {
+        mu.Lock()
+        defer mu.Unlock()
+        return append([][]string(nil), tags...)
+    }
+}
+
+func TestDegradationReasons(t *testing.T) {
+    d := &degradationReporter{dbSlow: 500 * time.Millisecond}
+    tests := []struct {
+        database, redis bool
+        latency         time.Duration
+        want            []string
+    }{
+        {true, true, 10 * time.Millisecond, nil},
+        {true, true, time.Second, []string{"db-slow"}},
+        {true, false, time.Second, []string{"db-slow", "redis-down"}},
+        // A broken database is unhealthy, not degraded.
+        {false, true, time.Second, nil},
+    }
+    for _, tt := range tests {
+        res := &pb.SelfTestResponse{Checks: []*pb.SelfTestCheck{
+            {Name: "database", Ok: tt.database, Latency: durationpb.New(tt.latency)},
+            {Name: "consul", Ok: true},
+            {Name: "redis", Ok: tt.redis},
+        }}
+        if got := d.reasons(res); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("reasons(database %v in %v, redis %v) = %v, want %v", tt.database, tt.latency, tt.redis, got, tt.want)
+        }
+    }
+}
+
+func TestDegradationUpdatesAreRateLimited(t *testing.T) {
+    consul, registrations := recordRegistrations(t)
+    d := &degradationReporter{consul: consul, minInterval: time.Hour}
+
+    d.update([]string{"db-slow"})
+    d.update([]string{"db-slow"})
+    // Too soon after the last update: left for a later run.
+    d.update(nil)
+    want := [][]string{{"degraded:db-slow"}}
+    if got := registrations(); !reflect.DeepEqual(got, want) {
+        t.Fatalf("registered tags %v, want %v", got, want)
+    }
+
+    d.lastUpdated = time.Now().Add(-2 * time.Hour)
+    d.update(nil)
+    want = append(want, nil)
+    if got := registrations(); !reflect.DeepEqual(got, want) {
+        t.Errorf("registered tags %v, want %v", got, want)
+    }
+}
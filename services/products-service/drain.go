@@ -0,0 +1,40 @@
+package main
+
+import (
+    "context"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/durationpb"
+
+    pb "products-service/proto/gen/proto"
+    "shared/drain"
+)
+
+// drainServer takes the instance out of rotation ahead of a deploy without
+// stopping the process.
+type drainServer struct {
+    pb.UnimplementedDrainServiceServer
+    health  *health.Server
+    limiter *concurrencyLimiter
+}
+
+// Drain marks every service NOT_SERVING, so Consul stops routing to this
+// instance, then waits for in-flight requests to finish or the timeout to
+// pass. Draining cannot be undone; the instance is expected to be stopped.
+func (d *drainServer) Drain(ctx context.Context, req *pb.DrainRequest) (*pb.DrainResponse, error) {
+    timeout := drain.DefaultTimeout
+    if req.Timeout != nil {
+        if err := req.Timeout.CheckValid(); err != nil || req.Timeout.AsDuration() <= 0 {
+            return nil, status.Error(codes.InvalidArgument, "timeout must be a positive duration")
+        }
+        timeout = req.Timeout.AsDuration()
+    }
+
+    res, err := drain.Drain(ctx, serviceName, d.health, d.limiter.inFlight, timeout)
+    if err != nil {
+        return nil, status.FromContextError(err).Err()
+    }
+    return &pb.DrainResponse{Idle: res.Idle, InFlight: int32(res.InFlight), Waited: durationpb.New(res.Waited)}, nil
+}
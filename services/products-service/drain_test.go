@@ -0,0 +1,42 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/protobuf/types/known/durationpb"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func servingStatus(t *testing.T, h *health.Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+    t.Helper()
+    res, err := h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return res.Status
+}
+
+func TestDrainTimesOut(t *testing.T) {
+    d := &drainServer{health: health.NewServer(), limiter: newConcurrencyLimiter(4)}
+    d.limiter.acquire()
+    d.limiter.acquire()
+    defer d.limiter.release()
+    defer d.limiter.release()
+
+    res, err := d.Drain(context.Background(), &pb.DrainRequest{Timeout: durationpb.New(200 * time.Millisecond)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Idle || res.InFlight != 2 {
+        t.Errorf("idle = %v with %d in flight, want 2 requests still running", res.Idle, res.InFlight)
+    }
+
+    if _, err := d.Drain(context.Background(), &pb.DrainRequest{Timeout: durationpb.New(-time.Second)}); err == nil {
+        t.Error("Drain with a negative timeout succeeded")
+    }
+}
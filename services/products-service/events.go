@@ -0,0 +1,140 @@
+package main
+
+import (
+    "sync"
+
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// watchBufferSize bounds how many events may queue up for a single watcher
+// before new events are dropped for it.
+const watchBufferSize = 64
+
+// eventHub fans the product change events relayed from the outbox out to
+// in-process subscribers. Publishing never blocks: a subscriber whose buffer
+// is full misses the event and is told how many it missed.
+type eventHub struct {
+    source string
+
+    mu          sync.Mutex
+    subscribers map[*subscription]struct{}
+}
+
+type subscription struct {
+    events  chan *pb.ProductEvent
+    dropped chan struct{}
+
+    mu           sync.Mutex
+    droppedCount int64
+}
+
+func newEventHub(source string) *eventHub {
+    return &eventHub{source: source, subscribers: make(map[*subscription]struct{})}
+}
+
+func (h *eventHub) subscribe() *subscription {
+    sub := &subscription{
+        events:  make(chan *pb.ProductEvent, watchBufferSize),
+        dropped: make(chan struct{}, 1),
+    }
+    h.mu.Lock()
+    h.subscribers[sub] = struct{}{}
+    h.mu.Unlock()
+    return sub
+}
+
+func (h *eventHub) unsubscribe(sub *subscription) {
+    h.mu.Lock()
+    delete(h.subscribers, sub)
+    h.mu.Unlock()
+}
+
+func (h *eventHub) publish(event *pb.ProductEvent) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    for sub := range h.subscribers {
+        select {
+        case sub.events <- event:
+        default:
+            sub.markDropped()
+        }
+    }
+}
+
+func (s *subscription) markDropped() {
+    s.mu.Lock()
+    s.droppedCount++
+    s.mu.Unlock()
+
+    select {
+    case s.dropped <- struct{}{}:
+    default:
+    }
+}
+
+// takeDropped returns and resets the number of events dropped since the last
+// call.
+func (s *subscription) takeDropped() int64 {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    n := s.droppedCount
+    s.droppedCount = 0
+    return n
+}
+
+func (s *server) WatchProducts(req *pb.WatchProductsRequest, stream pb.ProductService_WatchProductsServer) error {
+    sub := s.events.subscribe()
+    defer s.events.unsubscribe(sub)
+
+    for {
+        select {
+        case <-stream.Context().Done():
+            return nil
+        case <-sub.dropped:
+            event := &pb.ProductEvent{
+                Type:         pb.ProductEventType_PRODUCT_EVENTS_DROPPED,
+                DroppedCount: sub.takeDropped(),
+                OccurredAt:   timestamppb.Now(),
+            }
+            if err := stream.Send(event); err != nil {
+                return err
+            }
+        case event := <-sub.events:
+            if err := stream.Send(event); err != nil {
+                return err
+            }
+        }
+    }
+}
+
+// WatchCacheInvalidations streams compact "product:<id>" invalidation messages
+// derived from the product change feed. Their sequence numbers are outbox IDs,
+// so every instance sends the same number for the same change. When events
+// had to be dropped the subscriber is told to flush its whole cache instead.
+func (s *server) WatchCacheInvalidations(req *pb.WatchCacheInvalidationsRequest, stream pb.ProductService_WatchCacheInvalidationsServer) error {
+    sub := s.events.subscribe()
+    defer s.events.unsubscribe(sub)
+
+    for {
+        var msg *pb.CacheInvalidation
+        select {
+        case <-stream.Context().Done():
+            return nil
+        case <-sub.dropped:
+            sub.takeDropped()
+            msg = &pb.CacheInvalidation{FlushAll: true, Source: s.events.source}
+        case event := <-sub.events:
+            msg = &pb.CacheInvalidation{
+                Key:       "product:" + event.Product.GetId(),
+                UpdatedAt: event.Product.GetUpdatedAt(),
+                Sequence:  event.Sequence,
+                Source:    s.events.source,
+            }
+        }
+        if err := stream.Send(msg); err != nil {
+            return err
+        }
+    }
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// fakeServerStream records what a server-streaming handler sends.
+type fakeServerStream[T any] struct {
+    grpc.ServerStream
+    ctx  context.Context
+    sent chan *T
+}
+
+func newFakeServerStream[T any](ctx context.Context) *fakeServerStream[T] {
+    return &fakeServerStream[T]{ctx: ctx, sent: make(chan *T, 16)}
+}
+
+func (s *fakeServerStream[T]) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream[T]) Send(msg *T) error {
+    s.sent <- msg
+    return nil
+}
+
+func (s *fakeServerStream[T]) next(t *testing.T) *T {
+    t.Helper()
+    select {
+    case msg := <-s.sent:
+        return msg
+    case <-time.After(5 * time.Second):
+        t.Fatal("timed out waiting for a message")
+        return nil
+    }
+}
+
+func (h *eventHub) subscriberCount() int {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    return len(h.subscribers)
+}
+
+// waitForSubscribers waits until the hub has n subscribers.
+func waitForSubscribers(t *testing.T, h *eventHub, n int) {
+    t.Helper()
+    deadline := time.Now().Add(5 * time.Second)
+    for h.subscriberCount() != n {
+        if time.Now().After(deadline) {
+            t.Fatalf("hub has %d subscribers, want %d", h.subscriberCount(), n)
+        }
+        time.Sleep(time.Millisecond)
+    }
+}
+
+func TestEventHubDropsForSlowSubscriber(t *testing.T) {
+    hub := newEventHub("test")
+    sub := hub.subscribe()
+    defer hub.unsubscribe(sub)
+
+    for i := 0; i < watchBufferSize+3; i++ {
+        hub.publish(&pb.ProductEvent{Type: pb.ProductEventType_PRODUCT_UPDATED, Product: &pb.Product{Id: "1"}, Sequence: int64(i + 1)})
+    }
+
+    if got := len(sub.events); got != watchBufferSize {
+        t.Errorf("buffered events = %d, want %d", got, watchBufferSize)
+    }
+    select {
+    case <-sub.dropped:
+    default:
+        t.Fatal("subscriber was not signalled about dropped events")
+    }
+    if got := sub.takeDropped(); got != 3 {
+        t.Errorf("takeDropped() = %d, want 3", got)
+    }
+    if got := sub.takeDropped(); got != 0 {
+        t.Errorf("second takeDropped() = %d, want 0", got)
+    }
+    if first := <-sub.events; first.Sequence != 1 {
+        t.Errorf("first event sequence = %d, want 1", first.Sequence)
+    }
+}
+
+func TestEventHubPublishDoesNotBlockOnOtherSubscribers(t *testing.T) {
+    hub := newEventHub("test")
+    slow := hub.subscribe()
+    defer hub.unsubscribe(slow)
+    fast := hub.subscribe()
+    defer hub.unsubscribe(fast)
+
+    for i := 0; i < watchBufferSize*2; i++ {
+        hub.publish(&pb.ProductEvent{Type: pb.ProductEventType_PRODUCT_CREATED, Product: &pb.Product{Id: "1"}})
+        <-fast.events
+    }
+    if got := fast.takeDropped(); got != 0 {
+        t.Errorf("fast subscriber dropped %d events, want 0", got)
+    }
+    if got := slow.takeDropped(); got != watchBufferSize {
+        t.Errorf("slow subscriber dropped %d events, want %d", got, watchBufferSize)
+    }
+}
+
+func TestWatchProductsStreamsEventsAndUnsubscribes(t *testing.T) {
+    s := &server{events: newEventHub("test")}
+    ctx, cancel := context.WithCancel(context.Background())
+    stream := newFakeServerStream[pb.ProductEvent](ctx)
+    done := make(chan error, 1)
+    go func() { done <- s.WatchProducts(&pb.WatchProductsRequest{}, stream) }()
+    waitForSubscribers(t, s.events, 1)
+
+    s.events.publish(&pb.ProductEvent{Type: pb.ProductEventType_PRODUCT_DELETED, Product: &pb.Product{Id: "42"}})
+    event := stream.next(t)
+    if event.Type != pb.ProductEventType_PRODUCT_DELETED || event.Product.GetId() != "42" {
+        t.Errorf("got %v for product %q, want PRODUCT_DELETED for 42", event.Type, event.Product.GetId())
+    }
+
+    cancel()
+    if err := <-done; err != nil {
+        t.Errorf("WatchProducts returned %v after the client went away, want nil", err)
+    }
+    if n := s.events.subscriberCount(); n != 0 {
+        t.Errorf("hub has %d subscribers after disconnect, want 0", n)
+    }
+}
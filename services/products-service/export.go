@@ -0,0 +1,105 @@
+package main
+
+import (
+    "io"
+    "os"
+    "time"
+
+    "github.com/parquet-go/parquet-go"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// exportChunkSize is the size of each ExportChunk sent to the client.
+const exportChunkSize = 1 << 20
+
+// exportBatchSize is how many products are loaded from the database at once
+// while writing an export.
+const exportBatchSize = 500
+
+// productParquetRow mirrors the Product proto message, plus the soft-delete
+// timestamp so analytics pipelines can tell removed products apart.
+type productParquetRow struct {
+    ID        string     `parquet:"id"`
+    Name      string     `parquet:"name"`
+    Price     float64    `parquet:"price"`
+    UpdatedAt time.Time  `parquet:"updated_at"`
+    DeletedAt *time.Time `parquet:"deleted_at,optional"`
+}
+
+func newProductParquetRow(p *Product) productParquetRow {
+    row := productParquetRow{ID: p.toProto().Id, Name: p.Name, Price: p.Price, UpdatedAt: p.UpdatedAt}
+    if p.DeletedAt.Valid {
+        deletedAt := p.DeletedAt.Time
+        row.DeletedAt = &deletedAt
+    }
+    return row
+}
+
+// ExportProductsParquet writes the products created in the requested range to
+// a temporary Parquet file and streams it back in exportChunkSize pieces, so
+// the full file never has to be held in memory.
+func (s *server) ExportProductsParquet(req *pb.ExportProductsParquetRequest, stream pb.ProductService_ExportProductsParquetServer) error {
+    if req.From != nil && req.To != nil && req.From.AsTime().After(req.To.AsTime()) {
+        return status.Error(codes.InvalidArgument, "from must not be after to")
+    }
+
+    query := s.db.WithContext(stream.Context()).Unscoped().Model(&Product{}).Order("id")
+    if req.From != nil {
+        query = query.Where("created_at >= ?", req.From.AsTime())
+    }
+    if req.To != nil {
+        query = query.Where("created_at <= ?", req.To.AsTime())
+    }
+
+    f, err := os.CreateTemp("", "products-*.parquet")
+    if err != nil {
+        return status.Errorf(codes.Internal, "failed to create export file: %v", err)
+    }
+    defer os.Remove(f.Name())
+    defer f.Close()
+
+    if err := writeProductsParquet(query, f); err != nil {
+        return status.Errorf(codes.Internal, "failed to write export: %v", err)
+    }
+    if _, err := f.Seek(0, io.SeekStart); err != nil {
+        return status.Errorf(codes.Internal, "failed to rewind export: %v", err)
+    }
+
+    buf := make([]byte, exportChunkSize)
+    for {
+        n, err := io.ReadFull(f, buf)
+        if n > 0 {
+            if err := stream.Send(&pb.ExportChunk{Data: buf[:n]}); err != nil {
+                return err
+            }
+        }
+        if err == io.EOF || err == io.ErrUnexpectedEOF {
+            return nil
+        }
+        if err != nil {
+            return status.Errorf(codes.Internal, "failed to read export: %v", err)
+        }
+    }
+}
+
+func writeProductsParquet(query *gorm.DB, w io.Writer) error {
+    writer := parquet.NewGenericWriter[productParquetRow](w)
+
+    var products []Product
+    result := query.FindInBatches(&products, exportBatchSize, func(tx *gorm.DB, batch int) error {
+        rows := make([]productParquetRow, len(products))
+        for i := range products {
+            rows[i] = newProductParquetRow(&products[i])
+        }
+        _, err := writer.Write(rows)
+        return err
+    })
+    if result.Error != nil {
+        return result.Error
+    }
+    return writer.Close()
+}
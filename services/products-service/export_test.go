@@ -0,0 +1,104 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/parquet-go/parquet-go"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// exportStream joins the chunks of an export back into one file. Like a real
+// stream it copies each chunk before Send returns, as the handler reuses its
+// buffer.
+type exportStream struct {
+    grpc.ServerStream
+    ctx    context.Context
+    file   bytes.Buffer
+    chunks int
+}
+
+func (s *exportStream) Context() context.Context { return s.ctx }
+
+func (s *exportStream) Send(chunk *pb.ExportChunk) error {
+    if len(chunk.Data) > exportChunkSize {
+        return fmt.Errorf("chunk of %d bytes, want at most %d", len(chunk.Data), exportChunkSize)
+    }
+    s.file.Write(chunk.Data)
+    s.chunks++
+    return nil
+}
+
+func TestExportProductsParquetReassembles(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // Long names push the file past one chunk, so the client has to join
+    // several chunks back together.
+    const total = 2*exportBatchSize + 200
+    deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+    name := func(id int) string { return fmt.Sprintf("%d-%s", id, strings.Repeat("x", 1000)) }
+    for start := 1; start <= total; start += exportBatchSize {
+        rows := sqlmock.NewRows([]string{"id", "name", "price", "deleted_at"})
+        for id := start; id < start+exportBatchSize && id <= total; id++ {
+            var deleted interface{}
+            if id%100 == 0 {
+                deleted = deletedAt
+            }
+            rows.AddRow(id, name(id), float64(id)/100, deleted)
+        }
+        mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(rows)
+    }
+
+    stream := &exportStream{ctx: context.Background()}
+    if err := s.ExportProductsParquet(&pb.ExportProductsParquetRequest{}, stream); err != nil {
+        t.Fatal(err)
+    }
+    if stream.chunks < 2 {
+        t.Fatalf("export of %d bytes came in %d chunk(s), want several", stream.file.Len(), stream.chunks)
+    }
+
+    rows, err := parquet.Read[productParquetRow](bytes.NewReader(stream.file.Bytes()), int64(stream.file.Len()))
+    if err != nil {
+        t.Fatalf("reading the reassembled file: %v", err)
+    }
+    if len(rows) != total {
+        t.Fatalf("read %d rows, want %d", len(rows), total)
+    }
+    for i, row := range rows {
+        id := i + 1
+        if row.ID != fmt.Sprint(id) || row.Name != name(id) || row.Price != float64(id)/100 {
+            t.Fatalf("row %d = {%s %.20s… %v}, want product %d", i, row.ID, row.Name, row.Price, id)
+        }
+        if id%100 == 0 {
+            if row.DeletedAt == nil || !row.DeletedAt.Equal(deletedAt) {
+                t.Errorf("row %d deleted_at = %v, want %v", i, row.DeletedAt, deletedAt)
+            }
+        } else if row.DeletedAt != nil {
+            t.Errorf("row %d deleted_at = %v, want null", i, row.DeletedAt)
+        }
+    }
+}
+
+func TestExportProductsParquetRejectsInvertedRange(t *testing.T) {
+    s := &server{}
+    stream := &exportStream{ctx: context.Background()}
+    from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+    err := s.ExportProductsParquet(&pb.ExportProductsParquetRequest{
+        From: timestamppb.New(from),
+        To:   timestamppb.New(from.Add(-time.Hour)),
+    }, stream)
+    if status.Code(err) != codes.InvalidArgument {
+        t.Errorf("err = %v, want InvalidArgument", err)
+    }
+}
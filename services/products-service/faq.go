@@ -0,0 +1,328 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf8"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// Limits on FAQ entries, in characters.
+const (
+    maxFAQQuestionLength = 500
+    maxFAQAnswerLength   = 5000
+)
+
+// maxProductFAQs caps how many FAQ entries a product may have.
+const maxProductFAQs = 100
+
+// ProductFAQ is a question and answer shown on a product's page. A product's
+// entries are shown by OrderIndex, which runs from 0 without gaps.
+type ProductFAQ struct {
+    ID         uint   `gorm:"primaryKey"`
+    ProductID  uint   `gorm:"not null;index:idx_product_faqs_order,priority:1"`
+    Question   string `gorm:"type:text;not null"`
+    Answer     string `gorm:"type:text;not null"`
+    OrderIndex int    `gorm:"not null;index:idx_product_faqs_order,priority:2"`
+    CreatedBy  string `gorm:"not null"`
+    CreatedAt  time.Time
+}
+
+func (f *ProductFAQ) toProto() *pb.ProductFAQ {
+    return &pb.ProductFAQ{
+        Id:         fmt.Sprint(f.ID),
+        ProductId:  fmt.Sprint(f.ProductID),
+        Question:   f.Question,
+        Answer:     f.Answer,
+        OrderIndex: int32(f.OrderIndex),
+        CreatedBy:  f.CreatedBy,
+        CreatedAt:  timestamppb.New(f.CreatedAt),
+    }
+}
+
+func validateFAQText(field, value string, max int) error {
+    if value == "" {
+        return status.Errorf(codes.InvalidArgument, "%s is required", field)
+    }
+    if utf8.RuneCountInString(value) > max {
+        return status.Errorf(codes.InvalidArgument, "%s exceeds %d characters", field, max)
+    }
+    return nil
+}
+
+func parseFAQIDs(productID, faqID string) (uint64, uint64, error) {
+    pid, err := strconv.ParseUint(productID, 10, 64)
+    if err != nil {
+        return 0, 0, status.Errorf(codes.InvalidArgument, "invalid product id %q", productID)
+    }
+    if faqID == "" {
+        return pid, 0, nil
+    }
+    fid, err := strconv.ParseUint(faqID, 10, 64)
+    if err != nil {
+        return 0, 0, status.Errorf(codes.InvalidArgument, "invalid FAQ id %q", faqID)
+    }
+    return pid, fid, nil
+}
+
+// touchProduct locks a product and bumps its updated_at, so that caches of
+// the product, which its FAQ belongs to, are invalidated along with the
+// PRODUCT_UPDATED event each FAQ change records. Locking the product also
+// serializes changes to the order of its FAQ.
+func touchProduct(tx *gorm.DB, productID uint64, product *Product) error {
+    if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(product, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return status.Errorf(codes.NotFound, "product %d not found", productID)
+        }
+        return err
+    }
+    return tx.Model(product).Update("updated_at", time.Now()).Error
+}
+
+func findFAQ(tx *gorm.DB, productID, faqID uint64) (*ProductFAQ, error) {
+    var faq ProductFAQ
+    if err := tx.Where("id = ? AND product_id = ?", faqID, productID).Take(&faq).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "FAQ %d not found for product %d", faqID, productID)
+        }
+        return nil, err
+    }
+    return &faq, nil
+}
+
+// AddProductFAQ appends a question and answer to the end of a product's FAQ.
+func (s *server) AddProductFAQ(ctx context.Context, req *pb.AddProductFAQRequest) (*pb.ProductFAQResponse, error) {
+    productID, _, err := parseFAQIDs(req.ProductId, "")
+    if err != nil {
+        return nil, err
+    }
+    faq := ProductFAQ{
+        ProductID: uint(productID),
+        Question:  strings.TrimSpace(req.Question),
+        Answer:    strings.TrimSpace(req.Answer),
+        CreatedBy: actorFromContext(ctx),
+    }
+    if err := validateFAQText("question", faq.Question, maxFAQQuestionLength); err != nil {
+        return nil, err
+    }
+    if err := validateFAQText("answer", faq.Answer, maxFAQAnswerLength); err != nil {
+        return nil, err
+    }
+
+    var product Product
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := touchProduct(tx, productID, &product); err != nil {
+            return err
+        }
+        var count int64
+        if err := tx.Model(&ProductFAQ{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+            return err
+        }
+        if count >= maxProductFAQs {
+            return status.Errorf(codes.FailedPrecondition, "product %s already has %d FAQ entries", req.ProductId, maxProductFAQs)
+        }
+        faq.OrderIndex = int(count)
+        if err := tx.Create(&faq).Error; err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "create", "product_faq", faq.ID, map[string]uint{"product_id": faq.ProductID}); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.ProductFAQResponse{Faq: faq.toProto()}, nil
+}
+
+// UpdateProductFAQ changes the question, the answer or both of a FAQ entry.
+// Empty fields are left as they are.
+func (s *server) UpdateProductFAQ(ctx context.Context, req *pb.UpdateProductFAQRequest) (*pb.ProductFAQResponse, error) {
+    productID, faqID, err := parseFAQIDs(req.ProductId, req.FaqId)
+    if err != nil {
+        return nil, err
+    }
+    updates := map[string]interface{}{}
+    if question := strings.TrimSpace(req.Question); question != "" {
+        if err := validateFAQText("question", question, maxFAQQuestionLength); err != nil {
+            return nil, err
+        }
+        updates["question"] = question
+    }
+    if answer := strings.TrimSpace(req.Answer); answer != "" {
+        if err := validateFAQText("answer", answer, maxFAQAnswerLength); err != nil {
+            return nil, err
+        }
+        updates["answer"] = answer
+    }
+    if len(updates) == 0 {
+        return nil, status.Error(codes.InvalidArgument, "question or answer is required")
+    }
+
+    var product Product
+    var faq *ProductFAQ
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := touchProduct(tx, productID, &product); err != nil {
+            return err
+        }
+        if faq, err = findFAQ(tx, productID, faqID); err != nil {
+            return err
+        }
+        if err := tx.Model(faq).Updates(updates).Error; err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "update", "product_faq", faq.ID, map[string]uint{"product_id": faq.ProductID}); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.ProductFAQResponse{Faq: faq.toProto()}, nil
+}
+
+// DeleteProductFAQ removes a FAQ entry, moving the entries after it up.
+func (s *server) DeleteProductFAQ(ctx context.Context, req *pb.DeleteProductFAQRequest) (*pb.DeleteProductFAQResponse, error) {
+    productID, faqID, err := parseFAQIDs(req.ProductId, req.FaqId)
+    if err != nil {
+        return nil, err
+    }
+
+    var product Product
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := touchProduct(tx, productID, &product); err != nil {
+            return err
+        }
+        faq, err := findFAQ(tx, productID, faqID)
+        if err != nil {
+            return err
+        }
+        if err := tx.Delete(faq).Error; err != nil {
+            return err
+        }
+        err = tx.Model(&ProductFAQ{}).
+            Where("product_id = ? AND order_index > ?", productID, faq.OrderIndex).
+            UpdateColumn("order_index", gorm.Expr("order_index - 1")).Error
+        if err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "delete", "product_faq", faq.ID, map[string]uint{"product_id": faq.ProductID}); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.DeleteProductFAQResponse{}, nil
+}
+
+// ReorderProductFAQs sets the order of a product's FAQ. faq_id_order must
+// list every entry of the product exactly once. The new order is written in
+// a single UPDATE.
+func (s *server) ReorderProductFAQs(ctx context.Context, req *pb.ReorderProductFAQsRequest) (*pb.ReorderProductFAQsResponse, error) {
+    productID, _, err := parseFAQIDs(req.ProductId, "")
+    if err != nil {
+        return nil, err
+    }
+    order := make([]uint64, len(req.FaqIdOrder))
+    seen := make(map[uint64]bool, len(req.FaqIdOrder))
+    for i, id := range req.FaqIdOrder {
+        _, faqID, err := parseFAQIDs(req.ProductId, id)
+        if err != nil {
+            return nil, err
+        }
+        if faqID == 0 || seen[faqID] {
+            return nil, status.Errorf(codes.InvalidArgument, "FAQ id %q is empty or repeated", id)
+        }
+        seen[faqID] = true
+        order[i] = faqID
+    }
+
+    var product Product
+    var faqs []ProductFAQ
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := touchProduct(tx, productID, &product); err != nil {
+            return err
+        }
+        var ids []uint64
+        if err := tx.Model(&ProductFAQ{}).Where("product_id = ?", productID).Pluck("id", &ids).Error; err != nil {
+            return err
+        }
+        if len(ids) != len(order) {
+            return status.Errorf(codes.InvalidArgument, "faq_id_order must list all %d FAQ entries of product %s", len(ids), req.ProductId)
+        }
+        for _, id := range ids {
+            if !seen[id] {
+                return status.Errorf(codes.InvalidArgument, "faq_id_order is missing FAQ %d", id)
+            }
+        }
+        if len(order) > 0 {
+            var sql strings.Builder
+            args := make([]interface{}, 0, 2*len(order)+1)
+            sql.WriteString("UPDATE product_faqs SET order_index = CASE id")
+            for i, id := range order {
+                sql.WriteString(" WHEN ? THEN ?")
+                args = append(args, id, i)
+            }
+            sql.WriteString(" END WHERE product_id = ?")
+            args = append(args, productID)
+            if err := tx.Exec(sql.String(), args...).Error; err != nil {
+                return err
+            }
+        }
+        if err := tx.Where("product_id = ?", productID).Order("order_index").Find(&faqs).Error; err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "reorder_faqs", "product", productID, map[string][]string{"faq_id_order": req.FaqIdOrder}); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.ReorderProductFAQsResponse{Faqs: make([]*pb.ProductFAQ, len(faqs))}
+    for i := range faqs {
+        res.Faqs[i] = faqs[i].toProto()
+    }
+    return res, nil
+}
+
+// ListProductFAQs streams a product's FAQ in order.
+func (s *server) ListProductFAQs(req *pb.ListProductFAQsRequest, stream pb.ProductService_ListProductFAQsServer) error {
+    productID, _, err := parseFAQIDs(req.ProductId, "")
+    if err != nil {
+        return err
+    }
+    db := s.db.WithContext(stream.Context())
+    if err := db.Select("id").First(&Product{}, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return err
+    }
+    var faqs []ProductFAQ
+    if err := db.Where("product_id = ?", productID).Order("order_index").Find(&faqs).Error; err != nil {
+        return err
+    }
+    for i := range faqs {
+        if err := stream.Send(&pb.ProductFAQResponse{Faq: faqs[i].toProto()}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
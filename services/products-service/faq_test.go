@@ -0,0 +1,236 @@
+package main
+
+import (
+    "context"
+    "slices"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+// faqStream records the entries ListProductFAQs sends.
+type faqStream struct {
+    grpc.ServerStream
+    faqs []*pb.ProductFAQ
+}
+
+func (s *faqStream) Context() context.Context { return context.Background() }
+
+func (s *faqStream) Send(res *pb.ProductFAQResponse) error {
+    s.faqs = append(s.faqs, res.Faq)
+    return nil
+}
+
+func faqRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "product_id", "question", "answer", "order_index", "created_by", "created_at"})
+}
+
+// expectTouchProduct expects a FAQ change to lock product id and bump its
+// updated_at.
+func expectTouchProduct(mock sqlmock.Sqlmock, id uint) {
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1 .* FOR UPDATE`).WithArgs(id).
+        WillReturnRows(statusRow(id, "Mug", productStatusActive))
+    mock.ExpectExec(`UPDATE "products" SET "updated_at"=\$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectProductUpdated expects the PRODUCT_UPDATED event that evicts the
+// product from caches.
+func expectProductUpdated(mock sqlmock.Sqlmock) {
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int32(pb.ProductEventType_PRODUCT_UPDATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestProductFAQsRejectBadRequests(t *testing.T) {
+    // The mock has no expectations, so reaching the database fails the test.
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    ctx := context.Background()
+    tooLong := strings.Repeat("é", maxFAQAnswerLength+1)
+    for name, call := range map[string]func() error{
+        "bad product id": func() error {
+            _, err := s.AddProductFAQ(ctx, &pb.AddProductFAQRequest{ProductId: "mug", Question: "Q?", Answer: "A."})
+            return err
+        },
+        "blank question": func() error {
+            _, err := s.AddProductFAQ(ctx, &pb.AddProductFAQRequest{ProductId: "1", Question: "  ", Answer: "A."})
+            return err
+        },
+        "long answer": func() error {
+            _, err := s.AddProductFAQ(ctx, &pb.AddProductFAQRequest{ProductId: "1", Question: "Q?", Answer: tooLong})
+            return err
+        },
+        "empty update": func() error {
+            _, err := s.UpdateProductFAQ(ctx, &pb.UpdateProductFAQRequest{ProductId: "1", FaqId: "2"})
+            return err
+        },
+        "bad FAQ id": func() error {
+            _, err := s.DeleteProductFAQ(ctx, &pb.DeleteProductFAQRequest{ProductId: "1", FaqId: "first"})
+            return err
+        },
+        "repeated id": func() error {
+            _, err := s.ReorderProductFAQs(ctx, &pb.ReorderProductFAQsRequest{ProductId: "1", FaqIdOrder: []string{"2", "3", "2"}})
+            return err
+        },
+    } {
+        if err := call(); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestAddProductFAQAppendsEntry(t *testing.T) {
+    db, mock := newMockDB(t)
+    ctx := context.WithValue(context.Background(), actorContextKey{}, "key:abc")
+
+    mock.ExpectBegin()
+    expectTouchProduct(mock, 42)
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "product_faqs" WHERE product_id = \$1`).WithArgs(42).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+    mock.ExpectQuery(`INSERT INTO "product_faqs"`).
+        WithArgs(42, "Is it dishwasher safe?", "Yes.", 2, "key:abc", sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectAudit(mock, "create", "product_faq")
+    expectProductUpdated(mock)
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).AddProductFAQ(ctx, &pb.AddProductFAQRequest{
+        ProductId: "42", Question: " Is it dishwasher safe? ", Answer: "Yes.",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if faq := res.Faq; faq.Id != "7" || faq.OrderIndex != 2 || faq.CreatedBy != "key:abc" || faq.Question != "Is it dishwasher safe?" {
+        t.Errorf("added %v, want entry 7 at index 2 created by key:abc", faq)
+    }
+}
+
+func TestAddProductFAQToMissingProduct(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectRollback()
+
+    _, err := (&server{db: db}).AddProductFAQ(context.Background(), &pb.AddProductFAQRequest{ProductId: "9", Question: "Q?", Answer: "A."})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("FAQ for a missing product = %v, want NotFound", err)
+    }
+}
+
+func TestReorderProductFAQsWritesOneUpdate(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    expectTouchProduct(mock, 42)
+    mock.ExpectQuery(`SELECT "id" FROM "product_faqs" WHERE product_id = \$1`).WithArgs(42).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6).AddRow(7))
+    mock.ExpectExec(`UPDATE product_faqs SET order_index = CASE id WHEN \$1 THEN \$2 WHEN \$3 THEN \$4 WHEN \$5 THEN \$6 END WHERE product_id = \$7`).
+        WithArgs(7, 0, 5, 1, 6, 2, 42).
+        WillReturnResult(sqlmock.NewResult(0, 3))
+    now := time.Now()
+    mock.ExpectQuery(`SELECT \* FROM "product_faqs" WHERE product_id = \$1 ORDER BY order_index`).WithArgs(42).
+        WillReturnRows(faqRows().
+            AddRow(7, 42, "Q7", "A7", 0, "key:abc", now).
+            AddRow(5, 42, "Q5", "A5", 1, "key:abc", now).
+            AddRow(6, 42, "Q6", "A6", 2, "key:abc", now))
+    expectAudit(mock, "reorder_faqs", "product")
+    expectProductUpdated(mock)
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).ReorderProductFAQs(context.Background(), &pb.ReorderProductFAQsRequest{
+        ProductId: "42", FaqIdOrder: []string{"7", "5", "6"},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    var ids []string
+    for _, faq := range res.Faqs {
+        ids = append(ids, faq.Id)
+    }
+    if !slices.Equal(ids, []string{"7", "5", "6"}) {
+        t.Errorf("reordered FAQ = %v, want 7, 5, 6", ids)
+    }
+}
+
+func TestReorderProductFAQsMustListEveryEntry(t *testing.T) {
+    db, mock := newMockDB(t)
+    for _, order := range [][]string{{"5", "6"}, {"5", "6", "8"}} {
+        mock.ExpectBegin()
+        expectTouchProduct(mock, 42)
+        mock.ExpectQuery(`SELECT "id" FROM "product_faqs"`).
+            WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6).AddRow(7))
+        mock.ExpectRollback()
+
+        _, err := (&server{db: db}).ReorderProductFAQs(context.Background(), &pb.ReorderProductFAQsRequest{ProductId: "42", FaqIdOrder: order})
+        if status.Code(err) != codes.InvalidArgument {
+            t.Errorf("reorder to %v = %v, want InvalidArgument", order, err)
+        }
+    }
+}
+
+func TestProductFAQsWithDatabase(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &ProductFAQ{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db}
+    ctx := context.Background()
+    product := Product{Name: "Mug", Price: 12.5, Status: productStatusActive}
+    if err := db.Create(&product).Error; err != nil {
+        t.Fatal(err)
+    }
+    productID := product.toProto().Id
+
+    var ids []string
+    for _, question := range []string{"First?", "Second?", "Third?"} {
+        res, err := s.AddProductFAQ(ctx, &pb.AddProductFAQRequest{ProductId: productID, Question: question, Answer: "Yes."})
+        if err != nil {
+            t.Fatal(err)
+        }
+        ids = append(ids, res.Faq.Id)
+    }
+    list := func() []string {
+        t.Helper()
+        stream := &faqStream{}
+        if err := s.ListProductFAQs(&pb.ListProductFAQsRequest{ProductId: productID}, stream); err != nil {
+            t.Fatal(err)
+        }
+        var questions []string
+        for i, faq := range stream.faqs {
+            if int(faq.OrderIndex) != i {
+                t.Errorf("%s has index %d, want %d", faq.Question, faq.OrderIndex, i)
+            }
+            questions = append(questions, faq.Question)
+        }
+        return questions
+    }
+
+    if _, err := s.ReorderProductFAQs(ctx, &pb.ReorderProductFAQsRequest{ProductId: productID, FaqIdOrder: []string{ids[2], ids[0], ids[1]}}); err != nil {
+        t.Fatal(err)
+    }
+    if got := list(); !slices.Equal(got, []string{"Third?", "First?", "Second?"}) {
+        t.Errorf("after reorder: %v", got)
+    }
+    // Deleting an entry moves the later ones up, leaving no gap.
+    if _, err := s.DeleteProductFAQ(ctx, &pb.DeleteProductFAQRequest{ProductId: productID, FaqId: ids[0]}); err != nil {
+        t.Fatal(err)
+    }
+    if got := list(); !slices.Equal(got, []string{"Third?", "Second?"}) {
+        t.Errorf("after delete: %v", got)
+    }
+    // Every change recorded an event for caches of the product.
+    var events int64
+    if err := db.Model(&OutboxEvent{}).Count(&events).Error; err != nil {
+        t.Fatal(err)
+    }
+    if events != 5 {
+        t.Errorf("recorded %d outbox events, want 5", events)
+    }
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "strings"
+    "unicode/utf8"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    defaultFuzzySearchLimit = 10
+    maxFuzzySearchLimit     = 100
+    // defaultFuzzySearchThreshold is used when a request does not set
+    // similarity_threshold; requests that do are clamped to
+    // [minFuzzySearchThreshold, 1].
+    defaultFuzzySearchThreshold = 0.3
+    minFuzzySearchThreshold     = 0.1
+    maxFuzzySearchQueryLength   = 200
+)
+
+// migrateProductNameTrigramIndex enables pg_trgm and indexes product names
+// with it, for FuzzySearchProducts. It must run after Product is migrated.
+func migrateProductNameTrigramIndex(db *gorm.DB) error {
+    statements := []string{
+        `CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+        `CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING gist (name gist_trgm_ops)`,
+    }
+    for _, statement := range statements {
+        if err := db.Exec(statement).Error; err != nil {
+            return fmt.Errorf("migrate idx_products_name_trgm: %w", err)
+        }
+    }
+    return nil
+}
+
+// FuzzySearchProducts returns the products whose names are similar to the
+// query by trigrams, most similar first, so misspelled queries still
+// find them. A product matches on the similarity of its whole name, or on
+// word similarity, which scores how well the query matches some part of the
+// name; its similarity is the better of the two. Only active products are
+// searched unless include_archived is set.
+func (s *server) FuzzySearchProducts(ctx context.Context, req *pb.FuzzySearchProductsRequest) (*pb.FuzzySearchProductsResponse, error) {
+    query := strings.TrimSpace(req.Query)
+    if query == "" {
+        return nil, status.Error(codes.InvalidArgument, "query is required")
+    }
+    if utf8.RuneCountInString(query) > maxFuzzySearchQueryLength {
+        return nil, status.Errorf(codes.InvalidArgument, "query must be at most %d characters", maxFuzzySearchQueryLength)
+    }
+    threshold := req.SimilarityThreshold
+    switch {
+    case threshold == 0:
+        threshold = defaultFuzzySearchThreshold
+    case threshold < minFuzzySearchThreshold:
+        threshold = minFuzzySearchThreshold
+    case threshold > 1:
+        threshold = 1
+    }
+    limit := int(req.Limit)
+    switch {
+    case limit < 0:
+        return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+    case limit == 0:
+        limit = defaultFuzzySearchLimit
+    case limit > maxFuzzySearchLimit:
+        limit = maxFuzzySearchLimit
+    }
+
+    var matches []similarProduct
+    err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        // % and <% compare against these thresholds, and can use
+        // idx_products_name_trgm where similarity() cannot.
+        value := strconv.FormatFloat(threshold, 'f', -1, 64)
+        if err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', ?, true), set_config('pg_trgm.word_similarity_threshold', ?, true)`, value, value).Error; err != nil {
+            return err
+        }
+        return tx.Raw(`
+            SELECT products.*, GREATEST(similarity(name, @query), word_similarity(@query, name)) AS similarity
+            FROM products
+            WHERE (name % @query OR @query <% name)
+                AND deleted_at IS NULL
+                AND (@include_archived OR status = @status)
+                AND review_status = @review_status
+            ORDER BY similarity DESC, id
+            LIMIT @limit`,
+            map[string]interface{}{"query": query, "status": productStatusActive, "review_status": reviewStatusApproved, "include_archived": req.IncludeArchived, "limit": limit}).Scan(&matches).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    res := &pb.FuzzySearchProductsResponse{Products: make([]*pb.ProductSearchResult, len(matches))}
+    for i := range matches {
+        res.Products[i] = &pb.ProductSearchResult{Product: matches[i].toProto(), Similarity: matches[i].Similarity}
+    }
+    return res, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestFuzzySearchProductsRejectsBadRequests(t *testing.T) {
+    s := &server{}
+    for name, req := range map[string]*pb.FuzzySearchProductsRequest{
+        "empty":          {Query: "  "},
+        "too long":       {Query: strings.Repeat("a", maxFuzzySearchQueryLength+1)},
+        "negative limit": {Query: "mug", Limit: -1},
+    } {
+        if _, err := s.FuzzySearchProducts(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: FuzzySearchProducts = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestFuzzySearchProductsClampsThresholdAndLimit(t *testing.T) {
+    for _, tt := range []struct {
+        threshold float64
+        limit     int32
+        wantValue string
+        wantLimit int
+    }{
+        {0, 0, "0.3", defaultFuzzySearchLimit},
+        {0.01, 5, "0.1", 5},
+        {0.55, 500, "0.55", maxFuzzySearchLimit},
+        {7, 1, "1", 1},
+    } {
+        db, mock := newMockDB(t)
+        mock.ExpectBegin()
+        mock.ExpectExec(`SELECT set_config\('pg_trgm.similarity_threshold', \$1, true\), set_config\('pg_trgm.word_similarity_threshold', \$2, true\)`).
+            WithArgs(tt.wantValue, tt.wantValue).
+            WillReturnResult(sqlmock.NewResult(0, 0))
+        mock.ExpectQuery(`WHERE \(name % \$3 OR \$4 <% name\)`).
+            WithArgs("mug", "mug", "mug", "mug", false, productStatusActive, reviewStatusApproved, tt.wantLimit).
+            WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "similarity"}).AddRow(1, "Mug", 4.5, 0.75))
+        mock.ExpectCommit()
+
+        req := &pb.FuzzySearchProductsRequest{Query: " mug ", SimilarityThreshold: tt.threshold, Limit: tt.limit}
+        res, err := (&server{db: db}).FuzzySearchProducts(context.Background(), req)
+        if err != nil {
+            t.Fatalf("threshold %v, limit %d: %v", tt.threshold, tt.limit, err)
+        }
+        if len(res.Products) != 1 || res.Products[0].Product.Name != "Mug" || res.Products[0].Similarity != 0.75 {
+            t.Errorf("threshold %v, limit %d: got %v", tt.threshold, tt.limit, res.Products)
+        }
+    }
+}
+
+func TestFuzzySearchProductsFindsMisspellings(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateProductNameTrigramIndex(db); err != nil {
+        t.Skipf("pg_trgm is not available: %v", err)
+    }
+    for _, p := range []Product{
+        {Name: "Espresso Machine", Price: 299, Status: productStatusActive},
+        {Name: "Stainless Steel Kettle", Price: 45, Status: productStatusActive},
+        {Name: "Ceramic Coffee Mug", Price: 12, Status: productStatusActive},
+        {Name: "Espresso Cups", Price: 18, Status: productStatusArchived},
+    } {
+        if err := db.Create(&p).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+    s := &server{db: db}
+
+    for query, want := range map[string]string{
+        "expresso machine": "Espresso Machine",
+        "stainles kettel":  "Stainless Steel Kettle",
+        // Only word similarity matches a query for part of a longer name.
+        "cofee": "Ceramic Coffee Mug",
+    } {
+        res, err := s.FuzzySearchProducts(context.Background(), &pb.FuzzySearchProductsRequest{Query: query})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(res.Products) == 0 {
+            t.Errorf("%q found nothing, want %s", query, want)
+            continue
+        }
+        if best := res.Products[0]; best.Product.Name != want || best.Similarity <= 0.3 {
+            t.Errorf("%q: best match %s with similarity %v, want %s above 0.3", query, best.Product.Name, best.Similarity, want)
+        }
+        for i := 1; i < len(res.Products); i++ {
+            if res.Products[i].Similarity > res.Products[i-1].Similarity {
+                t.Errorf("%q: results are not ordered by similarity: %v", query, res.Products)
+            }
+        }
+    }
+
+    res, err := s.FuzzySearchProducts(context.Background(), &pb.FuzzySearchProductsRequest{Query: "espresso"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, p := range res.Products {
+        if p.Product.Name == "Espresso Cups" {
+            t.Error("archived Espresso Cups was returned")
+        }
+    }
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+    "bytes"
+    "encoding/xml"
+    "fmt"
+    "unicode/utf8"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// Google Product Data Specification limits on item fields, in characters.
+const (
+    maxFeedTitleLength       = 150
+    maxFeedDescriptionLength = 5000
+)
+
+// googleShoppingFeedHeader opens the RSS 2.0 document, declaring the g:
+// namespace the item fields are in. %s is the escaped storefront URL.
+const googleShoppingFeedHeader = xml.Header + `<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">
+<channel>
+<title>Products</title>
+<link>%s</link>
+<description>Product catalog</description>
+`
+
+const googleShoppingFeedFooter = `</channel>
+</rss>
+`
+
+// googleShoppingItem is a feed item with the fields Google Shopping requires
+// of new goods, and the brand where one is set.
+type googleShoppingItem struct {
+    XMLName      xml.Name `xml:"item"`
+    ID           string   `xml:"g:id"`
+    Title        string   `xml:"g:title"`
+    Description  string   `xml:"g:description"`
+    Link         string   `xml:"g:link"`
+    ImageLink    string   `xml:"g:image_link"`
+    Availability string   `xml:"g:availability"`
+    Price        string   `xml:"g:price"`
+    Condition    string   `xml:"g:condition"`
+    Brand        string   `xml:"g:brand,omitempty"`
+}
+
+func newGoogleShoppingItem(p *Product) googleShoppingItem {
+    // Google requires a description, so products without one repeat their
+    // name.
+    description := p.Description
+    if description == "" {
+        description = p.Name
+    }
+    // Stock is not tracked and only active products are listed, so every
+    // listed product is in stock.
+    return googleShoppingItem{
+        ID:           fmt.Sprint(p.ID),
+        Title:        truncateRunes(p.Name, maxFeedTitleLength),
+        Description:  truncateRunes(description, maxFeedDescriptionLength),
+        Link:         productURL(p.ID),
+        ImageLink:    p.ImageURL,
+        Availability: "in_stock",
+        Price:        fmt.Sprintf("%.2f %s", p.Price, defaultCurrency),
+        Condition:    "new",
+        Brand:        p.Brand,
+    }
+}
+
+func truncateRunes(s string, n int) string {
+    if utf8.RuneCountInString(s) <= n {
+        return s
+    }
+    return string([]rune(s)[:n])
+}
+
+// ExportGoogleShoppingFeed streams the active products that have an image as
+// a Google Shopping RSS 2.0 feed. Each batch of exportBatchSize products is
+// sent as one chunk, so the feed is never held in memory whole.
+func (s *server) ExportGoogleShoppingFeed(req *pb.ExportGoogleShoppingFeedRequest, stream pb.ProductService_ExportGoogleShoppingFeedServer) error {
+    var buf bytes.Buffer
+    var link bytes.Buffer
+    xml.EscapeText(&link, []byte(storefrontURL()))
+    fmt.Fprintf(&buf, googleShoppingFeedHeader, link.String())
+
+    encoder := xml.NewEncoder(&buf)
+    var products []Product
+    result := s.db.WithContext(stream.Context()).
+        Where("status = ? AND review_status = ? AND image_url <> ''", productStatusActive, reviewStatusApproved).
+        FindInBatches(&products, exportBatchSize, func(tx *gorm.DB, batch int) error {
+            for i := range products {
+                if err := encoder.Encode(newGoogleShoppingItem(&products[i])); err != nil {
+                    return err
+                }
+                buf.WriteByte('\n')
+            }
+            if err := encoder.Flush(); err != nil {
+                return err
+            }
+            if err := stream.Send(&pb.ExportChunk{Data: buf.Bytes()}); err != nil {
+                return err
+            }
+            buf.Reset()
+            return nil
+        })
+    if result.Error != nil {
+        if _, ok := status.FromError(result.Error); ok {
+            return result.Error
+        }
+        return status.Errorf(codes.Internal, "failed to write feed: %v", result.Error)
+    }
+    buf.WriteString(googleShoppingFeedFooter)
+    return stream.Send(&pb.ExportChunk{Data: buf.Bytes()})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+    "context"
+    "encoding/xml"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+// googleShoppingNamespace is the namespace the feed binds the g: prefix to.
+const googleShoppingNamespace = "http://base.google.com/ns/1.0"
+
+// parsedFeed is a Google Shopping feed as a consumer reads it, with the g:
+// fields resolved by namespace rather than by prefix.
+type parsedFeed struct {
+    XMLName xml.Name `xml:"rss"`
+    Version string   `xml:"version,attr"`
+    Link    string   `xml:"channel>link"`
+    Items   []struct {
+        ID           string `xml:"http://base.google.com/ns/1.0 id"`
+        Title        string `xml:"http://base.google.com/ns/1.0 title"`
+        Description  string `xml:"http://base.google.com/ns/1.0 description"`
+        Link         string `xml:"http://base.google.com/ns/1.0 link"`
+        ImageLink    string `xml:"http://base.google.com/ns/1.0 image_link"`
+        Availability string `xml:"http://base.google.com/ns/1.0 availability"`
+        Price        string `xml:"http://base.google.com/ns/1.0 price"`
+        Condition    string `xml:"http://base.google.com/ns/1.0 condition"`
+        Brand        *struct {
+            Value string `xml:",chardata"`
+        } `xml:"http://base.google.com/ns/1.0 brand"`
+    } `xml:"channel>item"`
+}
+
+func feedRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "name", "price", "status", "description", "brand", "image_url"})
+}
+
+func TestExportGoogleShoppingFeed(t *testing.T) {
+    t.Setenv("BASE_URL", "https://shop.example/")
+    db, mock := newMockDB(t)
+    long := strings.Repeat("é", maxFeedTitleLength+10)
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE \(status = \$1 AND review_status = \$2 AND image_url <> ''\) .* ORDER BY "products"."id" LIMIT 500`).
+        WithArgs(productStatusActive, reviewStatusApproved).
+        WillReturnRows(feedRows().
+            AddRow(1, "Mugs & Cups <Set>", 12.5, productStatusActive, "Stoneware", "Acme", "https://img.example/1.png").
+            AddRow(2, long, 3, productStatusActive, "", "", "https://img.example/2.png"))
+
+    stream := &exportStream{ctx: context.Background()}
+    if err := (&server{db: db}).ExportGoogleShoppingFeed(&pb.ExportGoogleShoppingFeedRequest{}, stream); err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(stream.file.String(), `xmlns:g="`+googleShoppingNamespace+`"`) {
+        t.Errorf("feed does not declare the g: namespace:\n%s", stream.file.String())
+    }
+    var feed parsedFeed
+    if err := xml.Unmarshal(stream.file.Bytes(), &feed); err != nil {
+        t.Fatalf("feed is not well-formed XML: %v\n%s", err, stream.file.String())
+    }
+    if feed.Version != "2.0" || feed.Link != "https://shop.example" || len(feed.Items) != 2 {
+        t.Fatalf("got RSS %s linking %s with %d items, want 2.0, https://shop.example and 2", feed.Version, feed.Link, len(feed.Items))
+    }
+
+    mug := feed.Items[0]
+    if mug.ID != "1" || mug.Title != "Mugs & Cups <Set>" || mug.Description != "Stoneware" ||
+        mug.Link != "https://shop.example/products/1" || mug.ImageLink != "https://img.example/1.png" ||
+        mug.Availability != "in_stock" || mug.Price != "12.50 USD" || mug.Condition != "new" ||
+        mug.Brand == nil || mug.Brand.Value != "Acme" {
+        t.Errorf("first item = %+v", mug)
+    }
+    // Titles are cut to Google's limit in characters, products without a
+    // description repeat their name, and an unset brand is left out.
+    second := feed.Items[1]
+    if second.Title != strings.Repeat("é", maxFeedTitleLength) || second.Description != long ||
+        second.Price != "3.00 USD" || second.Brand != nil {
+        t.Errorf("second item has title of %d characters, description %q, price %s and brand %v",
+            len([]rune(second.Title)), second.Description, second.Price, second.Brand)
+    }
+}
+
+func TestExportGoogleShoppingFeedWithoutProducts(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(feedRows())
+
+    stream := &exportStream{ctx: context.Background()}
+    if err := (&server{db: db}).ExportGoogleShoppingFeed(&pb.ExportGoogleShoppingFeedRequest{}, stream); err != nil {
+        t.Fatal(err)
+    }
+    var feed parsedFeed
+    if err := xml.Unmarshal(stream.file.Bytes(), &feed); err != nil {
+        t.Fatalf("empty feed is not well-formed XML: %v\n%s", err, stream.file.String())
+    }
+    if len(feed.Items) != 0 {
+        t.Errorf("got %d items, want none", len(feed.Items))
+    }
+}
+
+func TestCreateProductValidatesImageURL(t *testing.T) {
+    // The mock has no expectations, so reaching the database fails the test.
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for _, imageURL := range []string{"ftp://img.example/1.png", "/images/1.png", "https://", "javascript:alert(1)"} {
+        if _, err := s.CreateProduct(context.Background(), &pb.CreateProductRequest{Name: "Mug", Price: 1, ImageUrl: imageURL}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("v1 with image_url %q: %v, want InvalidArgument", imageURL, err)
+        }
+        if _, err := (&serverV2{core: s}).CreateProduct(context.Background(), &pbv2.CreateProductRequest{Name: "Mug", PriceCents: 100, ImageUrl: imageURL}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("v2 with image_url %q: %v, want InvalidArgument", imageURL, err)
+        }
+    }
+    for _, imageURL := range []string{"", "http://img.example/1.png", "https://img.example/1.png?size=large"} {
+        if err := validateImageURL(imageURL); err != nil {
+            t.Errorf("validateImageURL(%q) = %v, want nil", imageURL, err)
+        }
+    }
+}
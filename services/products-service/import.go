@@ -0,0 +1,330 @@
+package main
+
+import (
+    "context"
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+const (
+    // importTimeout bounds the whole download, including reading the body.
+    importTimeout = 30 * time.Second
+    // importProgressInterval is how many rows are processed between
+    // progress updates.
+    importProgressInterval = 100
+    // maxImportSize caps how much of a catalog is read.
+    maxImportSize      = 64 << 20
+    maxImportRedirects = 5
+)
+
+// catalogImporter fetches product catalogs from the domains it allows.
+type catalogImporter struct {
+    client *http.Client
+    // allowedDomains are the hosts catalogs may be fetched from, along with
+    // their subdomains. No imports are allowed when it is empty.
+    allowedDomains []string
+}
+
+// newCatalogImporter allows imports from allowedDomains, as read from
+// IMPORT_ALLOWED_DOMAINS. Redirects are followed only to allowed URLs, so an
+// allowed host cannot be used to reach an internal one.
+func newCatalogImporter(allowedDomains []string) *catalogImporter {
+    c := &catalogImporter{}
+    for _, domain := range allowedDomains {
+        c.allowedDomains = append(c.allowedDomains, strings.ToLower(strings.TrimSuffix(domain, ".")))
+    }
+    c.client = &http.Client{
+        Timeout: importTimeout,
+        CheckRedirect: func(req *http.Request, via []*http.Request) error {
+            if len(via) >= maxImportRedirects {
+                return fmt.Errorf("stopped after %d redirects", maxImportRedirects)
+            }
+            return c.checkURL(req.URL)
+        },
+    }
+    return c
+}
+
+// checkURL rejects URLs that are not HTTPS or not on an allowed domain.
+func (c *catalogImporter) checkURL(u *url.URL) error {
+    if u.Scheme != "https" {
+        return status.Errorf(codes.InvalidArgument, "import URL must use https, not %q", u.Scheme)
+    }
+    host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+    for _, domain := range c.allowedDomains {
+        if host == domain || strings.HasSuffix(host, "."+domain) {
+            return nil
+        }
+    }
+    return status.Errorf(codes.PermissionDenied, "imports from %q are not allowed", host)
+}
+
+// fetch starts downloading rawURL. The caller must close the body.
+func (c *catalogImporter) fetch(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+    u, err := url.Parse(rawURL)
+    if err != nil || u.Host == "" {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid import URL %q", rawURL)
+    }
+    if err := c.checkURL(u); err != nil {
+        return nil, err
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid import URL %q", rawURL)
+    }
+    resp, err := c.client.Do(req)
+    if err != nil {
+        var st interface{ GRPCStatus() *status.Status }
+        if errors.As(err, &st) {
+            return nil, st.GRPCStatus().Err()
+        }
+        return nil, status.Errorf(codes.Unavailable, "failed to fetch catalog: %v", err)
+    }
+    if resp.StatusCode != http.StatusOK {
+        resp.Body.Close()
+        return nil, status.Errorf(codes.FailedPrecondition, "catalog URL returned %s", resp.Status)
+    }
+    return resp.Body, nil
+}
+
+// errCatalogTooLarge is returned by a catalogSizeLimiter once the catalog
+// has more than maxImportSize bytes.
+var errCatalogTooLarge = status.Errorf(codes.InvalidArgument, "catalog is larger than %d bytes", maxImportSize)
+
+// catalogSizeLimiter reads up to limit bytes of a catalog and fails with
+// errCatalogTooLarge if there are more, rather than silently cutting the
+// catalog short as io.LimitReader would.
+type catalogSizeLimiter struct {
+    r     io.Reader
+    limit int64
+    read  int64
+}
+
+func newCatalogSizeLimiter(r io.Reader, limit int64) *catalogSizeLimiter {
+    // One byte past the limit is enough to tell that the catalog is larger.
+    return &catalogSizeLimiter{r: io.LimitReader(r, limit+1), limit: limit}
+}
+
+func (l *catalogSizeLimiter) Read(p []byte) (int, error) {
+    n, err := l.r.Read(p)
+    l.read += int64(n)
+    if l.read > l.limit {
+        return n - int(l.read-l.limit), errCatalogTooLarge
+    }
+    return n, err
+}
+
+// importRow is a product read from a catalog.
+type importRow struct {
+    Name        string  `json:"name"`
+    Description string  `json:"description"`
+    Price       float64 `json:"price"`
+}
+
+// rowError reports a catalog row that could not be read. The rows after it
+// can still be.
+type rowError struct {
+    msg string
+}
+
+func (e *rowError) Error() string { return e.msg }
+
+// catalogReader reads a catalog one row at a time. next returns io.EOF after
+// the last row, and a *rowError for a row that cannot be read.
+type catalogReader interface {
+    next() (*importRow, error)
+}
+
+// csvCatalogReader reads a CSV catalog whose header row names its columns.
+// The name and price columns are required; description is optional, and
+// other columns are ignored.
+type csvCatalogReader struct {
+    r       *csv.Reader
+    columns map[string]int
+}
+
+func newCSVCatalogReader(r io.Reader) (*csvCatalogReader, error) {
+    c := &csvCatalogReader{r: csv.NewReader(r), columns: make(map[string]int)}
+    c.r.FieldsPerRecord = -1
+    c.r.TrimLeadingSpace = true
+    header, err := c.r.Read()
+    if err == io.EOF {
+        return nil, status.Error(codes.InvalidArgument, "catalog is empty")
+    }
+    if errors.Is(err, errCatalogTooLarge) {
+        return nil, err
+    }
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid CSV header: %v", err)
+    }
+    for i, column := range header {
+        c.columns[strings.ToLower(strings.TrimSpace(column))] = i
+    }
+    for _, required := range []string{"name", "price"} {
+        if _, ok := c.columns[required]; !ok {
+            return nil, status.Errorf(codes.InvalidArgument, "CSV header has no %s column", required)
+        }
+    }
+    return c, nil
+}
+
+func (c *csvCatalogReader) next() (*importRow, error) {
+    record, err := c.r.Read()
+    if err != nil {
+        var parseErr *csv.ParseError
+        if errors.As(err, &parseErr) {
+            return nil, &rowError{msg: parseErr.Err.Error()}
+        }
+        return nil, err
+    }
+    field := func(column string) string {
+        if i, ok := c.columns[column]; ok && i < len(record) {
+            return strings.TrimSpace(record[i])
+        }
+        return ""
+    }
+    row := &importRow{Name: field("name"), Description: field("description")}
+    if row.Price, err = strconv.ParseFloat(field("price"), 64); err != nil {
+        return nil, &rowError{msg: fmt.Sprintf("invalid price %q", field("price"))}
+    }
+    return row, nil
+}
+
+// jsonCatalogReader reads a JSON array of {"name", "description", "price"}
+// objects, decoding one object at a time.
+type jsonCatalogReader struct {
+    d *json.Decoder
+}
+
+func newJSONCatalogReader(r io.Reader) (*jsonCatalogReader, error) {
+    d := json.NewDecoder(r)
+    token, err := d.Token()
+    if errors.Is(err, errCatalogTooLarge) {
+        return nil, err
+    }
+    if err != nil || token != json.Delim('[') {
+        return nil, status.Error(codes.InvalidArgument, "JSON catalog must be an array of products")
+    }
+    return &jsonCatalogReader{d: d}, nil
+}
+
+func (j *jsonCatalogReader) next() (*importRow, error) {
+    if !j.d.More() {
+        return nil, io.EOF
+    }
+    var row importRow
+    if err := j.d.Decode(&row); err != nil {
+        // A value of the wrong type is skipped whole; anything else leaves
+        // the decoder unable to find the next row.
+        var typeErr *json.UnmarshalTypeError
+        if errors.As(err, &typeErr) {
+            return nil, &rowError{msg: fmt.Sprintf("invalid %s", typeErr.Field)}
+        }
+        var syntaxErr *json.SyntaxError
+        if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid JSON catalog: %v", err)
+        }
+        return nil, err
+    }
+    return &row, nil
+}
+
+// createRequest converts a row to a v2 CreateProductRequest and applies
+// CreateProduct's checks to it.
+func (r *importRow) createRequest() (*pbv2.CreateProductRequest, error) {
+    cents, err := v1PriceCents(r.Price)
+    if err != nil {
+        return nil, err
+    }
+    req := &pbv2.CreateProductRequest{Name: strings.TrimSpace(r.Name), Description: r.Description, PriceCents: cents}
+    return req, validateCreateProductRequest(req)
+}
+
+// ImportProductsFromURL downloads a CSV or JSON catalog and creates a product
+// for each valid row, or only validates the rows for a dry run. Rows are
+// created one at a time as they are read, so an import that fails part way
+// keeps the products created before it. Like CreateProduct, a row identical
+// to a product created within the dedup window is not created again, so it
+// is counted as processed but neither created nor failed. Progress is
+// streamed every importProgressInterval rows and once the catalog is done.
+func (s *server) ImportProductsFromURL(req *pb.ImportProductsFromURLRequest, stream pb.ProductService_ImportProductsFromURLServer) error {
+    ctx := stream.Context()
+    if req.Format != pb.ImportFormat_IMPORT_FORMAT_CSV && req.Format != pb.ImportFormat_IMPORT_FORMAT_JSON {
+        return status.Errorf(codes.InvalidArgument, "unsupported import format %v", req.Format)
+    }
+    body, err := s.imports.fetch(ctx, req.Url)
+    if err != nil {
+        return err
+    }
+    defer body.Close()
+
+    limited := newCatalogSizeLimiter(body, maxImportSize)
+    var catalog catalogReader
+    if req.Format == pb.ImportFormat_IMPORT_FORMAT_CSV {
+        catalog, err = newCSVCatalogReader(limited)
+    } else {
+        catalog, err = newJSONCatalogReader(limited)
+    }
+    if err != nil {
+        return err
+    }
+
+    progress := &pb.ImportProgressUpdate{}
+    for {
+        row, err := catalog.next()
+        if err == io.EOF {
+            break
+        }
+        var rowErr *rowError
+        if err != nil && !errors.As(err, &rowErr) {
+            if _, ok := status.FromError(err); !ok {
+                err = status.Errorf(codes.Unavailable, "failed to read catalog: %v", err)
+            }
+            return err
+        }
+        progress.Processed++
+        var create *pbv2.CreateProductRequest
+        if err == nil {
+            create, err = row.createRequest()
+        }
+        if err == nil && !req.DryRun {
+            var duplicate bool
+            _, duplicate, err = s.createProduct(ctx, create.Name, create.Description, create.Brand, create.ImageUrl, create.PriceCents)
+            if err == nil && !duplicate {
+                progress.Created++
+            }
+        }
+        if err != nil {
+            if ctx.Err() != nil {
+                return status.FromContextError(ctx.Err()).Err()
+            }
+            progress.Failed++
+            progress.Errors = append(progress.Errors, &pb.ImportError{Row: progress.Processed, Message: status.Convert(err).Message()})
+        }
+
+        if progress.Processed%importProgressInterval == 0 {
+            if err := stream.Send(progress); err != nil {
+                return err
+            }
+            progress = &pb.ImportProgressUpdate{Processed: progress.Processed, Created: progress.Created, Failed: progress.Failed}
+        }
+    }
+    if progress.Processed%importProgressInterval == 0 && progress.Processed > 0 {
+        return nil
+    }
+    return stream.Send(progress)
+}
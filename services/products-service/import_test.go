@@ -0,0 +1,185 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const sampleCatalogCSV = `name,description,price
+Walnut Desk Lamp,A lamp for the desk,49.99
+Ceramic Mug,,12.5
+Linen Blanket,Too precise,19.999
+,No name,5
+Wool Scarf,Warm,not a price
+`
+
+// newCatalogServer serves the sample catalog over HTTPS and returns an
+// importer that trusts it and allows its host.
+func newCatalogServer(t *testing.T) (*httptest.Server, *catalogImporter) {
+    t.Helper()
+    mux := http.NewServeMux()
+    mux.HandleFunc("/catalog.csv", func(w http.ResponseWriter, r *http.Request) {
+        io.WriteString(w, sampleCatalogCSV)
+    })
+    mux.HandleFunc("/catalog.json", func(w http.ResponseWriter, r *http.Request) {
+        io.WriteString(w, `[{"name": "Ceramic Mug", "price": 12.5}, {"name": "Broken", "price": "free"}]`)
+    })
+    mux.HandleFunc("/missing", http.NotFound)
+    var srv *httptest.Server
+    mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+        http.Redirect(w, r, srv.URL+"/catalog.csv", http.StatusFound)
+    })
+    mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) {
+        http.Redirect(w, r, "https://metadata.internal/latest", http.StatusFound)
+    })
+    mux.HandleFunc("/downgrade", func(w http.ResponseWriter, r *http.Request) {
+        http.Redirect(w, r, "http://"+r.Host+"/catalog.csv", http.StatusFound)
+    })
+    srv = httptest.NewTLSServer(mux)
+    t.Cleanup(srv.Close)
+
+    u, err := url.Parse(srv.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    importer := newCatalogImporter([]string{u.Hostname()})
+    importer.client.Transport = srv.Client().Transport
+    return srv, importer
+}
+
+func TestCatalogImporterFetch(t *testing.T) {
+    srv, importer := newCatalogServer(t)
+    tests := []struct {
+        name string
+        url  string
+        want codes.Code
+    }{
+        {"allowed", srv.URL + "/catalog.csv", codes.OK},
+        {"redirect to an allowed URL", srv.URL + "/moved", codes.OK},
+        {"plain http", strings.Replace(srv.URL, "https://", "http://", 1) + "/catalog.csv", codes.InvalidArgument},
+        {"domain not allowed", "https://supplier.example.com/catalog.csv", codes.PermissionDenied},
+        {"lookalike domain", "https://127.0.0.1.example.com/catalog.csv", codes.PermissionDenied},
+        {"redirect to a domain not allowed", srv.URL + "/internal", codes.PermissionDenied},
+        {"redirect to plain http", srv.URL + "/downgrade", codes.InvalidArgument},
+        {"not found", srv.URL + "/missing", codes.FailedPrecondition},
+        {"no host", "https:///catalog.csv", codes.InvalidArgument},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            body, err := importer.fetch(context.Background(), tt.url)
+            if status.Code(err) != tt.want {
+                t.Fatalf("fetch(%s): err = %v, want %v", tt.url, err, tt.want)
+            }
+            if err == nil {
+                data, _ := io.ReadAll(body)
+                body.Close()
+                if string(data) != sampleCatalogCSV {
+                    t.Errorf("fetch(%s) = %q", tt.url, data)
+                }
+            }
+        })
+    }
+}
+
+func TestCheckURLSubdomains(t *testing.T) {
+    importer := newCatalogImporter([]string{"Supplier.example.com."})
+    for rawURL, want := range map[string]codes.Code{
+        "https://supplier.example.com/a.csv":      codes.OK,
+        "https://cdn.supplier.example.com/a.csv":  codes.OK,
+        "https://SUPPLIER.example.com./a.csv":     codes.OK,
+        "https://evilsupplier.example.com/a.csv":  codes.PermissionDenied,
+        "https://supplier.example.com.evil/a.csv": codes.PermissionDenied,
+        "ftp://supplier.example.com/a.csv":        codes.InvalidArgument,
+    } {
+        u, err := url.Parse(rawURL)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := status.Code(importer.checkURL(u)); got != want {
+            t.Errorf("checkURL(%s) = %v, want %v", rawURL, got, want)
+        }
+    }
+    if err := newCatalogImporter(nil).checkURL(&url.URL{Scheme: "https", Host: "supplier.example.com"}); status.Code(err) != codes.PermissionDenied {
+        t.Errorf("no allowed domains: err = %v, want PermissionDenied", err)
+    }
+}
+
+func TestImportProductsFromURLDryRun(t *testing.T) {
+    srv, importer := newCatalogServer(t)
+    s := &server{imports: importer}
+    tests := []struct {
+        format                    pb.ImportFormat
+        path                      string
+        processed, failed         int32
+        firstErrorRow, errorCount int
+    }{
+        {pb.ImportFormat_IMPORT_FORMAT_CSV, "/catalog.csv", 5, 3, 3, 3},
+        {pb.ImportFormat_IMPORT_FORMAT_JSON, "/catalog.json", 2, 1, 2, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.format.String(), func(t *testing.T) {
+            stream := newFakeServerStream[pb.ImportProgressUpdate](context.Background())
+            err := s.ImportProductsFromURL(&pb.ImportProductsFromURLRequest{Url: srv.URL + tt.path, Format: tt.format, DryRun: true}, stream)
+            if err != nil {
+                t.Fatal(err)
+            }
+            progress := stream.next(t)
+            if progress.Processed != tt.processed || progress.Created != 0 || progress.Failed != tt.failed {
+                t.Errorf("progress = %v, want %d processed, none created, %d failed", progress, tt.processed, tt.failed)
+            }
+            if len(progress.Errors) != tt.errorCount || int(progress.Errors[0].Row) != tt.firstErrorRow {
+                t.Errorf("errors = %v", progress.Errors)
+            }
+        })
+    }
+}
+
+func TestCatalogSizeLimiter(t *testing.T) {
+    for _, tt := range []struct {
+        body    string
+        tooLong bool
+    }{
+        {"", false},
+        {"12345", false},
+        {"123456", true},
+        {strings.Repeat("x", 4096), true},
+    } {
+        data, err := io.ReadAll(newCatalogSizeLimiter(strings.NewReader(tt.body), 5))
+        if tt.tooLong {
+            if !errors.Is(err, errCatalogTooLarge) || len(data) != 5 {
+                t.Errorf("%d bytes: read %d, %v; want 5 then errCatalogTooLarge", len(tt.body), len(data), err)
+            }
+        } else if err != nil || string(data) != tt.body {
+            t.Errorf("%d bytes: read %q, %v", len(tt.body), data, err)
+        }
+    }
+
+    // The readers report the limit rather than a parse error.
+    if _, err := newCSVCatalogReader(newCatalogSizeLimiter(strings.NewReader("name,price\n"), 4)); !errors.Is(err, errCatalogTooLarge) {
+        t.Errorf("CSV header over the limit: err = %v", err)
+    }
+    csvReader, err := newCSVCatalogReader(newCatalogSizeLimiter(strings.NewReader("name,price\nMug,1\nLamp,2\n"), 20))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := csvReader.next(); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := csvReader.next(); !errors.Is(err, errCatalogTooLarge) {
+        t.Errorf("CSV row over the limit: err = %v", err)
+    }
+    if _, err := newJSONCatalogReader(newCatalogSizeLimiter(strings.NewReader(`   [{"name": "Mug"}]`), 2)); !errors.Is(err, errCatalogTooLarge) {
+        t.Errorf("JSON over the limit: err = %v", err)
+    }
+}
@@ -0,0 +1,135 @@
+package journal
+
+import (
+    "context"
+    "encoding/binary"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// readJournal decodes every record in the journal files in dir, oldest
+// file first.
+func readJournal(t *testing.T, dir string) (files int, entries []*pb.JournalEntry) {
+    t.Helper()
+    names, err := filepath.Glob(filepath.Join(dir, "journal-*.bin"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        data, err := os.ReadFile(name)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for len(data) > 0 {
+            if len(data) < 4 {
+                t.Fatalf("%s: truncated length prefix", name)
+            }
+            size := binary.BigEndian.Uint32(data)
+            if uint32(len(data)-4) < size {
+                t.Fatalf("%s: %v", name, io.ErrUnexpectedEOF)
+            }
+            entry := &pb.JournalEntry{}
+            if err := proto.Unmarshal(data[4:4+size], entry); err != nil {
+                t.Fatal(err)
+            }
+            entries = append(entries, entry)
+            data = data[4+size:]
+        }
+    }
+    return len(names), entries
+}
+
+func TestJournalRecordsScriptedSession(t *testing.T) {
+    dir := t.TempDir()
+    j, err := Open(Config{
+        Dir:          dir,
+        MaxFileSize:  256,
+        MetadataKeys: []string{"x-tenant"},
+        MaskFields:   []string{"products.CreatePriceAlertRequest.user_id", "products.CreatePriceAlertRequest.target_price.currency_code"},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme", "x-api-key", "secret"))
+    session := []struct {
+        method string
+        req    proto.Message
+        err    error
+    }{
+        {"/products.ProductService/CreateProduct", &pb.CreateProductRequest{Name: "Mug", Price: 12.5}, nil},
+        {"/products.ProductService/GetProduct", &pb.GetProductRequest{Id: "1"}, nil},
+        {"/products.ProductService/CreatePriceAlert", &pb.CreatePriceAlertRequest{UserId: "ada", ProductId: "1", TargetPrice: &pb.Money{Amount: 10, CurrencyCode: "USD"}}, nil},
+        {"/products.ProductService/GetProduct", &pb.GetProductRequest{Id: "2"}, status.Error(codes.NotFound, "product 2 not found")},
+    }
+    for _, call := range session {
+        alert, _ := call.req.(*pb.CreatePriceAlertRequest)
+        _, err := j.UnaryInterceptor(ctx, call.req, &grpc.UnaryServerInfo{FullMethod: call.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+            return nil, call.err
+        })
+        if err != call.err {
+            t.Errorf("%s returned %v, want the handler's %v", call.method, err, call.err)
+        }
+        if alert != nil && alert.UserId != "ada" {
+            t.Error("masking changed the request passed to the handler")
+        }
+    }
+    if err := j.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    files, entries := readJournal(t, dir)
+    if files < 2 {
+        t.Errorf("%d journal files after writing past MaxFileSize, want a rotation", files)
+    }
+    if len(entries) != len(session) {
+        t.Fatalf("got %d entries, want %d", len(entries), len(session))
+    }
+    for i, entry := range entries {
+        call := session[i]
+        if entry.Method != call.method || codes.Code(entry.Code) != status.Code(call.err) {
+            t.Errorf("entry %d is %s with %v, want %s with %v", i, entry.Method, codes.Code(entry.Code), call.method, status.Code(call.err))
+        }
+        if len(entry.Metadata) != 1 || entry.Metadata["x-tenant"] != "acme" {
+            t.Errorf("entry %d has metadata %v, want only x-tenant", i, entry.Metadata)
+        }
+        if i > 0 && entry.StartedAt.AsTime().Before(entries[i-1].StartedAt.AsTime()) {
+            t.Errorf("entry %d started before entry %d", i, i-1)
+        }
+        got := call.req.ProtoReflect().New().Interface()
+        if err := proto.Unmarshal(entry.Request, got); err != nil {
+            t.Fatal(err)
+        }
+        want := call.req
+        if _, ok := call.req.(*pb.CreatePriceAlertRequest); ok {
+            want = &pb.CreatePriceAlertRequest{UserId: maskedValue, ProductId: "1", TargetPrice: &pb.Money{Amount: 10, CurrencyCode: maskedValue}}
+        }
+        if !proto.Equal(got, want) {
+            t.Errorf("entry %d recorded %v, want %v", i, got, want)
+        }
+    }
+}
+
+func TestParseMask(t *testing.T) {
+    message, path, err := parseMask("products.CreatePriceAlertRequest.target_price.currency_code")
+    if err != nil || message != "products.CreatePriceAlertRequest" || len(path) != 2 || path[0] != "target_price" || path[1] != "currency_code" {
+        t.Errorf("parseMask = %s %v %v", message, path, err)
+    }
+    for _, bad := range []string{"products.name", "products.CreateProductRequest", ""} {
+        if _, _, err := parseMask(bad); err == nil {
+            t.Errorf("parseMask(%q) succeeded", bad)
+        }
+    }
+}
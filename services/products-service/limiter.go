@@ -0,0 +1,91 @@
+package main
+
+import (
+    "context"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    "shared/metrics"
+)
+
+// concurrencyLimiter caps the number of RPCs handled at the same time so a
+// burst of calls cannot exhaust the database connection pool.
+type concurrencyLimiter struct {
+    slots chan struct{}
+}
+
+// unlimitedMethods do not take a slot. Health checks must get through at the
+// limit, or Consul marks a busy instance unhealthy. Watch streams stay open
+// for as long as their clients are connected, so each would hold a slot
+// indefinitely. Drain waits for the slots to empty, so it must not hold one.
+var unlimitedMethods = map[string]bool{
+    grpc_health_v1.Health_Check_FullMethodName:               true,
+    grpc_health_v1.Health_Watch_FullMethodName:               true,
+    pb.ProductService_WatchProducts_FullMethodName:           true,
+    pb.ProductService_WatchCacheInvalidations_FullMethodName: true,
+    pb.DrainService_Drain_FullMethodName:                     true,
+}
+
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+    return &concurrencyLimiter{slots: make(chan struct{}, limit)}
+}
+
+func (l *concurrencyLimiter) acquire() bool {
+    select {
+    case l.slots <- struct{}{}:
+        metrics.InFlightRequests.Inc()
+        return true
+    default:
+        return false
+    }
+}
+
+func (l *concurrencyLimiter) release() {
+    <-l.slots
+    metrics.InFlightRequests.Dec()
+}
+
+// inFlight returns the number of RPCs currently holding a slot.
+func (l *concurrencyLimiter) inFlight() int {
+    return len(l.slots)
+}
+
+func (l *concurrencyLimiter) limitExceeded() error {
+    return status.Errorf(codes.ResourceExhausted, "too many concurrent requests (limit %d)", cap(l.slots))
+}
+
+func (l *concurrencyLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    if unlimitedMethods[info.FullMethod] {
+        return handler(ctx, req)
+    }
+    if !l.acquire() {
+        return nil, l.limitExceeded()
+    }
+    defer l.release()
+    return handler(ctx, req)
+}
+
+func (l *concurrencyLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    if unlimitedMethods[info.FullMethod] {
+        return handler(srv, ss)
+    }
+    if !l.acquire() {
+        return l.limitExceeded()
+    }
+    defer l.release()
+    return handler(srv, ss)
+}
+
+// rateLimitKey groups requests for RATE_LIMIT. Each tenant has its own budget
+// shared by all methods. unlimitedMethods are exempt for the same reasons as
+// from the concurrency limit.
+func rateLimitKey(ctx context.Context, fullMethod string) string {
+    if unlimitedMethods[fullMethod] {
+        return ""
+    }
+    return serviceName + ":" + tenantFromContext(ctx)
+}
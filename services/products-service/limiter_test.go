@@ -0,0 +1,75 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestConcurrencyLimiterAtLimit(t *testing.T) {
+    l := newConcurrencyLimiter(1)
+    if !l.acquire() {
+        t.Fatal("acquire() = false on an empty limiter")
+    }
+    defer l.release()
+
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+    for _, method := range []string{grpc_health_v1.Health_Check_FullMethodName, pb.DrainService_Drain_FullMethodName} {
+        if _, err := l.unaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler); err != nil {
+            t.Errorf("%s at the limit: %v, want no error", method, err)
+        }
+    }
+    _, err := l.unaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: pb.ProductService_GetProduct_FullMethodName}, handler)
+    if status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("GetProduct at the limit: %v, want ResourceExhausted", err)
+    }
+
+    stream := func(srv interface{}, ss grpc.ServerStream) error { return nil }
+    for _, method := range []string{
+        grpc_health_v1.Health_Watch_FullMethodName,
+        pb.ProductService_WatchProducts_FullMethodName,
+        pb.ProductService_WatchCacheInvalidations_FullMethodName,
+    } {
+        if err := l.streamInterceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: method}, stream); err != nil {
+            t.Errorf("%s at the limit: %v, want no error", method, err)
+        }
+    }
+    if len(l.slots) != 1 {
+        t.Errorf("%d slots taken after exempt calls, want 1", len(l.slots))
+    }
+}
+
+func TestConcurrencyLimiterReleasesSlots(t *testing.T) {
+    l := newConcurrencyLimiter(2)
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+    info := &grpc.UnaryServerInfo{FullMethod: pb.ProductService_GetProduct_FullMethodName}
+    for i := 0; i < 5; i++ {
+        if _, err := l.unaryInterceptor(context.Background(), nil, info, handler); err != nil {
+            t.Fatalf("call %d: %v", i, err)
+        }
+    }
+    if len(l.slots) != 0 {
+        t.Errorf("%d slots still taken after the calls returned, want 0", len(l.slots))
+    }
+}
+
+func TestRateLimitKey(t *testing.T) {
+    for _, method := range []string{grpc_health_v1.Health_Check_FullMethodName, pb.DrainService_Drain_FullMethodName} {
+        if key := rateLimitKey(tenantContext("acme"), method); key != "" {
+            t.Errorf("%s has rate limit key %q, want it exempt", method, key)
+        }
+    }
+    acme := rateLimitKey(tenantContext("acme"), pb.ProductService_GetProduct_FullMethodName)
+    if acme != rateLimitKey(tenantContext("acme"), pb.ProductService_CreateProduct_FullMethodName) {
+        t.Error("one tenant's methods have separate budgets")
+    }
+    if acme == rateLimitKey(tenantContext("globex"), pb.ProductService_GetProduct_FullMethodName) {
+        t.Error("two tenants share a budget")
+    }
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+    "context"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/pagination"
+)
+
+// page is a decoded page_size and page_token. Listings are ordered by id.
+type page struct {
+    size  int
+    after pagination.PageToken
+}
+
+// clampPageSize applies the default and maximum page size, which are the
+// same for every list RPC.
+func (s *server) clampPageSize(pageSize int32) (int, error) {
+    size, err := pagination.ClampPageSize(pageSize, s.maxPageSize)
+    if err != nil {
+        return 0, status.Error(codes.InvalidArgument, "page_size must not be negative")
+    }
+    return size, nil
+}
+
+func (s *server) parsePage(pageSize int32, pageToken string) (page, error) {
+    size, err := s.clampPageSize(pageSize)
+    if err != nil {
+        return page{}, err
+    }
+    p := page{size: size}
+    after, err := pagination.ParsePageToken(pageToken)
+    if err != nil {
+        return page{}, status.Error(codes.InvalidArgument, "invalid page_token")
+    }
+    p.after = after
+    return p, nil
+}
+
+// list fetches the page of the products query selects and converts it into
+// a response.
+func (p page) list(ctx context.Context, query *gorm.DB) (*pb.ListProductsResponse, error) {
+    var products []Product
+    next, _, err := pagination.NewCursorPaginator(query, "id").Page(ctx, &products, p.after, p.size)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.ListProductsResponse{NextPageToken: next.String(), Products: make([]*pb.Product, len(products))}
+    for i := range products {
+        res.Products[i] = products[i].toProto()
+    }
+    return res, nil
+}
+
+// ListProductsByDateRange lists products created at or after from and before
+// to. Either bound may be left unset. Like ListProducts it leaves out
+// products that are not active unless include_archived is set.
+func (s *server) ListProductsByDateRange(ctx context.Context, req *pb.ListProductsByDateRangeRequest) (*pb.ListProductsResponse, error) {
+    for name, ts := range map[string]*timestamppb.Timestamp{"from": req.From, "to": req.To} {
+        if ts != nil && ts.CheckValid() != nil {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid %s timestamp", name)
+        }
+    }
+    if req.From != nil && req.To != nil && req.From.AsTime().After(req.To.AsTime()) {
+        return nil, status.Error(codes.InvalidArgument, "from must not be after to")
+    }
+    p, err := s.parsePage(req.PageSize, req.PageToken)
+    if err != nil {
+        return nil, err
+    }
+
+    query := reviewedProducts(visibleProducts(s.db.WithContext(ctx), req.IncludeArchived), false)
+    if req.From != nil {
+        query = query.Where("created_at >= ?", req.From.AsTime())
+    }
+    if req.To != nil {
+        query = query.Where("created_at < ?", req.To.AsTime())
+    }
+    return p.list(ctx, query)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "products-service/proto/gen/proto"
+    "shared/pagination"
+)
+
+func TestParsePageClampsSize(t *testing.T) {
+    s := &server{maxPageSize: 100}
+    for size, want := range map[int32]int{0: pagination.DefaultPageSize, 1: 1, 100: 100, 1000: 100} {
+        p, err := s.parsePage(size, "")
+        if err != nil || p.size != want {
+            t.Errorf("parsePage(%d) = %d, %v, want %d", size, p.size, err, want)
+        }
+    }
+    for _, token := range []string{"not base64!", "YWJj"} {
+        if _, err := s.parsePage(10, token); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("parsePage(10, %q): %v, want InvalidArgument", token, err)
+        }
+    }
+    if _, err := s.parsePage(-1, ""); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("parsePage(-1): %v, want InvalidArgument", err)
+    }
+}
+
+func TestPageTokenRoundTrip(t *testing.T) {
+    db, mock := newMockDB(t)
+    ctx := context.Background()
+    p := page{size: 2}
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."deleted_at" IS NULL ORDER BY id LIMIT 3`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Mug").AddRow(9, "Cup").AddRow(12, "Bowl"))
+    res, err := p.list(ctx, db.Model(&Product{}))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Products) != 2 || res.NextPageToken == "" {
+        t.Fatalf("got %d products and token %q, want 2 and a token", len(res.Products), res.NextPageToken)
+    }
+    next, err := (&server{}).parsePage(2, res.NextPageToken)
+    if err != nil || next.after.ID != 9 {
+        t.Fatalf("next page starts after %d (%v), want 9", next.after.ID, err)
+    }
+
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE id > \$1 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 3`).
+        WithArgs(9).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(12, "Bowl"))
+    last, err := next.list(ctx, db.Model(&Product{}))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(last.Products) != 1 || last.NextPageToken != "" {
+        t.Errorf("last page has %d products and token %q", len(last.Products), last.NextPageToken)
+    }
+}
+
+// TestListRPCsObeyMaxPageSize sends an oversized page_size to every list
+// RPC and checks that each fetches only MAX_PAGE_SIZE rows, plus the one
+// that tells whether another page follows.
+func TestListRPCsObeyMaxPageSize(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, maxPageSize: 5}
+    ctx := context.Background()
+    for name, list := range map[string]func() error{
+        "ListProducts": func() error {
+            _, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 1000})
+            return err
+        },
+        "ListProductsByDateRange": func() error {
+            _, err := s.ListProductsByDateRange(ctx, &pb.ListProductsByDateRangeRequest{PageSize: 1000})
+            return err
+        },
+    } {
+        mock.ExpectQuery(`SELECT \* FROM "products" .*LIMIT 6$`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+        if err := list(); err != nil {
+            t.Errorf("%s: %v", name, err)
+        }
+    }
+}
+
+func TestListProductsByDateRange(t *testing.T) {
+    db, mock := newMockDB(t)
+    from := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE status = \$1 AND review_status = \$2 AND created_at >= \$3 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 3`).
+        WithArgs(productStatusActive, reviewStatusApproved, from).
+        WillReturnRows(productRow(42, "Mug", 12.5))
+
+    res, err := (&server{db: db}).ListProductsByDateRange(context.Background(), &pb.ListProductsByDateRangeRequest{From: timestamppb.New(from), PageSize: 2})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Products) != 1 || res.NextPageToken != "" {
+        t.Errorf("got %d products and token %q, want one product and no token", len(res.Products), res.NextPageToken)
+    }
+}
+
+func TestListProductsByDateRangeRejectsReversedRange(t *testing.T) {
+    db, _ := newMockDB(t)
+    now := time.Now()
+    req := &pb.ListProductsByDateRangeRequest{From: timestamppb.New(now), To: timestamppb.New(now.Add(-time.Hour))}
+    if _, err := (&server{db: db}).ListProductsByDateRange(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("from after to: %v, want InvalidArgument", err)
+    }
+}
@@ -5,47 +5,208 @@ import (
     "fmt"
     "log"
     "net"
+    "net/http"
     "os"
+    "os/signal"
+    "strconv"
+    "strings"
+    "syscall"
     "time"
 
+    "github.com/google/uuid"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/redis/go-redis/v9"
     "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
     "google.golang.org/grpc/health"
     "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/reflection"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
     "gorm.io/driver/postgres"
     "gorm.io/gorm"
+    "gorm.io/gorm/logger"
 
+    "products-service/internal/journal"
     pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+    "shared/audit"
+    "shared/automigrate"
+    "shared/backfill"
+    "shared/canary"
+    "shared/dbinit"
+    "shared/drain"
+    "shared/healthcheck"
+    "shared/loadreport"
+    "shared/metrics"
+    "shared/pagination"
+    "shared/ratelimit"
+    "shared/recovery"
+    "shared/servedby"
+    "shared/slo"
+    "shared/sqlaudit"
+    "shared/statementtimeout"
+    "shared/tlsconfig"
     consulapi "github.com/hashicorp/consul/api"
 )
 
 const serviceName = "products-service"
 const servicePort = 50052
+const metricsPort = 9090
+
+// consulRegistrationCheckInterval is how often keepRegistered checks that the
+// Consul agent still knows this instance.
+const consulRegistrationCheckInterval = 10 * time.Second
+
+// defaultMaxConcurrentRPCs is used when MAX_CONCURRENT_RPCS is not set.
+const defaultMaxConcurrentRPCs = 100
+
+// defaultShutdownTimeout is used when SHUTDOWN_TIMEOUT is not set. It stays
+// under the 10 seconds Docker waits before killing a stopped container.
+const defaultShutdownTimeout = 8 * time.Second
+
+// defaultAvailabilitySLO and defaultLatencyP99TargetMs are the objectives
+// used when SLO_AVAILABILITY and SLO_LATENCY_P99_MS are not set.
+const (
+    defaultAvailabilitySLO    = 0.999
+    defaultLatencyP99TargetMs = 300
+)
+
+// defaultCountRefreshInterval is used when BUSINESS_METRICS_INTERVAL is not set.
+const defaultCountRefreshInterval = 30 * time.Second
 
 type Product struct {
     gorm.Model
+    UUID  string `gorm:"type:uuid;uniqueIndex;not null;default:gen_random_uuid()"`
     Name  string
     Price float64
+    // PriceCents is written alongside Price and will replace it once the
+    // products.price_cents backfill has filled it in for older rows.
+    PriceCents *int64
+    // Status hides archived products from listings without deleting them.
+    Status      string `gorm:"type:varchar(16);not null;default:'active';index"`
+    Description string `gorm:"type:text;not null;default:''"`
+    Brand       string `gorm:"not null;default:''"`
+    ImageURL    string `gorm:"type:text;not null;default:''"`
+    // ReviewStatus hides products from listings until a moderator approves
+    // them. It defaults to approved for products from before reviews.
+    ReviewStatus string `gorm:"type:varchar(16);not null;default:'approved';index"`
+    // CreatedBy is the actor that created the product, who is notified of
+    // its review.
+    CreatedBy string `gorm:"not null;default:''"`
+}
+
+func (p *Product) BeforeCreate(tx *gorm.DB) error {
+    if p.UUID == "" {
+        p.UUID = uuid.NewString()
+    }
+    return nil
+}
+
+func (p *Product) toProto() *pb.Product {
+    return &pb.Product{Id: fmt.Sprint(p.ID), Name: p.Name, Price: p.Price, UpdatedAt: timestamppb.New(p.UpdatedAt), Status: productStatuses[p.Status], Description: p.Description, Brand: p.Brand, ImageUrl: p.ImageURL, ReviewStatus: reviewStatuses[p.ReviewStatus]}
 }
 
 type server struct {
     pb.UnimplementedProductServiceServer
-    db *gorm.DB
+    db     *gorm.DB
+    events *eventHub
+    // redis is nil when REDIS_ADDR is not set.
+    redis        *redis.Client
+    recent       *recentCreates
+    transactions *transactionManager
+    // maxPageSize caps the page_size of every list RPC.
+    maxPageSize int
+    imports     *catalogImporter
+    // alternatives caches GetAlternativeProducts responses.
+    alternatives Cache
+    // tagSimilar caches GetTagSimilarProducts responses.
+    tagSimilar Cache
+    // priceGini caches the GetCatalogPriceGini response.
+    priceGini Cache
+    // reviewWebhook notifies vendors of reviews. It is nil when
+    // REVIEW_WEBHOOK_URL is unset.
+    reviewWebhook *reviewNotifier
+}
+
+// inTransaction runs fn in a database transaction bound to ctx. Handlers that
+// write to more than one table must go through it so a failure part way
+// leaves nothing behind.
+func (s *server) inTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+    return s.db.WithContext(ctx).Transaction(fn)
 }
 
+// createProduct stores a product validated by the v2 create path, together
+// with its outbox event. An identical product created within the dedup
+// window is returned instead of inserting a new one, with duplicate set.
+// Creates in a client transaction are not deduplicated, as the
+// transaction may yet be rolled back.
+func (s *server) createProduct(ctx context.Context, name, description, brand, imageURL string, priceCents int64) (product *Product, duplicate bool, err error) {
+    finish := func(*Product) {}
+    if transactionID(ctx) == "" {
+        var existing *Product
+        existing, finish, err = s.recent.claim(ctx, productFingerprint(tenantFromContext(ctx), actorFromContext(ctx), name, description, brand, imageURL, priceCents))
+        if err != nil {
+            return nil, false, err
+        }
+        if existing != nil {
+            return existing, true, nil
+        }
+    }
+
+    product = &Product{Name: name, Description: description, Brand: brand, ImageURL: imageURL, Price: priceFromCents(priceCents), PriceCents: &priceCents, Status: productStatusActive, ReviewStatus: initialReviewStatus(ctx), CreatedBy: actorFromContext(ctx)}
+    err = s.inRequestTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.Create(product).Error; err != nil {
+            return err
+        }
+        if _, err := recordProductVersion(ctx, tx, product, firstVersion); err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "create", "product", product.ID, map[string]interface{}{
+            "name":        name,
+            "description": description,
+            "brand":       brand,
+            "image_url":   imageURL,
+            "price_cents": priceCents,
+        }); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_CREATED, product)
+    })
+    if err != nil {
+        finish(nil)
+        return nil, false, err
+    }
+    finish(product)
+    return product, false, nil
+}
+
+// CreateProduct adapts the v1 request to v2 and creates the product through
+// the v2 path.
 func (s *server) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.ProductResponse, error) {
-    product := Product{Name: req.Name, Price: req.Price}
-    if result := s.db.Create(&product); result.Error != nil {
-        return nil, result.Error
+    priceCents, err := v1PriceCents(req.Price)
+    if err != nil {
+        return nil, err
+    }
+    product, duplicate, err := (&serverV2{core: s}).createProduct(ctx, &pbv2.CreateProductRequest{Name: req.Name, Description: req.Description, Brand: req.Brand, ImageUrl: req.ImageUrl, PriceCents: priceCents})
+    if err != nil {
+        return nil, err
     }
-    return &pb.ProductResponse{Product: &pb.Product{Id: fmt.Sprint(product.ID), Name: product.Name, Price: product.Price}}, nil
+    return &pb.ProductResponse{Product: product.toProto(), Duplicate: duplicate}, nil
 }
 
 func (s *server) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
+    // A string condition is SQL to GORM, so the id must be parsed rather
+    // than passed through.
+    id, err := strconv.ParseUint(req.Id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.Id)
+    }
     var product Product
-    if result := s.db.First(&product, req.Id); result.Error != nil {
+    if result := s.db.WithContext(ctx).First(&product, id); result.Error != nil {
         return nil, result.Error
     }
-    return &pb.ProductResponse{Product: &pb.Product{Id: fmt.Sprint(product.ID), Name: product.Name, Price: product.Price}}, nil
+    return &pb.ProductResponse{Product: product.toProto()}, nil
 }
 
 func main() {
@@ -54,40 +215,347 @@ func main() {
 
     // Connect to database with retry logic
     db := connectToDatabaseWithRetry()
-    db.AutoMigrate(&Product{})
+    queryCache := NewCachingPlugin(NewMemoryCache(queryCacheSweepInterval), queryCacheTTL)
+    if err := db.Use(queryCache); err != nil {
+        log.Fatalf("Failed to install query cache: %v", err)
+    }
+    if err := db.Use(sqlaudit.Plugin{}); err != nil {
+        log.Fatalf("Failed to install SQL audit: %v", err)
+    }
+    if err := metrics.RegisterDBStatsCollector(db, serviceName); err != nil {
+        log.Fatalf("Failed to register connection pool metrics: %v", err)
+    }
+    if err := enableVectorExtension(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := automigrate.Run(db, &Product{}, &DiscountCode{}, &OutboxEvent{}, &SelfTestProbe{}, &PriceAlert{}, &QuotaUsage{}, &Tag{}, &ProductTag{}, &TaxRuleSet{}, &ProductEmbedding{}, &audit.Entry{}, &StatusChangeLog{}, &ProductFAQ{}, &ProductVersion{}, &ProductCost{}, &PurchaseLimit{}, &backfill.Progress{}); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := migrateTagBitmaps(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := seedTaxRules(db); err != nil {
+        log.Fatalf("Failed to seed tax rules: %v", err)
+    }
+    if err := migrateProductNameTrigramIndex(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := audit.MigrateTrigger(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
 
     // Start gRPC server
-    lis, err := net.Listen("tcp", fmt.Sprintf(":%d", servicePort))
+    listenAddr, err := listenAddress()
+    if err != nil {
+        log.Fatalf("Invalid LISTEN_ADDR: %v", err)
+    }
+    lis, err := net.Listen("tcp", listenAddr)
     if err != nil {
         log.Fatalf("Failed to listen: %v", err)
     }
-    s := grpc.NewServer()
-    pb.RegisterProductServiceServer(s, &server{db: db})
+    apiKeys, err := loadAPIKeys()
+    if err != nil {
+        log.Fatalf("Failed to load API keys: %v", err)
+    }
+    auth := &authenticator{keys: apiKeys, anonymousModerator: getEnvBool("ANONYMOUS_MODERATOR", false)}
+    if apiKeys == nil {
+        log.Println("API_KEYS_JSON not set, API key authentication is disabled and moderator and admin methods are denied")
+        if auth.anonymousModerator {
+            log.Println("ANONYMOUS_MODERATOR is set, anonymous callers may review products")
+        }
+    }
+
+    maxConcurrentRPCs := getEnvInt("MAX_CONCURRENT_RPCS", defaultMaxConcurrentRPCs)
+    limiter := newConcurrencyLimiter(maxConcurrentRPCs)
+    loadReporter := loadreport.New(limiter.inFlight, maxConcurrentRPCs)
+    servedBy := servedby.Trailer(instanceID())
+    monitor, err := slo.NewMonitor(slo.SLOConfig{
+        ServiceName:        serviceName,
+        AvailabilitySLO:    getEnvFloat("SLO_AVAILABILITY", defaultAvailabilitySLO),
+        LatencyP99TargetMs: getEnvFloat("SLO_LATENCY_P99_MS", defaultLatencyP99TargetMs),
+    }, prometheus.DefaultRegisterer)
+    if err != nil {
+        log.Fatalf("Failed to register SLO metrics: %v", err)
+    }
+    unaryInterceptors := []grpc.UnaryServerInterceptor{servedBy.UnaryServerInterceptor, loadReporter.UnaryServerInterceptor, monitor.UnaryInterceptor, limiter.unaryInterceptor, auth.unaryAuthInterceptor, auth.unaryAuthzInterceptor, sqlaudit.UnaryServerInterceptor, statementtimeout.UnaryServerInterceptor}
+    if dir := os.Getenv("JOURNAL_DIR"); dir != "" {
+        j, err := journal.Open(journal.Config{
+            Dir:          dir,
+            MaxFileSize:  int64(getEnvInt("JOURNAL_MAX_BYTES", journal.DefaultMaxFileSize)),
+            MetadataKeys: getEnvList("JOURNAL_METADATA_KEYS"),
+            MaskFields:   getEnvList("JOURNAL_MASK_FIELDS"),
+        })
+        if err != nil {
+            log.Fatalf("Failed to open request journal: %v", err)
+        }
+        // Outermost, so that rejected requests and the full latency are recorded.
+        unaryInterceptors = append([]grpc.UnaryServerInterceptor{j.UnaryInterceptor}, unaryInterceptors...)
+        log.Printf("Journaling requests to %s", dir)
+    }
+    var redisClient *redis.Client
+    if redisAddr := os.Getenv("REDIS_ADDR"); redisAddr != "" {
+        // CreateProduct is not deduplicated through Redis: the dedup window
+        // in createProduct answers a repeat with the original product.
+        redisClient = redis.NewClient(&redis.Options{Addr: redisAddr})
+    } else {
+        log.Println("REDIS_ADDR not set, QR code caching is disabled")
+    }
+    if rateLimit := getEnvInt("RATE_LIMIT", 0); rateLimit > 0 {
+        var store ratelimit.RateLimitStore = ratelimit.NewLocalRateLimiter()
+        if redisClient != nil {
+            store = ratelimit.NewRedisRateLimiter(redisClient)
+        }
+        window := getEnvDuration("RATE_LIMIT_WINDOW", time.Second)
+        unaryInterceptors = append(unaryInterceptors, ratelimit.NewRateLimitInterceptor(store, rateLimit, window, rateLimitKey))
+    }
+    consul, err := newConsulClient()
+    if err != nil {
+        log.Fatalf("Failed to create consul client: %v", err)
+    }
+    limits, err := loadQuotaLimits(consul)
+    if err != nil {
+        log.Fatalf("Failed to load quota limits: %v", err)
+    }
+    quotas := &quotaEnforcer{db: db, limits: limits}
+    unaryInterceptors = append(unaryInterceptors, quotas.unaryInterceptor)
+    var alerter recovery.AlertFunc
+    if url := os.Getenv("PANIC_WEBHOOK_URL"); url != "" {
+        alerter = recovery.WebhookAlerter(url, instanceID())
+    }
+    // Outermost, so a panic anywhere in the chain becomes an Internal error.
+    unaryInterceptors = append([]grpc.UnaryServerInterceptor{recovery.NewRecoveryInterceptor(alerter)}, unaryInterceptors...)
+    creds, err := tlsconfig.ServerCredentials()
+    if err != nil {
+        log.Fatalf("Invalid TLS configuration: %v", err)
+    }
+    s := grpc.NewServer(
+        grpc.Creds(creds),
+        grpc.ChainUnaryInterceptor(unaryInterceptors...),
+        grpc.ChainStreamInterceptor(recovery.NewStreamRecoveryInterceptor(alerter), servedBy.StreamServerInterceptor, loadReporter.StreamServerInterceptor, limiter.streamInterceptor, auth.streamAuthInterceptor, auth.streamAuthzInterceptor, statementtimeout.StreamServerInterceptor),
+    )
+    canaryConfig := canary.FromEnv()
+    tester := &selfTester{db: db, consul: consul, redis: redisClient, degradation: newDegradationReporter(consul, canaryConfig)}
+
+    transactions := newTransactionManager(db, getEnvDuration("TRANSACTION_TIMEOUT", defaultTransactionTimeout), getEnvInt("MAX_OPEN_TRANSACTIONS", defaultMaxOpenTransactions))
+    events := newEventHub(instanceName())
+    relay, err := newOutboxRelay(db, events)
+    if err != nil {
+        log.Fatalf("Failed to read the product outbox: %v", err)
+    }
+    go relay.run(context.Background())
+    go queryCache.invalidateOn(context.Background(), events, "products")
+    srv := &server{
+        db:           db,
+        events:       events,
+        redis:        redisClient,
+        recent:       newRecentCreates(dedupWindow()),
+        transactions: transactions,
+        maxPageSize:  getEnvInt("MAX_PAGE_SIZE", pagination.DefaultMaxPageSize),
+        imports:      newCatalogImporter(getEnvList("IMPORT_ALLOWED_DOMAINS")),
+        alternatives: NewMemoryCache(queryCacheSweepInterval),
+        tagSimilar:   NewMemoryCache(queryCacheSweepInterval),
+        priceGini:    NewMemoryCache(queryCacheSweepInterval),
+    }
+    if url := os.Getenv("REVIEW_WEBHOOK_URL"); url != "" {
+        srv.reviewWebhook = newReviewNotifier(url)
+    }
+    pb.RegisterProductServiceServer(s, srv)
+    pbv2.RegisterProductServiceServer(s, &serverV2{core: srv})
+    pb.RegisterSelfTestServiceServer(s, &selfTestServer{tester: tester})
+    pb.RegisterQuotaServiceServer(s, &quotaServer{quotas: quotas})
+    pb.RegisterTransactionServiceServer(s, &transactionServer{transactions: transactions})
+    backfills := backfill.NewRunner(db, getEnvInt("BACKFILL_BATCH_SIZE", defaultBackfillBatchSize), getEnvDuration("BACKFILL_PAUSE", defaultBackfillPause))
+    registerBackfills(backfills)
+    pb.RegisterBackfillServiceServer(s, &backfillServer{runner: backfills})
+    pb.RegisterSnapshotServiceServer(s, &snapshotServer{db: db, allowRestore: getEnvBool("ALLOW_RESTORE", false)})
+    pb.RegisterAuditServiceServer(s, &auditServer{db: db, maxPageSize: srv.maxPageSize})
+    pb.RegisterDebugServiceServer(s, &debugServer{db: db, consul: consul, canary: canaryConfig, flags: map[string]bool{
+        "api_key_auth":    apiKeys != nil,
+        "redis":           redisClient != nil,
+        "rate_limit":      getEnvInt("RATE_LIMIT", 0) > 0,
+        "request_journal": os.Getenv("JOURNAL_DIR") != "",
+        "allow_restore":   getEnvBool("ALLOW_RESTORE", false),
+        "consul_required": getEnvBool("CONSUL_REQUIRED", false),
+        "tls":             tlsconfig.Enabled(),
+    }})
+    reflection.Register(s)
+
+    // ctx is cancelled on SIGINT or SIGTERM, which stops the server gracefully.
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
 
     // Register health check
     healthServer := health.NewServer()
     grpc_health_v1.RegisterHealthServer(s, healthServer)
-    healthServer.SetServingStatus("products.ProductService", grpc_health_v1.HealthCheckResponse_SERVING)
+    pb.RegisterDrainServiceServer(s, &drainServer{health: healthServer, limiter: limiter})
+    watcher := healthcheck.NewHealthWatcher(serviceName, db, healthServer, getEnvDuration("HEALTH_CHECK_INTERVAL", healthcheck.DefaultInterval), "products.ProductService", "products.v2.ProductService")
+    warmUp(db, watcher, getEnvDuration("READINESS_DELAY", 0))
+    go watcher.Run(ctx)
 
-    // Register with Consul
-    if err := registerServiceWithConsul(); err != nil {
-        log.Fatalf("Failed to register with Consul: %v", err)
+    // Register with Consul. Unless CONSUL_REQUIRED is set, failing to is not
+    // fatal: the instance serves anyway and keepRegistered retries.
+    deregisterStaleInstance(consul)
+    if err := registerServiceWithConsul(consul, tester.degradation.registration()); err != nil {
+        if getEnvBool("CONSUL_REQUIRED", false) {
+            log.Fatalf("Failed to register with Consul: %v", err)
+        }
+        log.Printf("WARNING: %s is not registered with Consul and cannot be discovered until it is; retrying every %v: %v", serviceName, consulRegistrationCheckInterval, err)
     }
+    keepRegistered(consul, tester.degradation.registration)
 
-    log.Printf("%s gRPC server listening at %v", serviceName, lis.Addr())
+    var readyz http.HandlerFunc
+    if interval := getEnvDuration("SELF_TEST_INTERVAL", 0); interval > 0 {
+        tester.runPeriodically(interval)
+        readyz = tester.readyzHandler
+    }
+    metrics.StartServer(serviceName, metricsPort, readyz, monitor)
+    startProductCountCollector(db, getEnvDuration("BUSINESS_METRICS_INTERVAL", defaultCountRefreshInterval))
+    startTagBitmapRefresher(db, getEnvDuration("TAG_BITMAP_REFRESH_INTERVAL", defaultTagBitmapRefreshInterval))
+    startCatalogMetricsCollector(db, getEnvDuration("CATALOG_METRICS_INTERVAL", defaultCatalogMetricsInterval))
+    backfills.Start(ctx)
+
+    log.Printf("%s gRPC server listening at %v (max %d concurrent RPCs)", serviceName, lis.Addr(), maxConcurrentRPCs)
+    shutdownTimeout := getEnvDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+    go func() {
+        <-ctx.Done()
+        log.Printf("Shutting down %s, waiting up to %v for %d in-flight requests", serviceName, shutdownTimeout, limiter.inFlight())
+        healthServer.Shutdown()
+        drain.Stop(s, serviceName, limiter.inFlight, shutdownTimeout)
+    }()
     if err := s.Serve(lis); err != nil {
         log.Fatalf("Failed to serve: %v", err)
     }
 }
 
+// instanceID is this instance's Consul registration ID, which is unique
+// among the service's replicas.
+func instanceID() string {
+    return serviceName + "-" + instanceName()
+}
+
+// instanceName identifies this process in messages sent to other services.
+func instanceName() string {
+    if hostname, err := os.Hostname(); err == nil {
+        return hostname
+    }
+    return serviceName
+}
+
+// listenAddress builds the gRPC bind address from LISTEN_ADDR, which holds
+// only the host part (e.g. "127.0.0.1"). Unset or ":" binds all interfaces.
+func listenAddress() (string, error) {
+    host := strings.Trim(strings.TrimSuffix(os.Getenv("LISTEN_ADDR"), ":"), "[]")
+    if host == "" {
+        return fmt.Sprintf(":%d", servicePort), nil
+    }
+    ip := net.ParseIP(host)
+    if ip == nil {
+        return "", fmt.Errorf("%q is not an IP address", host)
+    }
+    if ip.IsLoopback() {
+        log.Printf("Listening on loopback %s only; Consul health checks from other containers will fail", host)
+    }
+    return net.JoinHostPort(host, strconv.Itoa(servicePort)), nil
+}
+
+func getEnvInt(key string, fallback int) int {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    n, err := strconv.Atoi(value)
+    if err != nil || n <= 0 {
+        log.Printf("Invalid %s=%q, using default %d", key, value, fallback)
+        return fallback
+    }
+    return n
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+    var values []string
+    for _, value := range strings.Split(os.Getenv(key), ",") {
+        if value = strings.TrimSpace(value); value != "" {
+            values = append(values, value)
+        }
+    }
+    return values
+}
+
+func getEnvBool(key string, fallback bool) bool {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    b, err := strconv.ParseBool(value)
+    if err != nil {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return b
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    f, err := strconv.ParseFloat(value, 64)
+    if err != nil || f <= 0 {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return f
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    d, err := time.ParseDuration(value)
+    if err != nil || d <= 0 {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return d
+}
+
+// newDatabaseLogger logs queries slower than SLOW_QUERY_THRESHOLD (default
+// 200ms) with their SQL and duration, along with GORM's warnings and errors.
+// Statements are logged with placeholders rather than parameter values, which
+// may hold personal data such as emails.
+func newDatabaseLogger() logger.Interface {
+    return logger.New(log.Default(), logger.Config{
+        SlowThreshold:             getEnvDuration("SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+        LogLevel:                  logger.Warn,
+        IgnoreRecordNotFoundError: true,
+        ParameterizedQueries:      true,
+    })
+}
+
 func connectToDatabaseWithRetry() *gorm.DB {
     dsn := "host=products-db user=user password=password dbname=products_db port=5432 sslmode=disable"
 
-    var db *gorm.DB
-    var err error
+    // Postgres cancels any statement running longer than
+    // PG_STATEMENT_TIMEOUT, even if the request's context is never cancelled.
+    if timeout := getEnvDuration("PG_STATEMENT_TIMEOUT", 0); timeout > 0 {
+        dsn = statementtimeout.DSN(dsn, timeout)
+        log.Printf("Postgres statement timeout is %v", timeout)
+    }
+
+    initSQL := dbinit.FromEnv()
+    if len(initSQL) > 0 {
+        log.Printf("Running %d init statements on each database connection", len(initSQL))
+    }
+    pool, err := dbinit.Open(dsn, initSQL)
+    if err != nil {
+        log.Fatalf("Invalid database configuration: %v", err)
+    }
 
+    var db *gorm.DB
     for i := 0; i < 30; i++ {
-        db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+        db, err = gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{Logger: newDatabaseLogger()})
         if err == nil {
             log.Println("Successfully connected to database")
             break
@@ -104,33 +572,105 @@ func connectToDatabaseWithRetry() *gorm.DB {
     return db
 }
 
-func registerServiceWithConsul() error {
+func newConsulClient() (*consulapi.Client, error) {
     config := consulapi.DefaultConfig()
     if addr := os.Getenv("CONSUL_HTTP_ADDR"); addr != "" {
         config.Address = addr
     }
+    return consulapi.NewClient(config)
+}
 
-    consul, err := consulapi.NewClient(config)
-    if err != nil {
-        return err
+func registerServiceWithConsul(consul *consulapi.Client, registration *consulapi.AgentServiceRegistration) error {
+    err := consul.Agent().ServiceRegister(registration)
+    if err == nil {
+        log.Printf("Successfully registered %s with Consul as %s at %s:%d", serviceName, instanceID(), serviceName, servicePort)
+        deregisterLegacyInstance(consul)
+    }
+    return err
+}
+
+// deregisterLegacyInstance removes the registration an older release made
+// under the plain service name, before instances had their own IDs, if it
+// points at the same address as this instance. It must only run once this
+// instance is registered, so the service stays discoverable.
+func deregisterLegacyInstance(consul *consulapi.Client) {
+    if instanceID() == serviceName {
+        return
+    }
+    service, _, err := consul.Agent().Service(serviceName, nil)
+    if err != nil || service == nil {
+        return
+    }
+    if service.Service != serviceName || service.Address != serviceName || service.Port != servicePort {
+        log.Printf("Leaving legacy %s registration in Consul, it points at %s:%d rather than this instance", serviceName, service.Address, service.Port)
+        return
     }
+    if err := consul.Agent().ServiceDeregister(serviceName); err != nil {
+        log.Printf("Failed to deregister legacy %s registration from Consul: %v", serviceName, err)
+        return
+    }
+    log.Printf("Deregistered legacy %s registration from Consul, replaced by %s", serviceName, instanceID())
+}
 
+// serviceRegistration describes this instance to Consul. Each degradation
+// reason is added as a "degraded:<reason>" tag, so the gateway can prefer
+// other instances, and a canary carries canaryConfig's metadata.
+func serviceRegistration(degraded []string, canaryConfig canary.Config) *consulapi.AgentServiceRegistration {
     // Use the service name as the address within the Docker network
     registration := &consulapi.AgentServiceRegistration{
-        ID:      serviceName,
+        ID:      instanceID(),
         Name:    serviceName,
         Port:    servicePort,
         Address: serviceName,
         Check: &consulapi.AgentServiceCheck{
             GRPC:                           fmt.Sprintf("%s:%d", serviceName, servicePort),
+            GRPCUseTLS:                     tlsconfig.Enabled(),
             Interval:                       "10s",
             DeregisterCriticalServiceAfter: "30s",
         },
+        Meta: canaryConfig.ConsulMeta(),
+    }
+    for _, reason := range degraded {
+        registration.Tags = append(registration.Tags, degradedTagPrefix+reason)
+    }
+    if len(degraded) > 0 {
+        if registration.Meta == nil {
+            registration.Meta = make(map[string]string)
+        }
+        registration.Meta["degraded"] = strings.Join(degraded, ",")
     }
+    return registration
+}
 
-    err = consul.Agent().ServiceRegister(registration)
-    if err == nil {
-        log.Printf("Successfully registered %s with Consul at %s:%d", serviceName, serviceName, servicePort)
+// deregisterStaleInstance removes a registration left behind under this
+// instance's ID by a previous process, e.g. one that was killed before it
+// could deregister. Its health check may still report the dead instance as
+// passing, so registering over it would let Consul briefly route to it.
+func deregisterStaleInstance(consul *consulapi.Client) {
+    id := instanceID()
+    service, _, err := consul.Agent().Service(id, nil)
+    if err != nil || service == nil {
+        return
     }
-    return err
+    if err := consul.Agent().ServiceDeregister(id); err != nil {
+        log.Printf("Failed to deregister stale %s registration from Consul: %v", id, err)
+        return
+    }
+    log.Printf("Deregistered stale %s registration from Consul before registering", id)
+}
+
+// keepRegistered registers the instance again whenever the Consul agent does
+// not know it, e.g. because registration failed at startup or the agent
+// restarted and lost it.
+func keepRegistered(consul *consulapi.Client, registration func() *consulapi.AgentServiceRegistration) {
+    go func() {
+        for range time.Tick(consulRegistrationCheckInterval) {
+            if service, _, err := consul.Agent().Service(instanceID(), nil); err == nil && service != nil {
+                continue
+            }
+            if err := registerServiceWithConsul(consul, registration()); err != nil {
+                log.Printf("Still not registered with Consul, retrying in %v: %v", consulRegistrationCheckInterval, err)
+            }
+        }
+    }()
 }
\ No newline at end of file
@@ -0,0 +1,34 @@
+package main
+
+import (
+    "log"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "gorm.io/gorm"
+)
+
+var productsTotal = prometheus.NewGauge(prometheus.GaugeOpts{
+    Name: "products_total",
+    Help: "Number of products in the database, refreshed periodically.",
+})
+
+func init() {
+    prometheus.MustRegister(productsTotal)
+}
+
+// startProductCountCollector refreshes products_total every interval. A failed
+// count is logged and the gauge keeps its last value.
+func startProductCountCollector(db *gorm.DB, interval time.Duration) {
+    go func() {
+        for {
+            var count int64
+            if err := db.Model(&Product{}).Count(&count).Error; err != nil {
+                log.Printf("Failed to count products for metrics: %v", err)
+            } else {
+                productsTotal.Set(float64(count))
+            }
+            time.Sleep(interval)
+        }
+    }()
+}
@@ -0,0 +1,12 @@
+-- Drops everything the baseline created except the intarray extension,
+-- which other schemas in the database may use.
+
+DROP TABLE IF EXISTS product_tag_bitmaps;
+DROP TABLE IF EXISTS product_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS quota_usages;
+DROP TABLE IF EXISTS price_alerts;
+DROP TABLE IF EXISTS self_test_probes;
+DROP TABLE IF EXISTS product_outbox;
+DROP TABLE IF EXISTS discount_codes;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,96 @@
+-- Baseline: the schema products-service created with GORM AutoMigrate and
+-- migrateTagBitmaps before migrations were versioned. Every statement is
+-- idempotent, so databases the service already migrated accept it.
+
+CREATE EXTENSION IF NOT EXISTS intarray;
+
+CREATE TABLE IF NOT EXISTS "products" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "uuid" uuid NOT NULL DEFAULT gen_random_uuid(),
+    "name" text,
+    "price" decimal,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_products_deleted_at" ON "products" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_products_uuid" ON "products" ("uuid");
+
+CREATE TABLE IF NOT EXISTS "discount_codes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "code" text,
+    "type" text,
+    "value" decimal,
+    "min_order_value" decimal,
+    "expires_at" timestamptz,
+    "max_uses" bigint,
+    "current_uses" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_discount_codes_code" ON "discount_codes" ("code");
+CREATE INDEX IF NOT EXISTS "idx_discount_codes_deleted_at" ON "discount_codes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "product_outbox" (
+    "id" bigserial,
+    "type" integer,
+    "product" bytea,
+    "price_alert" bytea,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "self_test_probes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "price_alerts" (
+    "id" bigserial,
+    "user_id" text,
+    "product_id" bigint,
+    "target_price" decimal,
+    "triggered" boolean,
+    "triggered_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_price_alerts_triggered" ON "price_alerts" ("triggered");
+CREATE INDEX IF NOT EXISTS "idx_price_alerts_product_id" ON "price_alerts" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_price_alerts_user_id" ON "price_alerts" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "quota_usages" (
+    "tenant" text,
+    "method" text,
+    "period" text,
+    "count" bigint NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("tenant", "method", "period")
+);
+
+CREATE TABLE IF NOT EXISTS "tags" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "slug" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_slug" ON "tags" ("slug");
+
+CREATE TABLE IF NOT EXISTS "product_tags" (
+    "product_id" bigint,
+    "tag_id" bigint,
+    PRIMARY KEY ("product_id", "tag_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_tags_tag_id" ON "product_tags" ("tag_id");
+
+-- Tag search (tags.go), rebuilt from product_tags by refreshTagBitmaps.
+CREATE TABLE IF NOT EXISTS product_tag_bitmaps (
+    product_id bigint PRIMARY KEY,
+    tag_ids integer[] NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_product_tag_bitmaps_tag_ids
+    ON product_tag_bitmaps USING gin (tag_ids gin__int_ops);
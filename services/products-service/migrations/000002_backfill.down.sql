@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS backfill_progress;
+ALTER TABLE "products" DROP COLUMN IF EXISTS "price_cents";
@@ -0,0 +1,16 @@
+-- products.price_cents and the progress table for resumable backfills. The column is
+-- nullable until its backfill has filled in the older rows.
+
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "price_cents" bigint;
+
+CREATE TABLE IF NOT EXISTS "backfill_progress" (
+    "name" text,
+    "table" text NOT NULL,
+    "last_id" bigint NOT NULL,
+    "processed" bigint NOT NULL,
+    "done" boolean NOT NULL,
+    "started_at" timestamptz,
+    "updated_at" timestamptz,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("name")
+);
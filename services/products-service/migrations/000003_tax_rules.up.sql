@@ -0,0 +1,14 @@
+-- Tax rules for CalculateTax (tax.go). product_category is a tag slug, or *
+-- for every product; tax_rate is a fraction. products-service seeds the
+-- default rules at startup (seedTaxRules), so they are not repeated here.
+
+CREATE TABLE IF NOT EXISTS "tax_rule_sets" (
+    "id" bigserial,
+    "country_code" char(2) NOT NULL,
+    "region_code" text NOT NULL DEFAULT '',
+    "product_category" text NOT NULL DEFAULT '*',
+    "tax_rate" decimal NOT NULL,
+    "tax_name" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tax_rule_sets_rule" ON "tax_rule_sets" ("country_code","region_code","product_category");
@@ -0,0 +1,4 @@
+-- Product archiving (archive.go). Existing products stay listed.
+
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "status" varchar(16) NOT NULL DEFAULT 'active';
+CREATE INDEX IF NOT EXISTS "idx_products_status" ON "products" ("status");
@@ -0,0 +1,12 @@
+-- Product embeddings for GetSimilarProducts (similar.go). Needs pgvector
+-- installed in the database, e.g. the pgvector/pgvector image. The vector
+-- column has no fixed dimension so the model can change.
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS "product_embeddings" (
+    "product_id" bigint,
+    "embedding" vector NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("product_id")
+);
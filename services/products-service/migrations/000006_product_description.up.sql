@@ -0,0 +1,3 @@
+-- Product descriptions. Existing products get an empty one.
+
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "description" text NOT NULL DEFAULT '';
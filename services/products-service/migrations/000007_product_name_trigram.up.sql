@@ -0,0 +1,4 @@
+-- Trigram matching on product names for FuzzySearchProducts (fuzzysearch.go).
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS "idx_products_name_trgm" ON "products" USING gist ("name" gist_trgm_ops);
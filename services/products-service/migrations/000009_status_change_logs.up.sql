@@ -0,0 +1,14 @@
+-- The log of product status changes made by ArchiveProduct,
+-- UnarchiveProduct and BulkUpdateProductStatus, with the reason given.
+
+CREATE TABLE IF NOT EXISTS "status_change_logs" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "old_status" varchar(16) NOT NULL,
+    "new_status" varchar(16) NOT NULL,
+    "reason" text NOT NULL DEFAULT '',
+    "changed_by" text NOT NULL,
+    "changed_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_status_change_logs_product_id" ON "status_change_logs" ("product_id");
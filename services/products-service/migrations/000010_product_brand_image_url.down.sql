@@ -0,0 +1,2 @@
+ALTER TABLE "products" DROP COLUMN IF EXISTS image_url;
+ALTER TABLE "products" DROP COLUMN IF EXISTS brand;
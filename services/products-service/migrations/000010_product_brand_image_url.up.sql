@@ -0,0 +1,6 @@
+-- Product brands and image URLs, for the Google Shopping feed. Existing
+-- products get empty ones, and are left out of the feed until they have an
+-- image.
+
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "brand" text NOT NULL DEFAULT '';
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "image_url" text NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+-- Product FAQ entries, shown on a product's page by order_index, which runs
+-- from 0 without gaps per product.
+
+CREATE TABLE IF NOT EXISTS "product_faqs" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "question" text NOT NULL,
+    "answer" text NOT NULL,
+    "order_index" bigint NOT NULL,
+    "created_by" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_faqs_order" ON "product_faqs" ("product_id", "order_index");
@@ -0,0 +1,19 @@
+-- Immutable product snapshots (versions.go), one per create and update.
+-- Products created before this migration have no versions until their
+-- first update.
+
+CREATE TABLE IF NOT EXISTS "product_versions" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "major" bigint NOT NULL,
+    "minor" bigint NOT NULL,
+    "patch" bigint NOT NULL,
+    "version" text NOT NULL,
+    "name" text NOT NULL,
+    "price" decimal,
+    "snapshot_json" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "created_by" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_product_versions_version" ON "product_versions" ("product_id", "major", "minor", "patch");
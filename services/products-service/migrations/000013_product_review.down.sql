@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_review_status;
+ALTER TABLE "products" DROP COLUMN IF EXISTS created_by;
+ALTER TABLE "products" DROP COLUMN IF EXISTS review_status;
@@ -0,0 +1,6 @@
+-- Moderator review of new products (review.go). Existing products are
+-- approved, so the live catalog stays listed.
+
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "review_status" varchar(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "created_by" text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "idx_products_review_status" ON "products" ("review_status");
@@ -0,0 +1,15 @@
+-- Supplier costs for margin analysis (costs.go). A product's cost periods
+-- do not overlap, and at most one starts at any instant.
+
+CREATE TABLE IF NOT EXISTS "product_costs" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "supplier_cost_cents" bigint NOT NULL,
+    "currency" char(3) NOT NULL,
+    "effective_from" timestamptz NOT NULL,
+    "effective_to" timestamptz,
+    "created_by" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_product_costs_effective_from" ON "product_costs" ("product_id", "effective_from");
@@ -0,0 +1,9 @@
+-- Per-user purchase limits (purchaselimits.go), at most one per product.
+
+CREATE TABLE IF NOT EXISTS "purchase_limits" (
+    "product_id" bigint,
+    "max_quantity_per_user" integer NOT NULL,
+    "window_hours" integer NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("product_id")
+);
@@ -0,0 +1,90 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// newMockDB returns a GORM connection whose SQL is checked against the
+// expectations set on mock. Unexpected statements fail the test.
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+    t.Helper()
+    conn, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Error(err)
+        }
+    })
+    return db, mock
+}
+
+// expectAudit expects the audit entry a mutation of an entity writes.
+func expectAudit(mock sqlmock.Sqlmock, action, entityType string) {
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs(action, entityType, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// expectProductVersion expects the snapshot a create or an update records as
+// the product's next version.
+func expectProductVersion(mock sqlmock.Sqlmock) {
+    mock.ExpectQuery(`INSERT INTO "product_versions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// newTestDatabase connects to the PostgreSQL server in TEST_DATABASE_DSN,
+// e.g. "host=localhost user=user password=password dbname=products_test
+// port=5432 sslmode=disable", and returns a connection to a schema of its
+// own that is dropped when the test ends. Tests that need real SQL
+// semantics, such as concurrent upserts, are skipped when it is not set.
+func newTestDatabase(t *testing.T) *gorm.DB {
+    t.Helper()
+    dsn := os.Getenv("TEST_DATABASE_DSN")
+    if dsn == "" {
+        t.Skip("TEST_DATABASE_DSN is not set")
+    }
+    config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+    admin, err := gorm.Open(postgres.Open(dsn), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+    if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Stay well under the server's connection limit however many goroutines
+    // a test starts.
+    if conn, err := db.DB(); err == nil {
+        conn.SetMaxOpenConns(20)
+    }
+    t.Cleanup(func() {
+        if conn, err := db.DB(); err == nil {
+            conn.Close()
+        }
+        if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+            t.Errorf("dropping test schema %s: %v", schema, err)
+        }
+        if conn, err := admin.DB(); err == nil {
+            conn.Close()
+        }
+    })
+    return db
+}
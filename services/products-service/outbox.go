@@ -0,0 +1,191 @@
+package main
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// outboxPollInterval is how often each instance reads new outbox rows.
+const outboxPollInterval = 200 * time.Millisecond
+
+// outboxBatchSize caps the rows read by a single poll.
+const outboxBatchSize = 500
+
+// outboxGapTimeout is how long the relay waits for a missing outbox ID
+// before skipping it. IDs are taken when a row is inserted, so a transaction
+// that commits late leaves a gap until it does, and one that rolls back
+// leaves a gap for good.
+const outboxGapTimeout = 5 * time.Second
+
+// outboxRetention is how long relayed rows are kept.
+const outboxRetention = time.Hour
+
+// OutboxEvent is a product change, written in the same transaction as the
+// change itself. Every instance relays every row to its own watchers, so a
+// change committed on one instance reaches clients streaming from another.
+// The ID doubles as the event's sequence number.
+type OutboxEvent struct {
+    ID      uint64 `gorm:"primaryKey"`
+    Type    pb.ProductEventType
+    Product []byte // a marshalled pb.Product
+    // PriceAlert is a marshalled pb.PriceAlert for
+    // PRODUCT_PRICE_ALERT_TRIGGERED events, and empty otherwise.
+    PriceAlert []byte
+    CreatedAt  time.Time
+}
+
+func (OutboxEvent) TableName() string {
+    return "product_outbox"
+}
+
+// recordProductEvent adds a change event for product to the outbox. tx must
+// be the transaction that writes the change, so the event is relayed if and
+// only if the change commits.
+func recordProductEvent(tx *gorm.DB, eventType pb.ProductEventType, product *Product) error {
+    data, err := proto.Marshal(product.toProto())
+    if err != nil {
+        return err
+    }
+    return tx.Create(&OutboxEvent{Type: eventType, Product: data}).Error
+}
+
+// recordPriceAlertEvent adds a PRODUCT_PRICE_ALERT_TRIGGERED event for alert
+// to the outbox, in the transaction that marks the alert triggered.
+func recordPriceAlertEvent(tx *gorm.DB, alert *PriceAlert, product *Product) error {
+    data, err := proto.Marshal(product.toProto())
+    if err != nil {
+        return err
+    }
+    alertData, err := proto.Marshal(alert.toProto())
+    if err != nil {
+        return err
+    }
+    return tx.Create(&OutboxEvent{Type: pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED, Product: data, PriceAlert: alertData}).Error
+}
+
+// outboxCursor reads outbox rows in ID order. It stops at a missing ID
+// until the row commits or outboxGapTimeout passes, so rows are returned in
+// sequence order.
+type outboxCursor struct {
+    db  *gorm.DB
+    now func() time.Time
+
+    // after is the last ID returned or given up on.
+    after uint64
+    // gapSince is when the cursor first found the row after `after` missing.
+    gapSince time.Time
+}
+
+// newestOutboxID returns the ID of the newest committed outbox row, or 0.
+func newestOutboxID(db *gorm.DB) (uint64, error) {
+    var id uint64
+    err := db.Model(&OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+    return id, err
+}
+
+// next returns the rows added since the last call.
+func (c *outboxCursor) next(ctx context.Context) ([]OutboxEvent, error) {
+    var rows []OutboxEvent
+    err := c.db.WithContext(ctx).Where("id > ?", c.after).Order("id").Limit(outboxBatchSize).Find(&rows).Error
+    if err != nil {
+        return nil, err
+    }
+    for i, row := range rows {
+        if row.ID != c.after+1 {
+            if c.gapSince.IsZero() {
+                c.gapSince = c.now()
+            }
+            if c.now().Sub(c.gapSince) < outboxGapTimeout {
+                return rows[:i], nil
+            }
+        }
+        c.gapSince = time.Time{}
+        c.after = row.ID
+    }
+    return rows, nil
+}
+
+// outboxRelay publishes outbox rows to an eventHub in ID order.
+type outboxRelay struct {
+    outboxCursor
+    hub *eventHub
+}
+
+// newOutboxRelay returns a relay that starts after the newest row, since
+// watchers only receive changes made after they subscribe.
+func newOutboxRelay(db *gorm.DB, hub *eventHub) (*outboxRelay, error) {
+    after, err := newestOutboxID(db)
+    if err != nil {
+        return nil, err
+    }
+    return &outboxRelay{outboxCursor: outboxCursor{db: db, now: time.Now, after: after}, hub: hub}, nil
+}
+
+// run polls the outbox until ctx is cancelled, and prunes rows older than
+// outboxRetention.
+func (r *outboxRelay) run(ctx context.Context) {
+    ticker := time.NewTicker(outboxPollInterval)
+    defer ticker.Stop()
+    lastPrune := r.now()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        if err := r.poll(ctx); err != nil {
+            log.Printf("Failed to read the product outbox: %v", err)
+        }
+        if r.now().Sub(lastPrune) >= outboxRetention {
+            lastPrune = r.now()
+            if err := r.db.WithContext(ctx).Where("created_at < ?", lastPrune.Add(-outboxRetention)).Delete(&OutboxEvent{}).Error; err != nil {
+                log.Printf("Failed to prune the product outbox: %v", err)
+            }
+        }
+    }
+}
+
+// productEvent decodes row into the event watchers receive.
+func (row *OutboxEvent) productEvent() (*pb.ProductEvent, error) {
+    product := &pb.Product{}
+    if err := proto.Unmarshal(row.Product, product); err != nil {
+        return nil, err
+    }
+    event := &pb.ProductEvent{
+        Type:       row.Type,
+        Product:    product,
+        OccurredAt: timestamppb.New(row.CreatedAt),
+        Sequence:   int64(row.ID),
+    }
+    if len(row.PriceAlert) > 0 {
+        event.PriceAlert = &pb.PriceAlert{}
+        if err := proto.Unmarshal(row.PriceAlert, event.PriceAlert); err != nil {
+            return nil, err
+        }
+    }
+    return event, nil
+}
+
+// poll publishes the rows added since the last poll, in sequence order.
+func (r *outboxRelay) poll(ctx context.Context) error {
+    rows, err := r.next(ctx)
+    if err != nil {
+        return err
+    }
+    for i := range rows {
+        event, err := rows[i].productEvent()
+        if err != nil {
+            log.Printf("Skipping unreadable outbox row %d: %v", rows[i].ID, err)
+            continue
+        }
+        r.hub.publish(event)
+    }
+    return nil
+}
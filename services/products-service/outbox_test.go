@@ -0,0 +1,148 @@
+package main
+
+import (
+    "context"
+    "database/sql/driver"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+
+    pb "products-service/proto/gen/proto"
+    "shared/fakedata"
+)
+
+// capturedBytes matches any []byte argument and keeps it.
+type capturedBytes struct {
+    data []byte
+}
+
+func (c *capturedBytes) Match(v driver.Value) bool {
+    data, ok := v.([]byte)
+    c.data = data
+    return ok
+}
+
+func outboxRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "type", "product", "created_at"})
+}
+
+// publishedSequences drains the events the hub delivered to sub.
+func publishedSequences(sub *subscription) []int64 {
+    var got []int64
+    for len(sub.events) > 0 {
+        got = append(got, (<-sub.events).Sequence)
+    }
+    return got
+}
+
+func equalSequences(a, b []int64) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
+
+func TestCreateProductWritesOutboxInTransaction(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, events: newEventHub("test")}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).WillReturnError(errors.New("disk full"))
+    mock.ExpectRollback()
+
+    // Without its event the product must not be created either, or watchers
+    // and caches would never hear of it.
+    if _, err := s.CreateProduct(context.Background(), fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t))); err == nil {
+        t.Fatal("CreateProduct succeeded although its outbox row was not written")
+    }
+}
+
+func TestOutboxRelayWaitsForGaps(t *testing.T) {
+    db, mock := newMockDB(t)
+    hub := newEventHub("test")
+    sub := hub.subscribe()
+    defer hub.unsubscribe(sub)
+    now := time.Now()
+    relay := &outboxRelay{outboxCursor: outboxCursor{db: db, now: func() time.Time { return now }}, hub: hub}
+
+    // Row 3 belongs to a transaction that has not committed yet.
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox" WHERE id > \$1 ORDER BY id`).WithArgs(0).
+        WillReturnRows(outboxRows().AddRow(1, 1, nil, now).AddRow(2, 2, nil, now).AddRow(4, 2, nil, now))
+    // It commits before the relay gives up on it.
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox"`).WithArgs(2).
+        WillReturnRows(outboxRows().AddRow(3, 2, nil, now).AddRow(4, 2, nil, now).AddRow(6, 2, nil, now))
+    // Row 5 is never committed.
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox"`).WithArgs(4).
+        WillReturnRows(outboxRows().AddRow(6, 2, nil, now))
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox"`).WithArgs(4).
+        WillReturnRows(outboxRows().AddRow(6, 2, nil, now))
+
+    steps := []struct {
+        advance time.Duration
+        want    []int64
+    }{
+        {0, []int64{1, 2}},
+        {time.Second, []int64{3, 4}},
+        {time.Second, nil},
+        {outboxGapTimeout, []int64{6}},
+    }
+    for i, step := range steps {
+        now = now.Add(step.advance)
+        if err := relay.poll(context.Background()); err != nil {
+            t.Fatalf("poll %d: %v", i+1, err)
+        }
+        if got := publishedSequences(sub); !equalSequences(got, step.want) {
+            t.Errorf("poll %d published %v, want %v", i+1, got, step.want)
+        }
+    }
+}
+
+func TestChangeOnOneInstanceReachesWatchersOfAnother(t *testing.T) {
+    dbA, mockA := newMockDB(t)
+    dbB, mockB := newMockDB(t)
+    a := &server{db: dbA, events: newEventHub("a")}
+    b := &server{db: dbB, events: newEventHub("b")}
+    relayB := &outboxRelay{outboxCursor: outboxCursor{db: dbB, now: time.Now, after: 6}, hub: b.events}
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    stream := newFakeServerStream[pb.CacheInvalidation](ctx)
+    go b.WatchCacheInvalidations(&pb.WatchCacheInvalidationsRequest{}, stream)
+    waitForSubscribers(t, b.events, 1)
+
+    // The product is created on instance A...
+    var product capturedBytes
+    mockA.ExpectBegin()
+    mockA.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+    expectProductVersion(mockA)
+    expectAudit(mockA, "create", "product")
+    mockA.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int64(pb.ProductEventType_PRODUCT_CREATED), &product, sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    mockA.ExpectCommit()
+    if _, err := a.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t))); err != nil {
+        t.Fatal(err)
+    }
+
+    // ...and instance B finds the row in the shared outbox.
+    mockB.ExpectQuery(`SELECT \* FROM "product_outbox" WHERE id > \$1 ORDER BY id`).WithArgs(6).
+        WillReturnRows(outboxRows().AddRow(7, int64(pb.ProductEventType_PRODUCT_CREATED), product.data, time.Now()))
+    if err := relayB.poll(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    msg := stream.next(t)
+    if msg.Key != "product:42" || msg.Sequence != 7 || msg.Source != "b" {
+        t.Errorf("got key %q, sequence %d from %q, want product:42, 7 from b", msg.Key, msg.Sequence, msg.Source)
+    }
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// PriceAlert asks for a notification once a product's price is at or below
+// TargetPrice. An alert fires once; afterwards the user must create a new one.
+type PriceAlert struct {
+    ID          uint   `gorm:"primarykey"`
+    UserID      string `gorm:"index"`
+    ProductID   uint   `gorm:"index"`
+    TargetPrice float64
+    Triggered   bool `gorm:"index"`
+    TriggeredAt *time.Time
+    CreatedAt   time.Time
+}
+
+func (a *PriceAlert) toProto() *pb.PriceAlert {
+    return &pb.PriceAlert{
+        Id:          fmt.Sprint(a.ID),
+        UserId:      a.UserID,
+        ProductId:   fmt.Sprint(a.ProductID),
+        TargetPrice: money(a.TargetPrice),
+        Triggered:   a.Triggered,
+        CreatedAt:   timestamppb.New(a.CreatedAt),
+    }
+}
+
+func (s *server) CreatePriceAlert(ctx context.Context, req *pb.CreatePriceAlertRequest) (*pb.PriceAlertResponse, error) {
+    if req.UserId == "" {
+        return nil, status.Error(codes.InvalidArgument, "user_id is required")
+    }
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    if req.TargetPrice == nil || req.TargetPrice.Amount <= 0 {
+        return nil, status.Error(codes.InvalidArgument, "target_price must be positive")
+    }
+    if req.TargetPrice.CurrencyCode != "" && req.TargetPrice.CurrencyCode != defaultCurrency {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported currency %q", req.TargetPrice.CurrencyCode)
+    }
+    if _, err := v1PriceCents(req.TargetPrice.Amount); err != nil {
+        return nil, err
+    }
+
+    alert := PriceAlert{UserID: req.UserId, ProductID: uint(productID), TargetPrice: req.TargetPrice.Amount}
+    var product Product
+    var triggered []PriceAlert
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.First(&product, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        if err := tx.Create(&alert).Error; err != nil {
+            return err
+        }
+        // The target may already be met.
+        triggered, err = triggerPriceAlerts(tx, &product)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    for _, t := range triggered {
+        if t.ID == alert.ID {
+            alert = t
+        }
+    }
+    return &pb.PriceAlertResponse{PriceAlert: alert.toProto()}, nil
+}
+
+func (s *server) DeletePriceAlert(ctx context.Context, req *pb.DeletePriceAlertRequest) (*pb.DeletePriceAlertResponse, error) {
+    id, err := strconv.ParseUint(req.Id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid price alert id %q", req.Id)
+    }
+    result := s.db.WithContext(ctx).Delete(&PriceAlert{}, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, status.Errorf(codes.NotFound, "price alert %s not found", req.Id)
+    }
+    return &pb.DeletePriceAlertResponse{}, nil
+}
+
+func (s *server) ListPriceAlerts(ctx context.Context, req *pb.ListPriceAlertsRequest) (*pb.ListPriceAlertsResponse, error) {
+    if req.UserId == "" && req.ProductId == "" {
+        return nil, status.Error(codes.InvalidArgument, "user_id or product_id is required")
+    }
+
+    query := s.db.WithContext(ctx).Order("id")
+    if req.UserId != "" {
+        query = query.Where("user_id = ?", req.UserId)
+    }
+    if req.ProductId != "" {
+        productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+        if err != nil {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+        }
+        query = query.Where("product_id = ?", productID)
+    }
+    if !req.IncludeTriggered {
+        query = query.Where("triggered = ?", false)
+    }
+
+    var alerts []PriceAlert
+    if err := query.Find(&alerts).Error; err != nil {
+        return nil, err
+    }
+    res := &pb.ListPriceAlertsResponse{PriceAlerts: make([]*pb.PriceAlert, len(alerts))}
+    for i := range alerts {
+        res.PriceAlerts[i] = alerts[i].toProto()
+    }
+    return res, nil
+}
+
+// GetPriceAlertStats summarises the target prices of a product's active
+// alerts, showing where customers would be willing to buy.
+func (s *server) GetPriceAlertStats(ctx context.Context, req *pb.GetPriceAlertStatsRequest) (*pb.GetPriceAlertStatsResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+
+    var targets []float64
+    err = s.db.WithContext(ctx).Model(&PriceAlert{}).
+        Where("product_id = ? AND triggered = ?", productID, false).
+        Order("target_price").
+        Pluck("target_price", &targets).Error
+    if err != nil {
+        return nil, err
+    }
+
+    res := &pb.GetPriceAlertStatsResponse{ActiveCount: int64(len(targets))}
+    if len(targets) == 0 {
+        return res, nil
+    }
+    sort.Float64s(targets)
+    var sum float64
+    for _, target := range targets {
+        sum += target
+    }
+    median := targets[len(targets)/2]
+    if len(targets)%2 == 0 {
+        median = (targets[len(targets)/2-1] + median) / 2
+    }
+    res.MinTarget = money(targets[0])
+    res.MaxTarget = money(targets[len(targets)-1])
+    res.MeanTarget = money(sum / float64(len(targets)))
+    res.MedianTarget = money(median)
+    return res, nil
+}
+
+// triggerPriceAlerts marks the product's active alerts whose target is at or
+// above its current price as triggered, records a price_alert.triggered event
+// for each in the outbox and returns them. Any code path that changes a
+// product's price must call it in the same transaction.
+func triggerPriceAlerts(tx *gorm.DB, product *Product) ([]PriceAlert, error) {
+    var alerts []PriceAlert
+    err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+        Where("product_id = ? AND triggered = ? AND target_price >= ?", product.ID, false, product.Price).
+        Find(&alerts).Error
+    if err != nil || len(alerts) == 0 {
+        return nil, err
+    }
+
+    ids := make([]uint, len(alerts))
+    now := time.Now()
+    for i := range alerts {
+        ids[i] = alerts[i].ID
+        alerts[i].Triggered = true
+        alerts[i].TriggeredAt = &now
+    }
+    err = tx.Model(&PriceAlert{}).Where("id IN ?", ids).Updates(map[string]interface{}{"triggered": true, "triggered_at": now}).Error
+    if err != nil {
+        return nil, err
+    }
+    for i := range alerts {
+        if err := recordPriceAlertEvent(tx, &alerts[i], product); err != nil {
+            return nil, err
+        }
+    }
+    return alerts, nil
+}
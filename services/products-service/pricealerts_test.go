@@ -0,0 +1,178 @@
+package main
+
+import (
+    "context"
+    "math"
+    "net"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// serveProducts serves s over gRPC on a local port and returns a client for
+// it.
+func serveProducts(t *testing.T, s *server) pb.ProductServiceClient {
+    t.Helper()
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    srv := grpc.NewServer()
+    pb.RegisterProductServiceServer(srv, s)
+    go srv.Serve(lis)
+    t.Cleanup(srv.Stop)
+
+    conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return pb.NewProductServiceClient(conn)
+}
+
+func productRow(id uint, name string, price float64) *sqlmock.Rows {
+    now := time.Now()
+    return sqlmock.NewRows([]string{"id", "uuid", "name", "price", "review_status", "created_at", "updated_at"}).
+        AddRow(id, "6f1c7c1e-0d55-4b8e-9a52-4f0f2f3c9b10", name, price, reviewStatusApproved, now, now)
+}
+
+func priceAlertRow(id uint, userID string, productID uint, target float64) *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "user_id", "product_id", "target_price", "triggered", "created_at"}).
+        AddRow(id, userID, productID, target, false, time.Now())
+}
+
+func TestPriceAlertEndToEnd(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, events: newEventHub("test")}
+    relay := &outboxRelay{outboxCursor: outboxCursor{db: db, now: time.Now, after: 7}, hub: s.events}
+    client := serveProducts(t, s)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    watch, err := client.WatchProducts(ctx, &pb.WatchProductsRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    waitForSubscribers(t, s.events, 1)
+
+    // The product costs 12.50, so an alert at 10 stays active...
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(42, "Mug", 12.5))
+    mock.ExpectQuery(`INSERT INTO "price_alerts"`).
+        WithArgs("ada", 42, 10.0, false, nil, sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectQuery(`SELECT \* FROM "price_alerts" WHERE product_id = \$1 AND triggered = \$2 AND target_price >= \$3 FOR UPDATE`).
+        WithArgs(42, false, 12.5).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectCommit()
+    res, err := client.CreatePriceAlert(ctx, &pb.CreatePriceAlertRequest{UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: 10}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.PriceAlert.Triggered {
+        t.Error("alert at 10 triggered for a product at 12.50")
+    }
+
+    // ...while one at 15 fires at once, and its event goes to the outbox in
+    // the same transaction.
+    var product, alert capturedBytes
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(42, "Mug", 12.5))
+    mock.ExpectQuery(`INSERT INTO "price_alerts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+    mock.ExpectQuery(`SELECT \* FROM "price_alerts" WHERE product_id = \$1 AND triggered = \$2 AND target_price >= \$3 FOR UPDATE`).
+        WithArgs(42, false, 12.5).
+        WillReturnRows(priceAlertRow(2, "grace", 42, 15))
+    mock.ExpectExec(`UPDATE "price_alerts" SET "triggered"=\$1,"triggered_at"=\$2 WHERE id IN \(\$3\)`).
+        WithArgs(true, sqlmock.AnyArg(), 2).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int64(pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED), &product, &alert, sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+    mock.ExpectCommit()
+    res, err = client.CreatePriceAlert(ctx, &pb.CreatePriceAlertRequest{UserId: "grace", ProductId: "42", TargetPrice: &pb.Money{Amount: 15}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !res.PriceAlert.Triggered || res.PriceAlert.Id != "2" {
+        t.Errorf("alert %s triggered = %v, want alert 2 triggered", res.PriceAlert.Id, res.PriceAlert.Triggered)
+    }
+
+    // The relay publishes the outbox row to watchers.
+    mock.ExpectQuery(`SELECT \* FROM "product_outbox"`).WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "type", "product", "price_alert", "created_at"}).
+            AddRow(8, int64(pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED), product.data, alert.data, time.Now()))
+    if err := relay.poll(ctx); err != nil {
+        t.Fatal(err)
+    }
+    event, err := watch.Recv()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if event.Type != pb.ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED || event.Sequence != 8 {
+        t.Fatalf("got %v event %d, want PRODUCT_PRICE_ALERT_TRIGGERED 8", event.Type, event.Sequence)
+    }
+    if event.PriceAlert.GetId() != "2" || event.PriceAlert.GetUserId() != "grace" || event.Product.GetId() != "42" {
+        t.Errorf("event is for alert %s of %s on product %s, want alert 2 of grace on product 42",
+            event.PriceAlert.GetId(), event.PriceAlert.GetUserId(), event.Product.GetId())
+    }
+
+    // A triggered alert is no longer active, so the stats only count the
+    // first one.
+    mock.ExpectQuery(`SELECT "target_price" FROM "price_alerts" WHERE product_id = \$1 AND triggered = \$2`).
+        WithArgs(42, false).
+        WillReturnRows(sqlmock.NewRows([]string{"target_price"}).AddRow(10.0))
+    stats, err := client.GetPriceAlertStats(ctx, &pb.GetPriceAlertStatsRequest{ProductId: "42"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if stats.ActiveCount != 1 || stats.MinTarget.GetAmount() != 10 {
+        t.Errorf("stats = %d active from %v, want 1 from 10", stats.ActiveCount, stats.MinTarget.GetAmount())
+    }
+}
+
+func TestPriceAlertStatsDistribution(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT "target_price" FROM "price_alerts"`).
+        WillReturnRows(sqlmock.NewRows([]string{"target_price"}).AddRow(10.0).AddRow(12.5).AddRow(20.0).AddRow(40.0))
+
+    stats, err := (&server{db: db}).GetPriceAlertStats(context.Background(), &pb.GetPriceAlertStatsRequest{ProductId: "42"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := []float64{stats.MinTarget.Amount, stats.MaxTarget.Amount, stats.MeanTarget.Amount, stats.MedianTarget.Amount}
+    want := []float64{10, 40, 20.63, 16.25}
+    for i := range want {
+        if got[i] != want[i] {
+            t.Errorf("min, max, mean, median = %v, want %v", got, want)
+            break
+        }
+    }
+}
+
+func TestCreatePriceAlertRejectsBadRequests(t *testing.T) {
+    // No statement is expected: every request is rejected up front.
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for _, req := range []*pb.CreatePriceAlertRequest{
+        {ProductId: "42", TargetPrice: &pb.Money{Amount: 10}},
+        {UserId: "ada", ProductId: "1 OR 1=1", TargetPrice: &pb.Money{Amount: 10}},
+        {UserId: "ada", ProductId: "42"},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: -1}},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: 19.999}},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: math.NaN()}},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: math.Inf(1)}},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: math.Inf(-1)}},
+        {UserId: "ada", ProductId: "42", TargetPrice: &pb.Money{Amount: 10, CurrencyCode: "EUR"}},
+    } {
+        if _, err := s.CreatePriceAlert(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("CreatePriceAlert(%v): %v, want InvalidArgument", req, err)
+        }
+    }
+}
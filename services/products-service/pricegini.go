@@ -0,0 +1,134 @@
+package main
+
+import (
+    "context"
+    "math"
+    "math/rand"
+    "sort"
+    "time"
+
+    "google.golang.org/protobuf/proto"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    // priceGiniCacheTTL is how long GetCatalogPriceGini serves a result
+    // before reading the catalog again.
+    priceGiniCacheTTL = time.Hour
+    priceGiniCacheKey = "price-gini"
+    // priceGiniSampleSize caps how many prices are kept in memory. Larger
+    // catalogs are reservoir sampled, which estimates the coefficient to
+    // well within 1% at this size.
+    priceGiniSampleSize = 100000
+)
+
+// priceGiniPercentiles are the percentiles GetCatalogPriceGini reports.
+var priceGiniPercentiles = []int32{10, 25, 50, 75, 90, 95, 99}
+
+// priceSample is a uniform random sample of up to size prices, kept with
+// Algorithm R: the first size prices are kept, and the nth after that
+// replaces a random one with probability size/n.
+type priceSample struct {
+    size   int
+    seen   int
+    prices []float64
+    rand   *rand.Rand
+}
+
+func newPriceSample(size int) *priceSample {
+    return &priceSample{size: size, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (s *priceSample) observe(price float64) {
+    s.seen++
+    if len(s.prices) < s.size {
+        s.prices = append(s.prices, price)
+        return
+    }
+    if i := s.rand.Intn(s.seen); i < s.size {
+        s.prices[i] = price
+    }
+}
+
+// giniCoefficient returns the Gini coefficient of sorted, which must be in
+// ascending order, with the sorted cumulative sum formula
+//
+//	G = 2·Σ i·x_i / (n·Σ x_i) − (n+1)/n
+//
+// for i from 1 to n. It is 0 when every price is equal and approaches 1 as
+// a single product accounts for the whole catalog's value. A catalog that
+// is empty or entirely free has a coefficient of 0.
+func giniCoefficient(sorted []float64) float64 {
+    n := float64(len(sorted))
+    var sum, weighted float64
+    for i, x := range sorted {
+        sum += x
+        weighted += float64(i+1) * x
+    }
+    if sum == 0 {
+        return 0
+    }
+    return 2*weighted/(n*sum) - (n+1)/n
+}
+
+// percentile returns the pth percentile of sorted, which must be in
+// ascending order and not empty, interpolating linearly between ranks.
+func percentile(sorted []float64, p float64) float64 {
+    rank := p / 100 * float64(len(sorted)-1)
+    lower := int(math.Floor(rank))
+    if lower+1 >= len(sorted) {
+        return sorted[len(sorted)-1]
+    }
+    return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// samplePrices reads the price of every active, approved product in
+// batches, sampling them once there are more than priceGiniSampleSize.
+func samplePrices(db *gorm.DB) ([]float64, error) {
+    sample := newPriceSample(priceGiniSampleSize)
+    var products []Product
+    result := db.Select("id", "price").
+        Where("status = ? AND review_status = ?", productStatusActive, reviewStatusApproved).
+        FindInBatches(&products, catalogMetricsBatchSize, func(tx *gorm.DB, batch int) error {
+            for _, product := range products {
+                sample.observe(product.Price)
+            }
+            return nil
+        })
+    return sample.prices, result.Error
+}
+
+// GetCatalogPriceGini measures how unequally prices are spread across the
+// active catalog, with percentiles of the prices. The computation is done
+// here rather than in SQL so it does not depend on the database, and its
+// result is cached for an hour.
+func (s *server) GetCatalogPriceGini(ctx context.Context, req *pb.GetCatalogPriceGiniRequest) (*pb.GetCatalogPriceGiniResponse, error) {
+    if data, ok := s.priceGini.Get(ctx, priceGiniCacheKey); ok {
+        var res pb.GetCatalogPriceGiniResponse
+        if err := proto.Unmarshal(data, &res); err == nil {
+            return &res, nil
+        }
+    }
+
+    prices, err := samplePrices(s.db.WithContext(ctx))
+    if err != nil {
+        return nil, err
+    }
+    sort.Float64s(prices)
+    res := &pb.GetCatalogPriceGiniResponse{
+        GiniCoefficient: giniCoefficient(prices),
+        Percentiles:     make(map[int32]float64, len(priceGiniPercentiles)),
+        ComputedAt:      time.Now().UTC().Format(time.RFC3339),
+    }
+    if len(prices) > 0 {
+        for _, p := range priceGiniPercentiles {
+            res.Percentiles[p] = percentile(prices, float64(p))
+        }
+    }
+    if data, err := proto.Marshal(res); err == nil {
+        s.priceGini.Set(ctx, priceGiniCacheKey, data, priceGiniCacheTTL)
+    }
+    return res, nil
+}
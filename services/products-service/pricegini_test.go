@@ -0,0 +1,147 @@
+package main
+
+import (
+    "context"
+    "math"
+    "math/rand"
+    "sort"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// meanDifferenceGini is the textbook definition of the Gini coefficient,
+// the mean absolute difference between all pairs over twice the mean, to
+// check the sorted cumulative sum formula against.
+func meanDifferenceGini(prices []float64) float64 {
+    var diffs, sum float64
+    for _, x := range prices {
+        sum += x
+        for _, y := range prices {
+            diffs += math.Abs(x - y)
+        }
+    }
+    n := float64(len(prices))
+    return diffs / (2 * n * sum)
+}
+
+func TestGiniCoefficient(t *testing.T) {
+    tests := []struct {
+        name   string
+        prices []float64
+        want   float64
+    }{
+        {"empty", nil, 0},
+        {"all free", []float64{0, 0, 0}, 0},
+        {"all equal", []float64{9.99, 9.99, 9.99, 9.99}, 0},
+        {"one to five", []float64{1, 2, 3, 4, 5}, 4.0 / 15},
+        {"one product holds all the value", []float64{0, 0, 0, 100}, 0.75},
+        {"two prices", []float64{1, 3}, 0.25},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := giniCoefficient(tt.prices); math.Abs(got-tt.want) > 1e-12 {
+                t.Errorf("giniCoefficient(%v) = %v, want %v", tt.prices, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestGiniCoefficientMatchesDefinition(t *testing.T) {
+    r := rand.New(rand.NewSource(1))
+    for trial := 0; trial < 20; trial++ {
+        prices := make([]float64, 1+r.Intn(200))
+        for i := range prices {
+            prices[i] = math.Round(r.ExpFloat64()*5000) / 100
+        }
+        want := meanDifferenceGini(prices)
+        sort.Float64s(prices)
+        if got := giniCoefficient(prices); math.Abs(got-want) > 1e-9 {
+            t.Errorf("%d prices: giniCoefficient = %v, want %v", len(prices), got, want)
+        }
+    }
+}
+
+func TestPriceSampleEstimatesGini(t *testing.T) {
+    // Exponentially distributed prices have a Gini coefficient of 1/2.
+    r := rand.New(rand.NewSource(1))
+    prices := make([]float64, 10*priceGiniSampleSize)
+    sample := newPriceSample(priceGiniSampleSize)
+    sample.rand = rand.New(rand.NewSource(2))
+    for i := range prices {
+        prices[i] = r.ExpFloat64() * 50
+        sample.observe(prices[i])
+    }
+    if len(sample.prices) != priceGiniSampleSize {
+        t.Fatalf("sample kept %d prices, want %d", len(sample.prices), priceGiniSampleSize)
+    }
+    sort.Float64s(prices)
+    sort.Float64s(sample.prices)
+    exact, estimate := giniCoefficient(prices), giniCoefficient(sample.prices)
+    if math.Abs(exact-0.5) > 0.01 {
+        t.Fatalf("exact coefficient = %v, want about 0.5", exact)
+    }
+    if math.Abs(estimate-exact)/exact > 0.01 {
+        t.Errorf("sampled coefficient = %v, want within 1%% of %v", estimate, exact)
+    }
+}
+
+func TestPriceSampleKeepsSmallCatalogs(t *testing.T) {
+    sample := newPriceSample(5)
+    for _, price := range []float64{3, 1, 2} {
+        sample.observe(price)
+    }
+    if len(sample.prices) != 3 || sample.prices[0] != 3 || sample.prices[2] != 2 {
+        t.Errorf("sample = %v, want every price", sample.prices)
+    }
+}
+
+func TestPercentile(t *testing.T) {
+    sorted := []float64{10, 20, 30, 40, 50}
+    for p, want := range map[float64]float64{0: 10, 10: 14, 25: 20, 50: 30, 90: 46, 99: 49.6, 100: 50} {
+        if got := percentile(sorted, p); math.Abs(got-want) > 1e-9 {
+            t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+        }
+    }
+    if got := percentile([]float64{7}, 99); got != 7 {
+        t.Errorf("percentile of one price = %v, want 7", got)
+    }
+}
+
+func TestGetCatalogPriceGini(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, priceGini: NewMemoryCache(time.Hour)}
+    rows := sqlmock.NewRows([]string{"id", "price"})
+    for i, price := range []float64{5, 1, 4, 2, 3} {
+        rows.AddRow(i+1, price)
+    }
+    mock.ExpectQuery(`SELECT "id","price" FROM "products" WHERE \(status = \$1 AND review_status = \$2\) .* ORDER BY "products"."id" LIMIT 1000`).
+        WithArgs(productStatusActive, reviewStatusApproved).
+        WillReturnRows(rows)
+
+    res, err := s.GetCatalogPriceGini(context.Background(), &pb.GetCatalogPriceGiniRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if math.Abs(res.GiniCoefficient-4.0/15) > 1e-12 {
+        t.Errorf("coefficient = %v, want 4/15", res.GiniCoefficient)
+    }
+    if len(res.Percentiles) != len(priceGiniPercentiles) || res.Percentiles[50] != 3 || res.Percentiles[10] != 1.4 {
+        t.Errorf("percentiles = %v, want p50 3 and p10 1.4", res.Percentiles)
+    }
+    if _, err := time.Parse(time.RFC3339, res.ComputedAt); err != nil {
+        t.Errorf("computed_at %q: %v", res.ComputedAt, err)
+    }
+
+    // The result is cached, so the catalog is not read again.
+    cached, err := s.GetCatalogPriceGini(context.Background(), &pb.GetCatalogPriceGiniRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if cached.GiniCoefficient != res.GiniCoefficient || cached.ComputedAt != res.ComputedAt {
+        t.Errorf("cached result = %v, want %v", cached, res)
+    }
+}
@@ -0,0 +1,195 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v3.21.12
+// source: proto/drain.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DrainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	mi := &file_proto_drain_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_drain_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_drain_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DrainRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type DrainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Idle          bool                   `protobuf:"varint,1,opt,name=idle,proto3" json:"idle,omitempty"`
+	InFlight      int32                  `protobuf:"varint,2,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	Waited        *durationpb.Duration   `protobuf:"bytes,3,opt,name=waited,proto3" json:"waited,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainResponse) Reset() {
+	*x = DrainResponse{}
+	mi := &file_proto_drain_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainResponse) ProtoMessage() {}
+
+func (x *DrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_drain_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainResponse.ProtoReflect.Descriptor instead.
+func (*DrainResponse) Descriptor() ([]byte, []int) {
+	return file_proto_drain_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DrainResponse) GetIdle() bool {
+	if x != nil {
+		return x.Idle
+	}
+	return false
+}
+
+func (x *DrainResponse) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *DrainResponse) GetWaited() *durationpb.Duration {
+	if x != nil {
+		return x.Waited
+	}
+	return nil
+}
+
+var File_proto_drain_proto protoreflect.FileDescriptor
+
+const file_proto_drain_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/drain.proto\x12\x05drain\x1a\x1egoogle/protobuf/duration.proto\"C\n" +
+	"\fDrainRequest\x123\n" +
+	"\atimeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"s\n" +
+	"\rDrainResponse\x12\x12\n" +
+	"\x04idle\x18\x01 \x01(\bR\x04idle\x12\x1b\n" +
+	"\tin_flight\x18\x02 \x01(\x05R\binFlight\x121\n" +
+	"\x06waited\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06waited2B\n" +
+	"\fDrainService\x122\n" +
+	"\x05Drain\x12\x13.drain.DrainRequest\x1a\x14.drain.DrainResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+
+var (
+	file_proto_drain_proto_rawDescOnce sync.Once
+	file_proto_drain_proto_rawDescData []byte
+)
+
+func file_proto_drain_proto_rawDescGZIP() []byte {
+	file_proto_drain_proto_rawDescOnce.Do(func() {
+		file_proto_drain_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_drain_proto_rawDesc), len(file_proto_drain_proto_rawDesc)))
+	})
+	return file_proto_drain_proto_rawDescData
+}
+
+var file_proto_drain_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_drain_proto_goTypes = []any{
+	(*DrainRequest)(nil),        // 0: drain.DrainRequest
+	(*DrainResponse)(nil),       // 1: drain.DrainResponse
+	(*durationpb.Duration)(nil), // 2: google.protobuf.Duration
+}
+var file_proto_drain_proto_depIdxs = []int32{
+	2, // 0: drain.DrainRequest.timeout:type_name -> google.protobuf.Duration
+	2, // 1: drain.DrainResponse.waited:type_name -> google.protobuf.Duration
+	0, // 2: drain.DrainService.Drain:input_type -> drain.DrainRequest
+	1, // 3: drain.DrainService.Drain:output_type -> drain.DrainResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_drain_proto_init() }
+func file_proto_drain_proto_init() {
+	if File_proto_drain_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_drain_proto_rawDesc), len(file_proto_drain_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_drain_proto_goTypes,
+		DependencyIndexes: file_proto_drain_proto_depIdxs,
+		MessageInfos:      file_proto_drain_proto_msgTypes,
+	}.Build()
+	File_proto_drain_proto = out.File
+	file_proto_drain_proto_goTypes = nil
+	file_proto_drain_proto_depIdxs = nil
+}
@@ -0,0 +1,121 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/drain.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DrainService_Drain_FullMethodName = "/drain.DrainService/Drain"
+)
+
+// DrainServiceClient is the client API for DrainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DrainServiceClient interface {
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
+}
+
+type drainServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDrainServiceClient(cc grpc.ClientConnInterface) DrainServiceClient {
+	return &drainServiceClient{cc}
+}
+
+func (c *drainServiceClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, DrainService_Drain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DrainServiceServer is the server API for DrainService service.
+// All implementations must embed UnimplementedDrainServiceServer
+// for forward compatibility.
+type DrainServiceServer interface {
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
+	mustEmbedUnimplementedDrainServiceServer()
+}
+
+// UnimplementedDrainServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDrainServiceServer struct{}
+
+func (UnimplementedDrainServiceServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedDrainServiceServer) mustEmbedUnimplementedDrainServiceServer() {}
+func (UnimplementedDrainServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeDrainServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DrainServiceServer will
+// result in compilation errors.
+type UnsafeDrainServiceServer interface {
+	mustEmbedUnimplementedDrainServiceServer()
+}
+
+func RegisterDrainServiceServer(s grpc.ServiceRegistrar, srv DrainServiceServer) {
+	// If the following call panics, it indicates UnimplementedDrainServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DrainService_ServiceDesc, srv)
+}
+
+func _DrainService_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrainServiceServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrainService_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrainServiceServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DrainService_ServiceDesc is the grpc.ServiceDesc for DrainService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DrainService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "drain.DrainService",
+	HandlerType: (*DrainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Drain",
+			Handler:    _DrainService_Drain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/drain.proto",
+}
@@ -9,6 +9,7 @@ package gen
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,11 +22,317 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProductEventType int32
+
+const (
+	ProductEventType_PRODUCT_EVENT_TYPE_UNSPECIFIED ProductEventType = 0
+	ProductEventType_PRODUCT_CREATED                ProductEventType = 1
+	ProductEventType_PRODUCT_UPDATED                ProductEventType = 2
+	ProductEventType_PRODUCT_DELETED                ProductEventType = 3
+	ProductEventType_PRODUCT_EVENTS_DROPPED         ProductEventType = 4
+	ProductEventType_PRODUCT_PRICE_ALERT_TRIGGERED  ProductEventType = 5
+	ProductEventType_PRODUCT_REVIEWED               ProductEventType = 6
+)
+
+// Enum value maps for ProductEventType.
+var (
+	ProductEventType_name = map[int32]string{
+		0: "PRODUCT_EVENT_TYPE_UNSPECIFIED",
+		1: "PRODUCT_CREATED",
+		2: "PRODUCT_UPDATED",
+		3: "PRODUCT_DELETED",
+		4: "PRODUCT_EVENTS_DROPPED",
+		5: "PRODUCT_PRICE_ALERT_TRIGGERED",
+		6: "PRODUCT_REVIEWED",
+	}
+	ProductEventType_value = map[string]int32{
+		"PRODUCT_EVENT_TYPE_UNSPECIFIED": 0,
+		"PRODUCT_CREATED":                1,
+		"PRODUCT_UPDATED":                2,
+		"PRODUCT_DELETED":                3,
+		"PRODUCT_EVENTS_DROPPED":         4,
+		"PRODUCT_PRICE_ALERT_TRIGGERED":  5,
+		"PRODUCT_REVIEWED":               6,
+	}
+)
+
+func (x ProductEventType) Enum() *ProductEventType {
+	p := new(ProductEventType)
+	*p = x
+	return p
+}
+
+func (x ProductEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[0].Descriptor()
+}
+
+func (ProductEventType) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[0]
+}
+
+func (x ProductEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductEventType.Descriptor instead.
+func (ProductEventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{0}
+}
+
+type QRFormat int32
+
+const (
+	QRFormat_QR_FORMAT_PNG QRFormat = 0
+	QRFormat_QR_FORMAT_SVG QRFormat = 1
+)
+
+// Enum value maps for QRFormat.
+var (
+	QRFormat_name = map[int32]string{
+		0: "QR_FORMAT_PNG",
+		1: "QR_FORMAT_SVG",
+	}
+	QRFormat_value = map[string]int32{
+		"QR_FORMAT_PNG": 0,
+		"QR_FORMAT_SVG": 1,
+	}
+)
+
+func (x QRFormat) Enum() *QRFormat {
+	p := new(QRFormat)
+	*p = x
+	return p
+}
+
+func (x QRFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QRFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[1].Descriptor()
+}
+
+func (QRFormat) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[1]
+}
+
+func (x QRFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QRFormat.Descriptor instead.
+func (QRFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{1}
+}
+
+type TagOperator int32
+
+const (
+	TagOperator_TAG_OPERATOR_AND TagOperator = 0
+	TagOperator_TAG_OPERATOR_OR  TagOperator = 1
+)
+
+// Enum value maps for TagOperator.
+var (
+	TagOperator_name = map[int32]string{
+		0: "TAG_OPERATOR_AND",
+		1: "TAG_OPERATOR_OR",
+	}
+	TagOperator_value = map[string]int32{
+		"TAG_OPERATOR_AND": 0,
+		"TAG_OPERATOR_OR":  1,
+	}
+)
+
+func (x TagOperator) Enum() *TagOperator {
+	p := new(TagOperator)
+	*p = x
+	return p
+}
+
+func (x TagOperator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TagOperator) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[2].Descriptor()
+}
+
+func (TagOperator) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[2]
+}
+
+func (x TagOperator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TagOperator.Descriptor instead.
+func (TagOperator) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{2}
+}
+
+type ProductStatus int32
+
+const (
+	ProductStatus_PRODUCT_STATUS_ACTIVE   ProductStatus = 0
+	ProductStatus_PRODUCT_STATUS_ARCHIVED ProductStatus = 1
+	ProductStatus_PRODUCT_STATUS_INACTIVE ProductStatus = 2
+)
+
+// Enum value maps for ProductStatus.
+var (
+	ProductStatus_name = map[int32]string{
+		0: "PRODUCT_STATUS_ACTIVE",
+		1: "PRODUCT_STATUS_ARCHIVED",
+		2: "PRODUCT_STATUS_INACTIVE",
+	}
+	ProductStatus_value = map[string]int32{
+		"PRODUCT_STATUS_ACTIVE":   0,
+		"PRODUCT_STATUS_ARCHIVED": 1,
+		"PRODUCT_STATUS_INACTIVE": 2,
+	}
+)
+
+func (x ProductStatus) Enum() *ProductStatus {
+	p := new(ProductStatus)
+	*p = x
+	return p
+}
+
+func (x ProductStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[3].Descriptor()
+}
+
+func (ProductStatus) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[3]
+}
+
+func (x ProductStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductStatus.Descriptor instead.
+func (ProductStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{3}
+}
+
+type ReviewStatus int32
+
+const (
+	ReviewStatus_REVIEW_STATUS_PENDING  ReviewStatus = 0
+	ReviewStatus_REVIEW_STATUS_APPROVED ReviewStatus = 1
+	ReviewStatus_REVIEW_STATUS_REJECTED ReviewStatus = 2
+)
+
+// Enum value maps for ReviewStatus.
+var (
+	ReviewStatus_name = map[int32]string{
+		0: "REVIEW_STATUS_PENDING",
+		1: "REVIEW_STATUS_APPROVED",
+		2: "REVIEW_STATUS_REJECTED",
+	}
+	ReviewStatus_value = map[string]int32{
+		"REVIEW_STATUS_PENDING":  0,
+		"REVIEW_STATUS_APPROVED": 1,
+		"REVIEW_STATUS_REJECTED": 2,
+	}
+)
+
+func (x ReviewStatus) Enum() *ReviewStatus {
+	p := new(ReviewStatus)
+	*p = x
+	return p
+}
+
+func (x ReviewStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[4].Descriptor()
+}
+
+func (ReviewStatus) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[4]
+}
+
+func (x ReviewStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewStatus.Descriptor instead.
+func (ReviewStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{4}
+}
+
+type ImportFormat int32
+
+const (
+	ImportFormat_IMPORT_FORMAT_UNSPECIFIED ImportFormat = 0
+	ImportFormat_IMPORT_FORMAT_CSV         ImportFormat = 1
+	ImportFormat_IMPORT_FORMAT_JSON        ImportFormat = 2
+)
+
+// Enum value maps for ImportFormat.
+var (
+	ImportFormat_name = map[int32]string{
+		0: "IMPORT_FORMAT_UNSPECIFIED",
+		1: "IMPORT_FORMAT_CSV",
+		2: "IMPORT_FORMAT_JSON",
+	}
+	ImportFormat_value = map[string]int32{
+		"IMPORT_FORMAT_UNSPECIFIED": 0,
+		"IMPORT_FORMAT_CSV":         1,
+		"IMPORT_FORMAT_JSON":        2,
+	}
+)
+
+func (x ImportFormat) Enum() *ImportFormat {
+	p := new(ImportFormat)
+	*p = x
+	return p
+}
+
+func (x ImportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_products_proto_enumTypes[5].Descriptor()
+}
+
+func (ImportFormat) Type() protoreflect.EnumType {
+	return &file_proto_products_proto_enumTypes[5]
+}
+
+func (x ImportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportFormat.Descriptor instead.
+func (ImportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{5}
+}
+
 type Product struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status        ProductStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=products.ProductStatus" json:"status,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Brand         string                 `protobuf:"bytes,7,opt,name=brand,proto3" json:"brand,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,8,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	ReviewStatus  ReviewStatus           `protobuf:"varint,9,opt,name=review_status,json=reviewStatus,proto3,enum=products.ReviewStatus" json:"review_status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,10 +388,55 @@ func (x *Product) GetPrice() float64 {
 	return 0
 }
 
+func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetStatus() ProductStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProductStatus_PRODUCT_STATUS_ACTIVE
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Product) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Product) GetReviewStatus() ReviewStatus {
+	if x != nil {
+		return x.ReviewStatus
+	}
+	return ReviewStatus_REVIEW_STATUS_PENDING
+}
+
 type CreateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Brand         string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,5,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,6 +485,27 @@ func (x *CreateProductRequest) GetPrice() float64 {
 	return 0
 }
 
+func (x *CreateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
 type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -180,6 +553,7 @@ func (x *GetProductRequest) GetId() string {
 type ProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -221,26 +595,5271 @@ func (x *ProductResponse) GetProduct() *Product {
 	return nil
 }
 
-var File_proto_products_proto protoreflect.FileDescriptor
+func (x *ProductResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
 
-const file_proto_products_proto_rawDesc = "" +
-	"\n" +
-	"\x14proto/products.proto\x12\bproducts\"C\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\"@\n" +
-	"\x14CreateProductRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
-	"\x05price\x18\x02 \x01(\x01R\x05price\"#\n" +
-	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
-	"\x0fProductResponse\x12+\n" +
-	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct2\xa2\x01\n" +
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyCode  string                 `protobuf:"bytes,1,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_proto_products_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Money) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *Money) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CartItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_proto_products_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CartItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CartItem) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *CartItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type LineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice     *Money                 `protobuf:"bytes,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Total         *Money                 `protobuf:"bytes,6,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_proto_products_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LineItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *LineItem) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *LineItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LineItem) GetUnitPrice() *Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+func (x *LineItem) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+type CalculateCartTotalRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Items        []*CartItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	DiscountCode string                 `protobuf:"bytes,2,opt,name=discount_code,json=discountCode,proto3" json:"discount_code,omitempty"`
+	// The buyer's ISO 3166-1 country and, optionally, region. The cart is
+	// untaxed when country_code is empty.
+	CountryCode   string `protobuf:"bytes,3,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	RegionCode    string `protobuf:"bytes,4,opt,name=region_code,json=regionCode,proto3" json:"region_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateCartTotalRequest) Reset() {
+	*x = CalculateCartTotalRequest{}
+	mi := &file_proto_products_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateCartTotalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateCartTotalRequest) ProtoMessage() {}
+
+func (x *CalculateCartTotalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateCartTotalRequest.ProtoReflect.Descriptor instead.
+func (*CalculateCartTotalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CalculateCartTotalRequest) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CalculateCartTotalRequest) GetDiscountCode() string {
+	if x != nil {
+		return x.DiscountCode
+	}
+	return ""
+}
+
+func (x *CalculateCartTotalRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *CalculateCartTotalRequest) GetRegionCode() string {
+	if x != nil {
+		return x.RegionCode
+	}
+	return ""
+}
+
+type CalculateCartTotalResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LineItems      []*LineItem            `protobuf:"bytes,1,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	Subtotal       *Money                 `protobuf:"bytes,2,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	DiscountAmount *Money                 `protobuf:"bytes,3,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	Total          *Money                 `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`
+	TaxAmount      *Money                 `protobuf:"bytes,5,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CalculateCartTotalResponse) Reset() {
+	*x = CalculateCartTotalResponse{}
+	mi := &file_proto_products_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateCartTotalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateCartTotalResponse) ProtoMessage() {}
+
+func (x *CalculateCartTotalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateCartTotalResponse.ProtoReflect.Descriptor instead.
+func (*CalculateCartTotalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CalculateCartTotalResponse) GetLineItems() []*LineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *CalculateCartTotalResponse) GetSubtotal() *Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *CalculateCartTotalResponse) GetDiscountAmount() *Money {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return nil
+}
+
+func (x *CalculateCartTotalResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *CalculateCartTotalResponse) GetTaxAmount() *Money {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return nil
+}
+
+type WatchProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchProductsRequest) Reset() {
+	*x = WatchProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProductsRequest) ProtoMessage() {}
+
+func (x *WatchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProductsRequest.ProtoReflect.Descriptor instead.
+func (*WatchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{9}
+}
+
+type ProductEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ProductEventType       `protobuf:"varint,1,opt,name=type,proto3,enum=products.ProductEventType" json:"type,omitempty"`
+	Product       *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	DroppedCount  int64                  `protobuf:"varint,3,opt,name=dropped_count,json=droppedCount,proto3" json:"dropped_count,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Sequence      int64                  `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	PriceAlert    *PriceAlert            `protobuf:"bytes,6,opt,name=price_alert,json=priceAlert,proto3" json:"price_alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductEvent) Reset() {
+	*x = ProductEvent{}
+	mi := &file_proto_products_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductEvent) ProtoMessage() {}
+
+func (x *ProductEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductEvent.ProtoReflect.Descriptor instead.
+func (*ProductEvent) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProductEvent) GetType() ProductEventType {
+	if x != nil {
+		return x.Type
+	}
+	return ProductEventType_PRODUCT_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ProductEvent) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductEvent) GetDroppedCount() int64 {
+	if x != nil {
+		return x.DroppedCount
+	}
+	return 0
+}
+
+func (x *ProductEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *ProductEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *ProductEvent) GetPriceAlert() *PriceAlert {
+	if x != nil {
+		return x.PriceAlert
+	}
+	return nil
+}
+
+type ExportProductsParquetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportProductsParquetRequest) Reset() {
+	*x = ExportProductsParquetRequest{}
+	mi := &file_proto_products_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportProductsParquetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProductsParquetRequest) ProtoMessage() {}
+
+func (x *ExportProductsParquetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProductsParquetRequest.ProtoReflect.Descriptor instead.
+func (*ExportProductsParquetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportProductsParquetRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ExportProductsParquetRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_proto_products_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type WatchCacheInvalidationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCacheInvalidationsRequest) Reset() {
+	*x = WatchCacheInvalidationsRequest{}
+	mi := &file_proto_products_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCacheInvalidationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCacheInvalidationsRequest) ProtoMessage() {}
+
+func (x *WatchCacheInvalidationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCacheInvalidationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchCacheInvalidationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{13}
+}
+
+type CacheInvalidation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Sequence      int64                  `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	FlushAll      bool                   `protobuf:"varint,5,opt,name=flush_all,json=flushAll,proto3" json:"flush_all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheInvalidation) Reset() {
+	*x = CacheInvalidation{}
+	mi := &file_proto_products_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheInvalidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheInvalidation) ProtoMessage() {}
+
+func (x *CacheInvalidation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheInvalidation.ProtoReflect.Descriptor instead.
+func (*CacheInvalidation) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CacheInvalidation) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CacheInvalidation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *CacheInvalidation) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *CacheInvalidation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CacheInvalidation) GetFlushAll() bool {
+	if x != nil {
+		return x.FlushAll
+	}
+	return false
+}
+
+type GetProductQRCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Format        QRFormat               `protobuf:"varint,3,opt,name=format,proto3,enum=products.QRFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductQRCodeRequest) Reset() {
+	*x = GetProductQRCodeRequest{}
+	mi := &file_proto_products_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductQRCodeRequest) ProtoMessage() {}
+
+func (x *GetProductQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetProductQRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetProductQRCodeRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetProductQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetProductQRCodeRequest) GetFormat() QRFormat {
+	if x != nil {
+		return x.Format
+	}
+	return QRFormat_QR_FORMAT_PNG
+}
+
+type GetProductQRCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ImageData     []byte                 `protobuf:"bytes,1,opt,name=image_data,json=imageData,proto3" json:"image_data,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductQRCodeResponse) Reset() {
+	*x = GetProductQRCodeResponse{}
+	mi := &file_proto_products_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductQRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductQRCodeResponse) ProtoMessage() {}
+
+func (x *GetProductQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductQRCodeResponse.ProtoReflect.Descriptor instead.
+func (*GetProductQRCodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetProductQRCodeResponse) GetImageData() []byte {
+	if x != nil {
+		return x.ImageData
+	}
+	return nil
+}
+
+func (x *GetProductQRCodeResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type PriceAlert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	TargetPrice   *Money                 `protobuf:"bytes,4,opt,name=target_price,json=targetPrice,proto3" json:"target_price,omitempty"`
+	Triggered     bool                   `protobuf:"varint,5,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceAlert) Reset() {
+	*x = PriceAlert{}
+	mi := &file_proto_products_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceAlert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceAlert) ProtoMessage() {}
+
+func (x *PriceAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceAlert.ProtoReflect.Descriptor instead.
+func (*PriceAlert) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PriceAlert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PriceAlert) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PriceAlert) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PriceAlert) GetTargetPrice() *Money {
+	if x != nil {
+		return x.TargetPrice
+	}
+	return nil
+}
+
+func (x *PriceAlert) GetTriggered() bool {
+	if x != nil {
+		return x.Triggered
+	}
+	return false
+}
+
+func (x *PriceAlert) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreatePriceAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	TargetPrice   *Money                 `protobuf:"bytes,3,opt,name=target_price,json=targetPrice,proto3" json:"target_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePriceAlertRequest) Reset() {
+	*x = CreatePriceAlertRequest{}
+	mi := &file_proto_products_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePriceAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePriceAlertRequest) ProtoMessage() {}
+
+func (x *CreatePriceAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePriceAlertRequest.ProtoReflect.Descriptor instead.
+func (*CreatePriceAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreatePriceAlertRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreatePriceAlertRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreatePriceAlertRequest) GetTargetPrice() *Money {
+	if x != nil {
+		return x.TargetPrice
+	}
+	return nil
+}
+
+type PriceAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceAlert    *PriceAlert            `protobuf:"bytes,1,opt,name=price_alert,json=priceAlert,proto3" json:"price_alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceAlertResponse) Reset() {
+	*x = PriceAlertResponse{}
+	mi := &file_proto_products_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceAlertResponse) ProtoMessage() {}
+
+func (x *PriceAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceAlertResponse.ProtoReflect.Descriptor instead.
+func (*PriceAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PriceAlertResponse) GetPriceAlert() *PriceAlert {
+	if x != nil {
+		return x.PriceAlert
+	}
+	return nil
+}
+
+type DeletePriceAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePriceAlertRequest) Reset() {
+	*x = DeletePriceAlertRequest{}
+	mi := &file_proto_products_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePriceAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePriceAlertRequest) ProtoMessage() {}
+
+func (x *DeletePriceAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePriceAlertRequest.ProtoReflect.Descriptor instead.
+func (*DeletePriceAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeletePriceAlertRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeletePriceAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePriceAlertResponse) Reset() {
+	*x = DeletePriceAlertResponse{}
+	mi := &file_proto_products_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePriceAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePriceAlertResponse) ProtoMessage() {}
+
+func (x *DeletePriceAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePriceAlertResponse.ProtoReflect.Descriptor instead.
+func (*DeletePriceAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{21}
+}
+
+type ListPriceAlertsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId        string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	IncludeTriggered bool                   `protobuf:"varint,3,opt,name=include_triggered,json=includeTriggered,proto3" json:"include_triggered,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListPriceAlertsRequest) Reset() {
+	*x = ListPriceAlertsRequest{}
+	mi := &file_proto_products_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceAlertsRequest) ProtoMessage() {}
+
+func (x *ListPriceAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListPriceAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListPriceAlertsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListPriceAlertsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListPriceAlertsRequest) GetIncludeTriggered() bool {
+	if x != nil {
+		return x.IncludeTriggered
+	}
+	return false
+}
+
+type ListPriceAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceAlerts   []*PriceAlert          `protobuf:"bytes,1,rep,name=price_alerts,json=priceAlerts,proto3" json:"price_alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPriceAlertsResponse) Reset() {
+	*x = ListPriceAlertsResponse{}
+	mi := &file_proto_products_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPriceAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPriceAlertsResponse) ProtoMessage() {}
+
+func (x *ListPriceAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPriceAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListPriceAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListPriceAlertsResponse) GetPriceAlerts() []*PriceAlert {
+	if x != nil {
+		return x.PriceAlerts
+	}
+	return nil
+}
+
+type GetPriceAlertStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceAlertStatsRequest) Reset() {
+	*x = GetPriceAlertStatsRequest{}
+	mi := &file_proto_products_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceAlertStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceAlertStatsRequest) ProtoMessage() {}
+
+func (x *GetPriceAlertStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceAlertStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceAlertStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetPriceAlertStatsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type GetPriceAlertStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveCount   int64                  `protobuf:"varint,1,opt,name=active_count,json=activeCount,proto3" json:"active_count,omitempty"`
+	MinTarget     *Money                 `protobuf:"bytes,2,opt,name=min_target,json=minTarget,proto3" json:"min_target,omitempty"`
+	MaxTarget     *Money                 `protobuf:"bytes,3,opt,name=max_target,json=maxTarget,proto3" json:"max_target,omitempty"`
+	MeanTarget    *Money                 `protobuf:"bytes,4,opt,name=mean_target,json=meanTarget,proto3" json:"mean_target,omitempty"`
+	MedianTarget  *Money                 `protobuf:"bytes,5,opt,name=median_target,json=medianTarget,proto3" json:"median_target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceAlertStatsResponse) Reset() {
+	*x = GetPriceAlertStatsResponse{}
+	mi := &file_proto_products_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceAlertStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceAlertStatsResponse) ProtoMessage() {}
+
+func (x *GetPriceAlertStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceAlertStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetPriceAlertStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetPriceAlertStatsResponse) GetActiveCount() int64 {
+	if x != nil {
+		return x.ActiveCount
+	}
+	return 0
+}
+
+func (x *GetPriceAlertStatsResponse) GetMinTarget() *Money {
+	if x != nil {
+		return x.MinTarget
+	}
+	return nil
+}
+
+func (x *GetPriceAlertStatsResponse) GetMaxTarget() *Money {
+	if x != nil {
+		return x.MaxTarget
+	}
+	return nil
+}
+
+func (x *GetPriceAlertStatsResponse) GetMeanTarget() *Money {
+	if x != nil {
+		return x.MeanTarget
+	}
+	return nil
+}
+
+func (x *GetPriceAlertStatsResponse) GetMedianTarget() *Money {
+	if x != nil {
+		return x.MedianTarget
+	}
+	return nil
+}
+
+type Tag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tag) Reset() {
+	*x = Tag{}
+	mi := &file_proto_products_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tag) ProtoMessage() {}
+
+func (x *Tag) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tag.ProtoReflect.Descriptor instead.
+func (*Tag) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Tag) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tag) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type SetProductTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProductTagsRequest) Reset() {
+	*x = SetProductTagsRequest{}
+	mi := &file_proto_products_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProductTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProductTagsRequest) ProtoMessage() {}
+
+func (x *SetProductTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProductTagsRequest.ProtoReflect.Descriptor instead.
+func (*SetProductTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetProductTagsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SetProductTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SetProductTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []*Tag                 `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProductTagsResponse) Reset() {
+	*x = SetProductTagsResponse{}
+	mi := &file_proto_products_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProductTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProductTagsResponse) ProtoMessage() {}
+
+func (x *SetProductTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProductTagsResponse.ProtoReflect.Descriptor instead.
+func (*SetProductTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SetProductTagsResponse) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SearchProductsByTagsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Tags            []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	Operator        TagOperator            `protobuf:"varint,2,opt,name=operator,proto3,enum=products.TagOperator" json:"operator,omitempty"`
+	IncludeArchived bool                   `protobuf:"varint,3,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchProductsByTagsRequest) Reset() {
+	*x = SearchProductsByTagsRequest{}
+	mi := &file_proto_products_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsByTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsByTagsRequest) ProtoMessage() {}
+
+func (x *SearchProductsByTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsByTagsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsByTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SearchProductsByTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchProductsByTagsRequest) GetOperator() TagOperator {
+	if x != nil {
+		return x.Operator
+	}
+	return TagOperator_TAG_OPERATOR_AND
+}
+
+func (x *SearchProductsByTagsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ListProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_proto_products_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListProductsByDateRangeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	From            *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To              *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	PageSize        int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken       string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	IncludeArchived bool                   `protobuf:"varint,5,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListProductsByDateRangeRequest) Reset() {
+	*x = ListProductsByDateRangeRequest{}
+	mi := &file_proto_products_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsByDateRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsByDateRangeRequest) ProtoMessage() {}
+
+func (x *ListProductsByDateRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsByDateRangeRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsByDateRangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListProductsByDateRangeRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListProductsByDateRangeRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListProductsByDateRangeRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProductsByDateRangeRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsByDateRangeRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type CalculateTaxRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,3,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	RegionCode    string                 `protobuf:"bytes,4,opt,name=region_code,json=regionCode,proto3" json:"region_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateTaxRequest) Reset() {
+	*x = CalculateTaxRequest{}
+	mi := &file_proto_products_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateTaxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateTaxRequest) ProtoMessage() {}
+
+func (x *CalculateTaxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateTaxRequest.ProtoReflect.Descriptor instead.
+func (*CalculateTaxRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CalculateTaxRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CalculateTaxRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CalculateTaxRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *CalculateTaxRequest) GetRegionCode() string {
+	if x != nil {
+		return x.RegionCode
+	}
+	return ""
+}
+
+type CalculateTaxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaxAmount     *Money                 `protobuf:"bytes,1,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`
+	TaxRate       float64                `protobuf:"fixed64,2,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	TaxName       string                 `protobuf:"bytes,3,opt,name=tax_name,json=taxName,proto3" json:"tax_name,omitempty"`
+	Inclusive     bool                   `protobuf:"varint,4,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateTaxResponse) Reset() {
+	*x = CalculateTaxResponse{}
+	mi := &file_proto_products_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateTaxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateTaxResponse) ProtoMessage() {}
+
+func (x *CalculateTaxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateTaxResponse.ProtoReflect.Descriptor instead.
+func (*CalculateTaxResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CalculateTaxResponse) GetTaxAmount() *Money {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return nil
+}
+
+func (x *CalculateTaxResponse) GetTaxRate() float64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *CalculateTaxResponse) GetTaxName() string {
+	if x != nil {
+		return x.TaxName
+	}
+	return ""
+}
+
+func (x *CalculateTaxResponse) GetInclusive() bool {
+	if x != nil {
+		return x.Inclusive
+	}
+	return false
+}
+
+type ListProductsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PageSize        int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken       string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	IncludeArchived bool                   `protobuf:"varint,3,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	IncludePending  bool                   `protobuf:"varint,4,opt,name=include_pending,json=includePending,proto3" json:"include_pending,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListProductsRequest) Reset() {
+	*x = ListProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsRequest) ProtoMessage() {}
+
+func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetIncludePending() bool {
+	if x != nil {
+		return x.IncludePending
+	}
+	return false
+}
+
+type ArchiveProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveProductRequest) Reset() {
+	*x = ArchiveProductRequest{}
+	mi := &file_proto_products_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveProductRequest) ProtoMessage() {}
+
+func (x *ArchiveProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveProductRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ArchiveProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UnarchiveProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveProductRequest) Reset() {
+	*x = UnarchiveProductRequest{}
+	mi := &file_proto_products_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveProductRequest) ProtoMessage() {}
+
+func (x *UnarchiveProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveProductRequest.ProtoReflect.Descriptor instead.
+func (*UnarchiveProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UnarchiveProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpsertProductEmbeddingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Vector        []float32              `protobuf:"fixed32,2,rep,packed,name=vector,proto3" json:"vector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertProductEmbeddingRequest) Reset() {
+	*x = UpsertProductEmbeddingRequest{}
+	mi := &file_proto_products_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertProductEmbeddingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertProductEmbeddingRequest) ProtoMessage() {}
+
+func (x *UpsertProductEmbeddingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertProductEmbeddingRequest.ProtoReflect.Descriptor instead.
+func (*UpsertProductEmbeddingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UpsertProductEmbeddingRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UpsertProductEmbeddingRequest) GetVector() []float32 {
+	if x != nil {
+		return x.Vector
+	}
+	return nil
+}
+
+type UpsertProductEmbeddingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertProductEmbeddingResponse) Reset() {
+	*x = UpsertProductEmbeddingResponse{}
+	mi := &file_proto_products_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertProductEmbeddingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertProductEmbeddingResponse) ProtoMessage() {}
+
+func (x *UpsertProductEmbeddingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertProductEmbeddingResponse.ProtoReflect.Descriptor instead.
+func (*UpsertProductEmbeddingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{38}
+}
+
+type GetSimilarProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	MinSimilarity float64                `protobuf:"fixed64,3,opt,name=min_similarity,json=minSimilarity,proto3" json:"min_similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSimilarProductsRequest) Reset() {
+	*x = GetSimilarProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSimilarProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarProductsRequest) ProtoMessage() {}
+
+func (x *GetSimilarProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSimilarProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetSimilarProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetSimilarProductsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetSimilarProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetSimilarProductsRequest) GetMinSimilarity() float64 {
+	if x != nil {
+		return x.MinSimilarity
+	}
+	return 0
+}
+
+type SimilarProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Similarity    float64                `protobuf:"fixed64,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarProduct) Reset() {
+	*x = SimilarProduct{}
+	mi := &file_proto_products_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarProduct) ProtoMessage() {}
+
+func (x *SimilarProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarProduct.ProtoReflect.Descriptor instead.
+func (*SimilarProduct) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SimilarProduct) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *SimilarProduct) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type GetSimilarProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*SimilarProduct      `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSimilarProductsResponse) Reset() {
+	*x = GetSimilarProductsResponse{}
+	mi := &file_proto_products_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSimilarProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarProductsResponse) ProtoMessage() {}
+
+func (x *GetSimilarProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSimilarProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetSimilarProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetSimilarProductsResponse) GetProducts() []*SimilarProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type FuzzySearchProductsRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Query               string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	SimilarityThreshold float64                `protobuf:"fixed64,2,opt,name=similarity_threshold,json=similarityThreshold,proto3" json:"similarity_threshold,omitempty"`
+	Limit               int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	IncludeArchived     bool                   `protobuf:"varint,4,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *FuzzySearchProductsRequest) Reset() {
+	*x = FuzzySearchProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FuzzySearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FuzzySearchProductsRequest) ProtoMessage() {}
+
+func (x *FuzzySearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FuzzySearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*FuzzySearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *FuzzySearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *FuzzySearchProductsRequest) GetSimilarityThreshold() float64 {
+	if x != nil {
+		return x.SimilarityThreshold
+	}
+	return 0
+}
+
+func (x *FuzzySearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FuzzySearchProductsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ProductSearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Similarity    float64                `protobuf:"fixed64,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchResult) Reset() {
+	*x = ProductSearchResult{}
+	mi := &file_proto_products_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchResult) ProtoMessage() {}
+
+func (x *ProductSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchResult.ProtoReflect.Descriptor instead.
+func (*ProductSearchResult) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ProductSearchResult) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchResult) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type FuzzySearchProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductSearchResult `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FuzzySearchProductsResponse) Reset() {
+	*x = FuzzySearchProductsResponse{}
+	mi := &file_proto_products_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FuzzySearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FuzzySearchProductsResponse) ProtoMessage() {}
+
+func (x *FuzzySearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FuzzySearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*FuzzySearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *FuzzySearchProductsResponse) GetProducts() []*ProductSearchResult {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type ImportProductsFromURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Format        ImportFormat           `protobuf:"varint,2,opt,name=format,proto3,enum=products.ImportFormat" json:"format,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportProductsFromURLRequest) Reset() {
+	*x = ImportProductsFromURLRequest{}
+	mi := &file_proto_products_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportProductsFromURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportProductsFromURLRequest) ProtoMessage() {}
+
+func (x *ImportProductsFromURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportProductsFromURLRequest.ProtoReflect.Descriptor instead.
+func (*ImportProductsFromURLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ImportProductsFromURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImportProductsFromURLRequest) GetFormat() ImportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportProductsFromURLRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ImportError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	mi := &file_proto_products_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ImportError) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportProgressUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Processed     int32                  `protobuf:"varint,1,opt,name=processed,proto3" json:"processed,omitempty"`
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	Errors        []*ImportError         `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportProgressUpdate) Reset() {
+	*x = ImportProgressUpdate{}
+	mi := &file_proto_products_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportProgressUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportProgressUpdate) ProtoMessage() {}
+
+func (x *ImportProgressUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportProgressUpdate.ProtoReflect.Descriptor instead.
+func (*ImportProgressUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ImportProgressUpdate) GetProcessed() int32 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *ImportProgressUpdate) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportProgressUpdate) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportProgressUpdate) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type GetAlternativeProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	CheaperCount  int32                  `protobuf:"varint,2,opt,name=cheaper_count,json=cheaperCount,proto3" json:"cheaper_count,omitempty"`
+	PricierCount  int32                  `protobuf:"varint,3,opt,name=pricier_count,json=pricierCount,proto3" json:"pricier_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlternativeProductsRequest) Reset() {
+	*x = GetAlternativeProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlternativeProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlternativeProductsRequest) ProtoMessage() {}
+
+func (x *GetAlternativeProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlternativeProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetAlternativeProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetAlternativeProductsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetAlternativeProductsRequest) GetCheaperCount() int32 {
+	if x != nil {
+		return x.CheaperCount
+	}
+	return 0
+}
+
+func (x *GetAlternativeProductsRequest) GetPricierCount() int32 {
+	if x != nil {
+		return x.PricierCount
+	}
+	return 0
+}
+
+type GetAlternativeProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cheaper       []*Product             `protobuf:"bytes,1,rep,name=cheaper,proto3" json:"cheaper,omitempty"`
+	Pricier       []*Product             `protobuf:"bytes,2,rep,name=pricier,proto3" json:"pricier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlternativeProductsResponse) Reset() {
+	*x = GetAlternativeProductsResponse{}
+	mi := &file_proto_products_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlternativeProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlternativeProductsResponse) ProtoMessage() {}
+
+func (x *GetAlternativeProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlternativeProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetAlternativeProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetAlternativeProductsResponse) GetCheaper() []*Product {
+	if x != nil {
+		return x.Cheaper
+	}
+	return nil
+}
+
+func (x *GetAlternativeProductsResponse) GetPricier() []*Product {
+	if x != nil {
+		return x.Pricier
+	}
+	return nil
+}
+
+type BulkUpdateProductStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductIds    []string               `protobuf:"bytes,1,rep,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	NewStatus     ProductStatus          `protobuf:"varint,2,opt,name=new_status,json=newStatus,proto3,enum=products.ProductStatus" json:"new_status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateProductStatusRequest) Reset() {
+	*x = BulkUpdateProductStatusRequest{}
+	mi := &file_proto_products_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateProductStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateProductStatusRequest) ProtoMessage() {}
+
+func (x *BulkUpdateProductStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateProductStatusRequest.ProtoReflect.Descriptor instead.
+func (*BulkUpdateProductStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BulkUpdateProductStatusRequest) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *BulkUpdateProductStatusRequest) GetNewStatus() ProductStatus {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ProductStatus_PRODUCT_STATUS_ACTIVE
+}
+
+func (x *BulkUpdateProductStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type BulkUpdateProductStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedCount  int32                  `protobuf:"varint,1,opt,name=updated_count,json=updatedCount,proto3" json:"updated_count,omitempty"`
+	FailedIds     []string               `protobuf:"bytes,2,rep,name=failed_ids,json=failedIds,proto3" json:"failed_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateProductStatusResponse) Reset() {
+	*x = BulkUpdateProductStatusResponse{}
+	mi := &file_proto_products_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateProductStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateProductStatusResponse) ProtoMessage() {}
+
+func (x *BulkUpdateProductStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateProductStatusResponse.ProtoReflect.Descriptor instead.
+func (*BulkUpdateProductStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BulkUpdateProductStatusResponse) GetUpdatedCount() int32 {
+	if x != nil {
+		return x.UpdatedCount
+	}
+	return 0
+}
+
+func (x *BulkUpdateProductStatusResponse) GetFailedIds() []string {
+	if x != nil {
+		return x.FailedIds
+	}
+	return nil
+}
+
+type ExportGoogleShoppingFeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportGoogleShoppingFeedRequest) Reset() {
+	*x = ExportGoogleShoppingFeedRequest{}
+	mi := &file_proto_products_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportGoogleShoppingFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportGoogleShoppingFeedRequest) ProtoMessage() {}
+
+func (x *ExportGoogleShoppingFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportGoogleShoppingFeedRequest.ProtoReflect.Descriptor instead.
+func (*ExportGoogleShoppingFeedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{52}
+}
+
+type ProductFAQ struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Question      string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Answer        string                 `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
+	OrderIndex    int32                  `protobuf:"varint,5,opt,name=order_index,json=orderIndex,proto3" json:"order_index,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductFAQ) Reset() {
+	*x = ProductFAQ{}
+	mi := &file_proto_products_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFAQ) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFAQ) ProtoMessage() {}
+
+func (x *ProductFAQ) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFAQ.ProtoReflect.Descriptor instead.
+func (*ProductFAQ) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ProductFAQ) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductFAQ) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductFAQ) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *ProductFAQ) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ProductFAQ) GetOrderIndex() int32 {
+	if x != nil {
+		return x.OrderIndex
+	}
+	return 0
+}
+
+func (x *ProductFAQ) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ProductFAQ) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ProductFAQResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Faq           *ProductFAQ            `protobuf:"bytes,1,opt,name=faq,proto3" json:"faq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductFAQResponse) Reset() {
+	*x = ProductFAQResponse{}
+	mi := &file_proto_products_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFAQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFAQResponse) ProtoMessage() {}
+
+func (x *ProductFAQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFAQResponse.ProtoReflect.Descriptor instead.
+func (*ProductFAQResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ProductFAQResponse) GetFaq() *ProductFAQ {
+	if x != nil {
+		return x.Faq
+	}
+	return nil
+}
+
+type AddProductFAQRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Question      string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Answer        string                 `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductFAQRequest) Reset() {
+	*x = AddProductFAQRequest{}
+	mi := &file_proto_products_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductFAQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductFAQRequest) ProtoMessage() {}
+
+func (x *AddProductFAQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductFAQRequest.ProtoReflect.Descriptor instead.
+func (*AddProductFAQRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AddProductFAQRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddProductFAQRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *AddProductFAQRequest) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+type UpdateProductFAQRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	FaqId         string                 `protobuf:"bytes,2,opt,name=faq_id,json=faqId,proto3" json:"faq_id,omitempty"`
+	Question      string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Answer        string                 `protobuf:"bytes,4,opt,name=answer,proto3" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductFAQRequest) Reset() {
+	*x = UpdateProductFAQRequest{}
+	mi := &file_proto_products_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductFAQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductFAQRequest) ProtoMessage() {}
+
+func (x *UpdateProductFAQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductFAQRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductFAQRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *UpdateProductFAQRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UpdateProductFAQRequest) GetFaqId() string {
+	if x != nil {
+		return x.FaqId
+	}
+	return ""
+}
+
+func (x *UpdateProductFAQRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *UpdateProductFAQRequest) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+type DeleteProductFAQRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	FaqId         string                 `protobuf:"bytes,2,opt,name=faq_id,json=faqId,proto3" json:"faq_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductFAQRequest) Reset() {
+	*x = DeleteProductFAQRequest{}
+	mi := &file_proto_products_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductFAQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductFAQRequest) ProtoMessage() {}
+
+func (x *DeleteProductFAQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductFAQRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductFAQRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *DeleteProductFAQRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *DeleteProductFAQRequest) GetFaqId() string {
+	if x != nil {
+		return x.FaqId
+	}
+	return ""
+}
+
+type DeleteProductFAQResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductFAQResponse) Reset() {
+	*x = DeleteProductFAQResponse{}
+	mi := &file_proto_products_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductFAQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductFAQResponse) ProtoMessage() {}
+
+func (x *DeleteProductFAQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductFAQResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProductFAQResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{58}
+}
+
+type ReorderProductFAQsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	FaqIdOrder    []string               `protobuf:"bytes,2,rep,name=faq_id_order,json=faqIdOrder,proto3" json:"faq_id_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderProductFAQsRequest) Reset() {
+	*x = ReorderProductFAQsRequest{}
+	mi := &file_proto_products_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderProductFAQsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderProductFAQsRequest) ProtoMessage() {}
+
+func (x *ReorderProductFAQsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderProductFAQsRequest.ProtoReflect.Descriptor instead.
+func (*ReorderProductFAQsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ReorderProductFAQsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ReorderProductFAQsRequest) GetFaqIdOrder() []string {
+	if x != nil {
+		return x.FaqIdOrder
+	}
+	return nil
+}
+
+type ReorderProductFAQsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Faqs          []*ProductFAQ          `protobuf:"bytes,1,rep,name=faqs,proto3" json:"faqs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderProductFAQsResponse) Reset() {
+	*x = ReorderProductFAQsResponse{}
+	mi := &file_proto_products_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderProductFAQsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderProductFAQsResponse) ProtoMessage() {}
+
+func (x *ReorderProductFAQsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderProductFAQsResponse.ProtoReflect.Descriptor instead.
+func (*ReorderProductFAQsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ReorderProductFAQsResponse) GetFaqs() []*ProductFAQ {
+	if x != nil {
+		return x.Faqs
+	}
+	return nil
+}
+
+type ListProductFAQsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductFAQsRequest) Reset() {
+	*x = ListProductFAQsRequest{}
+	mi := &file_proto_products_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductFAQsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductFAQsRequest) ProtoMessage() {}
+
+func (x *ListProductFAQsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductFAQsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductFAQsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListProductFAQsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type UpdateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Price         *float64               `protobuf:"fixed64,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	Description   *string                `protobuf:"bytes,4,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_proto_products_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetPrice() float64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+type ProductVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Price         *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	SnapshotJson  string                 `protobuf:"bytes,6,opt,name=snapshot_json,json=snapshotJson,proto3" json:"snapshot_json,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,8,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVersion) Reset() {
+	*x = ProductVersion{}
+	mi := &file_proto_products_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVersion) ProtoMessage() {}
+
+func (x *ProductVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVersion.ProtoReflect.Descriptor instead.
+func (*ProductVersion) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ProductVersion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductVersion) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ProductVersion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductVersion) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductVersion) GetSnapshotJson() string {
+	if x != nil {
+		return x.SnapshotJson
+	}
+	return ""
+}
+
+func (x *ProductVersion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProductVersion) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type ProductVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       *ProductVersion        `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVersionResponse) Reset() {
+	*x = ProductVersionResponse{}
+	mi := &file_proto_products_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVersionResponse) ProtoMessage() {}
+
+func (x *ProductVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVersionResponse.ProtoReflect.Descriptor instead.
+func (*ProductVersionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ProductVersionResponse) GetVersion() *ProductVersion {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+type GetProductVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductVersionRequest) Reset() {
+	*x = GetProductVersionRequest{}
+	mi := &file_proto_products_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductVersionRequest) ProtoMessage() {}
+
+func (x *GetProductVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetProductVersionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetProductVersionRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetProductVersionRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type ListProductVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductVersionsRequest) Reset() {
+	*x = ListProductVersionsRequest{}
+	mi := &file_proto_products_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductVersionsRequest) ProtoMessage() {}
+
+func (x *ListProductVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListProductVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListProductVersionsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type ReviewProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Status        ReviewStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=products.ReviewStatus" json:"status,omitempty"`
+	ReviewerNotes string                 `protobuf:"bytes,3,opt,name=reviewer_notes,json=reviewerNotes,proto3" json:"reviewer_notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewProductRequest) Reset() {
+	*x = ReviewProductRequest{}
+	mi := &file_proto_products_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewProductRequest) ProtoMessage() {}
+
+func (x *ReviewProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewProductRequest.ProtoReflect.Descriptor instead.
+func (*ReviewProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ReviewProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ReviewProductRequest) GetStatus() ReviewStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReviewStatus_REVIEW_STATUS_PENDING
+}
+
+func (x *ReviewProductRequest) GetReviewerNotes() string {
+	if x != nil {
+		return x.ReviewerNotes
+	}
+	return ""
+}
+
+type ReviewProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewProductResponse) Reset() {
+	*x = ReviewProductResponse{}
+	mi := &file_proto_products_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewProductResponse) ProtoMessage() {}
+
+func (x *ReviewProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewProductResponse.ProtoReflect.Descriptor instead.
+func (*ReviewProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ReviewProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type GetTagSimilarProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagSimilarProductsRequest) Reset() {
+	*x = GetTagSimilarProductsRequest{}
+	mi := &file_proto_products_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagSimilarProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagSimilarProductsRequest) ProtoMessage() {}
+
+func (x *GetTagSimilarProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagSimilarProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetTagSimilarProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetTagSimilarProductsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetTagSimilarProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ScoredProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	JaccardScore  float64                `protobuf:"fixed64,2,opt,name=jaccard_score,json=jaccardScore,proto3" json:"jaccard_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoredProduct) Reset() {
+	*x = ScoredProduct{}
+	mi := &file_proto_products_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoredProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoredProduct) ProtoMessage() {}
+
+func (x *ScoredProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoredProduct.ProtoReflect.Descriptor instead.
+func (*ScoredProduct) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ScoredProduct) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ScoredProduct) GetJaccardScore() float64 {
+	if x != nil {
+		return x.JaccardScore
+	}
+	return 0
+}
+
+type GetTagSimilarProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ScoredProduct       `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagSimilarProductsResponse) Reset() {
+	*x = GetTagSimilarProductsResponse{}
+	mi := &file_proto_products_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagSimilarProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagSimilarProductsResponse) ProtoMessage() {}
+
+func (x *GetTagSimilarProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagSimilarProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetTagSimilarProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetTagSimilarProductsResponse) GetProducts() []*ScoredProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type GetCatalogPriceGiniRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCatalogPriceGiniRequest) Reset() {
+	*x = GetCatalogPriceGiniRequest{}
+	mi := &file_proto_products_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCatalogPriceGiniRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCatalogPriceGiniRequest) ProtoMessage() {}
+
+func (x *GetCatalogPriceGiniRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCatalogPriceGiniRequest.ProtoReflect.Descriptor instead.
+func (*GetCatalogPriceGiniRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{72}
+}
+
+type GetCatalogPriceGiniResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GiniCoefficient float64                `protobuf:"fixed64,1,opt,name=gini_coefficient,json=giniCoefficient,proto3" json:"gini_coefficient,omitempty"`
+	Percentiles     map[int32]float64      `protobuf:"bytes,2,rep,name=percentiles,proto3" json:"percentiles,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	ComputedAt      string                 `protobuf:"bytes,3,opt,name=computed_at,json=computedAt,proto3" json:"computed_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetCatalogPriceGiniResponse) Reset() {
+	*x = GetCatalogPriceGiniResponse{}
+	mi := &file_proto_products_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCatalogPriceGiniResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCatalogPriceGiniResponse) ProtoMessage() {}
+
+func (x *GetCatalogPriceGiniResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCatalogPriceGiniResponse.ProtoReflect.Descriptor instead.
+func (*GetCatalogPriceGiniResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetCatalogPriceGiniResponse) GetGiniCoefficient() float64 {
+	if x != nil {
+		return x.GiniCoefficient
+	}
+	return 0
+}
+
+func (x *GetCatalogPriceGiniResponse) GetPercentiles() map[int32]float64 {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+func (x *GetCatalogPriceGiniResponse) GetComputedAt() string {
+	if x != nil {
+		return x.ComputedAt
+	}
+	return ""
+}
+
+type SyncProductCatalogRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Since          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncProductCatalogRequest) Reset() {
+	*x = SyncProductCatalogRequest{}
+	mi := &file_proto_products_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncProductCatalogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncProductCatalogRequest) ProtoMessage() {}
+
+func (x *SyncProductCatalogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncProductCatalogRequest.ProtoReflect.Descriptor instead.
+func (*SyncProductCatalogRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *SyncProductCatalogRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *SyncProductCatalogRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type SyncEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EventType      string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Product        *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	DeletedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	SequenceNumber int64                  `protobuf:"varint,4,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncEvent) Reset() {
+	*x = SyncEvent{}
+	mi := &file_proto_products_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncEvent) ProtoMessage() {}
+
+func (x *SyncEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncEvent.ProtoReflect.Descriptor instead.
+func (*SyncEvent) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *SyncEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *SyncEvent) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *SyncEvent) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *SyncEvent) GetSequenceNumber() int64 {
+	if x != nil {
+		return x.SequenceNumber
+	}
+	return 0
+}
+
+type ProductCost struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	SupplierCost  *Money                 `protobuf:"bytes,3,opt,name=supplier_cost,json=supplierCost,proto3" json:"supplier_cost,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
+	EffectiveTo   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=effective_to,json=effectiveTo,proto3" json:"effective_to,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductCost) Reset() {
+	*x = ProductCost{}
+	mi := &file_proto_products_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductCost) ProtoMessage() {}
+
+func (x *ProductCost) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductCost.ProtoReflect.Descriptor instead.
+func (*ProductCost) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ProductCost) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductCost) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductCost) GetSupplierCost() *Money {
+	if x != nil {
+		return x.SupplierCost
+	}
+	return nil
+}
+
+func (x *ProductCost) GetEffectiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return nil
+}
+
+func (x *ProductCost) GetEffectiveTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveTo
+	}
+	return nil
+}
+
+func (x *ProductCost) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ProductCost) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ProductCostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cost          *ProductCost           `protobuf:"bytes,1,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductCostResponse) Reset() {
+	*x = ProductCostResponse{}
+	mi := &file_proto_products_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductCostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductCostResponse) ProtoMessage() {}
+
+func (x *ProductCostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductCostResponse.ProtoReflect.Descriptor instead.
+func (*ProductCostResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ProductCostResponse) GetCost() *ProductCost {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
+
+type SetProductCostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Cost          *Money                 `protobuf:"bytes,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProductCostRequest) Reset() {
+	*x = SetProductCostRequest{}
+	mi := &file_proto_products_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProductCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProductCostRequest) ProtoMessage() {}
+
+func (x *SetProductCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProductCostRequest.ProtoReflect.Descriptor instead.
+func (*SetProductCostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *SetProductCostRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SetProductCostRequest) GetCost() *Money {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
+
+func (x *SetProductCostRequest) GetEffectiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return nil
+}
+
+type SetProductCostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cost          *ProductCost           `protobuf:"bytes,1,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProductCostResponse) Reset() {
+	*x = SetProductCostResponse{}
+	mi := &file_proto_products_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProductCostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProductCostResponse) ProtoMessage() {}
+
+func (x *SetProductCostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProductCostResponse.ProtoReflect.Descriptor instead.
+func (*SetProductCostResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *SetProductCostResponse) GetCost() *ProductCost {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
+
+type GetProductMarginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductMarginRequest) Reset() {
+	*x = GetProductMarginRequest{}
+	mi := &file_proto_products_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductMarginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductMarginRequest) ProtoMessage() {}
+
+func (x *GetProductMarginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductMarginRequest.ProtoReflect.Descriptor instead.
+func (*GetProductMarginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetProductMarginRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetProductMarginRequest) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type GetProductMarginResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SalePrice          *Money                 `protobuf:"bytes,1,opt,name=sale_price,json=salePrice,proto3" json:"sale_price,omitempty"`
+	Cost               *Money                 `protobuf:"bytes,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	GrossMargin        float64                `protobuf:"fixed64,3,opt,name=gross_margin,json=grossMargin,proto3" json:"gross_margin,omitempty"`
+	GrossMarginPercent float64                `protobuf:"fixed64,4,opt,name=gross_margin_percent,json=grossMarginPercent,proto3" json:"gross_margin_percent,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetProductMarginResponse) Reset() {
+	*x = GetProductMarginResponse{}
+	mi := &file_proto_products_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductMarginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductMarginResponse) ProtoMessage() {}
+
+func (x *GetProductMarginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductMarginResponse.ProtoReflect.Descriptor instead.
+func (*GetProductMarginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *GetProductMarginResponse) GetSalePrice() *Money {
+	if x != nil {
+		return x.SalePrice
+	}
+	return nil
+}
+
+func (x *GetProductMarginResponse) GetCost() *Money {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
+
+func (x *GetProductMarginResponse) GetGrossMargin() float64 {
+	if x != nil {
+		return x.GrossMargin
+	}
+	return 0
+}
+
+func (x *GetProductMarginResponse) GetGrossMarginPercent() float64 {
+	if x != nil {
+		return x.GrossMarginPercent
+	}
+	return 0
+}
+
+type GetProductCostHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductCostHistoryRequest) Reset() {
+	*x = GetProductCostHistoryRequest{}
+	mi := &file_proto_products_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductCostHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductCostHistoryRequest) ProtoMessage() {}
+
+func (x *GetProductCostHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductCostHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetProductCostHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *GetProductCostHistoryRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type CloneProductRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SourceProductId      string                 `protobuf:"bytes,1,opt,name=source_product_id,json=sourceProductId,proto3" json:"source_product_id,omitempty"`
+	NewName              string                 `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	AdjustPriceByPercent float64                `protobuf:"fixed64,3,opt,name=adjust_price_by_percent,json=adjustPriceByPercent,proto3" json:"adjust_price_by_percent,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CloneProductRequest) Reset() {
+	*x = CloneProductRequest{}
+	mi := &file_proto_products_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneProductRequest) ProtoMessage() {}
+
+func (x *CloneProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneProductRequest.ProtoReflect.Descriptor instead.
+func (*CloneProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *CloneProductRequest) GetSourceProductId() string {
+	if x != nil {
+		return x.SourceProductId
+	}
+	return ""
+}
+
+func (x *CloneProductRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *CloneProductRequest) GetAdjustPriceByPercent() float64 {
+	if x != nil {
+		return x.AdjustPriceByPercent
+	}
+	return 0
+}
+
+type CloneProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewProductId  string                 `protobuf:"bytes,1,opt,name=new_product_id,json=newProductId,proto3" json:"new_product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloneProductResponse) Reset() {
+	*x = CloneProductResponse{}
+	mi := &file_proto_products_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneProductResponse) ProtoMessage() {}
+
+func (x *CloneProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneProductResponse.ProtoReflect.Descriptor instead.
+func (*CloneProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *CloneProductResponse) GetNewProductId() string {
+	if x != nil {
+		return x.NewProductId
+	}
+	return ""
+}
+
+type PurchaseLimit struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ProductId          string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	MaxQuantityPerUser int32                  `protobuf:"varint,2,opt,name=max_quantity_per_user,json=maxQuantityPerUser,proto3" json:"max_quantity_per_user,omitempty"`
+	WindowHours        int32                  `protobuf:"varint,3,opt,name=window_hours,json=windowHours,proto3" json:"window_hours,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PurchaseLimit) Reset() {
+	*x = PurchaseLimit{}
+	mi := &file_proto_products_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseLimit) ProtoMessage() {}
+
+func (x *PurchaseLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseLimit.ProtoReflect.Descriptor instead.
+func (*PurchaseLimit) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *PurchaseLimit) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PurchaseLimit) GetMaxQuantityPerUser() int32 {
+	if x != nil {
+		return x.MaxQuantityPerUser
+	}
+	return 0
+}
+
+func (x *PurchaseLimit) GetWindowHours() int32 {
+	if x != nil {
+		return x.WindowHours
+	}
+	return 0
+}
+
+func (x *PurchaseLimit) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SetPurchaseLimitRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ProductId          string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	MaxQuantityPerUser int32                  `protobuf:"varint,2,opt,name=max_quantity_per_user,json=maxQuantityPerUser,proto3" json:"max_quantity_per_user,omitempty"`
+	WindowHours        int32                  `protobuf:"varint,3,opt,name=window_hours,json=windowHours,proto3" json:"window_hours,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SetPurchaseLimitRequest) Reset() {
+	*x = SetPurchaseLimitRequest{}
+	mi := &file_proto_products_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPurchaseLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPurchaseLimitRequest) ProtoMessage() {}
+
+func (x *SetPurchaseLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPurchaseLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetPurchaseLimitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *SetPurchaseLimitRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SetPurchaseLimitRequest) GetMaxQuantityPerUser() int32 {
+	if x != nil {
+		return x.MaxQuantityPerUser
+	}
+	return 0
+}
+
+func (x *SetPurchaseLimitRequest) GetWindowHours() int32 {
+	if x != nil {
+		return x.WindowHours
+	}
+	return 0
+}
+
+type SetPurchaseLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         *PurchaseLimit         `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPurchaseLimitResponse) Reset() {
+	*x = SetPurchaseLimitResponse{}
+	mi := &file_proto_products_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPurchaseLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPurchaseLimitResponse) ProtoMessage() {}
+
+func (x *SetPurchaseLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPurchaseLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetPurchaseLimitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *SetPurchaseLimitResponse) GetLimit() *PurchaseLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+type DeletePurchaseLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePurchaseLimitRequest) Reset() {
+	*x = DeletePurchaseLimitRequest{}
+	mi := &file_proto_products_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePurchaseLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePurchaseLimitRequest) ProtoMessage() {}
+
+func (x *DeletePurchaseLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePurchaseLimitRequest.ProtoReflect.Descriptor instead.
+func (*DeletePurchaseLimitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *DeletePurchaseLimitRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type DeletePurchaseLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePurchaseLimitResponse) Reset() {
+	*x = DeletePurchaseLimitResponse{}
+	mi := &file_proto_products_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePurchaseLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePurchaseLimitResponse) ProtoMessage() {}
+
+func (x *DeletePurchaseLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_products_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePurchaseLimitResponse.ProtoReflect.Descriptor instead.
+func (*DeletePurchaseLimitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_products_proto_rawDescGZIP(), []int{89}
+}
+
+var File_proto_products_proto protoreflect.FileDescriptor
+
+const file_proto_products_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/products.proto\x12\bproducts\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc1\x02\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12/\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x17.products.ProductStatusR\x06status\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05brand\x18\a \x01(\tR\x05brand\x12\x1b\n" +
+	"\timage_url\x18\b \x01(\tR\bimageUrl\x12;\n" +
+	"\rreview_status\x18\t \x01(\x0e2\x16.products.ReviewStatusR\freviewStatus\"\x95\x01\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12\x1b\n" +
+	"\timage_url\x18\x05 \x01(\tR\bimageUrl\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\\\n" +
+	"\x0fProductResponse\x12+\n" +
+	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\"D\n" +
+	"\x05Money\x12#\n" +
+	"\rcurrency_code\x18\x01 \x01(\tR\fcurrencyCode\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"d\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\xcf\x01\n" +
+	"\bLineItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12.\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\v2\x0f.products.MoneyR\tunitPrice\x12%\n" +
+	"\x05total\x18\x06 \x01(\v2\x0f.products.MoneyR\x05total\"\xae\x01\n" +
+	"\x19CalculateCartTotalRequest\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.products.CartItemR\x05items\x12#\n" +
+	"\rdiscount_code\x18\x02 \x01(\tR\fdiscountCode\x12!\n" +
+	"\fcountry_code\x18\x03 \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vregion_code\x18\x04 \x01(\tR\n" +
+	"regionCode\"\x8d\x02\n" +
+	"\x1aCalculateCartTotalResponse\x121\n" +
+	"\n" +
+	"line_items\x18\x01 \x03(\v2\x12.products.LineItemR\tlineItems\x12+\n" +
+	"\bsubtotal\x18\x02 \x01(\v2\x0f.products.MoneyR\bsubtotal\x128\n" +
+	"\x0fdiscount_amount\x18\x03 \x01(\v2\x0f.products.MoneyR\x0ediscountAmount\x12%\n" +
+	"\x05total\x18\x04 \x01(\v2\x0f.products.MoneyR\x05total\x12.\n" +
+	"\n" +
+	"tax_amount\x18\x05 \x01(\v2\x0f.products.MoneyR\ttaxAmount\"\x16\n" +
+	"\x14WatchProductsRequest\"\xa0\x02\n" +
+	"\fProductEvent\x12.\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1a.products.ProductEventTypeR\x04type\x12+\n" +
+	"\aproduct\x18\x02 \x01(\v2\x11.products.ProductR\aproduct\x12#\n" +
+	"\rdropped_count\x18\x03 \x01(\x03R\fdroppedCount\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x03R\bsequence\x125\n" +
+	"\vprice_alert\x18\x06 \x01(\v2\x14.products.PriceAlertR\n" +
+	"priceAlert\"z\n" +
+	"\x1cExportProductsParquetRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\" \n" +
+	"\x1eWatchCacheInvalidationsRequest\"\xb1\x01\n" +
+	"\x11CacheInvalidation\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x129\n" +
+	"\n" +
+	"updated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x03R\bsequence\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1b\n" +
+	"\tflush_all\x18\x05 \x01(\bR\bflushAll\"x\n" +
+	"\x17GetProductQRCodeRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\x12*\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x12.products.QRFormatR\x06format\"\\\n" +
+	"\x18GetProductQRCodeResponse\x12\x1d\n" +
+	"\n" +
+	"image_data\x18\x01 \x01(\fR\timageData\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"\xe1\x01\n" +
+	"\n" +
+	"PriceAlert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x122\n" +
+	"\ftarget_price\x18\x04 \x01(\v2\x0f.products.MoneyR\vtargetPrice\x12\x1c\n" +
+	"\ttriggered\x18\x05 \x01(\bR\ttriggered\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x85\x01\n" +
+	"\x17CreatePriceAlertRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x122\n" +
+	"\ftarget_price\x18\x03 \x01(\v2\x0f.products.MoneyR\vtargetPrice\"K\n" +
+	"\x12PriceAlertResponse\x125\n" +
+	"\vprice_alert\x18\x01 \x01(\v2\x14.products.PriceAlertR\n" +
+	"priceAlert\")\n" +
+	"\x17DeletePriceAlertRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1a\n" +
+	"\x18DeletePriceAlertResponse\"}\n" +
+	"\x16ListPriceAlertsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12+\n" +
+	"\x11include_triggered\x18\x03 \x01(\bR\x10includeTriggered\"R\n" +
+	"\x17ListPriceAlertsResponse\x127\n" +
+	"\fprice_alerts\x18\x01 \x03(\v2\x14.products.PriceAlertR\vpriceAlerts\":\n" +
+	"\x19GetPriceAlertStatsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x87\x02\n" +
+	"\x1aGetPriceAlertStatsResponse\x12!\n" +
+	"\factive_count\x18\x01 \x01(\x03R\vactiveCount\x12.\n" +
+	"\n" +
+	"min_target\x18\x02 \x01(\v2\x0f.products.MoneyR\tminTarget\x12.\n" +
+	"\n" +
+	"max_target\x18\x03 \x01(\v2\x0f.products.MoneyR\tmaxTarget\x120\n" +
+	"\vmean_target\x18\x04 \x01(\v2\x0f.products.MoneyR\n" +
+	"meanTarget\x124\n" +
+	"\rmedian_target\x18\x05 \x01(\v2\x0f.products.MoneyR\fmedianTarget\"=\n" +
+	"\x03Tag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"J\n" +
+	"\x15SetProductTagsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\";\n" +
+	"\x16SetProductTagsResponse\x12!\n" +
+	"\x04tags\x18\x01 \x03(\v2\r.products.TagR\x04tags\"\x8f\x01\n" +
+	"\x1bSearchProductsByTagsRequest\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\x121\n" +
+	"\boperator\x18\x02 \x01(\x0e2\x15.products.TagOperatorR\boperator\x12)\n" +
+	"\x10include_archived\x18\x03 \x01(\bR\x0fincludeArchived\"m\n" +
+	"\x14ListProductsResponse\x12-\n" +
+	"\bproducts\x18\x01 \x03(\v2\x11.products.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xe3\x01\n" +
+	"\x1eListProductsByDateRangeRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12)\n" +
+	"\x10include_archived\x18\x05 \x01(\bR\x0fincludeArchived\"\x94\x01\n" +
+	"\x13CalculateTaxRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12!\n" +
+	"\fcountry_code\x18\x03 \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vregion_code\x18\x04 \x01(\tR\n" +
+	"regionCode\"\x9a\x01\n" +
+	"\x14CalculateTaxResponse\x12.\n" +
+	"\n" +
+	"tax_amount\x18\x01 \x01(\v2\x0f.products.MoneyR\ttaxAmount\x12\x19\n" +
+	"\btax_rate\x18\x02 \x01(\x01R\ataxRate\x12\x19\n" +
+	"\btax_name\x18\x03 \x01(\tR\ataxName\x12\x1c\n" +
+	"\tinclusive\x18\x04 \x01(\bR\tinclusive\"\xa5\x01\n" +
+	"\x13ListProductsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12)\n" +
+	"\x10include_archived\x18\x03 \x01(\bR\x0fincludeArchived\x12'\n" +
+	"\x0finclude_pending\x18\x04 \x01(\bR\x0eincludePending\"'\n" +
+	"\x15ArchiveProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\")\n" +
+	"\x17UnarchiveProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x1dUpsertProductEmbeddingRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x16\n" +
+	"\x06vector\x18\x02 \x03(\x02R\x06vector\" \n" +
+	"\x1eUpsertProductEmbeddingResponse\"w\n" +
+	"\x19GetSimilarProductsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12%\n" +
+	"\x0emin_similarity\x18\x03 \x01(\x01R\rminSimilarity\"]\n" +
+	"\x0eSimilarProduct\x12+\n" +
+	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x02 \x01(\x01R\n" +
+	"similarity\"R\n" +
+	"\x1aGetSimilarProductsResponse\x124\n" +
+	"\bproducts\x18\x01 \x03(\v2\x18.products.SimilarProductR\bproducts\"\xa6\x01\n" +
+	"\x1aFuzzySearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x121\n" +
+	"\x14similarity_threshold\x18\x02 \x01(\x01R\x13similarityThreshold\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12)\n" +
+	"\x10include_archived\x18\x04 \x01(\bR\x0fincludeArchived\"b\n" +
+	"\x13ProductSearchResult\x12+\n" +
+	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x02 \x01(\x01R\n" +
+	"similarity\"X\n" +
+	"\x1bFuzzySearchProductsResponse\x129\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1d.products.ProductSearchResultR\bproducts\"y\n" +
+	"\x1cImportProductsFromURLRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12.\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x16.products.ImportFormatR\x06format\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"9\n" +
+	"\vImportError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x95\x01\n" +
+	"\x14ImportProgressUpdate\x12\x1c\n" +
+	"\tprocessed\x18\x01 \x01(\x05R\tprocessed\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x12-\n" +
+	"\x06errors\x18\x04 \x03(\v2\x15.products.ImportErrorR\x06errors\"\x88\x01\n" +
+	"\x1dGetAlternativeProductsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12#\n" +
+	"\rcheaper_count\x18\x02 \x01(\x05R\fcheaperCount\x12#\n" +
+	"\rpricier_count\x18\x03 \x01(\x05R\fpricierCount\"z\n" +
+	"\x1eGetAlternativeProductsResponse\x12+\n" +
+	"\acheaper\x18\x01 \x03(\v2\x11.products.ProductR\acheaper\x12+\n" +
+	"\apricier\x18\x02 \x03(\v2\x11.products.ProductR\apricier\"\x91\x01\n" +
+	"\x1eBulkUpdateProductStatusRequest\x12\x1f\n" +
+	"\vproduct_ids\x18\x01 \x03(\tR\n" +
+	"productIds\x126\n" +
+	"\n" +
+	"new_status\x18\x02 \x01(\x0e2\x17.products.ProductStatusR\tnewStatus\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"e\n" +
+	"\x1fBulkUpdateProductStatusResponse\x12#\n" +
+	"\rupdated_count\x18\x01 \x01(\x05R\fupdatedCount\x12\x1d\n" +
+	"\n" +
+	"failed_ids\x18\x02 \x03(\tR\tfailedIds\"!\n" +
+	"\x1fExportGoogleShoppingFeedRequest\"\xea\x01\n" +
+	"\n" +
+	"ProductFAQ\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x16\n" +
+	"\x06answer\x18\x04 \x01(\tR\x06answer\x12\x1f\n" +
+	"\vorder_index\x18\x05 \x01(\x05R\n" +
+	"orderIndex\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"<\n" +
+	"\x12ProductFAQResponse\x12&\n" +
+	"\x03faq\x18\x01 \x01(\v2\x14.products.ProductFAQR\x03faq\"i\n" +
+	"\x14AddProductFAQRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\tR\bquestion\x12\x16\n" +
+	"\x06answer\x18\x03 \x01(\tR\x06answer\"\x83\x01\n" +
+	"\x17UpdateProductFAQRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x15\n" +
+	"\x06faq_id\x18\x02 \x01(\tR\x05faqId\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x16\n" +
+	"\x06answer\x18\x04 \x01(\tR\x06answer\"O\n" +
+	"\x17DeleteProductFAQRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x15\n" +
+	"\x06faq_id\x18\x02 \x01(\tR\x05faqId\"\x1a\n" +
+	"\x18DeleteProductFAQResponse\"\\\n" +
+	"\x19ReorderProductFAQsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12 \n" +
+	"\ffaq_id_order\x18\x02 \x03(\tR\n" +
+	"faqIdOrder\"F\n" +
+	"\x1aReorderProductFAQsResponse\x12(\n" +
+	"\x04faqs\x18\x01 \x03(\v2\x14.products.ProductFAQR\x04faqs\"7\n" +
+	"\x16ListProductFAQsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\xa4\x01\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x01H\x01R\x05price\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x04 \x01(\tH\x02R\vdescription\x88\x01\x01B\a\n" +
+	"\x05_nameB\b\n" +
+	"\x06_priceB\x0e\n" +
+	"\f_description\"\x93\x02\n" +
+	"\x0eProductVersion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12%\n" +
+	"\x05price\x18\x05 \x01(\v2\x0f.products.MoneyR\x05price\x12#\n" +
+	"\rsnapshot_json\x18\x06 \x01(\tR\fsnapshotJson\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\b \x01(\tR\tcreatedBy\"L\n" +
+	"\x16ProductVersionResponse\x122\n" +
+	"\aversion\x18\x01 \x01(\v2\x18.products.ProductVersionR\aversion\"S\n" +
+	"\x18GetProductVersionRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\";\n" +
+	"\x1aListProductVersionsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x8c\x01\n" +
+	"\x14ReviewProductRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.products.ReviewStatusR\x06status\x12%\n" +
+	"\x0ereviewer_notes\x18\x03 \x01(\tR\rreviewerNotes\"D\n" +
+	"\x15ReviewProductResponse\x12+\n" +
+	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\"S\n" +
+	"\x1cGetTagSimilarProductsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"a\n" +
+	"\rScoredProduct\x12+\n" +
+	"\aproduct\x18\x01 \x01(\v2\x11.products.ProductR\aproduct\x12#\n" +
+	"\rjaccard_score\x18\x02 \x01(\x01R\fjaccardScore\"T\n" +
+	"\x1dGetTagSimilarProductsResponse\x123\n" +
+	"\bproducts\x18\x01 \x03(\v2\x17.products.ScoredProductR\bproducts\"\x1c\n" +
+	"\x1aGetCatalogPriceGiniRequest\"\x83\x02\n" +
+	"\x1bGetCatalogPriceGiniResponse\x12)\n" +
+	"\x10gini_coefficient\x18\x01 \x01(\x01R\x0fginiCoefficient\x12X\n" +
+	"\vpercentiles\x18\x02 \x03(\v26.products.GetCatalogPriceGiniResponse.PercentilesEntryR\vpercentiles\x12\x1f\n" +
+	"\vcomputed_at\x18\x03 \x01(\tR\n" +
+	"computedAt\x1a>\n" +
+	"\x10PercentilesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"v\n" +
+	"\x19SyncProductCatalogRequest\x120\n" +
+	"\x05since\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xbb\x01\n" +
+	"\tSyncEvent\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12+\n" +
+	"\aproduct\x18\x02 \x01(\v2\x11.products.ProductR\aproduct\x129\n" +
+	"\n" +
+	"deleted_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12'\n" +
+	"\x0fsequence_number\x18\x04 \x01(\x03R\x0esequenceNumber\"\xce\x02\n" +
+	"\vProductCost\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x124\n" +
+	"\rsupplier_cost\x18\x03 \x01(\v2\x0f.products.MoneyR\fsupplierCost\x12A\n" +
+	"\x0eeffective_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\x12=\n" +
+	"\feffective_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveTo\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"@\n" +
+	"\x13ProductCostResponse\x12)\n" +
+	"\x04cost\x18\x01 \x01(\v2\x15.products.ProductCostR\x04cost\"\x9e\x01\n" +
+	"\x15SetProductCostRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12#\n" +
+	"\x04cost\x18\x02 \x01(\v2\x0f.products.MoneyR\x04cost\x12A\n" +
+	"\x0eeffective_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\"C\n" +
+	"\x16SetProductCostResponse\x12)\n" +
+	"\x04cost\x18\x01 \x01(\v2\x15.products.ProductCostR\x04cost\"d\n" +
+	"\x17GetProductMarginRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"\xc4\x01\n" +
+	"\x18GetProductMarginResponse\x12.\n" +
+	"\n" +
+	"sale_price\x18\x01 \x01(\v2\x0f.products.MoneyR\tsalePrice\x12#\n" +
+	"\x04cost\x18\x02 \x01(\v2\x0f.products.MoneyR\x04cost\x12!\n" +
+	"\fgross_margin\x18\x03 \x01(\x01R\vgrossMargin\x120\n" +
+	"\x14gross_margin_percent\x18\x04 \x01(\x01R\x12grossMarginPercent\"=\n" +
+	"\x1cGetProductCostHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x93\x01\n" +
+	"\x13CloneProductRequest\x12*\n" +
+	"\x11source_product_id\x18\x01 \x01(\tR\x0fsourceProductId\x12\x19\n" +
+	"\bnew_name\x18\x02 \x01(\tR\anewName\x125\n" +
+	"\x17adjust_price_by_percent\x18\x03 \x01(\x01R\x14adjustPriceByPercent\"<\n" +
+	"\x14CloneProductResponse\x12$\n" +
+	"\x0enew_product_id\x18\x01 \x01(\tR\fnewProductId\"\xbf\x01\n" +
+	"\rPurchaseLimit\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x121\n" +
+	"\x15max_quantity_per_user\x18\x02 \x01(\x05R\x12maxQuantityPerUser\x12!\n" +
+	"\fwindow_hours\x18\x03 \x01(\x05R\vwindowHours\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8e\x01\n" +
+	"\x17SetPurchaseLimitRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x121\n" +
+	"\x15max_quantity_per_user\x18\x02 \x01(\x05R\x12maxQuantityPerUser\x12!\n" +
+	"\fwindow_hours\x18\x03 \x01(\x05R\vwindowHours\"I\n" +
+	"\x18SetPurchaseLimitResponse\x12-\n" +
+	"\x05limit\x18\x01 \x01(\v2\x17.products.PurchaseLimitR\x05limit\";\n" +
+	"\x1aDeletePurchaseLimitRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x1d\n" +
+	"\x1bDeletePurchaseLimitResponse*\xca\x01\n" +
+	"\x10ProductEventType\x12\"\n" +
+	"\x1ePRODUCT_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fPRODUCT_CREATED\x10\x01\x12\x13\n" +
+	"\x0fPRODUCT_UPDATED\x10\x02\x12\x13\n" +
+	"\x0fPRODUCT_DELETED\x10\x03\x12\x1a\n" +
+	"\x16PRODUCT_EVENTS_DROPPED\x10\x04\x12!\n" +
+	"\x1dPRODUCT_PRICE_ALERT_TRIGGERED\x10\x05\x12\x14\n" +
+	"\x10PRODUCT_REVIEWED\x10\x06*0\n" +
+	"\bQRFormat\x12\x11\n" +
+	"\rQR_FORMAT_PNG\x10\x00\x12\x11\n" +
+	"\rQR_FORMAT_SVG\x10\x01*8\n" +
+	"\vTagOperator\x12\x14\n" +
+	"\x10TAG_OPERATOR_AND\x10\x00\x12\x13\n" +
+	"\x0fTAG_OPERATOR_OR\x10\x01*d\n" +
+	"\rProductStatus\x12\x19\n" +
+	"\x15PRODUCT_STATUS_ACTIVE\x10\x00\x12\x1b\n" +
+	"\x17PRODUCT_STATUS_ARCHIVED\x10\x01\x12\x1b\n" +
+	"\x17PRODUCT_STATUS_INACTIVE\x10\x02*a\n" +
+	"\fReviewStatus\x12\x19\n" +
+	"\x15REVIEW_STATUS_PENDING\x10\x00\x12\x1a\n" +
+	"\x16REVIEW_STATUS_APPROVED\x10\x01\x12\x1a\n" +
+	"\x16REVIEW_STATUS_REJECTED\x10\x02*\\\n" +
+	"\fImportFormat\x12\x1d\n" +
+	"\x19IMPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11IMPORT_FORMAT_CSV\x10\x01\x12\x16\n" +
+	"\x12IMPORT_FORMAT_JSON\x10\x022\xc8\x1e\n" +
 	"\x0eProductService\x12J\n" +
 	"\rCreateProduct\x12\x1e.products.CreateProductRequest\x1a\x19.products.ProductResponse\x12D\n" +
 	"\n" +
-	"GetProduct\x12\x1b.products.GetProductRequest\x1a\x19.products.ProductResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+	"GetProduct\x12\x1b.products.GetProductRequest\x1a\x19.products.ProductResponse\x12_\n" +
+	"\x12CalculateCartTotal\x12#.products.CalculateCartTotalRequest\x1a$.products.CalculateCartTotalResponse\x12I\n" +
+	"\rWatchProducts\x12\x1e.products.WatchProductsRequest\x1a\x16.products.ProductEvent0\x01\x12X\n" +
+	"\x15ExportProductsParquet\x12&.products.ExportProductsParquetRequest\x1a\x15.products.ExportChunk0\x01\x12b\n" +
+	"\x17WatchCacheInvalidations\x12(.products.WatchCacheInvalidationsRequest\x1a\x1b.products.CacheInvalidation0\x01\x12Y\n" +
+	"\x10GetProductQRCode\x12!.products.GetProductQRCodeRequest\x1a\".products.GetProductQRCodeResponse\x12S\n" +
+	"\x10CreatePriceAlert\x12!.products.CreatePriceAlertRequest\x1a\x1c.products.PriceAlertResponse\x12Y\n" +
+	"\x10DeletePriceAlert\x12!.products.DeletePriceAlertRequest\x1a\".products.DeletePriceAlertResponse\x12V\n" +
+	"\x0fListPriceAlerts\x12 .products.ListPriceAlertsRequest\x1a!.products.ListPriceAlertsResponse\x12_\n" +
+	"\x12GetPriceAlertStats\x12#.products.GetPriceAlertStatsRequest\x1a$.products.GetPriceAlertStatsResponse\x12S\n" +
+	"\x0eSetProductTags\x12\x1f.products.SetProductTagsRequest\x1a .products.SetProductTagsResponse\x12]\n" +
+	"\x14SearchProductsByTags\x12%.products.SearchProductsByTagsRequest\x1a\x1e.products.ListProductsResponse\x12c\n" +
+	"\x17ListProductsByDateRange\x12(.products.ListProductsByDateRangeRequest\x1a\x1e.products.ListProductsResponse\x12M\n" +
+	"\fCalculateTax\x12\x1d.products.CalculateTaxRequest\x1a\x1e.products.CalculateTaxResponse\x12M\n" +
+	"\fListProducts\x12\x1d.products.ListProductsRequest\x1a\x1e.products.ListProductsResponse\x12L\n" +
+	"\x0eArchiveProduct\x12\x1f.products.ArchiveProductRequest\x1a\x19.products.ProductResponse\x12P\n" +
+	"\x10UnarchiveProduct\x12!.products.UnarchiveProductRequest\x1a\x19.products.ProductResponse\x12k\n" +
+	"\x16UpsertProductEmbedding\x12'.products.UpsertProductEmbeddingRequest\x1a(.products.UpsertProductEmbeddingResponse\x12_\n" +
+	"\x12GetSimilarProducts\x12#.products.GetSimilarProductsRequest\x1a$.products.GetSimilarProductsResponse\x12b\n" +
+	"\x13FuzzySearchProducts\x12$.products.FuzzySearchProductsRequest\x1a%.products.FuzzySearchProductsResponse\x12a\n" +
+	"\x15ImportProductsFromURL\x12&.products.ImportProductsFromURLRequest\x1a\x1e.products.ImportProgressUpdate0\x01\x12k\n" +
+	"\x16GetAlternativeProducts\x12'.products.GetAlternativeProductsRequest\x1a(.products.GetAlternativeProductsResponse\x12n\n" +
+	"\x17BulkUpdateProductStatus\x12(.products.BulkUpdateProductStatusRequest\x1a).products.BulkUpdateProductStatusResponse\x12^\n" +
+	"\x18ExportGoogleShoppingFeed\x12).products.ExportGoogleShoppingFeedRequest\x1a\x15.products.ExportChunk0\x01\x12M\n" +
+	"\rAddProductFAQ\x12\x1e.products.AddProductFAQRequest\x1a\x1c.products.ProductFAQResponse\x12S\n" +
+	"\x10UpdateProductFAQ\x12!.products.UpdateProductFAQRequest\x1a\x1c.products.ProductFAQResponse\x12Y\n" +
+	"\x10DeleteProductFAQ\x12!.products.DeleteProductFAQRequest\x1a\".products.DeleteProductFAQResponse\x12_\n" +
+	"\x12ReorderProductFAQs\x12#.products.ReorderProductFAQsRequest\x1a$.products.ReorderProductFAQsResponse\x12S\n" +
+	"\x0fListProductFAQs\x12 .products.ListProductFAQsRequest\x1a\x1c.products.ProductFAQResponse0\x01\x12J\n" +
+	"\rUpdateProduct\x12\x1e.products.UpdateProductRequest\x1a\x19.products.ProductResponse\x12Y\n" +
+	"\x11GetProductVersion\x12\".products.GetProductVersionRequest\x1a .products.ProductVersionResponse\x12_\n" +
+	"\x13ListProductVersions\x12$.products.ListProductVersionsRequest\x1a .products.ProductVersionResponse0\x01\x12P\n" +
+	"\rReviewProduct\x12\x1e.products.ReviewProductRequest\x1a\x1f.products.ReviewProductResponse\x12h\n" +
+	"\x15GetTagSimilarProducts\x12&.products.GetTagSimilarProductsRequest\x1a'.products.GetTagSimilarProductsResponse\x12b\n" +
+	"\x13GetCatalogPriceGini\x12$.products.GetCatalogPriceGiniRequest\x1a%.products.GetCatalogPriceGiniResponse\x12P\n" +
+	"\x12SyncProductCatalog\x12#.products.SyncProductCatalogRequest\x1a\x13.products.SyncEvent0\x01\x12S\n" +
+	"\x0eSetProductCost\x12\x1f.products.SetProductCostRequest\x1a .products.SetProductCostResponse\x12Y\n" +
+	"\x10GetProductMargin\x12!.products.GetProductMarginRequest\x1a\".products.GetProductMarginResponse\x12`\n" +
+	"\x15GetProductCostHistory\x12&.products.GetProductCostHistoryRequest\x1a\x1d.products.ProductCostResponse0\x01\x12M\n" +
+	"\fCloneProduct\x12\x1d.products.CloneProductRequest\x1a\x1e.products.CloneProductResponse\x12Y\n" +
+	"\x10SetPurchaseLimit\x12!.products.SetPurchaseLimitRequest\x1a\".products.SetPurchaseLimitResponse\x12b\n" +
+	"\x13DeletePurchaseLimit\x12$.products.DeletePurchaseLimitRequest\x1a%.products.DeletePurchaseLimitResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
 
 var (
 	file_proto_products_proto_rawDescOnce sync.Once
@@ -254,24 +5873,271 @@ func file_proto_products_proto_rawDescGZIP() []byte {
 	return file_proto_products_proto_rawDescData
 }
 
-var file_proto_products_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_products_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_proto_products_proto_msgTypes = make([]protoimpl.MessageInfo, 91)
 var file_proto_products_proto_goTypes = []any{
-	(*Product)(nil),              // 0: products.Product
-	(*CreateProductRequest)(nil), // 1: products.CreateProductRequest
-	(*GetProductRequest)(nil),    // 2: products.GetProductRequest
-	(*ProductResponse)(nil),      // 3: products.ProductResponse
+	(ProductEventType)(0),                   // 0: products.ProductEventType
+	(QRFormat)(0),                           // 1: products.QRFormat
+	(TagOperator)(0),                        // 2: products.TagOperator
+	(ProductStatus)(0),                      // 3: products.ProductStatus
+	(ReviewStatus)(0),                       // 4: products.ReviewStatus
+	(ImportFormat)(0),                       // 5: products.ImportFormat
+	(*Product)(nil),                         // 6: products.Product
+	(*CreateProductRequest)(nil),            // 7: products.CreateProductRequest
+	(*GetProductRequest)(nil),               // 8: products.GetProductRequest
+	(*ProductResponse)(nil),                 // 9: products.ProductResponse
+	(*Money)(nil),                           // 10: products.Money
+	(*CartItem)(nil),                        // 11: products.CartItem
+	(*LineItem)(nil),                        // 12: products.LineItem
+	(*CalculateCartTotalRequest)(nil),       // 13: products.CalculateCartTotalRequest
+	(*CalculateCartTotalResponse)(nil),      // 14: products.CalculateCartTotalResponse
+	(*WatchProductsRequest)(nil),            // 15: products.WatchProductsRequest
+	(*ProductEvent)(nil),                    // 16: products.ProductEvent
+	(*ExportProductsParquetRequest)(nil),    // 17: products.ExportProductsParquetRequest
+	(*ExportChunk)(nil),                     // 18: products.ExportChunk
+	(*WatchCacheInvalidationsRequest)(nil),  // 19: products.WatchCacheInvalidationsRequest
+	(*CacheInvalidation)(nil),               // 20: products.CacheInvalidation
+	(*GetProductQRCodeRequest)(nil),         // 21: products.GetProductQRCodeRequest
+	(*GetProductQRCodeResponse)(nil),        // 22: products.GetProductQRCodeResponse
+	(*PriceAlert)(nil),                      // 23: products.PriceAlert
+	(*CreatePriceAlertRequest)(nil),         // 24: products.CreatePriceAlertRequest
+	(*PriceAlertResponse)(nil),              // 25: products.PriceAlertResponse
+	(*DeletePriceAlertRequest)(nil),         // 26: products.DeletePriceAlertRequest
+	(*DeletePriceAlertResponse)(nil),        // 27: products.DeletePriceAlertResponse
+	(*ListPriceAlertsRequest)(nil),          // 28: products.ListPriceAlertsRequest
+	(*ListPriceAlertsResponse)(nil),         // 29: products.ListPriceAlertsResponse
+	(*GetPriceAlertStatsRequest)(nil),       // 30: products.GetPriceAlertStatsRequest
+	(*GetPriceAlertStatsResponse)(nil),      // 31: products.GetPriceAlertStatsResponse
+	(*Tag)(nil),                             // 32: products.Tag
+	(*SetProductTagsRequest)(nil),           // 33: products.SetProductTagsRequest
+	(*SetProductTagsResponse)(nil),          // 34: products.SetProductTagsResponse
+	(*SearchProductsByTagsRequest)(nil),     // 35: products.SearchProductsByTagsRequest
+	(*ListProductsResponse)(nil),            // 36: products.ListProductsResponse
+	(*ListProductsByDateRangeRequest)(nil),  // 37: products.ListProductsByDateRangeRequest
+	(*CalculateTaxRequest)(nil),             // 38: products.CalculateTaxRequest
+	(*CalculateTaxResponse)(nil),            // 39: products.CalculateTaxResponse
+	(*ListProductsRequest)(nil),             // 40: products.ListProductsRequest
+	(*ArchiveProductRequest)(nil),           // 41: products.ArchiveProductRequest
+	(*UnarchiveProductRequest)(nil),         // 42: products.UnarchiveProductRequest
+	(*UpsertProductEmbeddingRequest)(nil),   // 43: products.UpsertProductEmbeddingRequest
+	(*UpsertProductEmbeddingResponse)(nil),  // 44: products.UpsertProductEmbeddingResponse
+	(*GetSimilarProductsRequest)(nil),       // 45: products.GetSimilarProductsRequest
+	(*SimilarProduct)(nil),                  // 46: products.SimilarProduct
+	(*GetSimilarProductsResponse)(nil),      // 47: products.GetSimilarProductsResponse
+	(*FuzzySearchProductsRequest)(nil),      // 48: products.FuzzySearchProductsRequest
+	(*ProductSearchResult)(nil),             // 49: products.ProductSearchResult
+	(*FuzzySearchProductsResponse)(nil),     // 50: products.FuzzySearchProductsResponse
+	(*ImportProductsFromURLRequest)(nil),    // 51: products.ImportProductsFromURLRequest
+	(*ImportError)(nil),                     // 52: products.ImportError
+	(*ImportProgressUpdate)(nil),            // 53: products.ImportProgressUpdate
+	(*GetAlternativeProductsRequest)(nil),   // 54: products.GetAlternativeProductsRequest
+	(*GetAlternativeProductsResponse)(nil),  // 55: products.GetAlternativeProductsResponse
+	(*BulkUpdateProductStatusRequest)(nil),  // 56: products.BulkUpdateProductStatusRequest
+	(*BulkUpdateProductStatusResponse)(nil), // 57: products.BulkUpdateProductStatusResponse
+	(*ExportGoogleShoppingFeedRequest)(nil), // 58: products.ExportGoogleShoppingFeedRequest
+	(*ProductFAQ)(nil),                      // 59: products.ProductFAQ
+	(*ProductFAQResponse)(nil),              // 60: products.ProductFAQResponse
+	(*AddProductFAQRequest)(nil),            // 61: products.AddProductFAQRequest
+	(*UpdateProductFAQRequest)(nil),         // 62: products.UpdateProductFAQRequest
+	(*DeleteProductFAQRequest)(nil),         // 63: products.DeleteProductFAQRequest
+	(*DeleteProductFAQResponse)(nil),        // 64: products.DeleteProductFAQResponse
+	(*ReorderProductFAQsRequest)(nil),       // 65: products.ReorderProductFAQsRequest
+	(*ReorderProductFAQsResponse)(nil),      // 66: products.ReorderProductFAQsResponse
+	(*ListProductFAQsRequest)(nil),          // 67: products.ListProductFAQsRequest
+	(*UpdateProductRequest)(nil),            // 68: products.UpdateProductRequest
+	(*ProductVersion)(nil),                  // 69: products.ProductVersion
+	(*ProductVersionResponse)(nil),          // 70: products.ProductVersionResponse
+	(*GetProductVersionRequest)(nil),        // 71: products.GetProductVersionRequest
+	(*ListProductVersionsRequest)(nil),      // 72: products.ListProductVersionsRequest
+	(*ReviewProductRequest)(nil),            // 73: products.ReviewProductRequest
+	(*ReviewProductResponse)(nil),           // 74: products.ReviewProductResponse
+	(*GetTagSimilarProductsRequest)(nil),    // 75: products.GetTagSimilarProductsRequest
+	(*ScoredProduct)(nil),                   // 76: products.ScoredProduct
+	(*GetTagSimilarProductsResponse)(nil),   // 77: products.GetTagSimilarProductsResponse
+	(*GetCatalogPriceGiniRequest)(nil),      // 78: products.GetCatalogPriceGiniRequest
+	(*GetCatalogPriceGiniResponse)(nil),     // 79: products.GetCatalogPriceGiniResponse
+	(*SyncProductCatalogRequest)(nil),       // 80: products.SyncProductCatalogRequest
+	(*SyncEvent)(nil),                       // 81: products.SyncEvent
+	(*ProductCost)(nil),                     // 82: products.ProductCost
+	(*ProductCostResponse)(nil),             // 83: products.ProductCostResponse
+	(*SetProductCostRequest)(nil),           // 84: products.SetProductCostRequest
+	(*SetProductCostResponse)(nil),          // 85: products.SetProductCostResponse
+	(*GetProductMarginRequest)(nil),         // 86: products.GetProductMarginRequest
+	(*GetProductMarginResponse)(nil),        // 87: products.GetProductMarginResponse
+	(*GetProductCostHistoryRequest)(nil),    // 88: products.GetProductCostHistoryRequest
+	(*CloneProductRequest)(nil),             // 89: products.CloneProductRequest
+	(*CloneProductResponse)(nil),            // 90: products.CloneProductResponse
+	(*PurchaseLimit)(nil),                   // 91: products.PurchaseLimit
+	(*SetPurchaseLimitRequest)(nil),         // 92: products.SetPurchaseLimitRequest
+	(*SetPurchaseLimitResponse)(nil),        // 93: products.SetPurchaseLimitResponse
+	(*DeletePurchaseLimitRequest)(nil),      // 94: products.DeletePurchaseLimitRequest
+	(*DeletePurchaseLimitResponse)(nil),     // 95: products.DeletePurchaseLimitResponse
+	nil,                                     // 96: products.GetCatalogPriceGiniResponse.PercentilesEntry
+	(*timestamppb.Timestamp)(nil),           // 97: google.protobuf.Timestamp
 }
 var file_proto_products_proto_depIdxs = []int32{
-	0, // 0: products.ProductResponse.product:type_name -> products.Product
-	1, // 1: products.ProductService.CreateProduct:input_type -> products.CreateProductRequest
-	2, // 2: products.ProductService.GetProduct:input_type -> products.GetProductRequest
-	3, // 3: products.ProductService.CreateProduct:output_type -> products.ProductResponse
-	3, // 4: products.ProductService.GetProduct:output_type -> products.ProductResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	97,  // 0: products.Product.updated_at:type_name -> google.protobuf.Timestamp
+	3,   // 1: products.Product.status:type_name -> products.ProductStatus
+	4,   // 2: products.Product.review_status:type_name -> products.ReviewStatus
+	6,   // 3: products.ProductResponse.product:type_name -> products.Product
+	10,  // 4: products.LineItem.unit_price:type_name -> products.Money
+	10,  // 5: products.LineItem.total:type_name -> products.Money
+	11,  // 6: products.CalculateCartTotalRequest.items:type_name -> products.CartItem
+	12,  // 7: products.CalculateCartTotalResponse.line_items:type_name -> products.LineItem
+	10,  // 8: products.CalculateCartTotalResponse.subtotal:type_name -> products.Money
+	10,  // 9: products.CalculateCartTotalResponse.discount_amount:type_name -> products.Money
+	10,  // 10: products.CalculateCartTotalResponse.total:type_name -> products.Money
+	10,  // 11: products.CalculateCartTotalResponse.tax_amount:type_name -> products.Money
+	0,   // 12: products.ProductEvent.type:type_name -> products.ProductEventType
+	6,   // 13: products.ProductEvent.product:type_name -> products.Product
+	97,  // 14: products.ProductEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	23,  // 15: products.ProductEvent.price_alert:type_name -> products.PriceAlert
+	97,  // 16: products.ExportProductsParquetRequest.from:type_name -> google.protobuf.Timestamp
+	97,  // 17: products.ExportProductsParquetRequest.to:type_name -> google.protobuf.Timestamp
+	97,  // 18: products.CacheInvalidation.updated_at:type_name -> google.protobuf.Timestamp
+	1,   // 19: products.GetProductQRCodeRequest.format:type_name -> products.QRFormat
+	10,  // 20: products.PriceAlert.target_price:type_name -> products.Money
+	97,  // 21: products.PriceAlert.created_at:type_name -> google.protobuf.Timestamp
+	10,  // 22: products.CreatePriceAlertRequest.target_price:type_name -> products.Money
+	23,  // 23: products.PriceAlertResponse.price_alert:type_name -> products.PriceAlert
+	23,  // 24: products.ListPriceAlertsResponse.price_alerts:type_name -> products.PriceAlert
+	10,  // 25: products.GetPriceAlertStatsResponse.min_target:type_name -> products.Money
+	10,  // 26: products.GetPriceAlertStatsResponse.max_target:type_name -> products.Money
+	10,  // 27: products.GetPriceAlertStatsResponse.mean_target:type_name -> products.Money
+	10,  // 28: products.GetPriceAlertStatsResponse.median_target:type_name -> products.Money
+	32,  // 29: products.SetProductTagsResponse.tags:type_name -> products.Tag
+	2,   // 30: products.SearchProductsByTagsRequest.operator:type_name -> products.TagOperator
+	6,   // 31: products.ListProductsResponse.products:type_name -> products.Product
+	97,  // 32: products.ListProductsByDateRangeRequest.from:type_name -> google.protobuf.Timestamp
+	97,  // 33: products.ListProductsByDateRangeRequest.to:type_name -> google.protobuf.Timestamp
+	10,  // 34: products.CalculateTaxResponse.tax_amount:type_name -> products.Money
+	6,   // 35: products.SimilarProduct.product:type_name -> products.Product
+	46,  // 36: products.GetSimilarProductsResponse.products:type_name -> products.SimilarProduct
+	6,   // 37: products.ProductSearchResult.product:type_name -> products.Product
+	49,  // 38: products.FuzzySearchProductsResponse.products:type_name -> products.ProductSearchResult
+	5,   // 39: products.ImportProductsFromURLRequest.format:type_name -> products.ImportFormat
+	52,  // 40: products.ImportProgressUpdate.errors:type_name -> products.ImportError
+	6,   // 41: products.GetAlternativeProductsResponse.cheaper:type_name -> products.Product
+	6,   // 42: products.GetAlternativeProductsResponse.pricier:type_name -> products.Product
+	3,   // 43: products.BulkUpdateProductStatusRequest.new_status:type_name -> products.ProductStatus
+	97,  // 44: products.ProductFAQ.created_at:type_name -> google.protobuf.Timestamp
+	59,  // 45: products.ProductFAQResponse.faq:type_name -> products.ProductFAQ
+	59,  // 46: products.ReorderProductFAQsResponse.faqs:type_name -> products.ProductFAQ
+	10,  // 47: products.ProductVersion.price:type_name -> products.Money
+	97,  // 48: products.ProductVersion.created_at:type_name -> google.protobuf.Timestamp
+	69,  // 49: products.ProductVersionResponse.version:type_name -> products.ProductVersion
+	4,   // 50: products.ReviewProductRequest.status:type_name -> products.ReviewStatus
+	6,   // 51: products.ReviewProductResponse.product:type_name -> products.Product
+	6,   // 52: products.ScoredProduct.product:type_name -> products.Product
+	76,  // 53: products.GetTagSimilarProductsResponse.products:type_name -> products.ScoredProduct
+	96,  // 54: products.GetCatalogPriceGiniResponse.percentiles:type_name -> products.GetCatalogPriceGiniResponse.PercentilesEntry
+	97,  // 55: products.SyncProductCatalogRequest.since:type_name -> google.protobuf.Timestamp
+	6,   // 56: products.SyncEvent.product:type_name -> products.Product
+	97,  // 57: products.SyncEvent.deleted_at:type_name -> google.protobuf.Timestamp
+	10,  // 58: products.ProductCost.supplier_cost:type_name -> products.Money
+	97,  // 59: products.ProductCost.effective_from:type_name -> google.protobuf.Timestamp
+	97,  // 60: products.ProductCost.effective_to:type_name -> google.protobuf.Timestamp
+	97,  // 61: products.ProductCost.created_at:type_name -> google.protobuf.Timestamp
+	82,  // 62: products.ProductCostResponse.cost:type_name -> products.ProductCost
+	10,  // 63: products.SetProductCostRequest.cost:type_name -> products.Money
+	97,  // 64: products.SetProductCostRequest.effective_from:type_name -> google.protobuf.Timestamp
+	82,  // 65: products.SetProductCostResponse.cost:type_name -> products.ProductCost
+	97,  // 66: products.GetProductMarginRequest.at:type_name -> google.protobuf.Timestamp
+	10,  // 67: products.GetProductMarginResponse.sale_price:type_name -> products.Money
+	10,  // 68: products.GetProductMarginResponse.cost:type_name -> products.Money
+	97,  // 69: products.PurchaseLimit.updated_at:type_name -> google.protobuf.Timestamp
+	91,  // 70: products.SetPurchaseLimitResponse.limit:type_name -> products.PurchaseLimit
+	7,   // 71: products.ProductService.CreateProduct:input_type -> products.CreateProductRequest
+	8,   // 72: products.ProductService.GetProduct:input_type -> products.GetProductRequest
+	13,  // 73: products.ProductService.CalculateCartTotal:input_type -> products.CalculateCartTotalRequest
+	15,  // 74: products.ProductService.WatchProducts:input_type -> products.WatchProductsRequest
+	17,  // 75: products.ProductService.ExportProductsParquet:input_type -> products.ExportProductsParquetRequest
+	19,  // 76: products.ProductService.WatchCacheInvalidations:input_type -> products.WatchCacheInvalidationsRequest
+	21,  // 77: products.ProductService.GetProductQRCode:input_type -> products.GetProductQRCodeRequest
+	24,  // 78: products.ProductService.CreatePriceAlert:input_type -> products.CreatePriceAlertRequest
+	26,  // 79: products.ProductService.DeletePriceAlert:input_type -> products.DeletePriceAlertRequest
+	28,  // 80: products.ProductService.ListPriceAlerts:input_type -> products.ListPriceAlertsRequest
+	30,  // 81: products.ProductService.GetPriceAlertStats:input_type -> products.GetPriceAlertStatsRequest
+	33,  // 82: products.ProductService.SetProductTags:input_type -> products.SetProductTagsRequest
+	35,  // 83: products.ProductService.SearchProductsByTags:input_type -> products.SearchProductsByTagsRequest
+	37,  // 84: products.ProductService.ListProductsByDateRange:input_type -> products.ListProductsByDateRangeRequest
+	38,  // 85: products.ProductService.CalculateTax:input_type -> products.CalculateTaxRequest
+	40,  // 86: products.ProductService.ListProducts:input_type -> products.ListProductsRequest
+	41,  // 87: products.ProductService.ArchiveProduct:input_type -> products.ArchiveProductRequest
+	42,  // 88: products.ProductService.UnarchiveProduct:input_type -> products.UnarchiveProductRequest
+	43,  // 89: products.ProductService.UpsertProductEmbedding:input_type -> products.UpsertProductEmbeddingRequest
+	45,  // 90: products.ProductService.GetSimilarProducts:input_type -> products.GetSimilarProductsRequest
+	48,  // 91: products.ProductService.FuzzySearchProducts:input_type -> products.FuzzySearchProductsRequest
+	51,  // 92: products.ProductService.ImportProductsFromURL:input_type -> products.ImportProductsFromURLRequest
+	54,  // 93: products.ProductService.GetAlternativeProducts:input_type -> products.GetAlternativeProductsRequest
+	56,  // 94: products.ProductService.BulkUpdateProductStatus:input_type -> products.BulkUpdateProductStatusRequest
+	58,  // 95: products.ProductService.ExportGoogleShoppingFeed:input_type -> products.ExportGoogleShoppingFeedRequest
+	61,  // 96: products.ProductService.AddProductFAQ:input_type -> products.AddProductFAQRequest
+	62,  // 97: products.ProductService.UpdateProductFAQ:input_type -> products.UpdateProductFAQRequest
+	63,  // 98: products.ProductService.DeleteProductFAQ:input_type -> products.DeleteProductFAQRequest
+	65,  // 99: products.ProductService.ReorderProductFAQs:input_type -> products.ReorderProductFAQsRequest
+	67,  // 100: products.ProductService.ListProductFAQs:input_type -> products.ListProductFAQsRequest
+	68,  // 101: products.ProductService.UpdateProduct:input_type -> products.UpdateProductRequest
+	71,  // 102: products.ProductService.GetProductVersion:input_type -> products.GetProductVersionRequest
+	72,  // 103: products.ProductService.ListProductVersions:input_type -> products.ListProductVersionsRequest
+	73,  // 104: products.ProductService.ReviewProduct:input_type -> products.ReviewProductRequest
+	75,  // 105: products.ProductService.GetTagSimilarProducts:input_type -> products.GetTagSimilarProductsRequest
+	78,  // 106: products.ProductService.GetCatalogPriceGini:input_type -> products.GetCatalogPriceGiniRequest
+	80,  // 107: products.ProductService.SyncProductCatalog:input_type -> products.SyncProductCatalogRequest
+	84,  // 108: products.ProductService.SetProductCost:input_type -> products.SetProductCostRequest
+	86,  // 109: products.ProductService.GetProductMargin:input_type -> products.GetProductMarginRequest
+	88,  // 110: products.ProductService.GetProductCostHistory:input_type -> products.GetProductCostHistoryRequest
+	89,  // 111: products.ProductService.CloneProduct:input_type -> products.CloneProductRequest
+	92,  // 112: products.ProductService.SetPurchaseLimit:input_type -> products.SetPurchaseLimitRequest
+	94,  // 113: products.ProductService.DeletePurchaseLimit:input_type -> products.DeletePurchaseLimitRequest
+	9,   // 114: products.ProductService.CreateProduct:output_type -> products.ProductResponse
+	9,   // 115: products.ProductService.GetProduct:output_type -> products.ProductResponse
+	14,  // 116: products.ProductService.CalculateCartTotal:output_type -> products.CalculateCartTotalResponse
+	16,  // 117: products.ProductService.WatchProducts:output_type -> products.ProductEvent
+	18,  // 118: products.ProductService.ExportProductsParquet:output_type -> products.ExportChunk
+	20,  // 119: products.ProductService.WatchCacheInvalidations:output_type -> products.CacheInvalidation
+	22,  // 120: products.ProductService.GetProductQRCode:output_type -> products.GetProductQRCodeResponse
+	25,  // 121: products.ProductService.CreatePriceAlert:output_type -> products.PriceAlertResponse
+	27,  // 122: products.ProductService.DeletePriceAlert:output_type -> products.DeletePriceAlertResponse
+	29,  // 123: products.ProductService.ListPriceAlerts:output_type -> products.ListPriceAlertsResponse
+	31,  // 124: products.ProductService.GetPriceAlertStats:output_type -> products.GetPriceAlertStatsResponse
+	34,  // 125: products.ProductService.SetProductTags:output_type -> products.SetProductTagsResponse
+	36,  // 126: products.ProductService.SearchProductsByTags:output_type -> products.ListProductsResponse
+	36,  // 127: products.ProductService.ListProductsByDateRange:output_type -> products.ListProductsResponse
+	39,  // 128: products.ProductService.CalculateTax:output_type -> products.CalculateTaxResponse
+	36,  // 129: products.ProductService.ListProducts:output_type -> products.ListProductsResponse
+	9,   // 130: products.ProductService.ArchiveProduct:output_type -> products.ProductResponse
+	9,   // 131: products.ProductService.UnarchiveProduct:output_type -> products.ProductResponse
+	44,  // 132: products.ProductService.UpsertProductEmbedding:output_type -> products.UpsertProductEmbeddingResponse
+	47,  // 133: products.ProductService.GetSimilarProducts:output_type -> products.GetSimilarProductsResponse
+	50,  // 134: products.ProductService.FuzzySearchProducts:output_type -> products.FuzzySearchProductsResponse
+	53,  // 135: products.ProductService.ImportProductsFromURL:output_type -> products.ImportProgressUpdate
+	55,  // 136: products.ProductService.GetAlternativeProducts:output_type -> products.GetAlternativeProductsResponse
+	57,  // 137: products.ProductService.BulkUpdateProductStatus:output_type -> products.BulkUpdateProductStatusResponse
+	18,  // 138: products.ProductService.ExportGoogleShoppingFeed:output_type -> products.ExportChunk
+	60,  // 139: products.ProductService.AddProductFAQ:output_type -> products.ProductFAQResponse
+	60,  // 140: products.ProductService.UpdateProductFAQ:output_type -> products.ProductFAQResponse
+	64,  // 141: products.ProductService.DeleteProductFAQ:output_type -> products.DeleteProductFAQResponse
+	66,  // 142: products.ProductService.ReorderProductFAQs:output_type -> products.ReorderProductFAQsResponse
+	60,  // 143: products.ProductService.ListProductFAQs:output_type -> products.ProductFAQResponse
+	9,   // 144: products.ProductService.UpdateProduct:output_type -> products.ProductResponse
+	70,  // 145: products.ProductService.GetProductVersion:output_type -> products.ProductVersionResponse
+	70,  // 146: products.ProductService.ListProductVersions:output_type -> products.ProductVersionResponse
+	74,  // 147: products.ProductService.ReviewProduct:output_type -> products.ReviewProductResponse
+	77,  // 148: products.ProductService.GetTagSimilarProducts:output_type -> products.GetTagSimilarProductsResponse
+	79,  // 149: products.ProductService.GetCatalogPriceGini:output_type -> products.GetCatalogPriceGiniResponse
+	81,  // 150: products.ProductService.SyncProductCatalog:output_type -> products.SyncEvent
+	85,  // 151: products.ProductService.SetProductCost:output_type -> products.SetProductCostResponse
+	87,  // 152: products.ProductService.GetProductMargin:output_type -> products.GetProductMarginResponse
+	83,  // 153: products.ProductService.GetProductCostHistory:output_type -> products.ProductCostResponse
+	90,  // 154: products.ProductService.CloneProduct:output_type -> products.CloneProductResponse
+	93,  // 155: products.ProductService.SetPurchaseLimit:output_type -> products.SetPurchaseLimitResponse
+	95,  // 156: products.ProductService.DeletePurchaseLimit:output_type -> products.DeletePurchaseLimitResponse
+	114, // [114:157] is the sub-list for method output_type
+	71,  // [71:114] is the sub-list for method input_type
+	71,  // [71:71] is the sub-list for extension type_name
+	71,  // [71:71] is the sub-list for extension extendee
+	0,   // [0:71] is the sub-list for field type_name
 }
 
 func init() { file_proto_products_proto_init() }
@@ -279,18 +6145,20 @@ func file_proto_products_proto_init() {
 	if File_proto_products_proto != nil {
 		return
 	}
+	file_proto_products_proto_msgTypes[62].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_products_proto_rawDesc), len(file_proto_products_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      6,
+			NumMessages:   91,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_products_proto_goTypes,
 		DependencyIndexes: file_proto_products_proto_depIdxs,
+		EnumInfos:         file_proto_products_proto_enumTypes,
 		MessageInfos:      file_proto_products_proto_msgTypes,
 	}.Build()
 	File_proto_products_proto = out.File
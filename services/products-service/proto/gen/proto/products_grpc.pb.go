@@ -19,8 +19,49 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProductService_CreateProduct_FullMethodName = "/products.ProductService/CreateProduct"
-	ProductService_GetProduct_FullMethodName    = "/products.ProductService/GetProduct"
+	ProductService_CreateProduct_FullMethodName            = "/products.ProductService/CreateProduct"
+	ProductService_GetProduct_FullMethodName               = "/products.ProductService/GetProduct"
+	ProductService_CalculateCartTotal_FullMethodName       = "/products.ProductService/CalculateCartTotal"
+	ProductService_WatchProducts_FullMethodName            = "/products.ProductService/WatchProducts"
+	ProductService_ExportProductsParquet_FullMethodName    = "/products.ProductService/ExportProductsParquet"
+	ProductService_WatchCacheInvalidations_FullMethodName  = "/products.ProductService/WatchCacheInvalidations"
+	ProductService_GetProductQRCode_FullMethodName         = "/products.ProductService/GetProductQRCode"
+	ProductService_CreatePriceAlert_FullMethodName         = "/products.ProductService/CreatePriceAlert"
+	ProductService_DeletePriceAlert_FullMethodName         = "/products.ProductService/DeletePriceAlert"
+	ProductService_ListPriceAlerts_FullMethodName          = "/products.ProductService/ListPriceAlerts"
+	ProductService_GetPriceAlertStats_FullMethodName       = "/products.ProductService/GetPriceAlertStats"
+	ProductService_SetProductTags_FullMethodName           = "/products.ProductService/SetProductTags"
+	ProductService_SearchProductsByTags_FullMethodName     = "/products.ProductService/SearchProductsByTags"
+	ProductService_ListProductsByDateRange_FullMethodName  = "/products.ProductService/ListProductsByDateRange"
+	ProductService_CalculateTax_FullMethodName             = "/products.ProductService/CalculateTax"
+	ProductService_ListProducts_FullMethodName             = "/products.ProductService/ListProducts"
+	ProductService_ArchiveProduct_FullMethodName           = "/products.ProductService/ArchiveProduct"
+	ProductService_UnarchiveProduct_FullMethodName         = "/products.ProductService/UnarchiveProduct"
+	ProductService_UpsertProductEmbedding_FullMethodName   = "/products.ProductService/UpsertProductEmbedding"
+	ProductService_GetSimilarProducts_FullMethodName       = "/products.ProductService/GetSimilarProducts"
+	ProductService_FuzzySearchProducts_FullMethodName      = "/products.ProductService/FuzzySearchProducts"
+	ProductService_ImportProductsFromURL_FullMethodName    = "/products.ProductService/ImportProductsFromURL"
+	ProductService_GetAlternativeProducts_FullMethodName   = "/products.ProductService/GetAlternativeProducts"
+	ProductService_BulkUpdateProductStatus_FullMethodName  = "/products.ProductService/BulkUpdateProductStatus"
+	ProductService_ExportGoogleShoppingFeed_FullMethodName = "/products.ProductService/ExportGoogleShoppingFeed"
+	ProductService_AddProductFAQ_FullMethodName            = "/products.ProductService/AddProductFAQ"
+	ProductService_UpdateProductFAQ_FullMethodName         = "/products.ProductService/UpdateProductFAQ"
+	ProductService_DeleteProductFAQ_FullMethodName         = "/products.ProductService/DeleteProductFAQ"
+	ProductService_ReorderProductFAQs_FullMethodName       = "/products.ProductService/ReorderProductFAQs"
+	ProductService_ListProductFAQs_FullMethodName          = "/products.ProductService/ListProductFAQs"
+	ProductService_UpdateProduct_FullMethodName            = "/products.ProductService/UpdateProduct"
+	ProductService_GetProductVersion_FullMethodName        = "/products.ProductService/GetProductVersion"
+	ProductService_ListProductVersions_FullMethodName      = "/products.ProductService/ListProductVersions"
+	ProductService_ReviewProduct_FullMethodName            = "/products.ProductService/ReviewProduct"
+	ProductService_GetTagSimilarProducts_FullMethodName    = "/products.ProductService/GetTagSimilarProducts"
+	ProductService_GetCatalogPriceGini_FullMethodName      = "/products.ProductService/GetCatalogPriceGini"
+	ProductService_SyncProductCatalog_FullMethodName       = "/products.ProductService/SyncProductCatalog"
+	ProductService_SetProductCost_FullMethodName           = "/products.ProductService/SetProductCost"
+	ProductService_GetProductMargin_FullMethodName         = "/products.ProductService/GetProductMargin"
+	ProductService_GetProductCostHistory_FullMethodName    = "/products.ProductService/GetProductCostHistory"
+	ProductService_CloneProduct_FullMethodName             = "/products.ProductService/CloneProduct"
+	ProductService_SetPurchaseLimit_FullMethodName         = "/products.ProductService/SetPurchaseLimit"
+	ProductService_DeletePurchaseLimit_FullMethodName      = "/products.ProductService/DeletePurchaseLimit"
 )
 
 // ProductServiceClient is the client API for ProductService service.
@@ -29,6 +70,47 @@ const (
 type ProductServiceClient interface {
 	CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
 	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
+	CalculateCartTotal(ctx context.Context, in *CalculateCartTotalRequest, opts ...grpc.CallOption) (*CalculateCartTotalResponse, error)
+	WatchProducts(ctx context.Context, in *WatchProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductEvent], error)
+	ExportProductsParquet(ctx context.Context, in *ExportProductsParquetRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	WatchCacheInvalidations(ctx context.Context, in *WatchCacheInvalidationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CacheInvalidation], error)
+	GetProductQRCode(ctx context.Context, in *GetProductQRCodeRequest, opts ...grpc.CallOption) (*GetProductQRCodeResponse, error)
+	CreatePriceAlert(ctx context.Context, in *CreatePriceAlertRequest, opts ...grpc.CallOption) (*PriceAlertResponse, error)
+	DeletePriceAlert(ctx context.Context, in *DeletePriceAlertRequest, opts ...grpc.CallOption) (*DeletePriceAlertResponse, error)
+	ListPriceAlerts(ctx context.Context, in *ListPriceAlertsRequest, opts ...grpc.CallOption) (*ListPriceAlertsResponse, error)
+	GetPriceAlertStats(ctx context.Context, in *GetPriceAlertStatsRequest, opts ...grpc.CallOption) (*GetPriceAlertStatsResponse, error)
+	SetProductTags(ctx context.Context, in *SetProductTagsRequest, opts ...grpc.CallOption) (*SetProductTagsResponse, error)
+	SearchProductsByTags(ctx context.Context, in *SearchProductsByTagsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	ListProductsByDateRange(ctx context.Context, in *ListProductsByDateRangeRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	CalculateTax(ctx context.Context, in *CalculateTaxRequest, opts ...grpc.CallOption) (*CalculateTaxResponse, error)
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	ArchiveProduct(ctx context.Context, in *ArchiveProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
+	UnarchiveProduct(ctx context.Context, in *UnarchiveProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
+	UpsertProductEmbedding(ctx context.Context, in *UpsertProductEmbeddingRequest, opts ...grpc.CallOption) (*UpsertProductEmbeddingResponse, error)
+	GetSimilarProducts(ctx context.Context, in *GetSimilarProductsRequest, opts ...grpc.CallOption) (*GetSimilarProductsResponse, error)
+	FuzzySearchProducts(ctx context.Context, in *FuzzySearchProductsRequest, opts ...grpc.CallOption) (*FuzzySearchProductsResponse, error)
+	ImportProductsFromURL(ctx context.Context, in *ImportProductsFromURLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ImportProgressUpdate], error)
+	GetAlternativeProducts(ctx context.Context, in *GetAlternativeProductsRequest, opts ...grpc.CallOption) (*GetAlternativeProductsResponse, error)
+	BulkUpdateProductStatus(ctx context.Context, in *BulkUpdateProductStatusRequest, opts ...grpc.CallOption) (*BulkUpdateProductStatusResponse, error)
+	ExportGoogleShoppingFeed(ctx context.Context, in *ExportGoogleShoppingFeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	AddProductFAQ(ctx context.Context, in *AddProductFAQRequest, opts ...grpc.CallOption) (*ProductFAQResponse, error)
+	UpdateProductFAQ(ctx context.Context, in *UpdateProductFAQRequest, opts ...grpc.CallOption) (*ProductFAQResponse, error)
+	DeleteProductFAQ(ctx context.Context, in *DeleteProductFAQRequest, opts ...grpc.CallOption) (*DeleteProductFAQResponse, error)
+	ReorderProductFAQs(ctx context.Context, in *ReorderProductFAQsRequest, opts ...grpc.CallOption) (*ReorderProductFAQsResponse, error)
+	ListProductFAQs(ctx context.Context, in *ListProductFAQsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductFAQResponse], error)
+	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
+	GetProductVersion(ctx context.Context, in *GetProductVersionRequest, opts ...grpc.CallOption) (*ProductVersionResponse, error)
+	ListProductVersions(ctx context.Context, in *ListProductVersionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductVersionResponse], error)
+	ReviewProduct(ctx context.Context, in *ReviewProductRequest, opts ...grpc.CallOption) (*ReviewProductResponse, error)
+	GetTagSimilarProducts(ctx context.Context, in *GetTagSimilarProductsRequest, opts ...grpc.CallOption) (*GetTagSimilarProductsResponse, error)
+	GetCatalogPriceGini(ctx context.Context, in *GetCatalogPriceGiniRequest, opts ...grpc.CallOption) (*GetCatalogPriceGiniResponse, error)
+	SyncProductCatalog(ctx context.Context, in *SyncProductCatalogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SyncEvent], error)
+	SetProductCost(ctx context.Context, in *SetProductCostRequest, opts ...grpc.CallOption) (*SetProductCostResponse, error)
+	GetProductMargin(ctx context.Context, in *GetProductMarginRequest, opts ...grpc.CallOption) (*GetProductMarginResponse, error)
+	GetProductCostHistory(ctx context.Context, in *GetProductCostHistoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductCostResponse], error)
+	CloneProduct(ctx context.Context, in *CloneProductRequest, opts ...grpc.CallOption) (*CloneProductResponse, error)
+	SetPurchaseLimit(ctx context.Context, in *SetPurchaseLimitRequest, opts ...grpc.CallOption) (*SetPurchaseLimitResponse, error)
+	DeletePurchaseLimit(ctx context.Context, in *DeletePurchaseLimitRequest, opts ...grpc.CallOption) (*DeletePurchaseLimitResponse, error)
 }
 
 type productServiceClient struct {
@@ -59,81 +141,1411 @@ func (c *productServiceClient) GetProduct(ctx context.Context, in *GetProductReq
 	return out, nil
 }
 
+func (c *productServiceClient) CalculateCartTotal(ctx context.Context, in *CalculateCartTotalRequest, opts ...grpc.CallOption) (*CalculateCartTotalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateCartTotalResponse)
+	err := c.cc.Invoke(ctx, ProductService_CalculateCartTotal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) WatchProducts(ctx context.Context, in *WatchProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[0], ProductService_WatchProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchProductsRequest, ProductEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_WatchProductsClient = grpc.ServerStreamingClient[ProductEvent]
+
+func (c *productServiceClient) ExportProductsParquet(ctx context.Context, in *ExportProductsParquetRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[1], ProductService_ExportProductsParquet_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportProductsParquetRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportProductsParquetClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *productServiceClient) WatchCacheInvalidations(ctx context.Context, in *WatchCacheInvalidationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CacheInvalidation], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[2], ProductService_WatchCacheInvalidations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCacheInvalidationsRequest, CacheInvalidation]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_WatchCacheInvalidationsClient = grpc.ServerStreamingClient[CacheInvalidation]
+
+func (c *productServiceClient) GetProductQRCode(ctx context.Context, in *GetProductQRCodeRequest, opts ...grpc.CallOption) (*GetProductQRCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductQRCodeResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetProductQRCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreatePriceAlert(ctx context.Context, in *CreatePriceAlertRequest, opts ...grpc.CallOption) (*PriceAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PriceAlertResponse)
+	err := c.cc.Invoke(ctx, ProductService_CreatePriceAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeletePriceAlert(ctx context.Context, in *DeletePriceAlertRequest, opts ...grpc.CallOption) (*DeletePriceAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePriceAlertResponse)
+	err := c.cc.Invoke(ctx, ProductService_DeletePriceAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListPriceAlerts(ctx context.Context, in *ListPriceAlertsRequest, opts ...grpc.CallOption) (*ListPriceAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPriceAlertsResponse)
+	err := c.cc.Invoke(ctx, ProductService_ListPriceAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetPriceAlertStats(ctx context.Context, in *GetPriceAlertStatsRequest, opts ...grpc.CallOption) (*GetPriceAlertStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPriceAlertStatsResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetPriceAlertStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SetProductTags(ctx context.Context, in *SetProductTagsRequest, opts ...grpc.CallOption) (*SetProductTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetProductTagsResponse)
+	err := c.cc.Invoke(ctx, ProductService_SetProductTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SearchProductsByTags(ctx context.Context, in *SearchProductsByTagsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_SearchProductsByTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProductsByDateRange(ctx context.Context, in *ListProductsByDateRangeRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_ListProductsByDateRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CalculateTax(ctx context.Context, in *CalculateTaxRequest, opts ...grpc.CallOption) (*CalculateTaxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateTaxResponse)
+	err := c.cc.Invoke(ctx, ProductService_CalculateTax_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_ListProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ArchiveProduct(ctx context.Context, in *ArchiveProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_ArchiveProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UnarchiveProduct(ctx context.Context, in *UnarchiveProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_UnarchiveProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UpsertProductEmbedding(ctx context.Context, in *UpsertProductEmbeddingRequest, opts ...grpc.CallOption) (*UpsertProductEmbeddingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertProductEmbeddingResponse)
+	err := c.cc.Invoke(ctx, ProductService_UpsertProductEmbedding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetSimilarProducts(ctx context.Context, in *GetSimilarProductsRequest, opts ...grpc.CallOption) (*GetSimilarProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSimilarProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetSimilarProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) FuzzySearchProducts(ctx context.Context, in *FuzzySearchProductsRequest, opts ...grpc.CallOption) (*FuzzySearchProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FuzzySearchProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_FuzzySearchProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ImportProductsFromURL(ctx context.Context, in *ImportProductsFromURLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ImportProgressUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[3], ProductService_ImportProductsFromURL_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportProductsFromURLRequest, ImportProgressUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ImportProductsFromURLClient = grpc.ServerStreamingClient[ImportProgressUpdate]
+
+func (c *productServiceClient) GetAlternativeProducts(ctx context.Context, in *GetAlternativeProductsRequest, opts ...grpc.CallOption) (*GetAlternativeProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAlternativeProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetAlternativeProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BulkUpdateProductStatus(ctx context.Context, in *BulkUpdateProductStatusRequest, opts ...grpc.CallOption) (*BulkUpdateProductStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkUpdateProductStatusResponse)
+	err := c.cc.Invoke(ctx, ProductService_BulkUpdateProductStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ExportGoogleShoppingFeed(ctx context.Context, in *ExportGoogleShoppingFeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[4], ProductService_ExportGoogleShoppingFeed_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportGoogleShoppingFeedRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportGoogleShoppingFeedClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *productServiceClient) AddProductFAQ(ctx context.Context, in *AddProductFAQRequest, opts ...grpc.CallOption) (*ProductFAQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductFAQResponse)
+	err := c.cc.Invoke(ctx, ProductService_AddProductFAQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UpdateProductFAQ(ctx context.Context, in *UpdateProductFAQRequest, opts ...grpc.CallOption) (*ProductFAQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductFAQResponse)
+	err := c.cc.Invoke(ctx, ProductService_UpdateProductFAQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteProductFAQ(ctx context.Context, in *DeleteProductFAQRequest, opts ...grpc.CallOption) (*DeleteProductFAQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteProductFAQResponse)
+	err := c.cc.Invoke(ctx, ProductService_DeleteProductFAQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReorderProductFAQs(ctx context.Context, in *ReorderProductFAQsRequest, opts ...grpc.CallOption) (*ReorderProductFAQsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReorderProductFAQsResponse)
+	err := c.cc.Invoke(ctx, ProductService_ReorderProductFAQs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProductFAQs(ctx context.Context, in *ListProductFAQsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductFAQResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[5], ProductService_ListProductFAQs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListProductFAQsRequest, ProductFAQResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductFAQsClient = grpc.ServerStreamingClient[ProductFAQResponse]
+
+func (c *productServiceClient) UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_UpdateProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetProductVersion(ctx context.Context, in *GetProductVersionRequest, opts ...grpc.CallOption) (*ProductVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductVersionResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetProductVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProductVersions(ctx context.Context, in *ListProductVersionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductVersionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[6], ProductService_ListProductVersions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListProductVersionsRequest, ProductVersionResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductVersionsClient = grpc.ServerStreamingClient[ProductVersionResponse]
+
+func (c *productServiceClient) ReviewProduct(ctx context.Context, in *ReviewProductRequest, opts ...grpc.CallOption) (*ReviewProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_ReviewProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetTagSimilarProducts(ctx context.Context, in *GetTagSimilarProductsRequest, opts ...grpc.CallOption) (*GetTagSimilarProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagSimilarProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetTagSimilarProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetCatalogPriceGini(ctx context.Context, in *GetCatalogPriceGiniRequest, opts ...grpc.CallOption) (*GetCatalogPriceGiniResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCatalogPriceGiniResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetCatalogPriceGini_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SyncProductCatalog(ctx context.Context, in *SyncProductCatalogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SyncEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[7], ProductService_SyncProductCatalog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SyncProductCatalogRequest, SyncEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_SyncProductCatalogClient = grpc.ServerStreamingClient[SyncEvent]
+
+func (c *productServiceClient) SetProductCost(ctx context.Context, in *SetProductCostRequest, opts ...grpc.CallOption) (*SetProductCostResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetProductCostResponse)
+	err := c.cc.Invoke(ctx, ProductService_SetProductCost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetProductMargin(ctx context.Context, in *GetProductMarginRequest, opts ...grpc.CallOption) (*GetProductMarginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductMarginResponse)
+	err := c.cc.Invoke(ctx, ProductService_GetProductMargin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetProductCostHistory(ctx context.Context, in *GetProductCostHistoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductCostResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[8], ProductService_GetProductCostHistory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetProductCostHistoryRequest, ProductCostResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_GetProductCostHistoryClient = grpc.ServerStreamingClient[ProductCostResponse]
+
+func (c *productServiceClient) CloneProduct(ctx context.Context, in *CloneProductRequest, opts ...grpc.CallOption) (*CloneProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloneProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_CloneProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SetPurchaseLimit(ctx context.Context, in *SetPurchaseLimitRequest, opts ...grpc.CallOption) (*SetPurchaseLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPurchaseLimitResponse)
+	err := c.cc.Invoke(ctx, ProductService_SetPurchaseLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeletePurchaseLimit(ctx context.Context, in *DeletePurchaseLimitRequest, opts ...grpc.CallOption) (*DeletePurchaseLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePurchaseLimitResponse)
+	err := c.cc.Invoke(ctx, ProductService_DeletePurchaseLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility.
 type ProductServiceServer interface {
 	CreateProduct(context.Context, *CreateProductRequest) (*ProductResponse, error)
 	GetProduct(context.Context, *GetProductRequest) (*ProductResponse, error)
+	CalculateCartTotal(context.Context, *CalculateCartTotalRequest) (*CalculateCartTotalResponse, error)
+	WatchProducts(*WatchProductsRequest, grpc.ServerStreamingServer[ProductEvent]) error
+	ExportProductsParquet(*ExportProductsParquetRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	WatchCacheInvalidations(*WatchCacheInvalidationsRequest, grpc.ServerStreamingServer[CacheInvalidation]) error
+	GetProductQRCode(context.Context, *GetProductQRCodeRequest) (*GetProductQRCodeResponse, error)
+	CreatePriceAlert(context.Context, *CreatePriceAlertRequest) (*PriceAlertResponse, error)
+	DeletePriceAlert(context.Context, *DeletePriceAlertRequest) (*DeletePriceAlertResponse, error)
+	ListPriceAlerts(context.Context, *ListPriceAlertsRequest) (*ListPriceAlertsResponse, error)
+	GetPriceAlertStats(context.Context, *GetPriceAlertStatsRequest) (*GetPriceAlertStatsResponse, error)
+	SetProductTags(context.Context, *SetProductTagsRequest) (*SetProductTagsResponse, error)
+	SearchProductsByTags(context.Context, *SearchProductsByTagsRequest) (*ListProductsResponse, error)
+	ListProductsByDateRange(context.Context, *ListProductsByDateRangeRequest) (*ListProductsResponse, error)
+	CalculateTax(context.Context, *CalculateTaxRequest) (*CalculateTaxResponse, error)
+	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	ArchiveProduct(context.Context, *ArchiveProductRequest) (*ProductResponse, error)
+	UnarchiveProduct(context.Context, *UnarchiveProductRequest) (*ProductResponse, error)
+	UpsertProductEmbedding(context.Context, *UpsertProductEmbeddingRequest) (*UpsertProductEmbeddingResponse, error)
+	GetSimilarProducts(context.Context, *GetSimilarProductsRequest) (*GetSimilarProductsResponse, error)
+	FuzzySearchProducts(context.Context, *FuzzySearchProductsRequest) (*FuzzySearchProductsResponse, error)
+	ImportProductsFromURL(*ImportProductsFromURLRequest, grpc.ServerStreamingServer[ImportProgressUpdate]) error
+	GetAlternativeProducts(context.Context, *GetAlternativeProductsRequest) (*GetAlternativeProductsResponse, error)
+	BulkUpdateProductStatus(context.Context, *BulkUpdateProductStatusRequest) (*BulkUpdateProductStatusResponse, error)
+	ExportGoogleShoppingFeed(*ExportGoogleShoppingFeedRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	AddProductFAQ(context.Context, *AddProductFAQRequest) (*ProductFAQResponse, error)
+	UpdateProductFAQ(context.Context, *UpdateProductFAQRequest) (*ProductFAQResponse, error)
+	DeleteProductFAQ(context.Context, *DeleteProductFAQRequest) (*DeleteProductFAQResponse, error)
+	ReorderProductFAQs(context.Context, *ReorderProductFAQsRequest) (*ReorderProductFAQsResponse, error)
+	ListProductFAQs(*ListProductFAQsRequest, grpc.ServerStreamingServer[ProductFAQResponse]) error
+	UpdateProduct(context.Context, *UpdateProductRequest) (*ProductResponse, error)
+	GetProductVersion(context.Context, *GetProductVersionRequest) (*ProductVersionResponse, error)
+	ListProductVersions(*ListProductVersionsRequest, grpc.ServerStreamingServer[ProductVersionResponse]) error
+	ReviewProduct(context.Context, *ReviewProductRequest) (*ReviewProductResponse, error)
+	GetTagSimilarProducts(context.Context, *GetTagSimilarProductsRequest) (*GetTagSimilarProductsResponse, error)
+	GetCatalogPriceGini(context.Context, *GetCatalogPriceGiniRequest) (*GetCatalogPriceGiniResponse, error)
+	SyncProductCatalog(*SyncProductCatalogRequest, grpc.ServerStreamingServer[SyncEvent]) error
+	SetProductCost(context.Context, *SetProductCostRequest) (*SetProductCostResponse, error)
+	GetProductMargin(context.Context, *GetProductMarginRequest) (*GetProductMarginResponse, error)
+	GetProductCostHistory(*GetProductCostHistoryRequest, grpc.ServerStreamingServer[ProductCostResponse]) error
+	CloneProduct(context.Context, *CloneProductRequest) (*CloneProductResponse, error)
+	SetPurchaseLimit(context.Context, *SetPurchaseLimitRequest) (*SetPurchaseLimitResponse, error)
+	DeletePurchaseLimit(context.Context, *DeletePurchaseLimitRequest) (*DeletePurchaseLimitResponse, error)
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+// UnimplementedProductServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProductServiceServer struct{}
+
+func (UnimplementedProductServiceServer) CreateProduct(context.Context, *CreateProductRequest) (*ProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*ProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedProductServiceServer) CalculateCartTotal(context.Context, *CalculateCartTotalRequest) (*CalculateCartTotalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateCartTotal not implemented")
+}
+func (UnimplementedProductServiceServer) WatchProducts(*WatchProductsRequest, grpc.ServerStreamingServer[ProductEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProducts not implemented")
+}
+func (UnimplementedProductServiceServer) ExportProductsParquet(*ExportProductsParquetRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportProductsParquet not implemented")
+}
+func (UnimplementedProductServiceServer) WatchCacheInvalidations(*WatchCacheInvalidationsRequest, grpc.ServerStreamingServer[CacheInvalidation]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCacheInvalidations not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductQRCode(context.Context, *GetProductQRCodeRequest) (*GetProductQRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductQRCode not implemented")
+}
+func (UnimplementedProductServiceServer) CreatePriceAlert(context.Context, *CreatePriceAlertRequest) (*PriceAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePriceAlert not implemented")
+}
+func (UnimplementedProductServiceServer) DeletePriceAlert(context.Context, *DeletePriceAlertRequest) (*DeletePriceAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePriceAlert not implemented")
+}
+func (UnimplementedProductServiceServer) ListPriceAlerts(context.Context, *ListPriceAlertsRequest) (*ListPriceAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPriceAlerts not implemented")
+}
+func (UnimplementedProductServiceServer) GetPriceAlertStats(context.Context, *GetPriceAlertStatsRequest) (*GetPriceAlertStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceAlertStats not implemented")
+}
+func (UnimplementedProductServiceServer) SetProductTags(context.Context, *SetProductTagsRequest) (*SetProductTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProductTags not implemented")
+}
+func (UnimplementedProductServiceServer) SearchProductsByTags(context.Context, *SearchProductsByTagsRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProductsByTags not implemented")
+}
+func (UnimplementedProductServiceServer) ListProductsByDateRange(context.Context, *ListProductsByDateRangeRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProductsByDateRange not implemented")
+}
+func (UnimplementedProductServiceServer) CalculateTax(context.Context, *CalculateTaxRequest) (*CalculateTaxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateTax not implemented")
+}
+func (UnimplementedProductServiceServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedProductServiceServer) ArchiveProduct(context.Context, *ArchiveProductRequest) (*ProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveProduct not implemented")
+}
+func (UnimplementedProductServiceServer) UnarchiveProduct(context.Context, *UnarchiveProductRequest) (*ProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnarchiveProduct not implemented")
+}
+func (UnimplementedProductServiceServer) UpsertProductEmbedding(context.Context, *UpsertProductEmbeddingRequest) (*UpsertProductEmbeddingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertProductEmbedding not implemented")
+}
+func (UnimplementedProductServiceServer) GetSimilarProducts(context.Context, *GetSimilarProductsRequest) (*GetSimilarProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarProducts not implemented")
+}
+func (UnimplementedProductServiceServer) FuzzySearchProducts(context.Context, *FuzzySearchProductsRequest) (*FuzzySearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FuzzySearchProducts not implemented")
+}
+func (UnimplementedProductServiceServer) ImportProductsFromURL(*ImportProductsFromURLRequest, grpc.ServerStreamingServer[ImportProgressUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportProductsFromURL not implemented")
+}
+func (UnimplementedProductServiceServer) GetAlternativeProducts(context.Context, *GetAlternativeProductsRequest) (*GetAlternativeProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlternativeProducts not implemented")
+}
+func (UnimplementedProductServiceServer) BulkUpdateProductStatus(context.Context, *BulkUpdateProductStatusRequest) (*BulkUpdateProductStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpdateProductStatus not implemented")
+}
+func (UnimplementedProductServiceServer) ExportGoogleShoppingFeed(*ExportGoogleShoppingFeedRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportGoogleShoppingFeed not implemented")
+}
+func (UnimplementedProductServiceServer) AddProductFAQ(context.Context, *AddProductFAQRequest) (*ProductFAQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProductFAQ not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateProductFAQ(context.Context, *UpdateProductFAQRequest) (*ProductFAQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProductFAQ not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteProductFAQ(context.Context, *DeleteProductFAQRequest) (*DeleteProductFAQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProductFAQ not implemented")
+}
+func (UnimplementedProductServiceServer) ReorderProductFAQs(context.Context, *ReorderProductFAQsRequest) (*ReorderProductFAQsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderProductFAQs not implemented")
+}
+func (UnimplementedProductServiceServer) ListProductFAQs(*ListProductFAQsRequest, grpc.ServerStreamingServer[ProductFAQResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListProductFAQs not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*ProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductVersion(context.Context, *GetProductVersionRequest) (*ProductVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductVersion not implemented")
+}
+func (UnimplementedProductServiceServer) ListProductVersions(*ListProductVersionsRequest, grpc.ServerStreamingServer[ProductVersionResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListProductVersions not implemented")
+}
+func (UnimplementedProductServiceServer) ReviewProduct(context.Context, *ReviewProductRequest) (*ReviewProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetTagSimilarProducts(context.Context, *GetTagSimilarProductsRequest) (*GetTagSimilarProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTagSimilarProducts not implemented")
+}
+func (UnimplementedProductServiceServer) GetCatalogPriceGini(context.Context, *GetCatalogPriceGiniRequest) (*GetCatalogPriceGiniResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalogPriceGini not implemented")
+}
+func (UnimplementedProductServiceServer) SyncProductCatalog(*SyncProductCatalogRequest, grpc.ServerStreamingServer[SyncEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SyncProductCatalog not implemented")
+}
+func (UnimplementedProductServiceServer) SetProductCost(context.Context, *SetProductCostRequest) (*SetProductCostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProductCost not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductMargin(context.Context, *GetProductMarginRequest) (*GetProductMarginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductMargin not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductCostHistory(*GetProductCostHistoryRequest, grpc.ServerStreamingServer[ProductCostResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GetProductCostHistory not implemented")
+}
+func (UnimplementedProductServiceServer) CloneProduct(context.Context, *CloneProductRequest) (*CloneProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneProduct not implemented")
+}
+func (UnimplementedProductServiceServer) SetPurchaseLimit(context.Context, *SetPurchaseLimitRequest) (*SetPurchaseLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPurchaseLimit not implemented")
+}
+func (UnimplementedProductServiceServer) DeletePurchaseLimit(context.Context, *DeletePurchaseLimitRequest) (*DeletePurchaseLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePurchaseLimit not implemented")
+}
+func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
+func (UnimplementedProductServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductServiceServer will
+// result in compilation errors.
+type UnsafeProductServiceServer interface {
 	mustEmbedUnimplementedProductServiceServer()
 }
 
-// UnimplementedProductServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedProductServiceServer struct{}
+func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
+	// If the following call panics, it indicates UnimplementedProductServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProductService_ServiceDesc, srv)
+}
+
+func _ProductService_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CreateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateProduct(ctx, req.(*CreateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CalculateCartTotal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateCartTotalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CalculateCartTotal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CalculateCartTotal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CalculateCartTotal(ctx, req.(*CalculateCartTotalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_WatchProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).WatchProducts(m, &grpc.GenericServerStream[WatchProductsRequest, ProductEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_WatchProductsServer = grpc.ServerStreamingServer[ProductEvent]
+
+func _ProductService_ExportProductsParquet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportProductsParquetRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ExportProductsParquet(m, &grpc.GenericServerStream[ExportProductsParquetRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportProductsParquetServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _ProductService_WatchCacheInvalidations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCacheInvalidationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).WatchCacheInvalidations(m, &grpc.GenericServerStream[WatchCacheInvalidationsRequest, CacheInvalidation]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_WatchCacheInvalidationsServer = grpc.ServerStreamingServer[CacheInvalidation]
+
+func _ProductService_GetProductQRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProductQRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProductQRCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProductQRCode(ctx, req.(*GetProductQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreatePriceAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePriceAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreatePriceAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CreatePriceAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreatePriceAlert(ctx, req.(*CreatePriceAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeletePriceAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePriceAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeletePriceAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_DeletePriceAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeletePriceAlert(ctx, req.(*DeletePriceAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListPriceAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPriceAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListPriceAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListPriceAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListPriceAlerts(ctx, req.(*ListPriceAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetPriceAlertStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceAlertStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetPriceAlertStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetPriceAlertStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetPriceAlertStats(ctx, req.(*GetPriceAlertStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SetProductTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProductTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetProductTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SetProductTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetProductTags(ctx, req.(*SetProductTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SearchProductsByTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsByTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SearchProductsByTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SearchProductsByTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SearchProductsByTags(ctx, req.(*SearchProductsByTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListProductsByDateRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsByDateRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListProductsByDateRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListProductsByDateRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListProductsByDateRange(ctx, req.(*ListProductsByDateRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CalculateTax_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateTaxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CalculateTax(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CalculateTax_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CalculateTax(ctx, req.(*CalculateTaxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ArchiveProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ArchiveProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ArchiveProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ArchiveProduct(ctx, req.(*ArchiveProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UnarchiveProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnarchiveProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UnarchiveProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UnarchiveProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UnarchiveProduct(ctx, req.(*UnarchiveProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpsertProductEmbedding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertProductEmbeddingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpsertProductEmbedding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpsertProductEmbedding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpsertProductEmbedding(ctx, req.(*UpsertProductEmbeddingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetSimilarProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSimilarProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetSimilarProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetSimilarProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetSimilarProducts(ctx, req.(*GetSimilarProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_FuzzySearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FuzzySearchProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).FuzzySearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_FuzzySearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).FuzzySearchProducts(ctx, req.(*FuzzySearchProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ImportProductsFromURL_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ImportProductsFromURLRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ImportProductsFromURL(m, &grpc.GenericServerStream[ImportProductsFromURLRequest, ImportProgressUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ImportProductsFromURLServer = grpc.ServerStreamingServer[ImportProgressUpdate]
+
+func _ProductService_GetAlternativeProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlternativeProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetAlternativeProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetAlternativeProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetAlternativeProducts(ctx, req.(*GetAlternativeProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_BulkUpdateProductStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkUpdateProductStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BulkUpdateProductStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_BulkUpdateProductStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BulkUpdateProductStatus(ctx, req.(*BulkUpdateProductStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ExportGoogleShoppingFeed_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportGoogleShoppingFeedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ExportGoogleShoppingFeed(m, &grpc.GenericServerStream[ExportGoogleShoppingFeedRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportGoogleShoppingFeedServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _ProductService_AddProductFAQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductFAQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddProductFAQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_AddProductFAQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddProductFAQ(ctx, req.(*AddProductFAQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateProductFAQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductFAQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateProductFAQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpdateProductFAQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateProductFAQ(ctx, req.(*UpdateProductFAQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteProductFAQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProductFAQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteProductFAQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_DeleteProductFAQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteProductFAQ(ctx, req.(*DeleteProductFAQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedProductServiceServer) CreateProduct(context.Context, *CreateProductRequest) (*ProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+func _ProductService_ReorderProductFAQs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderProductFAQsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ReorderProductFAQs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ReorderProductFAQs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ReorderProductFAQs(ctx, req.(*ReorderProductFAQsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*ProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+
+func _ProductService_ListProductFAQs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListProductFAQsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ListProductFAQs(m, &grpc.GenericServerStream[ListProductFAQsRequest, ProductFAQResponse]{ServerStream: stream})
 }
-func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
-func (UnimplementedProductServiceServer) testEmbeddedByValue()                        {}
 
-// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ProductServiceServer will
-// result in compilation errors.
-type UnsafeProductServiceServer interface {
-	mustEmbedUnimplementedProductServiceServer()
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductFAQsServer = grpc.ServerStreamingServer[ProductFAQResponse]
+
+func _ProductService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
-	// If the following call panics, it indicates UnimplementedProductServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _ProductService_GetProductVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&ProductService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProductVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProductVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProductVersion(ctx, req.(*GetProductVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateProductRequest)
+func _ProductService_ListProductVersions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListProductVersionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ListProductVersions(m, &grpc.GenericServerStream[ListProductVersionsRequest, ProductVersionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductVersionsServer = grpc.ServerStreamingServer[ProductVersionResponse]
+
+func _ProductService_ReviewProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewProductRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).CreateProduct(ctx, in)
+		return srv.(ProductServiceServer).ReviewProduct(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ProductService_CreateProduct_FullMethodName,
+		FullMethod: ProductService_ReviewProduct_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).CreateProduct(ctx, req.(*CreateProductRequest))
+		return srv.(ProductServiceServer).ReviewProduct(ctx, req.(*ReviewProductRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProductRequest)
+func _ProductService_GetTagSimilarProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagSimilarProductsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetProduct(ctx, in)
+		return srv.(ProductServiceServer).GetTagSimilarProducts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ProductService_GetProduct_FullMethodName,
+		FullMethod: ProductService_GetTagSimilarProducts_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+		return srv.(ProductServiceServer).GetTagSimilarProducts(ctx, req.(*GetTagSimilarProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetCatalogPriceGini_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogPriceGiniRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetCatalogPriceGini(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetCatalogPriceGini_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetCatalogPriceGini(ctx, req.(*GetCatalogPriceGiniRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SyncProductCatalog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyncProductCatalogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).SyncProductCatalog(m, &grpc.GenericServerStream[SyncProductCatalogRequest, SyncEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_SyncProductCatalogServer = grpc.ServerStreamingServer[SyncEvent]
+
+func _ProductService_SetProductCost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProductCostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetProductCost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SetProductCost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetProductCost(ctx, req.(*SetProductCostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProductMargin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductMarginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProductMargin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProductMargin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProductMargin(ctx, req.(*GetProductMarginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProductCostHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetProductCostHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).GetProductCostHistory(m, &grpc.GenericServerStream[GetProductCostHistoryRequest, ProductCostResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_GetProductCostHistoryServer = grpc.ServerStreamingServer[ProductCostResponse]
+
+func _ProductService_CloneProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CloneProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CloneProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CloneProduct(ctx, req.(*CloneProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SetPurchaseLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPurchaseLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetPurchaseLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SetPurchaseLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetPurchaseLimit(ctx, req.(*SetPurchaseLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeletePurchaseLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePurchaseLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeletePurchaseLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_DeletePurchaseLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeletePurchaseLimit(ctx, req.(*DeletePurchaseLimitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -153,7 +1565,181 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProduct",
 			Handler:    _ProductService_GetProduct_Handler,
 		},
+		{
+			MethodName: "CalculateCartTotal",
+			Handler:    _ProductService_CalculateCartTotal_Handler,
+		},
+		{
+			MethodName: "GetProductQRCode",
+			Handler:    _ProductService_GetProductQRCode_Handler,
+		},
+		{
+			MethodName: "CreatePriceAlert",
+			Handler:    _ProductService_CreatePriceAlert_Handler,
+		},
+		{
+			MethodName: "DeletePriceAlert",
+			Handler:    _ProductService_DeletePriceAlert_Handler,
+		},
+		{
+			MethodName: "ListPriceAlerts",
+			Handler:    _ProductService_ListPriceAlerts_Handler,
+		},
+		{
+			MethodName: "GetPriceAlertStats",
+			Handler:    _ProductService_GetPriceAlertStats_Handler,
+		},
+		{
+			MethodName: "SetProductTags",
+			Handler:    _ProductService_SetProductTags_Handler,
+		},
+		{
+			MethodName: "SearchProductsByTags",
+			Handler:    _ProductService_SearchProductsByTags_Handler,
+		},
+		{
+			MethodName: "ListProductsByDateRange",
+			Handler:    _ProductService_ListProductsByDateRange_Handler,
+		},
+		{
+			MethodName: "CalculateTax",
+			Handler:    _ProductService_CalculateTax_Handler,
+		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _ProductService_ListProducts_Handler,
+		},
+		{
+			MethodName: "ArchiveProduct",
+			Handler:    _ProductService_ArchiveProduct_Handler,
+		},
+		{
+			MethodName: "UnarchiveProduct",
+			Handler:    _ProductService_UnarchiveProduct_Handler,
+		},
+		{
+			MethodName: "UpsertProductEmbedding",
+			Handler:    _ProductService_UpsertProductEmbedding_Handler,
+		},
+		{
+			MethodName: "GetSimilarProducts",
+			Handler:    _ProductService_GetSimilarProducts_Handler,
+		},
+		{
+			MethodName: "FuzzySearchProducts",
+			Handler:    _ProductService_FuzzySearchProducts_Handler,
+		},
+		{
+			MethodName: "GetAlternativeProducts",
+			Handler:    _ProductService_GetAlternativeProducts_Handler,
+		},
+		{
+			MethodName: "BulkUpdateProductStatus",
+			Handler:    _ProductService_BulkUpdateProductStatus_Handler,
+		},
+		{
+			MethodName: "AddProductFAQ",
+			Handler:    _ProductService_AddProductFAQ_Handler,
+		},
+		{
+			MethodName: "UpdateProductFAQ",
+			Handler:    _ProductService_UpdateProductFAQ_Handler,
+		},
+		{
+			MethodName: "DeleteProductFAQ",
+			Handler:    _ProductService_DeleteProductFAQ_Handler,
+		},
+		{
+			MethodName: "ReorderProductFAQs",
+			Handler:    _ProductService_ReorderProductFAQs_Handler,
+		},
+		{
+			MethodName: "UpdateProduct",
+			Handler:    _ProductService_UpdateProduct_Handler,
+		},
+		{
+			MethodName: "GetProductVersion",
+			Handler:    _ProductService_GetProductVersion_Handler,
+		},
+		{
+			MethodName: "ReviewProduct",
+			Handler:    _ProductService_ReviewProduct_Handler,
+		},
+		{
+			MethodName: "GetTagSimilarProducts",
+			Handler:    _ProductService_GetTagSimilarProducts_Handler,
+		},
+		{
+			MethodName: "GetCatalogPriceGini",
+			Handler:    _ProductService_GetCatalogPriceGini_Handler,
+		},
+		{
+			MethodName: "SetProductCost",
+			Handler:    _ProductService_SetProductCost_Handler,
+		},
+		{
+			MethodName: "GetProductMargin",
+			Handler:    _ProductService_GetProductMargin_Handler,
+		},
+		{
+			MethodName: "CloneProduct",
+			Handler:    _ProductService_CloneProduct_Handler,
+		},
+		{
+			MethodName: "SetPurchaseLimit",
+			Handler:    _ProductService_SetPurchaseLimit_Handler,
+		},
+		{
+			MethodName: "DeletePurchaseLimit",
+			Handler:    _ProductService_DeletePurchaseLimit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProducts",
+			Handler:       _ProductService_WatchProducts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportProductsParquet",
+			Handler:       _ProductService_ExportProductsParquet_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchCacheInvalidations",
+			Handler:       _ProductService_WatchCacheInvalidations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportProductsFromURL",
+			Handler:       _ProductService_ImportProductsFromURL_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportGoogleShoppingFeed",
+			Handler:       _ProductService_ExportGoogleShoppingFeed_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListProductFAQs",
+			Handler:       _ProductService_ListProductFAQs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListProductVersions",
+			Handler:       _ProductService_ListProductVersions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SyncProductCatalog",
+			Handler:       _ProductService_SyncProductCatalog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetProductCostHistory",
+			Handler:       _ProductService_GetProductCostHistory_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/products.proto",
 }
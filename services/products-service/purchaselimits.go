@@ -0,0 +1,110 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// maxPurchaseLimitWindowHours caps a purchase limit's window at a year.
+const maxPurchaseLimitWindowHours = 365 * 24
+
+// PurchaseLimit caps how many of a product each user may reserve in a
+// window of WindowHours, e.g. one per customer in a flash sale. Limits are
+// only stored for now: nothing in this service reserves stock, so the
+// counting belongs with the stock reservation that will enforce them.
+type PurchaseLimit struct {
+    ProductID          uint  `gorm:"primaryKey;autoIncrement:false"`
+    MaxQuantityPerUser int32 `gorm:"not null"`
+    WindowHours        int32 `gorm:"not null"`
+    UpdatedAt          time.Time
+}
+
+func (l *PurchaseLimit) toProto() *pb.PurchaseLimit {
+    return &pb.PurchaseLimit{
+        ProductId:          fmt.Sprint(l.ProductID),
+        MaxQuantityPerUser: l.MaxQuantityPerUser,
+        WindowHours:        l.WindowHours,
+        UpdatedAt:          timestamppb.New(l.UpdatedAt),
+    }
+}
+
+func parsePurchaseLimitProductID(id string) (uint64, error) {
+    productID, err := strconv.ParseUint(id, 10, 64)
+    if err != nil {
+        return 0, status.Errorf(codes.InvalidArgument, "invalid product id %q", id)
+    }
+    return productID, nil
+}
+
+// SetPurchaseLimit limits how many of a product each user may reserve per
+// window, replacing any limit the product had.
+func (s *server) SetPurchaseLimit(ctx context.Context, req *pb.SetPurchaseLimitRequest) (*pb.SetPurchaseLimitResponse, error) {
+    productID, err := parsePurchaseLimitProductID(req.ProductId)
+    if err != nil {
+        return nil, err
+    }
+    if req.MaxQuantityPerUser < 1 {
+        return nil, status.Error(codes.InvalidArgument, "max_quantity_per_user must be positive")
+    }
+    if req.WindowHours < 1 || req.WindowHours > maxPurchaseLimitWindowHours {
+        return nil, status.Errorf(codes.InvalidArgument, "window_hours must be between 1 and %d", maxPurchaseLimitWindowHours)
+    }
+
+    limit := PurchaseLimit{ProductID: uint(productID), MaxQuantityPerUser: req.MaxQuantityPerUser, WindowHours: req.WindowHours}
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.Select("id").First(&Product{}, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        err := tx.Clauses(clause.OnConflict{
+            Columns:   []clause.Column{{Name: "product_id"}},
+            DoUpdates: clause.AssignmentColumns([]string{"max_quantity_per_user", "window_hours", "updated_at"}),
+        }).Create(&limit).Error
+        if err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "set_purchase_limit", "product", productID, map[string]int32{
+            "max_quantity_per_user": req.MaxQuantityPerUser,
+            "window_hours":          req.WindowHours,
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.SetPurchaseLimitResponse{Limit: limit.toProto()}, nil
+}
+
+// DeletePurchaseLimit removes a product's purchase limit.
+func (s *server) DeletePurchaseLimit(ctx context.Context, req *pb.DeletePurchaseLimitRequest) (*pb.DeletePurchaseLimitResponse, error) {
+    productID, err := parsePurchaseLimitProductID(req.ProductId)
+    if err != nil {
+        return nil, err
+    }
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        result := tx.Delete(&PurchaseLimit{}, productID)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return status.Errorf(codes.NotFound, "product %s has no purchase limit", req.ProductId)
+        }
+        return recordAudit(ctx, tx, "delete_purchase_limit", "product", productID, map[string]interface{}{})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.DeletePurchaseLimitResponse{}, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestSetPurchaseLimitValidation(t *testing.T) {
+    tests := []struct {
+        name string
+        req  *pb.SetPurchaseLimitRequest
+    }{
+        {"bad product id", &pb.SetPurchaseLimitRequest{ProductId: "abc", MaxQuantityPerUser: 1, WindowHours: 24}},
+        {"zero quantity", &pb.SetPurchaseLimitRequest{ProductId: "1", MaxQuantityPerUser: 0, WindowHours: 24}},
+        {"zero window", &pb.SetPurchaseLimitRequest{ProductId: "1", MaxQuantityPerUser: 1, WindowHours: 0}},
+        {"window over a year", &pb.SetPurchaseLimitRequest{ProductId: "1", MaxQuantityPerUser: 1, WindowHours: maxPurchaseLimitWindowHours + 1}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            // No expectations are set: an invalid request must not reach
+            // the database.
+            db, _ := newMockDB(t)
+            s := &server{db: db}
+            if _, err := s.SetPurchaseLimit(context.Background(), tt.req); status.Code(err) != codes.InvalidArgument {
+                t.Errorf("err = %v, want InvalidArgument", err)
+            }
+        })
+    }
+}
+
+func TestSetPurchaseLimitUpsertsAndAudits(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    mock.ExpectExec(`INSERT INTO "purchase_limits" .* ON CONFLICT \("product_id"\) DO UPDATE SET "max_quantity_per_user"="excluded"."max_quantity_per_user","window_hours"="excluded"."window_hours","updated_at"="excluded"."updated_at"`).
+        WithArgs(7, 1, 24, sqlmock.AnyArg()).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("set_purchase_limit", "product", "7", sqlmock.AnyArg(), sqlmock.AnyArg(), `{"max_quantity_per_user":1,"window_hours":24}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.SetPurchaseLimit(context.Background(), &pb.SetPurchaseLimitRequest{ProductId: "7", MaxQuantityPerUser: 1, WindowHours: 24})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Limit.ProductId != "7" || res.Limit.MaxQuantityPerUser != 1 || res.Limit.WindowHours != 24 {
+        t.Errorf("limit = %v", res.Limit)
+    }
+}
+
+func TestSetPurchaseLimitUnknownProduct(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectRollback()
+
+    _, err := s.SetPurchaseLimit(context.Background(), &pb.SetPurchaseLimitRequest{ProductId: "7", MaxQuantityPerUser: 1, WindowHours: 24})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("err = %v, want NotFound", err)
+    }
+}
+
+func TestDeletePurchaseLimit(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    mock.ExpectBegin()
+    mock.ExpectExec(`DELETE FROM "purchase_limits" WHERE "purchase_limits"."product_id" = \$1`).
+        WithArgs(7).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("delete_purchase_limit", "product", "7", sqlmock.AnyArg(), sqlmock.AnyArg(), `{}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+    if _, err := s.DeletePurchaseLimit(context.Background(), &pb.DeletePurchaseLimitRequest{ProductId: "7"}); err != nil {
+        t.Fatal(err)
+    }
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DELETE FROM "purchase_limits"`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectRollback()
+    _, err := s.DeletePurchaseLimit(context.Background(), &pb.DeletePurchaseLimitRequest{ProductId: "7"})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("deleting a missing limit: err = %v, want NotFound", err)
+    }
+}
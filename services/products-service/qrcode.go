@@ -0,0 +1,124 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/redis/go-redis/v9"
+    "github.com/skip2/go-qrcode"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    minQRCodeSize  = 64
+    maxQRCodeSize  = 1024
+    qrCodeCacheTTL = time.Hour
+)
+
+// defaultBaseURL is used for product links when BASE_URL is not set.
+const defaultBaseURL = "http://localhost:8080"
+
+func (s *server) GetProductQRCode(ctx context.Context, req *pb.GetProductQRCodeRequest) (*pb.GetProductQRCodeResponse, error) {
+    id, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    size := clampQRCodeSize(int(req.Size))
+
+    contentType := "image/png"
+    if req.Format == pb.QRFormat_QR_FORMAT_SVG {
+        contentType = "image/svg+xml"
+    }
+
+    cacheKey := fmt.Sprintf("qrcode:%d:%d:%s", id, size, req.Format)
+    if s.redis != nil {
+        data, err := s.redis.Get(ctx, cacheKey).Bytes()
+        if err == nil {
+            return &pb.GetProductQRCodeResponse{ImageData: data, ContentType: contentType}, nil
+        }
+        if !errors.Is(err, redis.Nil) {
+            log.Printf("Failed to read QR code cache: %v", err)
+        }
+    }
+
+    var product Product
+    if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return nil, err
+    }
+
+    qr, err := qrcode.New(productURL(product.ID), qrcode.Medium)
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to encode QR code: %v", err)
+    }
+    var data []byte
+    if req.Format == pb.QRFormat_QR_FORMAT_SVG {
+        data = qrCodeSVG(qr, size)
+    } else if data, err = qr.PNG(size); err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to render QR code: %v", err)
+    }
+
+    if s.redis != nil {
+        if err := s.redis.Set(ctx, cacheKey, data, qrCodeCacheTTL).Err(); err != nil {
+            log.Printf("Failed to cache QR code: %v", err)
+        }
+    }
+    return &pb.GetProductQRCodeResponse{ImageData: data, ContentType: contentType}, nil
+}
+
+func clampQRCodeSize(size int) int {
+    if size < minQRCodeSize {
+        return minQRCodeSize
+    }
+    if size > maxQRCodeSize {
+        return maxQRCodeSize
+    }
+    return size
+}
+
+// storefrontURL is the BASE_URL product pages are served under, without a
+// trailing slash.
+func storefrontURL() string {
+    baseURL := os.Getenv("BASE_URL")
+    if baseURL == "" {
+        baseURL = defaultBaseURL
+    }
+    return strings.TrimSuffix(baseURL, "/")
+}
+
+func productURL(id uint) string {
+    return fmt.Sprintf("%s/products/%d", storefrontURL(), id)
+}
+
+// qrCodeSVG draws the QR matrix, quiet zone included, as one square per dark
+// module scaled to size pixels.
+func qrCodeSVG(qr *qrcode.QRCode, size int) []byte {
+    bitmap := qr.Bitmap()
+    n := len(bitmap)
+
+    var b strings.Builder
+    fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+    fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, n, n)
+    b.WriteString(`<path fill="#000000" d="`)
+    for y, row := range bitmap {
+        for x, dark := range row {
+            if dark {
+                fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+            }
+        }
+    }
+    b.WriteString(`"/></svg>`)
+    return []byte(b.String())
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "image/png"
+    "regexp"
+    "strconv"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// The helpers below decode a QR code the way a phone would, independently of
+// the encoder: sample the modules, read the format information, unmask, and
+// parse the data segments. They handle versions 1-6 at error correction level
+// M, which covers every product URL the service produces, and assume the
+// image is undamaged, so Reed-Solomon correction is skipped.
+
+// qrBlocksM gives, for versions 1-6 at level M, the number of blocks, the data
+// codewords per block and the total number of codewords.
+var qrBlocksM = map[int][3]int{
+    1: {1, 16, 26},
+    2: {1, 28, 44},
+    3: {1, 44, 70},
+    4: {2, 32, 100},
+    5: {2, 43, 134},
+    6: {4, 27, 172},
+}
+
+// qrFormatCode returns the masked 15-bit format information for the 5-bit
+// error correction level and mask pattern.
+func qrFormatCode(data int) int {
+    v := data << 10
+    for i := 14; i >= 10; i-- {
+        if v&(1<<i) != 0 {
+            v ^= 0x537 << (i - 10)
+        }
+    }
+    return (data<<10 | v) ^ 0x5412
+}
+
+func qrMasked(mask, i, j int) bool {
+    switch mask {
+    case 0:
+        return (i+j)%2 == 0
+    case 1:
+        return i%2 == 0
+    case 2:
+        return j%3 == 0
+    case 3:
+        return (i+j)%3 == 0
+    case 4:
+        return (i/2+j/3)%2 == 0
+    case 5:
+        return (i*j)%2+(i*j)%3 == 0
+    case 6:
+        return ((i*j)%2+(i*j)%3)%2 == 0
+    default:
+        return ((i+j)%2+(i*j)%3)%2 == 0
+    }
+}
+
+// qrFunctionModules marks the modules of a symbol of size n that hold
+// finder, timing, alignment and format patterns rather than data.
+func qrFunctionModules(n int) [][]bool {
+    f := make([][]bool, n)
+    for i := range f {
+        f[i] = make([]bool, n)
+    }
+    mark := func(top, left, size int) {
+        for i := top; i < top+size; i++ {
+            for j := left; j < left+size; j++ {
+                f[i][j] = true
+            }
+        }
+    }
+    mark(0, 0, 9)
+    mark(0, n-8, 8)
+    mark(n-8, 0, 8)
+    for i := 0; i < n; i++ {
+        f[6][i], f[i][6] = true, true
+    }
+    for j := n - 8; j < n; j++ {
+        f[8][j] = true
+    }
+    for i := n - 8; i < n; i++ {
+        f[i][8] = true
+    }
+    if n > 21 {
+        mark(n-9, n-9, 5)
+    }
+    return f
+}
+
+// decodeQR returns the text held by symbol, a QR matrix without its quiet
+// zone.
+func decodeQR(symbol [][]bool) (string, error) {
+    n := len(symbol)
+    version := (n - 17) / 4
+    blocks, ok := qrBlocksM[version]
+    if !ok || n != 17+4*version {
+        return "", fmt.Errorf("unsupported symbol size %d", n)
+    }
+
+    // The first copy of the format information runs along row 8 and column
+    // 8 beside the top-left finder.
+    var format int
+    for _, p := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+        format <<= 1
+        if symbol[p[0]][p[1]] {
+            format |= 1
+        }
+    }
+    best, bestDistance := 0, 16
+    for data := 0; data < 32; data++ {
+        if d := bitsSet(qrFormatCode(data) ^ format); d < bestDistance {
+            best, bestDistance = data, d
+        }
+    }
+    if bestDistance > 3 {
+        return "", errors.New("unreadable format information")
+    }
+    if level := best >> 3; level != 0 {
+        return "", fmt.Errorf("error correction level bits %02b, want M", level)
+    }
+    mask := best & 7
+
+    function := qrFunctionModules(n)
+    var bits []bool
+    up := true
+    for j := n - 1; j > 0; j -= 2 {
+        if j == 6 {
+            j--
+        }
+        for k := 0; k < n; k++ {
+            i := k
+            if up {
+                i = n - 1 - k
+            }
+            for _, col := range []int{j, j - 1} {
+                if !function[i][col] {
+                    bits = append(bits, symbol[i][col] != qrMasked(mask, i, col))
+                }
+            }
+        }
+        up = !up
+    }
+
+    numBlocks, perBlock, total := blocks[0], blocks[1], blocks[2]
+    if len(bits) < total*8 {
+        return "", fmt.Errorf("read %d data bits, want at least %d", len(bits), total*8)
+    }
+    data := make([]byte, numBlocks*perBlock)
+    for idx := range data {
+        var b byte
+        for _, bit := range bits[idx*8 : idx*8+8] {
+            b <<= 1
+            if bit {
+                b |= 1
+            }
+        }
+        data[(idx%numBlocks)*perBlock+idx/numBlocks] = b
+    }
+    return parseQRSegments(data)
+}
+
+func bitsSet(v int) int {
+    n := 0
+    for ; v != 0; v &= v - 1 {
+        n++
+    }
+    return n
+}
+
+// parseQRSegments reads the numeric, alphanumeric and byte segments of a
+// version 1-9 symbol's data codewords.
+func parseQRSegments(data []byte) (string, error) {
+    const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+    pos := 0
+    read := func(n int) (int, bool) {
+        if pos+n > len(data)*8 {
+            return 0, false
+        }
+        v := 0
+        for i := 0; i < n; i++ {
+            v <<= 1
+            if data[(pos+i)/8]&(0x80>>((pos+i)%8)) != 0 {
+                v |= 1
+            }
+        }
+        pos += n
+        return v, true
+    }
+
+    var out []byte
+    for {
+        mode, ok := read(4)
+        if !ok || mode == 0 {
+            return string(out), nil
+        }
+        switch mode {
+        case 1:
+            count, _ := read(10)
+            for ; count >= 3; count -= 3 {
+                v, _ := read(10)
+                out = append(out, fmt.Sprintf("%03d", v)...)
+            }
+            if count == 2 {
+                v, _ := read(7)
+                out = append(out, fmt.Sprintf("%02d", v)...)
+            } else if count == 1 {
+                v, _ := read(4)
+                out = append(out, fmt.Sprintf("%d", v)...)
+            }
+        case 2:
+            count, _ := read(9)
+            for ; count >= 2; count -= 2 {
+                v, _ := read(11)
+                out = append(out, alphanumeric[v/45], alphanumeric[v%45])
+            }
+            if count == 1 {
+                v, _ := read(6)
+                out = append(out, alphanumeric[v])
+            }
+        case 4:
+            count, _ := read(8)
+            for i := 0; i < count; i++ {
+                v, ok := read(8)
+                if !ok {
+                    return "", errors.New("byte segment runs past the data")
+                }
+                out = append(out, byte(v))
+            }
+        default:
+            return "", fmt.Errorf("unsupported segment mode %04b", mode)
+        }
+    }
+}
+
+// cropQuietZone trims the light border around the modules.
+func cropQuietZone(modules [][]bool) [][]bool {
+    top, left, bottom, right := len(modules), len(modules), -1, -1
+    for i, row := range modules {
+        for j, dark := range row {
+            if dark {
+                top, left = min(top, i), min(left, j)
+                bottom, right = max(bottom, i), max(right, j)
+            }
+        }
+    }
+    var symbol [][]bool
+    for _, row := range modules[top : bottom+1] {
+        symbol = append(symbol, row[left:right+1])
+    }
+    return symbol
+}
+
+// sampleQRPNG reads the module grid from a rendered PNG. The symbol size is
+// found from the timing pattern, which alternates one module at a time
+// along row 6 between the top finder patterns.
+func sampleQRPNG(t *testing.T, data []byte) [][]bool {
+    t.Helper()
+    img, err := png.Decode(bytes.NewReader(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+    bounds := img.Bounds()
+    dark := func(x, y int) bool {
+        r, g, b, _ := img.At(x, y).RGBA()
+        return r+g+b < 3*0x8000
+    }
+
+    x0, y0 := -1, -1
+    for y := bounds.Min.Y; y < bounds.Max.Y && x0 < 0; y++ {
+        for x := bounds.Min.X; x < bounds.Max.X; x++ {
+            if dark(x, y) {
+                x0, y0 = x, y
+                break
+            }
+        }
+    }
+    if x0 < 0 {
+        t.Fatal("image has no dark pixels")
+    }
+    x1 := bounds.Max.X - 1
+    for !dark(x1, y0) {
+        x1--
+    }
+    // The finder's left edge is dark for 7 modules, so its last pixel lies
+    // in row 6.
+    row6 := y0
+    for dark(x0, row6+1) {
+        row6++
+    }
+    runs := 0
+    for x := x0; x <= x1; x++ {
+        if dark(x, row6) && !dark(x-1, row6) {
+            runs++
+        }
+    }
+    // Two finders plus (n-15)/2 dark timing modules.
+    n := 2*(runs-2) + 15
+    module := float64(x1-x0+1) / float64(n)
+
+    symbol := make([][]bool, n)
+    for i := range symbol {
+        symbol[i] = make([]bool, n)
+        for j := range symbol[i] {
+            symbol[i][j] = dark(x0+int((float64(j)+0.5)*module), y0+int((float64(i)+0.5)*module))
+        }
+    }
+    return symbol
+}
+
+var svgModule = regexp.MustCompile(`M(\d+) (\d+)h1v1h-1z`)
+var svgViewBox = regexp.MustCompile(`viewBox="0 0 (\d+) (\d+)"`)
+
+// sampleQRSVG reads the module grid from the service's SVG rendering.
+func sampleQRSVG(t *testing.T, data []byte) [][]bool {
+    t.Helper()
+    box := svgViewBox.FindSubmatch(data)
+    if box == nil {
+        t.Fatalf("no viewBox in %.100s", data)
+    }
+    n, _ := strconv.Atoi(string(box[1]))
+    modules := make([][]bool, n)
+    for i := range modules {
+        modules[i] = make([]bool, n)
+    }
+    for _, m := range svgModule.FindAllSubmatch(data, -1) {
+        x, _ := strconv.Atoi(string(m[1]))
+        y, _ := strconv.Atoi(string(m[2]))
+        modules[y][x] = true
+    }
+    return cropQuietZone(modules)
+}
+
+func TestGetProductQRCodeDecodesToProductURL(t *testing.T) {
+    t.Setenv("BASE_URL", "https://shop.example.com/")
+    const want = "https://shop.example.com/products/42"
+
+    tests := []struct {
+        name   string
+        format pb.QRFormat
+        size   int32
+        sample func(*testing.T, []byte) [][]bool
+    }{
+        {"png at the minimum size", pb.QRFormat_QR_FORMAT_PNG, 10, sampleQRPNG},
+        {"png", pb.QRFormat_QR_FORMAT_PNG, 300, sampleQRPNG},
+        {"png at the maximum size", pb.QRFormat_QR_FORMAT_PNG, 5000, sampleQRPNG},
+        {"svg", pb.QRFormat_QR_FORMAT_SVG, 256, sampleQRSVG},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, mock := newMockDB(t)
+            mock.ExpectQuery(`SELECT \* FROM "products"`).WithArgs(42).
+                WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(42, "Mug"))
+
+            res, err := (&server{db: db}).GetProductQRCode(context.Background(), &pb.GetProductQRCodeRequest{ProductId: "42", Size: tt.size, Format: tt.format})
+            if err != nil {
+                t.Fatal(err)
+            }
+            if tt.format == pb.QRFormat_QR_FORMAT_PNG {
+                img, err := png.DecodeConfig(bytes.NewReader(res.ImageData))
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if wantSize := clampQRCodeSize(int(tt.size)); img.Width != wantSize || img.Height != wantSize {
+                    t.Errorf("image is %dx%d, want %dx%d", img.Width, img.Height, wantSize, wantSize)
+                }
+            }
+
+            got, err := decodeQR(tt.sample(t, res.ImageData))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != want {
+                t.Errorf("decoded %q, want %q", got, want)
+            }
+        })
+    }
+}
+
+func TestGetProductQRCodeRejectsBadIDs(t *testing.T) {
+    db, _ := newMockDB(t)
+    for _, id := range []string{"", "abc", "-1", "1 OR 1=1"} {
+        _, err := (&server{db: db}).GetProductQRCode(context.Background(), &pb.GetProductQRCodeRequest{ProductId: id})
+        if status.Code(err) != codes.InvalidArgument {
+            t.Errorf("product id %q: %v, want InvalidArgument", id, err)
+        }
+    }
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+    "context"
+    "crypto/sha256"
+    "database/sql"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "gorm.io/gorm"
+    "gorm.io/gorm/callbacks"
+)
+
+// queryCacheTTL bounds how long a cached SELECT result may be served.
+const queryCacheTTL = time.Second
+
+// queryCacheSweepInterval is how often expired entries are removed from the
+// in-memory cache.
+const queryCacheSweepInterval = time.Minute
+
+// Cache is the storage backend used by CachingPlugin.
+type Cache interface {
+    Get(ctx context.Context, key string) ([]byte, bool)
+    Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+// CachingPlugin caches the results of SELECT statements keyed by their SQL and
+// arguments. Writes to a table bump that table's generation, which is part of
+// the key, so earlier results for the table are never read again and simply
+// expire from the backend.
+//
+// A write inside a transaction bumps the generation when the transaction
+// commits. Bumping it earlier would let another connection cache the old row
+// under the new generation before the write became visible.
+//
+// Only the statement's main table is tracked, and queries inside a
+// transaction or with a locking clause always go to the database.
+type CachingPlugin struct {
+    cache Cache
+    ttl   time.Duration
+
+    mu          sync.Mutex
+    generations map[string]uint64
+    // global is bumped by raw statements, whose tables are not known.
+    global uint64
+}
+
+type cachedResult struct {
+    RowsAffected int64
+    Dest         json.RawMessage
+}
+
+func NewCachingPlugin(cache Cache, ttl time.Duration) *CachingPlugin {
+    return &CachingPlugin{cache: cache, ttl: ttl, generations: make(map[string]uint64)}
+}
+
+func (p *CachingPlugin) Name() string {
+    return "caching"
+}
+
+func (p *CachingPlugin) Initialize(db *gorm.DB) error {
+    pool := &cachingConnPool{ConnPool: db.ConnPool, plugin: p}
+    db.ConnPool = pool
+    db.Statement.ConnPool = pool
+
+    if err := db.Callback().Query().Replace("gorm:query", p.query); err != nil {
+        return err
+    }
+    if err := db.Callback().Create().After("gorm:create").Register("caching:invalidate", p.invalidate); err != nil {
+        return err
+    }
+    if err := db.Callback().Update().After("gorm:update").Register("caching:invalidate", p.invalidate); err != nil {
+        return err
+    }
+    if err := db.Callback().Delete().After("gorm:delete").Register("caching:invalidate", p.invalidate); err != nil {
+        return err
+    }
+    return db.Callback().Raw().After("gorm:raw").Register("caching:invalidate", p.invalidateAll)
+}
+
+func (p *CachingPlugin) query(db *gorm.DB) {
+    if db.Error != nil || db.DryRun {
+        callbacks.Query(db)
+        return
+    }
+
+    callbacks.BuildQuerySQL(db)
+    if db.Error != nil {
+        return
+    }
+    key, ok := p.cacheKey(db)
+    if !ok {
+        callbacks.Query(db)
+        return
+    }
+
+    ctx := db.Statement.Context
+    if data, hit := p.cache.Get(ctx, key); hit {
+        var result cachedResult
+        if err := json.Unmarshal(data, &result); err == nil {
+            if err := json.Unmarshal(result.Dest, db.Statement.Dest); err == nil {
+                db.RowsAffected = result.RowsAffected
+                return
+            }
+        }
+    }
+
+    callbacks.Query(db)
+    // Empty results are left uncached so First keeps reporting
+    // ErrRecordNotFound from the database.
+    if db.Error != nil || db.RowsAffected == 0 {
+        return
+    }
+    dest, err := json.Marshal(db.Statement.Dest)
+    if err != nil {
+        return
+    }
+    data, err := json.Marshal(cachedResult{RowsAffected: db.RowsAffected, Dest: dest})
+    if err != nil {
+        return
+    }
+    p.cache.Set(ctx, key, data, p.ttl)
+}
+
+// cacheKey hashes the statement into a key, or reports false when the
+// statement must not be cached.
+func (p *CachingPlugin) cacheKey(db *gorm.DB) (string, bool) {
+    stmt := db.Statement
+    sql := stmt.SQL.String()
+    if stmt.Table == "" || stmt.Dest == nil || !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+        return "", false
+    }
+    if _, locking := stmt.Clauses["FOR"]; locking {
+        return "", false
+    }
+    if _, inTx := stmt.ConnPool.(gorm.TxCommitter); inTx {
+        return "", false
+    }
+
+    p.mu.Lock()
+    generation, global := p.generations[stmt.Table], p.global
+    p.mu.Unlock()
+
+    h := sha256.New()
+    fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\x00%v", stmt.Table, generation, global, sql, stmt.Vars)
+    return "query:" + hex.EncodeToString(h.Sum(nil)), true
+}
+
+func (p *CachingPlugin) invalidate(db *gorm.DB) {
+    if db.Statement.Table == "" {
+        p.invalidateAll(db)
+        return
+    }
+    if tx, ok := db.Statement.ConnPool.(*cachingTx); ok {
+        tx.mu.Lock()
+        tx.tables[db.Statement.Table] = true
+        tx.mu.Unlock()
+        return
+    }
+    p.bump([]string{db.Statement.Table}, false)
+}
+
+func (p *CachingPlugin) invalidateAll(db *gorm.DB) {
+    if tx, ok := db.Statement.ConnPool.(*cachingTx); ok {
+        tx.mu.Lock()
+        tx.all = true
+        tx.mu.Unlock()
+        return
+    }
+    p.bump(nil, true)
+}
+
+// invalidateOn bumps table's generation for every event relayed through hub
+// until ctx is cancelled. Commits only bump the generations of the instance
+// that made them, so this is how changes made on other instances evict the
+// results cached here. Dropped events invalidate every table.
+func (p *CachingPlugin) invalidateOn(ctx context.Context, hub *eventHub, table string) {
+    sub := hub.subscribe()
+    defer hub.unsubscribe(sub)
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-sub.dropped:
+            sub.takeDropped()
+            p.bump(nil, true)
+        case <-sub.events:
+            p.bump([]string{table}, false)
+        }
+    }
+}
+
+func (p *CachingPlugin) bump(tables []string, all bool) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    for _, table := range tables {
+        p.generations[table]++
+    }
+    if all {
+        p.global++
+    }
+}
+
+// cachingConnPool wraps the database so that transactions begun on it can
+// report the tables they wrote to once they commit.
+type cachingConnPool struct {
+    gorm.ConnPool
+    plugin *CachingPlugin
+}
+
+func (c *cachingConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+    var (
+        tx  gorm.ConnPool
+        err error
+    )
+    switch beginner := c.ConnPool.(type) {
+    case gorm.TxBeginner:
+        tx, err = beginner.BeginTx(ctx, opts)
+    case gorm.ConnPoolBeginner:
+        tx, err = beginner.BeginTx(ctx, opts)
+    default:
+        err = gorm.ErrInvalidTransaction
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &cachingTx{ConnPool: tx, pool: c, tables: make(map[string]bool)}, nil
+}
+
+// GetDBConn lets gorm.DB.DB reach the *sql.DB underneath.
+func (c *cachingConnPool) GetDBConn() (*sql.DB, error) {
+    if connector, ok := c.ConnPool.(gorm.GetDBConnector); ok {
+        return connector.GetDBConn()
+    }
+    if db, ok := c.ConnPool.(*sql.DB); ok {
+        return db, nil
+    }
+    return nil, gorm.ErrInvalidDB
+}
+
+// cachingTx collects the tables written in a transaction and invalidates
+// them after it commits.
+type cachingTx struct {
+    gorm.ConnPool
+    pool *cachingConnPool
+
+    mu     sync.Mutex
+    tables map[string]bool
+    all    bool
+}
+
+// Commit invalidates the written tables even when the commit fails, since
+// the outcome of a failed commit is not always known.
+func (tx *cachingTx) Commit() error {
+    err := tx.ConnPool.(gorm.TxCommitter).Commit()
+
+    tx.mu.Lock()
+    tables := make([]string, 0, len(tx.tables))
+    for table := range tx.tables {
+        tables = append(tables, table)
+    }
+    all := tx.all
+    tx.mu.Unlock()
+
+    tx.pool.plugin.bump(tables, all)
+    return err
+}
+
+func (tx *cachingTx) Rollback() error {
+    return tx.ConnPool.(gorm.TxCommitter).Rollback()
+}
+
+func (tx *cachingTx) GetDBConn() (*sql.DB, error) {
+    return tx.pool.GetDBConn()
+}
+
+// MemoryCache is an in-process Cache. Expired entries are dropped on read and
+// swept periodically.
+type MemoryCache struct {
+    entries sync.Map
+}
+
+type memoryCacheEntry struct {
+    value     []byte
+    expiresAt time.Time
+}
+
+func NewMemoryCache(sweepInterval time.Duration) *MemoryCache {
+    c := &MemoryCache{}
+    go func() {
+        for range time.Tick(sweepInterval) {
+            c.sweep()
+        }
+    }()
+    return c
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
+    v, ok := c.entries.Load(key)
+    if !ok {
+        return nil, false
+    }
+    entry := v.(memoryCacheEntry)
+    if time.Now().After(entry.expiresAt) {
+        c.entries.Delete(key)
+        return nil, false
+    }
+    return entry.value, true
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+    c.entries.Store(key, memoryCacheEntry{value: value, expiresAt: time.Now().Add(ttl)})
+}
+
+func (c *MemoryCache) sweep() {
+    now := time.Now()
+    c.entries.Range(func(key, v interface{}) bool {
+        if now.After(v.(memoryCacheEntry).expiresAt) {
+            c.entries.Delete(key)
+        }
+        return true
+    })
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// newCachedMockDB returns a mock database with CachingPlugin installed.
+func newCachedMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+    t.Helper()
+    db, mock := newMockDB(t)
+    if err := db.Use(NewCachingPlugin(NewMemoryCache(time.Hour), time.Hour)); err != nil {
+        t.Fatal(err)
+    }
+    return db, mock
+}
+
+func expectProductQuery(mock sqlmock.Sqlmock, name string) {
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, name))
+}
+
+func firstProduct(t *testing.T, db *gorm.DB) Product {
+    t.Helper()
+    var product Product
+    if err := db.First(&product, 1).Error; err != nil {
+        t.Fatal(err)
+    }
+    return product
+}
+
+func TestCachingPluginHitSkipsDatabase(t *testing.T) {
+    db, mock := newCachedMockDB(t)
+    expectProductQuery(mock, "Mug")
+
+    first := firstProduct(t, db)
+    // The mock fails any query beyond the one expected above.
+    second := firstProduct(t, db)
+    if second.ID != first.ID || second.Name != "Mug" {
+        t.Errorf("cached product = %d %q, want %d %q", second.ID, second.Name, first.ID, "Mug")
+    }
+}
+
+func TestCachingPluginInvalidatesOnCommit(t *testing.T) {
+    db, mock := newCachedMockDB(t)
+    expectProductQuery(mock, "Mug")
+    firstProduct(t, db)
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`UPDATE "products"`).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+    expectProductQuery(mock, "Cup")
+
+    err := db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&Product{}).Where("id = ?", 1).Update("name", "Cup").Error; err != nil {
+            return err
+        }
+        // Other connections still read the committed row, so the cached
+        // copy is still right.
+        if got := firstProduct(t, db); got.Name != "Mug" {
+            t.Errorf("before commit: name = %q, want Mug from the cache", got.Name)
+        }
+        return nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := firstProduct(t, db); got.Name != "Cup" {
+        t.Errorf("after commit: name = %q, want Cup", got.Name)
+    }
+}
+
+func TestCachingPluginKeepsCacheOnRollback(t *testing.T) {
+    db, mock := newCachedMockDB(t)
+    expectProductQuery(mock, "Mug")
+    firstProduct(t, db)
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`UPDATE "products"`).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectRollback()
+
+    err := db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&Product{}).Where("id = ?", 1).Update("name", "Cup").Error; err != nil {
+            return err
+        }
+        return gorm.ErrInvalidData
+    })
+    if err != gorm.ErrInvalidData {
+        t.Fatalf("Transaction = %v, want the callback's error", err)
+    }
+    if got := firstProduct(t, db); got.Name != "Mug" {
+        t.Errorf("after rollback: name = %q, want Mug from the cache", got.Name)
+    }
+}
+
+func TestCachingPluginSkipsQueriesInTransaction(t *testing.T) {
+    db, mock := newCachedMockDB(t)
+    mock.ExpectBegin()
+    expectProductQuery(mock, "Mug")
+    expectProductQuery(mock, "Mug")
+    mock.ExpectCommit()
+
+    err := db.Transaction(func(tx *gorm.DB) error {
+        firstProduct(t, tx)
+        firstProduct(t, tx)
+        return nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestCachingPluginInvalidatesOnRelayedEvents(t *testing.T) {
+    plugin := NewCachingPlugin(NewMemoryCache(time.Hour), time.Hour)
+    db, mock := newMockDB(t)
+    if err := db.Use(plugin); err != nil {
+        t.Fatal(err)
+    }
+    hub := newEventHub("test")
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go plugin.invalidateOn(ctx, hub, "products")
+    waitForSubscribers(t, hub, 1)
+
+    expectProductQuery(mock, "Mug")
+    firstProduct(t, db)
+
+    // The product is renamed on another instance; this one only hears of it
+    // through the outbox.
+    hub.publish(&pb.ProductEvent{Type: pb.ProductEventType_PRODUCT_UPDATED, Product: &pb.Product{Id: "1", Name: "Cup"}})
+    expectProductQuery(mock, "Cup")
+    deadline := time.Now().Add(5 * time.Second)
+    for firstProduct(t, db).Name != "Cup" {
+        if time.Now().After(deadline) {
+            t.Fatal("cached product still served after a relayed change")
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+}
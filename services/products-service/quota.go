@@ -0,0 +1,235 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "os"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    consulapi "github.com/hashicorp/consul/api"
+    "google.golang.org/genproto/googleapis/rpc/errdetails"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// quotaLimitsKVKey is the Consul KV key holding quota limits. QUOTA_LIMITS is
+// used when it is not set.
+const quotaLimitsKVKey = serviceName + "/quota-limits"
+
+// QuotaUsage counts the calls a tenant made to a method in one period. Rows
+// are only ever changed by the upsert in quotaEnforcer.acquire.
+type QuotaUsage struct {
+    Tenant    string `gorm:"primaryKey"`
+    Method    string `gorm:"primaryKey"`
+    Period    string `gorm:"primaryKey"`
+    Count     int64  `gorm:"not null"`
+    UpdatedAt time.Time
+}
+
+// quotaLimits holds monthly call limits by method, keyed by tenant. The
+// empty tenant holds the defaults for tenants without their own limit.
+// Methods without any limit are unlimited.
+type quotaLimits map[string]map[string]int64
+
+// parseQuotaLimits parses entries like
+// "CreateProduct=10000,acme:CreateProduct=50000". Methods are named without
+// their service, so a limit covers every API version of the method.
+func parseQuotaLimits(raw string) (quotaLimits, error) {
+    limits := quotaLimits{}
+    for _, entry := range strings.Split(raw, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        key, value, ok := strings.Cut(entry, "=")
+        if !ok {
+            return nil, fmt.Errorf("quota limit %q is not method=limit", entry)
+        }
+        tenant, method, ok := strings.Cut(key, ":")
+        if !ok {
+            tenant, method = "", key
+        }
+        limit, err := strconv.ParseInt(value, 10, 64)
+        if err != nil || limit < 0 {
+            return nil, fmt.Errorf("invalid limit in quota limit %q", entry)
+        }
+        if limits[tenant] == nil {
+            limits[tenant] = make(map[string]int64)
+        }
+        limits[tenant][method] = limit
+    }
+    return limits, nil
+}
+
+// loadQuotaLimits reads limits from Consul KV, falling back to QUOTA_LIMITS.
+func loadQuotaLimits(consul *consulapi.Client) (quotaLimits, error) {
+    pair, _, err := consul.KV().Get(quotaLimitsKVKey, nil)
+    if err != nil {
+        log.Printf("Failed to read %s from Consul KV, using QUOTA_LIMITS: %v", quotaLimitsKVKey, err)
+    }
+    if pair != nil {
+        return parseQuotaLimits(string(pair.Value))
+    }
+    return parseQuotaLimits(os.Getenv("QUOTA_LIMITS"))
+}
+
+func (l quotaLimits) limit(tenant, method string) (int64, bool) {
+    if limit, ok := l[tenant][method]; ok {
+        return limit, true
+    }
+    limit, ok := l[""][method]
+    return limit, ok
+}
+
+// methods returns every method that has a limit for some tenant.
+func (l quotaLimits) methods() map[string]bool {
+    methods := make(map[string]bool)
+    for _, byMethod := range l {
+        for method := range byMethod {
+            methods[method] = true
+        }
+    }
+    return methods
+}
+
+// quotaPeriod returns the calendar month t falls in, in UTC, and when it ends.
+func quotaPeriod(t time.Time) (string, time.Time) {
+    t = t.UTC()
+    start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+    return start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
+
+type quotaEnforcer struct {
+    db     *gorm.DB
+    limits quotaLimits
+}
+
+// acquire counts one call against the tenant's quota for method, unless the
+// quota is used up. The check and the increment are a single statement, so
+// concurrent calls can never push the count past the limit.
+func (q *quotaEnforcer) acquire(ctx context.Context, tenant, method, period string, limit int64) (bool, error) {
+    if limit == 0 {
+        return false, nil
+    }
+    var counts []int64
+    err := q.db.WithContext(ctx).Raw(`
+        INSERT INTO quota_usages (tenant, method, period, count, updated_at)
+        VALUES (?, ?, ?, 1, ?)
+        ON CONFLICT (tenant, method, period) DO UPDATE
+            SET count = quota_usages.count + 1, updated_at = EXCLUDED.updated_at
+            WHERE quota_usages.count < ?
+        RETURNING count`,
+        tenant, method, period, time.Now(), limit).Scan(&counts).Error
+    if err != nil {
+        return false, err
+    }
+    return len(counts) > 0, nil
+}
+
+// release gives back a call whose handler failed.
+func (q *quotaEnforcer) release(tenant, method, period string) {
+    err := q.db.Model(&QuotaUsage{}).
+        Where("tenant = ? AND method = ? AND period = ? AND count > 0", tenant, method, period).
+        Update("count", gorm.Expr("count - 1")).Error
+    if err != nil {
+        log.Printf("Failed to release quota for %s %s: %v", tenant, method, err)
+    }
+}
+
+// unaryInterceptor enforces monthly quotas. It must run after authentication
+// so the caller's tenant is known.
+func (q *quotaEnforcer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    method := path.Base(info.FullMethod)
+    tenant := tenantFromContext(ctx)
+    limit, ok := q.limits.limit(tenant, method)
+    if !ok {
+        return handler(ctx, req)
+    }
+
+    period, resetsAt := quotaPeriod(time.Now())
+    acquired, err := q.acquire(ctx, tenant, method, period, limit)
+    if err != nil {
+        log.Printf("Quota check for %s %s failed: %v", tenant, method, err)
+        return nil, status.Error(codes.Unavailable, "quota check failed")
+    }
+    if !acquired {
+        return nil, quotaExceededError(method, limit, resetsAt)
+    }
+
+    resp, err := handler(ctx, req)
+    if err != nil {
+        q.release(tenant, method, period)
+    }
+    return resp, err
+}
+
+func quotaExceededError(method string, limit int64, resetsAt time.Time) error {
+    st := status.Newf(codes.ResourceExhausted, "monthly quota of %d calls to %s exceeded", limit, method)
+    withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+        Reason: "QUOTA_EXCEEDED",
+        Domain: serviceName,
+        Metadata: map[string]string{
+            "method":     method,
+            "limit":      strconv.FormatInt(limit, 10),
+            "reset_time": resetsAt.Format(time.RFC3339),
+        },
+    })
+    if err != nil {
+        return st.Err()
+    }
+    return withDetails.Err()
+}
+
+type quotaServer struct {
+    pb.UnimplementedQuotaServiceServer
+    quotas *quotaEnforcer
+}
+
+// GetQuotaUsage reports the current period's usage of every quota-governed
+// method. Callers see their own tenant; admins may ask about any tenant.
+func (s *quotaServer) GetQuotaUsage(ctx context.Context, req *pb.GetQuotaUsageRequest) (*pb.GetQuotaUsageResponse, error) {
+    tenant := tenantFromContext(ctx)
+    if req.Tenant != "" && req.Tenant != tenant {
+        if r, ok := roleFromContext(ctx); ok && r < roleAdmin {
+            return nil, status.Error(codes.PermissionDenied, "only admins may read another tenant's quota usage")
+        }
+        tenant = req.Tenant
+    }
+
+    period, resetsAt := quotaPeriod(time.Now())
+    var rows []QuotaUsage
+    if err := s.quotas.db.WithContext(ctx).Where("tenant = ? AND period = ?", tenant, period).Find(&rows).Error; err != nil {
+        return nil, err
+    }
+    used := make(map[string]int64, len(rows))
+    for _, row := range rows {
+        used[row.Method] = row.Count
+    }
+
+    res := &pb.GetQuotaUsageResponse{Tenant: tenant}
+    for method := range s.quotas.limits.methods() {
+        limit, ok := s.quotas.limits.limit(tenant, method)
+        if !ok {
+            continue
+        }
+        res.Usage = append(res.Usage, &pb.QuotaUsage{
+            Method:   method,
+            Period:   period,
+            Used:     used[method],
+            Limit:    limit,
+            ResetsAt: timestamppb.New(resetsAt),
+        })
+    }
+    sort.Slice(res.Usage, func(i, j int) bool { return res.Usage[i].Method < res.Usage[j].Method })
+    return res, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/genproto/googleapis/rpc/errdetails"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func tenantContext(tenant string) context.Context {
+    return context.WithValue(context.Background(), tenantContextKey{}, tenant)
+}
+
+func TestParseQuotaLimits(t *testing.T) {
+    limits, err := parseQuotaLimits(" CreateProduct=100, acme:CreateProduct=5000,acme:CreatePriceAlert=0 ")
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        tenant, method string
+        want           int64
+        wantOk         bool
+    }{
+        {"globex", "CreateProduct", 100, true},
+        {"acme", "CreateProduct", 5000, true},
+        {"acme", "CreatePriceAlert", 0, true},
+        {"globex", "CreatePriceAlert", 0, false},
+        {"acme", "GetProduct", 0, false},
+    }
+    for _, tt := range tests {
+        if got, ok := limits.limit(tt.tenant, tt.method); got != tt.want || ok != tt.wantOk {
+            t.Errorf("limit(%s, %s) = %d, %v, want %d, %v", tt.tenant, tt.method, got, ok, tt.want, tt.wantOk)
+        }
+    }
+
+    for _, raw := range []string{"CreateProduct", "CreateProduct=lots", "CreateProduct=-1"} {
+        if _, err := parseQuotaLimits(raw); err == nil {
+            t.Errorf("parseQuotaLimits(%q) succeeded", raw)
+        }
+    }
+}
+
+func TestQuotaPeriodIsTheUTCMonth(t *testing.T) {
+    period, resetsAt := quotaPeriod(time.Date(2024, 12, 31, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)))
+    if period != "2025-01" || !resetsAt.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+        t.Errorf("quotaPeriod = %s resetting at %v, want 2025-01 resetting on 2025-02-01", period, resetsAt)
+    }
+}
+
+func TestQuotaExceededCarriesErrorInfo(t *testing.T) {
+    db, mock := newMockDB(t)
+    q := &quotaEnforcer{db: db, limits: quotaLimits{"": {"CreateProduct": 10}}}
+    // The upsert's WHERE leaves the full row alone, so nothing comes back.
+    mock.ExpectQuery(`INSERT INTO quota_usages`).WillReturnRows(sqlmock.NewRows([]string{"count"}))
+
+    info := &grpc.UnaryServerInfo{FullMethod: pb.ProductService_CreateProduct_FullMethodName}
+    _, err := q.unaryInterceptor(tenantContext("acme"), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+        t.Error("handler called with the quota used up")
+        return nil, nil
+    })
+    if status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("err = %v, want ResourceExhausted", err)
+    }
+    details := status.Convert(err).Details()
+    if len(details) != 1 {
+        t.Fatalf("got %d error details, want an ErrorInfo", len(details))
+    }
+    errorInfo, ok := details[0].(*errdetails.ErrorInfo)
+    if !ok || errorInfo.Reason != "QUOTA_EXCEEDED" || errorInfo.Metadata["limit"] != "10" {
+        t.Errorf("details = %v, want QUOTA_EXCEEDED with limit 10", details[0])
+    }
+    _, resetsAt := quotaPeriod(time.Now())
+    if errorInfo.Metadata["reset_time"] != resetsAt.Format(time.RFC3339) {
+        t.Errorf("reset_time = %s, want %s", errorInfo.Metadata["reset_time"], resetsAt.Format(time.RFC3339))
+    }
+}
+
+func TestQuotaGivesBackFailedCalls(t *testing.T) {
+    db, mock := newMockDB(t)
+    q := &quotaEnforcer{db: db, limits: quotaLimits{"": {"CreateProduct": 10}}}
+    mock.ExpectQuery(`INSERT INTO quota_usages`).
+        WithArgs("acme", "CreateProduct", sqlmock.AnyArg(), sqlmock.AnyArg(), 10).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+    mock.ExpectBegin()
+    mock.ExpectExec(`UPDATE "quota_usages" SET "count"=count - 1`).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    info := &grpc.UnaryServerInfo{FullMethod: pb.ProductService_CreateProduct_FullMethodName}
+    failure := status.Error(codes.InvalidArgument, "name must not be empty")
+    _, err := q.unaryInterceptor(tenantContext("acme"), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+        return nil, failure
+    })
+    if !errors.Is(err, failure) {
+        t.Errorf("err = %v, want the handler's error", err)
+    }
+}
+
+// TestQuotaConcurrentCallsNeverOvershoot hammers one quota from 100
+// goroutines. It needs PostgreSQL, because the guarantee comes from the
+// upsert itself.
+func TestQuotaConcurrentCallsNeverOvershoot(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&QuotaUsage{}); err != nil {
+        t.Fatal(err)
+    }
+    const limit, callers = 60, 100
+    q := &quotaEnforcer{db: db, limits: quotaLimits{"": {"CreateProduct": limit}}}
+    info := &grpc.UnaryServerInfo{FullMethod: pb.ProductService_CreateProduct_FullMethodName}
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+    start := make(chan struct{})
+    codesByTenant := map[string][]codes.Code{"acme": make([]codes.Code, callers), "globex": make([]codes.Code, callers)}
+    var wg sync.WaitGroup
+    for tenant, got := range codesByTenant {
+        for i := 0; i < callers; i++ {
+            wg.Add(1)
+            go func(tenant string, got []codes.Code, i int) {
+                defer wg.Done()
+                <-start
+                _, err := q.unaryInterceptor(tenantContext(tenant), nil, info, handler)
+                got[i] = status.Code(err)
+            }(tenant, got, i)
+        }
+    }
+    close(start)
+    wg.Wait()
+
+    period, _ := quotaPeriod(time.Now())
+    for tenant, got := range codesByTenant {
+        counts := map[codes.Code]int{}
+        for _, code := range got {
+            counts[code]++
+        }
+        if counts[codes.OK] != limit || counts[codes.ResourceExhausted] != callers-limit {
+            t.Errorf("%s: %v, want %d OK and %d ResourceExhausted", tenant, counts, limit, callers-limit)
+        }
+        var usage QuotaUsage
+        if err := db.Where("tenant = ? AND method = ? AND period = ?", tenant, "CreateProduct", period).First(&usage).Error; err != nil {
+            t.Fatal(err)
+        }
+        if usage.Count != limit {
+            t.Errorf("%s: stored count %d, want exactly %d", tenant, usage.Count, limit)
+        }
+    }
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "os"
+    "sync"
+    "time"
+)
+
+// defaultDedupWindow is used when DEDUP_WINDOW is not set.
+const defaultDedupWindow = 5 * time.Second
+
+// maxRecentCreates bounds how many fingerprints are remembered at once; the
+// oldest is forgotten first.
+const maxRecentCreates = 10000
+
+// recentCreates remembers the products created within the dedup window, keyed
+// by a fingerprint of their content, so a double-submitted CreateProduct can
+// be answered with the first product instead of inserting another row.
+type recentCreates struct {
+    window time.Duration
+
+    mu      sync.Mutex
+    entries map[string]*recentCreate
+    // ring holds fingerprints in insertion order; next is the oldest slot.
+    ring []string
+    next int
+}
+
+type recentCreate struct {
+    // done is closed once the first create has finished.
+    done      chan struct{}
+    product   *Product
+    expiresAt time.Time
+    slot      int
+}
+
+func newRecentCreates(window time.Duration) *recentCreates {
+    return &recentCreates{
+        window:  window,
+        entries: make(map[string]*recentCreate),
+        ring:    make([]string, maxRecentCreates),
+    }
+}
+
+// dedupWindow parses DEDUP_WINDOW. Zero disables deduplication.
+func dedupWindow() time.Duration {
+    value := os.Getenv("DEDUP_WINDOW")
+    if value == "" {
+        return defaultDedupWindow
+    }
+    d, err := time.ParseDuration(value)
+    if err != nil || d < 0 {
+        log.Printf("Invalid DEDUP_WINDOW=%q, using default %v", value, defaultDedupWindow)
+        return defaultDedupWindow
+    }
+    return d
+}
+
+// productFingerprint identifies a product's content as sent by actor of
+// tenant, so that callers and tenants creating the same product do not get
+// each other's.
+func productFingerprint(tenant, actor, name, description, brand, imageURL string, priceCents int64) string {
+    return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%d\x00%s", tenant, actor, name, description, brand, imageURL, priceCents, defaultCurrency)
+}
+
+// claim returns the product already created for fingerprint within the
+// window, waiting for it if that create is still in flight. Otherwise it
+// returns nil and a finish func the caller must call with the product it
+// created, or nil if the create failed. A nil recentCreates deduplicates
+// nothing.
+func (r *recentCreates) claim(ctx context.Context, fingerprint string) (*Product, func(*Product), error) {
+    if r == nil || r.window == 0 {
+        return nil, func(*Product) {}, nil
+    }
+
+    for {
+        r.mu.Lock()
+        entry, ok := r.entries[fingerprint]
+        if !ok || time.Now().After(entry.expiresAt) {
+            entry = &recentCreate{done: make(chan struct{}), expiresAt: time.Now().Add(r.window)}
+            r.store(fingerprint, entry)
+            r.mu.Unlock()
+            return nil, func(product *Product) { r.finish(fingerprint, entry, product) }, nil
+        }
+        r.mu.Unlock()
+
+        select {
+        case <-entry.done:
+        case <-ctx.Done():
+            return nil, nil, ctx.Err()
+        }
+        if entry.product != nil {
+            return entry.product, nil, nil
+        }
+        // The first create failed and released its claim; try to claim again.
+    }
+}
+
+func (r *recentCreates) finish(fingerprint string, entry *recentCreate, product *Product) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    entry.product = product
+    if product == nil && r.entries[fingerprint] == entry {
+        delete(r.entries, fingerprint)
+    }
+    close(entry.done)
+}
+
+// store must be called with r.mu held.
+func (r *recentCreates) store(fingerprint string, entry *recentCreate) {
+    // Evict whatever was stored in this slot, unless its fingerprint has
+    // been stored again since in a newer slot.
+    if oldest := r.ring[r.next]; oldest != "" {
+        if old, ok := r.entries[oldest]; ok && old.slot == r.next {
+            delete(r.entries, oldest)
+        }
+    }
+    entry.slot = r.next
+    r.entries[fingerprint] = entry
+    r.ring[r.next] = fingerprint
+    r.next = (r.next + 1) % len(r.ring)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+    "context"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+// create claims fingerprint and, if it is not a duplicate, "inserts" a
+// product by counting it in rows.
+func create(t *testing.T, r *recentCreates, fingerprint string, rows *atomic.Int64) (*Product, bool) {
+    t.Helper()
+    existing, finish, err := r.claim(context.Background(), fingerprint)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if existing != nil {
+        return existing, true
+    }
+    product := &Product{}
+    product.ID = uint(rows.Add(1))
+    finish(product)
+    return product, false
+}
+
+func TestRecentCreatesDuplicateWithinWindow(t *testing.T) {
+    r := newRecentCreates(time.Second)
+    fingerprint := productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299)
+    var rows atomic.Int64
+
+    first, _ := create(t, r, fingerprint, &rows)
+    time.Sleep(time.Millisecond)
+    second, duplicate := create(t, r, fingerprint, &rows)
+
+    if !duplicate || second != first {
+        t.Errorf("second create 1ms later: duplicate = %v, product %d; want the first product %d", duplicate, second.ID, first.ID)
+    }
+    if n := rows.Load(); n != 1 {
+        t.Errorf("%d rows inserted, want 1", n)
+    }
+}
+
+func TestRecentCreatesAfterWindow(t *testing.T) {
+    // A 10s gap against the default 5s window, scaled down.
+    r := newRecentCreates(50 * time.Millisecond)
+    fingerprint := productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299)
+    var rows atomic.Int64
+
+    create(t, r, fingerprint, &rows)
+    time.Sleep(100 * time.Millisecond)
+    if _, duplicate := create(t, r, fingerprint, &rows); duplicate {
+        t.Error("create after the window was treated as a duplicate")
+    }
+    if n := rows.Load(); n != 2 {
+        t.Errorf("%d rows inserted, want 2", n)
+    }
+}
+
+func TestRecentCreatesSeparatesCallers(t *testing.T) {
+    r := newRecentCreates(time.Second)
+    var rows atomic.Int64
+
+    create(t, r, productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299), &rows)
+    if _, duplicate := create(t, r, productFingerprint("globex", "key:a6b5c4d3e2f1", "Mug", "", "", "", 1299), &rows); duplicate {
+        t.Error("another caller's identical product was returned as a duplicate")
+    }
+}
+
+func TestRecentCreatesComparesDescriptions(t *testing.T) {
+    r := newRecentCreates(time.Second)
+    var rows atomic.Int64
+
+    create(t, r, productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "Blue", "", "", 1299), &rows)
+    if _, duplicate := create(t, r, productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "Red", "", "", 1299), &rows); duplicate {
+        t.Error("a product with another description was returned as a duplicate")
+    }
+}
+
+func TestRecentCreatesConcurrent(t *testing.T) {
+    r := newRecentCreates(time.Second)
+    fingerprint := productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299)
+    var rows atomic.Int64
+
+    products := make([]*Product, 20)
+    var wg sync.WaitGroup
+    for i := range products {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            products[i], _ = create(t, r, fingerprint, &rows)
+        }(i)
+    }
+    wg.Wait()
+
+    if n := rows.Load(); n != 1 {
+        t.Fatalf("%d rows inserted by concurrent creates, want 1", n)
+    }
+    for i, product := range products {
+        if product != products[0] {
+            t.Errorf("create %d got product %d, want %d", i, product.ID, products[0].ID)
+        }
+    }
+}
+
+func TestRecentCreatesRetriesFailedCreate(t *testing.T) {
+    r := newRecentCreates(time.Second)
+    fingerprint := productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299)
+
+    _, finish, err := r.claim(context.Background(), fingerprint)
+    if err != nil {
+        t.Fatal(err)
+    }
+    finish(nil)
+
+    existing, finish, err := r.claim(context.Background(), fingerprint)
+    if err != nil || existing != nil || finish == nil {
+        t.Fatalf("claim after a failed create = %v, %v; want a new claim", existing, err)
+    }
+}
+
+func TestRecentCreatesDisabled(t *testing.T) {
+    r := newRecentCreates(0)
+    fingerprint := productFingerprint("acme", "key:1f2e3d4c5b6a", "Mug", "", "", "", 1299)
+    var rows atomic.Int64
+
+    create(t, r, fingerprint, &rows)
+    if _, duplicate := create(t, r, fingerprint, &rows); duplicate {
+        t.Error("DEDUP_WINDOW=0 still deduplicated")
+    }
+}
+
+func TestCreateProductDoubleSubmitInsertsOnce(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, recent: newRecentCreates(time.Second)}
+    // The mock fails a second insert.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    req := &pb.CreateProductRequest{Name: "Mug", Price: 12.99}
+    first, err := s.CreateProduct(context.Background(), req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    second, err := (&serverV2{core: s}).CreateProduct(context.Background(), &pbv2.CreateProductRequest{Name: "Mug", PriceCents: 1299})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if first.Duplicate || !second.Duplicate {
+        t.Errorf("duplicate = %v then %v, want false then true", first.Duplicate, second.Duplicate)
+    }
+    if second.Product.Name != "Mug" || second.Product.PriceCents != 1299 {
+        t.Errorf("v2 returned %q at %d cents, want the product created through v1", second.Product.Name, second.Product.PriceCents)
+    }
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf8"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// Values of Product.ReviewStatus. Only approved products are listed or
+// searched; pending and rejected ones can still be read by id.
+const (
+    reviewStatusPending  = "pending"
+    reviewStatusApproved = "approved"
+    reviewStatusRejected = "rejected"
+)
+
+var reviewStatuses = map[string]pb.ReviewStatus{
+    reviewStatusPending:  pb.ReviewStatus_REVIEW_STATUS_PENDING,
+    reviewStatusApproved: pb.ReviewStatus_REVIEW_STATUS_APPROVED,
+    reviewStatusRejected: pb.ReviewStatus_REVIEW_STATUS_REJECTED,
+}
+
+// maxReviewerNotesLength caps reviewer_notes, in characters.
+const maxReviewerNotesLength = 2000
+
+func reviewStatusName(reviewStatus pb.ReviewStatus) (string, bool) {
+    for name, value := range reviewStatuses {
+        if value == reviewStatus {
+            return name, true
+        }
+    }
+    return "", false
+}
+
+// mayReview reports whether the caller may review products and see those
+// awaiting review. Only moderators and admins may; anonymous callers may not
+// unless ANONYMOUS_MODERATOR grants them the role.
+func mayReview(ctx context.Context) bool {
+    r, ok := roleFromContext(ctx)
+    return ok && r >= roleModerator
+}
+
+// initialReviewStatus is the review status of a product the caller creates.
+// Products from callers who could approve them anyway skip the review.
+func initialReviewStatus(ctx context.Context) string {
+    if mayReview(ctx) {
+        return reviewStatusApproved
+    }
+    return reviewStatusPending
+}
+
+// reviewedProducts leaves out products that have not been approved, unless
+// includePending is set.
+func reviewedProducts(query *gorm.DB, includePending bool) *gorm.DB {
+    if includePending {
+        return query
+    }
+    return query.Where("review_status = ?", reviewStatusApproved)
+}
+
+// ReviewProduct approves or rejects a product, or returns it to pending. A
+// change records a PRODUCT_REVIEWED event in the outbox and, once it
+// commits, is sent to the review webhook for the vendor that created the
+// product.
+func (s *server) ReviewProduct(ctx context.Context, req *pb.ReviewProductRequest) (*pb.ReviewProductResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    reviewStatus, ok := reviewStatusName(req.Status)
+    if !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported review status %v", req.Status)
+    }
+    notes := strings.TrimSpace(req.ReviewerNotes)
+    if utf8.RuneCountInString(notes) > maxReviewerNotesLength {
+        return nil, status.Errorf(codes.InvalidArgument, "reviewer_notes exceeds %d characters", maxReviewerNotesLength)
+    }
+
+    var product Product
+    changed := false
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        if product.ReviewStatus == reviewStatus {
+            return nil
+        }
+        previous := product.ReviewStatus
+        if err := tx.Model(&product).Update("review_status", reviewStatus).Error; err != nil {
+            return err
+        }
+        changed = true
+        if err := recordAudit(ctx, tx, "review", "product", product.ID, map[string]string{
+            "old_status":     previous,
+            "new_status":     reviewStatus,
+            "reviewer_notes": notes,
+        }); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_REVIEWED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    if changed && s.reviewWebhook != nil {
+        go s.reviewWebhook.notify(&product, notes)
+    }
+    return &pb.ReviewProductResponse{Product: product.toProto()}, nil
+}
+
+// reviewNotifier posts the outcome of each review to a webhook, which routes
+// it to the vendor named by the vendor field.
+type reviewNotifier struct {
+    url    string
+    client *http.Client
+}
+
+func newReviewNotifier(url string) *reviewNotifier {
+    return &reviewNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// notify sends a product.review.<status> event. Failures are logged and not
+// retried.
+func (n *reviewNotifier) notify(product *Product, notes string) {
+    body, err := json.Marshal(map[string]string{
+        "event":          "product.review." + product.ReviewStatus,
+        "product_id":     strconv.FormatUint(uint64(product.ID), 10),
+        "product_name":   product.Name,
+        "vendor":         product.CreatedBy,
+        "reviewer_notes": notes,
+    })
+    if err != nil {
+        log.Printf("Failed to encode review notification for product %d: %v", product.ID, err)
+        return
+    }
+    res, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+    if err != nil {
+        log.Printf("Failed to send review notification for product %d: %v", product.ID, err)
+        return
+    }
+    res.Body.Close()
+    if res.StatusCode >= 300 {
+        log.Printf("Failed to send review notification for product %d: %s", product.ID, res.Status)
+    }
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// roleContexts are callers of each role, and an anonymous one.
+var roleContexts = map[string]context.Context{
+    "anonymous": context.Background(),
+    "readonly":  context.WithValue(context.Background(), roleContextKey{}, roleReadOnly),
+    "readwrite": context.WithValue(context.Background(), roleContextKey{}, roleReadWrite),
+    "moderator": context.WithValue(context.Background(), roleContextKey{}, roleModerator),
+    "admin":     context.WithValue(context.Background(), roleContextKey{}, roleAdmin),
+}
+
+func TestNewProductsAwaitReviewUnlessCreatedByModerators(t *testing.T) {
+    want := map[string]string{
+        "anonymous": reviewStatusPending,
+        "readonly":  reviewStatusPending,
+        "readwrite": reviewStatusPending,
+        "moderator": reviewStatusApproved,
+        "admin":     reviewStatusApproved,
+    }
+    for name, ctx := range roleContexts {
+        if got := initialReviewStatus(ctx); got != want[name] {
+            t.Errorf("%s creates products %s, want %s", name, got, want[name])
+        }
+    }
+}
+
+func TestListProductsVisibilityByRole(t *testing.T) {
+    for name, ctx := range roleContexts {
+        db, mock := newMockDB(t)
+        s := &server{db: db}
+
+        // Everyone is listed approved products only by default.
+        mock.ExpectQuery(`SELECT \* FROM "products" WHERE status = \$1 AND review_status = \$2 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 11`).
+            WithArgs(productStatusActive, reviewStatusApproved).
+            WillReturnRows(statusRow(1, "Mug", productStatusActive))
+        if _, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 10}); err != nil {
+            t.Fatalf("%s: %v", name, err)
+        }
+
+        // Only moderators and admins may include pending products.
+        if !mayReview(ctx) {
+            _, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 10, IncludePending: true})
+            if status.Code(err) != codes.PermissionDenied {
+                t.Errorf("%s listing pending products: %v, want PermissionDenied", name, err)
+            }
+            continue
+        }
+        mock.ExpectQuery(`SELECT \* FROM "products" WHERE status = \$1 AND "products"."deleted_at" IS NULL ORDER BY id LIMIT 11`).
+            WithArgs(productStatusActive).
+            WillReturnRows(statusRow(1, "Mug", productStatusActive).AddRow(2, "Kettle", 40, productStatusActive, time.Now(), time.Now()))
+        res, err := s.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 10, IncludePending: true})
+        if err != nil {
+            t.Fatalf("%s: %v", name, err)
+        }
+        if len(res.Products) != 2 {
+            t.Errorf("%s listed %d products with pending ones, want 2", name, len(res.Products))
+        }
+    }
+}
+
+func TestReviewProductRecordsEventAndNotifiesVendor(t *testing.T) {
+    notifications := make(chan map[string]string, 1)
+    webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var body map[string]string
+        json.NewDecoder(r.Body).Decode(&body)
+        notifications <- body
+    }))
+    t.Cleanup(webhook.Close)
+
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1 .* FOR UPDATE`).WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "review_status", "created_by"}).AddRow(7, "Mug", 12.5, reviewStatusPending, "key:abc"))
+    mock.ExpectExec(`UPDATE "products" SET "review_status"=\$1`).WithArgs(reviewStatusApproved, sqlmock.AnyArg(), 7).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    expectAudit(mock, "review", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int32(pb.ProductEventType_PRODUCT_REVIEWED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    s := &server{db: db, reviewWebhook: newReviewNotifier(webhook.URL)}
+    res, err := s.ReviewProduct(roleContexts["moderator"], &pb.ReviewProductRequest{
+        ProductId:     "7",
+        Status:        pb.ReviewStatus_REVIEW_STATUS_APPROVED,
+        ReviewerNotes: " Looks good ",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Product.ReviewStatus != pb.ReviewStatus_REVIEW_STATUS_APPROVED {
+        t.Errorf("review status = %v, want approved", res.Product.ReviewStatus)
+    }
+
+    select {
+    case got := <-notifications:
+        if got["event"] != "product.review.approved" || got["vendor"] != "key:abc" || got["reviewer_notes"] != "Looks good" {
+            t.Errorf("notification = %v", got)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("the vendor was not notified")
+    }
+}
+
+func TestReviewProductWithoutChangeRecordsNothing(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "review_status"}).AddRow(7, reviewStatusRejected))
+    mock.ExpectCommit()
+
+    _, err := (&server{db: db}).ReviewProduct(roleContexts["moderator"], &pb.ReviewProductRequest{ProductId: "7", Status: pb.ReviewStatus_REVIEW_STATUS_REJECTED})
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestReviewProductRejectsBadRequests(t *testing.T) {
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for name, req := range map[string]*pb.ReviewProductRequest{
+        "bad id":         {ProductId: "mug", Status: pb.ReviewStatus_REVIEW_STATUS_APPROVED},
+        "unknown status": {ProductId: "7", Status: 42},
+    } {
+        if _, err := s.ReviewProduct(roleContexts["moderator"], req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestCalculateCartTotalRejectsPendingProducts(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "status", "review_status"}).AddRow(42, "Mug", 12.5, productStatusActive, reviewStatusPending))
+    mock.ExpectRollback()
+
+    _, err := (&server{db: db}).CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items: []*pb.CartItem{{ProductId: "42", Quantity: 1}},
+    })
+    if status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("err = %v, want FailedPrecondition", err)
+    }
+}
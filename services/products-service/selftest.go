@@ -0,0 +1,155 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+    "sync"
+    "time"
+
+    consulapi "github.com/hashicorp/consul/api"
+    "github.com/redis/go-redis/v9"
+    "google.golang.org/protobuf/types/known/durationpb"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// selfTestTimeout bounds a whole self-test run.
+const selfTestTimeout = 5 * time.Second
+
+// SelfTestProbe is written and read back by the self-test. Rows never
+// outlive the run because its transaction is always rolled back.
+type SelfTestProbe struct {
+    ID        uint `gorm:"primarykey"`
+    CreatedAt time.Time
+}
+
+var errSelfTestRollback = errors.New("self-test rollback")
+
+// selfTester checks that the instance can reach every dependency it needs to
+// do work, and remembers the last result for /readyz.
+type selfTester struct {
+    db     *gorm.DB
+    consul *consulapi.Client
+    // redis is nil when REDIS_ADDR is not set, and the check is skipped.
+    redis *redis.Client
+    // degradation, if set, is told the outcome of each periodic run.
+    degradation *degradationReporter
+
+    mu   sync.Mutex
+    last *pb.SelfTestResponse
+}
+
+type selfTestServer struct {
+    pb.UnimplementedSelfTestServiceServer
+    tester *selfTester
+}
+
+func (s *selfTestServer) SelfTest(ctx context.Context, req *pb.SelfTestRequest) (*pb.SelfTestResponse, error) {
+    return s.tester.run(ctx), nil
+}
+
+func (t *selfTester) run(ctx context.Context) *pb.SelfTestResponse {
+    ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+    defer cancel()
+
+    res := &pb.SelfTestResponse{Ok: true}
+    check := func(name string, fn func(context.Context) error) {
+        start := time.Now()
+        err := fn(ctx)
+        result := &pb.SelfTestCheck{Name: name, Ok: err == nil, Latency: durationpb.New(time.Since(start))}
+        if err != nil {
+            result.Error = err.Error()
+            res.Ok = false
+        }
+        res.Checks = append(res.Checks, result)
+    }
+
+    check("database", t.checkDatabase)
+    check("consul", t.checkConsul)
+    if t.redis != nil {
+        check("redis", func(ctx context.Context) error {
+            return t.redis.Ping(ctx).Err()
+        })
+    }
+    res.CompletedAt = timestamppb.Now()
+
+    t.mu.Lock()
+    t.last = res
+    t.mu.Unlock()
+    return res
+}
+
+// checkDatabase inserts, reads back and deletes a probe row inside a
+// transaction that is then rolled back.
+func (t *selfTester) checkDatabase(ctx context.Context) error {
+    err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        probe := SelfTestProbe{}
+        if err := tx.Create(&probe).Error; err != nil {
+            return err
+        }
+        if err := tx.First(&SelfTestProbe{}, probe.ID).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&probe).Error; err != nil {
+            return err
+        }
+        return errSelfTestRollback
+    })
+    if errors.Is(err, errSelfTestRollback) {
+        return nil
+    }
+    return err
+}
+
+func (t *selfTester) checkConsul(ctx context.Context) error {
+    // The Consul agent API takes no context, so bound the call here.
+    done := make(chan error, 1)
+    go func() {
+        _, err := t.consul.Agent().Self()
+        done <- err
+    }()
+    select {
+    case err := <-done:
+        return err
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// runPeriodically runs the self-test every interval in the background.
+func (t *selfTester) runPeriodically(interval time.Duration) {
+    go func() {
+        for {
+            res := t.run(context.Background())
+            if !res.Ok {
+                log.Printf("Self-test failed: %v", res.Checks)
+            }
+            if t.degradation != nil {
+                t.degradation.update(t.degradation.reasons(res))
+            }
+            time.Sleep(interval)
+        }
+    }()
+}
+
+// readyzHandler reports the last periodic self-test result.
+func (t *selfTester) readyzHandler(w http.ResponseWriter, r *http.Request) {
+    t.mu.Lock()
+    last := t.last
+    t.mu.Unlock()
+
+    if last == nil {
+        http.Error(w, "self-test has not run yet", http.StatusServiceUnavailable)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    if !last.Ok {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }
+    json.NewEncoder(w).Encode(last)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    consulapi "github.com/hashicorp/consul/api"
+    "github.com/redis/go-redis/v9"
+)
+
+// serveRedisPings answers PING on a local port, and every other command with
+// an error, which is enough for a go-redis client to connect and ping.
+func serveRedisPings(t *testing.T) string {
+    t.Helper()
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { lis.Close() })
+    go func() {
+        for {
+            conn, err := lis.Accept()
+            if err != nil {
+                return
+            }
+            go func() {
+                defer conn.Close()
+                r := bufio.NewReader(conn)
+                for {
+                    command, err := readRESPCommand(r)
+                    if err != nil {
+                        return
+                    }
+                    reply := "-ERR unknown command\r\n"
+                    if strings.EqualFold(command, "ping") {
+                        reply = "+PONG\r\n"
+                    }
+                    if _, err := conn.Write([]byte(reply)); err != nil {
+                        return
+                    }
+                }
+            }()
+        }
+    }()
+    return lis.Addr().String()
+}
+
+// readRESPCommand reads one RESP array of bulk strings and returns its first
+// element.
+func readRESPCommand(r *bufio.Reader) (string, error) {
+    var n int
+    if _, err := fmt.Fscanf(r, "*%d\r\n", &n); err != nil {
+        return "", err
+    }
+    var command string
+    for i := 0; i < n; i++ {
+        var size int
+        if _, err := fmt.Fscanf(r, "$%d\r\n", &size); err != nil {
+            return "", err
+        }
+        arg := make([]byte, size+2)
+        if _, err := io.ReadFull(r, arg); err != nil {
+            return "", err
+        }
+        if i == 0 {
+            command = string(arg[:size])
+        }
+    }
+    return command, nil
+}
+
+// consulAgent serves the Consul agent's /v1/agent/self, failing when broken
+// is set.
+func consulAgent(t *testing.T, broken bool) *consulapi.Client {
+    t.Helper()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if broken || r.URL.Path != "/v1/agent/self" {
+            http.Error(w, "agent unavailable", http.StatusInternalServerError)
+            return
+        }
+        w.Write([]byte(`{}`))
+    }))
+    t.Cleanup(srv.Close)
+    client, err := consulapi.NewClient(&consulapi.Config{Address: srv.URL})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return client
+}
+
+func TestSelfTestReportsEachBrokenDependency(t *testing.T) {
+    for _, broken := range []string{"", "database", "consul", "redis"} {
+        t.Run("broken="+broken, func(t *testing.T) {
+            db, mock := newMockDB(t)
+            if broken == "database" {
+                mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+            } else {
+                mock.ExpectBegin()
+                mock.ExpectQuery(`INSERT INTO "self_test_probes"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+                mock.ExpectQuery(`SELECT \* FROM "self_test_probes"`).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, nil))
+                mock.ExpectExec(`DELETE FROM "self_test_probes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+                // The probe must never be committed.
+                mock.ExpectRollback()
+            }
+
+            redisAddr := serveRedisPings(t)
+            if broken == "redis" {
+                // Nothing listens on a closed listener's port.
+                lis, err := net.Listen("tcp", "127.0.0.1:0")
+                if err != nil {
+                    t.Fatal(err)
+                }
+                redisAddr = lis.Addr().String()
+                lis.Close()
+            }
+            rdb := redis.NewClient(&redis.Options{Addr: redisAddr, MaxRetries: -1})
+            t.Cleanup(func() { rdb.Close() })
+
+            tester := &selfTester{db: db, consul: consulAgent(t, broken == "consul"), redis: rdb}
+            res := tester.run(context.Background())
+
+            if res.Ok != (broken == "") {
+                t.Errorf("ok = %v with %q broken", res.Ok, broken)
+            }
+            if len(res.Checks) != 3 {
+                t.Fatalf("got %d checks, want database, consul and redis", len(res.Checks))
+            }
+            for _, check := range res.Checks {
+                if wantOk := check.Name != broken; check.Ok != wantOk || (check.Error == "") != wantOk {
+                    t.Errorf("%s: ok = %v, error = %q, want ok = %v", check.Name, check.Ok, check.Error, wantOk)
+                }
+            }
+
+            rec := httptest.NewRecorder()
+            tester.readyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+            want := http.StatusOK
+            if broken != "" {
+                want = http.StatusServiceUnavailable
+            }
+            if rec.Code != want {
+                t.Errorf("/readyz = %d, want %d", rec.Code, want)
+            }
+        })
+    }
+}
+
+func TestReadyzBeforeFirstSelfTest(t *testing.T) {
+    rec := httptest.NewRecorder()
+    (&selfTester{}).readyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+    if rec.Code != http.StatusServiceUnavailable {
+        t.Errorf("/readyz = %d before any run, want 503", rec.Code)
+    }
+}
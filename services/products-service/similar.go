@@ -0,0 +1,141 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "strconv"
+    "time"
+
+    "github.com/pgvector/pgvector-go"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    defaultSimilarProductsLimit = 10
+    maxSimilarProductsLimit     = 100
+    // maxEmbeddingDimensions is pgvector's limit for the vector type.
+    maxEmbeddingDimensions = 16000
+)
+
+// ProductEmbedding is a product's attribute vector, pushed by the ML
+// pipelines. The column has no fixed dimension so the model can change;
+// only vectors of the same dimension are compared.
+type ProductEmbedding struct {
+    ProductID uint            `gorm:"primaryKey;autoIncrement:false"`
+    Embedding pgvector.Vector `gorm:"type:vector;not null"`
+    UpdatedAt time.Time
+}
+
+// enableVectorExtension must run before ProductEmbedding is migrated. The
+// database needs pgvector installed, e.g. the pgvector/pgvector image.
+func enableVectorExtension(db *gorm.DB) error {
+    if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS vector`).Error; err != nil {
+        return fmt.Errorf("enable pgvector: %w", err)
+    }
+    return nil
+}
+
+func (s *server) UpsertProductEmbedding(ctx context.Context, req *pb.UpsertProductEmbeddingRequest) (*pb.UpsertProductEmbeddingResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    if len(req.Vector) == 0 || len(req.Vector) > maxEmbeddingDimensions {
+        return nil, status.Errorf(codes.InvalidArgument, "vector must have between 1 and %d dimensions", maxEmbeddingDimensions)
+    }
+    zero := true
+    for _, v := range req.Vector {
+        if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+            return nil, status.Error(codes.InvalidArgument, "vector must not contain NaN or infinite values")
+        }
+        zero = zero && v == 0
+    }
+    if zero {
+        // Cosine distance is undefined for the zero vector.
+        return nil, status.Error(codes.InvalidArgument, "vector must not be all zeros")
+    }
+
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.First(&Product{}, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        embedding := ProductEmbedding{ProductID: uint(productID), Embedding: pgvector.NewVector(req.Vector)}
+        return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&embedding).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UpsertProductEmbeddingResponse{}, nil
+}
+
+type similarProduct struct {
+    Product
+    Similarity float64
+}
+
+// GetSimilarProducts returns the active products whose embeddings are
+// closest to the given product's by cosine distance, most similar first.
+func (s *server) GetSimilarProducts(ctx context.Context, req *pb.GetSimilarProductsRequest) (*pb.GetSimilarProductsResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    limit := int(req.Limit)
+    switch {
+    case limit < 0:
+        return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+    case limit == 0:
+        limit = defaultSimilarProductsLimit
+    case limit > maxSimilarProductsLimit:
+        limit = maxSimilarProductsLimit
+    }
+    if req.MinSimilarity < -1 || req.MinSimilarity > 1 {
+        return nil, status.Error(codes.InvalidArgument, "min_similarity must be between -1 and 1")
+    }
+
+    var found int64
+    if err := s.db.WithContext(ctx).Model(&ProductEmbedding{}).Where("product_id = ?", productID).Count(&found).Error; err != nil {
+        return nil, err
+    }
+    if found == 0 {
+        return nil, status.Errorf(codes.NotFound, "product %s has no embedding", req.ProductId)
+    }
+
+    // The target vector stays in SQL: the query cache stores results as JSON,
+    // which pgvector.Vector does not survive.
+    var similar []similarProduct
+    err = s.db.WithContext(ctx).Raw(`
+        WITH target AS (SELECT embedding FROM product_embeddings WHERE product_id = ?)
+        SELECT products.*, 1 - (e.embedding <=> target.embedding) AS similarity
+        FROM product_embeddings e
+        CROSS JOIN target
+        JOIN products ON products.id = e.product_id
+        WHERE e.product_id <> ?
+            AND vector_dims(e.embedding) = vector_dims(target.embedding)
+            AND products.deleted_at IS NULL
+            AND products.status = ?
+            AND products.review_status = ?
+            AND 1 - (e.embedding <=> target.embedding) >= ?
+        ORDER BY e.embedding <=> target.embedding
+        LIMIT ?`,
+        productID, productID, productStatusActive, reviewStatusApproved, req.MinSimilarity, limit).Scan(&similar).Error
+    if err != nil {
+        return nil, err
+    }
+
+    res := &pb.GetSimilarProductsResponse{Products: make([]*pb.SimilarProduct, len(similar))}
+    for i := range similar {
+        res.Products[i] = &pb.SimilarProduct{Product: similar[i].toProto(), Similarity: similar[i].Similarity}
+    }
+    return res, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+    "context"
+    "math"
+    "slices"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestUpsertProductEmbeddingRejectsBadVectors(t *testing.T) {
+    // Validation happens before the database is touched.
+    s := &server{}
+    nan := float32(math.NaN())
+    inf := float32(math.Inf(1))
+    for name, req := range map[string]*pb.UpsertProductEmbeddingRequest{
+        "bad id":    {ProductId: "mug", Vector: []float32{1}},
+        "empty":     {ProductId: "1"},
+        "too long":  {ProductId: "1", Vector: make([]float32, maxEmbeddingDimensions+1)},
+        "NaN":       {ProductId: "1", Vector: []float32{1, nan}},
+        "infinite":  {ProductId: "1", Vector: []float32{inf, 0}},
+        "all zeros": {ProductId: "1", Vector: []float32{0, 0, 0}},
+    } {
+        if _, err := s.UpsertProductEmbedding(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: UpsertProductEmbedding = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestGetSimilarProductsRejectsBadRequests(t *testing.T) {
+    s := &server{}
+    for _, req := range []*pb.GetSimilarProductsRequest{
+        {ProductId: "mug"},
+        {ProductId: "1", Limit: -1},
+        {ProductId: "1", MinSimilarity: 1.5},
+        {ProductId: "1", MinSimilarity: -2},
+    } {
+        if _, err := s.GetSimilarProducts(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("GetSimilarProducts(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+// TestGetSimilarProductsWithToyVectors ranks 2-dimensional embeddings whose
+// cosine similarities to the target are known: 1, about 0.99, 0 and -1.
+func TestGetSimilarProductsWithToyVectors(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := enableVectorExtension(db); err != nil {
+        t.Skipf("pgvector is not installed: %v", err)
+    }
+    if err := db.AutoMigrate(&Product{}, &ProductEmbedding{}); err != nil {
+        t.Fatal(err)
+    }
+    ctx := context.Background()
+    s := &server{db: db}
+
+    ids := make(map[string]string)
+    for _, p := range []struct {
+        name   string
+        status string
+        vector []float32
+    }{
+        {"Target", productStatusActive, []float32{1, 0}},
+        {"Twin", productStatusActive, []float32{2, 0}},
+        {"Close", productStatusActive, []float32{0.9, 0.1}},
+        {"Orthogonal", productStatusActive, []float32{0, 1}},
+        {"Opposite", productStatusActive, []float32{-1, 0}},
+        // Neither of these may ever be returned.
+        {"Archived", productStatusArchived, []float32{1, 0}},
+        {"OtherModel", productStatusActive, []float32{1, 0, 0}},
+    } {
+        product := Product{Name: p.name, Price: 1, Status: p.status}
+        if err := db.Create(&product).Error; err != nil {
+            t.Fatal(err)
+        }
+        ids[p.name] = product.toProto().Id
+        if _, err := s.UpsertProductEmbedding(ctx, &pb.UpsertProductEmbeddingRequest{ProductId: ids[p.name], Vector: p.vector}); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    similar := func(req *pb.GetSimilarProductsRequest) []string {
+        t.Helper()
+        req.ProductId = ids["Target"]
+        res, err := s.GetSimilarProducts(ctx, req)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var names []string
+        for _, p := range res.Products {
+            names = append(names, p.Product.Name)
+        }
+        return names
+    }
+    for _, tt := range []struct {
+        req  *pb.GetSimilarProductsRequest
+        want []string
+    }{
+        {&pb.GetSimilarProductsRequest{MinSimilarity: -1}, []string{"Twin", "Close", "Orthogonal", "Opposite"}},
+        {&pb.GetSimilarProductsRequest{MinSimilarity: -1, Limit: 2}, []string{"Twin", "Close"}},
+        {&pb.GetSimilarProductsRequest{MinSimilarity: 0.5}, []string{"Twin", "Close"}},
+        {&pb.GetSimilarProductsRequest{}, []string{"Twin", "Close", "Orthogonal"}},
+    } {
+        if got := similar(tt.req); !slices.Equal(got, tt.want) {
+            t.Errorf("GetSimilarProducts(%v) = %v, want %v", tt.req, got, tt.want)
+        }
+    }
+
+    // Upserting replaces the vector: Opposite moves to about 0.71.
+    if _, err := s.UpsertProductEmbedding(ctx, &pb.UpsertProductEmbeddingRequest{ProductId: ids["Opposite"], Vector: []float32{1, 1}}); err != nil {
+        t.Fatal(err)
+    }
+    if got, want := similar(&pb.GetSimilarProductsRequest{MinSimilarity: 0.5}), []string{"Twin", "Close", "Opposite"}; !slices.Equal(got, want) {
+        t.Errorf("after the upsert got %v, want %v", got, want)
+    }
+
+    if _, err := s.GetSimilarProducts(ctx, &pb.GetSimilarProductsRequest{ProductId: "999"}); status.Code(err) != codes.NotFound {
+        t.Errorf("product without embedding = %v, want NotFound", err)
+    }
+    if _, err := s.UpsertProductEmbedding(ctx, &pb.UpsertProductEmbeddingRequest{ProductId: "999", Vector: []float32{1}}); status.Code(err) != codes.NotFound {
+        t.Errorf("embedding for a missing product = %v, want NotFound", err)
+    }
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "net"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/pgvector/pgvector-go"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+func serveSnapshots(t *testing.T, s *snapshotServer) pb.SnapshotServiceClient {
+    t.Helper()
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    srv := grpc.NewServer()
+    pb.RegisterSnapshotServiceServer(srv, s)
+    go srv.Serve(lis)
+    t.Cleanup(srv.Stop)
+
+    conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return pb.NewSnapshotServiceClient(conn)
+}
+
+func takeSnapshot(t *testing.T, client pb.SnapshotServiceClient) []byte {
+    t.Helper()
+    stream, err := client.SnapshotData(context.Background(), &pb.SnapshotDataRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var data bytes.Buffer
+    for {
+        chunk, err := stream.Recv()
+        if err == io.EOF {
+            return data.Bytes()
+        }
+        if err != nil {
+            t.Fatal(err)
+        }
+        data.Write(chunk.Data)
+    }
+}
+
+func restoreSnapshot(t *testing.T, client pb.SnapshotServiceClient, data []byte) (*pb.RestoreDataResponse, error) {
+    t.Helper()
+    stream, err := client.RestoreData(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    for len(data) > 0 {
+        n := min(len(data), 100)
+        if err := stream.Send(&pb.SnapshotChunk{Data: data[:n]}); err != nil {
+            break
+        }
+        data = data[n:]
+    }
+    return stream.CloseAndRecv()
+}
+
+// dumpTables reads every row of the snapshot tables, in id order where
+// there is one.
+func dumpTables(t *testing.T, db *gorm.DB) map[string][]map[string]interface{} {
+    t.Helper()
+    dump := make(map[string][]map[string]interface{})
+    for _, table := range snapshotTables {
+        order := "id"
+        switch table {
+        case "product_tags":
+            order = "product_id, tag_id"
+        case "product_embeddings":
+            order = "product_id"
+        }
+        var rows []map[string]interface{}
+        if err := db.Table(table).Order(order).Find(&rows).Error; err != nil {
+            t.Fatal(err)
+        }
+        dump[table] = rows
+    }
+    return dump
+}
+
+func TestRestoreDataIsDisabledByDefault(t *testing.T) {
+    client := serveSnapshots(t, &snapshotServer{})
+    _, err := restoreSnapshot(t, client, []byte("not a snapshot"))
+    if status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("RestoreData without ALLOW_RESTORE = %v, want FailedPrecondition", err)
+    }
+}
+
+// TestSnapshotRoundTrip snapshots seeded data, wipes the tables, restores
+// the snapshot and diffs the result against the original rows.
+func TestSnapshotRoundTrip(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := enableVectorExtension(db); err != nil {
+        t.Skipf("pgvector is not installed: %v", err)
+    }
+    if err := db.AutoMigrate(&Product{}, &DiscountCode{}, &PriceAlert{}, &Tag{}, &ProductTag{}, &ProductEmbedding{}, &ProductFAQ{}, &ProductVersion{}, &ProductCost{}, &PurchaseLimit{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateTagBitmaps(db); err != nil {
+        t.Fatal(err)
+    }
+    ctx := context.Background()
+    s := &server{db: db}
+    var mugID string
+    for _, p := range []Product{{Name: "Mug", Price: 12.5}, {Name: "Kettle", Price: 40}} {
+        if err := db.Create(&p).Error; err != nil {
+            t.Fatal(err)
+        }
+        if mugID == "" {
+            mugID = p.toProto().Id
+        }
+    }
+    if _, err := s.SetProductTags(ctx, &pb.SetProductTagsRequest{ProductId: mugID, Tags: []string{"sale", "featured"}}); err != nil {
+        t.Fatal(err)
+    }
+    seed := []interface{}{
+        &DiscountCode{Code: "SAVE10", Type: "percent", Value: 10, MaxUses: 5},
+        &PriceAlert{UserID: "ada", ProductID: 1, TargetPrice: 10},
+        &ProductEmbedding{ProductID: 1, Embedding: pgvector.NewVector([]float32{0.5, -1, 2})},
+        &ProductFAQ{ProductID: 1, Question: "Is it dishwasher safe?", Answer: "Yes.", CreatedBy: "key:abc"},
+        &ProductVersion{ProductID: 1, Major: 1, Version: "1.0.0", Name: "Mug", Price: 12.5, Snapshot: `{"id":"1","name":"Mug"}`, CreatedBy: "key:abc"},
+        &ProductCost{ProductID: 1, SupplierCostCents: 700, Currency: "USD", EffectiveFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), CreatedBy: "key:abc"},
+        &PurchaseLimit{ProductID: 1, MaxQuantityPerUser: 1, WindowHours: 24},
+    }
+    for _, row := range seed {
+        if err := db.Create(row).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+    before := dumpTables(t, db)
+
+    client := serveSnapshots(t, &snapshotServer{db: db, allowRestore: true})
+    data := takeSnapshot(t, client)
+    if err := db.Exec("TRUNCATE products, discount_codes, price_alerts, tags, product_tags, product_embeddings, product_faqs, product_versions, product_costs, purchase_limits").Error; err != nil {
+        t.Fatal(err)
+    }
+    res, err := restoreSnapshot(t, client, data)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]int64{"products": 2, "discount_codes": 1, "price_alerts": 1, "tags": 2, "product_tags": 2, "product_embeddings": 1, "product_faqs": 1, "product_versions": 1, "product_costs": 1, "purchase_limits": 1}
+    if !reflect.DeepEqual(res.Rows, want) {
+        t.Errorf("restored %v rows, want %v", res.Rows, want)
+    }
+    if after := dumpTables(t, db); !reflect.DeepEqual(after, before) {
+        t.Errorf("restored data differs:\n got %v\nwant %v", after, before)
+    }
+
+    // The id sequences were moved past the restored rows.
+    product := Product{Name: "Teapot", Price: 20}
+    if err := db.Create(&product).Error; err != nil {
+        t.Fatal(err)
+    }
+    if product.ID <= 2 {
+        t.Errorf("new product got id %d after restoring ids 1 and 2", product.ID)
+    }
+
+    // A corrupt snapshot is refused and changes nothing.
+    before = dumpTables(t, db)
+    if _, err := restoreSnapshot(t, client, data[:len(data)/2]); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("restoring a truncated snapshot = %v, want InvalidArgument", err)
+    }
+    if after := dumpTables(t, db); !reflect.DeepEqual(after, before) {
+        t.Error("a failed restore changed the data")
+    }
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestGetProductRejectsNonNumericIDs(t *testing.T) {
+    // No statement is expected: the id never reaches GORM.
+    db, _ := newMockDB(t)
+    for _, id := range []string{"1 OR 1=1", "1; DROP TABLE products", "", "-1"} {
+        if _, err := (&server{db: db}).GetProduct(context.Background(), &pb.GetProductRequest{Id: id}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("GetProduct(%q): %v, want InvalidArgument", id, err)
+        }
+    }
+}
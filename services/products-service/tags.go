@@ -0,0 +1,230 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+    "time"
+    "unicode"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// defaultTagBitmapRefreshInterval is used when TAG_BITMAP_REFRESH_INTERVAL
+// is not set.
+const defaultTagBitmapRefreshInterval = 30 * time.Second
+
+type Tag struct {
+    ID   uint   `gorm:"primarykey"`
+    Name string `gorm:"not null"`
+    Slug string `gorm:"uniqueIndex;not null"`
+}
+
+func (t *Tag) toProto() *pb.Tag {
+    return &pb.Tag{Id: fmt.Sprint(t.ID), Name: t.Name, Slug: t.Slug}
+}
+
+type ProductTag struct {
+    ProductID uint `gorm:"primaryKey"`
+    TagID     uint `gorm:"primaryKey;index"`
+}
+
+// migrateTagBitmaps creates product_tag_bitmaps, which holds each product's
+// tag ids as a sorted int[] so tag searches are a single indexed array
+// comparison instead of a join. It is rebuilt from product_tags by
+// refreshTagBitmaps, so searches can lag tag changes by up to one refresh
+// interval.
+func migrateTagBitmaps(db *gorm.DB) error {
+    statements := []string{
+        `CREATE EXTENSION IF NOT EXISTS intarray`,
+        `CREATE TABLE IF NOT EXISTS product_tag_bitmaps (
+            product_id bigint PRIMARY KEY,
+            tag_ids integer[] NOT NULL
+        )`,
+        `CREATE INDEX IF NOT EXISTS idx_product_tag_bitmaps_tag_ids
+            ON product_tag_bitmaps USING gin (tag_ids gin__int_ops)`,
+    }
+    for _, statement := range statements {
+        if err := db.Exec(statement).Error; err != nil {
+            return fmt.Errorf("migrate product_tag_bitmaps: %w", err)
+        }
+    }
+    return nil
+}
+
+// refreshTagBitmaps rebuilds product_tag_bitmaps in one transaction, so
+// searches see either the old or the new contents.
+func refreshTagBitmaps(ctx context.Context, db *gorm.DB) error {
+    return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec(`DELETE FROM product_tag_bitmaps`).Error; err != nil {
+            return err
+        }
+        return tx.Exec(`
+            INSERT INTO product_tag_bitmaps (product_id, tag_ids)
+            SELECT product_id, sort(array_agg(tag_id)::integer[])
+            FROM product_tags
+            GROUP BY product_id`).Error
+    })
+}
+
+// startTagBitmapRefresher refreshes product_tag_bitmaps every interval.
+func startTagBitmapRefresher(db *gorm.DB, interval time.Duration) {
+    go func() {
+        for {
+            if err := refreshTagBitmaps(context.Background(), db); err != nil {
+                log.Printf("Failed to refresh tag bitmaps: %v", err)
+            }
+            time.Sleep(interval)
+        }
+    }()
+}
+
+// slugify lowercases name and joins its words with hyphens, so "New
+// Arrival" and "new-arrival" name the same tag.
+func slugify(name string) string {
+    var b strings.Builder
+    hyphen := false
+    for _, r := range strings.ToLower(name) {
+        if unicode.IsLetter(r) || unicode.IsDigit(r) {
+            if hyphen && b.Len() > 0 {
+                b.WriteByte('-')
+            }
+            b.WriteRune(r)
+            hyphen = false
+        } else {
+            hyphen = true
+        }
+    }
+    return b.String()
+}
+
+// SetProductTags replaces a product's tags, creating tags that do not exist
+// yet.
+func (s *server) SetProductTags(ctx context.Context, req *pb.SetProductTagsRequest) (*pb.SetProductTagsResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    tags := make([]Tag, 0, len(req.Tags))
+    seen := make(map[string]bool, len(req.Tags))
+    for _, name := range req.Tags {
+        slug := slugify(name)
+        if slug == "" {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid tag %q", name)
+        }
+        if !seen[slug] {
+            seen[slug] = true
+            tags = append(tags, Tag{Name: strings.TrimSpace(name), Slug: slug})
+        }
+    }
+
+    err = s.inTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.First(&Product{}, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+            }
+            return err
+        }
+        if err := tx.Where("product_id = ?", productID).Delete(&ProductTag{}).Error; err != nil {
+            return err
+        }
+        for i := range tags {
+            // An existing tag keeps its name, so read the stored row back.
+            err := tx.Clauses(clause.OnConflict{
+                Columns:   []clause.Column{{Name: "slug"}},
+                DoUpdates: clause.Assignments(map[string]interface{}{"slug": gorm.Expr("EXCLUDED.slug")}),
+            }).Create(&tags[i]).Error
+            if err != nil {
+                return err
+            }
+            if err := tx.Where("id = ?", tags[i].ID).First(&tags[i]).Error; err != nil {
+                return err
+            }
+            if err := tx.Create(&ProductTag{ProductID: uint(productID), TagID: tags[i].ID}).Error; err != nil {
+                return err
+            }
+        }
+        slugs := make([]string, len(tags))
+        for i := range tags {
+            slugs[i] = tags[i].Slug
+        }
+        return recordAudit(ctx, tx, "update", "product", productID, map[string][]string{"tags": slugs})
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    res := &pb.SetProductTagsResponse{Tags: make([]*pb.Tag, len(tags))}
+    for i := range tags {
+        res.Tags[i] = tags[i].toProto()
+    }
+    return res, nil
+}
+
+// SearchProductsByTags returns the products carrying all (AND) or any (OR) of
+// the given tags, using the intarray @> and && operators on
+// product_tag_bitmaps. Only active products are returned unless
+// include_archived is set.
+func (s *server) SearchProductsByTags(ctx context.Context, req *pb.SearchProductsByTagsRequest) (*pb.ListProductsResponse, error) {
+    if len(req.Tags) == 0 {
+        return nil, status.Error(codes.InvalidArgument, "at least one tag is required")
+    }
+    slugs := make([]string, len(req.Tags))
+    for i, name := range req.Tags {
+        slugs[i] = slugify(name)
+    }
+
+    var tagIDs []uint
+    if err := s.db.WithContext(ctx).Model(&Tag{}).Where("slug IN ?", slugs).Distinct().Pluck("id", &tagIDs).Error; err != nil {
+        return nil, err
+    }
+    res := &pb.ListProductsResponse{}
+    operator := "&&"
+    if req.Operator == pb.TagOperator_TAG_OPERATOR_AND {
+        operator = "@>"
+        if len(tagIDs) < len(distinct(slugs)) {
+            // A tag that does not exist matches no product.
+            return res, nil
+        }
+    }
+    if len(tagIDs) == 0 {
+        return res, nil
+    }
+
+    matching := s.db.Table("product_tag_bitmaps").Select("product_id").
+        Where("tag_ids "+operator+" ?::integer[]", intArrayLiteral(tagIDs))
+    var products []Product
+    if err := reviewedProducts(visibleProducts(s.db.WithContext(ctx), req.IncludeArchived), false).Where("id IN (?)", matching).Order("id").Find(&products).Error; err != nil {
+        return nil, err
+    }
+    res.Products = make([]*pb.Product, len(products))
+    for i := range products {
+        res.Products[i] = products[i].toProto()
+    }
+    return res, nil
+}
+
+func distinct(values []string) map[string]bool {
+    set := make(map[string]bool, len(values))
+    for _, value := range values {
+        set[value] = true
+    }
+    return set
+}
+
+// intArrayLiteral formats ids as a Postgres array literal, e.g. "{1,2}".
+func intArrayLiteral(ids []uint) string {
+    parts := make([]string, len(ids))
+    for i, id := range ids {
+        parts[i] = strconv.FormatUint(uint64(id), 10)
+    }
+    return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+    "context"
+    "reflect"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+func TestSlugify(t *testing.T) {
+    tests := map[string]string{
+        "sale":           "sale",
+        "New Arrival":    "new-arrival",
+        "  new-arrival ": "new-arrival",
+        "Café_Crème!":    "café-crème",
+        "--":             "",
+    }
+    for name, want := range tests {
+        if got := slugify(name); got != want {
+            t.Errorf("slugify(%q) = %q, want %q", name, got, want)
+        }
+    }
+}
+
+func TestSearchProductsByTagsOperators(t *testing.T) {
+    for _, tt := range []struct {
+        operator pb.TagOperator
+        sql      string
+    }{
+        {pb.TagOperator_TAG_OPERATOR_AND, `tag_ids @> \$3::integer\[\]`},
+        {pb.TagOperator_TAG_OPERATOR_OR, `tag_ids && \$3::integer\[\]`},
+    } {
+        db, mock := newMockDB(t)
+        mock.ExpectQuery(`SELECT DISTINCT "id" FROM "tags" WHERE slug IN \(\$1,\$2\)`).
+            WithArgs("sale", "new-arrival").
+            WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+        mock.ExpectQuery(`SELECT \* FROM "products" WHERE status = \$1 AND review_status = \$2 AND id IN \(SELECT product_id FROM "product_tag_bitmaps" WHERE `+tt.sql).
+            WithArgs(productStatusActive, reviewStatusApproved, "{1,2}").
+            WillReturnRows(productRow(42, "Mug", 12.5))
+
+        res, err := (&server{db: db}).SearchProductsByTags(context.Background(), &pb.SearchProductsByTagsRequest{Tags: []string{"sale", "New Arrival"}, Operator: tt.operator})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(res.Products) != 1 || res.Products[0].Id != "42" {
+            t.Errorf("%v: got %v, want product 42", tt.operator, res.Products)
+        }
+    }
+}
+
+func TestSearchProductsByTagsWithUnknownTag(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT DISTINCT "id" FROM "tags"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+    // No product carries a tag that does not exist, so AND matches nothing
+    // without querying products.
+    res, err := (&server{db: db}).SearchProductsByTags(context.Background(), &pb.SearchProductsByTagsRequest{Tags: []string{"sale", "no-such-tag"}, Operator: pb.TagOperator_TAG_OPERATOR_AND})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Products) != 0 {
+        t.Errorf("got %d products, want none", len(res.Products))
+    }
+}
+
+// TestSearchProductsByTagsSemantics runs AND and OR searches against
+// PostgreSQL, which needs the intarray extension.
+func TestSearchProductsByTagsSemantics(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &Tag{}, &ProductTag{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateTagBitmaps(db); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db}
+    ctx := context.Background()
+
+    tagged := map[string][]string{
+        "Mug":    {"sale", "featured"},
+        "Teapot": {"sale"},
+        "Kettle": {"new-arrival", "featured"},
+        "Spoon":  nil,
+    }
+    ids := map[string]string{}
+    for _, name := range []string{"Mug", "Teapot", "Kettle", "Spoon"} {
+        product := Product{Name: name, Price: 10}
+        if err := db.Create(&product).Error; err != nil {
+            t.Fatal(err)
+        }
+        ids[product.toProto().Id] = name
+        if _, err := s.SetProductTags(ctx, &pb.SetProductTagsRequest{ProductId: product.toProto().Id, Tags: tagged[name]}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := refreshTagBitmaps(ctx, db); err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        operator pb.TagOperator
+        tags     []string
+        want     []string
+    }{
+        {pb.TagOperator_TAG_OPERATOR_AND, []string{"sale"}, []string{"Mug", "Teapot"}},
+        {pb.TagOperator_TAG_OPERATOR_AND, []string{"sale", "featured"}, []string{"Mug"}},
+        {pb.TagOperator_TAG_OPERATOR_AND, []string{"sale", "new-arrival"}, nil},
+        {pb.TagOperator_TAG_OPERATOR_AND, []string{"sale", "clearance"}, nil},
+        {pb.TagOperator_TAG_OPERATOR_OR, []string{"sale", "new-arrival"}, []string{"Mug", "Teapot", "Kettle"}},
+        {pb.TagOperator_TAG_OPERATOR_OR, []string{"featured", "clearance"}, []string{"Mug", "Kettle"}},
+        {pb.TagOperator_TAG_OPERATOR_OR, []string{"clearance"}, nil},
+    }
+    for _, tt := range tests {
+        res, err := s.SearchProductsByTags(ctx, &pb.SearchProductsByTagsRequest{Tags: tt.tags, Operator: tt.operator})
+        if err != nil {
+            t.Fatal(err)
+        }
+        var got []string
+        for _, product := range res.Products {
+            got = append(got, ids[product.Id])
+        }
+        if !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("%v %v = %v, want %v", tt.operator, tt.tags, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    defaultTagSimilarProductsLimit = 10
+    maxTagSimilarProductsLimit     = 100
+    // tagSimilarCacheTTL is how long GetTagSimilarProducts serves a result
+    // before asking the database again. Tag changes already take up to a
+    // bitmap refresh interval to show.
+    tagSimilarCacheTTL = 5 * time.Minute
+)
+
+// tagSimilarProductsSQL scores every product sharing a tag with the target
+// by the Jaccard similarity of their tag sets: the number of shared tags
+// over the number of tags either has. It reads product_tag_bitmaps, so the
+// intarray & and | operators give the intersection and union, # counts
+// them, and the && overlap test uses the GIN index. Its parameters are the
+// product id twice, the product status, the review status and the limit.
+const tagSimilarProductsSQL = `
+    WITH target AS (
+        SELECT tag_ids FROM product_tag_bitmaps WHERE product_id = ?
+    ), scored AS (
+        SELECT b.product_id,
+            #(b.tag_ids & target.tag_ids)::float8 / #(b.tag_ids | target.tag_ids) AS jaccard_score
+        FROM product_tag_bitmaps b
+        CROSS JOIN target
+        WHERE b.product_id <> ? AND b.tag_ids && target.tag_ids
+    )
+    SELECT products.*, scored.jaccard_score
+    FROM scored
+    JOIN products ON products.id = scored.product_id
+    WHERE products.deleted_at IS NULL
+        AND products.status = ?
+        AND products.review_status = ?
+    ORDER BY scored.jaccard_score DESC, products.id
+    LIMIT ?`
+
+// tagSimilarProductsQuery returns the query for the limit active products
+// most similar to productID by tags, most similar first.
+func tagSimilarProductsQuery(db *gorm.DB, productID uint64, limit int) *gorm.DB {
+    return db.Raw(tagSimilarProductsSQL, productID, productID, productStatusActive, reviewStatusApproved, limit)
+}
+
+type tagSimilarProduct struct {
+    Product
+    JaccardScore float64
+}
+
+// GetTagSimilarProducts returns the active products that share the most of
+// the given product's tags, relative to how many tags the two have between
+// them. Unlike GetSimilarProducts it needs no embeddings; a product without
+// tags has no similar products.
+func (s *server) GetTagSimilarProducts(ctx context.Context, req *pb.GetTagSimilarProductsRequest) (*pb.GetTagSimilarProductsResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    limit := int(req.Limit)
+    switch {
+    case limit < 0:
+        return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+    case limit == 0:
+        limit = defaultTagSimilarProductsLimit
+    case limit > maxTagSimilarProductsLimit:
+        limit = maxTagSimilarProductsLimit
+    }
+
+    cacheKey := fmt.Sprintf("tag-similar:%d:%d", productID, limit)
+    if data, ok := s.tagSimilar.Get(ctx, cacheKey); ok {
+        var res pb.GetTagSimilarProductsResponse
+        if err := proto.Unmarshal(data, &res); err == nil {
+            return &res, nil
+        }
+    }
+
+    db := s.db.WithContext(ctx)
+    if err := db.Select("id").First(&Product{}, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return nil, err
+    }
+    var similar []tagSimilarProduct
+    if err := tagSimilarProductsQuery(db, productID, limit).Scan(&similar).Error; err != nil {
+        return nil, err
+    }
+
+    res := &pb.GetTagSimilarProductsResponse{Products: make([]*pb.ScoredProduct, len(similar))}
+    for i := range similar {
+        res.Products[i] = &pb.ScoredProduct{Product: similar[i].toProto(), JaccardScore: similar[i].JaccardScore}
+    }
+    if data, err := proto.Marshal(res); err == nil {
+        s.tagSimilar.Set(ctx, cacheKey, data, tagSimilarCacheTTL)
+    }
+    return res, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+    "context"
+    "regexp"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestTagSimilarProductsQuery(t *testing.T) {
+    db, _ := newMockDB(t)
+    got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+        var similar []tagSimilarProduct
+        return tagSimilarProductsQuery(tx, 7, 25).Scan(&similar)
+    })
+    want := `
+    WITH target AS (
+        SELECT tag_ids FROM product_tag_bitmaps WHERE product_id = 7
+    ), scored AS (
+        SELECT b.product_id,
+            #(b.tag_ids & target.tag_ids)::float8 / #(b.tag_ids | target.tag_ids) AS jaccard_score
+        FROM product_tag_bitmaps b
+        CROSS JOIN target
+        WHERE b.product_id <> 7 AND b.tag_ids && target.tag_ids
+    )
+    SELECT products.*, scored.jaccard_score
+    FROM scored
+    JOIN products ON products.id = scored.product_id
+    WHERE products.deleted_at IS NULL
+        AND products.status = 'active'
+        AND products.review_status = 'approved'
+    ORDER BY scored.jaccard_score DESC, products.id
+    LIMIT 25`
+    if got != want {
+        t.Errorf("query =%s\nwant%s", got, want)
+    }
+}
+
+func TestTagSimilarProductsQueryIsParameterized(t *testing.T) {
+    db, _ := newMockDB(t)
+    stmt := tagSimilarProductsQuery(db.Session(&gorm.Session{DryRun: true}), 7, 25).Statement
+    if n := strings.Count(stmt.SQL.String(), "$"); n != 5 {
+        t.Errorf("query has %d placeholders, want 5:\n%s", n, stmt.SQL.String())
+    }
+    want := []interface{}{uint64(7), uint64(7), productStatusActive, reviewStatusApproved, 25}
+    if len(stmt.Vars) != len(want) {
+        t.Fatalf("vars = %v, want %v", stmt.Vars, want)
+    }
+    for i := range want {
+        if stmt.Vars[i] != want[i] {
+            t.Errorf("var %d = %#v, want %#v", i, stmt.Vars[i], want[i])
+        }
+    }
+}
+
+func expectTagSimilar(mock sqlmock.Sqlmock, limit int) {
+    mock.ExpectQuery(`SELECT "id" FROM "products" WHERE "products"."id" = \$1`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    mock.ExpectQuery(regexp.QuoteMeta("#(b.tag_ids & target.tag_ids)::float8 / #(b.tag_ids | target.tag_ids)")).
+        WithArgs(7, 7, productStatusActive, reviewStatusApproved, limit).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "jaccard_score"}).
+            AddRow(9, "Teapot", 1.0).
+            AddRow(3, "Kettle", 2.0/3))
+}
+
+func TestGetTagSimilarProducts(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, tagSimilar: NewMemoryCache(time.Hour)}
+    expectTagSimilar(mock, defaultTagSimilarProductsLimit)
+
+    res, err := s.GetTagSimilarProducts(context.Background(), &pb.GetTagSimilarProductsRequest{ProductId: "7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Products) != 2 ||
+        res.Products[0].Product.Name != "Teapot" || res.Products[0].JaccardScore != 1 ||
+        res.Products[1].Product.Name != "Kettle" || res.Products[1].JaccardScore != 2.0/3 {
+        t.Errorf("GetTagSimilarProducts = %v, want Teapot 1 then Kettle 2/3", res.Products)
+    }
+
+    // The response is cached, so the database is not asked again.
+    cached, err := s.GetTagSimilarProducts(context.Background(), &pb.GetTagSimilarProductsRequest{ProductId: "7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(cached.Products) != 2 || cached.Products[1].JaccardScore != 2.0/3 {
+        t.Errorf("cached GetTagSimilarProducts = %v", cached.Products)
+    }
+}
+
+func TestGetTagSimilarProductsLimit(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, tagSimilar: NewMemoryCache(time.Hour)}
+    expectTagSimilar(mock, maxTagSimilarProductsLimit)
+    if _, err := s.GetTagSimilarProducts(context.Background(), &pb.GetTagSimilarProductsRequest{ProductId: "7", Limit: 1000}); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, req := range []*pb.GetTagSimilarProductsRequest{{ProductId: "x"}, {ProductId: "7", Limit: -1}} {
+        if _, err := s.GetTagSimilarProducts(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%v: err = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestGetTagSimilarProductsNotFound(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, tagSimilar: NewMemoryCache(time.Hour)}
+    mock.ExpectQuery(`SELECT "id" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    if _, err := s.GetTagSimilarProducts(context.Background(), &pb.GetTagSimilarProductsRequest{ProductId: "7"}); status.Code(err) != codes.NotFound {
+        t.Errorf("err = %v, want NotFound", err)
+    }
+}
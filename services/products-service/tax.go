@@ -0,0 +1,206 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/redis/go-redis/v9"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+const (
+    // wildcardTaxCategory is the ProductCategory of rules that apply to
+    // every product.
+    wildcardTaxCategory = "*"
+    // exemptTaxName is reported when no rule applies.
+    exemptTaxName   = "exempt"
+    taxRuleCacheTTL = 24 * time.Hour
+)
+
+// TaxRuleSet is the tax rate of a country, or of a region of it when
+// RegionCode is set. ProductCategory is the slug of the tag the rule applies
+// to, or * for every product. TaxRate is a fraction, e.g. 0.2 for 20%.
+type TaxRuleSet struct {
+    ID              uint    `gorm:"primaryKey"`
+    CountryCode     string  `gorm:"type:char(2);not null;uniqueIndex:idx_tax_rule_sets_rule,priority:1"`
+    RegionCode      string  `gorm:"not null;default:'';uniqueIndex:idx_tax_rule_sets_rule,priority:2"`
+    ProductCategory string  `gorm:"not null;default:'*';uniqueIndex:idx_tax_rule_sets_rule,priority:3"`
+    TaxRate         float64 `gorm:"not null"`
+    TaxName         string  `gorm:"not null"`
+}
+
+// defaultTaxRules are seeded at startup by seedTaxRules.
+var defaultTaxRules = []TaxRuleSet{
+    {CountryCode: "US", RegionCode: "CA", ProductCategory: "*", TaxRate: 0.0725, TaxName: "California sales tax"},
+    {CountryCode: "US", RegionCode: "NY", ProductCategory: "*", TaxRate: 0.04, TaxName: "New York sales tax"},
+    {CountryCode: "US", RegionCode: "TX", ProductCategory: "*", TaxRate: 0.0625, TaxName: "Texas sales tax"},
+    {CountryCode: "CA", ProductCategory: "*", TaxRate: 0.05, TaxName: "GST"},
+    {CountryCode: "CA", RegionCode: "ON", ProductCategory: "*", TaxRate: 0.13, TaxName: "HST"},
+    {CountryCode: "CA", RegionCode: "QC", ProductCategory: "*", TaxRate: 0.14975, TaxName: "GST + QST"},
+    {CountryCode: "GB", ProductCategory: "*", TaxRate: 0.20, TaxName: "VAT"},
+    {CountryCode: "GB", ProductCategory: "books", TaxRate: 0, TaxName: "VAT (zero-rated)"},
+    {CountryCode: "DE", ProductCategory: "*", TaxRate: 0.19, TaxName: "MwSt"},
+    {CountryCode: "DE", ProductCategory: "books", TaxRate: 0.07, TaxName: "MwSt (reduced)"},
+    {CountryCode: "FR", ProductCategory: "*", TaxRate: 0.20, TaxName: "TVA"},
+    {CountryCode: "FR", ProductCategory: "books", TaxRate: 0.055, TaxName: "TVA (reduced)"},
+    {CountryCode: "IN", ProductCategory: "*", TaxRate: 0.18, TaxName: "GST"},
+    {CountryCode: "AU", ProductCategory: "*", TaxRate: 0.10, TaxName: "GST"},
+}
+
+// seedTaxRules inserts the default tax rules that are missing. Rules that
+// already exist are left as they are, so edits made in the database survive
+// a restart. It runs right after the table is migrated, so CalculateTax never
+// sees an empty rule set.
+func seedTaxRules(db *gorm.DB) error {
+    rules := append([]TaxRuleSet(nil), defaultTaxRules...)
+    return db.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "country_code"}, {Name: "region_code"}, {Name: "product_category"}},
+        DoNothing: true,
+    }).Create(&rules).Error
+}
+
+// taxRule is what CalculateTax caches for a product and jurisdiction.
+type taxRule struct {
+    Rate float64 `json:"rate"`
+    Name string  `json:"name"`
+}
+
+// resolveTaxRule returns the most specific of a country's rules that applies
+// in region to a product tagged with categories, or nil if none does. A rule
+// for the region beats one for the whole country, and within either a rule
+// for one of the categories beats a wildcard. Equally specific rules are
+// decided by the order of rules.
+func resolveTaxRule(rules []TaxRuleSet, region string, categories []string) *TaxRuleSet {
+    tagged := make(map[string]bool, len(categories))
+    for _, category := range categories {
+        tagged[category] = true
+    }
+    var best *TaxRuleSet
+    bestScore := -1
+    for i := range rules {
+        rule := &rules[i]
+        score := 0
+        if rule.RegionCode != "" {
+            if rule.RegionCode != region {
+                continue
+            }
+            score += 2
+        }
+        if rule.ProductCategory != wildcardTaxCategory {
+            if !tagged[rule.ProductCategory] {
+                continue
+            }
+            score++
+        }
+        if score > bestScore {
+            best, bestScore = rule, score
+        }
+    }
+    return best
+}
+
+// jurisdiction normalizes a country and region code, reporting
+// InvalidArgument for a country that is not an ISO 3166-1 alpha-2 code.
+func jurisdiction(countryCode, regionCode string) (country, region string, err error) {
+    country = strings.ToUpper(strings.TrimSpace(countryCode))
+    if len(country) != 2 {
+        return "", "", status.Errorf(codes.InvalidArgument, "country_code %q is not an ISO 3166-1 alpha-2 code", countryCode)
+    }
+    return country, strings.ToUpper(strings.TrimSpace(regionCode)), nil
+}
+
+// CalculateTax returns the tax on quantity of a product bought in a country
+// and, optionally, a region of it. Prices are stored without tax, so the
+// result is never inclusive. The rule for a product and jurisdiction is
+// cached for a day, so rule and tag changes can take that long to apply.
+func (s *server) CalculateTax(ctx context.Context, req *pb.CalculateTaxRequest) (*pb.CalculateTaxResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    if req.Quantity <= 0 {
+        return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+    }
+    country, region, err := jurisdiction(req.CountryCode, req.RegionCode)
+    if err != nil {
+        return nil, err
+    }
+
+    db := s.db.WithContext(ctx)
+    var product Product
+    if err := db.First(&product, productID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.ProductId)
+        }
+        return nil, err
+    }
+    rule, err := s.taxRuleFor(ctx, db, product.ID, country, region)
+    if err != nil {
+        return nil, err
+    }
+    return &pb.CalculateTaxResponse{
+        TaxAmount: money(product.Price * float64(req.Quantity) * rule.Rate),
+        TaxRate:   rule.Rate,
+        TaxName:   rule.Name,
+        Inclusive: false,
+    }, nil
+}
+
+// taxRuleFor resolves the rule for a product in a jurisdiction, through the
+// Redis cache when it is configured.
+func (s *server) taxRuleFor(ctx context.Context, db *gorm.DB, productID uint, country, region string) (taxRule, error) {
+    cacheKey := fmt.Sprintf("tax:%d:%s:%s", productID, country, region)
+    if s.redis != nil {
+        data, err := s.redis.Get(ctx, cacheKey).Bytes()
+        if err == nil {
+            var rule taxRule
+            if err := json.Unmarshal(data, &rule); err == nil {
+                return rule, nil
+            }
+        } else if !errors.Is(err, redis.Nil) {
+            log.Printf("Failed to read tax rule cache: %v", err)
+        }
+    }
+
+    var rules []TaxRuleSet
+    if err := db.Where("country_code = ?", country).Order("id").Find(&rules).Error; err != nil {
+        return taxRule{}, err
+    }
+    // A country without any rules is most likely missing from the table
+    // rather than tax-free, so the exempt answer is not cached: a rule added
+    // for it applies at once instead of a day later.
+    if len(rules) == 0 {
+        return taxRule{Name: exemptTaxName}, nil
+    }
+    var categories []string
+    err := db.Model(&Tag{}).
+        Joins("JOIN product_tags ON product_tags.tag_id = tags.id").
+        Where("product_tags.product_id = ?", productID).
+        Pluck("tags.slug", &categories).Error
+    if err != nil {
+        return taxRule{}, err
+    }
+    rule := taxRule{Name: exemptTaxName}
+    if match := resolveTaxRule(rules, region, categories); match != nil {
+        rule = taxRule{Rate: match.TaxRate, Name: match.TaxName}
+    }
+
+    if s.redis != nil {
+        data, _ := json.Marshal(rule)
+        if err := s.redis.Set(ctx, cacheKey, data, taxRuleCacheTTL).Err(); err != nil {
+            log.Printf("Failed to cache tax rule: %v", err)
+        }
+    }
+    return rule, nil
+}
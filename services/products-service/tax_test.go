@@ -0,0 +1,270 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/alicebob/miniredis/v2"
+    "github.com/redis/go-redis/v9"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+)
+
+func TestResolveTaxRule(t *testing.T) {
+    // All rules are for one country, as CalculateTax loads them.
+    country := TaxRuleSet{ID: 1, ProductCategory: "*", TaxRate: 0.05, TaxName: "country"}
+    countryBooks := TaxRuleSet{ID: 2, ProductCategory: "books", TaxRate: 0.01, TaxName: "country books"}
+    region := TaxRuleSet{ID: 3, RegionCode: "ON", ProductCategory: "*", TaxRate: 0.13, TaxName: "region"}
+    regionBooks := TaxRuleSet{ID: 4, RegionCode: "ON", ProductCategory: "books", TaxRate: 0.08, TaxName: "region books"}
+    otherRegion := TaxRuleSet{ID: 5, RegionCode: "QC", ProductCategory: "*", TaxRate: 0.15, TaxName: "other region"}
+    countryToys := TaxRuleSet{ID: 6, ProductCategory: "toys", TaxRate: 0.02, TaxName: "country toys"}
+
+    tests := []struct {
+        name       string
+        rules      []TaxRuleSet
+        region     string
+        categories []string
+        want       string
+    }{
+        {"no rules", nil, "ON", []string{"books"}, ""},
+        {"country wildcard", []TaxRuleSet{country}, "", nil, "country"},
+        {"country wildcard applies in any region", []TaxRuleSet{country}, "ON", nil, "country"},
+        {"region beats country", []TaxRuleSet{country, region}, "ON", nil, "region"},
+        {"rule for another region is ignored", []TaxRuleSet{country, otherRegion}, "ON", nil, "country"},
+        {"region rule needs the region", []TaxRuleSet{region}, "", nil, ""},
+        {"category beats wildcard", []TaxRuleSet{country, countryBooks}, "", []string{"books"}, "country books"},
+        {"category rule needs the tag", []TaxRuleSet{country, countryBooks}, "", []string{"toys"}, "country"},
+        {"region wildcard beats country category", []TaxRuleSet{countryBooks, region}, "ON", []string{"books"}, "region"},
+        {"region category beats everything", []TaxRuleSet{country, countryBooks, region, regionBooks, otherRegion}, "ON", []string{"books"}, "region books"},
+        {"only category rules and no tags", []TaxRuleSet{countryBooks}, "", nil, ""},
+        {"equally specific rules keep their order", []TaxRuleSet{countryToys, countryBooks}, "", []string{"books", "toys"}, "country toys"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := resolveTaxRule(tt.rules, tt.region, tt.categories)
+            name := ""
+            if got != nil {
+                name = got.TaxName
+            }
+            if name != tt.want {
+                t.Errorf("resolveTaxRule = %q, want %q", name, tt.want)
+            }
+        })
+    }
+}
+
+// seededTaxRules returns the default rules for country as CalculateTax
+// loads them.
+func seededTaxRules(country string) *sqlmock.Rows {
+    rows := sqlmock.NewRows([]string{"id", "country_code", "region_code", "product_category", "tax_rate", "tax_name"})
+    for i, rule := range defaultTaxRules {
+        if rule.CountryCode == country {
+            rows.AddRow(i+1, rule.CountryCode, rule.RegionCode, rule.ProductCategory, rule.TaxRate, rule.TaxName)
+        }
+    }
+    return rows
+}
+
+// expectTaxLookup expects the seeded rules for country to be loaded and,
+// if there are any, the tags of product 7.
+func expectTaxLookup(mock sqlmock.Sqlmock, country string, tags ...string) {
+    mock.ExpectQuery(`SELECT \* FROM "tax_rule_sets" WHERE country_code = \$1 ORDER BY id`).
+        WithArgs(country).
+        WillReturnRows(seededTaxRules(country))
+    seeded := false
+    for _, rule := range defaultTaxRules {
+        seeded = seeded || rule.CountryCode == country
+    }
+    if !seeded {
+        return
+    }
+    slugs := sqlmock.NewRows([]string{"slug"})
+    for _, tag := range tags {
+        slugs.AddRow(tag)
+    }
+    mock.ExpectQuery(`SELECT "tags"."slug" FROM "tags" JOIN product_tags ON product_tags.tag_id = tags.id WHERE product_tags.product_id = \$1`).
+        WithArgs(7).
+        WillReturnRows(slugs)
+}
+
+// TestCalculateTaxPerJurisdiction prices a $12 product bought three at a
+// time under the seeded rules.
+func TestCalculateTaxPerJurisdiction(t *testing.T) {
+    tests := []struct {
+        country, region string
+        tags            []string
+        amount, rate    float64
+        name            string
+    }{
+        {"us", "ca", nil, 2.61, 0.0725, "California sales tax"},
+        {"US", "NY", nil, 1.44, 0.04, "New York sales tax"},
+        // US has only state rules, so a state without one is exempt.
+        {"US", "OR", nil, 0, 0, exemptTaxName},
+        {"CA", "ON", nil, 4.68, 0.13, "HST"},
+        {"CA", "QC", nil, 5.39, 0.14975, "GST + QST"},
+        {"CA", "BC", nil, 1.8, 0.05, "GST"},
+        {"CA", "", nil, 1.8, 0.05, "GST"},
+        {"GB", "", []string{"gifts"}, 7.2, 0.2, "VAT"},
+        {"GB", "", []string{"books"}, 0, 0, "VAT (zero-rated)"},
+        {"DE", "", []string{"books", "sale"}, 2.52, 0.07, "MwSt (reduced)"},
+        {"FR", "", []string{"books"}, 1.98, 0.055, "TVA (reduced)"},
+        {"IN", "", nil, 6.48, 0.18, "GST"},
+        {"AU", "NSW", nil, 3.6, 0.1, "GST"},
+    }
+    for _, tt := range tests {
+        db, mock := newMockDB(t)
+        mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Novel", 12))
+        expectTaxLookup(mock, strings.ToUpper(tt.country), tt.tags...)
+
+        res, err := (&server{db: db}).CalculateTax(context.Background(), &pb.CalculateTaxRequest{ProductId: "7", Quantity: 3, CountryCode: tt.country, RegionCode: tt.region})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if res.TaxAmount.Amount != tt.amount || res.TaxRate != tt.rate || res.TaxName != tt.name || res.Inclusive {
+            t.Errorf("%s/%s %v: got %v %v %q, want %v %v %q", tt.country, tt.region, tt.tags, res.TaxAmount.Amount, res.TaxRate, res.TaxName, tt.amount, tt.rate, tt.name)
+        }
+    }
+}
+
+func newTaxCache(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+    t.Helper()
+    m := miniredis.RunT(t)
+    client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+    t.Cleanup(func() { client.Close() })
+    return client, m
+}
+
+func TestCalculateTaxCachesTheRule(t *testing.T) {
+    db, mock := newMockDB(t)
+    cache, m := newTaxCache(t)
+    s := &server{db: db, redis: cache}
+    req := &pb.CalculateTaxRequest{ProductId: "7", Quantity: 2, CountryCode: "GB"}
+
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Novel", 12.5))
+    expectTaxLookup(mock, "GB", "gifts")
+    if _, err := s.CalculateTax(context.Background(), req); err != nil {
+        t.Fatal(err)
+    }
+    if ttl := m.TTL("tax:7:GB:"); ttl != taxRuleCacheTTL {
+        t.Errorf("cached for %v, want %v", ttl, taxRuleCacheTTL)
+    }
+
+    // Only the price is read again, so the amount follows it and the new
+    // quantity.
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Novel", 15))
+    req.Quantity = 1
+    res, err := s.CalculateTax(context.Background(), req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.TaxAmount.Amount != 3 || res.TaxName != "VAT" {
+        t.Errorf("cached CalculateTax = %v, want 3 VAT", res)
+    }
+}
+
+// TestCalculateTaxWithoutRulesIsNotCached checks that an exempt answer for
+// a country missing from the rule set is looked up again every time.
+func TestCalculateTaxWithoutRulesIsNotCached(t *testing.T) {
+    db, mock := newMockDB(t)
+    cache, m := newTaxCache(t)
+    s := &server{db: db, redis: cache}
+    for i := 0; i < 2; i++ {
+        mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Novel", 10))
+        // Without rules for the country the product's tags are not needed.
+        expectTaxLookup(mock, "BT")
+
+        res, err := s.CalculateTax(context.Background(), &pb.CalculateTaxRequest{ProductId: "7", Quantity: 1, CountryCode: "BT"})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if res.TaxAmount.Amount != 0 || res.TaxRate != 0 || res.TaxName != exemptTaxName {
+            t.Errorf("CalculateTax = %v, want exempt", res)
+        }
+    }
+    if keys := m.Keys(); len(keys) != 0 {
+        t.Errorf("cached %v", keys)
+    }
+}
+
+func TestCalculateTaxValidation(t *testing.T) {
+    for _, req := range []*pb.CalculateTaxRequest{
+        {ProductId: "x", Quantity: 1, CountryCode: "US"},
+        {ProductId: "7", CountryCode: "US"},
+        {ProductId: "7", Quantity: 1},
+        {ProductId: "7", Quantity: 1, CountryCode: "USA"},
+    } {
+        db, _ := newMockDB(t)
+        if _, err := (&server{db: db}).CalculateTax(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("CalculateTax(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestCalculateCartTotalAddsTax(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(productRow(7, "Novel", 10))
+    mock.ExpectQuery(`SELECT \* FROM "discount_codes"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type", "value"}).AddRow(1, "HALF", "PERCENTAGE", 50))
+    mock.ExpectExec(`UPDATE "discount_codes"`).WillReturnResult(sqlmock.NewResult(0, 1))
+    expectTaxLookup(mock, "CA")
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items:        []*pb.CartItem{{ProductId: "7", Quantity: 3}},
+        DiscountCode: "HALF",
+        CountryCode:  "CA",
+        RegionCode:   "on",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    // 13% HST on the discounted 15.
+    if res.Subtotal.Amount != 30 || res.DiscountAmount.Amount != 15 || res.TaxAmount.Amount != 1.95 || res.Total.Amount != 16.95 {
+        t.Errorf("subtotal, discount, tax, total = %v, %v, %v, %v, want 30, 15, 1.95, 16.95",
+            res.Subtotal.Amount, res.DiscountAmount.Amount, res.TaxAmount.Amount, res.Total.Amount)
+    }
+
+    if _, err := (&server{db: db}).CalculateCartTotal(context.Background(), &pb.CalculateCartTotalRequest{
+        Items:       []*pb.CartItem{{ProductId: "7", Quantity: 1}},
+        CountryCode: "Canada",
+    }); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("cart in country Canada = %v, want InvalidArgument", err)
+    }
+}
+
+// TestSeedTaxRules checks that seeding fills an empty table and keeps rules
+// edited in the database.
+func TestSeedTaxRules(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&TaxRuleSet{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := seedTaxRules(db); err != nil {
+        t.Fatal(err)
+    }
+    if err := db.Model(&TaxRuleSet{}).Where("country_code = ? AND region_code = ''", "GB").Where("product_category = '*'").Update("tax_rate", 0.175).Error; err != nil {
+        t.Fatal(err)
+    }
+    if err := seedTaxRules(db); err != nil {
+        t.Fatal(err)
+    }
+    var count int64
+    if err := db.Model(&TaxRuleSet{}).Count(&count).Error; err != nil {
+        t.Fatal(err)
+    }
+    if count != int64(len(defaultTaxRules)) {
+        t.Errorf("%d rules after seeding twice, want %d", count, len(defaultTaxRules))
+    }
+    var vat TaxRuleSet
+    if err := db.First(&vat, "country_code = ? AND region_code = '' AND product_category = '*'", "GB").Error; err != nil {
+        t.Fatal(err)
+    }
+    if vat.TaxRate != 0.175 {
+        t.Errorf("GB VAT is %v after reseeding, want the edited 0.175", vat.TaxRate)
+    }
+}
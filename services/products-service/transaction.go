@@ -0,0 +1,229 @@
+package main
+
+import (
+    "context"
+    "log"
+    "sync"
+    "time"
+
+    "github.com/google/uuid"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// transactionHeader is the metadata key naming the client transaction a
+// CreateProduct, UpdateProduct, ArchiveProduct, UnarchiveProduct or
+// BulkUpdateProductStatus call joins.
+const transactionHeader = "x-transaction-id"
+
+// defaultTransactionTimeout is used when TRANSACTION_TIMEOUT is not set and a
+// BeginTransaction request does not set a timeout.
+const defaultTransactionTimeout = 30 * time.Second
+
+// maxTransactionTimeout caps the timeout a BeginTransaction request may ask for.
+const maxTransactionTimeout = 5 * time.Minute
+
+// defaultMaxOpenTransactions is used when MAX_OPEN_TRANSACTIONS is not set.
+const defaultMaxOpenTransactions = 10
+
+// clientTransaction is a database transaction kept open across RPCs. Each
+// open transaction holds a database connection and its locks until it is
+// committed, rolled back or expires. It exists only in this instance, so
+// clients must send every call using it here.
+type clientTransaction struct {
+    id     string
+    tenant string
+
+    // mu serialises the calls using the transaction, which share one
+    // connection.
+    mu    sync.Mutex
+    tx    *gorm.DB
+    timer *time.Timer
+}
+
+// transactionManager tracks open client transactions and rolls back those
+// that are neither committed nor rolled back within their timeout, so a
+// crashed client cannot hold locks forever.
+type transactionManager struct {
+    db             *gorm.DB
+    defaultTimeout time.Duration
+    maxOpen        int
+
+    mu   sync.Mutex
+    open map[string]*clientTransaction
+}
+
+func newTransactionManager(db *gorm.DB, defaultTimeout time.Duration, maxOpen int) *transactionManager {
+    return &transactionManager{db: db, defaultTimeout: defaultTimeout, maxOpen: maxOpen, open: make(map[string]*clientTransaction)}
+}
+
+// begin opens a transaction for the calling tenant. It is not bound to ctx,
+// which ends with the BeginTransaction call.
+func (m *transactionManager) begin(ctx context.Context, timeout time.Duration) (*clientTransaction, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if len(m.open) >= m.maxOpen {
+        return nil, status.Errorf(codes.ResourceExhausted, "too many open transactions (max %d)", m.maxOpen)
+    }
+    tx := m.db.Begin()
+    if tx.Error != nil {
+        return nil, tx.Error
+    }
+    t := &clientTransaction{id: uuid.NewString(), tenant: tenantFromContext(ctx), tx: tx}
+    t.timer = time.AfterFunc(timeout, func() {
+        if m.remove(t.id) == nil {
+            return
+        }
+        log.Printf("Rolling back transaction %s, not finished within %v", t.id, timeout)
+        t.rollback()
+    })
+    m.open[t.id] = t
+    return t, nil
+}
+
+// get returns the caller's open transaction with the given id. Another
+// tenant's transaction is reported as not found.
+func (m *transactionManager) get(ctx context.Context, id string) (*clientTransaction, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    t, ok := m.open[id]
+    if !ok || t.tenant != tenantFromContext(ctx) {
+        return nil, status.Errorf(codes.NotFound, "transaction %s not found; it may have expired or been begun on another instance", id)
+    }
+    return t, nil
+}
+
+// take removes the caller's open transaction with the given id, so nothing
+// else can use it.
+func (m *transactionManager) take(ctx context.Context, id string) (*clientTransaction, error) {
+    t, err := m.get(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    if m.remove(id) == nil {
+        return nil, status.Errorf(codes.NotFound, "transaction %s not found; it may have expired or been begun on another instance", id)
+    }
+    t.timer.Stop()
+    return t, nil
+}
+
+func (m *transactionManager) remove(id string) *clientTransaction {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    t := m.open[id]
+    delete(m.open, id)
+    return t
+}
+
+// run calls fn in a savepoint of the transaction, so a failing call leaves
+// the transaction usable.
+func (t *clientTransaction) run(ctx context.Context, fn func(tx *gorm.DB) error) error {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.tx == nil {
+        return status.Errorf(codes.Aborted, "transaction %s has already finished", t.id)
+    }
+    return t.tx.WithContext(ctx).Transaction(fn)
+}
+
+func (t *clientTransaction) commit() error {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.tx == nil {
+        return status.Errorf(codes.Aborted, "transaction %s has already finished", t.id)
+    }
+    err := t.tx.Commit().Error
+    t.tx = nil
+    return err
+}
+
+func (t *clientTransaction) rollback() error {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.tx == nil {
+        return nil
+    }
+    err := t.tx.Rollback().Error
+    t.tx = nil
+    return err
+}
+
+// transactionID returns the client transaction named in the request's
+// metadata, or "" if there is none.
+func transactionID(ctx context.Context) string {
+    md, _ := metadata.FromIncomingContext(ctx)
+    if values := md.Get(transactionHeader); len(values) > 0 {
+        return values[0]
+    }
+    return ""
+}
+
+// inRequestTransaction runs fn in the client transaction named in the
+// request's metadata, or in a transaction of its own if there is none.
+// Outbox events recorded by fn are relayed once the transaction commits
+// and dropped if it is rolled back.
+func (s *server) inRequestTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+    id := transactionID(ctx)
+    if id == "" {
+        return s.inTransaction(ctx, fn)
+    }
+    t, err := s.transactions.get(ctx, id)
+    if err != nil {
+        return err
+    }
+    return t.run(ctx, fn)
+}
+
+type transactionServer struct {
+    pb.UnimplementedTransactionServiceServer
+    transactions *transactionManager
+}
+
+// BeginTransaction opens a transaction that CreateProduct, UpdateProduct,
+// ArchiveProduct, UnarchiveProduct and BulkUpdateProductStatus join when
+// their metadata carries its id under x-transaction-id. It is rolled back if
+// not finished within the timeout.
+func (s *transactionServer) BeginTransaction(ctx context.Context, req *pb.BeginTransactionRequest) (*pb.BeginTransactionResponse, error) {
+    timeout := s.transactions.defaultTimeout
+    if req.Timeout != nil {
+        if err := req.Timeout.CheckValid(); err != nil || req.Timeout.AsDuration() <= 0 {
+            return nil, status.Error(codes.InvalidArgument, "timeout must be a positive duration")
+        }
+        if req.Timeout.AsDuration() > maxTransactionTimeout {
+            return nil, status.Errorf(codes.InvalidArgument, "timeout must be at most %v", maxTransactionTimeout)
+        }
+        timeout = req.Timeout.AsDuration()
+    }
+    t, err := s.transactions.begin(ctx, timeout)
+    if err != nil {
+        return nil, err
+    }
+    return &pb.BeginTransactionResponse{TransactionId: t.id, ExpiresAt: timestamppb.New(time.Now().Add(timeout))}, nil
+}
+
+func (s *transactionServer) CommitTransaction(ctx context.Context, req *pb.CommitTransactionRequest) (*pb.CommitTransactionResponse, error) {
+    t, err := s.transactions.take(ctx, req.TransactionId)
+    if err != nil {
+        return nil, err
+    }
+    if err := t.commit(); err != nil {
+        return nil, err
+    }
+    return &pb.CommitTransactionResponse{}, nil
+}
+
+func (s *transactionServer) RollbackTransaction(ctx context.Context, req *pb.RollbackTransactionRequest) (*pb.RollbackTransactionResponse, error) {
+    t, err := s.transactions.take(ctx, req.TransactionId)
+    if err != nil {
+        return nil, err
+    }
+    if err := t.rollback(); err != nil {
+        return nil, err
+    }
+    return &pb.RollbackTransactionResponse{}, nil
+}
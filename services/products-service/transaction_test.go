@@ -0,0 +1,183 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "products-service/proto/gen/proto"
+    "shared/fakedata"
+)
+
+func withTenant(ctx context.Context, tenant string) context.Context {
+    return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+func TestTransactionJoinedAndCommitted(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, transactions: newTransactionManager(db, time.Minute, 10)}
+
+    mock.ExpectBegin()
+    tx, err := s.transactions.begin(context.Background(), time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(transactionHeader, tx.id))
+
+    // The create runs in a savepoint of the open transaction, and its outbox
+    // row is committed, and so relayed, only with the client's commit.
+    mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    if _, err := s.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t))); err != nil {
+        t.Fatal(err)
+    }
+
+    mock.ExpectCommit()
+    if _, err := (&transactionServer{transactions: s.transactions}).CommitTransaction(ctx, &pb.CommitTransactionRequest{TransactionId: tx.id}); err != nil {
+        t.Fatal(err)
+    }
+    err = s.inRequestTransaction(ctx, func(*gorm.DB) error { return nil })
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("call after commit: err = %v, want NotFound", err)
+    }
+}
+
+func TestFailedCallLeavesTransactionUsable(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, transactions: newTransactionManager(db, time.Minute, 10)}
+    mock.ExpectBegin()
+    tx, err := s.transactions.begin(context.Background(), time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(transactionHeader, tx.id))
+
+    mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`SELECT "id","status" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+    mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+    if _, err := s.ArchiveProduct(ctx, &pb.ArchiveProductRequest{Id: "9"}); status.Code(err) != codes.NotFound {
+        t.Fatalf("archiving a missing product: err = %v, want NotFound", err)
+    }
+
+    mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    if _, err := s.CreateProduct(ctx, fakedata.Product[*pb.CreateProductRequest](fakedata.NewGenerator(t))); err != nil {
+        t.Fatalf("create after a failed call: %v", err)
+    }
+
+    mock.ExpectRollback()
+    if _, err := (&transactionServer{transactions: s.transactions}).RollbackTransaction(ctx, &pb.RollbackTransactionRequest{TransactionId: tx.id}); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestTransactionNotFound(t *testing.T) {
+    db, mock := newMockDB(t)
+    m := newTransactionManager(db, time.Minute, 10)
+    mock.ExpectBegin()
+    tx, err := m.begin(withTenant(context.Background(), "acme"), time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        mock.ExpectRollback()
+        tx.timer.Stop()
+        tx.rollback()
+    })
+
+    tests := map[string]struct {
+        ctx context.Context
+        id  string
+    }{
+        // An id from another instance looks the same as an unknown one.
+        "unknown id":     {withTenant(context.Background(), "acme"), "4d6f6e2e-other-instance"},
+        "another tenant": {withTenant(context.Background(), "globex"), tx.id},
+    }
+    for name, tt := range tests {
+        t.Run(name, func(t *testing.T) {
+            _, err := m.get(tt.ctx, tt.id)
+            if status.Code(err) != codes.NotFound || !strings.Contains(err.Error(), "another instance") {
+                t.Errorf("err = %v, want NotFound mentioning another instance", err)
+            }
+        })
+    }
+}
+
+func TestAbandonedTransactionIsRolledBack(t *testing.T) {
+    db, mock := newMockDB(t)
+    m := newTransactionManager(db, time.Minute, 1)
+    mock.ExpectBegin()
+    mock.ExpectRollback()
+    if _, err := m.begin(context.Background(), 10*time.Millisecond); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := m.begin(context.Background(), time.Minute); status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("second transaction: err = %v, want ResourceExhausted", err)
+    }
+
+    // The timer removes the transaction and then rolls it back.
+    deadline := time.Now().Add(time.Second)
+    for mock.ExpectationsWereMet() != nil {
+        if time.Now().After(deadline) {
+            t.Fatal("transaction was not rolled back after its timeout")
+        }
+        time.Sleep(time.Millisecond)
+    }
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if len(m.open) != 0 {
+        t.Errorf("%d transactions still open", len(m.open))
+    }
+}
+
+func TestUpdateProductJoinsTransaction(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, transactions: newTransactionManager(db, time.Minute, 10)}
+
+    mock.ExpectBegin()
+    tx, err := s.transactions.begin(context.Background(), time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(transactionHeader, tx.id))
+
+    // Like a create, the update and its outbox row are committed only with
+    // the client's commit.
+    mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1 .* FOR UPDATE`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}).AddRow(7, "Mug", 9.5))
+    mock.ExpectQuery(`SELECT \* FROM "product_versions" WHERE product_id = \$1 ORDER BY major DESC, minor DESC, patch DESC`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "major", "minor", "patch"}).AddRow(1, 7, 1, 0, 0))
+    mock.ExpectExec(`UPDATE "products" SET "name"=\$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+    expectProductVersion(mock)
+    expectAudit(mock, "update", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int32(pb.ProductEventType_PRODUCT_UPDATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    name := "Travel Mug"
+    res, err := s.UpdateProduct(ctx, &pb.UpdateProductRequest{Id: "7", Name: &name})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Product.Name != name {
+        t.Errorf("UpdateProduct = %v, want the new name", res.Product)
+    }
+
+    mock.ExpectCommit()
+    if _, err := (&transactionServer{transactions: s.transactions}).CommitTransaction(ctx, &pb.CommitTransactionRequest{TransactionId: tx.id}); err != nil {
+        t.Fatal(err)
+    }
+}
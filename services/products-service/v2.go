@@ -0,0 +1,167 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "math"
+    "net/url"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/google/uuid"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+// serverV2 implements products.v2.ProductService on top of the same business
+// logic as the v1 server. Products are identified by their UUID and priced in
+// cents.
+type serverV2 struct {
+    pbv2.UnimplementedProductServiceServer
+    core *server
+}
+
+func (s *serverV2) CreateProduct(ctx context.Context, req *pbv2.CreateProductRequest) (*pbv2.ProductResponse, error) {
+    product, duplicate, err := s.createProduct(ctx, req)
+    if err != nil {
+        return nil, err
+    }
+    return &pbv2.ProductResponse{Product: product.toProtoV2(), Duplicate: duplicate}, nil
+}
+
+// createProduct validates req and stores the product, or returns the
+// identical product created within the dedup window with duplicate set. The
+// v1 CreateProduct handler converts its request and comes through here too,
+// so both versions accept exactly the same products.
+func (s *serverV2) createProduct(ctx context.Context, req *pbv2.CreateProductRequest) (product *Product, duplicate bool, err error) {
+    if err := validateCreateProductRequest(req); err != nil {
+        return nil, false, err
+    }
+    return s.core.createProduct(ctx, req.Name, req.Description, strings.TrimSpace(req.Brand), req.ImageUrl, req.PriceCents)
+}
+
+// validateCreateProductRequest applies CreateProduct's checks to req.
+func validateCreateProductRequest(req *pbv2.CreateProductRequest) error {
+    if err := validateProductName(req.Name); err != nil {
+        return err
+    }
+    if err := validateDescription(req.Description); err != nil {
+        return err
+    }
+    if err := validateImageURL(req.ImageUrl); err != nil {
+        return err
+    }
+    if req.PriceCents < 0 {
+        return status.Error(codes.InvalidArgument, "price_cents must not be negative")
+    }
+    if req.PriceCents > maxPriceCents {
+        return status.Errorf(codes.InvalidArgument, "price_cents %d exceeds the maximum of %d", req.PriceCents, maxPriceCents)
+    }
+    return nil
+}
+
+func (s *serverV2) GetProduct(ctx context.Context, req *pbv2.GetProductRequest) (*pbv2.ProductResponse, error) {
+    id, err := uuid.Parse(req.Id)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.Id)
+    }
+
+    var product Product
+    if err := s.core.db.WithContext(ctx).Where("uuid = ?", id.String()).First(&product).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "product %s not found", req.Id)
+        }
+        return nil, err
+    }
+    return &pbv2.ProductResponse{Product: product.toProtoV2()}, nil
+}
+
+func (p *Product) toProtoV2() *pbv2.Product {
+    return &pbv2.Product{
+        Id:           p.UUID,
+        Name:         p.Name,
+        Description:  p.Description,
+        Brand:        p.Brand,
+        ImageUrl:     p.ImageURL,
+        PriceCents:   centsFromPrice(p.Price),
+        CurrencyCode: defaultCurrency,
+        CreatedAt:    timestamppb.New(p.CreatedAt),
+        UpdatedAt:    timestamppb.New(p.UpdatedAt),
+    }
+}
+
+// maxProductNameLength caps a product name, in characters.
+const maxProductNameLength = 200
+
+// maxDescriptionLength caps a product description, in characters.
+const maxDescriptionLength = 5000
+
+// validateDescription rejects descriptions longer than maxDescriptionLength.
+// An empty description is allowed.
+func validateDescription(description string) error {
+    if n := utf8.RuneCountInString(description); n > maxDescriptionLength {
+        return status.Errorf(codes.InvalidArgument, "description is %d characters, more than the maximum of %d", n, maxDescriptionLength)
+    }
+    return nil
+}
+
+// validateImageURL rejects image URLs that are not absolute http or https
+// URLs. An empty image URL is allowed.
+func validateImageURL(imageURL string) error {
+    if imageURL == "" {
+        return nil
+    }
+    u, err := url.Parse(imageURL)
+    if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+        return status.Errorf(codes.InvalidArgument, "image_url %q is not an http or https URL", imageURL)
+    }
+    return nil
+}
+
+// validateProductName rejects names that are blank or longer than
+// maxProductNameLength.
+func validateProductName(name string) error {
+    if strings.TrimSpace(name) == "" {
+        return status.Error(codes.InvalidArgument, "name must not be empty")
+    }
+    if n := utf8.RuneCountInString(name); n > maxProductNameLength {
+        return status.Errorf(codes.InvalidArgument, "name is %d characters, more than the maximum of %d", n, maxProductNameLength)
+    }
+    return nil
+}
+
+// maxPriceCents keeps prices well inside the range of integers a float64
+// holds exactly, so v1 prices convert to and from cents without loss.
+const maxPriceCents = 100_000_000_000_000
+
+// v1PriceCents converts a v1 price to cents for the v2 create path. Prices
+// that are not finite, too large to convert or not a whole number of cents,
+// such as 19.999, are rejected here rather than rounded; the range itself is
+// checked by the v2 path.
+func v1PriceCents(price float64) (int64, error) {
+    if math.IsNaN(price) || math.IsInf(price, 0) {
+        return 0, status.Errorf(codes.InvalidArgument, "price %v is not a finite number", price)
+    }
+    if math.Abs(price) > priceFromCents(maxPriceCents) {
+        return 0, status.Errorf(codes.InvalidArgument, "price %v exceeds the maximum of %v", price, priceFromCents(maxPriceCents))
+    }
+    cents := centsFromPrice(price)
+    if priceFromCents(cents) != price {
+        return 0, status.Errorf(codes.InvalidArgument, "price %v has more than two decimal places", price)
+    }
+    return cents, nil
+}
+
+// centsFromPrice converts a v1 float price to minor units, rounding to the
+// nearest cent.
+func centsFromPrice(price float64) int64 {
+    return int64(math.Round(price * 100))
+}
+
+func priceFromCents(cents int64) float64 {
+    return float64(cents) / 100
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+    "context"
+    "math"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/google/uuid"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "products-service/proto/gen/proto"
+    pbv2 "products-service/proto/gen/proto/v2"
+)
+
+func TestPriceConversionRoundTrips(t *testing.T) {
+    for cents := int64(0); cents <= 1_000_000; cents++ {
+        price := priceFromCents(cents)
+        got, err := v1PriceCents(price)
+        if err != nil {
+            t.Fatalf("v1PriceCents(%v) from %d cents: %v", price, cents, err)
+        }
+        if got != cents {
+            t.Fatalf("v1PriceCents(%v) = %d, want %d", price, got, cents)
+        }
+    }
+}
+
+func TestCentsFromPriceRounds(t *testing.T) {
+    tests := []struct {
+        price float64
+        want  int64
+    }{
+        {0.1 + 0.2, 30},
+        {19.99, 1999},
+        {1.005, 100},
+        {1.0051, 101},
+        {priceFromCents(maxPriceCents), maxPriceCents},
+    }
+    for _, tt := range tests {
+        if got := centsFromPrice(tt.price); got != tt.want {
+            t.Errorf("centsFromPrice(%v) = %d, want %d", tt.price, got, tt.want)
+        }
+    }
+}
+
+func TestProductReadsTheSameThroughV1AndV2(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    v2 := &serverV2{core: s}
+    id := uuid.NewString()
+    updatedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+    row := func() *sqlmock.Rows {
+        return sqlmock.NewRows([]string{"id", "uuid", "name", "price", "created_at", "updated_at"}).
+            AddRow(7, id, "Mug", 19.99, updatedAt, updatedAt)
+    }
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(row())
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(row())
+
+    v1, err := s.GetProduct(context.Background(), &pb.GetProductRequest{Id: "7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    res, err := v2.GetProduct(context.Background(), &pbv2.GetProductRequest{Id: id})
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := res.Product
+    if got.Name != v1.Product.Name {
+        t.Errorf("v2 read %q, v1 read %q", got.Name, v1.Product.Name)
+    }
+    if got.PriceCents != 1999 || priceFromCents(got.PriceCents) != v1.Product.Price {
+        t.Errorf("v2 price_cents = %d, v1 price = %v", got.PriceCents, v1.Product.Price)
+    }
+    if !got.UpdatedAt.AsTime().Equal(v1.Product.UpdatedAt.AsTime()) {
+        t.Errorf("v2 updated_at = %v, v1 updated_at = %v", got.UpdatedAt.AsTime(), v1.Product.UpdatedAt.AsTime())
+    }
+}
+
+func TestCreateProductRejectsInvalidProducts(t *testing.T) {
+    tests := []struct {
+        name       string
+        v1         *pb.CreateProductRequest
+        v2         *pbv2.CreateProductRequest
+        wantReason string
+    }{
+        {"negative price", &pb.CreateProductRequest{Name: "Mug", Price: -0.01}, &pbv2.CreateProductRequest{Name: "Mug", PriceCents: -1}, "negative"},
+        {"price too large", &pb.CreateProductRequest{Name: "Mug", Price: priceFromCents(maxPriceCents + 1)}, &pbv2.CreateProductRequest{Name: "Mug", PriceCents: maxPriceCents + 1}, "maximum"},
+        {"price overflows cents", &pb.CreateProductRequest{Name: "Mug", Price: 1e300}, &pbv2.CreateProductRequest{Name: "Mug", PriceCents: math.MaxInt64}, "maximum"},
+        {"blank name", &pb.CreateProductRequest{Name: " \t", Price: 1}, &pbv2.CreateProductRequest{Name: " \t", PriceCents: 100}, "empty"},
+        {"name too long", &pb.CreateProductRequest{Name: strings.Repeat("é", maxProductNameLength+1), Price: 1}, &pbv2.CreateProductRequest{Name: strings.Repeat("é", maxProductNameLength+1), PriceCents: 100}, "maximum"},
+        {"description too long", &pb.CreateProductRequest{Name: "Mug", Description: strings.Repeat("é", maxDescriptionLength+1), Price: 1}, &pbv2.CreateProductRequest{Name: "Mug", Description: strings.Repeat("é", maxDescriptionLength+1), PriceCents: 100}, "maximum"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            // No statement is expected: both versions reject the product
+            // before touching the database.
+            db, _ := newMockDB(t)
+            s := &server{db: db}
+
+            _, err := s.CreateProduct(context.Background(), tt.v1)
+            if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.wantReason) {
+                t.Errorf("v1: %v, want InvalidArgument mentioning %q", err, tt.wantReason)
+            }
+            _, err = (&serverV2{core: s}).CreateProduct(context.Background(), tt.v2)
+            if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.wantReason) {
+                t.Errorf("v2: %v, want InvalidArgument mentioning %q", err, tt.wantReason)
+            }
+        })
+    }
+}
+
+func TestCreateProductRejectsNonFiniteV1Prices(t *testing.T) {
+    db, _ := newMockDB(t)
+    for _, price := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+        _, err := (&server{db: db}).CreateProduct(context.Background(), &pb.CreateProductRequest{Name: "Mug", Price: price})
+        if status.Code(err) != codes.InvalidArgument {
+            t.Errorf("price %v: %v, want InvalidArgument", price, err)
+        }
+    }
+}
+
+func TestV1CreateProductWritesThroughV2(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+
+    // The product's event goes to the outbox in the same transaction.
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "products"`).
+        WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Mug", 19.99, int64(1999), "active", "Stoneware, 350 ml", "Acme", "https://img.example/mug.png", reviewStatusPending, anonymousActor, sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectProductVersion(mock)
+    expectAudit(mock, "create", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.CreateProduct(context.Background(), &pb.CreateProductRequest{
+        Name:        "Mug",
+        Description: "Stoneware, 350 ml",
+        Price:       19.99,
+        Brand:       " Acme ",
+        ImageUrl:    "https://img.example/mug.png",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Product.Id != "7" || res.Product.Price != 19.99 || res.Product.Description != "Stoneware, 350 ml" {
+        t.Errorf("created product %s at %v described %q, want 7 at 19.99 with the description", res.Product.Id, res.Product.Price, res.Product.Description)
+    }
+    if res.Product.Brand != "Acme" || res.Product.ImageUrl != "https://img.example/mug.png" {
+        t.Errorf("created product has brand %q and image URL %q", res.Product.Brand, res.Product.ImageUrl)
+    }
+}
+
+func TestCreateProductRejectsFractionalCents(t *testing.T) {
+    for _, price := range []float64{19.99, 0.01, 1234567890.12} {
+        if _, err := v1PriceCents(price); err != nil {
+            t.Errorf("v1PriceCents(%v) = %v, want nil", price, err)
+        }
+    }
+
+    // The mock has no expectations, so reaching the database fails the test.
+    db, _ := newMockDB(t)
+    for _, price := range []float64{19.999, 0.001, 1234567890.125} {
+        _, err := (&server{db: db}).CreateProduct(context.Background(), &pb.CreateProductRequest{Name: "Mug", Price: price})
+        if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "decimal places") {
+            t.Errorf("price %v: %v, want InvalidArgument for its decimal places", price, err)
+        }
+    }
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "products-service/proto/gen/proto"
+)
+
+// latestVersion is the alias GetProductVersion accepts for a product's
+// current version.
+const latestVersion = "latest"
+
+// firstVersion is the version of a product as created.
+var firstVersion = semver{major: 1}
+
+// semver is a MAJOR.MINOR.PATCH version number.
+type semver struct {
+    major, minor, patch int
+}
+
+// parseSemver parses a version such as "1.0.3". A leading "v" is allowed.
+func parseSemver(s string) (semver, bool) {
+    parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+    if len(parts) != 3 {
+        return semver{}, false
+    }
+    var numbers [3]int
+    for i, part := range parts {
+        n, err := strconv.Atoi(part)
+        // Semver forbids leading zeros and signs.
+        if err != nil || n < 0 || part != strconv.Itoa(n) {
+            return semver{}, false
+        }
+        numbers[i] = n
+    }
+    return semver{major: numbers[0], minor: numbers[1], patch: numbers[2]}, true
+}
+
+func (v semver) String() string {
+    return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// ProductVersion is an immutable snapshot of a product, taken when it is
+// created and after every update. Versions are ordered by their numeric
+// major, minor and patch columns, as the version string does not sort.
+type ProductVersion struct {
+    ID        uint   `gorm:"primaryKey"`
+    ProductID uint   `gorm:"not null;uniqueIndex:idx_product_versions_version,priority:1"`
+    Major     int    `gorm:"not null;uniqueIndex:idx_product_versions_version,priority:2"`
+    Minor     int    `gorm:"not null;uniqueIndex:idx_product_versions_version,priority:3"`
+    Patch     int    `gorm:"not null;uniqueIndex:idx_product_versions_version,priority:4"`
+    Version   string `gorm:"not null"`
+    Name      string `gorm:"not null"`
+    Price     float64
+    // Snapshot is the product as its API representation.
+    Snapshot  string `gorm:"column:snapshot_json;type:jsonb;not null"`
+    CreatedAt time.Time
+    CreatedBy string `gorm:"not null"`
+}
+
+func (v *ProductVersion) semver() semver {
+    return semver{major: v.Major, minor: v.Minor, patch: v.Patch}
+}
+
+func (v *ProductVersion) toProto() *pb.ProductVersion {
+    return &pb.ProductVersion{
+        Id:           fmt.Sprint(v.ID),
+        ProductId:    fmt.Sprint(v.ProductID),
+        Version:      v.Version,
+        Name:         v.Name,
+        Price:        money(v.Price),
+        SnapshotJson: v.Snapshot,
+        CreatedAt:    timestamppb.New(v.CreatedAt),
+        CreatedBy:    v.CreatedBy,
+    }
+}
+
+// newestVersionsFirst orders a product's versions from the newest.
+const newestVersionsFirst = "major DESC, minor DESC, patch DESC"
+
+// findLatestVersion returns the product's newest version, or nil if it has
+// none: products created before versioning have none until their first
+// update.
+func findLatestVersion(tx *gorm.DB, productID uint) (*ProductVersion, error) {
+    var version ProductVersion
+    err := tx.Where("product_id = ?", productID).Order(newestVersionsFirst).Take(&version).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &version, nil
+}
+
+// recordProductVersion snapshots product as version number. It must run in
+// the transaction that brought the product to that state.
+func recordProductVersion(ctx context.Context, tx *gorm.DB, product *Product, number semver) (*ProductVersion, error) {
+    snapshot, err := protojson.Marshal(product.toProto())
+    if err != nil {
+        return nil, err
+    }
+    version := ProductVersion{
+        ProductID: product.ID,
+        Major:     number.major,
+        Minor:     number.minor,
+        Patch:     number.patch,
+        Version:   number.String(),
+        Name:      product.Name,
+        Price:     product.Price,
+        Snapshot:  string(snapshot),
+        CreatedBy: actorFromContext(ctx),
+    }
+    if err := tx.Create(&version).Error; err != nil {
+        return nil, err
+    }
+    return &version, nil
+}
+
+// UpdateProduct changes the fields that are set in the request. A change
+// records the next patch version of the product; a request that changes
+// nothing does not. It joins the client transaction named in the request's
+// metadata, and its update event is relayed once that commits.
+func (s *server) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.ProductResponse, error) {
+    productID, err := strconv.ParseUint(req.Id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.Id)
+    }
+    if req.Name != nil {
+        if err := validateProductName(*req.Name); err != nil {
+            return nil, err
+        }
+    }
+    var priceCents int64
+    if req.Price != nil {
+        if priceCents, err = v1PriceCents(*req.Price); err != nil {
+            return nil, err
+        }
+        if priceCents < 0 {
+            return nil, status.Error(codes.InvalidArgument, "price must not be negative")
+        }
+    }
+    if req.Description != nil {
+        if err := validateDescription(*req.Description); err != nil {
+            return nil, err
+        }
+    }
+
+    var product Product
+    err = s.inRequestTransaction(ctx, func(tx *gorm.DB) error {
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "product %s not found", req.Id)
+            }
+            return err
+        }
+        updates := map[string]interface{}{}
+        if req.Name != nil && *req.Name != product.Name {
+            updates["name"] = *req.Name
+        }
+        if req.Price != nil && priceCents != centsFromPrice(product.Price) {
+            updates["price"] = priceFromCents(priceCents)
+            updates["price_cents"] = priceCents
+        }
+        if req.Description != nil && *req.Description != product.Description {
+            updates["description"] = *req.Description
+        }
+        if len(updates) == 0 {
+            return nil
+        }
+
+        latest, err := findLatestVersion(tx, product.ID)
+        if err != nil {
+            return err
+        }
+        if latest == nil {
+            // Keep the state from before versioning as the first version.
+            if latest, err = recordProductVersion(ctx, tx, &product, firstVersion); err != nil {
+                return err
+            }
+        }
+        if err := tx.Model(&product).Updates(updates).Error; err != nil {
+            return err
+        }
+        if _, ok := updates["price"]; ok {
+            if _, err := triggerPriceAlerts(tx, &product); err != nil {
+                return err
+            }
+        }
+        next := latest.semver()
+        next.patch++
+        if _, err := recordProductVersion(ctx, tx, &product, next); err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "update", "product", product.ID, map[string]interface{}{
+            "changes": updates,
+            "version": next.String(),
+        }); err != nil {
+            return err
+        }
+        return recordProductEvent(tx, pb.ProductEventType_PRODUCT_UPDATED, &product)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.ProductResponse{Product: product.toProto()}, nil
+}
+
+// GetProductVersion returns one snapshot of a product. The version "latest"
+// is its current version.
+func (s *server) GetProductVersion(ctx context.Context, req *pb.GetProductVersionRequest) (*pb.ProductVersionResponse, error) {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    db := s.db.WithContext(ctx)
+    var version *ProductVersion
+    if req.Version == latestVersion {
+        if version, err = findLatestVersion(db, uint(productID)); err != nil {
+            return nil, err
+        }
+    } else {
+        number, ok := parseSemver(req.Version)
+        if !ok {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid version %q", req.Version)
+        }
+        version = &ProductVersion{}
+        err := db.Where("product_id = ? AND major = ? AND minor = ? AND patch = ?", productID, number.major, number.minor, number.patch).Take(version).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            version = nil
+        } else if err != nil {
+            return nil, err
+        }
+    }
+    if version == nil {
+        return nil, status.Errorf(codes.NotFound, "version %s of product %s not found", req.Version, req.ProductId)
+    }
+    return &pb.ProductVersionResponse{Version: version.toProto()}, nil
+}
+
+// ListProductVersions streams every version of a product, newest first.
+func (s *server) ListProductVersions(req *pb.ListProductVersionsRequest, stream pb.ProductService_ListProductVersionsServer) error {
+    productID, err := strconv.ParseUint(req.ProductId, 10, 64)
+    if err != nil {
+        return status.Errorf(codes.InvalidArgument, "invalid product id %q", req.ProductId)
+    }
+    var versions []ProductVersion
+    if err := s.db.WithContext(stream.Context()).Where("product_id = ?", productID).Order(newestVersionsFirst).Find(&versions).Error; err != nil {
+        return err
+    }
+    for i := range versions {
+        if err := stream.Send(&pb.ProductVersionResponse{Version: versions[i].toProto()}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+    "context"
+    "database/sql/driver"
+    "math"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/encoding/protojson"
+
+    pb "products-service/proto/gen/proto"
+    "shared/audit"
+)
+
+// versionStream records the versions ListProductVersions sends.
+type versionStream struct {
+    grpc.ServerStream
+    versions []*pb.ProductVersion
+}
+
+func (s *versionStream) Context() context.Context { return context.Background() }
+
+func (s *versionStream) Send(res *pb.ProductVersionResponse) error {
+    s.versions = append(s.versions, res.Version)
+    return nil
+}
+
+// snapshotOf matches a snapshot_json argument holding a product with the
+// given name and price.
+type snapshotOf struct {
+    name  string
+    price float64
+}
+
+func (m snapshotOf) Match(v driver.Value) bool {
+    data, ok := v.(string)
+    if !ok {
+        return false
+    }
+    var product pb.Product
+    if err := protojson.Unmarshal([]byte(data), &product); err != nil {
+        return false
+    }
+    return product.Name == m.name && product.Price == m.price
+}
+
+func TestParseSemver(t *testing.T) {
+    for s, want := range map[string]semver{
+        "1.0.0":    {major: 1},
+        "v2.3.14":  {major: 2, minor: 3, patch: 14},
+        "0.10.200": {minor: 10, patch: 200},
+    } {
+        if got, ok := parseSemver(s); !ok || got != want {
+            t.Errorf("parseSemver(%q) = %v, %v, want %v", s, got, ok, want)
+        }
+        if got, _ := parseSemver(s); "v"+got.String() != s && got.String() != s {
+            t.Errorf("%q formats as %q", s, got.String())
+        }
+    }
+    for _, s := range []string{"", "latest", "1.0", "1.0.0.0", "1.0.x", "01.0.0", "1.-1.0", "1.+2.0", "1.0.0-beta"} {
+        if _, ok := parseSemver(s); ok {
+            t.Errorf("parseSemver(%q) succeeded", s)
+        }
+    }
+}
+
+func TestUpdateProductRejectsBadRequests(t *testing.T) {
+    // The mock has no expectations, so reaching the database fails the test.
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    blank, negative, fraction, nan := " ", -1.0, 19.999, math.NaN()
+    for name, req := range map[string]*pb.UpdateProductRequest{
+        "bad id":         {Id: "mug"},
+        "blank name":     {Id: "1", Name: &blank},
+        "negative price": {Id: "1", Price: &negative},
+        "sub-cent price": {Id: "1", Price: &fraction},
+        "NaN price":      {Id: "1", Price: &nan},
+    } {
+        if _, err := s.UpdateProduct(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: UpdateProduct = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestUpdateProductSnapshotsProductBeforeVersioning(t *testing.T) {
+    db, mock := newMockDB(t)
+
+    // The product predates versioning, so its state before the update is
+    // kept as 1.0.0 and the update becomes 1.0.1. Each snapshot holds the
+    // product as it was at that version.
+    price := 15.0
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products" WHERE "products"."id" = \$1 .* FOR UPDATE`).WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "status"}).AddRow(7, "Mug", 12.5, productStatusActive))
+    mock.ExpectQuery(`SELECT \* FROM "product_versions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectQuery(`INSERT INTO "product_versions"`).
+        WithArgs(7, 1, 0, 0, "1.0.0", "Mug", 12.5, snapshotOf{"Mug", 12.5}, sqlmock.AnyArg(), anonymousActor).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectExec(`UPDATE "products" SET "price"=\$1,"price_cents"=\$2,"updated_at"=\$3 WHERE .*"id" = \$4`).
+        WithArgs(15.0, 1500, sqlmock.AnyArg(), 7).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`SELECT \* FROM "price_alerts"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectQuery(`INSERT INTO "product_versions"`).
+        WithArgs(7, 1, 0, 1, "1.0.1", "Mug", 15.0, snapshotOf{"Mug", 15}, sqlmock.AnyArg(), anonymousActor).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+    expectAudit(mock, "update", "product")
+    mock.ExpectQuery(`INSERT INTO "product_outbox"`).
+        WithArgs(int32(pb.ProductEventType_PRODUCT_UPDATED), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).UpdateProduct(context.Background(), &pb.UpdateProductRequest{Id: "7", Price: &price})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Product.Price != 15 {
+        t.Errorf("updated product = %v, want price 15", res.Product)
+    }
+}
+
+func TestUpdateProductWithoutChangesRecordsNothing(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "products"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}).AddRow(7, "Mug", 12.5))
+    mock.ExpectCommit()
+
+    name, price := "Mug", 12.5
+    if _, err := (&server{db: db}).UpdateProduct(context.Background(), &pb.UpdateProductRequest{Id: "7", Name: &name, Price: &price}); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestGetProductVersion(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    ctx := context.Background()
+    versionColumns := []string{"id", "product_id", "major", "minor", "patch", "version", "name", "price", "snapshot_json", "created_by"}
+
+    mock.ExpectQuery(`SELECT \* FROM "product_versions" WHERE product_id = \$1 ORDER BY major DESC, minor DESC, patch DESC LIMIT 1`).WithArgs(7).
+        WillReturnRows(sqlmock.NewRows(versionColumns).AddRow(3, 7, 1, 0, 2, "1.0.2", "Mug", 15, `{"id":"7"}`, "key:abc"))
+    latest, err := s.GetProductVersion(ctx, &pb.GetProductVersionRequest{ProductId: "7", Version: "latest"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if v := latest.Version; v.Version != "1.0.2" || v.Price.GetAmount() != 15 || v.SnapshotJson != `{"id":"7"}` {
+        t.Errorf("latest version = %v", v)
+    }
+
+    mock.ExpectQuery(`SELECT \* FROM "product_versions" WHERE product_id = \$1 AND major = \$2 AND minor = \$3 AND patch = \$4`).
+        WithArgs(7, 1, 0, 1).
+        WillReturnRows(sqlmock.NewRows(versionColumns))
+    if _, err := s.GetProductVersion(ctx, &pb.GetProductVersionRequest{ProductId: "7", Version: "v1.0.1"}); status.Code(err) != codes.NotFound {
+        t.Errorf("missing version = %v, want NotFound", err)
+    }
+    if _, err := s.GetProductVersion(ctx, &pb.GetProductVersionRequest{ProductId: "7", Version: "1.0"}); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("version 1.0 = %v, want InvalidArgument", err)
+    }
+}
+
+func TestProductVersionsWithDatabase(t *testing.T) {
+    db := newTestDatabase(t)
+    if err := db.AutoMigrate(&Product{}, &ProductVersion{}, &PriceAlert{}, &OutboxEvent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db, recent: newRecentCreates(time.Second)}
+    ctx := context.Background()
+    created, err := s.CreateProduct(ctx, &pb.CreateProductRequest{Name: "Mug", Price: 1})
+    if err != nil {
+        t.Fatal(err)
+    }
+    id := created.Product.Id
+
+    // Eleven updates take the product to 1.0.11, which must sort after
+    // 1.0.9 although it does not as a string.
+    for i := 2; i <= 12; i++ {
+        price := float64(i)
+        if _, err := s.UpdateProduct(ctx, &pb.UpdateProductRequest{Id: id, Price: &price}); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    stream := &versionStream{}
+    if err := s.ListProductVersions(&pb.ListProductVersionsRequest{ProductId: id}, stream); err != nil {
+        t.Fatal(err)
+    }
+    var got []string
+    for _, v := range stream.versions {
+        got = append(got, v.Version)
+    }
+    want := []string{"1.0.11", "1.0.10", "1.0.9", "1.0.8", "1.0.7", "1.0.6", "1.0.5", "1.0.4", "1.0.3", "1.0.2", "1.0.1", "1.0.0"}
+    if !slices.Equal(got, want) {
+        t.Fatalf("versions = %v, want %v", got, want)
+    }
+    // Every snapshot holds the price the product had at that version.
+    for i, v := range stream.versions {
+        var snapshot pb.Product
+        if err := protojson.Unmarshal([]byte(v.SnapshotJson), &snapshot); err != nil {
+            t.Fatal(err)
+        }
+        if wantPrice := float64(12 - i); snapshot.Price != wantPrice || v.Price.GetAmount() != wantPrice {
+            t.Errorf("%s has snapshot price %v and price %v, want %v", v.Version, snapshot.Price, v.Price.GetAmount(), wantPrice)
+        }
+    }
+
+    latest, err := s.GetProductVersion(ctx, &pb.GetProductVersionRequest{ProductId: id, Version: "latest"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if latest.Version.Version != "1.0.11" {
+        t.Errorf("latest = %s, want 1.0.11", latest.Version.Version)
+    }
+}
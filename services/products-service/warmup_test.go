@@ -0,0 +1,52 @@
+package main
+
+import (
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/health/grpc_health_v1"
+
+    "shared/healthcheck"
+)
+
+func TestWarmUpWithoutDelayServesAtOnce(t *testing.T) {
+    // No warmup query is expected.
+    db, _ := newMockDB(t)
+    h := health.NewServer()
+    warmUp(db, healthcheck.NewHealthWatcher(serviceName, db, h, time.Second, "products.ProductService"), 0)
+    for _, service := range []string{"", "products.ProductService"} {
+        if got := servingStatus(t, h, service); got != grpc_health_v1.HealthCheckResponse_SERVING {
+            t.Errorf("%q is %v, want SERVING", service, got)
+        }
+    }
+}
+
+func TestWarmUpHoldsNotServing(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.MatchExpectationsInOrder(false)
+    mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+    mock.ExpectQuery(`SELECT \* FROM "tags"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+    h := health.NewServer()
+    start := time.Now()
+    warmUp(db, healthcheck.NewHealthWatcher(serviceName, db, h, time.Second, "products.ProductService"), 200*time.Millisecond)
+    if got := servingStatus(t, h, "products.ProductService"); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+        t.Fatalf("status during warmup = %v, want NOT_SERVING", got)
+    }
+
+    for servingStatus(t, h, "products.ProductService") != grpc_health_v1.HealthCheckResponse_SERVING {
+        if time.Since(start) > 5*time.Second {
+            t.Fatal("still NOT_SERVING 5s after a 200ms warmup")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+        t.Errorf("SERVING after %v, before READINESS_DELAY passed", elapsed)
+    }
+    if got := servingStatus(t, h, ""); got != grpc_health_v1.HealthCheckResponse_SERVING {
+        t.Errorf("server status = %v, want SERVING", got)
+    }
+}
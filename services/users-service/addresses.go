@@ -0,0 +1,387 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "net/http"
+    "net/url"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// geocodeTimeout bounds a single geocoding request, which runs after the
+// RPC that triggered it has returned.
+const geocodeTimeout = 10 * time.Second
+
+// UserAddress is a postal address of a user. Latitude and Longitude are
+// filled in by the geocoder after the address is saved, and stay nil if it
+// fails. A user has at most one default address.
+type UserAddress struct {
+    ID          uint   `gorm:"primaryKey"`
+    UserID      uint   `gorm:"not null;index;uniqueIndex:idx_user_addresses_default,where:is_default"`
+    Line1       string `gorm:"not null"`
+    Line2       string `gorm:"not null;default:''"`
+    City        string `gorm:"not null"`
+    StateCode   string `gorm:"not null;default:''"`
+    CountryCode string `gorm:"type:char(2);not null"`
+    PostalCode  string `gorm:"not null;default:''"`
+    Latitude    *float64
+    Longitude   *float64
+    IsDefault   bool `gorm:"not null;default:false"`
+    CreatedAt   time.Time
+    UpdatedAt   time.Time
+}
+
+func (a *UserAddress) toProto() *pb.UserAddress {
+    return &pb.UserAddress{
+        Id:          fmt.Sprint(a.ID),
+        UserId:      fmt.Sprint(a.UserID),
+        Line1:       a.Line1,
+        Line2:       a.Line2,
+        City:        a.City,
+        StateCode:   a.StateCode,
+        CountryCode: a.CountryCode,
+        PostalCode:  a.PostalCode,
+        Latitude:    a.Latitude,
+        Longitude:   a.Longitude,
+        IsDefault:   a.IsDefault,
+    }
+}
+
+// iso3166Alpha2 lists the ISO 3166-1 alpha-2 country codes.
+var iso3166Alpha2 = func() map[string]bool {
+    codes := make(map[string]bool)
+    for _, code := range strings.Fields(`
+        AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ
+        BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR
+        CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+        GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU
+        ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ
+        LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+        MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF
+        PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI
+        SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR
+        TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`) {
+        codes[code] = true
+    }
+    return codes
+}()
+
+// postalCodeFormats are the postal code formats checked for the countries
+// that have one here. Codes are upper-cased before they are matched.
+var postalCodeFormats = map[string]*regexp.Regexp{
+    "US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+    "CA": regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`),
+    "GB": regexp.MustCompile(`^([A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}|GIR ?0AA)$`),
+}
+
+// AddressValidator checks addresses before they are saved: the country code
+// must be an ISO 3166-1 alpha-2 code, and US, Canadian and UK postal codes
+// must be in their country's format. "UK" is accepted for GB.
+type AddressValidator struct{}
+
+// Validate normalizes address's country and postal codes in place and
+// reports the first problem with it as an InvalidArgument error.
+func (AddressValidator) Validate(address *UserAddress) error {
+    address.Line1 = strings.TrimSpace(address.Line1)
+    address.Line2 = strings.TrimSpace(address.Line2)
+    address.City = strings.TrimSpace(address.City)
+    address.StateCode = strings.ToUpper(strings.TrimSpace(address.StateCode))
+    address.CountryCode = strings.ToUpper(strings.TrimSpace(address.CountryCode))
+    address.PostalCode = strings.ToUpper(strings.TrimSpace(address.PostalCode))
+    if address.CountryCode == "UK" {
+        address.CountryCode = "GB"
+    }
+
+    if address.Line1 == "" {
+        return status.Error(codes.InvalidArgument, "line1 is required")
+    }
+    if address.City == "" {
+        return status.Error(codes.InvalidArgument, "city is required")
+    }
+    if !iso3166Alpha2[address.CountryCode] {
+        return status.Errorf(codes.InvalidArgument, "country_code %q is not an ISO 3166-1 alpha-2 code", address.CountryCode)
+    }
+    if format, ok := postalCodeFormats[address.CountryCode]; ok && !format.MatchString(address.PostalCode) {
+        return status.Errorf(codes.InvalidArgument, "postal_code %q is not a valid %s postal code", address.PostalCode, address.CountryCode)
+    }
+    return nil
+}
+
+// Geocoder looks up the coordinates of an address.
+type Geocoder interface {
+    Geocode(ctx context.Context, address *UserAddress) (latitude, longitude float64, err error)
+}
+
+// httpGeocoder queries the geocoding API at GEOCODER_URL with
+// GET ?address=<one line address>&country=<country code>, and expects a JSON
+// {"latitude": ..., "longitude": ...} response.
+type httpGeocoder struct {
+    endpoint string
+    client   *http.Client
+}
+
+func newHTTPGeocoder(endpoint string) *httpGeocoder {
+    return &httpGeocoder{endpoint: endpoint, client: &http.Client{Timeout: geocodeTimeout}}
+}
+
+func (g *httpGeocoder) Geocode(ctx context.Context, address *UserAddress) (float64, float64, error) {
+    u, err := url.Parse(g.endpoint)
+    if err != nil {
+        return 0, 0, err
+    }
+    var parts []string
+    for _, part := range []string{address.Line1, address.Line2, address.City, address.StateCode, address.PostalCode} {
+        if part != "" {
+            parts = append(parts, part)
+        }
+    }
+    query := u.Query()
+    query.Set("address", strings.Join(parts, ", "))
+    query.Set("country", address.CountryCode)
+    u.RawQuery = query.Encode()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+    if err != nil {
+        return 0, 0, err
+    }
+    resp, err := g.client.Do(req)
+    if err != nil {
+        return 0, 0, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return 0, 0, fmt.Errorf("geocoder returned %s", resp.Status)
+    }
+    var result struct {
+        Latitude  *float64 `json:"latitude"`
+        Longitude *float64 `json:"longitude"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+        return 0, 0, fmt.Errorf("invalid geocoder response: %w", err)
+    }
+    if result.Latitude == nil || result.Longitude == nil {
+        return 0, 0, errors.New("geocoder response has no coordinates")
+    }
+    return *result.Latitude, *result.Longitude, nil
+}
+
+// geocodeAsync geocodes a saved address in the background and stores its
+// coordinates. They are only stored if the address has not been changed
+// since, so a slow lookup cannot overwrite a newer address's coordinates.
+// Failures are logged and leave the coordinates nil.
+func (s *server) geocodeAsync(address UserAddress) {
+    if s.geocoder == nil {
+        return
+    }
+    go func() {
+        ctx, cancel := context.WithTimeout(context.Background(), geocodeTimeout)
+        defer cancel()
+        latitude, longitude, err := s.geocoder.Geocode(ctx, &address)
+        if err != nil {
+            log.Printf("Failed to geocode address %d: %v", address.ID, err)
+            return
+        }
+        err = s.db.WithContext(ctx).Model(&UserAddress{}).
+            Where("id = ? AND updated_at = ?", address.ID, address.UpdatedAt).
+            UpdateColumns(map[string]interface{}{"latitude": latitude, "longitude": longitude}).Error
+        if err != nil {
+            log.Printf("Failed to store coordinates of address %d: %v", address.ID, err)
+        }
+    }()
+}
+
+// findAddress loads one of the user's addresses in tx, locking it for the
+// rest of the transaction.
+func findAddress(tx *gorm.DB, userID uint, addressID string) (*UserAddress, error) {
+    id, err := strconv.ParseUint(addressID, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid address id %q", addressID)
+    }
+    var address UserAddress
+    err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", id, userID).Take(&address).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, status.Errorf(codes.NotFound, "address %s not found", addressID)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &address, nil
+}
+
+// clearDefaultAddress unsets the user's default address, so another can
+// become the default without breaking idx_user_addresses_default. Like every
+// change of is_default it leaves updated_at alone, which only tracks changes
+// geocodeAsync cares about.
+func clearDefaultAddress(tx *gorm.DB, userID uint) error {
+    return tx.Model(&UserAddress{}).Where("user_id = ? AND is_default", userID).UpdateColumn("is_default", false).Error
+}
+
+// AddUserAddress saves a new address for the user. A user's first address
+// is their default whether or not is_default is set.
+func (s *server) AddUserAddress(ctx context.Context, req *pb.AddUserAddressRequest) (*pb.UserAddressResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    address := UserAddress{
+        UserID:      user.ID,
+        Line1:       req.Line1,
+        Line2:       req.Line2,
+        City:        req.City,
+        StateCode:   req.StateCode,
+        CountryCode: req.CountryCode,
+        PostalCode:  req.PostalCode,
+        IsDefault:   req.IsDefault,
+    }
+    if err := (AddressValidator{}).Validate(&address); err != nil {
+        return nil, err
+    }
+
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var count int64
+        if err := tx.Model(&UserAddress{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+            return err
+        }
+        if count == 0 {
+            address.IsDefault = true
+        }
+        if address.IsDefault {
+            if err := clearDefaultAddress(tx, user.ID); err != nil {
+                return err
+            }
+        }
+        if err := tx.Create(&address).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "create", "user_address", address.ID, map[string]interface{}{"user_id": user.ID, "is_default": address.IsDefault})
+    })
+    if err != nil {
+        return nil, err
+    }
+    s.geocodeAsync(address)
+    return &pb.UserAddressResponse{Address: address.toProto()}, nil
+}
+
+// GetUserAddresses returns the user's addresses, the default first.
+func (s *server) GetUserAddresses(ctx context.Context, req *pb.GetUserAddressesRequest) (*pb.GetUserAddressesResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var addresses []UserAddress
+    if err := s.db.WithContext(ctx).Where("user_id = ?", user.ID).Order("is_default DESC, id").Find(&addresses).Error; err != nil {
+        return nil, err
+    }
+    res := &pb.GetUserAddressesResponse{Addresses: make([]*pb.UserAddress, len(addresses))}
+    for i := range addresses {
+        res.Addresses[i] = addresses[i].toProto()
+    }
+    return res, nil
+}
+
+// UpdateUserAddress replaces the fields of one of the user's addresses and
+// geocodes it again. Its coordinates are cleared until that finishes.
+func (s *server) UpdateUserAddress(ctx context.Context, req *pb.UpdateUserAddressRequest) (*pb.UserAddressResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    update := UserAddress{
+        Line1:       req.Line1,
+        Line2:       req.Line2,
+        City:        req.City,
+        StateCode:   req.StateCode,
+        CountryCode: req.CountryCode,
+        PostalCode:  req.PostalCode,
+    }
+    if err := (AddressValidator{}).Validate(&update); err != nil {
+        return nil, err
+    }
+
+    var address *UserAddress
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var err error
+        if address, err = findAddress(tx, user.ID, req.AddressId); err != nil {
+            return err
+        }
+        address.Line1, address.Line2, address.City = update.Line1, update.Line2, update.City
+        address.StateCode, address.CountryCode, address.PostalCode = update.StateCode, update.CountryCode, update.PostalCode
+        address.Latitude, address.Longitude = nil, nil
+        if err := tx.Save(address).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "update", "user_address", address.ID, map[string]uint{"user_id": user.ID})
+    })
+    if err != nil {
+        return nil, err
+    }
+    s.geocodeAsync(*address)
+    return &pb.UserAddressResponse{Address: address.toProto()}, nil
+}
+
+// DeleteUserAddress deletes one of the user's addresses. Deleting the
+// default address leaves the user without one until SetDefaultAddress is
+// called.
+func (s *server) DeleteUserAddress(ctx context.Context, req *pb.DeleteUserAddressRequest) (*pb.DeleteUserAddressResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        address, err := findAddress(tx, user.ID, req.AddressId)
+        if err != nil {
+            return err
+        }
+        if err := tx.Delete(address).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "delete", "user_address", address.ID, map[string]uint{"user_id": user.ID})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.DeleteUserAddressResponse{}, nil
+}
+
+// SetDefaultAddress makes one of the user's addresses their default.
+func (s *server) SetDefaultAddress(ctx context.Context, req *pb.SetDefaultAddressRequest) (*pb.UserAddressResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var address *UserAddress
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var err error
+        if address, err = findAddress(tx, user.ID, req.AddressId); err != nil {
+            return err
+        }
+        if address.IsDefault {
+            return nil
+        }
+        if err := clearDefaultAddress(tx, user.ID); err != nil {
+            return err
+        }
+        if err := tx.Model(address).UpdateColumn("is_default", true).Error; err != nil {
+            return err
+        }
+        address.IsDefault = true
+        return recordAudit(ctx, tx, "set_default", "user_address", address.ID, map[string]uint{"user_id": user.ID})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UserAddressResponse{Address: address.toProto()}, nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    "shared/audit"
+    "shared/testdb"
+    pb "users-service/proto/gen/proto"
+)
+
+// fakeGeocoder returns fixed coordinates, or err, and sends every address it
+// is asked about to calls.
+type fakeGeocoder struct {
+    latitude, longitude float64
+    err                 error
+    calls               chan UserAddress
+}
+
+func newFakeGeocoder(latitude, longitude float64, err error) *fakeGeocoder {
+    return &fakeGeocoder{latitude: latitude, longitude: longitude, err: err, calls: make(chan UserAddress, 10)}
+}
+
+func (g *fakeGeocoder) Geocode(ctx context.Context, address *UserAddress) (float64, float64, error) {
+    g.calls <- *address
+    return g.latitude, g.longitude, g.err
+}
+
+// called waits for the next Geocode call.
+func (g *fakeGeocoder) called(t *testing.T) UserAddress {
+    t.Helper()
+    select {
+    case address := <-g.calls:
+        return address
+    case <-time.After(5 * time.Second):
+        t.Fatal("address was not geocoded")
+        return UserAddress{}
+    }
+}
+
+func TestAddressValidator(t *testing.T) {
+    valid := map[string]UserAddress{
+        "US ZIP":         {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "us", PostalCode: "95014"},
+        "US ZIP+4":       {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "US", PostalCode: "95014-2083"},
+        "Canada":         {Line1: "24 Sussex Dr", City: "Ottawa", CountryCode: "CA", PostalCode: "k1m 1m4"},
+        "UK":             {Line1: "10 Downing St", City: "London", CountryCode: "UK", PostalCode: "SW1A 2AA"},
+        "no format":      {Line1: "Norzin Lam", City: "Thimphu", CountryCode: "BT", PostalCode: "11001"},
+        "no postal code": {Line1: "Norzin Lam", City: "Thimphu", CountryCode: "BT"},
+    }
+    for name, address := range valid {
+        if err := (AddressValidator{}).Validate(&address); err != nil {
+            t.Errorf("%s: %v", name, err)
+        }
+    }
+
+    normalized := UserAddress{Line1: " 10 Downing St ", City: "London", CountryCode: " uk", PostalCode: "sw1a 2aa"}
+    if err := (AddressValidator{}).Validate(&normalized); err != nil {
+        t.Fatal(err)
+    }
+    if normalized.Line1 != "10 Downing St" || normalized.CountryCode != "GB" || normalized.PostalCode != "SW1A 2AA" {
+        t.Errorf("normalized to %+v", normalized)
+    }
+
+    invalid := map[string]UserAddress{
+        "no line1":        {City: "Cupertino", CountryCode: "US", PostalCode: "95014"},
+        "no city":         {Line1: "1 Infinite Loop", CountryCode: "US", PostalCode: "95014"},
+        "unknown country": {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "XX"},
+        "alpha-3 country": {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "USA", PostalCode: "95014"},
+        "short ZIP":       {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "US", PostalCode: "9501"},
+        "Canadian D":      {Line1: "24 Sussex Dr", City: "Ottawa", CountryCode: "CA", PostalCode: "D1M 1M4"},
+        "UK without unit": {Line1: "10 Downing St", City: "London", CountryCode: "GB", PostalCode: "SW1A"},
+        "missing ZIP":     {Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "US"},
+    }
+    for name, address := range invalid {
+        if err := (AddressValidator{}).Validate(&address); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: Validate = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestHTTPGeocoder(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Query().Get("address") {
+        case "1 Infinite Loop, Cupertino, CA, 95014":
+            if r.URL.Query().Get("country") != "US" || r.URL.Query().Get("key") != "secret" {
+                http.Error(w, "bad query", http.StatusBadRequest)
+                return
+            }
+            json.NewEncoder(w).Encode(map[string]float64{"latitude": 37.33, "longitude": -122.03})
+        case "Nowhere":
+            json.NewEncoder(w).Encode(map[string]interface{}{"latitude": nil})
+        default:
+            http.Error(w, "geocoder is down", http.StatusServiceUnavailable)
+        }
+    }))
+    defer srv.Close()
+    g := newHTTPGeocoder(srv.URL + "/geocode?key=secret")
+    ctx := context.Background()
+
+    latitude, longitude, err := g.Geocode(ctx, &UserAddress{Line1: "1 Infinite Loop", City: "Cupertino", StateCode: "CA", CountryCode: "US", PostalCode: "95014"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if latitude != 37.33 || longitude != -122.03 {
+        t.Errorf("geocoded to %v,%v, want 37.33,-122.03", latitude, longitude)
+    }
+    for _, line1 := range []string{"Nowhere", "Anywhere"} {
+        if _, _, err := g.Geocode(ctx, &UserAddress{Line1: line1, CountryCode: "US"}); err == nil {
+            t.Errorf("geocoding %q succeeded", line1)
+        }
+    }
+}
+
+func addAddressRequest() *pb.AddUserAddressRequest {
+    return &pb.AddUserAddressRequest{UserId: "1", Line1: "1 Infinite Loop", City: "Cupertino", StateCode: "CA", CountryCode: "US", PostalCode: "95014"}
+}
+
+// expectAddAddress expects AddUserAddress to save a user's first address.
+func expectAddAddress(mock sqlmock.Sqlmock) {
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Ada", "ada@example.com"))
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "user_addresses" WHERE user_id = \$1`).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+    mock.ExpectExec(`UPDATE "user_addresses" SET "is_default"=\$1 WHERE user_id = \$2 AND is_default`).
+        WithArgs(false, 1).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`INSERT INTO "user_addresses"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+    expectAudit(mock, "create", "user_address")
+    mock.ExpectCommit()
+}
+
+func TestAddUserAddressGeocodesInBackground(t *testing.T) {
+    db, mock := newMockDB(t)
+    geocoder := newFakeGeocoder(37.33, -122.03, nil)
+    s := &server{db: db, geocoder: geocoder}
+
+    expectAddAddress(mock)
+    mock.ExpectBegin()
+    mock.ExpectExec(`UPDATE "user_addresses" SET "latitude"=\$1,"longitude"=\$2 WHERE id = \$3 AND updated_at = \$4`).
+        WithArgs(37.33, -122.03, 5, sqlmock.AnyArg()).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    res, err := s.AddUserAddress(context.Background(), addAddressRequest())
+    if err != nil {
+        t.Fatal(err)
+    }
+    // The first address is the default, and has no coordinates until the
+    // geocoder answers.
+    if !res.Address.IsDefault || res.Address.Latitude != nil {
+        t.Errorf("added %v, want a default address without coordinates", res.Address)
+    }
+    if address := geocoder.called(t); address.ID != 5 || address.PostalCode != "95014" {
+        t.Errorf("geocoded %+v, want address 5", address)
+    }
+    deadline := time.Now().Add(5 * time.Second)
+    for mock.ExpectationsWereMet() != nil && time.Now().Before(deadline) {
+        time.Sleep(10 * time.Millisecond)
+    }
+}
+
+func TestAddUserAddressSurvivesGeocodingFailures(t *testing.T) {
+    db, mock := newMockDB(t)
+    geocoder := newFakeGeocoder(0, 0, errors.New("geocoder is down"))
+    expectAddAddress(mock)
+
+    res, err := (&server{db: db, geocoder: geocoder}).AddUserAddress(context.Background(), addAddressRequest())
+    if err != nil {
+        t.Fatalf("AddUserAddress with a failing geocoder = %v, want success", err)
+    }
+    geocoder.called(t)
+    if res.Address.Latitude != nil || res.Address.Longitude != nil {
+        t.Errorf("coordinates = %v,%v, want none", res.Address.Latitude, res.Address.Longitude)
+    }
+}
+
+func TestAddUserAddressRejectsInvalidAddresses(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Ada", "ada@example.com"))
+    req := addAddressRequest()
+    req.PostalCode = "CUPERTINO"
+    if _, err := (&server{db: db}).AddUserAddress(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("AddUserAddress with a bad ZIP = %v, want InvalidArgument", err)
+    }
+}
+
+func TestUserAddressesWithDatabase(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&User{}, &UserAddress{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    geocoder := newFakeGeocoder(37.33, -122.03, nil)
+    s := &server{db: db, geocoder: geocoder}
+    ctx := context.Background()
+    user := User{Name: "Ada", Email: "ada@example.com"}
+    if err := db.Create(&user).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    first, err := s.AddUserAddress(ctx, addAddressRequest())
+    if err != nil {
+        t.Fatal(err)
+    }
+    geocoder.called(t)
+    second, err := s.AddUserAddress(ctx, &pb.AddUserAddressRequest{UserId: "1", Line1: "10 Downing St", City: "London", CountryCode: "UK", PostalCode: "SW1A 2AA", IsDefault: true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    geocoder.called(t)
+    if !second.Address.IsDefault || second.Address.CountryCode != "GB" {
+        t.Errorf("second address = %v, want the default, in GB", second.Address)
+    }
+
+    // The coordinates are stored once the background lookups finish.
+    var addresses *pb.GetUserAddressesResponse
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        if addresses, err = s.GetUserAddresses(ctx, &pb.GetUserAddressesRequest{UserId: "1"}); err != nil {
+            t.Fatal(err)
+        }
+        if addresses.Addresses[1].Latitude != nil || time.Now().After(deadline) {
+            break
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    if len(addresses.Addresses) != 2 || addresses.Addresses[0].Id != second.Address.Id || addresses.Addresses[1].IsDefault {
+        t.Fatalf("addresses = %v, want the London one first and the only default", addresses.Addresses)
+    }
+    if latitude := addresses.Addresses[1].Latitude; latitude == nil || *latitude != 37.33 {
+        t.Errorf("latitude of the first address = %v, want 37.33", latitude)
+    }
+
+    if _, err := s.SetDefaultAddress(ctx, &pb.SetDefaultAddressRequest{UserId: "1", AddressId: first.Address.Id}); err != nil {
+        t.Fatal(err)
+    }
+    updated, err := s.UpdateUserAddress(ctx, &pb.UpdateUserAddressRequest{UserId: "1", AddressId: second.Address.Id, Line1: "11 Downing St", City: "London", CountryCode: "GB", PostalCode: "SW1A 2AB"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if updated.Address.IsDefault || updated.Address.Latitude != nil {
+        t.Errorf("updated address = %v, want it not the default and without coordinates", updated.Address)
+    }
+    if address := geocoder.called(t); address.Line1 != "11 Downing St" {
+        t.Errorf("geocoded %q after the update, want 11 Downing St", address.Line1)
+    }
+
+    if _, err := s.DeleteUserAddress(ctx, &pb.DeleteUserAddressRequest{UserId: "1", AddressId: first.Address.Id}); err != nil {
+        t.Fatal(err)
+    }
+    for _, req := range []*pb.DeleteUserAddressRequest{
+        {UserId: "1", AddressId: first.Address.Id},
+        {UserId: "2", AddressId: second.Address.Id},
+    } {
+        if _, err := s.DeleteUserAddress(ctx, req); status.Code(err) != codes.NotFound {
+            t.Errorf("DeleteUserAddress(%v) = %v, want NotFound", req, err)
+        }
+    }
+}
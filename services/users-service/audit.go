@@ -0,0 +1,65 @@
+package main
+
+import (
+    "context"
+    "fmt"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    "shared/audit"
+    "shared/pagination"
+    pb "users-service/proto/gen/proto"
+)
+
+// recordAudit writes an audit entry for a mutation made by the caller of ctx.
+// tx must be the mutation's transaction.
+func recordAudit(ctx context.Context, tx *gorm.DB, action, entityType string, entityID interface{}, details interface{}) error {
+    return audit.Record(tx, actorFromContext(ctx), action, entityType, entityID, details)
+}
+
+func auditEntryToProto(e *audit.Entry) *pb.AuditLogEntry {
+    return &pb.AuditLogEntry{
+        Id:          fmt.Sprint(e.ID),
+        Action:      e.Action,
+        EntityType:  e.EntityType,
+        EntityId:    e.EntityID,
+        Actor:       e.Actor,
+        Timestamp:   timestamppb.New(e.Timestamp),
+        DetailsJson: e.Details,
+    }
+}
+
+type auditServer struct {
+    pb.UnimplementedAuditServiceServer
+    db          *gorm.DB
+    maxPageSize int
+}
+
+// GetAuditLog pages through the audit log oldest first, optionally only the
+// entries for one entity type or entity.
+func (s *auditServer) GetAuditLog(ctx context.Context, req *pb.GetAuditLogRequest) (*pb.GetAuditLogResponse, error) {
+    if req.EntityId != "" && req.EntityType == "" {
+        return nil, status.Error(codes.InvalidArgument, "entity_id requires entity_type")
+    }
+    size, err := pagination.ClampPageSize(req.PageSize, s.maxPageSize)
+    if err != nil {
+        return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+    }
+    after, err := pagination.ParsePageToken(req.PageToken)
+    if err != nil {
+        return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+    }
+
+    entries, next, err := audit.Page(ctx, s.db, req.EntityType, req.EntityId, after, size)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.GetAuditLogResponse{NextPageToken: next.String(), Entries: make([]*pb.AuditLogEntry, len(entries))}
+    for i := range entries {
+        res.Entries[i] = auditEntryToProto(&entries[i])
+    }
+    return res, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+func TestGetAuditLog(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &auditServer{db: db}
+    ctx := context.Background()
+
+    if _, err := s.GetAuditLog(ctx, &pb.GetAuditLogRequest{EntityId: "7"}); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("entity_id without entity_type = %v, want InvalidArgument", err)
+    }
+
+    at := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+    mock.ExpectQuery(`SELECT \* FROM "audit_logs" WHERE entity_type = \$1 AND entity_id = \$2 ORDER BY id LIMIT 2`).
+        WithArgs("user", "7").
+        WillReturnRows(sqlmock.NewRows([]string{"id", "action", "entity_type", "entity_id", "actor", "timestamp", "details"}).
+            AddRow(3, "set_preference", "user", "7", "key:0123456789ab", at, `{"key":"theme"}`).
+            AddRow(5, "set_preference", "user", "7", "anonymous", at, `{}`))
+    res, err := s.GetAuditLog(ctx, &pb.GetAuditLogRequest{EntityType: "user", EntityId: "7", PageSize: 1})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Entries) != 1 || res.NextPageToken == "" {
+        t.Fatalf("got %d entries and next page %q, want 1 entry and a next page", len(res.Entries), res.NextPageToken)
+    }
+    if e := res.Entries[0]; e.Id != "3" || e.Actor != "key:0123456789ab" || e.DetailsJson != `{"key":"theme"}` || !e.Timestamp.AsTime().Equal(at) {
+        t.Errorf("entry = %v", e)
+    }
+}
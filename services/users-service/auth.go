@@ -0,0 +1,242 @@
+package main
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/reflection/grpc_reflection_v1"
+    "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+    pbv2 "users-service/proto/gen/proto/v2"
+)
+
+// apiKeyHeader is the metadata key callers put their API key in.
+const apiKeyHeader = "x-api-key"
+
+type role int
+
+const (
+    roleReadOnly role = iota + 1
+    roleReadWrite
+    roleAdmin
+)
+
+var roleNames = map[string]role{
+    "readonly":  roleReadOnly,
+    "readwrite": roleReadWrite,
+    "admin":     roleAdmin,
+}
+
+func (r role) String() string {
+    for name, value := range roleNames {
+        if value == r {
+            return name
+        }
+    }
+    return "none"
+}
+
+// methodPolicies lists the minimum role needed for every RPC. Methods that
+// are not listed are denied.
+var methodPolicies = map[string]role{
+    pb.UserService_CreateUser_FullMethodName:                    roleReadWrite,
+    pb.UserService_GetUser_FullMethodName:                       roleReadOnly,
+    pb.UserService_BatchGetUsers_FullMethodName:                 roleReadOnly,
+    pb.UserService_SetPreference_FullMethodName:                 roleReadWrite,
+    pb.UserService_GetPreferences_FullMethodName:                roleReadOnly,
+    pb.UserService_ListUsers_FullMethodName:                     roleAdmin,
+    pb.UserService_LinkSocialAccount_FullMethodName:             roleReadWrite,
+    pb.UserService_UnlinkSocialAccount_FullMethodName:           roleReadWrite,
+    pb.UserService_FindUserBySocialAccount_FullMethodName:       roleReadOnly,
+    pb.UserService_FindDuplicateUsers_FullMethodName:            roleAdmin,
+    pb.UserService_MergeUsers_FullMethodName:                    roleAdmin,
+    pb.UserService_CreateCohort_FullMethodName:                  roleAdmin,
+    pb.UserService_GetUserCohort_FullMethodName:                 roleReadOnly,
+    pb.UserService_ListCohortMembers_FullMethodName:             roleAdmin,
+    pb.UserService_GetCohortStats_FullMethodName:                roleReadOnly,
+    pb.UserService_AddUserAddress_FullMethodName:                roleReadWrite,
+    pb.UserService_GetUserAddresses_FullMethodName:              roleReadOnly,
+    pb.UserService_UpdateUserAddress_FullMethodName:             roleReadWrite,
+    pb.UserService_DeleteUserAddress_FullMethodName:             roleReadWrite,
+    pb.UserService_SetDefaultAddress_FullMethodName:             roleReadWrite,
+    pb.UserService_EnrollTOTP_FullMethodName:                    roleReadWrite,
+    pb.UserService_VerifyTOTP_FullMethodName:                    roleReadWrite,
+    pb.UserService_GetUserTimeline_FullMethodName:               roleAdmin,
+    pb.UserService_RecordConsent_FullMethodName:                 roleReadWrite,
+    pb.UserService_WithdrawConsent_FullMethodName:               roleReadWrite,
+    pb.UserService_GetConsentHistory_FullMethodName:             roleReadOnly,
+    pb.UserService_UpdateCommunicationPreference_FullMethodName: roleReadWrite,
+    pb.UserService_GenerateUnsubscribeToken_FullMethodName:      roleReadWrite,
+    pbv2.UserService_CreateUser_FullMethodName:                  roleReadWrite,
+    pbv2.UserService_GetUser_FullMethodName:                     roleReadOnly,
+    pb.SelfTestService_SelfTest_FullMethodName:                  roleAdmin,
+    pb.DrainService_Drain_FullMethodName:                        roleAdmin,
+    pb.BackfillService_ListBackfills_FullMethodName:             roleAdmin,
+    pb.SnapshotService_SnapshotData_FullMethodName:              roleAdmin,
+    pb.SnapshotService_RestoreData_FullMethodName:               roleAdmin,
+    pb.AuditService_GetAuditLog_FullMethodName:                  roleAdmin,
+    pb.DebugService_GetDebugInfo_FullMethodName:                 roleAdmin,
+    pb.DebugService_GetDeploymentInfo_FullMethodName:            roleAdmin,
+    // Reflection lists the v1 and v2 services for tools such as grpcurl.
+    grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName:      roleReadOnly,
+    grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: roleReadOnly,
+}
+
+// publicMethods skip authentication entirely; Consul's health checks do not
+// carry an API key, and UnsubscribeByToken is authorized by its token.
+var publicMethods = map[string]bool{
+    grpc_health_v1.Health_Check_FullMethodName:       true,
+    grpc_health_v1.Health_Watch_FullMethodName:       true,
+    pb.UserService_UnsubscribeByToken_FullMethodName: true,
+}
+
+type roleContextKey struct{}
+
+type actorContextKey struct{}
+
+func roleFromContext(ctx context.Context) (role, bool) {
+    r, ok := ctx.Value(roleContextKey{}).(role)
+    return r, ok
+}
+
+// anonymousActor is the actor of calls made without an API key, which is
+// every call when authentication is disabled.
+const anonymousActor = "anonymous"
+
+// actorFromContext identifies the caller that made a request.
+func actorFromContext(ctx context.Context) string {
+    if actor, ok := ctx.Value(actorContextKey{}).(string); ok {
+        return actor
+    }
+    return anonymousActor
+}
+
+// keyActor identifies an API key without revealing it, so the actor can be
+// stored and logged.
+func keyActor(key string) string {
+    sum := sha256.Sum256([]byte(key))
+    return "key:" + hex.EncodeToString(sum[:])[:12]
+}
+
+type authenticator struct {
+    keys map[string]role
+}
+
+// loadAPIKeys parses API_KEYS_JSON, e.g. {"key1":"admin","key2":"readonly"}.
+// A nil map means authentication is disabled and admin methods are denied.
+func loadAPIKeys() (map[string]role, error) {
+    raw := os.Getenv("API_KEYS_JSON")
+    if raw == "" {
+        return nil, nil
+    }
+
+    var names map[string]string
+    if err := json.Unmarshal([]byte(raw), &names); err != nil {
+        return nil, fmt.Errorf("invalid API_KEYS_JSON: %w", err)
+    }
+
+    keys := make(map[string]role, len(names))
+    for key, name := range names {
+        r, ok := roleNames[name]
+        if !ok {
+            return nil, fmt.Errorf("unknown role %q in API_KEYS_JSON", name)
+        }
+        keys[key] = r
+    }
+    return keys, nil
+}
+
+// authenticate resolves the caller's role and actor from its API key and
+// stores them in the returned context.
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+    if a.keys == nil || publicMethods[method] {
+        return ctx, nil
+    }
+
+    md, _ := metadata.FromIncomingContext(ctx)
+    values := md.Get(apiKeyHeader)
+    if len(values) == 0 {
+        return nil, status.Error(codes.Unauthenticated, "missing API key")
+    }
+    r, ok := a.keys[values[0]]
+    if !ok {
+        return nil, status.Error(codes.Unauthenticated, "invalid API key")
+    }
+    ctx = context.WithValue(ctx, roleContextKey{}, r)
+    return context.WithValue(ctx, actorContextKey{}, keyActor(values[0])), nil
+}
+
+// authorize checks the caller's role against methodPolicies. Without
+// API_KEYS_JSON every caller is anonymous, so admin methods, and methods
+// without a policy, are denied rather than opened to everyone.
+func (a *authenticator) authorize(ctx context.Context, method string) error {
+    if publicMethods[method] {
+        return nil
+    }
+
+    required, ok := methodPolicies[method]
+    if !ok {
+        return status.Errorf(codes.PermissionDenied, "method %s is not permitted", method)
+    }
+    if a.keys == nil {
+        if required >= roleAdmin {
+            return status.Errorf(codes.PermissionDenied, "%s needs an admin API key, and API_KEYS_JSON is not set", method)
+        }
+        return nil
+    }
+    r, _ := roleFromContext(ctx)
+    if r < required {
+        return status.Errorf(codes.PermissionDenied, "role %s may not call %s", r, method)
+    }
+    return nil
+}
+
+func (a *authenticator) unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    ctx, err := a.authenticate(ctx, info.FullMethod)
+    if err != nil {
+        return nil, err
+    }
+    return handler(ctx, req)
+}
+
+func (a *authenticator) unaryAuthzInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    if err := a.authorize(ctx, info.FullMethod); err != nil {
+        return nil, err
+    }
+    return handler(ctx, req)
+}
+
+func (a *authenticator) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+    if err != nil {
+        return err
+    }
+    return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+}
+
+func (a *authenticator) streamAuthzInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+        return err
+    }
+    return handler(srv, ss)
+}
+
+// contextServerStream overrides the context of a wrapped server stream.
+type contextServerStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+    return s.ctx
+}
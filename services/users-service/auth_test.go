@@ -0,0 +1,136 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+    pbv2 "users-service/proto/gen/proto/v2"
+)
+
+func TestAuthorizePolicyMatrix(t *testing.T) {
+    auth := &authenticator{keys: map[string]role{
+        "ro":  roleReadOnly,
+        "rw":  roleReadWrite,
+        "adm": roleAdmin,
+    }}
+    methods := []struct {
+        method string
+        want   role
+    }{
+        {pb.UserService_GetUser_FullMethodName, roleReadOnly},
+        {pb.UserService_BatchGetUsers_FullMethodName, roleReadOnly},
+        {pb.UserService_CreateUser_FullMethodName, roleReadWrite},
+        {pb.UserService_GetPreferences_FullMethodName, roleReadOnly},
+        {pb.UserService_SetPreference_FullMethodName, roleReadWrite},
+        {pb.UserService_ListUsers_FullMethodName, roleAdmin},
+        {pb.UserService_LinkSocialAccount_FullMethodName, roleReadWrite},
+        {pb.UserService_UnlinkSocialAccount_FullMethodName, roleReadWrite},
+        {pb.UserService_FindUserBySocialAccount_FullMethodName, roleReadOnly},
+        {pb.UserService_FindDuplicateUsers_FullMethodName, roleAdmin},
+        {pb.UserService_MergeUsers_FullMethodName, roleAdmin},
+        {pb.UserService_CreateCohort_FullMethodName, roleAdmin},
+        {pb.UserService_GetUserCohort_FullMethodName, roleReadOnly},
+        {pb.UserService_ListCohortMembers_FullMethodName, roleAdmin},
+        {pb.UserService_GetCohortStats_FullMethodName, roleReadOnly},
+        {pb.UserService_AddUserAddress_FullMethodName, roleReadWrite},
+        {pb.UserService_GetUserAddresses_FullMethodName, roleReadOnly},
+        {pb.UserService_UpdateUserAddress_FullMethodName, roleReadWrite},
+        {pb.UserService_DeleteUserAddress_FullMethodName, roleReadWrite},
+        {pb.UserService_SetDefaultAddress_FullMethodName, roleReadWrite},
+        {pb.UserService_EnrollTOTP_FullMethodName, roleReadWrite},
+        {pb.UserService_VerifyTOTP_FullMethodName, roleReadWrite},
+        {pb.UserService_GetUserTimeline_FullMethodName, roleAdmin},
+        {pb.UserService_RecordConsent_FullMethodName, roleReadWrite},
+        {pb.UserService_WithdrawConsent_FullMethodName, roleReadWrite},
+        {pb.UserService_GetConsentHistory_FullMethodName, roleReadOnly},
+        {pb.UserService_UpdateCommunicationPreference_FullMethodName, roleReadWrite},
+        {pb.UserService_GenerateUnsubscribeToken_FullMethodName, roleReadWrite},
+        {pbv2.UserService_GetUser_FullMethodName, roleReadOnly},
+        {pbv2.UserService_CreateUser_FullMethodName, roleReadWrite},
+        {pb.SelfTestService_SelfTest_FullMethodName, roleAdmin},
+        {pb.DrainService_Drain_FullMethodName, roleAdmin},
+        {pb.AuditService_GetAuditLog_FullMethodName, roleAdmin},
+        {pb.DebugService_GetDebugInfo_FullMethodName, roleAdmin},
+        {pb.DebugService_GetDeploymentInfo_FullMethodName, roleAdmin},
+        {pb.BackfillService_ListBackfills_FullMethodName, roleAdmin},
+        {pb.SnapshotService_SnapshotData_FullMethodName, roleAdmin},
+        {pb.SnapshotService_RestoreData_FullMethodName, roleAdmin},
+    }
+    for _, key := range []string{"ro", "rw", "adm"} {
+        ctx, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, key)), "")
+        if err != nil {
+            t.Fatalf("authenticate(%s): %v", key, err)
+        }
+        r, _ := roleFromContext(ctx)
+        for _, m := range methods {
+            err := auth.authorize(ctx, m.method)
+            if allowed := r >= m.want; allowed != (err == nil) {
+                t.Errorf("role %s calling %s: err = %v, want allowed = %t", r, m.method, err, allowed)
+            } else if err != nil && status.Code(err) != codes.PermissionDenied {
+                t.Errorf("role %s calling %s: code = %s, want PermissionDenied", r, m.method, status.Code(err))
+            }
+        }
+        if err := auth.authorize(ctx, "/users.UserService/NoSuchMethod"); status.Code(err) != codes.PermissionDenied {
+            t.Errorf("role %s calling an unlisted method: err = %v, want PermissionDenied", r, err)
+        }
+    }
+}
+
+func TestAuthenticateRejectsBadKeys(t *testing.T) {
+    auth := &authenticator{keys: map[string]role{"good": roleReadOnly}}
+    tests := []struct {
+        name string
+        md   metadata.MD
+    }{
+        {"missing", metadata.MD{}},
+        {"unknown", metadata.Pairs(apiKeyHeader, "bad")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), tt.md), pb.UserService_GetUser_FullMethodName)
+            if status.Code(err) != codes.Unauthenticated {
+                t.Errorf("err = %v, want Unauthenticated", err)
+            }
+        })
+    }
+
+    // Health checks carry no key, and unsubscribe links carry a signed
+    // token instead.
+    for _, method := range []string{grpc_health_v1.Health_Check_FullMethodName, pb.UserService_UnsubscribeByToken_FullMethodName} {
+        ctx, err := auth.authenticate(context.Background(), method)
+        if err == nil {
+            err = auth.authorize(ctx, method)
+        }
+        if err != nil {
+            t.Errorf("%s without a key: %v", method, err)
+        }
+    }
+}
+
+func TestAuthorizeWithoutKeys(t *testing.T) {
+    auth := &authenticator{}
+    tests := []struct {
+        method string
+        want   codes.Code
+    }{
+        {grpc_health_v1.Health_Check_FullMethodName, codes.OK},
+        {pb.UserService_GetUser_FullMethodName, codes.OK},
+        {pb.UserService_CreateUser_FullMethodName, codes.OK},
+        {"/users.UserService/NoSuchMethod", codes.PermissionDenied},
+    }
+    for _, tt := range tests {
+        ctx, err := auth.authenticate(context.Background(), tt.method)
+        if err != nil {
+            t.Fatalf("authenticate(%s): %v", tt.method, err)
+        }
+        if got := status.Code(auth.authorize(ctx, tt.method)); got != tt.want {
+            t.Errorf("%s: code = %s, want %s", tt.method, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    "shared/backfill"
+    pb "users-service/proto/gen/proto"
+)
+
+const (
+    defaultBackfillBatchSize = 500
+    defaultBackfillPause     = 100 * time.Millisecond
+)
+
+// registerBackfills adds the data migrations in progress. Each job's name
+// keys its saved progress, so jobs are never renamed; remove a job only once
+// it is done everywhere.
+func registerBackfills(runner *backfill.Runner) {
+    // users.email_normalized holds the trimmed, lowercased email and is the
+    // step before moving email to citext. Rows created since it was added
+    // have it set by createUser; this fills in the rest.
+    runner.RegisterBackfill("users.email_normalized", "users", func(tx *gorm.DB, ids []uint64) error {
+        return tx.Model(&User{}).Unscoped().
+            Where("id IN ? AND email_normalized IS NULL", ids).
+            UpdateColumn("email_normalized", gorm.Expr("lower(btrim(email))")).Error
+    })
+}
+
+type backfillServer struct {
+    pb.UnimplementedBackfillServiceServer
+    runner *backfill.Runner
+}
+
+// ListBackfills reports the progress of every registered backfill job.
+func (s *backfillServer) ListBackfills(ctx context.Context, req *pb.ListBackfillsRequest) (*pb.ListBackfillsResponse, error) {
+    jobs, err := s.runner.List(ctx)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.ListBackfillsResponse{Backfills: make([]*pb.Backfill, len(jobs))}
+    for i, job := range jobs {
+        res.Backfills[i] = &pb.Backfill{
+            Name:      job.Name,
+            Table:     job.Table,
+            LastId:    job.LastID,
+            Processed: job.Processed,
+            Done:      job.Done,
+        }
+        if !job.StartedAt.IsZero() {
+            res.Backfills[i].StartedAt = timestamppb.New(job.StartedAt)
+            res.Backfills[i].UpdatedAt = timestamppb.New(job.UpdatedAt)
+        }
+        if job.CompletedAt != nil {
+            res.Backfills[i].CompletedAt = timestamppb.New(*job.CompletedAt)
+        }
+    }
+    return res, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/jackc/pgx/v5/pgconn"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    "shared/pagination"
+    pb "users-service/proto/gen/proto"
+)
+
+// SQLSTATEs for the constraints CreateCohort can violate.
+const (
+    uniqueViolation    = "23505"
+    exclusionViolation = "23P01"
+)
+
+// Cohort groups the users who registered in [StartDate, EndDate). Cohorts do
+// not overlap, so every user is in at most one.
+type Cohort struct {
+    ID        uint      `gorm:"primaryKey"`
+    Name      string    `gorm:"not null;unique"`
+    StartDate time.Time `gorm:"not null"`
+    EndDate   time.Time `gorm:"not null"`
+    CreatedAt time.Time
+}
+
+func (c *Cohort) toProto() *pb.Cohort {
+    return &pb.Cohort{
+        Id:        fmt.Sprint(c.ID),
+        Name:      c.Name,
+        StartDate: timestamppb.New(c.StartDate),
+        EndDate:   timestamppb.New(c.EndDate),
+    }
+}
+
+// migrateCohorts keeps cohorts from overlapping and indexes users by
+// registration date, which cohort membership is looked up by. It must run
+// after User and Cohort are migrated.
+func migrateCohorts(db *gorm.DB) error {
+    statements := []string{
+        `DO $$
+        BEGIN
+            IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'cohorts_no_overlap') THEN
+                ALTER TABLE cohorts ADD CONSTRAINT cohorts_no_overlap
+                    EXCLUDE USING gist (tstzrange(start_date, end_date) WITH &&);
+            END IF;
+        END
+        $$`,
+        `CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at, id)`,
+    }
+    for _, statement := range statements {
+        if err := db.Exec(statement).Error; err != nil {
+            return fmt.Errorf("migrate cohorts: %w", err)
+        }
+    }
+    return nil
+}
+
+// CreateCohort defines a cohort. It may not overlap an existing one.
+func (s *server) CreateCohort(ctx context.Context, req *pb.CreateCohortRequest) (*pb.CohortResponse, error) {
+    name := strings.TrimSpace(req.Name)
+    if name == "" {
+        return nil, status.Error(codes.InvalidArgument, "name is required")
+    }
+    if req.StartDate == nil || req.EndDate == nil {
+        return nil, status.Error(codes.InvalidArgument, "start_date and end_date are required")
+    }
+    cohort := Cohort{Name: name, StartDate: req.StartDate.AsTime(), EndDate: req.EndDate.AsTime()}
+    if !cohort.StartDate.Before(cohort.EndDate) {
+        return nil, status.Error(codes.InvalidArgument, "start_date must be before end_date")
+    }
+
+    err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&cohort).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "create", "cohort", cohort.ID, map[string]interface{}{
+            "name":       cohort.Name,
+            "start_date": cohort.StartDate,
+            "end_date":   cohort.EndDate,
+        })
+    })
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) {
+        switch pgErr.Code {
+        case uniqueViolation:
+            return nil, status.Errorf(codes.AlreadyExists, "cohort %q already exists", name)
+        case exclusionViolation:
+            return nil, status.Error(codes.FailedPrecondition, "cohort overlaps an existing cohort")
+        }
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &pb.CohortResponse{Cohort: cohort.toProto()}, nil
+}
+
+// findCohort loads the cohort with the given id, as a status error if there
+// is none.
+func (s *server) findCohort(ctx context.Context, id string) (*Cohort, error) {
+    cohortID, err := strconv.ParseUint(id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid cohort id %q", id)
+    }
+    var cohort Cohort
+    if err := s.db.WithContext(ctx).First(&cohort, cohortID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "cohort %s not found", id)
+        }
+        return nil, err
+    }
+    return &cohort, nil
+}
+
+// cohortMembers filters users to the cohort's members.
+func (s *server) cohortMembers(ctx context.Context, cohort *Cohort) *gorm.DB {
+    return s.db.WithContext(ctx).Model(&User{}).Where("created_at >= ? AND created_at < ?", cohort.StartDate, cohort.EndDate)
+}
+
+// GetUserCohort returns the cohort the user registered in.
+func (s *server) GetUserCohort(ctx context.Context, req *pb.GetUserCohortRequest) (*pb.GetUserCohortResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var cohort Cohort
+    err = s.db.WithContext(ctx).Where("start_date <= ? AND end_date > ?", user.CreatedAt, user.CreatedAt).Take(&cohort).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, status.Errorf(codes.NotFound, "no cohort covers the registration of user %s", req.UserId)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &pb.GetUserCohortResponse{
+        CohortId:    fmt.Sprint(cohort.ID),
+        CohortName:  cohort.Name,
+        CohortStart: timestamppb.New(cohort.StartDate),
+        CohortEnd:   timestamppb.New(cohort.EndDate),
+    }, nil
+}
+
+// ListCohortMembers pages through a cohort's users in order of registration.
+func (s *server) ListCohortMembers(ctx context.Context, req *pb.ListCohortMembersRequest) (*pb.ListCohortMembersResponse, error) {
+    pageSize, err := s.clampPageSize(req.PageSize)
+    if err != nil {
+        return nil, err
+    }
+    after, err := pagination.ParsePageToken(req.PageToken)
+    if err != nil {
+        return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+    }
+    cohort, err := s.findCohort(ctx, req.CohortId)
+    if err != nil {
+        return nil, err
+    }
+
+    var users []User
+    next, _, err := pagination.NewCursorPaginator(s.cohortMembers(ctx, cohort), "created_at").Page(ctx, &users, after, pageSize)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.ListCohortMembersResponse{NextPageToken: next.String(), Users: make([]*pb.User, len(users))}
+    for i, u := range users {
+        res.Users[i] = &pb.User{Id: fmt.Sprint(u.ID), Name: u.Name, Email: u.Email}
+    }
+    return res, nil
+}
+
+// GetCohortStats reports how many users are in a cohort. Order counts, spend
+// and retention need order data, which no service tracks yet.
+func (s *server) GetCohortStats(ctx context.Context, req *pb.GetCohortStatsRequest) (*pb.GetCohortStatsResponse, error) {
+    cohort, err := s.findCohort(ctx, req.CohortId)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.GetCohortStatsResponse{}
+    if err := s.cohortMembers(ctx, cohort).Count(&res.Size).Error; err != nil {
+        return nil, err
+    }
+    return res, nil
+}
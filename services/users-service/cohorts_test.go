@@ -0,0 +1,156 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/jackc/pgx/v5/pgconn"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    "shared/audit"
+    "shared/testdb"
+    pb "users-service/proto/gen/proto"
+)
+
+func cohortRequest(name string, start, end time.Time) *pb.CreateCohortRequest {
+    return &pb.CreateCohortRequest{Name: name, StartDate: timestamppb.New(start), EndDate: timestamppb.New(end)}
+}
+
+var (
+    q1 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    q2 = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+    q3 = time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestCreateCohortRejectsBadRequests(t *testing.T) {
+    // Validation happens before the database is touched.
+    s := &server{}
+    for name, req := range map[string]*pb.CreateCohortRequest{
+        "no name":   cohortRequest(" ", q1, q2),
+        "no start":  {Name: "Q1", EndDate: timestamppb.New(q2)},
+        "no end":    {Name: "Q1", StartDate: timestamppb.New(q1)},
+        "empty":     cohortRequest("Q1", q1, q1),
+        "backwards": cohortRequest("Q1", q2, q1),
+    } {
+        if _, err := s.CreateCohort(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: CreateCohort = %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestCreateCohortRecordsAudit(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "cohorts"`).
+        WithArgs("2024 Q1", q1, q2, sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+    expectAudit(mock, "create", "cohort")
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).CreateCohort(context.Background(), cohortRequest(" 2024 Q1 ", q1, q2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Cohort.Id != "7" || res.Cohort.Name != "2024 Q1" || !res.Cohort.EndDate.AsTime().Equal(q2) {
+        t.Errorf("created %v", res.Cohort)
+    }
+}
+
+func TestCreateCohortReportsConstraintViolations(t *testing.T) {
+    for sqlState, want := range map[string]codes.Code{
+        uniqueViolation:    codes.AlreadyExists,
+        exclusionViolation: codes.FailedPrecondition,
+    } {
+        db, mock := newMockDB(t)
+        mock.ExpectBegin()
+        mock.ExpectQuery(`INSERT INTO "cohorts"`).WillReturnError(&pgconn.PgError{Code: sqlState})
+        mock.ExpectRollback()
+        if _, err := (&server{db: db}).CreateCohort(context.Background(), cohortRequest("Q1", q1, q2)); status.Code(err) != want {
+            t.Errorf("SQLSTATE %s: CreateCohort = %v, want %v", sqlState, err, want)
+        }
+    }
+}
+
+func TestCohortsWithDatabase(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&User{}, &Cohort{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateCohorts(db); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db, maxPageSize: 100}
+    ctx := context.Background()
+
+    // Users register at the start of each month of 2024 up to August.
+    userIDs := make([]string, 8)
+    for i := range userIDs {
+        user := User{Name: "User", Email: time.Month(i+1).String() + "@example.com"}
+        user.CreatedAt = time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+        if err := db.Create(&user).Error; err != nil {
+            t.Fatal(err)
+        }
+        userIDs[i] = fmt.Sprint(user.ID)
+    }
+
+    first, err := s.CreateCohort(ctx, cohortRequest("2024 Q1", q1, q2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := s.CreateCohort(ctx, cohortRequest("2024 Q2", q2, q3)); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := s.CreateCohort(ctx, cohortRequest("Spring", q1.AddDate(0, 2, 0), q2.AddDate(0, 1, 0))); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("overlapping cohort = %v, want FailedPrecondition", err)
+    }
+    if _, err := s.CreateCohort(ctx, cohortRequest("2024 Q1", q3, q3.AddDate(0, 3, 0))); status.Code(err) != codes.AlreadyExists {
+        t.Errorf("cohort with a taken name = %v, want AlreadyExists", err)
+    }
+
+    // April 1st starts Q2, not ends Q1; July is in no cohort.
+    for i, want := range map[int]string{0: "2024 Q1", 2: "2024 Q1", 3: "2024 Q2", 5: "2024 Q2"} {
+        res, err := s.GetUserCohort(ctx, &pb.GetUserCohortRequest{UserId: userIDs[i]})
+        if err != nil || res.CohortName != want {
+            t.Errorf("cohort of the user registered in %v = %v (%v), want %s", time.Month(i+1), res, err, want)
+        }
+    }
+    if _, err := s.GetUserCohort(ctx, &pb.GetUserCohortRequest{UserId: userIDs[6]}); status.Code(err) != codes.NotFound {
+        t.Errorf("cohort of a user in no cohort = %v, want NotFound", err)
+    }
+
+    var members []string
+    req := &pb.ListCohortMembersRequest{CohortId: first.Cohort.Id, PageSize: 2}
+    for pages := 0; ; pages++ {
+        if pages > 2 {
+            t.Fatal("ListCohortMembers did not finish after 3 pages")
+        }
+        res, err := s.ListCohortMembers(ctx, req)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, u := range res.Users {
+            members = append(members, u.Id)
+        }
+        if res.NextPageToken == "" {
+            break
+        }
+        req.PageToken = res.NextPageToken
+    }
+    if want := userIDs[:3]; !slices.Equal(members, want) {
+        t.Errorf("Q1 members = %v, want %v", members, want)
+    }
+
+    stats, err := s.GetCohortStats(ctx, &pb.GetCohortStatsRequest{CohortId: first.Cohort.Id})
+    if err != nil || stats.Size != 3 {
+        t.Errorf("Q1 stats = %v (%v), want size 3", stats, err)
+    }
+    if _, err := s.GetCohortStats(ctx, &pb.GetCohortStatsRequest{CohortId: "999"}); status.Code(err) != codes.NotFound {
+        t.Errorf("stats of a missing cohort = %v, want NotFound", err)
+    }
+}
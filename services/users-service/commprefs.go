@@ -0,0 +1,237 @@
+package main
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// unsubscribeTokenLifetime is how long an unsubscribe link keeps working.
+const unsubscribeTokenLifetime = 365 * 24 * time.Hour
+
+// minUnsubscribeSecretLength is the shortest UNSUBSCRIBE_TOKEN_SECRET
+// accepted, the size of an HMAC-SHA256 key's output.
+const minUnsubscribeSecretLength = 32
+
+var (
+    communicationChannels = map[string]bool{"EMAIL": true, "SMS": true, "PUSH": true}
+    communicationTypes    = map[string]bool{"marketing": true, "transactional": true, "alerts": true}
+)
+
+// UserCommunicationPreference records whether a user wants one type of
+// message on one channel. Users without a row are subscribed.
+// UnsubscribeToken is the token last generated for it, kept for reference:
+// tokens are verified by their signature, so older ones stay valid.
+// Subscribed has no GORM default, which GORM would write in place of false.
+type UserCommunicationPreference struct {
+    UserID           uint   `gorm:"primaryKey"`
+    Channel          string `gorm:"primaryKey;type:varchar(8)"`
+    Type             string `gorm:"primaryKey;type:varchar(16)"`
+    Subscribed       bool   `gorm:"not null"`
+    UnsubscribeToken string `gorm:"not null;default:''"`
+    UpdatedAt        time.Time
+}
+
+func (p *UserCommunicationPreference) toProto() *pb.CommunicationPreference {
+    return &pb.CommunicationPreference{
+        UserId:     fmt.Sprint(p.UserID),
+        Channel:    p.Channel,
+        Type:       p.Type,
+        Subscribed: p.Subscribed,
+        UpdatedAt:  timestamppb.New(p.UpdatedAt),
+    }
+}
+
+func validateCommunicationKind(channel, kind string) error {
+    if !communicationChannels[channel] {
+        return status.Errorf(codes.InvalidArgument, "unknown channel %q", channel)
+    }
+    if !communicationTypes[kind] {
+        return status.Errorf(codes.InvalidArgument, "unknown communication type %q", kind)
+    }
+    return nil
+}
+
+// loadUnsubscribeSecret reads the key unsubscribe tokens are signed with.
+// Tokens are disabled when it is nil.
+func loadUnsubscribeSecret() []byte {
+    value := os.Getenv("UNSUBSCRIBE_TOKEN_SECRET")
+    if value == "" {
+        return nil
+    }
+    if len(value) < minUnsubscribeSecretLength {
+        log.Fatalf("UNSUBSCRIBE_TOKEN_SECRET must be at least %d bytes", minUnsubscribeSecretLength)
+    }
+    return []byte(value)
+}
+
+// unsubscribeToken is what an unsubscribe token says.
+type unsubscribeToken struct {
+    userID   uint
+    channel  string
+    kind     string
+    issuedAt time.Time
+}
+
+// sign encodes t as "{payload}.{mac}", where the payload is
+// "{user_id}:{channel}:{type}:{unix time}" and the mac is its HMAC-SHA256
+// under secret, both base64url-encoded so the token fits in a URL.
+func (t unsubscribeToken) sign(secret []byte) string {
+    payload := fmt.Sprintf("%d:%s:%s:%d", t.userID, t.channel, t.kind, t.issuedAt.Unix())
+    mac := hmac.New(sha256.New, secret)
+    mac.Write([]byte(payload))
+    return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyUnsubscribeToken checks a token's signature and age and returns what
+// it says. Every failure is reported alike, so callers cannot probe which
+// part of a forged token was wrong.
+func verifyUnsubscribeToken(secret []byte, token string, now time.Time) (unsubscribeToken, error) {
+    invalid := status.Error(codes.InvalidArgument, "invalid or expired unsubscribe token")
+    encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+    if !ok {
+        return unsubscribeToken{}, invalid
+    }
+    payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+    if err != nil {
+        return unsubscribeToken{}, invalid
+    }
+    got, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+    if err != nil {
+        return unsubscribeToken{}, invalid
+    }
+    mac := hmac.New(sha256.New, secret)
+    mac.Write(payload)
+    if !hmac.Equal(got, mac.Sum(nil)) {
+        return unsubscribeToken{}, invalid
+    }
+
+    fields := strings.Split(string(payload), ":")
+    if len(fields) != 4 {
+        return unsubscribeToken{}, invalid
+    }
+    userID, err := strconv.ParseUint(fields[0], 10, 64)
+    if err != nil {
+        return unsubscribeToken{}, invalid
+    }
+    issued, err := strconv.ParseInt(fields[3], 10, 64)
+    if err != nil {
+        return unsubscribeToken{}, invalid
+    }
+    t := unsubscribeToken{userID: uint(userID), channel: fields[1], kind: fields[2], issuedAt: time.Unix(issued, 0)}
+    if now.Sub(t.issuedAt) > unsubscribeTokenLifetime || t.issuedAt.After(now.Add(time.Minute)) {
+        return unsubscribeToken{}, invalid
+    }
+    return t, nil
+}
+
+// setCommunicationPreference subscribes or unsubscribes a user and records
+// the change in the audit log.
+func setCommunicationPreference(ctx context.Context, tx *gorm.DB, userID uint, channel, kind string, subscribed bool) (*UserCommunicationPreference, error) {
+    pref := UserCommunicationPreference{UserID: userID, Channel: channel, Type: kind, Subscribed: subscribed}
+    err := tx.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}, {Name: "type"}},
+        DoUpdates: clause.AssignmentColumns([]string{"subscribed", "updated_at"}),
+    }).Create(&pref).Error
+    if err != nil {
+        return nil, err
+    }
+    if err := tx.Where("user_id = ? AND channel = ? AND type = ?", userID, channel, kind).Take(&pref).Error; err != nil {
+        return nil, err
+    }
+    return &pref, recordAudit(ctx, tx, "set_communication_preference", "user", userID, map[string]interface{}{
+        "channel":    channel,
+        "type":       kind,
+        "subscribed": subscribed,
+    })
+}
+
+// UpdateCommunicationPreference subscribes a user to, or unsubscribes them
+// from, one type of message on one channel.
+func (s *server) UpdateCommunicationPreference(ctx context.Context, req *pb.UpdateCommunicationPreferenceRequest) (*pb.UpdateCommunicationPreferenceResponse, error) {
+    if err := validateCommunicationKind(req.Channel, req.Type); err != nil {
+        return nil, err
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var pref *UserCommunicationPreference
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        pref, err = setCommunicationPreference(ctx, tx, user.ID, req.Channel, req.Type, req.Subscribed)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UpdateCommunicationPreferenceResponse{Preference: pref.toProto()}, nil
+}
+
+// GenerateUnsubscribeToken returns a token for the unsubscribe link of a
+// message, valid for a year. It does not change the user's preference.
+func (s *server) GenerateUnsubscribeToken(ctx context.Context, req *pb.GenerateUnsubscribeTokenRequest) (*pb.GenerateUnsubscribeTokenResponse, error) {
+    if s.unsubscribeSecret == nil {
+        return nil, status.Error(codes.FailedPrecondition, "unsubscribe tokens are not configured")
+    }
+    if err := validateCommunicationKind(req.Channel, req.Type); err != nil {
+        return nil, err
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    token := unsubscribeToken{userID: user.ID, channel: req.Channel, kind: req.Type, issuedAt: time.Now()}.sign(s.unsubscribeSecret)
+    pref := UserCommunicationPreference{UserID: user.ID, Channel: req.Channel, Type: req.Type, Subscribed: true, UnsubscribeToken: token}
+    err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}, {Name: "type"}},
+        DoUpdates: clause.AssignmentColumns([]string{"unsubscribe_token"}),
+    }).Create(&pref).Error
+    if err != nil {
+        return nil, err
+    }
+    return &pb.GenerateUnsubscribeTokenResponse{Token: token}, nil
+}
+
+// UnsubscribeByToken unsubscribes the user a token was generated for. It is
+// a public method: the token's signature authorizes the call, so a user can
+// unsubscribe from a link in their inbox without signing in. Using a token
+// again is harmless.
+func (s *server) UnsubscribeByToken(ctx context.Context, req *pb.UnsubscribeByTokenRequest) (*pb.UnsubscribeByTokenResponse, error) {
+    if s.unsubscribeSecret == nil {
+        return nil, status.Error(codes.FailedPrecondition, "unsubscribe tokens are not configured")
+    }
+    token, err := verifyUnsubscribeToken(s.unsubscribeSecret, req.Token, time.Now())
+    if err != nil {
+        return nil, err
+    }
+    if err := validateCommunicationKind(token.channel, token.kind); err != nil {
+        return nil, err
+    }
+    user, err := s.findUser(ctx, fmt.Sprint(token.userID))
+    if err != nil {
+        return nil, err
+    }
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        _, err := setCommunicationPreference(ctx, tx, user.ID, token.channel, token.kind, false)
+        return err
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UnsubscribeByTokenResponse{Channel: token.channel, Type: token.kind}, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+var testUnsubscribeSecret = bytes.Repeat([]byte("s"), minUnsubscribeSecretLength)
+
+// signPayload signs an arbitrary payload the way unsubscribeToken.sign does,
+// to make tokens that are authentic but malformed.
+func signPayload(secret []byte, payload string) string {
+    mac := hmac.New(sha256.New, secret)
+    mac.Write([]byte(payload))
+    return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestUnsubscribeTokenRoundTrip(t *testing.T) {
+    issued := time.Unix(1700000000, 0)
+    token := unsubscribeToken{userID: 42, channel: "EMAIL", kind: "marketing", issuedAt: issued}.sign(testUnsubscribeSecret)
+    got, err := verifyUnsubscribeToken(testUnsubscribeSecret, token, issued.Add(time.Hour))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.userID != 42 || got.channel != "EMAIL" || got.kind != "marketing" || !got.issuedAt.Equal(issued) {
+        t.Errorf("verifyUnsubscribeToken = %+v", got)
+    }
+    if strings.ContainsAny(token, "+/=") {
+        t.Errorf("token %q is not URL safe", token)
+    }
+}
+
+func TestUnsubscribeTokenTampering(t *testing.T) {
+    now := time.Unix(1700000000, 0)
+    token := unsubscribeToken{userID: 42, channel: "EMAIL", kind: "marketing", issuedAt: now}.sign(testUnsubscribeSecret)
+    payload, mac, _ := strings.Cut(token, ".")
+    macBytes, _ := base64.RawURLEncoding.DecodeString(mac)
+    macBytes[0] ^= 1
+
+    tests := map[string]string{
+        "other user":          base64.RawURLEncoding.EncodeToString([]byte("43:EMAIL:marketing:1700000000")) + "." + mac,
+        "other channel":       base64.RawURLEncoding.EncodeToString([]byte("42:SMS:marketing:1700000000")) + "." + mac,
+        "renewed":             base64.RawURLEncoding.EncodeToString([]byte("42:EMAIL:marketing:1800000000")) + "." + mac,
+        "flipped mac bit":     payload + "." + base64.RawURLEncoding.EncodeToString(macBytes),
+        "truncated mac":       payload + "." + mac[:len(mac)-2],
+        "no mac":              payload,
+        "empty mac":           payload + ".",
+        "padded base64":       payload + "." + mac + "=",
+        "other secret":        unsubscribeToken{userID: 42, channel: "EMAIL", kind: "marketing", issuedAt: now}.sign(bytes.Repeat([]byte("t"), minUnsubscribeSecretLength)),
+        "empty":               "",
+        "signed, too few":     signPayload(testUnsubscribeSecret, "42:EMAIL:1700000000"),
+        "signed, too many":    signPayload(testUnsubscribeSecret, "42:EMAIL:marketing:x:1700000000"),
+        "signed, bad user id": signPayload(testUnsubscribeSecret, "bob:EMAIL:marketing:1700000000"),
+        "signed, bad time":    signPayload(testUnsubscribeSecret, "42:EMAIL:marketing:yesterday"),
+    }
+    for name, token := range tests {
+        t.Run(name, func(t *testing.T) {
+            _, err := verifyUnsubscribeToken(testUnsubscribeSecret, token, now)
+            // Every failure looks the same to the caller.
+            if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != "invalid or expired unsubscribe token" {
+                t.Errorf("err = %v, want the invalid token error", err)
+            }
+        })
+    }
+}
+
+func TestUnsubscribeTokenExpiry(t *testing.T) {
+    issued := time.Unix(1700000000, 0)
+    token := unsubscribeToken{userID: 42, channel: "EMAIL", kind: "alerts", issuedAt: issued}.sign(testUnsubscribeSecret)
+    tests := []struct {
+        name  string
+        now   time.Time
+        valid bool
+    }{
+        {"just issued", issued, true},
+        {"a day before expiry", issued.Add(unsubscribeTokenLifetime - 24*time.Hour), true},
+        {"at expiry", issued.Add(unsubscribeTokenLifetime), true},
+        {"a second after expiry", issued.Add(unsubscribeTokenLifetime + time.Second), false},
+        {"two years on", issued.Add(2 * unsubscribeTokenLifetime), false},
+        // A little clock skew between instances is tolerated, a token
+        // from the future is not.
+        {"issued 30s ahead", issued.Add(-30 * time.Second), true},
+        {"issued an hour ahead", issued.Add(-time.Hour), false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := verifyUnsubscribeToken(testUnsubscribeSecret, token, tt.now)
+            if valid := err == nil; valid != tt.valid {
+                t.Errorf("valid = %v (%v), want %v", valid, err, tt.valid)
+            }
+        })
+    }
+}
+
+func TestUnsubscribeByToken(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, unsubscribeSecret: testUnsubscribeSecret}
+    token := unsubscribeToken{userID: 42, channel: "SMS", kind: "marketing", issuedAt: time.Now()}.sign(testUnsubscribeSecret)
+
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WithArgs(42).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(42, "pema@example.com"))
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO "user_communication_preferences" .* ON CONFLICT \("user_id","channel","type"\) DO UPDATE SET "subscribed"="excluded"."subscribed"`).
+        WithArgs(42, "SMS", "marketing", false, "", sqlmock.AnyArg()).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`SELECT \* FROM "user_communication_preferences" WHERE \(user_id = \$1 AND channel = \$2 AND type = \$3\)`).
+        WillReturnRows(sqlmock.NewRows([]string{"user_id", "channel", "type", "subscribed"}).AddRow(42, "SMS", "marketing", false))
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("set_communication_preference", "user", "42", sqlmock.AnyArg(), sqlmock.AnyArg(), `{"channel":"SMS","subscribed":false,"type":"marketing"}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.UnsubscribeByToken(context.Background(), &pb.UnsubscribeByTokenRequest{Token: token})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Channel != "SMS" || res.Type != "marketing" {
+        t.Errorf("UnsubscribeByToken = %v, want SMS marketing", res)
+    }
+}
+
+func TestUnsubscribeByTokenRejected(t *testing.T) {
+    // Rejected tokens never reach the database.
+    db, _ := newMockDB(t)
+    s := &server{db: db, unsubscribeSecret: testUnsubscribeSecret}
+    expired := unsubscribeToken{userID: 42, channel: "EMAIL", kind: "marketing", issuedAt: time.Now().Add(-unsubscribeTokenLifetime - time.Hour)}.sign(testUnsubscribeSecret)
+    unknownType := unsubscribeToken{userID: 42, channel: "EMAIL", kind: "newsletter", issuedAt: time.Now()}.sign(testUnsubscribeSecret)
+    for name, token := range map[string]string{"expired": expired, "unknown type": unknownType, "garbage": "not-a-token"} {
+        if _, err := s.UnsubscribeByToken(context.Background(), &pb.UnsubscribeByTokenRequest{Token: token}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: err = %v, want InvalidArgument", name, err)
+        }
+    }
+
+    s.unsubscribeSecret = nil
+    if _, err := s.UnsubscribeByToken(context.Background(), &pb.UnsubscribeByTokenRequest{Token: expired}); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("without a secret: err = %v, want FailedPrecondition", err)
+    }
+}
+
+func TestGenerateUnsubscribeToken(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, unsubscribeSecret: testUnsubscribeSecret}
+    mock.ExpectQuery(`SELECT \* FROM "users"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(42, "pema@example.com"))
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO "user_communication_preferences" .* ON CONFLICT \("user_id","channel","type"\) DO UPDATE SET "unsubscribe_token"="excluded"."unsubscribe_token"`).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    res, err := s.GenerateUnsubscribeToken(context.Background(), &pb.GenerateUnsubscribeTokenRequest{UserId: "42", Channel: "PUSH", Type: "alerts"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    token, err := verifyUnsubscribeToken(testUnsubscribeSecret, res.Token, time.Now())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if token.userID != 42 || token.channel != "PUSH" || token.kind != "alerts" {
+        t.Errorf("token says %+v, want user 42, PUSH alerts", token)
+    }
+
+    if _, err := s.GenerateUnsubscribeToken(context.Background(), &pb.GenerateUnsubscribeTokenRequest{UserId: "42", Channel: "FAX", Type: "alerts"}); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("unknown channel: err = %v, want InvalidArgument", err)
+    }
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// defaultConsentTypes are the consent types accepted when CONSENT_TYPES is
+// not set.
+var defaultConsentTypes = []string{"marketing_emails", "analytics_tracking", "third_party_sharing"}
+
+// maxConsentVersionLength caps the version of the terms a consent is for.
+const maxConsentVersionLength = 64
+
+// UserConsent records that a user agreed to a version of the terms for one
+// kind of processing. Consents are never deleted: withdrawing one sets
+// WithdrawnAt, so the history can be shown to an auditor. IPAddress is
+// checked when recorded but stored as text, since it may be empty.
+type UserConsent struct {
+    ID          uint      `gorm:"primaryKey"`
+    UserID      uint      `gorm:"not null;index"`
+    ConsentType string    `gorm:"not null"`
+    Version     string    `gorm:"not null"`
+    ConsentedAt time.Time `gorm:"not null"`
+    IPAddress   string    `gorm:"not null;default:''"`
+    WithdrawnAt *time.Time
+}
+
+func (c *UserConsent) toProto() *pb.UserConsent {
+    consent := &pb.UserConsent{
+        Id:          fmt.Sprint(c.ID),
+        UserId:      fmt.Sprint(c.UserID),
+        ConsentType: c.ConsentType,
+        Version:     c.Version,
+        ConsentedAt: timestamppb.New(c.ConsentedAt),
+        IpAddress:   c.IPAddress,
+    }
+    if c.WithdrawnAt != nil {
+        consent.WithdrawnAt = timestamppb.New(*c.WithdrawnAt)
+    }
+    return consent
+}
+
+// loadConsentTypes reads the accepted consent types from CONSENT_TYPES, a
+// comma-separated list.
+func loadConsentTypes() map[string]bool {
+    names := getEnvList("CONSENT_TYPES")
+    if len(names) == 0 {
+        names = defaultConsentTypes
+    }
+    types := make(map[string]bool, len(names))
+    for _, name := range names {
+        types[name] = true
+    }
+    return types
+}
+
+// RecordConsent records the user's consent. Recording a consent the user has
+// already given, and not withdrawn, returns the existing record, so clients
+// may safely retry.
+func (s *server) RecordConsent(ctx context.Context, req *pb.RecordConsentRequest) (*pb.RecordConsentResponse, error) {
+    if !s.consentTypes[req.ConsentType] {
+        return nil, status.Errorf(codes.InvalidArgument, "unknown consent type %q", req.ConsentType)
+    }
+    version := strings.TrimSpace(req.Version)
+    if version == "" || len(version) > maxConsentVersionLength {
+        return nil, status.Errorf(codes.InvalidArgument, "version must be between 1 and %d characters", maxConsentVersionLength)
+    }
+    var ipAddress string
+    if req.IpAddress != "" {
+        ip := net.ParseIP(req.IpAddress)
+        if ip == nil {
+            return nil, status.Errorf(codes.InvalidArgument, "invalid ip_address %q", req.IpAddress)
+        }
+        ipAddress = ip.String()
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+
+    var consent UserConsent
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        // Locking the user serializes concurrent records of the same consent.
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Take(&User{}, user.ID).Error; err != nil {
+            return err
+        }
+        err := tx.Where("user_id = ? AND consent_type = ? AND version = ? AND withdrawn_at IS NULL", user.ID, req.ConsentType, version).
+            Take(&consent).Error
+        if err == nil {
+            return nil
+        }
+        if !errors.Is(err, gorm.ErrRecordNotFound) {
+            return err
+        }
+        consent = UserConsent{
+            UserID:      user.ID,
+            ConsentType: req.ConsentType,
+            Version:     version,
+            ConsentedAt: time.Now(),
+            IPAddress:   ipAddress,
+        }
+        if err := tx.Create(&consent).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "record", "user_consent", consent.ID, map[string]interface{}{
+            "user_id":      user.ID,
+            "consent_type": req.ConsentType,
+            "version":      version,
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.RecordConsentResponse{ConsentId: fmt.Sprint(consent.ID)}, nil
+}
+
+// WithdrawConsent marks a consent withdrawn. Withdrawing it again keeps the
+// original withdrawal time.
+func (s *server) WithdrawConsent(ctx context.Context, req *pb.WithdrawConsentRequest) (*pb.WithdrawConsentResponse, error) {
+    consentID, err := strconv.ParseUint(req.ConsentId, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid consent id %q", req.ConsentId)
+    }
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var consent UserConsent
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&consent, consentID).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return status.Errorf(codes.NotFound, "consent %s not found", req.ConsentId)
+            }
+            return err
+        }
+        if consent.WithdrawnAt != nil {
+            return nil
+        }
+        if err := tx.Model(&consent).Update("withdrawn_at", time.Now()).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "withdraw", "user_consent", consent.ID, map[string]interface{}{
+            "user_id":      consent.UserID,
+            "consent_type": consent.ConsentType,
+            "version":      consent.Version,
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.WithdrawConsentResponse{}, nil
+}
+
+// GetConsentHistory returns every consent the user has given, including
+// withdrawn ones, newest first.
+func (s *server) GetConsentHistory(ctx context.Context, req *pb.GetConsentHistoryRequest) (*pb.GetConsentHistoryResponse, error) {
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var consents []UserConsent
+    if err := s.db.WithContext(ctx).Where("user_id = ?", user.ID).Order("consented_at DESC, id DESC").Find(&consents).Error; err != nil {
+        return nil, err
+    }
+    res := &pb.GetConsentHistoryResponse{Consents: make([]*pb.UserConsent, len(consents))}
+    for i := range consents {
+        res.Consents[i] = consents[i].toProto()
+    }
+    return res, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    "shared/audit"
+    "shared/testdb"
+    pb "users-service/proto/gen/proto"
+)
+
+func TestLoadConsentTypes(t *testing.T) {
+    if types := loadConsentTypes(); len(types) != 3 || !types["marketing_emails"] || !types["analytics_tracking"] || !types["third_party_sharing"] {
+        t.Errorf("default consent types = %v", types)
+    }
+    t.Setenv("CONSENT_TYPES", "newsletter, profiling")
+    if types := loadConsentTypes(); len(types) != 2 || !types["newsletter"] || !types["profiling"] {
+        t.Errorf("CONSENT_TYPES=newsletter, profiling gave %v", types)
+    }
+}
+
+func TestRecordConsentRejectsBadRequests(t *testing.T) {
+    // The mock has no expectations, so reaching the database fails the test.
+    db, _ := newMockDB(t)
+    s := &server{db: db, consentTypes: loadConsentTypes()}
+    for name, req := range map[string]*pb.RecordConsentRequest{
+        "unknown type": {UserId: "1", ConsentType: "telepathy", Version: "v1"},
+        "no version":   {UserId: "1", ConsentType: "marketing_emails", Version: " "},
+        "long version": {UserId: "1", ConsentType: "marketing_emails", Version: strings.Repeat("1", maxConsentVersionLength+1)},
+        "bad address":  {UserId: "1", ConsentType: "marketing_emails", Version: "v1", IpAddress: "999.1.1.1"},
+    } {
+        if _, err := s.RecordConsent(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("%s: %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+// expectConsentLookup expects RecordConsent to find user 1, lock it and look
+// for an active consent like the one being recorded.
+func expectConsentLookup(mock sqlmock.Sqlmock, existing *sqlmock.Rows) {
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Ada", "ada@example.com"))
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT "id" FROM "users" WHERE "users"."id" = \$1 .*FOR UPDATE`).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectQuery(`SELECT \* FROM "user_consents" WHERE user_id = \$1 AND consent_type = \$2 AND version = \$3 AND withdrawn_at IS NULL`).
+        WithArgs(1, "marketing_emails", "2024-06").
+        WillReturnRows(existing)
+}
+
+func TestRecordConsentReturnsTheActiveConsent(t *testing.T) {
+    db, mock := newMockDB(t)
+    expectConsentLookup(mock, sqlmock.NewRows([]string{"id", "user_id", "consent_type", "version"}).AddRow(5, 1, "marketing_emails", "2024-06"))
+    mock.ExpectCommit()
+
+    s := &server{db: db, consentTypes: loadConsentTypes()}
+    res, err := s.RecordConsent(context.Background(), &pb.RecordConsentRequest{UserId: "1", ConsentType: "marketing_emails", Version: "2024-06"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.ConsentId != "5" {
+        t.Errorf("consent id = %s, want the existing 5", res.ConsentId)
+    }
+}
+
+func TestRecordConsentCreatesAndAudits(t *testing.T) {
+    db, mock := newMockDB(t)
+    expectConsentLookup(mock, sqlmock.NewRows([]string{"id"}))
+    mock.ExpectQuery(`INSERT INTO "user_consents"`).
+        WithArgs(1, "marketing_emails", "2024-06", sqlmock.AnyArg(), "2001:db8::1", nil).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+    expectAudit(mock, "record", "user_consent")
+    mock.ExpectCommit()
+
+    s := &server{db: db, consentTypes: loadConsentTypes()}
+    res, err := s.RecordConsent(context.Background(), &pb.RecordConsentRequest{UserId: "1", ConsentType: "marketing_emails", Version: " 2024-06 ", IpAddress: "2001:DB8:0::1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.ConsentId != "6" {
+        t.Errorf("consent id = %s, want 6", res.ConsentId)
+    }
+}
+
+func TestWithdrawConsentKeepsTheFirstWithdrawal(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "user_consents" WHERE "user_consents"."id" = \$1 .*FOR UPDATE`).WithArgs(5).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "withdrawn_at"}).AddRow(5, 1, time.Now()))
+    mock.ExpectCommit()
+
+    if _, err := (&server{db: db}).WithdrawConsent(context.Background(), &pb.WithdrawConsentRequest{ConsentId: "5"}); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestConsentsWithDatabase(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&User{}, &UserConsent{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    s := &server{db: db, consentTypes: loadConsentTypes()}
+    ctx := context.Background()
+    user := User{Name: "Ada", Email: "ada@example.com"}
+    if err := db.Create(&user).Error; err != nil {
+        t.Fatal(err)
+    }
+    req := &pb.RecordConsentRequest{UserId: "1", ConsentType: "analytics_tracking", Version: "2024-06", IpAddress: "192.0.2.7"}
+
+    // Concurrent retries of the same consent all get the one record.
+    ids := make([]string, 10)
+    var wg sync.WaitGroup
+    for i := range ids {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            res, err := s.RecordConsent(ctx, req)
+            if err != nil {
+                t.Error(err)
+                return
+            }
+            ids[i] = res.ConsentId
+        }(i)
+    }
+    wg.Wait()
+    for _, id := range ids {
+        if id != ids[0] {
+            t.Fatalf("concurrent records returned consents %v, want one", ids)
+        }
+    }
+    var count int64
+    db.Model(&UserConsent{}).Count(&count)
+    if count != 1 {
+        t.Fatalf("%d consents stored, want 1", count)
+    }
+
+    // A withdrawn consent is kept, and consenting again records a new one.
+    if _, err := s.WithdrawConsent(ctx, &pb.WithdrawConsentRequest{ConsentId: ids[0]}); err != nil {
+        t.Fatal(err)
+    }
+    again, err := s.RecordConsent(ctx, req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if again.ConsentId == ids[0] {
+        t.Errorf("consenting after a withdrawal returned the withdrawn consent %s", again.ConsentId)
+    }
+
+    history, err := s.GetConsentHistory(ctx, &pb.GetConsentHistoryRequest{UserId: "1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(history.Consents) != 2 || history.Consents[0].Id != again.ConsentId || history.Consents[0].WithdrawnAt != nil || history.Consents[1].WithdrawnAt == nil {
+        t.Fatalf("history = %v, want the new consent first and the withdrawn one kept", history.Consents)
+    }
+    withdrawnAt := history.Consents[1].WithdrawnAt.AsTime()
+    if _, err := s.WithdrawConsent(ctx, &pb.WithdrawConsentRequest{ConsentId: ids[0]}); err != nil {
+        t.Fatal(err)
+    }
+    var withdrawn UserConsent
+    if err := db.First(&withdrawn, ids[0]).Error; err != nil {
+        t.Fatal(err)
+    }
+    if !withdrawn.WithdrawnAt.Equal(withdrawnAt) {
+        t.Errorf("second withdrawal moved withdrawn_at from %v to %v", withdrawnAt, withdrawn.WithdrawnAt)
+    }
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/cespare/xxhash/v2"
+    "github.com/redis/go-redis/v9"
+    spb "google.golang.org/genproto/googleapis/rpc/status"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/types/known/anypb"
+)
+
+// dedupTTL is how long an identical request is treated as a duplicate.
+const dedupTTL = 30 * time.Second
+
+// DeduplicationStore records request fingerprints and the responses produced
+// for them.
+type DeduplicationStore interface {
+    // SetNX claims fingerprint and reports whether it was not already claimed.
+    SetNX(ctx context.Context, fingerprint string, ttl time.Duration) (bool, error)
+    // Release drops a claim whose request failed, so it may be retried.
+    Release(ctx context.Context, fingerprint string) error
+    SaveResponse(ctx context.Context, fingerprint string, response []byte, ttl time.Duration) error
+    // LoadResponse returns nil if no response has been saved yet.
+    LoadResponse(ctx context.Context, fingerprint string) ([]byte, error)
+}
+
+type redisDeduplicationStore struct {
+    client *redis.Client
+}
+
+func newRedisDeduplicationStore(client *redis.Client) *redisDeduplicationStore {
+    return &redisDeduplicationStore{client: client}
+}
+
+func (r *redisDeduplicationStore) SetNX(ctx context.Context, fingerprint string, ttl time.Duration) (bool, error) {
+    return r.client.SetNX(ctx, "dedup:"+fingerprint, 1, ttl).Result()
+}
+
+func (r *redisDeduplicationStore) Release(ctx context.Context, fingerprint string) error {
+    return r.client.Del(ctx, "dedup:"+fingerprint).Err()
+}
+
+func (r *redisDeduplicationStore) SaveResponse(ctx context.Context, fingerprint string, response []byte, ttl time.Duration) error {
+    return r.client.Set(ctx, "dedup:response:"+fingerprint, response, ttl).Err()
+}
+
+func (r *redisDeduplicationStore) LoadResponse(ctx context.Context, fingerprint string) ([]byte, error) {
+    data, err := r.client.Get(ctx, "dedup:response:"+fingerprint).Bytes()
+    if errors.Is(err, redis.Nil) {
+        return nil, nil
+    }
+    return data, err
+}
+
+// NewDeduplicationInterceptor rejects repeats of a request to one of methods
+// within ttl, as sent by proxies that retry on timeout. Requests are matched on
+// their content and caller rather than a caller-supplied key, so two callers
+// sending the same request both get it served. It must run after
+// authentication. A duplicate gets
+// AlreadyExists, carrying the first response as a status detail once it is
+// available. If the store is unreachable requests are let through.
+func NewDeduplicationInterceptor(store DeduplicationStore, ttl time.Duration, methods []string) grpc.UnaryServerInterceptor {
+    deduplicated := make(map[string]bool, len(methods))
+    for _, method := range methods {
+        deduplicated[method] = true
+    }
+
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        msg, ok := req.(proto.Message)
+        if !deduplicated[info.FullMethod] || !ok {
+            return handler(ctx, req)
+        }
+
+        fingerprint, err := requestFingerprint(info.FullMethod, actorFromContext(ctx), msg)
+        if err != nil {
+            return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+        }
+
+        first, err := store.SetNX(ctx, fingerprint, ttl)
+        if err != nil {
+            log.Printf("Deduplication store unavailable, not deduplicating %s: %v", info.FullMethod, err)
+            return handler(ctx, req)
+        }
+        if !first {
+            return nil, duplicateError(ctx, store, fingerprint)
+        }
+
+        res, err := handler(ctx, req)
+        if err != nil {
+            if releaseErr := store.Release(context.Background(), fingerprint); releaseErr != nil {
+                log.Printf("Failed to release deduplication key for %s: %v", info.FullMethod, releaseErr)
+            }
+            return nil, err
+        }
+
+        if err := saveResponse(store, fingerprint, res, ttl); err != nil {
+            log.Printf("Failed to save deduplicated response for %s: %v", info.FullMethod, err)
+        }
+        return res, nil
+    }
+}
+
+func requestFingerprint(method, actor string, req proto.Message) (string, error) {
+    data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+    if err != nil {
+        return "", err
+    }
+    h := xxhash.New()
+    h.WriteString(method)
+    h.Write([]byte{0})
+    h.WriteString(actor)
+    h.Write([]byte{0})
+    h.Write(data)
+    return fmt.Sprintf("%016x", h.Sum64()), nil
+}
+
+// saveResponse stores res as an Any so a duplicate can be answered with it
+// without knowing its type.
+func saveResponse(store DeduplicationStore, fingerprint string, res interface{}, ttl time.Duration) error {
+    msg, ok := res.(proto.Message)
+    if !ok {
+        return nil
+    }
+    wrapped, err := anypb.New(msg)
+    if err != nil {
+        return err
+    }
+    data, err := proto.Marshal(wrapped)
+    if err != nil {
+        return err
+    }
+    return store.SaveResponse(context.Background(), fingerprint, data, ttl)
+}
+
+// duplicateError builds the AlreadyExists error returned for a duplicate,
+// attaching the first request's response when it has been saved.
+func duplicateError(ctx context.Context, store DeduplicationStore, fingerprint string) error {
+    st := &spb.Status{Code: int32(codes.AlreadyExists), Message: "duplicate request"}
+    data, err := store.LoadResponse(ctx, fingerprint)
+    if err == nil && data != nil {
+        var response anypb.Any
+        if proto.Unmarshal(data, &response) == nil {
+            st.Details = []*anypb.Any{&response}
+        }
+    }
+    return status.FromProto(st).Err()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    "shared/fakedata"
+    pb "users-service/proto/gen/proto"
+)
+
+// memoryDeduplicationStore is an in-process DeduplicationStore with the same
+// claim semantics as the Redis one.
+type memoryDeduplicationStore struct {
+    mu        sync.Mutex
+    claims    map[string]bool
+    responses map[string][]byte
+}
+
+func newMemoryDeduplicationStore() *memoryDeduplicationStore {
+    return &memoryDeduplicationStore{claims: make(map[string]bool), responses: make(map[string][]byte)}
+}
+
+func (m *memoryDeduplicationStore) SetNX(ctx context.Context, fingerprint string, ttl time.Duration) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if m.claims[fingerprint] {
+        return false, nil
+    }
+    m.claims[fingerprint] = true
+    return true, nil
+}
+
+func (m *memoryDeduplicationStore) Release(ctx context.Context, fingerprint string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    delete(m.claims, fingerprint)
+    return nil
+}
+
+func (m *memoryDeduplicationStore) SaveResponse(ctx context.Context, fingerprint string, response []byte, ttl time.Duration) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.responses[fingerprint] = response
+    return nil
+}
+
+func (m *memoryDeduplicationStore) LoadResponse(ctx context.Context, fingerprint string) ([]byte, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    return m.responses[fingerprint], nil
+}
+
+func expectUserInsert(mock sqlmock.Sqlmock, id int) {
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+    expectAudit(mock, "create", "user")
+    mock.ExpectCommit()
+}
+
+// createUsersConcurrently sends one identical CreateUser request per API key,
+// all at once, through authentication and deduplication, and returns the
+// status code of each call.
+func createUsersConcurrently(t *testing.T, s *server, keys []string) []codes.Code {
+    t.Helper()
+    auth := &authenticator{keys: map[string]role{"k1": roleReadWrite, "k2": roleReadWrite}}
+    dedup := NewDeduplicationInterceptor(newMemoryDeduplicationStore(), dedupTTL, []string{pb.UserService_CreateUser_FullMethodName})
+    info := &grpc.UnaryServerInfo{FullMethod: pb.UserService_CreateUser_FullMethodName}
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+        return s.CreateUser(ctx, req.(*pb.CreateUserRequest))
+    }
+
+    req := fakedata.User[*pb.CreateUserRequest](fakedata.NewGenerator(t))
+    start := make(chan struct{})
+    got := make([]codes.Code, len(keys))
+    var wg sync.WaitGroup
+    for i, key := range keys {
+        wg.Add(1)
+        go func(i int, key string) {
+            defer wg.Done()
+            ctx, err := auth.authenticate(metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, key)), info.FullMethod)
+            if err != nil {
+                t.Error(err)
+                return
+            }
+            <-start
+            _, err = dedup(ctx, req, info, handler)
+            got[i] = status.Code(err)
+        }(i, key)
+    }
+    close(start)
+    wg.Wait()
+    return got
+}
+
+func TestConcurrentIdenticalCreateUserInsertsOnce(t *testing.T) {
+    db, mock := newMockDB(t)
+    // The mock fails a second insert.
+    expectUserInsert(mock, 1)
+
+    got := createUsersConcurrently(t, &server{db: db}, []string{"k1", "k1"})
+    ok, duplicates := 0, 0
+    for _, code := range got {
+        switch code {
+        case codes.OK:
+            ok++
+        case codes.AlreadyExists:
+            duplicates++
+        }
+    }
+    if ok != 1 || duplicates != 1 {
+        t.Errorf("codes = %v, want one OK and one AlreadyExists", got)
+    }
+}
+
+func TestIdenticalCreateUserFromDifferentCallers(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.MatchExpectationsInOrder(false)
+    expectUserInsert(mock, 1)
+    expectUserInsert(mock, 2)
+
+    for i, code := range createUsersConcurrently(t, &server{db: db}, []string{"k1", "k2"}) {
+        if code != codes.OK {
+            t.Errorf("caller %d: %v, want OK: requests from different callers are not duplicates", i+1, code)
+        }
+    }
+}
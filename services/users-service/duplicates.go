@@ -0,0 +1,332 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// duplicateBatchSize is how many groups or users FindDuplicateUsers reads per
+// query.
+const duplicateBatchSize = 100
+
+// fuzzyNameThreshold is the pg_trgm similarity from which two names are
+// reported as duplicates.
+const fuzzyNameThreshold = 0.6
+
+// duplicateEmailKeys are the SQL expressions the email strategies group users
+// by. Users whose emails give the same key are duplicates.
+var duplicateEmailKeys = map[pb.DuplicateStrategy]string{
+    // Gmail ignores dots in the local part, and googlemail.com is an alias
+    // of gmail.com.
+    pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE: `CASE
+        WHEN split_part(lower(btrim(email)), '@', 2) IN ('gmail.com', 'googlemail.com')
+        THEN replace(split_part(lower(btrim(email)), '@', 1), '.', '') || '@gmail.com'
+        ELSE lower(btrim(email)) END`,
+    // Most providers deliver user+tag@ to user@.
+    pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP: `split_part(split_part(lower(btrim(email)), '@', 1), '+', 1)
+        || '@' || split_part(lower(btrim(email)), '@', 2)`,
+}
+
+// migrateNameTrigramIndex enables pg_trgm and indexes user names with it, for
+// FindDuplicateUsers' fuzzy name matching. It must run after User is
+// migrated.
+func migrateNameTrigramIndex(db *gorm.DB) error {
+    statements := []string{
+        `CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+        `CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (name gin_trgm_ops)`,
+    }
+    for _, statement := range statements {
+        if err := db.Exec(statement).Error; err != nil {
+            return fmt.Errorf("migrate idx_users_name_trgm: %w", err)
+        }
+    }
+    return nil
+}
+
+// FindDuplicateUsers streams groups of users that are likely the same person,
+// by the request's strategy. The oldest user of each group is its canonical
+// user. Groups are sent as each batch is read, so a large table does not
+// hold back the first results.
+func (s *server) FindDuplicateUsers(req *pb.FindDuplicateUsersRequest, stream pb.UserService_FindDuplicateUsersServer) error {
+    switch req.Strategy {
+    case pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE, pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP:
+        return s.findDuplicateEmails(stream, req.Strategy)
+    case pb.DuplicateStrategy_DUPLICATE_STRATEGY_FUZZY_NAME:
+        return s.findDuplicateNames(stream)
+    default:
+        return status.Errorf(codes.InvalidArgument, "unsupported strategy %v", req.Strategy)
+    }
+}
+
+// findDuplicateEmails pages through the email keys shared by more than one
+// user.
+func (s *server) findDuplicateEmails(stream pb.UserService_FindDuplicateUsersServer, strategy pb.DuplicateStrategy) error {
+    // The key is one of the constant expressions above, never request data.
+    query := `SELECT email_key, string_agg(id::text, ',' ORDER BY id) AS ids
+        FROM (SELECT id, ` + duplicateEmailKeys[strategy] + ` AS email_key FROM users WHERE deleted_at IS NULL) keyed
+        WHERE email_key > ?
+        GROUP BY email_key
+        HAVING count(*) > 1
+        ORDER BY email_key
+        LIMIT ?`
+
+    after := ""
+    for {
+        var groups []struct {
+            EmailKey string
+            IDs      string `gorm:"column:ids"`
+        }
+        if err := s.db.WithContext(stream.Context()).Raw(query, after, duplicateBatchSize).Scan(&groups).Error; err != nil {
+            return err
+        }
+        for _, group := range groups {
+            ids := strings.Split(group.IDs, ",")
+            err := stream.Send(&pb.DuplicateUserGroup{
+                CanonicalUserId:  ids[0],
+                DuplicateIds:     ids[1:],
+                SimilarityReason: fmt.Sprintf("emails match %s", group.EmailKey),
+            })
+            if err != nil {
+                return err
+            }
+        }
+        if len(groups) < duplicateBatchSize {
+            return nil
+        }
+        after = groups[len(groups)-1].EmailKey
+    }
+}
+
+// findDuplicateNames pages through users by id, grouping each with the newer
+// users whose names are similar to theirs. A user is reported in at most one
+// group.
+func (s *server) findDuplicateNames(stream pb.UserService_FindDuplicateUsersServer) error {
+    ctx := stream.Context()
+    reported := make(map[uint]bool)
+    var lastID uint
+    for {
+        var users []User
+        if err := s.db.WithContext(ctx).Where("id > ? AND name <> ''", lastID).Order("id").Limit(duplicateBatchSize).Find(&users).Error; err != nil {
+            return err
+        }
+        if len(users) == 0 {
+            return nil
+        }
+        lastID = users[len(users)-1].ID
+        ids := make([]uint, len(users))
+        for i, user := range users {
+            ids[i] = user.ID
+        }
+
+        var matches []struct {
+            CanonicalID uint
+            DuplicateID uint
+        }
+        err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+            // % compares against this threshold, and can use idx_users_name_trgm.
+            if err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', ?, true)`, strconv.FormatFloat(fuzzyNameThreshold, 'f', -1, 64)).Error; err != nil {
+                return err
+            }
+            return tx.Raw(`SELECT a.id AS canonical_id, b.id AS duplicate_id
+                FROM users a JOIN users b ON b.name % a.name AND b.id > a.id AND b.deleted_at IS NULL
+                WHERE a.id IN ?
+                ORDER BY a.id, b.id`, ids).Scan(&matches).Error
+        })
+        if err != nil {
+            return err
+        }
+
+        duplicates := make(map[uint][]uint)
+        for _, match := range matches {
+            if !reported[match.CanonicalID] && !reported[match.DuplicateID] {
+                duplicates[match.CanonicalID] = append(duplicates[match.CanonicalID], match.DuplicateID)
+            }
+        }
+        for _, user := range users {
+            if len(duplicates[user.ID]) == 0 {
+                continue
+            }
+            reported[user.ID] = true
+            duplicateIDs := make([]string, len(duplicates[user.ID]))
+            for i, id := range duplicates[user.ID] {
+                reported[id] = true
+                duplicateIDs[i] = fmt.Sprint(id)
+            }
+            err := stream.Send(&pb.DuplicateUserGroup{
+                CanonicalUserId:  fmt.Sprint(user.ID),
+                DuplicateIds:     duplicateIDs,
+                SimilarityReason: fmt.Sprintf("names similar to %q", user.Name),
+            })
+            if err != nil {
+                return err
+            }
+        }
+    }
+}
+
+// MergeUsers folds the duplicate user into the canonical one and deletes the
+// duplicate. Its social accounts move to the canonical user, and its
+// preferences are kept where the canonical user has not set the same key.
+// No service tracks orders yet, so there are none to move.
+func (s *server) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+    if req.CanonicalId == req.DuplicateId {
+        return nil, status.Error(codes.InvalidArgument, "canonical_id and duplicate_id must differ")
+    }
+    canonical, err := s.findUser(ctx, req.CanonicalId)
+    if err != nil {
+        return nil, err
+    }
+    duplicate, err := s.findUser(ctx, req.DuplicateId)
+    if err != nil {
+        return nil, err
+    }
+
+    res := &pb.MergeUsersResponse{}
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        // Lock both users, in id order, so concurrent merges of either
+        // cannot interleave.
+        var locked []User
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", []uint{canonical.ID, duplicate.ID}).Order("id").Find(&locked).Error; err != nil {
+            return err
+        }
+        if len(locked) != 2 {
+            return status.Error(codes.Aborted, "user was deleted during the merge")
+        }
+
+        var conflicts []string
+        if err := tx.Model(&SocialAccount{}).
+            Where("user_id = ? AND provider IN (?)", duplicate.ID, tx.Model(&SocialAccount{}).Select("provider").Where("user_id = ?", canonical.ID)).
+            Pluck("provider", &conflicts).Error; err != nil {
+            return err
+        }
+        if len(conflicts) > 0 {
+            return status.Errorf(codes.FailedPrecondition, "both users have %s accounts linked; unlink one first", strings.Join(conflicts, ", "))
+        }
+        result := tx.Model(&SocialAccount{}).Where("user_id = ?", duplicate.ID).Update("user_id", canonical.ID)
+        if result.Error != nil {
+            return result.Error
+        }
+        res.SocialAccountsMoved = int32(result.RowsAffected)
+
+        // The canonical user's default address, if any, stays the default.
+        if err := tx.Model(&UserAddress{}).Where("user_id = ?", duplicate.ID).
+            UpdateColumns(map[string]interface{}{"user_id": canonical.ID, "is_default": false}).Error; err != nil {
+            return err
+        }
+
+        // Consents are the duplicate's record of what it agreed to, so they
+        // are kept, withdrawn ones included.
+        if err := tx.Model(&UserConsent{}).Where("user_id = ?", duplicate.ID).UpdateColumn("user_id", canonical.ID).Error; err != nil {
+            return err
+        }
+
+        // Where both users have a communication preference, the canonical
+        // user's is kept, but an unsubscribe from either stands.
+        if err := tx.Exec(`
+            UPDATE user_communication_preferences c SET subscribed = false, updated_at = ?
+            FROM user_communication_preferences d
+            WHERE c.user_id = ? AND d.user_id = ? AND d.channel = c.channel AND d.type = c.type
+                AND c.subscribed AND NOT d.subscribed`, time.Now(), canonical.ID, duplicate.ID).Error; err != nil {
+            return err
+        }
+        if err := tx.Exec(`
+            DELETE FROM user_communication_preferences d
+            WHERE d.user_id = ? AND EXISTS (
+                SELECT 1 FROM user_communication_preferences c
+                WHERE c.user_id = ? AND c.channel = d.channel AND c.type = d.type)`, duplicate.ID, canonical.ID).Error; err != nil {
+            return err
+        }
+        if err := tx.Model(&UserCommunicationPreference{}).Where("user_id = ?", duplicate.ID).UpdateColumn("user_id", canonical.ID).Error; err != nil {
+            return err
+        }
+
+        // The duplicate's second factor goes with it; the canonical user's
+        // is kept.
+        if err := tx.Where("user_id = ?", duplicate.ID).Delete(&UserBackupCode{}).Error; err != nil {
+            return err
+        }
+
+        moved, err := mergePreferences(tx, canonical.ID, duplicate.ID)
+        if err != nil {
+            return err
+        }
+        res.PreferencesMoved = int32(moved)
+        if err := tx.Delete(&User{}, duplicate.ID).Error; err != nil {
+            return err
+        }
+        if err := recordAudit(ctx, tx, "merge", "user", canonical.ID, map[string]interface{}{
+            "duplicate_id":          duplicate.ID,
+            "social_accounts_moved": res.SocialAccountsMoved,
+            "preferences_moved":     res.PreferencesMoved,
+        }); err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "delete", "user", duplicate.ID, map[string]uint{"merged_into": canonical.ID})
+    })
+    if err != nil {
+        return nil, err
+    }
+    res.User = &pb.User{Id: fmt.Sprint(canonical.ID), Name: canonical.Name, Email: canonical.Email}
+    return res, nil
+}
+
+// mergePreferences copies the duplicate's preferences to the canonical user,
+// keeping the canonical user's value for keys both have set, and deletes the
+// duplicate's. It returns the number of preferences copied.
+func mergePreferences(tx *gorm.DB, canonicalID, duplicateID uint) (int, error) {
+    load := func(userID uint) (map[string]string, error) {
+        var prefs UserPreferences
+        err := tx.Where("user_id = ?", userID).Take(&prefs).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return map[string]string{}, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        values := make(map[string]string)
+        if err := json.Unmarshal([]byte(prefs.Preferences), &values); err != nil {
+            return nil, status.Errorf(codes.Internal, "corrupt preferences for user %d: %v", userID, err)
+        }
+        return values, nil
+    }
+    from, err := load(duplicateID)
+    if err != nil || len(from) == 0 {
+        return 0, err
+    }
+    into, err := load(canonicalID)
+    if err != nil {
+        return 0, err
+    }
+
+    moved := 0
+    for key, value := range from {
+        if _, ok := into[key]; !ok {
+            into[key] = value
+            moved++
+        }
+    }
+    raw, err := json.Marshal(into)
+    if err != nil {
+        return 0, err
+    }
+    err = tx.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "user_id"}},
+        DoUpdates: clause.AssignmentColumns([]string{"preferences"}),
+    }).Create(&UserPreferences{UserID: canonicalID, Preferences: string(raw)}).Error
+    if err != nil {
+        return 0, err
+    }
+    return moved, tx.Where("user_id = ?", duplicateID).Delete(&UserPreferences{}).Error
+}
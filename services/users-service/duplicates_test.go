@@ -0,0 +1,198 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    "shared/audit"
+    "shared/testdb"
+    pb "users-service/proto/gen/proto"
+)
+
+// duplicateStream records the groups FindDuplicateUsers sends.
+type duplicateStream struct {
+    grpc.ServerStream
+    groups []*pb.DuplicateUserGroup
+}
+
+func (s *duplicateStream) Context() context.Context { return context.Background() }
+
+func (s *duplicateStream) Send(group *pb.DuplicateUserGroup) error {
+    s.groups = append(s.groups, group)
+    return nil
+}
+
+func TestFindDuplicateUsersRejectsUnknownStrategies(t *testing.T) {
+    err := (&server{}).FindDuplicateUsers(&pb.FindDuplicateUsersRequest{Strategy: 99}, &duplicateStream{})
+    if status.Code(err) != codes.InvalidArgument {
+        t.Errorf("strategy 99 = %v, want InvalidArgument", err)
+    }
+}
+
+func TestFindDuplicateEmailsSendsEachGroup(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT email_key, string_agg`).
+        WithArgs("", duplicateBatchSize).
+        WillReturnRows(sqlmock.NewRows([]string{"email_key", "ids"}).
+            AddRow("ada@gmail.com", "3,7,9").
+            AddRow("grace@example.com", "4,5"))
+
+    stream := &duplicateStream{}
+    req := &pb.FindDuplicateUsersRequest{Strategy: pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP}
+    if err := (&server{db: db}).FindDuplicateUsers(req, stream); err != nil {
+        t.Fatal(err)
+    }
+    if len(stream.groups) != 2 {
+        t.Fatalf("sent %d groups, want 2", len(stream.groups))
+    }
+    if g := stream.groups[0]; g.CanonicalUserId != "3" || !reflect.DeepEqual(g.DuplicateIds, []string{"7", "9"}) {
+        t.Errorf("first group = %v, want 3 with duplicates 7 and 9", g)
+    }
+}
+
+// duplicateUsersDatabase returns a test database holding users 1 to 6:
+//
+//	1 Ada Lovelace  ada.lovelace@gmail.com
+//	2 Ada Lovelace  AdaLovelace@googlemail.com
+//	3 Grace Hopper  grace@example.com
+//	4 Grace Hopper  grace+news@example.com
+//	5 Grace Hoper   Grace@Example.com
+//	6 Alan Turing   alan@example.org
+func duplicateUsersDatabase(t *testing.T) *gorm.DB {
+    t.Helper()
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&User{}, &UserPreferences{}, &SocialAccount{}, &UserAddress{}, &UserBackupCode{}, &UserConsent{}, &UserCommunicationPreference{}, &audit.Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := migrateNameTrigramIndex(db); err != nil {
+        t.Skipf("pg_trgm is not available: %v", err)
+    }
+    for _, u := range [][2]string{
+        {"Ada Lovelace", "ada.lovelace@gmail.com"},
+        {"Ada Lovelace", "AdaLovelace@googlemail.com"},
+        {"Grace Hopper", "grace@example.com"},
+        {"Grace Hopper", "grace+news@example.com"},
+        {"Grace Hoper", "Grace@Example.com"},
+        {"Alan Turing", "alan@example.org"},
+    } {
+        if err := db.Create(&User{Name: u[0], Email: u[1]}).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+    return db
+}
+
+func TestFindDuplicateUsersStrategies(t *testing.T) {
+    db := duplicateUsersDatabase(t)
+    s := &server{db: db}
+    for strategy, want := range map[pb.DuplicateStrategy]map[string][]string{
+        pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE:  {"1": {"2"}, "3": {"5"}},
+        pb.DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP: {"3": {"4", "5"}},
+        pb.DuplicateStrategy_DUPLICATE_STRATEGY_FUZZY_NAME:       {"1": {"2"}, "3": {"4", "5"}},
+    } {
+        stream := &duplicateStream{}
+        if err := s.FindDuplicateUsers(&pb.FindDuplicateUsersRequest{Strategy: strategy}, stream); err != nil {
+            t.Fatalf("%v: %v", strategy, err)
+        }
+        got := make(map[string][]string)
+        for _, g := range stream.groups {
+            got[g.CanonicalUserId] = g.DuplicateIds
+        }
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("%v found %v, want %v", strategy, got, want)
+        }
+    }
+}
+
+func TestMergeUsers(t *testing.T) {
+    db := duplicateUsersDatabase(t)
+    s := &server{db: db}
+    ctx := context.Background()
+    for _, row := range []interface{}{
+        &UserPreferences{UserID: 1, Preferences: `{"theme":"dark"}`},
+        &UserPreferences{UserID: 2, Preferences: `{"theme":"light","lang":"en"}`},
+        &SocialAccount{UserID: 2, Provider: "github", ProviderUserID: "583231", AccessToken: "gho_a"},
+        &SocialAccount{UserID: 4, Provider: "github", ProviderUserID: "9001", AccessToken: "gho_b"},
+        &SocialAccount{UserID: 3, Provider: "github", ProviderUserID: "9002", AccessToken: "gho_c"},
+        &UserAddress{UserID: 1, Line1: "12 St James's Sq", City: "London", CountryCode: "GB", IsDefault: true},
+        &UserAddress{UserID: 2, Line1: "1 Infinite Loop", City: "Cupertino", CountryCode: "US", IsDefault: true},
+        &UserConsent{UserID: 2, ConsentType: "marketing_emails", Version: "2024-06", ConsentedAt: time.Now()},
+        &UserCommunicationPreference{UserID: 1, Channel: "EMAIL", Type: "marketing", Subscribed: true},
+        &UserCommunicationPreference{UserID: 2, Channel: "EMAIL", Type: "marketing", Subscribed: false},
+        &UserCommunicationPreference{UserID: 2, Channel: "SMS", Type: "alerts", Subscribed: false},
+    } {
+        if err := db.Create(row).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    res, err := s.MergeUsers(ctx, &pb.MergeUsersRequest{CanonicalId: "1", DuplicateId: "2"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.SocialAccountsMoved != 1 || res.PreferencesMoved != 1 || res.User.Id != "1" {
+        t.Errorf("merge response = %v, want 1 account and 1 preference moved to user 1", res)
+    }
+    var prefs UserPreferences
+    if err := db.Take(&prefs, "user_id = ?", 1).Error; err != nil {
+        t.Fatal(err)
+    }
+    var values map[string]string
+    if err := json.Unmarshal([]byte(prefs.Preferences), &values); err != nil {
+        t.Fatal(err)
+    }
+    if want := map[string]string{"theme": "dark", "lang": "en"}; !reflect.DeepEqual(values, want) {
+        t.Errorf("merged preferences = %v, want %v", values, want)
+    }
+    var owner SocialAccount
+    if err := db.Take(&owner, "provider_user_id = ?", "583231").Error; err != nil || owner.UserID != 1 {
+        t.Errorf("github account belongs to user %d (%v), want 1", owner.UserID, err)
+    }
+    var addresses []UserAddress
+    if err := db.Where("user_id = ?", 1).Order("id").Find(&addresses).Error; err != nil {
+        t.Fatal(err)
+    }
+    if len(addresses) != 2 || !addresses[0].IsDefault || addresses[1].IsDefault {
+        t.Errorf("user 1 has addresses %+v, want both with the London one still the default", addresses)
+    }
+    var consent UserConsent
+    if err := db.Take(&consent, "consent_type = ?", "marketing_emails").Error; err != nil || consent.UserID != 1 {
+        t.Errorf("consent belongs to user %d (%v), want 1", consent.UserID, err)
+    }
+    // An unsubscribe from either user stands.
+    var commPrefs []UserCommunicationPreference
+    if err := db.Order("user_id, channel").Find(&commPrefs).Error; err != nil {
+        t.Fatal(err)
+    }
+    if len(commPrefs) != 2 || commPrefs[0].UserID != 1 || commPrefs[0].Subscribed || commPrefs[1].UserID != 1 || commPrefs[1].Subscribed {
+        t.Errorf("communication preferences = %+v, want user 1 unsubscribed from EMAIL marketing and SMS alerts", commPrefs)
+    }
+    if _, err := s.findUser(ctx, "2"); status.Code(err) != codes.NotFound {
+        t.Errorf("merged duplicate still found: %v", err)
+    }
+
+    // Users 3 and 4 each have a GitHub account, and only one can stay.
+    if _, err := s.MergeUsers(ctx, &pb.MergeUsersRequest{CanonicalId: "3", DuplicateId: "4"}); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("merging two GitHub users = %v, want FailedPrecondition", err)
+    }
+    for _, req := range []*pb.MergeUsersRequest{
+        {CanonicalId: "3", DuplicateId: "3"},
+        {CanonicalId: "3", DuplicateId: "x"},
+    } {
+        if _, err := s.MergeUsers(ctx, req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("MergeUsers(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+    if _, err := s.MergeUsers(ctx, &pb.MergeUsersRequest{CanonicalId: "3", DuplicateId: "2"}); status.Code(err) != codes.NotFound {
+        t.Errorf("merging a deleted user = %v, want NotFound", err)
+    }
+}
@@ -0,0 +1,217 @@
+// Package journal records unary requests to disk so that real traffic can be
+// replayed later with api-gateway/cmd/replay.
+//
+// A journal file is a sequence of records, each a 4-byte big-endian length
+// followed by that many bytes of a marshaled JournalEntry. Files are named
+// journal-<UTC start time>.bin and rotated once they reach a size limit.
+package journal
+
+import (
+    "context"
+    "encoding/binary"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/types/known/durationpb"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// DefaultMaxFileSize is the size at which a journal file is rotated.
+const DefaultMaxFileSize = 64 << 20
+
+// maskedValue replaces masked string fields.
+const maskedValue = "***"
+
+// Config configures a Journal.
+type Config struct {
+    Dir string
+    // MaxFileSize is the size in bytes at which a new file is started.
+    MaxFileSize int64
+    // MetadataKeys lists the request metadata to record. Others are dropped.
+    MetadataKeys []string
+    // MaskFields lists fields to blank before recording, as the full message
+    // name followed by a field path, e.g. "users.CreateUserRequest.email".
+    MaskFields []string
+}
+
+// Journal appends requests to rotating files in a directory.
+type Journal struct {
+    dir          string
+    maxFileSize  int64
+    metadataKeys []string
+    masks        map[protoreflect.FullName][][]protoreflect.Name
+
+    mu   sync.Mutex
+    file *os.File
+    size int64
+}
+
+func Open(config Config) (*Journal, error) {
+    if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+        return nil, fmt.Errorf("create journal dir: %w", err)
+    }
+    j := &Journal{
+        dir:          config.Dir,
+        maxFileSize:  config.MaxFileSize,
+        metadataKeys: config.MetadataKeys,
+        masks:        make(map[protoreflect.FullName][][]protoreflect.Name),
+    }
+    if j.maxFileSize <= 0 {
+        j.maxFileSize = DefaultMaxFileSize
+    }
+    for _, field := range config.MaskFields {
+        message, path, err := parseMask(field)
+        if err != nil {
+            return nil, err
+        }
+        j.masks[message] = append(j.masks[message], path)
+    }
+    return j, nil
+}
+
+// parseMask splits "pkg.Message.field.subfield" into the message name and
+// field path. Message names start with an upper-case letter and fields do
+// not, which is how the two are told apart.
+func parseMask(field string) (protoreflect.FullName, []protoreflect.Name, error) {
+    parts := strings.Split(field, ".")
+    for i, part := range parts {
+        if part != "" && part[0] >= 'A' && part[0] <= 'Z' && i+1 < len(parts) {
+            path := make([]protoreflect.Name, 0, len(parts)-i-1)
+            for _, name := range parts[i+1:] {
+                path = append(path, protoreflect.Name(name))
+            }
+            return protoreflect.FullName(strings.Join(parts[:i+1], ".")), path, nil
+        }
+    }
+    return "", nil, fmt.Errorf("mask %q is not <package>.<Message>.<field path>", field)
+}
+
+// Close closes the current file.
+func (j *Journal) Close() error {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    if j.file == nil {
+        return nil
+    }
+    err := j.file.Close()
+    j.file = nil
+    return err
+}
+
+// UnaryInterceptor records every unary request once it has been handled.
+func (j *Journal) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    start := time.Now()
+    resp, err := handler(ctx, req)
+    if msg, ok := req.(proto.Message); ok {
+        if werr := j.record(ctx, info.FullMethod, msg, start, time.Since(start), err); werr != nil {
+            log.Printf("Failed to journal %s: %v", info.FullMethod, werr)
+        }
+    }
+    return resp, err
+}
+
+func (j *Journal) record(ctx context.Context, method string, req proto.Message, start time.Time, latency time.Duration, handlerErr error) error {
+    data, err := proto.Marshal(j.mask(req))
+    if err != nil {
+        return err
+    }
+    entry := &pb.JournalEntry{
+        Method:    method,
+        Request:   data,
+        StartedAt: timestamppb.New(start),
+        Latency:   durationpb.New(latency),
+        Code:      int32(status.Code(handlerErr)),
+    }
+    if md, ok := metadata.FromIncomingContext(ctx); ok {
+        for _, key := range j.metadataKeys {
+            if values := md.Get(key); len(values) > 0 {
+                if entry.Metadata == nil {
+                    entry.Metadata = make(map[string]string)
+                }
+                entry.Metadata[key] = values[0]
+            }
+        }
+    }
+    record, err := proto.Marshal(entry)
+    if err != nil {
+        return err
+    }
+    frame := make([]byte, 4+len(record))
+    binary.BigEndian.PutUint32(frame, uint32(len(record)))
+    copy(frame[4:], record)
+    return j.write(frame)
+}
+
+func (j *Journal) write(frame []byte) error {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    if j.file == nil || j.size+int64(len(frame)) > j.maxFileSize {
+        if err := j.rotate(); err != nil {
+            return err
+        }
+    }
+    n, err := j.file.Write(frame)
+    j.size += int64(n)
+    return err
+}
+
+// rotate must be called with j.mu held.
+func (j *Journal) rotate() error {
+    if j.file != nil {
+        if err := j.file.Close(); err != nil {
+            log.Printf("Failed to close journal file: %v", err)
+        }
+    }
+    name := filepath.Join(j.dir, "journal-"+time.Now().UTC().Format("20060102T150405.000000000")+".bin")
+    file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+    if err != nil {
+        j.file = nil
+        return err
+    }
+    j.file, j.size = file, 0
+    return nil
+}
+
+// mask returns req with the configured fields blanked, copying it first if
+// anything is masked.
+func (j *Journal) mask(req proto.Message) proto.Message {
+    paths := j.masks[req.ProtoReflect().Descriptor().FullName()]
+    if len(paths) == 0 {
+        return req
+    }
+    masked := proto.Clone(req)
+    for _, path := range paths {
+        maskPath(masked.ProtoReflect(), path)
+    }
+    return masked
+}
+
+func maskPath(msg protoreflect.Message, path []protoreflect.Name) {
+    fd := msg.Descriptor().Fields().ByName(path[0])
+    if fd == nil || !msg.Has(fd) {
+        return
+    }
+    if len(path) > 1 {
+        if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+            maskPath(msg.Mutable(fd).Message(), path[1:])
+        }
+        return
+    }
+    if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+        msg.Set(fd, protoreflect.ValueOfString(maskedValue))
+        return
+    }
+    msg.Clear(fd)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc/health/grpc_health_v1"
+
+    pb "users-service/proto/gen/proto"
+)
+
+func TestRateLimitKey(t *testing.T) {
+    caller := func(key string) context.Context {
+        return context.WithValue(context.Background(), actorContextKey{}, keyActor(key))
+    }
+    for _, method := range []string{grpc_health_v1.Health_Check_FullMethodName, pb.DrainService_Drain_FullMethodName} {
+        if key := rateLimitKey(caller("k1"), method); key != "" {
+            t.Errorf("%s has rate limit key %q, want it exempt", method, key)
+        }
+    }
+    k1 := rateLimitKey(caller("k1"), pb.UserService_GetUser_FullMethodName)
+    if k1 != rateLimitKey(caller("k1"), pb.UserService_CreateUser_FullMethodName) {
+        t.Error("one caller's methods have separate budgets")
+    }
+    if k1 == rateLimitKey(caller("k2"), pb.UserService_GetUser_FullMethodName) {
+        t.Error("two callers share a budget")
+    }
+}
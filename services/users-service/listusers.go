@@ -0,0 +1,137 @@
+package main
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    "shared/pagination"
+    pb "users-service/proto/gen/proto"
+)
+
+// userOrdering is a sort order ListUsers accepts. Every ordering ends with
+// the id so that it is total and pages never overlap.
+type userOrdering struct {
+    orderBy string
+    // after restricts the query to rows after the cursor.
+    after func(c userCursor) (string, []interface{})
+}
+
+var userOrderings = map[string]userOrdering{
+    "": {
+        orderBy: "id",
+        after: func(c userCursor) (string, []interface{}) {
+            return "id > ?", []interface{}{c.ID}
+        },
+    },
+    "name_asc": {
+        orderBy: "name, id",
+        after: func(c userCursor) (string, []interface{}) {
+            return "(name, id) > (?, ?)", []interface{}{c.Name, c.ID}
+        },
+    },
+    "created_desc": {
+        orderBy: "created_at DESC, id DESC",
+        after: func(c userCursor) (string, []interface{}) {
+            return "(created_at, id) < (?, ?)", []interface{}{c.CreatedAt, c.ID}
+        },
+    },
+}
+
+// userCursor is the decoded page_token: the sort key of the last user on the
+// previous page, and the filter it was issued for.
+type userCursor struct {
+    OrderBy    string    `json:"o,omitempty"`
+    NamePrefix string    `json:"p,omitempty"`
+    ID         uint      `json:"i"`
+    Name       string    `json:"n,omitempty"`
+    CreatedAt  time.Time `json:"c,omitempty"`
+}
+
+func (c userCursor) encode() string {
+    raw, _ := json.Marshal(c)
+    return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeUserCursor(token string) (userCursor, error) {
+    var c userCursor
+    raw, err := base64.RawURLEncoding.DecodeString(token)
+    if err == nil {
+        err = json.Unmarshal(raw, &c)
+    }
+    return c, err
+}
+
+// escapeLike escapes the LIKE wildcards in s, so a name prefix such as "50%"
+// matches itself rather than every name starting with "50".
+func escapeLike(s string) string {
+    return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// clampPageSize applies the default and maximum page size, which are the
+// same for every list RPC.
+func (s *server) clampPageSize(pageSize int32) (int, error) {
+    size, err := pagination.ClampPageSize(pageSize, s.maxPageSize)
+    if err != nil {
+        return 0, status.Error(codes.InvalidArgument, "page_size must not be negative")
+    }
+    return size, nil
+}
+
+// ListUsers pages through users, optionally only those whose name starts
+// with name_prefix (case-insensitively), in one of the userOrderings.
+func (s *server) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+    ordering, ok := userOrderings[req.OrderBy]
+    if !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported order_by %q: must be name_asc or created_desc", req.OrderBy)
+    }
+    pageSize, err := s.clampPageSize(req.PageSize)
+    if err != nil {
+        return nil, err
+    }
+
+    query := s.db.WithContext(ctx).Order(ordering.orderBy).Limit(pageSize + 1)
+    if req.NamePrefix != "" {
+        // Matches idx_users_name_lower; ILIKE could not use an index.
+        query = query.Where("lower(name) LIKE ?", strings.ToLower(escapeLike(req.NamePrefix))+"%")
+    }
+    if req.PageToken != "" {
+        cursor, err := decodeUserCursor(req.PageToken)
+        if err != nil {
+            return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+        }
+        if cursor.OrderBy != req.OrderBy || cursor.NamePrefix != req.NamePrefix {
+            return nil, status.Error(codes.InvalidArgument, "page_token was issued for a different name_prefix or order_by")
+        }
+        condition, args := ordering.after(cursor)
+        query = query.Where(condition, args...)
+    }
+
+    var users []User
+    if err := query.Find(&users).Error; err != nil {
+        return nil, err
+    }
+    res := &pb.ListUsersResponse{}
+    if len(users) > pageSize {
+        users = users[:pageSize]
+        last := users[len(users)-1]
+        res.NextPageToken = userCursor{
+            OrderBy:    req.OrderBy,
+            NamePrefix: req.NamePrefix,
+            ID:         last.ID,
+            Name:       last.Name,
+            CreatedAt:  last.CreatedAt,
+        }.encode()
+    }
+    res.Users = make([]*pb.User, len(users))
+    for i, u := range users {
+        res.Users[i] = &pb.User{Id: fmt.Sprint(u.ID), Name: u.Name, Email: u.Email}
+    }
+    return res, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    "shared/testdb"
+    pb "users-service/proto/gen/proto"
+)
+
+func userRows(names ...string) *sqlmock.Rows {
+    rows := sqlmock.NewRows([]string{"id", "name", "email", "created_at"})
+    for i, name := range names {
+        rows.AddRow(i+1, name, name+"@example.com", time.Date(2024, 6, 3-i, 0, 0, 0, 0, time.UTC))
+    }
+    return rows
+}
+
+func TestEscapeLike(t *testing.T) {
+    tests := map[string]string{
+        "ada":     "ada",
+        "50%":     `50\%`,
+        "a_b":     `a\_b`,
+        `back\`:   `back\\`,
+        `%_\ mix`: `\%\_\\ mix`,
+    }
+    for prefix, want := range tests {
+        if got := escapeLike(prefix); got != want {
+            t.Errorf("escapeLike(%q) = %q, want %q", prefix, got, want)
+        }
+    }
+}
+
+func TestListUsersRejectsBadRequests(t *testing.T) {
+    db, _ := newMockDB(t)
+    s := &server{db: db}
+    for _, req := range []*pb.ListUsersRequest{
+        {OrderBy: "email_asc"},
+        {OrderBy: "name_asc; DROP TABLE users"},
+        {PageSize: -1},
+        {PageToken: "not a token"},
+        // A token only continues the listing it was issued for.
+        {OrderBy: "name_asc", PageToken: userCursor{OrderBy: "created_desc", ID: 1}.encode()},
+        {NamePrefix: "b", PageToken: userCursor{NamePrefix: "a", ID: 1}.encode()},
+    } {
+        if _, err := s.ListUsers(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("ListUsers(%v) = %v, want InvalidArgument", req, err)
+        }
+    }
+}
+
+func TestListUsersSearchesAndPages(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db}
+    ctx := context.Background()
+
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE lower\(name\) LIKE \$1 AND "users"."deleted_at" IS NULL ORDER BY name, id LIMIT 3`).
+        WithArgs(`ad\_%`).
+        WillReturnRows(userRows("ad_a", "ad_b", "ad_c"))
+    res, err := s.ListUsers(ctx, &pb.ListUsersRequest{NamePrefix: "ad_", OrderBy: "name_asc", PageSize: 2})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Users) != 2 || res.Users[1].Name != "ad_b" || res.NextPageToken == "" {
+        t.Fatalf("first page = %v, next %q; want ad_a and ad_b and a token", res.Users, res.NextPageToken)
+    }
+
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE lower\(name\) LIKE \$1 AND \(name, id\) > \(\$2, \$3\) AND "users"."deleted_at" IS NULL ORDER BY name, id LIMIT 3`).
+        WithArgs(`ad\_%`, "ad_b", 2).
+        WillReturnRows(userRows("ad_c"))
+    res, err = s.ListUsers(ctx, &pb.ListUsersRequest{NamePrefix: "ad_", OrderBy: "name_asc", PageSize: 2, PageToken: res.NextPageToken})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Users) != 1 || res.NextPageToken != "" {
+        t.Errorf("last page = %v, next %q; want one user and no token", res.Users, res.NextPageToken)
+    }
+}
+
+func TestListUsersObeysMaxPageSize(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, maxPageSize: 5}
+    for _, orderBy := range []string{"name_asc", "created_desc"} {
+        mock.ExpectQuery(`SELECT \* FROM "users" .*LIMIT 6$`).WillReturnRows(userRows("Ada"))
+        if _, err := s.ListUsers(context.Background(), &pb.ListUsersRequest{OrderBy: orderBy, PageSize: 1000}); err != nil {
+            t.Errorf("%s: %v", orderBy, err)
+        }
+    }
+}
+
+func TestListUsersNewestFirst(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."deleted_at" IS NULL ORDER BY created_at DESC, id DESC LIMIT 101`).
+        WillReturnRows(userRows("Ada", "Grace"))
+    res, err := (&server{db: db}).ListUsers(context.Background(), &pb.ListUsersRequest{OrderBy: "created_desc", PageSize: 500})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Users) != 2 || res.Users[0].Name != "Ada" {
+        t.Errorf("got %v, want Ada then Grace", res.Users)
+    }
+}
+
+// TestNamePrefixSearchUsesIndex checks the plan of the statement ListUsers
+// runs for a name_prefix search.
+func TestNamePrefixSearchUsesIndex(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&User{}); err != nil {
+        t.Fatal(err)
+    }
+    users := make([]User, 2000)
+    for i := range users {
+        users[i] = User{Name: fmt.Sprintf("User %04d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+    }
+    users[42].Name = "Ada Lovelace"
+    if err := db.CreateInBatches(users, 500).Error; err != nil {
+        t.Fatal(err)
+    }
+    if err := db.Exec("ANALYZE users").Error; err != nil {
+        t.Fatal(err)
+    }
+
+    var statement string
+    err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+        statement = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    res, err := (&server{db: db}).ListUsers(context.Background(), &pb.ListUsersRequest{NamePrefix: "ADA", OrderBy: "created_desc"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Users) != 1 || res.Users[0].Name != "Ada Lovelace" {
+        t.Fatalf("got %v, want Ada Lovelace", res.Users)
+    }
+
+    var plan []string
+    if err := db.Raw("EXPLAIN " + statement).Scan(&plan).Error; err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(strings.Join(plan, "\n"), "idx_users_name_lower") {
+        t.Errorf("%s\nis planned without idx_users_name_lower:\n%s", statement, strings.Join(plan, "\n"))
+    }
+}
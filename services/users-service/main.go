@@ -5,89 +5,514 @@ import (
     "fmt"
     "log"
     "net"
+    "net/http"
     "os"
+    "os/signal"
+    "strconv"
+    "strings"
+    "syscall"
     "time"
 
+    "github.com/google/uuid"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/redis/go-redis/v9"
     "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
     "google.golang.org/grpc/health"
     "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/reflection"
+    "google.golang.org/grpc/status"
     "gorm.io/driver/postgres"
     "gorm.io/gorm"
+    "gorm.io/gorm/logger"
 
+    "shared/audit"
+    "shared/automigrate"
+    "shared/backfill"
+    "shared/canary"
+    "shared/dbinit"
+    "shared/drain"
+    "shared/healthcheck"
+    "shared/loadreport"
+    "shared/metrics"
+    "shared/pagination"
+    "shared/ratelimit"
+    "shared/recovery"
+    "shared/servedby"
+    "shared/slo"
+    "shared/sqlaudit"
+    "shared/statementtimeout"
+    "shared/tlsconfig"
+    "users-service/internal/journal"
     pb "users-service/proto/gen/proto"
+    pbv2 "users-service/proto/gen/proto/v2"
     consulapi "github.com/hashicorp/consul/api"
 )
 
 const serviceName = "users-service"
 const servicePort = 50051
+const metricsPort = 9090
+
+// consulRegistrationCheckInterval is how often keepRegistered checks that the
+// Consul agent still knows this instance.
+const consulRegistrationCheckInterval = 10 * time.Second
+
+// defaultMaxConcurrentRPCs is used when MAX_CONCURRENT_RPCS is not set.
+const defaultMaxConcurrentRPCs = 100
+
+// defaultShutdownTimeout is used when SHUTDOWN_TIMEOUT is not set. It stays
+// under the 10 seconds Docker waits before killing a stopped container.
+const defaultShutdownTimeout = 8 * time.Second
+
+// defaultAvailabilitySLO and defaultLatencyP99TargetMs are the objectives
+// used when SLO_AVAILABILITY and SLO_LATENCY_P99_MS are not set.
+const (
+    defaultAvailabilitySLO    = 0.999
+    defaultLatencyP99TargetMs = 300
+)
+
+// defaultCountRefreshInterval is used when BUSINESS_METRICS_INTERVAL is not set.
+const defaultCountRefreshInterval = 30 * time.Second
 
 type User struct {
     gorm.Model
-    Name  string
+    UUID  string `gorm:"type:uuid;uniqueIndex;not null;default:gen_random_uuid()"`
+    // Name is indexed for ordering by name, and lower(name) for ListUsers'
+    // name_prefix search. text_pattern_ops lets LIKE 'prefix%' use the index
+    // whatever the database's collation.
+    Name string `gorm:"index;index:idx_users_name_lower,expression:lower(name) text_pattern_ops"`
+    // Email's unique constraint is backed by an index, which serves lookups
+    // by email.
     Email string `gorm:"unique"`
+    // EmailNormalized is written alongside Email and will replace it, as a
+    // citext column, once the users.email_normalized backfill has filled it
+    // in for older rows.
+    EmailNormalized *string
+    // TOTPSecret is the user's TOTP secret, sealed by sealTOTPSecret, or nil
+    // if they have not enrolled. TOTPLastStep is the time step of the last
+    // code accepted, which no code may repeat.
+    TOTPSecret   *string `gorm:"column:totp_secret"`
+    TOTPLastStep *int64  `gorm:"column:totp_last_step"`
+}
+
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+    if u.UUID == "" {
+        u.UUID = uuid.NewString()
+    }
+    return nil
 }
 
 type server struct {
     pb.UnimplementedUserServiceServer
     db *gorm.DB
+    // socialProviders holds the configured OAuth providers by name.
+    socialProviders map[string]*socialProvider
+    // maxPageSize caps the page_size of every list RPC.
+    maxPageSize int
+    // totpKey encrypts TOTP secrets. TOTP is disabled when it is nil.
+    totpKey []byte
+    // geocoder looks up the coordinates of saved addresses. It is nil when
+    // GEOCODER_URL is unset, and addresses are then not geocoded.
+    geocoder Geocoder
+    // consentTypes is the set of consent types RecordConsent accepts.
+    consentTypes map[string]bool
+    // unsubscribeSecret signs unsubscribe tokens. They are disabled when it
+    // is nil.
+    unsubscribeSecret []byte
+}
+
+// createUser stores a user validated by the v2 create path.
+func (s *server) createUser(ctx context.Context, name, email string) (*User, error) {
+    normalized := normalizeEmail(email)
+    user := User{Name: name, Email: email, EmailNormalized: &normalized}
+    err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&user).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "create", "user", user.ID, map[string]string{"name": name, "email": email})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &user, nil
+}
+
+// normalizeEmail matches lower(btrim(email)) in the users.email_normalized
+// backfill.
+func normalizeEmail(email string) string {
+    return strings.ToLower(strings.Trim(email, " "))
 }
 
+// CreateUser adapts the v1 request to v2 and creates the user through the v2
+// path.
 func (s *server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
-    user := User{Name: req.Name, Email: req.Email}
-    if result := s.db.Create(&user); result.Error != nil {
-        return nil, result.Error
+    user, err := (&serverV2{core: s}).createUser(ctx, &pbv2.CreateUserRequest{DisplayName: req.Name, Email: req.Email})
+    if err != nil {
+        return nil, err
     }
     return &pb.UserResponse{User: &pb.User{Id: fmt.Sprint(user.ID), Name: user.Name, Email: user.Email}}, nil
 }
 
 func (s *server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserResponse, error) {
+    // A string condition is SQL to GORM, so the id must be parsed rather
+    // than passed through.
+    id, err := strconv.ParseUint(req.Id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid user id %q", req.Id)
+    }
     var user User
-    if result := s.db.First(&user, req.Id); result.Error != nil {
+    if result := s.db.WithContext(ctx).First(&user, id); result.Error != nil {
         return nil, result.Error
     }
     return &pb.UserResponse{User: &pb.User{Id: fmt.Sprint(user.ID), Name: user.Name, Email: user.Email}}, nil
 }
 
+// maxBatchGetUsers caps the ids a BatchGetUsers request may carry.
+const maxBatchGetUsers = 200
+
+// BatchGetUsers looks up many users in one query. Users and missing ids are
+// both returned in the order their ids were first requested. Ids that are not
+// numbers are reported missing rather than rejected, so the response does not
+// tell well-formed ids apart from existing ones.
+func (s *server) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+    if len(req.Ids) > maxBatchGetUsers {
+        return nil, status.Errorf(codes.InvalidArgument, "at most %d ids may be requested at once, got %d", maxBatchGetUsers, len(req.Ids))
+    }
+    var ids []string
+    var userIDs []uint64
+    seen := make(map[string]bool, len(req.Ids))
+    for _, id := range req.Ids {
+        // "7" and "007" name the same user.
+        key := id
+        userID, err := strconv.ParseUint(id, 10, 64)
+        if err == nil {
+            key = strconv.FormatUint(userID, 10)
+        }
+        if seen[key] {
+            continue
+        }
+        seen[key] = true
+        ids = append(ids, id)
+        if err == nil {
+            userIDs = append(userIDs, userID)
+        }
+    }
+
+    // Always run the query, even with no well-formed ids, so every request
+    // costs the same round trip.
+    var users []User
+    if err := s.db.WithContext(ctx).Where("id IN ?", append(userIDs, 0)).Find(&users).Error; err != nil {
+        return nil, err
+    }
+    found := make(map[uint64]*User, len(users))
+    for i := range users {
+        found[uint64(users[i].ID)] = &users[i]
+    }
+
+    res := &pb.BatchGetUsersResponse{}
+    for _, id := range ids {
+        userID, err := strconv.ParseUint(id, 10, 64)
+        user, ok := found[userID]
+        if err != nil || !ok {
+            res.MissingIds = append(res.MissingIds, id)
+            continue
+        }
+        res.Users = append(res.Users, &pb.User{Id: fmt.Sprint(user.ID), Name: user.Name, Email: user.Email})
+    }
+    return res, nil
+}
+
 func main() {
     // Wait for database to be ready
     time.Sleep(10 * time.Second)
 
     // Connect to database with retry logic
     db := connectToDatabaseWithRetry()
-    db.AutoMigrate(&User{})
+    if err := db.Use(sqlaudit.Plugin{}); err != nil {
+        log.Fatalf("Failed to install SQL audit: %v", err)
+    }
+    if err := metrics.RegisterDBStatsCollector(db, serviceName); err != nil {
+        log.Fatalf("Failed to register connection pool metrics: %v", err)
+    }
+    if err := automigrate.Run(db, &User{}, &UserPreferences{}, &SocialAccount{}, &SelfTestProbe{}, &audit.Entry{}, &Cohort{}, &UserAddress{}, &UserBackupCode{}, &UserConsent{}, &UserCommunicationPreference{}, &backfill.Progress{}); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := migrateNameTrigramIndex(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := audit.MigrateTrigger(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
+    if err := migrateCohorts(db); err != nil {
+        log.Fatalf("Failed to migrate database: %v", err)
+    }
 
     // Start gRPC server
-    lis, err := net.Listen("tcp", fmt.Sprintf(":%d", servicePort))
+    listenAddr, err := listenAddress()
+    if err != nil {
+        log.Fatalf("Invalid LISTEN_ADDR: %v", err)
+    }
+    lis, err := net.Listen("tcp", listenAddr)
     if err != nil {
         log.Fatalf("Failed to listen: %v", err)
     }
-    s := grpc.NewServer()
-    pb.RegisterUserServiceServer(s, &server{db: db})
+    apiKeys, err := loadAPIKeys()
+    if err != nil {
+        log.Fatalf("Failed to load API keys: %v", err)
+    }
+    if apiKeys == nil {
+        log.Println("API_KEYS_JSON not set, API key authentication is disabled and admin methods are denied")
+    }
+    auth := &authenticator{keys: apiKeys}
+
+    maxConcurrentRPCs := getEnvInt("MAX_CONCURRENT_RPCS", defaultMaxConcurrentRPCs)
+    limiter := newConcurrencyLimiter(maxConcurrentRPCs)
+    loadReporter := loadreport.New(limiter.inFlight, maxConcurrentRPCs)
+    servedBy := servedby.Trailer(instanceID())
+    monitor, err := slo.NewMonitor(slo.SLOConfig{
+        ServiceName:        serviceName,
+        AvailabilitySLO:    getEnvFloat("SLO_AVAILABILITY", defaultAvailabilitySLO),
+        LatencyP99TargetMs: getEnvFloat("SLO_LATENCY_P99_MS", defaultLatencyP99TargetMs),
+    }, prometheus.DefaultRegisterer)
+    if err != nil {
+        log.Fatalf("Failed to register SLO metrics: %v", err)
+    }
+    unaryInterceptors := []grpc.UnaryServerInterceptor{servedBy.UnaryServerInterceptor, loadReporter.UnaryServerInterceptor, monitor.UnaryInterceptor, limiter.unaryInterceptor, auth.unaryAuthInterceptor, auth.unaryAuthzInterceptor, sqlaudit.UnaryServerInterceptor, statementtimeout.UnaryServerInterceptor}
+    if dir := os.Getenv("JOURNAL_DIR"); dir != "" {
+        j, err := journal.Open(journal.Config{
+            Dir:          dir,
+            MaxFileSize:  int64(getEnvInt("JOURNAL_MAX_BYTES", journal.DefaultMaxFileSize)),
+            MetadataKeys: getEnvList("JOURNAL_METADATA_KEYS"),
+            MaskFields:   getEnvList("JOURNAL_MASK_FIELDS"),
+        })
+        if err != nil {
+            log.Fatalf("Failed to open request journal: %v", err)
+        }
+        // Outermost, so that rejected requests and the full latency are recorded.
+        unaryInterceptors = append([]grpc.UnaryServerInterceptor{j.UnaryInterceptor}, unaryInterceptors...)
+        log.Printf("Journaling requests to %s", dir)
+    }
+    var redisClient *redis.Client
+    if redisAddr := os.Getenv("REDIS_ADDR"); redisAddr != "" {
+        redisClient = redis.NewClient(&redis.Options{Addr: redisAddr})
+        dedupMethods := []string{pb.UserService_CreateUser_FullMethodName, pbv2.UserService_CreateUser_FullMethodName}
+        unaryInterceptors = append(unaryInterceptors, NewDeduplicationInterceptor(newRedisDeduplicationStore(redisClient), dedupTTL, dedupMethods))
+    } else {
+        log.Println("REDIS_ADDR not set, request deduplication is disabled")
+    }
+    if rateLimit := getEnvInt("RATE_LIMIT", 0); rateLimit > 0 {
+        var store ratelimit.RateLimitStore = ratelimit.NewLocalRateLimiter()
+        if redisClient != nil {
+            store = ratelimit.NewRedisRateLimiter(redisClient)
+        }
+        window := getEnvDuration("RATE_LIMIT_WINDOW", time.Second)
+        unaryInterceptors = append(unaryInterceptors, ratelimit.NewRateLimitInterceptor(store, rateLimit, window, rateLimitKey))
+    }
+    var alerter recovery.AlertFunc
+    if url := os.Getenv("PANIC_WEBHOOK_URL"); url != "" {
+        alerter = recovery.WebhookAlerter(url, instanceID())
+    }
+    // Outermost, so a panic anywhere in the chain becomes an Internal error.
+    unaryInterceptors = append([]grpc.UnaryServerInterceptor{recovery.NewRecoveryInterceptor(alerter)}, unaryInterceptors...)
+    creds, err := tlsconfig.ServerCredentials()
+    if err != nil {
+        log.Fatalf("Invalid TLS configuration: %v", err)
+    }
+    s := grpc.NewServer(
+        grpc.Creds(creds),
+        grpc.ChainUnaryInterceptor(unaryInterceptors...),
+        grpc.ChainStreamInterceptor(recovery.NewStreamRecoveryInterceptor(alerter), servedBy.StreamServerInterceptor, loadReporter.StreamServerInterceptor, limiter.streamInterceptor, auth.streamAuthInterceptor, auth.streamAuthzInterceptor, statementtimeout.StreamServerInterceptor),
+    )
+    consul, err := newConsulClient()
+    if err != nil {
+        log.Fatalf("Failed to create consul client: %v", err)
+    }
+    tester := &selfTester{db: db, consul: consul, redis: redisClient}
+
+    srv := &server{db: db, socialProviders: loadSocialProviders(), maxPageSize: getEnvInt("MAX_PAGE_SIZE", pagination.DefaultMaxPageSize), totpKey: loadTOTPKey(), consentTypes: loadConsentTypes(), unsubscribeSecret: loadUnsubscribeSecret()}
+    if url := os.Getenv("GEOCODER_URL"); url != "" {
+        srv.geocoder = newHTTPGeocoder(url)
+    }
+    pb.RegisterUserServiceServer(s, srv)
+    pbv2.RegisterUserServiceServer(s, &serverV2{core: srv})
+    pb.RegisterSelfTestServiceServer(s, &selfTestServer{tester: tester})
+    backfills := backfill.NewRunner(db, getEnvInt("BACKFILL_BATCH_SIZE", defaultBackfillBatchSize), getEnvDuration("BACKFILL_PAUSE", defaultBackfillPause))
+    registerBackfills(backfills)
+    pb.RegisterBackfillServiceServer(s, &backfillServer{runner: backfills})
+    pb.RegisterSnapshotServiceServer(s, &snapshotServer{db: db, allowRestore: getEnvBool("ALLOW_RESTORE", false)})
+    pb.RegisterAuditServiceServer(s, &auditServer{db: db, maxPageSize: srv.maxPageSize})
+    canaryConfig := canary.FromEnv()
+    pb.RegisterDebugServiceServer(s, &debugServer{db: db, consul: consul, canary: canaryConfig, flags: map[string]bool{
+        "api_key_auth":    apiKeys != nil,
+        "redis":           redisClient != nil,
+        "rate_limit":      getEnvInt("RATE_LIMIT", 0) > 0,
+        "request_journal": os.Getenv("JOURNAL_DIR") != "",
+        "allow_restore":   getEnvBool("ALLOW_RESTORE", false),
+        "consul_required": getEnvBool("CONSUL_REQUIRED", false),
+        "tls":             tlsconfig.Enabled(),
+    }})
+    reflection.Register(s)
+
+    // ctx is cancelled on SIGINT or SIGTERM, which stops the server gracefully.
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
 
     // Register health check
     healthServer := health.NewServer()
     grpc_health_v1.RegisterHealthServer(s, healthServer)
-    healthServer.SetServingStatus("users.UserService", grpc_health_v1.HealthCheckResponse_SERVING)
+    pb.RegisterDrainServiceServer(s, &drainServer{health: healthServer, limiter: limiter})
+    watcher := healthcheck.NewHealthWatcher(serviceName, db, healthServer, getEnvDuration("HEALTH_CHECK_INTERVAL", healthcheck.DefaultInterval), "users.UserService", "users.v2.UserService")
+    warmUp(db, watcher, getEnvDuration("READINESS_DELAY", 0))
+    go watcher.Run(ctx)
+
+    // Register with Consul. Unless CONSUL_REQUIRED is set, failing to is not
+    // fatal: the instance serves anyway and keepRegistered retries.
+    deregisterStaleInstance(consul)
+    if err := registerServiceWithConsul(consul, canaryConfig); err != nil {
+        if getEnvBool("CONSUL_REQUIRED", false) {
+            log.Fatalf("Failed to register with Consul: %v", err)
+        }
+        log.Printf("WARNING: %s is not registered with Consul and cannot be discovered until it is; retrying every %v: %v", serviceName, consulRegistrationCheckInterval, err)
+    }
+    keepRegistered(consul, canaryConfig)
 
-    // Register with Consul
-    if err := registerServiceWithConsul(); err != nil {
-        log.Fatalf("Failed to register with Consul: %v", err)
+    var readyz http.HandlerFunc
+    if interval := getEnvDuration("SELF_TEST_INTERVAL", 0); interval > 0 {
+        tester.runPeriodically(interval)
+        readyz = tester.readyzHandler
     }
+    metrics.StartServer(serviceName, metricsPort, readyz, monitor)
+    startUserCountCollector(db, getEnvDuration("BUSINESS_METRICS_INTERVAL", defaultCountRefreshInterval))
+    backfills.Start(ctx)
 
-    log.Printf("%s gRPC server listening at %v", serviceName, lis.Addr())
+    log.Printf("%s gRPC server listening at %v (max %d concurrent RPCs)", serviceName, lis.Addr(), maxConcurrentRPCs)
+    shutdownTimeout := getEnvDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+    go func() {
+        <-ctx.Done()
+        log.Printf("Shutting down %s, waiting up to %v for %d in-flight requests", serviceName, shutdownTimeout, limiter.inFlight())
+        healthServer.Shutdown()
+        drain.Stop(s, serviceName, limiter.inFlight, shutdownTimeout)
+    }()
     if err := s.Serve(lis); err != nil {
         log.Fatalf("Failed to serve: %v", err)
     }
 }
 
+// listenAddress builds the gRPC bind address from LISTEN_ADDR, which holds
+// only the host part (e.g. "127.0.0.1"). Unset or ":" binds all interfaces.
+func listenAddress() (string, error) {
+    host := strings.Trim(strings.TrimSuffix(os.Getenv("LISTEN_ADDR"), ":"), "[]")
+    if host == "" {
+        return fmt.Sprintf(":%d", servicePort), nil
+    }
+    ip := net.ParseIP(host)
+    if ip == nil {
+        return "", fmt.Errorf("%q is not an IP address", host)
+    }
+    if ip.IsLoopback() {
+        log.Printf("Listening on loopback %s only; Consul health checks from other containers will fail", host)
+    }
+    return net.JoinHostPort(host, strconv.Itoa(servicePort)), nil
+}
+
+func getEnvInt(key string, fallback int) int {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    n, err := strconv.Atoi(value)
+    if err != nil || n <= 0 {
+        log.Printf("Invalid %s=%q, using default %d", key, value, fallback)
+        return fallback
+    }
+    return n
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+    var values []string
+    for _, value := range strings.Split(os.Getenv(key), ",") {
+        if value = strings.TrimSpace(value); value != "" {
+            values = append(values, value)
+        }
+    }
+    return values
+}
+
+func getEnvBool(key string, fallback bool) bool {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    b, err := strconv.ParseBool(value)
+    if err != nil {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return b
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    f, err := strconv.ParseFloat(value, 64)
+    if err != nil || f <= 0 {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return f
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+    value := os.Getenv(key)
+    if value == "" {
+        return fallback
+    }
+    d, err := time.ParseDuration(value)
+    if err != nil || d <= 0 {
+        log.Printf("Invalid %s=%q, using default %v", key, value, fallback)
+        return fallback
+    }
+    return d
+}
+
+// newDatabaseLogger logs queries slower than SLOW_QUERY_THRESHOLD (default
+// 200ms) with their SQL and duration, along with GORM's warnings and errors.
+// Statements are logged with placeholders rather than parameter values, which
+// may hold personal data such as emails.
+func newDatabaseLogger() logger.Interface {
+    return logger.New(log.Default(), logger.Config{
+        SlowThreshold:             getEnvDuration("SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+        LogLevel:                  logger.Warn,
+        IgnoreRecordNotFoundError: true,
+        ParameterizedQueries:      true,
+    })
+}
+
 func connectToDatabaseWithRetry() *gorm.DB {
     dsn := "host=users-db user=user password=password dbname=users_db port=5432 sslmode=disable"
 
-    var db *gorm.DB
-    var err error
+    // Postgres cancels any statement running longer than
+    // PG_STATEMENT_TIMEOUT, even if the request's context is never cancelled.
+    if timeout := getEnvDuration("PG_STATEMENT_TIMEOUT", 0); timeout > 0 {
+        dsn = statementtimeout.DSN(dsn, timeout)
+        log.Printf("Postgres statement timeout is %v", timeout)
+    }
+
+    initSQL := dbinit.FromEnv()
+    if len(initSQL) > 0 {
+        log.Printf("Running %d init statements on each database connection", len(initSQL))
+    }
+    pool, err := dbinit.Open(dsn, initSQL)
+    if err != nil {
+        log.Fatalf("Invalid database configuration: %v", err)
+    }
 
+    var db *gorm.DB
     for i := 0; i < 30; i++ {
-        db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+        db, err = gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{Logger: newDatabaseLogger()})
         if err == nil {
             log.Println("Successfully connected to database")
             break
@@ -104,33 +529,104 @@ func connectToDatabaseWithRetry() *gorm.DB {
     return db
 }
 
-func registerServiceWithConsul() error {
+// instanceID is this instance's Consul registration ID, which is unique
+// among the service's replicas.
+func instanceID() string {
+    hostname, err := os.Hostname()
+    if err != nil {
+        return serviceName
+    }
+    return serviceName + "-" + hostname
+}
+
+func newConsulClient() (*consulapi.Client, error) {
     config := consulapi.DefaultConfig()
     if addr := os.Getenv("CONSUL_HTTP_ADDR"); addr != "" {
         config.Address = addr
     }
+    return consulapi.NewClient(config)
+}
 
-    consul, err := consulapi.NewClient(config)
-    if err != nil {
-        return err
+func registerServiceWithConsul(consul *consulapi.Client, canaryConfig canary.Config) error {
+    err := consul.Agent().ServiceRegister(serviceRegistration(canaryConfig))
+    if err == nil {
+        log.Printf("Successfully registered %s with Consul as %s at %s:%d", serviceName, instanceID(), serviceName, servicePort)
+        deregisterLegacyInstance(consul)
     }
+    return err
+}
+
+// deregisterLegacyInstance removes the registration an older release made
+// under the plain service name, before instances had their own IDs, if it
+// points at the same address as this instance. It must only run once this
+// instance is registered, so the service stays discoverable.
+func deregisterLegacyInstance(consul *consulapi.Client) {
+    if instanceID() == serviceName {
+        return
+    }
+    service, _, err := consul.Agent().Service(serviceName, nil)
+    if err != nil || service == nil {
+        return
+    }
+    if service.Service != serviceName || service.Address != serviceName || service.Port != servicePort {
+        log.Printf("Leaving legacy %s registration in Consul, it points at %s:%d rather than this instance", serviceName, service.Address, service.Port)
+        return
+    }
+    if err := consul.Agent().ServiceDeregister(serviceName); err != nil {
+        log.Printf("Failed to deregister legacy %s registration from Consul: %v", serviceName, err)
+        return
+    }
+    log.Printf("Deregistered legacy %s registration from Consul, replaced by %s", serviceName, instanceID())
+}
 
+// serviceRegistration describes this instance to Consul, with canaryConfig's
+// metadata if it is a canary.
+func serviceRegistration(canaryConfig canary.Config) *consulapi.AgentServiceRegistration {
     // Use the service name as the address within the Docker network
-    registration := &consulapi.AgentServiceRegistration{
-        ID:      serviceName,
+    return &consulapi.AgentServiceRegistration{
+        ID:      instanceID(),
         Name:    serviceName,
         Port:    servicePort,
         Address: serviceName,
+        Meta:    canaryConfig.ConsulMeta(),
         Check: &consulapi.AgentServiceCheck{
             GRPC:                           fmt.Sprintf("%s:%d", serviceName, servicePort),
+            GRPCUseTLS:                     tlsconfig.Enabled(),
             Interval:                       "10s",
             DeregisterCriticalServiceAfter: "30s",
         },
     }
+}
 
-    err = consul.Agent().ServiceRegister(registration)
-    if err == nil {
-        log.Printf("Successfully registered %s with Consul at %s:%d", serviceName, serviceName, servicePort)
+// deregisterStaleInstance removes a registration left behind under this
+// instance's ID by a previous process, e.g. one that was killed before it
+// could deregister. Its health check may still report the dead instance as
+// passing, so registering over it would let Consul briefly route to it.
+func deregisterStaleInstance(consul *consulapi.Client) {
+    id := instanceID()
+    service, _, err := consul.Agent().Service(id, nil)
+    if err != nil || service == nil {
+        return
     }
-    return err
+    if err := consul.Agent().ServiceDeregister(id); err != nil {
+        log.Printf("Failed to deregister stale %s registration from Consul: %v", id, err)
+        return
+    }
+    log.Printf("Deregistered stale %s registration from Consul before registering", id)
+}
+
+// keepRegistered registers the instance again whenever the Consul agent does
+// not know it, e.g. because registration failed at startup or the agent
+// restarted and lost it.
+func keepRegistered(consul *consulapi.Client, canaryConfig canary.Config) {
+    go func() {
+        for range time.Tick(consulRegistrationCheckInterval) {
+            if service, _, err := consul.Agent().Service(instanceID(), nil); err == nil && service != nil {
+                continue
+            }
+            if err := registerServiceWithConsul(consul, canaryConfig); err != nil {
+                log.Printf("Still not registered with Consul, retrying in %v: %v", consulRegistrationCheckInterval, err)
+            }
+        }
+    }()
 }
\ No newline at end of file
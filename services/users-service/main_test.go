@@ -0,0 +1,89 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "reflect"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+func TestGetUserRejectsNonNumericIDs(t *testing.T) {
+    // No statement is expected: the id never reaches GORM, which would run a
+    // string condition as SQL.
+    db, _ := newMockDB(t)
+    for _, id := range []string{"1 OR 1=1", "1; DROP TABLE users", "", "-1"} {
+        if _, err := (&server{db: db}).GetUser(context.Background(), &pb.GetUserRequest{Id: id}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("GetUser(%q): %v, want InvalidArgument", id, err)
+        }
+    }
+}
+
+func TestBatchGetUsersMixesFoundAndMissing(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE id IN \(\$1,\$2,\$3,\$4\) AND "users"."deleted_at" IS NULL`).
+        WithArgs(9, 3, 5, 0).
+        WillReturnRows(userRows().AddRow(3, "Grace", "grace@example.com", time.Now()).AddRow(9, "Ada", "ada@example.com", time.Now()))
+
+    res, err := (&server{db: db}).BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"9", "mug", "3", "5"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var found []string
+    for _, u := range res.Users {
+        found = append(found, u.Id)
+    }
+    if want := []string{"9", "3"}; !reflect.DeepEqual(found, want) {
+        t.Errorf("found %v, want %v in request order", found, want)
+    }
+    if want := []string{"mug", "5"}; !reflect.DeepEqual(res.MissingIds, want) {
+        t.Errorf("missing %v, want %v in request order", res.MissingIds, want)
+    }
+}
+
+func TestBatchGetUsersDropsDuplicateIDs(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE id IN \(\$1,\$2,\$3\)`).
+        WithArgs(7, 8, 0).
+        WillReturnRows(userRows().AddRow(7, "Ada", "ada@example.com", time.Now()))
+
+    res, err := (&server{db: db}).BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"7", "8", "007", "7", "8"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Users) != 1 || res.Users[0].Id != "7" {
+        t.Errorf("found %v, want user 7 once", res.Users)
+    }
+    if want := []string{"8"}; !reflect.DeepEqual(res.MissingIds, want) {
+        t.Errorf("missing %v, want %v", res.MissingIds, want)
+    }
+}
+
+func TestBatchGetUsersAlwaysQueries(t *testing.T) {
+    // Malformed ids cost the same round trip as unknown ones.
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE id IN \(\$1\)`).WithArgs(0).WillReturnRows(userRows())
+
+    res, err := (&server{db: db}).BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"mug", "-1"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := []string{"mug", "-1"}; len(res.Users) != 0 || !reflect.DeepEqual(res.MissingIds, want) {
+        t.Errorf("got users %v and missing %v, want only %v missing", res.Users, res.MissingIds, want)
+    }
+}
+
+func TestBatchGetUsersRejectsLargeBatches(t *testing.T) {
+    ids := make([]string, maxBatchGetUsers+1)
+    for i := range ids {
+        ids[i] = fmt.Sprint(i + 1)
+    }
+    if _, err := (&server{}).BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: ids}); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("batch of %d = %v, want InvalidArgument", len(ids), err)
+    }
+}
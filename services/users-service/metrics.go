@@ -0,0 +1,34 @@
+package main
+
+import (
+    "log"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "gorm.io/gorm"
+)
+
+var usersTotal = prometheus.NewGauge(prometheus.GaugeOpts{
+    Name: "users_total",
+    Help: "Number of users in the database, refreshed periodically.",
+})
+
+func init() {
+    prometheus.MustRegister(usersTotal)
+}
+
+// startUserCountCollector refreshes users_total every interval. A failed
+// count is logged and the gauge keeps its last value.
+func startUserCountCollector(db *gorm.DB, interval time.Duration) {
+    go func() {
+        for {
+            var count int64
+            if err := db.Model(&User{}).Count(&count).Error; err != nil {
+                log.Printf("Failed to count users for metrics: %v", err)
+            } else {
+                usersTotal.Set(float64(count))
+            }
+            time.Sleep(interval)
+        }
+    }()
+}
@@ -0,0 +1,5 @@
+-- Drops everything the baseline created.
+
+DROP TABLE IF EXISTS self_test_probes;
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,28 @@
+-- Baseline: the schema users-service created with GORM AutoMigrate before
+-- migrations were versioned. Every statement is idempotent, so databases the
+-- service already migrated accept it.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "uuid" uuid NOT NULL DEFAULT gen_random_uuid(),
+    "name" text,
+    "email" text UNIQUE,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_uuid" ON "users" ("uuid");
+
+CREATE TABLE IF NOT EXISTS "user_preferences" (
+    "user_id" bigserial,
+    "preferences" jsonb NOT NULL DEFAULT '{}',
+    PRIMARY KEY ("user_id")
+);
+
+CREATE TABLE IF NOT EXISTS "self_test_probes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS backfill_progress;
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_normalized";
@@ -0,0 +1,16 @@
+-- users.email_normalized and the progress table for resumable backfills. The column is
+-- nullable until its backfill has filled in the older rows.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_normalized" text;
+
+CREATE TABLE IF NOT EXISTS "backfill_progress" (
+    "name" text,
+    "table" text NOT NULL,
+    "last_id" bigint NOT NULL,
+    "processed" bigint NOT NULL,
+    "done" boolean NOT NULL,
+    "started_at" timestamptz,
+    "updated_at" timestamptz,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("name")
+);
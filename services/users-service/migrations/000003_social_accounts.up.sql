@@ -0,0 +1,17 @@
+-- Social account links (social.go). A provider account belongs to at most
+-- one user, and a user has at most one account per provider.
+
+CREATE TABLE IF NOT EXISTS "social_accounts" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "provider" text NOT NULL,
+    "provider_user_id" text NOT NULL,
+    "access_token" text NOT NULL,
+    "refresh_token" text,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_social_accounts_user_provider" ON "social_accounts" ("user_id","provider");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_social_accounts_provider_account" ON "social_accounts" ("provider","provider_user_id");
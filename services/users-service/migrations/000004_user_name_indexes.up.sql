@@ -0,0 +1,5 @@
+-- Indexes for ListUsers (listusers.go): idx_users_name serves the name_asc
+-- ordering and idx_users_name_lower the case-insensitive name_prefix search.
+
+CREATE INDEX IF NOT EXISTS "idx_users_name" ON "users" ("name");
+CREATE INDEX IF NOT EXISTS "idx_users_name_lower" ON "users" (lower(name) text_pattern_ops);
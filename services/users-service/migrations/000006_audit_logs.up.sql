@@ -0,0 +1,24 @@
+-- The append-only audit log of mutations (shared/audit). A trigger rejects
+-- updates and deletes of entries.
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" bigserial,
+    "action" text NOT NULL,
+    "entity_type" text NOT NULL,
+    "entity_id" text NOT NULL,
+    "actor" text NOT NULL,
+    "timestamp" timestamptz NOT NULL,
+    "details" jsonb NOT NULL DEFAULT '{}',
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity" ON "audit_logs" ("entity_type","entity_id");
+
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,13 @@
+-- Registration cohorts (cohorts.go). The exclusion constraint keeps cohorts
+-- from overlapping, so each user is in at most one.
+
+CREATE TABLE IF NOT EXISTS "cohorts" (
+    "id" bigserial,
+    "name" text NOT NULL UNIQUE,
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "cohorts_no_overlap" EXCLUDE USING gist (tstzrange("start_date", "end_date") WITH &&)
+);
+CREATE INDEX IF NOT EXISTS "idx_users_created_at" ON "users" ("created_at","id");
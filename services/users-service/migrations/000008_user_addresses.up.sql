@@ -0,0 +1,21 @@
+-- Postal addresses of users (addresses.go). The partial unique index allows
+-- each user one default address.
+
+CREATE TABLE IF NOT EXISTS "user_addresses" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "line1" text NOT NULL,
+    "line2" text NOT NULL DEFAULT '',
+    "city" text NOT NULL,
+    "state_code" text NOT NULL DEFAULT '',
+    "country_code" char(2) NOT NULL,
+    "postal_code" text NOT NULL DEFAULT '',
+    "latitude" decimal,
+    "longitude" decimal,
+    "is_default" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_addresses_user_id" ON "user_addresses" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_addresses_default" ON "user_addresses" ("user_id") WHERE is_default;
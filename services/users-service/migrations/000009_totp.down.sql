@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_backup_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
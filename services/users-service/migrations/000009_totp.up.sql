@@ -0,0 +1,16 @@
+-- TOTP two-factor authentication (totp.go): each user's encrypted secret
+-- and the time step of the last code accepted, and their hashed single-use
+-- backup codes.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_secret" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_last_step" bigint;
+
+CREATE TABLE IF NOT EXISTS "user_backup_codes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "code_hash" text NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_backup_codes_user_id" ON "user_backup_codes" ("user_id");
@@ -0,0 +1,14 @@
+-- GDPR consent records (consent.go). Withdrawn consents keep their row, with
+-- withdrawn_at set.
+
+CREATE TABLE IF NOT EXISTS "user_consents" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "consent_type" text NOT NULL,
+    "version" text NOT NULL,
+    "consented_at" timestamptz NOT NULL,
+    "ip_address" text NOT NULL DEFAULT '',
+    "withdrawn_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_consents_user_id" ON "user_consents" ("user_id");
@@ -0,0 +1,12 @@
+-- Communication preferences (commprefs.go), one row per user, channel and
+-- type. Users without a row are subscribed.
+
+CREATE TABLE IF NOT EXISTS "user_communication_preferences" (
+    "user_id" bigint,
+    "channel" varchar(8),
+    "type" varchar(16),
+    "subscribed" boolean NOT NULL,
+    "unsubscribe_token" text NOT NULL DEFAULT '',
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id", "channel", "type")
+);
@@ -0,0 +1,37 @@
+package main
+
+import (
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// newMockDB returns a GORM connection whose SQL is checked against the
+// expectations set on mock. Unexpected statements fail the test.
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+    t.Helper()
+    conn, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Error(err)
+        }
+    })
+    return db, mock
+}
+
+// expectAudit expects the audit entry a mutation of an entity writes.
+func expectAudit(mock sqlmock.Sqlmock, action, entityType string) {
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs(action, entityType, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
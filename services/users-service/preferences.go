@@ -0,0 +1,124 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "regexp"
+    "strconv"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// maxPreferenceValueSize caps a single preference value, in bytes.
+const maxPreferenceValueSize = 4096
+
+var preferenceKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,64}$`)
+
+// UserPreferences stores free-form user settings as a single JSONB object of
+// string values, so new settings do not need schema changes.
+type UserPreferences struct {
+    UserID      uint   `gorm:"primaryKey"`
+    Preferences string `gorm:"type:jsonb;not null;default:'{}'"`
+}
+
+func validatePreferenceKey(key string) error {
+    if !preferenceKeyPattern.MatchString(key) {
+        return status.Errorf(codes.InvalidArgument, "invalid preference key %q: must match %s", key, preferenceKeyPattern)
+    }
+    return nil
+}
+
+func (s *server) findUser(ctx context.Context, id string) (*User, error) {
+    userID, err := strconv.ParseUint(id, 10, 64)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid user id %q", id)
+    }
+    var user User
+    if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "user %s not found", id)
+        }
+        return nil, err
+    }
+    return &user, nil
+}
+
+func (s *server) SetPreference(ctx context.Context, req *pb.SetPreferenceRequest) (*pb.SetPreferenceResponse, error) {
+    if err := validatePreferenceKey(req.Key); err != nil {
+        return nil, err
+    }
+    if len(req.Value) > maxPreferenceValueSize {
+        return nil, status.Errorf(codes.InvalidArgument, "preference value exceeds %d bytes", maxPreferenceValueSize)
+    }
+
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        // jsonb_set only touches the one key, so concurrent writes to
+        // different preferences of the same user do not overwrite each other.
+        err := tx.Exec(`
+            INSERT INTO user_preferences (user_id, preferences)
+            VALUES (?, jsonb_build_object(?::text, ?::text))
+            ON CONFLICT (user_id) DO UPDATE
+            SET preferences = jsonb_set(user_preferences.preferences, ARRAY[?::text], to_jsonb(?::text))`,
+            user.ID, req.Key, req.Value, req.Key, req.Value,
+        ).Error
+        if err != nil {
+            return err
+        }
+        // Preference values may be personal, so only the key is recorded.
+        return recordAudit(ctx, tx, "set_preference", "user", user.ID, map[string]string{"key": req.Key})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.SetPreferenceResponse{}, nil
+}
+
+// GetPreferences returns the requested preferences, or all of them when no
+// keys are given. Keys that are not set are left out of the response.
+func (s *server) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.GetPreferencesResponse, error) {
+    for _, key := range req.Keys {
+        if err := validatePreferenceKey(key); err != nil {
+            return nil, err
+        }
+    }
+
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+
+    var prefs UserPreferences
+    err = s.db.WithContext(ctx).Where("user_id = ?", user.ID).Take(&prefs).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return &pb.GetPreferencesResponse{Preferences: map[string]string{}}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    all := make(map[string]string)
+    if err := json.Unmarshal([]byte(prefs.Preferences), &all); err != nil {
+        return nil, status.Errorf(codes.Internal, "corrupt preferences for user %s: %v", req.UserId, err)
+    }
+    if len(req.Keys) == 0 {
+        return &pb.GetPreferencesResponse{Preferences: all}, nil
+    }
+
+    selected := make(map[string]string, len(req.Keys))
+    for _, key := range req.Keys {
+        if value, ok := all[key]; ok {
+            selected[key] = value
+        }
+    }
+    return &pb.GetPreferencesResponse{Preferences: selected}, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+func expectUser(mock sqlmock.Sqlmock, id int) {
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).WithArgs(id).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(id, "Ada", "ada@example.com"))
+}
+
+func expectPreferences(mock sqlmock.Sqlmock, userID int, prefs string) {
+    mock.ExpectQuery(`SELECT \* FROM "user_preferences" WHERE user_id = \$1`).WithArgs(userID).
+        WillReturnRows(sqlmock.NewRows([]string{"user_id", "preferences"}).AddRow(userID, prefs))
+}
+
+func TestValidatePreferenceKey(t *testing.T) {
+    tests := []struct {
+        key  string
+        want codes.Code
+    }{
+        {"theme", codes.OK},
+        {"ui.sidebar-width_2", codes.OK},
+        {strings.Repeat("k", 64), codes.OK},
+        {"", codes.InvalidArgument},
+        {strings.Repeat("k", 65), codes.InvalidArgument},
+        {"has space", codes.InvalidArgument},
+        {"quote'", codes.InvalidArgument},
+        {"{theme}", codes.InvalidArgument},
+    }
+    for _, tt := range tests {
+        if got := status.Code(validatePreferenceKey(tt.key)); got != tt.want {
+            t.Errorf("validatePreferenceKey(%q) = %v, want %v", tt.key, got, tt.want)
+        }
+    }
+}
+
+func TestSetPreferenceRejectsBadInput(t *testing.T) {
+    tests := []struct {
+        name string
+        req  *pb.SetPreferenceRequest
+        want codes.Code
+    }{
+        {"bad key", &pb.SetPreferenceRequest{UserId: "1", Key: "a b", Value: "x"}, codes.InvalidArgument},
+        {"value too large", &pb.SetPreferenceRequest{UserId: "1", Key: "theme", Value: strings.Repeat("x", maxPreferenceValueSize+1)}, codes.InvalidArgument},
+        {"bad user id", &pb.SetPreferenceRequest{UserId: "1 OR 1=1", Key: "theme", Value: "x"}, codes.InvalidArgument},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            // No statement is expected: the request is rejected up front.
+            db, _ := newMockDB(t)
+            _, err := (&server{db: db}).SetPreference(context.Background(), tt.req)
+            if status.Code(err) != tt.want {
+                t.Errorf("err = %v, want %v", err, tt.want)
+            }
+        })
+    }
+}
+
+func TestSetPreferenceUnknownUser(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+    _, err := (&server{db: db}).SetPreference(context.Background(), &pb.SetPreferenceRequest{UserId: "9", Key: "theme", Value: "dark"})
+    if status.Code(err) != codes.NotFound {
+        t.Errorf("err = %v, want NotFound", err)
+    }
+}
+
+func TestSetPreferenceUpdatesOneKey(t *testing.T) {
+    db, mock := newMockDB(t)
+    expectUser(mock, 7)
+    // The upsert must change only the given key with jsonb_set, not replace
+    // the whole document.
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO user_preferences .* ON CONFLICT \(user_id\) DO UPDATE\s+SET preferences = jsonb_set\(user_preferences.preferences, ARRAY\[\$4::text\], to_jsonb\(\$5::text\)\)`).
+        WithArgs(7, "theme", "dark", "theme", "dark").
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    // The audit entry records the key but not the value.
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("set_preference", "user", "7", anonymousActor, sqlmock.AnyArg(), `{"key":"theme"}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    _, err := (&server{db: db}).SetPreference(context.Background(), &pb.SetPreferenceRequest{UserId: "7", Key: "theme", Value: "dark"})
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestSetPreferenceAcceptsMaxSizeValue(t *testing.T) {
+    db, mock := newMockDB(t)
+    value := strings.Repeat("x", maxPreferenceValueSize)
+    expectUser(mock, 7)
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO user_preferences`).
+        WithArgs(7, "note", value, "note", value).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    expectAudit(mock, "set_preference", "user")
+    mock.ExpectCommit()
+
+    _, err := (&server{db: db}).SetPreference(context.Background(), &pb.SetPreferenceRequest{UserId: "7", Key: "note", Value: value})
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestGetPreferencesFiltersKeys(t *testing.T) {
+    const stored = `{"theme": "dark", "lang": "en", "tz": "UTC"}`
+    tests := []struct {
+        name string
+        keys []string
+        want map[string]string
+    }{
+        {"all", nil, map[string]string{"theme": "dark", "lang": "en", "tz": "UTC"}},
+        {"some", []string{"theme", "tz"}, map[string]string{"theme": "dark", "tz": "UTC"}},
+        {"unset keys left out", []string{"theme", "font"}, map[string]string{"theme": "dark"}},
+        {"none set", []string{"font"}, map[string]string{}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, mock := newMockDB(t)
+            expectUser(mock, 7)
+            expectPreferences(mock, 7, stored)
+
+            res, err := (&server{db: db}).GetPreferences(context.Background(), &pb.GetPreferencesRequest{UserId: "7", Keys: tt.keys})
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(res.Preferences) != len(tt.want) {
+                t.Fatalf("preferences = %v, want %v", res.Preferences, tt.want)
+            }
+            for key, value := range tt.want {
+                if res.Preferences[key] != value {
+                    t.Errorf("preferences = %v, want %v", res.Preferences, tt.want)
+                    break
+                }
+            }
+        })
+    }
+}
+
+func TestGetPreferencesWithoutRow(t *testing.T) {
+    db, mock := newMockDB(t)
+    expectUser(mock, 7)
+    mock.ExpectQuery(`SELECT \* FROM "user_preferences"`).WillReturnRows(sqlmock.NewRows([]string{"user_id", "preferences"}))
+
+    res, err := (&server{db: db}).GetPreferences(context.Background(), &pb.GetPreferencesRequest{UserId: "7"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Preferences == nil || len(res.Preferences) != 0 {
+        t.Errorf("preferences = %#v, want an empty map", res.Preferences)
+    }
+}
+
+func TestGetPreferencesRejectsBadKeys(t *testing.T) {
+    db, _ := newMockDB(t)
+    _, err := (&server{db: db}).GetPreferences(context.Background(), &pb.GetPreferencesRequest{UserId: "7", Keys: []string{"theme", "bad key"}})
+    if status.Code(err) != codes.InvalidArgument {
+        t.Errorf("err = %v, want InvalidArgument", err)
+    }
+}
+
+func TestGetPreferencesCorruptDocument(t *testing.T) {
+    db, mock := newMockDB(t)
+    expectUser(mock, 7)
+    expectPreferences(mock, 7, `{"theme": 1}`)
+
+    _, err := (&server{db: db}).GetPreferences(context.Background(), &pb.GetPreferencesRequest{UserId: "7"})
+    if status.Code(err) != codes.Internal {
+        t.Errorf("err = %v, want Internal", err)
+    }
+}
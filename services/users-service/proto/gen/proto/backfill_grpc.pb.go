@@ -0,0 +1,121 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/backfill.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BackfillService_ListBackfills_FullMethodName = "/backfill.BackfillService/ListBackfills"
+)
+
+// BackfillServiceClient is the client API for BackfillService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BackfillServiceClient interface {
+	ListBackfills(ctx context.Context, in *ListBackfillsRequest, opts ...grpc.CallOption) (*ListBackfillsResponse, error)
+}
+
+type backfillServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackfillServiceClient(cc grpc.ClientConnInterface) BackfillServiceClient {
+	return &backfillServiceClient{cc}
+}
+
+func (c *backfillServiceClient) ListBackfills(ctx context.Context, in *ListBackfillsRequest, opts ...grpc.CallOption) (*ListBackfillsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBackfillsResponse)
+	err := c.cc.Invoke(ctx, BackfillService_ListBackfills_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BackfillServiceServer is the server API for BackfillService service.
+// All implementations must embed UnimplementedBackfillServiceServer
+// for forward compatibility.
+type BackfillServiceServer interface {
+	ListBackfills(context.Context, *ListBackfillsRequest) (*ListBackfillsResponse, error)
+	mustEmbedUnimplementedBackfillServiceServer()
+}
+
+// UnimplementedBackfillServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBackfillServiceServer struct{}
+
+func (UnimplementedBackfillServiceServer) ListBackfills(context.Context, *ListBackfillsRequest) (*ListBackfillsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackfills not implemented")
+}
+func (UnimplementedBackfillServiceServer) mustEmbedUnimplementedBackfillServiceServer() {}
+func (UnimplementedBackfillServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeBackfillServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BackfillServiceServer will
+// result in compilation errors.
+type UnsafeBackfillServiceServer interface {
+	mustEmbedUnimplementedBackfillServiceServer()
+}
+
+func RegisterBackfillServiceServer(s grpc.ServiceRegistrar, srv BackfillServiceServer) {
+	// If the following call panics, it indicates UnimplementedBackfillServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BackfillService_ServiceDesc, srv)
+}
+
+func _BackfillService_ListBackfills_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackfillsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackfillServiceServer).ListBackfills(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackfillService_ListBackfills_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackfillServiceServer).ListBackfills(ctx, req.(*ListBackfillsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BackfillService_ServiceDesc is the grpc.ServiceDesc for BackfillService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BackfillService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "backfill.BackfillService",
+	HandlerType: (*BackfillServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBackfills",
+			Handler:    _BackfillService_ListBackfills_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/backfill.proto",
+}
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v3.21.12
+// source: proto/snapshot.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SnapshotDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotDataRequest) Reset() {
+	*x = SnapshotDataRequest{}
+	mi := &file_proto_snapshot_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotDataRequest) ProtoMessage() {}
+
+func (x *SnapshotDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_snapshot_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotDataRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+type SnapshotChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	mi := &file_proto_snapshot_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_snapshot_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_proto_snapshot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SnapshotChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type RestoreDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          map[string]int64       `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreDataResponse) Reset() {
+	*x = RestoreDataResponse{}
+	mi := &file_proto_snapshot_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreDataResponse) ProtoMessage() {}
+
+func (x *RestoreDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_snapshot_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreDataResponse.ProtoReflect.Descriptor instead.
+func (*RestoreDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RestoreDataResponse) GetRows() map[string]int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+var File_proto_snapshot_proto protoreflect.FileDescriptor
+
+const file_proto_snapshot_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/snapshot.proto\x12\bsnapshot\"\x15\n" +
+	"\x13SnapshotDataRequest\"#\n" +
+	"\rSnapshotChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\x8b\x01\n" +
+	"\x13RestoreDataResponse\x12;\n" +
+	"\x04rows\x18\x01 \x03(\v2'.snapshot.RestoreDataResponse.RowsEntryR\x04rows\x1a7\n" +
+	"\tRowsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\xa4\x01\n" +
+	"\x0fSnapshotService\x12H\n" +
+	"\fSnapshotData\x12\x1d.snapshot.SnapshotDataRequest\x1a\x17.snapshot.SnapshotChunk0\x01\x12G\n" +
+	"\vRestoreData\x12\x17.snapshot.SnapshotChunk\x1a\x1d.snapshot.RestoreDataResponse(\x01B\x11Z\x0f./proto/gen;genb\x06proto3"
+
+var (
+	file_proto_snapshot_proto_rawDescOnce sync.Once
+	file_proto_snapshot_proto_rawDescData []byte
+)
+
+func file_proto_snapshot_proto_rawDescGZIP() []byte {
+	file_proto_snapshot_proto_rawDescOnce.Do(func() {
+		file_proto_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_snapshot_proto_rawDesc), len(file_proto_snapshot_proto_rawDesc)))
+	})
+	return file_proto_snapshot_proto_rawDescData
+}
+
+var file_proto_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_snapshot_proto_goTypes = []any{
+	(*SnapshotDataRequest)(nil), // 0: snapshot.SnapshotDataRequest
+	(*SnapshotChunk)(nil),       // 1: snapshot.SnapshotChunk
+	(*RestoreDataResponse)(nil), // 2: snapshot.RestoreDataResponse
+	nil,                         // 3: snapshot.RestoreDataResponse.RowsEntry
+}
+var file_proto_snapshot_proto_depIdxs = []int32{
+	3, // 0: snapshot.RestoreDataResponse.rows:type_name -> snapshot.RestoreDataResponse.RowsEntry
+	0, // 1: snapshot.SnapshotService.SnapshotData:input_type -> snapshot.SnapshotDataRequest
+	1, // 2: snapshot.SnapshotService.RestoreData:input_type -> snapshot.SnapshotChunk
+	1, // 3: snapshot.SnapshotService.SnapshotData:output_type -> snapshot.SnapshotChunk
+	2, // 4: snapshot.SnapshotService.RestoreData:output_type -> snapshot.RestoreDataResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_snapshot_proto_init() }
+func file_proto_snapshot_proto_init() {
+	if File_proto_snapshot_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_snapshot_proto_rawDesc), len(file_proto_snapshot_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_snapshot_proto_goTypes,
+		DependencyIndexes: file_proto_snapshot_proto_depIdxs,
+		MessageInfos:      file_proto_snapshot_proto_msgTypes,
+	}.Build()
+	File_proto_snapshot_proto = out.File
+	file_proto_snapshot_proto_goTypes = nil
+	file_proto_snapshot_proto_depIdxs = nil
+}
@@ -9,6 +9,7 @@ package gen
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DuplicateStrategy int32
+
+const (
+	DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE  DuplicateStrategy = 0
+	DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP DuplicateStrategy = 1
+	DuplicateStrategy_DUPLICATE_STRATEGY_FUZZY_NAME       DuplicateStrategy = 2
+)
+
+// Enum value maps for DuplicateStrategy.
+var (
+	DuplicateStrategy_name = map[int32]string{
+		0: "DUPLICATE_STRATEGY_EMAIL_NORMALIZE",
+		1: "DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP",
+		2: "DUPLICATE_STRATEGY_FUZZY_NAME",
+	}
+	DuplicateStrategy_value = map[string]int32{
+		"DUPLICATE_STRATEGY_EMAIL_NORMALIZE":  0,
+		"DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP": 1,
+		"DUPLICATE_STRATEGY_FUZZY_NAME":       2,
+	}
+)
+
+func (x DuplicateStrategy) Enum() *DuplicateStrategy {
+	p := new(DuplicateStrategy)
+	*p = x
+	return p
+}
+
+func (x DuplicateStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DuplicateStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_users_proto_enumTypes[0].Descriptor()
+}
+
+func (DuplicateStrategy) Type() protoreflect.EnumType {
+	return &file_proto_users_proto_enumTypes[0]
+}
+
+func (x DuplicateStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DuplicateStrategy.Descriptor instead.
+func (DuplicateStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{0}
+}
+
 type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -221,26 +271,3361 @@ func (x *UserResponse) GetUser() *User {
 	return nil
 }
 
-var File_proto_users_proto protoreflect.FileDescriptor
+type BatchGetUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_users_proto_rawDesc = "" +
-	"\n" +
-	"\x11proto/users.proto\x12\x05users\"@\n" +
-	"\x04User\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"=\n" +
-	"\x11CreateUserRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\" \n" +
-	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
-	"\fUserResponse\x12\x1f\n" +
-	"\x04user\x18\x01 \x01(\v2\v.users.UserR\x04user2\x81\x01\n" +
-	"\vUserService\x12;\n" +
-	"\n" +
-	"CreateUser\x12\x18.users.CreateUserRequest\x1a\x13.users.UserResponse\x125\n" +
-	"\aGetUser\x12\x15.users.GetUserRequest\x1a\x13.users.UserResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
+func (x *BatchGetUsersRequest) Reset() {
+	*x = BatchGetUsersRequest{}
+	mi := &file_proto_users_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersRequest) ProtoMessage() {}
+
+func (x *BatchGetUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchGetUsersRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchGetUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	MissingIds    []string               `protobuf:"bytes,2,rep,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetUsersResponse) Reset() {
+	*x = BatchGetUsersResponse{}
+	mi := &file_proto_users_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersResponse) ProtoMessage() {}
+
+func (x *BatchGetUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchGetUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *BatchGetUsersResponse) GetMissingIds() []string {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+type SetPreferenceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPreferenceRequest) Reset() {
+	*x = SetPreferenceRequest{}
+	mi := &file_proto_users_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPreferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPreferenceRequest) ProtoMessage() {}
+
+func (x *SetPreferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPreferenceRequest.ProtoReflect.Descriptor instead.
+func (*SetPreferenceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetPreferenceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetPreferenceRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SetPreferenceRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type SetPreferenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPreferenceResponse) Reset() {
+	*x = SetPreferenceResponse{}
+	mi := &file_proto_users_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPreferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPreferenceResponse) ProtoMessage() {}
+
+func (x *SetPreferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPreferenceResponse.ProtoReflect.Descriptor instead.
+func (*SetPreferenceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{7}
+}
+
+type GetPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Keys          []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPreferencesRequest) Reset() {
+	*x = GetPreferencesRequest{}
+	mi := &file_proto_users_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreferencesRequest) ProtoMessage() {}
+
+func (x *GetPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*GetPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPreferencesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetPreferencesRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetPreferencesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Preferences   map[string]string      `protobuf:"bytes,1,rep,name=preferences,proto3" json:"preferences,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPreferencesResponse) Reset() {
+	*x = GetPreferencesResponse{}
+	mi := &file_proto_users_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPreferencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreferencesResponse) ProtoMessage() {}
+
+func (x *GetPreferencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreferencesResponse.ProtoReflect.Descriptor instead.
+func (*GetPreferencesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPreferencesResponse) GetPreferences() map[string]string {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	NamePrefix    string                 `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	OrderBy       string                 `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_proto_users_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_proto_users_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type LinkSocialAccountRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Provider          string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	AuthorizationCode string                 `protobuf:"bytes,3,opt,name=authorization_code,json=authorizationCode,proto3" json:"authorization_code,omitempty"`
+	RedirectUri       string                 `protobuf:"bytes,4,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LinkSocialAccountRequest) Reset() {
+	*x = LinkSocialAccountRequest{}
+	mi := &file_proto_users_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkSocialAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkSocialAccountRequest) ProtoMessage() {}
+
+func (x *LinkSocialAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkSocialAccountRequest.ProtoReflect.Descriptor instead.
+func (*LinkSocialAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LinkSocialAccountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LinkSocialAccountRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *LinkSocialAccountRequest) GetAuthorizationCode() string {
+	if x != nil {
+		return x.AuthorizationCode
+	}
+	return ""
+}
+
+func (x *LinkSocialAccountRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+type LinkSocialAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Linked        bool                   `protobuf:"varint,1,opt,name=linked,proto3" json:"linked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkSocialAccountResponse) Reset() {
+	*x = LinkSocialAccountResponse{}
+	mi := &file_proto_users_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkSocialAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkSocialAccountResponse) ProtoMessage() {}
+
+func (x *LinkSocialAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkSocialAccountResponse.ProtoReflect.Descriptor instead.
+func (*LinkSocialAccountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LinkSocialAccountResponse) GetLinked() bool {
+	if x != nil {
+		return x.Linked
+	}
+	return false
+}
+
+type UnlinkSocialAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkSocialAccountRequest) Reset() {
+	*x = UnlinkSocialAccountRequest{}
+	mi := &file_proto_users_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkSocialAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkSocialAccountRequest) ProtoMessage() {}
+
+func (x *UnlinkSocialAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkSocialAccountRequest.ProtoReflect.Descriptor instead.
+func (*UnlinkSocialAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnlinkSocialAccountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UnlinkSocialAccountRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type UnlinkSocialAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Unlinked      bool                   `protobuf:"varint,1,opt,name=unlinked,proto3" json:"unlinked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkSocialAccountResponse) Reset() {
+	*x = UnlinkSocialAccountResponse{}
+	mi := &file_proto_users_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkSocialAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkSocialAccountResponse) ProtoMessage() {}
+
+func (x *UnlinkSocialAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkSocialAccountResponse.ProtoReflect.Descriptor instead.
+func (*UnlinkSocialAccountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UnlinkSocialAccountResponse) GetUnlinked() bool {
+	if x != nil {
+		return x.Unlinked
+	}
+	return false
+}
+
+type FindUserBySocialAccountRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Provider       string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	ProviderUserId string                 `protobuf:"bytes,2,opt,name=provider_user_id,json=providerUserId,proto3" json:"provider_user_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FindUserBySocialAccountRequest) Reset() {
+	*x = FindUserBySocialAccountRequest{}
+	mi := &file_proto_users_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindUserBySocialAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindUserBySocialAccountRequest) ProtoMessage() {}
+
+func (x *FindUserBySocialAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindUserBySocialAccountRequest.ProtoReflect.Descriptor instead.
+func (*FindUserBySocialAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FindUserBySocialAccountRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *FindUserBySocialAccountRequest) GetProviderUserId() string {
+	if x != nil {
+		return x.ProviderUserId
+	}
+	return ""
+}
+
+type FindDuplicateUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategy      DuplicateStrategy      `protobuf:"varint,1,opt,name=strategy,proto3,enum=users.DuplicateStrategy" json:"strategy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindDuplicateUsersRequest) Reset() {
+	*x = FindDuplicateUsersRequest{}
+	mi := &file_proto_users_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDuplicateUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDuplicateUsersRequest) ProtoMessage() {}
+
+func (x *FindDuplicateUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDuplicateUsersRequest.ProtoReflect.Descriptor instead.
+func (*FindDuplicateUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FindDuplicateUsersRequest) GetStrategy() DuplicateStrategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return DuplicateStrategy_DUPLICATE_STRATEGY_EMAIL_NORMALIZE
+}
+
+type DuplicateUserGroup struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CanonicalUserId  string                 `protobuf:"bytes,1,opt,name=canonical_user_id,json=canonicalUserId,proto3" json:"canonical_user_id,omitempty"`
+	DuplicateIds     []string               `protobuf:"bytes,2,rep,name=duplicate_ids,json=duplicateIds,proto3" json:"duplicate_ids,omitempty"`
+	SimilarityReason string                 `protobuf:"bytes,3,opt,name=similarity_reason,json=similarityReason,proto3" json:"similarity_reason,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DuplicateUserGroup) Reset() {
+	*x = DuplicateUserGroup{}
+	mi := &file_proto_users_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateUserGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateUserGroup) ProtoMessage() {}
+
+func (x *DuplicateUserGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateUserGroup.ProtoReflect.Descriptor instead.
+func (*DuplicateUserGroup) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DuplicateUserGroup) GetCanonicalUserId() string {
+	if x != nil {
+		return x.CanonicalUserId
+	}
+	return ""
+}
+
+func (x *DuplicateUserGroup) GetDuplicateIds() []string {
+	if x != nil {
+		return x.DuplicateIds
+	}
+	return nil
+}
+
+func (x *DuplicateUserGroup) GetSimilarityReason() string {
+	if x != nil {
+		return x.SimilarityReason
+	}
+	return ""
+}
+
+type MergeUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CanonicalId   string                 `protobuf:"bytes,1,opt,name=canonical_id,json=canonicalId,proto3" json:"canonical_id,omitempty"`
+	DuplicateId   string                 `protobuf:"bytes,2,opt,name=duplicate_id,json=duplicateId,proto3" json:"duplicate_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeUsersRequest) Reset() {
+	*x = MergeUsersRequest{}
+	mi := &file_proto_users_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeUsersRequest) ProtoMessage() {}
+
+func (x *MergeUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeUsersRequest.ProtoReflect.Descriptor instead.
+func (*MergeUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *MergeUsersRequest) GetCanonicalId() string {
+	if x != nil {
+		return x.CanonicalId
+	}
+	return ""
+}
+
+func (x *MergeUsersRequest) GetDuplicateId() string {
+	if x != nil {
+		return x.DuplicateId
+	}
+	return ""
+}
+
+type MergeUsersResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	User                *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	SocialAccountsMoved int32                  `protobuf:"varint,2,opt,name=social_accounts_moved,json=socialAccountsMoved,proto3" json:"social_accounts_moved,omitempty"`
+	PreferencesMoved    int32                  `protobuf:"varint,3,opt,name=preferences_moved,json=preferencesMoved,proto3" json:"preferences_moved,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *MergeUsersResponse) Reset() {
+	*x = MergeUsersResponse{}
+	mi := &file_proto_users_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeUsersResponse) ProtoMessage() {}
+
+func (x *MergeUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeUsersResponse.ProtoReflect.Descriptor instead.
+func (*MergeUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MergeUsersResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *MergeUsersResponse) GetSocialAccountsMoved() int32 {
+	if x != nil {
+		return x.SocialAccountsMoved
+	}
+	return 0
+}
+
+func (x *MergeUsersResponse) GetPreferencesMoved() int32 {
+	if x != nil {
+		return x.PreferencesMoved
+	}
+	return 0
+}
+
+type Cohort struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cohort) Reset() {
+	*x = Cohort{}
+	mi := &file_proto_users_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cohort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cohort) ProtoMessage() {}
+
+func (x *Cohort) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cohort.ProtoReflect.Descriptor instead.
+func (*Cohort) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Cohort) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Cohort) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Cohort) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Cohort) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type CreateCohortRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCohortRequest) Reset() {
+	*x = CreateCohortRequest{}
+	mi := &file_proto_users_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCohortRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCohortRequest) ProtoMessage() {}
+
+func (x *CreateCohortRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCohortRequest.ProtoReflect.Descriptor instead.
+func (*CreateCohortRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CreateCohortRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCohortRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CreateCohortRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type CohortResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cohort        *Cohort                `protobuf:"bytes,1,opt,name=cohort,proto3" json:"cohort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CohortResponse) Reset() {
+	*x = CohortResponse{}
+	mi := &file_proto_users_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CohortResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CohortResponse) ProtoMessage() {}
+
+func (x *CohortResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CohortResponse.ProtoReflect.Descriptor instead.
+func (*CohortResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CohortResponse) GetCohort() *Cohort {
+	if x != nil {
+		return x.Cohort
+	}
+	return nil
+}
+
+type GetUserCohortRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserCohortRequest) Reset() {
+	*x = GetUserCohortRequest{}
+	mi := &file_proto_users_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserCohortRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserCohortRequest) ProtoMessage() {}
+
+func (x *GetUserCohortRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserCohortRequest.ProtoReflect.Descriptor instead.
+func (*GetUserCohortRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetUserCohortRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetUserCohortResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CohortId      string                 `protobuf:"bytes,1,opt,name=cohort_id,json=cohortId,proto3" json:"cohort_id,omitempty"`
+	CohortName    string                 `protobuf:"bytes,2,opt,name=cohort_name,json=cohortName,proto3" json:"cohort_name,omitempty"`
+	CohortStart   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=cohort_start,json=cohortStart,proto3" json:"cohort_start,omitempty"`
+	CohortEnd     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=cohort_end,json=cohortEnd,proto3" json:"cohort_end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserCohortResponse) Reset() {
+	*x = GetUserCohortResponse{}
+	mi := &file_proto_users_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserCohortResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserCohortResponse) ProtoMessage() {}
+
+func (x *GetUserCohortResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserCohortResponse.ProtoReflect.Descriptor instead.
+func (*GetUserCohortResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetUserCohortResponse) GetCohortId() string {
+	if x != nil {
+		return x.CohortId
+	}
+	return ""
+}
+
+func (x *GetUserCohortResponse) GetCohortName() string {
+	if x != nil {
+		return x.CohortName
+	}
+	return ""
+}
+
+func (x *GetUserCohortResponse) GetCohortStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CohortStart
+	}
+	return nil
+}
+
+func (x *GetUserCohortResponse) GetCohortEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CohortEnd
+	}
+	return nil
+}
+
+type ListCohortMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CohortId      string                 `protobuf:"bytes,1,opt,name=cohort_id,json=cohortId,proto3" json:"cohort_id,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCohortMembersRequest) Reset() {
+	*x = ListCohortMembersRequest{}
+	mi := &file_proto_users_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCohortMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCohortMembersRequest) ProtoMessage() {}
+
+func (x *ListCohortMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCohortMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListCohortMembersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListCohortMembersRequest) GetCohortId() string {
+	if x != nil {
+		return x.CohortId
+	}
+	return ""
+}
+
+func (x *ListCohortMembersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCohortMembersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListCohortMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCohortMembersResponse) Reset() {
+	*x = ListCohortMembersResponse{}
+	mi := &file_proto_users_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCohortMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCohortMembersResponse) ProtoMessage() {}
+
+func (x *ListCohortMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCohortMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListCohortMembersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListCohortMembersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListCohortMembersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetCohortStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CohortId      string                 `protobuf:"bytes,1,opt,name=cohort_id,json=cohortId,proto3" json:"cohort_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCohortStatsRequest) Reset() {
+	*x = GetCohortStatsRequest{}
+	mi := &file_proto_users_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCohortStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCohortStatsRequest) ProtoMessage() {}
+
+func (x *GetCohortStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCohortStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCohortStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetCohortStatsRequest) GetCohortId() string {
+	if x != nil {
+		return x.CohortId
+	}
+	return ""
+}
+
+type GetCohortStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int64                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCohortStatsResponse) Reset() {
+	*x = GetCohortStatsResponse{}
+	mi := &file_proto_users_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCohortStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCohortStatsResponse) ProtoMessage() {}
+
+func (x *GetCohortStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCohortStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCohortStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetCohortStatsResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type UserAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Line1         string                 `protobuf:"bytes,3,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,4,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	StateCode     string                 `protobuf:"bytes,6,opt,name=state_code,json=stateCode,proto3" json:"state_code,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,7,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,8,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Latitude      *float64               `protobuf:"fixed64,9,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude     *float64               `protobuf:"fixed64,10,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,11,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserAddress) Reset() {
+	*x = UserAddress{}
+	mi := &file_proto_users_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserAddress) ProtoMessage() {}
+
+func (x *UserAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserAddress.ProtoReflect.Descriptor instead.
+func (*UserAddress) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UserAddress) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserAddress) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserAddress) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *UserAddress) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *UserAddress) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *UserAddress) GetStateCode() string {
+	if x != nil {
+		return x.StateCode
+	}
+	return ""
+}
+
+func (x *UserAddress) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *UserAddress) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *UserAddress) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *UserAddress) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+func (x *UserAddress) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+type AddUserAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	StateCode     string                 `protobuf:"bytes,5,opt,name=state_code,json=stateCode,proto3" json:"state_code,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,6,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,7,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,8,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddUserAddressRequest) Reset() {
+	*x = AddUserAddressRequest{}
+	mi := &file_proto_users_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddUserAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddUserAddressRequest) ProtoMessage() {}
+
+func (x *AddUserAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddUserAddressRequest.ProtoReflect.Descriptor instead.
+func (*AddUserAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AddUserAddressRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetStateCode() string {
+	if x != nil {
+		return x.StateCode
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *AddUserAddressRequest) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+type UserAddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *UserAddress           `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserAddressResponse) Reset() {
+	*x = UserAddressResponse{}
+	mi := &file_proto_users_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserAddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserAddressResponse) ProtoMessage() {}
+
+func (x *UserAddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserAddressResponse.ProtoReflect.Descriptor instead.
+func (*UserAddressResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UserAddressResponse) GetAddress() *UserAddress {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+type GetUserAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserAddressesRequest) Reset() {
+	*x = GetUserAddressesRequest{}
+	mi := &file_proto_users_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserAddressesRequest) ProtoMessage() {}
+
+func (x *GetUserAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserAddressesRequest.ProtoReflect.Descriptor instead.
+func (*GetUserAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetUserAddressesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetUserAddressesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []*UserAddress         `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserAddressesResponse) Reset() {
+	*x = GetUserAddressesResponse{}
+	mi := &file_proto_users_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserAddressesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserAddressesResponse) ProtoMessage() {}
+
+func (x *GetUserAddressesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserAddressesResponse.ProtoReflect.Descriptor instead.
+func (*GetUserAddressesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetUserAddressesResponse) GetAddresses() []*UserAddress {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type UpdateUserAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AddressId     string                 `protobuf:"bytes,2,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	Line1         string                 `protobuf:"bytes,3,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,4,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	StateCode     string                 `protobuf:"bytes,6,opt,name=state_code,json=stateCode,proto3" json:"state_code,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,7,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,8,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserAddressRequest) Reset() {
+	*x = UpdateUserAddressRequest{}
+	mi := &file_proto_users_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserAddressRequest) ProtoMessage() {}
+
+func (x *UpdateUserAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserAddressRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UpdateUserAddressRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetStateCode() string {
+	if x != nil {
+		return x.StateCode
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *UpdateUserAddressRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+type DeleteUserAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AddressId     string                 `protobuf:"bytes,2,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserAddressRequest) Reset() {
+	*x = DeleteUserAddressRequest{}
+	mi := &file_proto_users_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserAddressRequest) ProtoMessage() {}
+
+func (x *DeleteUserAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserAddressRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteUserAddressRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteUserAddressRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+type DeleteUserAddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserAddressResponse) Reset() {
+	*x = DeleteUserAddressResponse{}
+	mi := &file_proto_users_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserAddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserAddressResponse) ProtoMessage() {}
+
+func (x *DeleteUserAddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserAddressResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserAddressResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{37}
+}
+
+type SetDefaultAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AddressId     string                 `protobuf:"bytes,2,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultAddressRequest) Reset() {
+	*x = SetDefaultAddressRequest{}
+	mi := &file_proto_users_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultAddressRequest) ProtoMessage() {}
+
+func (x *SetDefaultAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultAddressRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SetDefaultAddressRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetDefaultAddressRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+type EnrollTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
+	mi := &file_proto_users_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPRequest) ProtoMessage() {}
+
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *EnrollTOTPRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type EnrollTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secret        string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	QrCodeUri     string                 `protobuf:"bytes,2,opt,name=qr_code_uri,json=qrCodeUri,proto3" json:"qr_code_uri,omitempty"`
+	BackupCodes   []string               `protobuf:"bytes,3,rep,name=backup_codes,json=backupCodes,proto3" json:"backup_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPResponse) Reset() {
+	*x = EnrollTOTPResponse{}
+	mi := &file_proto_users_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPResponse) ProtoMessage() {}
+
+func (x *EnrollTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *EnrollTOTPResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTOTPResponse) GetQrCodeUri() string {
+	if x != nil {
+		return x.QrCodeUri
+	}
+	return ""
+}
+
+func (x *EnrollTOTPResponse) GetBackupCodes() []string {
+	if x != nil {
+		return x.BackupCodes
+	}
+	return nil
+}
+
+type VerifyTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTOTPRequest) Reset() {
+	*x = VerifyTOTPRequest{}
+	mi := &file_proto_users_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTOTPRequest) ProtoMessage() {}
+
+func (x *VerifyTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTOTPRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *VerifyTOTPRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VerifyTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTOTPResponse) Reset() {
+	*x = VerifyTOTPResponse{}
+	mi := &file_proto_users_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTOTPResponse) ProtoMessage() {}
+
+func (x *VerifyTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTOTPResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *VerifyTOTPResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+type GetUserTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserTimelineRequest) Reset() {
+	*x = GetUserTimelineRequest{}
+	mi := &file_proto_users_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserTimelineRequest) ProtoMessage() {}
+
+func (x *GetUserTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetUserTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetUserTimelineRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserTimelineRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetUserTimelineRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetUserTimelineRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type TimelineEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	EventType     string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Summary       string                 `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	Details       map[string]string      `protobuf:"bytes,4,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SourceService string                 `protobuf:"bytes,5,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEvent) Reset() {
+	*x = TimelineEvent{}
+	mi := &file_proto_users_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEvent) ProtoMessage() {}
+
+func (x *TimelineEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEvent.ProtoReflect.Descriptor instead.
+func (*TimelineEvent) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *TimelineEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TimelineEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *TimelineEvent) GetSourceService() string {
+	if x != nil {
+		return x.SourceService
+	}
+	return ""
+}
+
+type UserConsent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ConsentType   string                 `protobuf:"bytes,3,opt,name=consent_type,json=consentType,proto3" json:"consent_type,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	ConsentedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=consented_at,json=consentedAt,proto3" json:"consented_at,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	WithdrawnAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=withdrawn_at,json=withdrawnAt,proto3" json:"withdrawn_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserConsent) Reset() {
+	*x = UserConsent{}
+	mi := &file_proto_users_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserConsent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserConsent) ProtoMessage() {}
+
+func (x *UserConsent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserConsent.ProtoReflect.Descriptor instead.
+func (*UserConsent) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UserConsent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserConsent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserConsent) GetConsentType() string {
+	if x != nil {
+		return x.ConsentType
+	}
+	return ""
+}
+
+func (x *UserConsent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *UserConsent) GetConsentedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConsentedAt
+	}
+	return nil
+}
+
+func (x *UserConsent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *UserConsent) GetWithdrawnAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WithdrawnAt
+	}
+	return nil
+}
+
+type RecordConsentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ConsentType   string                 `protobuf:"bytes,2,opt,name=consent_type,json=consentType,proto3" json:"consent_type,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordConsentRequest) Reset() {
+	*x = RecordConsentRequest{}
+	mi := &file_proto_users_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordConsentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordConsentRequest) ProtoMessage() {}
+
+func (x *RecordConsentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordConsentRequest.ProtoReflect.Descriptor instead.
+func (*RecordConsentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RecordConsentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RecordConsentRequest) GetConsentType() string {
+	if x != nil {
+		return x.ConsentType
+	}
+	return ""
+}
+
+func (x *RecordConsentRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RecordConsentRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+type RecordConsentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsentId     string                 `protobuf:"bytes,1,opt,name=consent_id,json=consentId,proto3" json:"consent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordConsentResponse) Reset() {
+	*x = RecordConsentResponse{}
+	mi := &file_proto_users_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordConsentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordConsentResponse) ProtoMessage() {}
+
+func (x *RecordConsentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordConsentResponse.ProtoReflect.Descriptor instead.
+func (*RecordConsentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RecordConsentResponse) GetConsentId() string {
+	if x != nil {
+		return x.ConsentId
+	}
+	return ""
+}
+
+type WithdrawConsentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsentId     string                 `protobuf:"bytes,1,opt,name=consent_id,json=consentId,proto3" json:"consent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawConsentRequest) Reset() {
+	*x = WithdrawConsentRequest{}
+	mi := &file_proto_users_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawConsentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawConsentRequest) ProtoMessage() {}
+
+func (x *WithdrawConsentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawConsentRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawConsentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WithdrawConsentRequest) GetConsentId() string {
+	if x != nil {
+		return x.ConsentId
+	}
+	return ""
+}
+
+type WithdrawConsentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawConsentResponse) Reset() {
+	*x = WithdrawConsentResponse{}
+	mi := &file_proto_users_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawConsentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawConsentResponse) ProtoMessage() {}
+
+func (x *WithdrawConsentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawConsentResponse.ProtoReflect.Descriptor instead.
+func (*WithdrawConsentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{49}
+}
+
+type GetConsentHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConsentHistoryRequest) Reset() {
+	*x = GetConsentHistoryRequest{}
+	mi := &file_proto_users_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConsentHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsentHistoryRequest) ProtoMessage() {}
+
+func (x *GetConsentHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsentHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetConsentHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetConsentHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetConsentHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consents      []*UserConsent         `protobuf:"bytes,1,rep,name=consents,proto3" json:"consents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConsentHistoryResponse) Reset() {
+	*x = GetConsentHistoryResponse{}
+	mi := &file_proto_users_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConsentHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsentHistoryResponse) ProtoMessage() {}
+
+func (x *GetConsentHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsentHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetConsentHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetConsentHistoryResponse) GetConsents() []*UserConsent {
+	if x != nil {
+		return x.Consents
+	}
+	return nil
+}
+
+type CommunicationPreference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Channel       string                 `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Subscribed    bool                   `protobuf:"varint,4,opt,name=subscribed,proto3" json:"subscribed,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommunicationPreference) Reset() {
+	*x = CommunicationPreference{}
+	mi := &file_proto_users_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommunicationPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommunicationPreference) ProtoMessage() {}
+
+func (x *CommunicationPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommunicationPreference.ProtoReflect.Descriptor instead.
+func (*CommunicationPreference) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CommunicationPreference) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CommunicationPreference) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *CommunicationPreference) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CommunicationPreference) GetSubscribed() bool {
+	if x != nil {
+		return x.Subscribed
+	}
+	return false
+}
+
+func (x *CommunicationPreference) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type UpdateCommunicationPreferenceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Channel       string                 `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Subscribed    bool                   `protobuf:"varint,4,opt,name=subscribed,proto3" json:"subscribed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommunicationPreferenceRequest) Reset() {
+	*x = UpdateCommunicationPreferenceRequest{}
+	mi := &file_proto_users_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommunicationPreferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommunicationPreferenceRequest) ProtoMessage() {}
+
+func (x *UpdateCommunicationPreferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommunicationPreferenceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCommunicationPreferenceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *UpdateCommunicationPreferenceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateCommunicationPreferenceRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *UpdateCommunicationPreferenceRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UpdateCommunicationPreferenceRequest) GetSubscribed() bool {
+	if x != nil {
+		return x.Subscribed
+	}
+	return false
+}
+
+type UpdateCommunicationPreferenceResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Preference    *CommunicationPreference `protobuf:"bytes,1,opt,name=preference,proto3" json:"preference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCommunicationPreferenceResponse) Reset() {
+	*x = UpdateCommunicationPreferenceResponse{}
+	mi := &file_proto_users_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCommunicationPreferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommunicationPreferenceResponse) ProtoMessage() {}
+
+func (x *UpdateCommunicationPreferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommunicationPreferenceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCommunicationPreferenceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *UpdateCommunicationPreferenceResponse) GetPreference() *CommunicationPreference {
+	if x != nil {
+		return x.Preference
+	}
+	return nil
+}
+
+type GenerateUnsubscribeTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Channel       string                 `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateUnsubscribeTokenRequest) Reset() {
+	*x = GenerateUnsubscribeTokenRequest{}
+	mi := &file_proto_users_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateUnsubscribeTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateUnsubscribeTokenRequest) ProtoMessage() {}
+
+func (x *GenerateUnsubscribeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateUnsubscribeTokenRequest.ProtoReflect.Descriptor instead.
+func (*GenerateUnsubscribeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GenerateUnsubscribeTokenRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GenerateUnsubscribeTokenRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *GenerateUnsubscribeTokenRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type GenerateUnsubscribeTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateUnsubscribeTokenResponse) Reset() {
+	*x = GenerateUnsubscribeTokenResponse{}
+	mi := &file_proto_users_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateUnsubscribeTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateUnsubscribeTokenResponse) ProtoMessage() {}
+
+func (x *GenerateUnsubscribeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateUnsubscribeTokenResponse.ProtoReflect.Descriptor instead.
+func (*GenerateUnsubscribeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GenerateUnsubscribeTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type UnsubscribeByTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnsubscribeByTokenRequest) Reset() {
+	*x = UnsubscribeByTokenRequest{}
+	mi := &file_proto_users_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeByTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeByTokenRequest) ProtoMessage() {}
+
+func (x *UnsubscribeByTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeByTokenRequest.ProtoReflect.Descriptor instead.
+func (*UnsubscribeByTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *UnsubscribeByTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type UnsubscribeByTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnsubscribeByTokenResponse) Reset() {
+	*x = UnsubscribeByTokenResponse{}
+	mi := &file_proto_users_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeByTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeByTokenResponse) ProtoMessage() {}
+
+func (x *UnsubscribeByTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_users_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeByTokenResponse.ProtoReflect.Descriptor instead.
+func (*UnsubscribeByTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_users_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UnsubscribeByTokenResponse) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *UnsubscribeByTokenResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+var File_proto_users_proto protoreflect.FileDescriptor
+
+const file_proto_users_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/users.proto\x12\x05users\x1a\x1fgoogle/protobuf/timestamp.proto\"@\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"=\n" +
+	"\x11CreateUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\fUserResponse\x12\x1f\n" +
+	"\x04user\x18\x01 \x01(\v2\v.users.UserR\x04user\"(\n" +
+	"\x14BatchGetUsersRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"[\n" +
+	"\x15BatchGetUsersResponse\x12!\n" +
+	"\x05users\x18\x01 \x03(\v2\v.users.UserR\x05users\x12\x1f\n" +
+	"\vmissing_ids\x18\x02 \x03(\tR\n" +
+	"missingIds\"W\n" +
+	"\x14SetPreferenceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"\x17\n" +
+	"\x15SetPreferenceResponse\"D\n" +
+	"\x15GetPreferencesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\"\xaa\x01\n" +
+	"\x16GetPreferencesResponse\x12P\n" +
+	"\vpreferences\x18\x01 \x03(\v2..users.GetPreferencesResponse.PreferencesEntryR\vpreferences\x1a>\n" +
+	"\x10PreferencesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8a\x01\n" +
+	"\x10ListUsersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1f\n" +
+	"\vname_prefix\x18\x03 \x01(\tR\n" +
+	"namePrefix\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\"^\n" +
+	"\x11ListUsersResponse\x12!\n" +
+	"\x05users\x18\x01 \x03(\v2\v.users.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa1\x01\n" +
+	"\x18LinkSocialAccountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12-\n" +
+	"\x12authorization_code\x18\x03 \x01(\tR\x11authorizationCode\x12!\n" +
+	"\fredirect_uri\x18\x04 \x01(\tR\vredirectUri\"3\n" +
+	"\x19LinkSocialAccountResponse\x12\x16\n" +
+	"\x06linked\x18\x01 \x01(\bR\x06linked\"Q\n" +
+	"\x1aUnlinkSocialAccountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\"9\n" +
+	"\x1bUnlinkSocialAccountResponse\x12\x1a\n" +
+	"\bunlinked\x18\x01 \x01(\bR\bunlinked\"f\n" +
+	"\x1eFindUserBySocialAccountRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12(\n" +
+	"\x10provider_user_id\x18\x02 \x01(\tR\x0eproviderUserId\"Q\n" +
+	"\x19FindDuplicateUsersRequest\x124\n" +
+	"\bstrategy\x18\x01 \x01(\x0e2\x18.users.DuplicateStrategyR\bstrategy\"\x92\x01\n" +
+	"\x12DuplicateUserGroup\x12*\n" +
+	"\x11canonical_user_id\x18\x01 \x01(\tR\x0fcanonicalUserId\x12#\n" +
+	"\rduplicate_ids\x18\x02 \x03(\tR\fduplicateIds\x12+\n" +
+	"\x11similarity_reason\x18\x03 \x01(\tR\x10similarityReason\"Y\n" +
+	"\x11MergeUsersRequest\x12!\n" +
+	"\fcanonical_id\x18\x01 \x01(\tR\vcanonicalId\x12!\n" +
+	"\fduplicate_id\x18\x02 \x01(\tR\vduplicateId\"\x96\x01\n" +
+	"\x12MergeUsersResponse\x12\x1f\n" +
+	"\x04user\x18\x01 \x01(\v2\v.users.UserR\x04user\x122\n" +
+	"\x15social_accounts_moved\x18\x02 \x01(\x05R\x13socialAccountsMoved\x12+\n" +
+	"\x11preferences_moved\x18\x03 \x01(\x05R\x10preferencesMoved\"\x9e\x01\n" +
+	"\x06Cohort\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"\x9b\x01\n" +
+	"\x13CreateCohortRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"7\n" +
+	"\x0eCohortResponse\x12%\n" +
+	"\x06cohort\x18\x01 \x01(\v2\r.users.CohortR\x06cohort\"/\n" +
+	"\x14GetUserCohortRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xcf\x01\n" +
+	"\x15GetUserCohortResponse\x12\x1b\n" +
+	"\tcohort_id\x18\x01 \x01(\tR\bcohortId\x12\x1f\n" +
+	"\vcohort_name\x18\x02 \x01(\tR\n" +
+	"cohortName\x12=\n" +
+	"\fcohort_start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcohortStart\x129\n" +
+	"\n" +
+	"cohort_end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcohortEnd\"s\n" +
+	"\x18ListCohortMembersRequest\x12\x1b\n" +
+	"\tcohort_id\x18\x01 \x01(\tR\bcohortId\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"f\n" +
+	"\x19ListCohortMembersResponse\x12!\n" +
+	"\x05users\x18\x01 \x03(\v2\v.users.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"4\n" +
+	"\x15GetCohortStatsRequest\x12\x1b\n" +
+	"\tcohort_id\x18\x01 \x01(\tR\bcohortId\",\n" +
+	"\x16GetCohortStatsResponse\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\"\xd7\x02\n" +
+	"\vUserAddress\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05line1\x18\x03 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x04 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x1d\n" +
+	"\n" +
+	"state_code\x18\x06 \x01(\tR\tstateCode\x12!\n" +
+	"\fcountry_code\x18\a \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vpostal_code\x18\b \x01(\tR\n" +
+	"postalCode\x12\x1f\n" +
+	"\blatitude\x18\t \x01(\x01H\x00R\blatitude\x88\x01\x01\x12!\n" +
+	"\tlongitude\x18\n" +
+	" \x01(\x01H\x01R\tlongitude\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\v \x01(\bR\tisDefaultB\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitude\"\xf2\x01\n" +
+	"\x15AddUserAddressRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x1d\n" +
+	"\n" +
+	"state_code\x18\x05 \x01(\tR\tstateCode\x12!\n" +
+	"\fcountry_code\x18\x06 \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vpostal_code\x18\a \x01(\tR\n" +
+	"postalCode\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\b \x01(\bR\tisDefault\"C\n" +
+	"\x13UserAddressResponse\x12,\n" +
+	"\aaddress\x18\x01 \x01(\v2\x12.users.UserAddressR\aaddress\"2\n" +
+	"\x17GetUserAddressesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"L\n" +
+	"\x18GetUserAddressesResponse\x120\n" +
+	"\taddresses\x18\x01 \x03(\v2\x12.users.UserAddressR\taddresses\"\xf5\x01\n" +
+	"\x18UpdateUserAddressRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x02 \x01(\tR\taddressId\x12\x14\n" +
+	"\x05line1\x18\x03 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x04 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x1d\n" +
+	"\n" +
+	"state_code\x18\x06 \x01(\tR\tstateCode\x12!\n" +
+	"\fcountry_code\x18\a \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vpostal_code\x18\b \x01(\tR\n" +
+	"postalCode\"R\n" +
+	"\x18DeleteUserAddressRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x02 \x01(\tR\taddressId\"\x1b\n" +
+	"\x19DeleteUserAddressResponse\"R\n" +
+	"\x18SetDefaultAddressRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x02 \x01(\tR\taddressId\",\n" +
+	"\x11EnrollTOTPRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"o\n" +
+	"\x12EnrollTOTPResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x1e\n" +
+	"\vqr_code_uri\x18\x02 \x01(\tR\tqrCodeUri\x12!\n" +
+	"\fbackup_codes\x18\x03 \x03(\tR\vbackupCodes\"@\n" +
+	"\x11VerifyTOTPRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"*\n" +
+	"\x12VerifyTOTPResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\"\xae\x01\n" +
+	"\x16GetUserTimelineRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1f\n" +
+	"\vevent_types\x18\x04 \x03(\tR\n" +
+	"eventTypes\"\xa2\x02\n" +
+	"\rTimelineEvent\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x18\n" +
+	"\asummary\x18\x03 \x01(\tR\asummary\x12;\n" +
+	"\adetails\x18\x04 \x03(\v2!.users.TimelineEvent.DetailsEntryR\adetails\x12%\n" +
+	"\x0esource_service\x18\x05 \x01(\tR\rsourceService\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x90\x02\n" +
+	"\vUserConsent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\fconsent_type\x18\x03 \x01(\tR\vconsentType\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12=\n" +
+	"\fconsented_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vconsentedAt\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x06 \x01(\tR\tipAddress\x12=\n" +
+	"\fwithdrawn_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vwithdrawnAt\"\x8b\x01\n" +
+	"\x14RecordConsentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fconsent_type\x18\x02 \x01(\tR\vconsentType\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tR\tipAddress\"6\n" +
+	"\x15RecordConsentResponse\x12\x1d\n" +
+	"\n" +
+	"consent_id\x18\x01 \x01(\tR\tconsentId\"7\n" +
+	"\x16WithdrawConsentRequest\x12\x1d\n" +
+	"\n" +
+	"consent_id\x18\x01 \x01(\tR\tconsentId\"\x19\n" +
+	"\x17WithdrawConsentResponse\"3\n" +
+	"\x18GetConsentHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"K\n" +
+	"\x19GetConsentHistoryResponse\x12.\n" +
+	"\bconsents\x18\x01 \x03(\v2\x12.users.UserConsentR\bconsents\"\xbb\x01\n" +
+	"\x17CommunicationPreference\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1e\n" +
+	"\n" +
+	"subscribed\x18\x04 \x01(\bR\n" +
+	"subscribed\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8d\x01\n" +
+	"$UpdateCommunicationPreferenceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1e\n" +
+	"\n" +
+	"subscribed\x18\x04 \x01(\bR\n" +
+	"subscribed\"g\n" +
+	"%UpdateCommunicationPreferenceResponse\x12>\n" +
+	"\n" +
+	"preference\x18\x01 \x01(\v2\x1e.users.CommunicationPreferenceR\n" +
+	"preference\"h\n" +
+	"\x1fGenerateUnsubscribeTokenRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"8\n" +
+	" GenerateUnsubscribeTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"1\n" +
+	"\x19UnsubscribeByTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"J\n" +
+	"\x1aUnsubscribeByTokenResponse\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type*\x87\x01\n" +
+	"\x11DuplicateStrategy\x12&\n" +
+	"\"DUPLICATE_STRATEGY_EMAIL_NORMALIZE\x10\x00\x12'\n" +
+	"#DUPLICATE_STRATEGY_EMAIL_PLUS_STRIP\x10\x01\x12!\n" +
+	"\x1dDUPLICATE_STRATEGY_FUZZY_NAME\x10\x022\xaa\x12\n" +
+	"\vUserService\x12;\n" +
+	"\n" +
+	"CreateUser\x12\x18.users.CreateUserRequest\x1a\x13.users.UserResponse\x125\n" +
+	"\aGetUser\x12\x15.users.GetUserRequest\x1a\x13.users.UserResponse\x12J\n" +
+	"\rBatchGetUsers\x12\x1b.users.BatchGetUsersRequest\x1a\x1c.users.BatchGetUsersResponse\x12J\n" +
+	"\rSetPreference\x12\x1b.users.SetPreferenceRequest\x1a\x1c.users.SetPreferenceResponse\x12M\n" +
+	"\x0eGetPreferences\x12\x1c.users.GetPreferencesRequest\x1a\x1d.users.GetPreferencesResponse\x12>\n" +
+	"\tListUsers\x12\x17.users.ListUsersRequest\x1a\x18.users.ListUsersResponse\x12V\n" +
+	"\x11LinkSocialAccount\x12\x1f.users.LinkSocialAccountRequest\x1a .users.LinkSocialAccountResponse\x12\\\n" +
+	"\x13UnlinkSocialAccount\x12!.users.UnlinkSocialAccountRequest\x1a\".users.UnlinkSocialAccountResponse\x12U\n" +
+	"\x17FindUserBySocialAccount\x12%.users.FindUserBySocialAccountRequest\x1a\x13.users.UserResponse\x12S\n" +
+	"\x12FindDuplicateUsers\x12 .users.FindDuplicateUsersRequest\x1a\x19.users.DuplicateUserGroup0\x01\x12A\n" +
+	"\n" +
+	"MergeUsers\x12\x18.users.MergeUsersRequest\x1a\x19.users.MergeUsersResponse\x12A\n" +
+	"\fCreateCohort\x12\x1a.users.CreateCohortRequest\x1a\x15.users.CohortResponse\x12J\n" +
+	"\rGetUserCohort\x12\x1b.users.GetUserCohortRequest\x1a\x1c.users.GetUserCohortResponse\x12V\n" +
+	"\x11ListCohortMembers\x12\x1f.users.ListCohortMembersRequest\x1a .users.ListCohortMembersResponse\x12M\n" +
+	"\x0eGetCohortStats\x12\x1c.users.GetCohortStatsRequest\x1a\x1d.users.GetCohortStatsResponse\x12J\n" +
+	"\x0eAddUserAddress\x12\x1c.users.AddUserAddressRequest\x1a\x1a.users.UserAddressResponse\x12S\n" +
+	"\x10GetUserAddresses\x12\x1e.users.GetUserAddressesRequest\x1a\x1f.users.GetUserAddressesResponse\x12P\n" +
+	"\x11UpdateUserAddress\x12\x1f.users.UpdateUserAddressRequest\x1a\x1a.users.UserAddressResponse\x12V\n" +
+	"\x11DeleteUserAddress\x12\x1f.users.DeleteUserAddressRequest\x1a .users.DeleteUserAddressResponse\x12P\n" +
+	"\x11SetDefaultAddress\x12\x1f.users.SetDefaultAddressRequest\x1a\x1a.users.UserAddressResponse\x12A\n" +
+	"\n" +
+	"EnrollTOTP\x12\x18.users.EnrollTOTPRequest\x1a\x19.users.EnrollTOTPResponse\x12A\n" +
+	"\n" +
+	"VerifyTOTP\x12\x18.users.VerifyTOTPRequest\x1a\x19.users.VerifyTOTPResponse\x12H\n" +
+	"\x0fGetUserTimeline\x12\x1d.users.GetUserTimelineRequest\x1a\x14.users.TimelineEvent0\x01\x12J\n" +
+	"\rRecordConsent\x12\x1b.users.RecordConsentRequest\x1a\x1c.users.RecordConsentResponse\x12P\n" +
+	"\x0fWithdrawConsent\x12\x1d.users.WithdrawConsentRequest\x1a\x1e.users.WithdrawConsentResponse\x12V\n" +
+	"\x11GetConsentHistory\x12\x1f.users.GetConsentHistoryRequest\x1a .users.GetConsentHistoryResponse\x12z\n" +
+	"\x1dUpdateCommunicationPreference\x12+.users.UpdateCommunicationPreferenceRequest\x1a,.users.UpdateCommunicationPreferenceResponse\x12k\n" +
+	"\x18GenerateUnsubscribeToken\x12&.users.GenerateUnsubscribeTokenRequest\x1a'.users.GenerateUnsubscribeTokenResponse\x12Y\n" +
+	"\x12UnsubscribeByToken\x12 .users.UnsubscribeByTokenRequest\x1a!.users.UnsubscribeByTokenResponseB\x11Z\x0f./proto/gen;genb\x06proto3"
 
 var (
 	file_proto_users_proto_rawDescOnce sync.Once
@@ -254,24 +3639,162 @@ func file_proto_users_proto_rawDescGZIP() []byte {
 	return file_proto_users_proto_rawDescData
 }
 
-var file_proto_users_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_users_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_users_proto_msgTypes = make([]protoimpl.MessageInfo, 61)
 var file_proto_users_proto_goTypes = []any{
-	(*User)(nil),              // 0: users.User
-	(*CreateUserRequest)(nil), // 1: users.CreateUserRequest
-	(*GetUserRequest)(nil),    // 2: users.GetUserRequest
-	(*UserResponse)(nil),      // 3: users.UserResponse
+	(DuplicateStrategy)(0),                        // 0: users.DuplicateStrategy
+	(*User)(nil),                                  // 1: users.User
+	(*CreateUserRequest)(nil),                     // 2: users.CreateUserRequest
+	(*GetUserRequest)(nil),                        // 3: users.GetUserRequest
+	(*UserResponse)(nil),                          // 4: users.UserResponse
+	(*BatchGetUsersRequest)(nil),                  // 5: users.BatchGetUsersRequest
+	(*BatchGetUsersResponse)(nil),                 // 6: users.BatchGetUsersResponse
+	(*SetPreferenceRequest)(nil),                  // 7: users.SetPreferenceRequest
+	(*SetPreferenceResponse)(nil),                 // 8: users.SetPreferenceResponse
+	(*GetPreferencesRequest)(nil),                 // 9: users.GetPreferencesRequest
+	(*GetPreferencesResponse)(nil),                // 10: users.GetPreferencesResponse
+	(*ListUsersRequest)(nil),                      // 11: users.ListUsersRequest
+	(*ListUsersResponse)(nil),                     // 12: users.ListUsersResponse
+	(*LinkSocialAccountRequest)(nil),              // 13: users.LinkSocialAccountRequest
+	(*LinkSocialAccountResponse)(nil),             // 14: users.LinkSocialAccountResponse
+	(*UnlinkSocialAccountRequest)(nil),            // 15: users.UnlinkSocialAccountRequest
+	(*UnlinkSocialAccountResponse)(nil),           // 16: users.UnlinkSocialAccountResponse
+	(*FindUserBySocialAccountRequest)(nil),        // 17: users.FindUserBySocialAccountRequest
+	(*FindDuplicateUsersRequest)(nil),             // 18: users.FindDuplicateUsersRequest
+	(*DuplicateUserGroup)(nil),                    // 19: users.DuplicateUserGroup
+	(*MergeUsersRequest)(nil),                     // 20: users.MergeUsersRequest
+	(*MergeUsersResponse)(nil),                    // 21: users.MergeUsersResponse
+	(*Cohort)(nil),                                // 22: users.Cohort
+	(*CreateCohortRequest)(nil),                   // 23: users.CreateCohortRequest
+	(*CohortResponse)(nil),                        // 24: users.CohortResponse
+	(*GetUserCohortRequest)(nil),                  // 25: users.GetUserCohortRequest
+	(*GetUserCohortResponse)(nil),                 // 26: users.GetUserCohortResponse
+	(*ListCohortMembersRequest)(nil),              // 27: users.ListCohortMembersRequest
+	(*ListCohortMembersResponse)(nil),             // 28: users.ListCohortMembersResponse
+	(*GetCohortStatsRequest)(nil),                 // 29: users.GetCohortStatsRequest
+	(*GetCohortStatsResponse)(nil),                // 30: users.GetCohortStatsResponse
+	(*UserAddress)(nil),                           // 31: users.UserAddress
+	(*AddUserAddressRequest)(nil),                 // 32: users.AddUserAddressRequest
+	(*UserAddressResponse)(nil),                   // 33: users.UserAddressResponse
+	(*GetUserAddressesRequest)(nil),               // 34: users.GetUserAddressesRequest
+	(*GetUserAddressesResponse)(nil),              // 35: users.GetUserAddressesResponse
+	(*UpdateUserAddressRequest)(nil),              // 36: users.UpdateUserAddressRequest
+	(*DeleteUserAddressRequest)(nil),              // 37: users.DeleteUserAddressRequest
+	(*DeleteUserAddressResponse)(nil),             // 38: users.DeleteUserAddressResponse
+	(*SetDefaultAddressRequest)(nil),              // 39: users.SetDefaultAddressRequest
+	(*EnrollTOTPRequest)(nil),                     // 40: users.EnrollTOTPRequest
+	(*EnrollTOTPResponse)(nil),                    // 41: users.EnrollTOTPResponse
+	(*VerifyTOTPRequest)(nil),                     // 42: users.VerifyTOTPRequest
+	(*VerifyTOTPResponse)(nil),                    // 43: users.VerifyTOTPResponse
+	(*GetUserTimelineRequest)(nil),                // 44: users.GetUserTimelineRequest
+	(*TimelineEvent)(nil),                         // 45: users.TimelineEvent
+	(*UserConsent)(nil),                           // 46: users.UserConsent
+	(*RecordConsentRequest)(nil),                  // 47: users.RecordConsentRequest
+	(*RecordConsentResponse)(nil),                 // 48: users.RecordConsentResponse
+	(*WithdrawConsentRequest)(nil),                // 49: users.WithdrawConsentRequest
+	(*WithdrawConsentResponse)(nil),               // 50: users.WithdrawConsentResponse
+	(*GetConsentHistoryRequest)(nil),              // 51: users.GetConsentHistoryRequest
+	(*GetConsentHistoryResponse)(nil),             // 52: users.GetConsentHistoryResponse
+	(*CommunicationPreference)(nil),               // 53: users.CommunicationPreference
+	(*UpdateCommunicationPreferenceRequest)(nil),  // 54: users.UpdateCommunicationPreferenceRequest
+	(*UpdateCommunicationPreferenceResponse)(nil), // 55: users.UpdateCommunicationPreferenceResponse
+	(*GenerateUnsubscribeTokenRequest)(nil),       // 56: users.GenerateUnsubscribeTokenRequest
+	(*GenerateUnsubscribeTokenResponse)(nil),      // 57: users.GenerateUnsubscribeTokenResponse
+	(*UnsubscribeByTokenRequest)(nil),             // 58: users.UnsubscribeByTokenRequest
+	(*UnsubscribeByTokenResponse)(nil),            // 59: users.UnsubscribeByTokenResponse
+	nil,                                           // 60: users.GetPreferencesResponse.PreferencesEntry
+	nil,                                           // 61: users.TimelineEvent.DetailsEntry
+	(*timestamppb.Timestamp)(nil),                 // 62: google.protobuf.Timestamp
 }
 var file_proto_users_proto_depIdxs = []int32{
-	0, // 0: users.UserResponse.user:type_name -> users.User
-	1, // 1: users.UserService.CreateUser:input_type -> users.CreateUserRequest
-	2, // 2: users.UserService.GetUser:input_type -> users.GetUserRequest
-	3, // 3: users.UserService.CreateUser:output_type -> users.UserResponse
-	3, // 4: users.UserService.GetUser:output_type -> users.UserResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1,  // 0: users.UserResponse.user:type_name -> users.User
+	1,  // 1: users.BatchGetUsersResponse.users:type_name -> users.User
+	60, // 2: users.GetPreferencesResponse.preferences:type_name -> users.GetPreferencesResponse.PreferencesEntry
+	1,  // 3: users.ListUsersResponse.users:type_name -> users.User
+	0,  // 4: users.FindDuplicateUsersRequest.strategy:type_name -> users.DuplicateStrategy
+	1,  // 5: users.MergeUsersResponse.user:type_name -> users.User
+	62, // 6: users.Cohort.start_date:type_name -> google.protobuf.Timestamp
+	62, // 7: users.Cohort.end_date:type_name -> google.protobuf.Timestamp
+	62, // 8: users.CreateCohortRequest.start_date:type_name -> google.protobuf.Timestamp
+	62, // 9: users.CreateCohortRequest.end_date:type_name -> google.protobuf.Timestamp
+	22, // 10: users.CohortResponse.cohort:type_name -> users.Cohort
+	62, // 11: users.GetUserCohortResponse.cohort_start:type_name -> google.protobuf.Timestamp
+	62, // 12: users.GetUserCohortResponse.cohort_end:type_name -> google.protobuf.Timestamp
+	1,  // 13: users.ListCohortMembersResponse.users:type_name -> users.User
+	31, // 14: users.UserAddressResponse.address:type_name -> users.UserAddress
+	31, // 15: users.GetUserAddressesResponse.addresses:type_name -> users.UserAddress
+	62, // 16: users.GetUserTimelineRequest.from:type_name -> google.protobuf.Timestamp
+	62, // 17: users.GetUserTimelineRequest.to:type_name -> google.protobuf.Timestamp
+	62, // 18: users.TimelineEvent.timestamp:type_name -> google.protobuf.Timestamp
+	61, // 19: users.TimelineEvent.details:type_name -> users.TimelineEvent.DetailsEntry
+	62, // 20: users.UserConsent.consented_at:type_name -> google.protobuf.Timestamp
+	62, // 21: users.UserConsent.withdrawn_at:type_name -> google.protobuf.Timestamp
+	46, // 22: users.GetConsentHistoryResponse.consents:type_name -> users.UserConsent
+	62, // 23: users.CommunicationPreference.updated_at:type_name -> google.protobuf.Timestamp
+	53, // 24: users.UpdateCommunicationPreferenceResponse.preference:type_name -> users.CommunicationPreference
+	2,  // 25: users.UserService.CreateUser:input_type -> users.CreateUserRequest
+	3,  // 26: users.UserService.GetUser:input_type -> users.GetUserRequest
+	5,  // 27: users.UserService.BatchGetUsers:input_type -> users.BatchGetUsersRequest
+	7,  // 28: users.UserService.SetPreference:input_type -> users.SetPreferenceRequest
+	9,  // 29: users.UserService.GetPreferences:input_type -> users.GetPreferencesRequest
+	11, // 30: users.UserService.ListUsers:input_type -> users.ListUsersRequest
+	13, // 31: users.UserService.LinkSocialAccount:input_type -> users.LinkSocialAccountRequest
+	15, // 32: users.UserService.UnlinkSocialAccount:input_type -> users.UnlinkSocialAccountRequest
+	17, // 33: users.UserService.FindUserBySocialAccount:input_type -> users.FindUserBySocialAccountRequest
+	18, // 34: users.UserService.FindDuplicateUsers:input_type -> users.FindDuplicateUsersRequest
+	20, // 35: users.UserService.MergeUsers:input_type -> users.MergeUsersRequest
+	23, // 36: users.UserService.CreateCohort:input_type -> users.CreateCohortRequest
+	25, // 37: users.UserService.GetUserCohort:input_type -> users.GetUserCohortRequest
+	27, // 38: users.UserService.ListCohortMembers:input_type -> users.ListCohortMembersRequest
+	29, // 39: users.UserService.GetCohortStats:input_type -> users.GetCohortStatsRequest
+	32, // 40: users.UserService.AddUserAddress:input_type -> users.AddUserAddressRequest
+	34, // 41: users.UserService.GetUserAddresses:input_type -> users.GetUserAddressesRequest
+	36, // 42: users.UserService.UpdateUserAddress:input_type -> users.UpdateUserAddressRequest
+	37, // 43: users.UserService.DeleteUserAddress:input_type -> users.DeleteUserAddressRequest
+	39, // 44: users.UserService.SetDefaultAddress:input_type -> users.SetDefaultAddressRequest
+	40, // 45: users.UserService.EnrollTOTP:input_type -> users.EnrollTOTPRequest
+	42, // 46: users.UserService.VerifyTOTP:input_type -> users.VerifyTOTPRequest
+	44, // 47: users.UserService.GetUserTimeline:input_type -> users.GetUserTimelineRequest
+	47, // 48: users.UserService.RecordConsent:input_type -> users.RecordConsentRequest
+	49, // 49: users.UserService.WithdrawConsent:input_type -> users.WithdrawConsentRequest
+	51, // 50: users.UserService.GetConsentHistory:input_type -> users.GetConsentHistoryRequest
+	54, // 51: users.UserService.UpdateCommunicationPreference:input_type -> users.UpdateCommunicationPreferenceRequest
+	56, // 52: users.UserService.GenerateUnsubscribeToken:input_type -> users.GenerateUnsubscribeTokenRequest
+	58, // 53: users.UserService.UnsubscribeByToken:input_type -> users.UnsubscribeByTokenRequest
+	4,  // 54: users.UserService.CreateUser:output_type -> users.UserResponse
+	4,  // 55: users.UserService.GetUser:output_type -> users.UserResponse
+	6,  // 56: users.UserService.BatchGetUsers:output_type -> users.BatchGetUsersResponse
+	8,  // 57: users.UserService.SetPreference:output_type -> users.SetPreferenceResponse
+	10, // 58: users.UserService.GetPreferences:output_type -> users.GetPreferencesResponse
+	12, // 59: users.UserService.ListUsers:output_type -> users.ListUsersResponse
+	14, // 60: users.UserService.LinkSocialAccount:output_type -> users.LinkSocialAccountResponse
+	16, // 61: users.UserService.UnlinkSocialAccount:output_type -> users.UnlinkSocialAccountResponse
+	4,  // 62: users.UserService.FindUserBySocialAccount:output_type -> users.UserResponse
+	19, // 63: users.UserService.FindDuplicateUsers:output_type -> users.DuplicateUserGroup
+	21, // 64: users.UserService.MergeUsers:output_type -> users.MergeUsersResponse
+	24, // 65: users.UserService.CreateCohort:output_type -> users.CohortResponse
+	26, // 66: users.UserService.GetUserCohort:output_type -> users.GetUserCohortResponse
+	28, // 67: users.UserService.ListCohortMembers:output_type -> users.ListCohortMembersResponse
+	30, // 68: users.UserService.GetCohortStats:output_type -> users.GetCohortStatsResponse
+	33, // 69: users.UserService.AddUserAddress:output_type -> users.UserAddressResponse
+	35, // 70: users.UserService.GetUserAddresses:output_type -> users.GetUserAddressesResponse
+	33, // 71: users.UserService.UpdateUserAddress:output_type -> users.UserAddressResponse
+	38, // 72: users.UserService.DeleteUserAddress:output_type -> users.DeleteUserAddressResponse
+	33, // 73: users.UserService.SetDefaultAddress:output_type -> users.UserAddressResponse
+	41, // 74: users.UserService.EnrollTOTP:output_type -> users.EnrollTOTPResponse
+	43, // 75: users.UserService.VerifyTOTP:output_type -> users.VerifyTOTPResponse
+	45, // 76: users.UserService.GetUserTimeline:output_type -> users.TimelineEvent
+	48, // 77: users.UserService.RecordConsent:output_type -> users.RecordConsentResponse
+	50, // 78: users.UserService.WithdrawConsent:output_type -> users.WithdrawConsentResponse
+	52, // 79: users.UserService.GetConsentHistory:output_type -> users.GetConsentHistoryResponse
+	55, // 80: users.UserService.UpdateCommunicationPreference:output_type -> users.UpdateCommunicationPreferenceResponse
+	57, // 81: users.UserService.GenerateUnsubscribeToken:output_type -> users.GenerateUnsubscribeTokenResponse
+	59, // 82: users.UserService.UnsubscribeByToken:output_type -> users.UnsubscribeByTokenResponse
+	54, // [54:83] is the sub-list for method output_type
+	25, // [25:54] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_users_proto_init() }
@@ -279,18 +3802,20 @@ func file_proto_users_proto_init() {
 	if File_proto_users_proto != nil {
 		return
 	}
+	file_proto_users_proto_msgTypes[30].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_users_proto_rawDesc), len(file_proto_users_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   61,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_users_proto_goTypes,
 		DependencyIndexes: file_proto_users_proto_depIdxs,
+		EnumInfos:         file_proto_users_proto_enumTypes,
 		MessageInfos:      file_proto_users_proto_msgTypes,
 	}.Build()
 	File_proto_users_proto = out.File
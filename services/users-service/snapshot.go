@@ -0,0 +1,93 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "log"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    "shared/snapshot"
+    pb "users-service/proto/gen/proto"
+)
+
+// snapshotTables are the tables SnapshotData dumps and RestoreData replaces.
+// Bookkeeping tables (self-test probes, backfill progress) are left alone.
+var snapshotTables = []string{"users", "user_preferences", "social_accounts", "cohorts", "user_addresses", "user_backup_codes", "user_consents", "user_communication_preferences"}
+
+// snapshotChunkSize is the size of the chunks a snapshot is streamed in.
+const snapshotChunkSize = 64 << 10
+
+// snapshotServer dumps and restores the service's data, for resetting demo
+// environments between presentations.
+type snapshotServer struct {
+    pb.UnimplementedSnapshotServiceServer
+    db *gorm.DB
+    // allowRestore is set by ALLOW_RESTORE, so a production deployment
+    // cannot be wiped by a stray call.
+    allowRestore bool
+}
+
+func (s *snapshotServer) SnapshotData(req *pb.SnapshotDataRequest, stream pb.SnapshotService_SnapshotDataServer) error {
+    w := bufio.NewWriterSize(chunkWriter{stream: stream}, snapshotChunkSize)
+    counts, err := snapshot.Write(stream.Context(), s.db, w, serviceName, snapshotTables)
+    if err != nil {
+        return err
+    }
+    if err := w.Flush(); err != nil {
+        return err
+    }
+    log.Printf("Sent a snapshot of %s: %v", serviceName, counts)
+    return nil
+}
+
+// RestoreData replaces the service's data with a snapshot. It is all or
+// nothing: on any error the existing data is kept.
+func (s *snapshotServer) RestoreData(stream pb.SnapshotService_RestoreDataServer) error {
+    if !s.allowRestore {
+        return status.Error(codes.FailedPrecondition, "restore is disabled; set ALLOW_RESTORE=true to enable it")
+    }
+    counts, err := snapshot.Restore(stream.Context(), s.db, &chunkReader{recv: stream.Recv}, serviceName, snapshotTables)
+    if errors.Is(err, snapshot.ErrMismatch) {
+        return status.Error(codes.InvalidArgument, err.Error())
+    }
+    if err != nil {
+        return err
+    }
+    log.Printf("Restored %s from a snapshot: %v", serviceName, counts)
+    return stream.SendAndClose(&pb.RestoreDataResponse{Rows: counts})
+}
+
+// chunkWriter sends each write as one SnapshotChunk.
+type chunkWriter struct {
+    stream pb.SnapshotService_SnapshotDataServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+    // Send marshals p before returning, so the caller may reuse it.
+    if err := w.stream.Send(&pb.SnapshotChunk{Data: p}); err != nil {
+        return 0, err
+    }
+    return len(p), nil
+}
+
+// chunkReader reads the data of a stream of SnapshotChunks.
+type chunkReader struct {
+    recv func() (*pb.SnapshotChunk, error)
+    buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+    for len(r.buf) == 0 {
+        chunk, err := r.recv()
+        if err != nil {
+            return 0, err
+        }
+        r.buf = chunk.Data
+    }
+    n := copy(p, r.buf)
+    r.buf = r.buf[n:]
+    return n, nil
+}
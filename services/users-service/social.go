@@ -0,0 +1,263 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "log"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+
+    "golang.org/x/oauth2"
+    "golang.org/x/oauth2/endpoints"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// maxUserInfoSize caps the provider's user info response, in bytes.
+const maxUserInfoSize = 1 << 20
+
+// SocialAccount links a user to their account with an OAuth provider. A
+// provider account belongs to at most one user, and a user has at most one
+// account per provider.
+type SocialAccount struct {
+    ID             uint   `gorm:"primaryKey"`
+    UserID         uint   `gorm:"not null;uniqueIndex:idx_social_accounts_user_provider"`
+    Provider       string `gorm:"not null;uniqueIndex:idx_social_accounts_user_provider;uniqueIndex:idx_social_accounts_provider_account,priority:1"`
+    ProviderUserID string `gorm:"not null;uniqueIndex:idx_social_accounts_provider_account,priority:2"`
+    AccessToken    string `gorm:"not null"`
+    RefreshToken   string
+    // ExpiresAt is nil for access tokens that do not expire.
+    ExpiresAt *time.Time
+    CreatedAt time.Time
+    UpdatedAt time.Time
+}
+
+// socialProvider is an OAuth provider accounts can be linked from.
+type socialProvider struct {
+    config oauth2.Config
+    // userInfoURL returns the signed-in account as JSON, with its id in
+    // idField.
+    userInfoURL string
+    idField     string
+}
+
+// defaultSocialProviders are the supported providers, before their client
+// credentials are filled in.
+var defaultSocialProviders = map[string]socialProvider{
+    "google": {
+        config:      oauth2.Config{Endpoint: endpoints.Google, Scopes: []string{"openid"}},
+        userInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+        idField:     "sub",
+    },
+    "github": {
+        config:      oauth2.Config{Endpoint: endpoints.GitHub},
+        userInfoURL: "https://api.github.com/user",
+        idField:     "id",
+    },
+    "facebook": {
+        config:      oauth2.Config{Endpoint: endpoints.Facebook},
+        userInfoURL: "https://graph.facebook.com/me?fields=id",
+        idField:     "id",
+    },
+}
+
+// loadSocialProviders configures each provider from OAUTH_<PROVIDER>_CLIENT_ID
+// and OAUTH_<PROVIDER>_CLIENT_SECRET, leaving out those without a client ID.
+// OAUTH_<PROVIDER>_AUTH_URL, _TOKEN_URL and _USERINFO_URL override the
+// provider's endpoints, e.g. to point at a fake provider.
+func loadSocialProviders() map[string]*socialProvider {
+    providers := make(map[string]*socialProvider)
+    for name, provider := range defaultSocialProviders {
+        provider := provider
+        prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+        provider.config.ClientID = os.Getenv(prefix + "CLIENT_ID")
+        if provider.config.ClientID == "" {
+            continue
+        }
+        provider.config.ClientSecret = os.Getenv(prefix + "CLIENT_SECRET")
+        if url := os.Getenv(prefix + "AUTH_URL"); url != "" {
+            provider.config.Endpoint.AuthURL = url
+        }
+        if url := os.Getenv(prefix + "TOKEN_URL"); url != "" {
+            provider.config.Endpoint.TokenURL = url
+        }
+        if url := os.Getenv(prefix + "USERINFO_URL"); url != "" {
+            provider.userInfoURL = url
+        }
+        providers[name] = &provider
+        log.Printf("Social login enabled for %s", name)
+    }
+    return providers
+}
+
+func (s *server) socialProvider(name string) (*socialProvider, error) {
+    if _, ok := defaultSocialProviders[name]; !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported provider %q", name)
+    }
+    provider, ok := s.socialProviders[name]
+    if !ok {
+        return nil, status.Errorf(codes.FailedPrecondition, "provider %s is not configured", name)
+    }
+    return provider, nil
+}
+
+// exchange trades an authorization code for the provider's tokens and the
+// id of the account they belong to.
+func (p *socialProvider) exchange(ctx context.Context, code, redirectURI string) (*oauth2.Token, string, error) {
+    config := p.config
+    config.RedirectURL = redirectURI
+    token, err := config.Exchange(ctx, code)
+    if err != nil {
+        var retrieveErr *oauth2.RetrieveError
+        if errors.As(err, &retrieveErr) {
+            return nil, "", status.Errorf(codes.InvalidArgument, "provider rejected the authorization code: %v", retrieveErr)
+        }
+        return nil, "", status.Errorf(codes.Unavailable, "exchanging authorization code: %v", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.userInfoURL, nil)
+    if err != nil {
+        return nil, "", err
+    }
+    res, err := config.Client(ctx, token).Do(req)
+    if err != nil {
+        return nil, "", status.Errorf(codes.Unavailable, "fetching user info: %v", err)
+    }
+    defer res.Body.Close()
+    if res.StatusCode != http.StatusOK {
+        return nil, "", status.Errorf(codes.Unavailable, "fetching user info: %s", res.Status)
+    }
+    var info map[string]interface{}
+    decoder := json.NewDecoder(io.LimitReader(res.Body, maxUserInfoSize))
+    // GitHub ids are numbers; UseNumber keeps them exact.
+    decoder.UseNumber()
+    if err := decoder.Decode(&info); err != nil {
+        return nil, "", status.Errorf(codes.Unavailable, "decoding user info: %v", err)
+    }
+    var id string
+    switch v := info[p.idField].(type) {
+    case string:
+        id = v
+    case json.Number:
+        id = v.String()
+    }
+    if id == "" {
+        return nil, "", status.Errorf(codes.Unavailable, "user info has no %q field", p.idField)
+    }
+    return token, id, nil
+}
+
+// LinkSocialAccount exchanges an authorization code from the provider's
+// consent screen and links the account it was issued for to the user.
+// Linking the same account again refreshes its stored tokens.
+func (s *server) LinkSocialAccount(ctx context.Context, req *pb.LinkSocialAccountRequest) (*pb.LinkSocialAccountResponse, error) {
+    provider, err := s.socialProvider(req.Provider)
+    if err != nil {
+        return nil, err
+    }
+    if req.AuthorizationCode == "" {
+        return nil, status.Error(codes.InvalidArgument, "authorization_code is required")
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+
+    token, providerUserID, err := provider.exchange(ctx, req.AuthorizationCode, req.RedirectUri)
+    if err != nil {
+        return nil, err
+    }
+    account := SocialAccount{
+        UserID:         user.ID,
+        Provider:       req.Provider,
+        ProviderUserID: providerUserID,
+        AccessToken:    token.AccessToken,
+        RefreshToken:   token.RefreshToken,
+    }
+    if !token.Expiry.IsZero() {
+        account.ExpiresAt = &token.Expiry
+    }
+
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var existing SocialAccount
+        err := tx.Where("provider = ? AND (provider_user_id = ? OR user_id = ?)", req.Provider, providerUserID, user.ID).Take(&existing).Error
+        switch {
+        case errors.Is(err, gorm.ErrRecordNotFound):
+            err = tx.Create(&account).Error
+        case err != nil:
+            return err
+        case existing.UserID != user.ID:
+            return status.Errorf(codes.AlreadyExists, "this %s account is linked to another user", req.Provider)
+        case existing.ProviderUserID != providerUserID:
+            return status.Errorf(codes.AlreadyExists, "user %s already has a %s account linked; unlink it first", req.UserId, req.Provider)
+        default:
+            // Providers such as Google only issue a refresh token on first
+            // consent, so keep the old one if none came back.
+            if account.RefreshToken == "" {
+                account.RefreshToken = existing.RefreshToken
+            }
+            err = tx.Model(&existing).Select("AccessToken", "RefreshToken", "ExpiresAt").Updates(&account).Error
+        }
+        if err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "link_social_account", "user", user.ID, map[string]string{"provider": req.Provider, "provider_user_id": providerUserID})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.LinkSocialAccountResponse{Linked: true}, nil
+}
+
+// UnlinkSocialAccount removes the user's account with the provider. It
+// reports unlinked false if there was none.
+func (s *server) UnlinkSocialAccount(ctx context.Context, req *pb.UnlinkSocialAccountRequest) (*pb.UnlinkSocialAccountResponse, error) {
+    if _, ok := defaultSocialProviders[req.Provider]; !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported provider %q", req.Provider)
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    var unlinked bool
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("user_id = ? AND provider = ?", user.ID, req.Provider).Delete(&SocialAccount{})
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        unlinked = true
+        return recordAudit(ctx, tx, "unlink_social_account", "user", user.ID, map[string]string{"provider": req.Provider})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UnlinkSocialAccountResponse{Unlinked: unlinked}, nil
+}
+
+// FindUserBySocialAccount returns the user a provider account is linked to,
+// for signing in with it.
+func (s *server) FindUserBySocialAccount(ctx context.Context, req *pb.FindUserBySocialAccountRequest) (*pb.UserResponse, error) {
+    if _, ok := defaultSocialProviders[req.Provider]; !ok {
+        return nil, status.Errorf(codes.InvalidArgument, "unsupported provider %q", req.Provider)
+    }
+    var user User
+    err := s.db.WithContext(ctx).
+        Joins("JOIN social_accounts ON social_accounts.user_id = users.id").
+        Where("social_accounts.provider = ? AND social_accounts.provider_user_id = ?", req.Provider, req.ProviderUserId).
+        Take(&user).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, status.Errorf(codes.NotFound, "no user is linked to %s account %s", req.Provider, req.ProviderUserId)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &pb.UserResponse{User: &pb.User{Id: fmt.Sprint(user.ID), Name: user.Name, Email: user.Email}}, nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "golang.org/x/oauth2"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// fakeOAuthServer is an OAuth2 provider in the style of GitHub. Its token
+// endpoint trades the authorization codes in accounts for bearer tokens, and
+// its user info endpoint returns the numeric id of the token's account.
+func fakeOAuthServer(t *testing.T, accounts map[string]int64) *httptest.Server {
+    t.Helper()
+    tokens := make(map[string]int64)
+    mux := http.NewServeMux()
+    mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+        if err := r.ParseForm(); err != nil {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        id, secret, ok := r.BasicAuth()
+        if !ok {
+            id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+        }
+        w.Header().Set("Content-Type", "application/json")
+        account, known := accounts[r.PostForm.Get("code")]
+        switch {
+        case id != "client-id" || secret != "client-secret":
+            w.WriteHeader(http.StatusUnauthorized)
+            json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+        case r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != "https://shop.example/callback" || !known:
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "The code passed is incorrect or expired."})
+        default:
+            token := "gho_" + r.PostForm.Get("code")
+            tokens[token] = account
+            json.NewEncoder(w).Encode(map[string]interface{}{
+                "access_token":  token,
+                "token_type":    "bearer",
+                "scope":         "read:user",
+                "expires_in":    28800,
+                "refresh_token": "ghr_" + r.PostForm.Get("code"),
+            })
+        }
+    })
+    mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+        account, ok := tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+        if !ok {
+            http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+            return
+        }
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(map[string]interface{}{"login": "octocat", "id": account})
+    })
+    srv := httptest.NewServer(mux)
+    t.Cleanup(srv.Close)
+    return srv
+}
+
+func fakeGitHub(srv *httptest.Server) map[string]*socialProvider {
+    return map[string]*socialProvider{"github": {
+        config: oauth2.Config{
+            ClientID:     "client-id",
+            ClientSecret: "client-secret",
+            Endpoint: oauth2.Endpoint{
+                AuthURL:  srv.URL + "/login/oauth/authorize",
+                TokenURL: srv.URL + "/login/oauth/access_token",
+            },
+        },
+        userInfoURL: srv.URL + "/user",
+        idField:     "id",
+    }}
+}
+
+func socialAccountRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"id", "user_id", "provider", "provider_user_id", "access_token", "refresh_token"})
+}
+
+func TestSocialProviderExchange(t *testing.T) {
+    srv := fakeOAuthServer(t, map[string]int64{"code-ada": 583231})
+    provider := fakeGitHub(srv)["github"]
+    ctx := context.Background()
+
+    token, id, err := provider.exchange(ctx, "code-ada", "https://shop.example/callback")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if id != "583231" || token.AccessToken != "gho_code-ada" || token.RefreshToken != "ghr_code-ada" {
+        t.Errorf("got account %s, tokens %s and %s", id, token.AccessToken, token.RefreshToken)
+    }
+    if until := time.Until(token.Expiry); until < 7*time.Hour || until > 8*time.Hour {
+        t.Errorf("token expires in %v, want about 8h", until)
+    }
+
+    for _, code := range []string{"used-code", ""} {
+        if _, _, err := provider.exchange(ctx, code, "https://shop.example/callback"); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("exchange(%q) = %v, want InvalidArgument", code, err)
+        }
+    }
+    if _, _, err := provider.exchange(ctx, "code-ada", "https://evil.example/callback"); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("exchange with another redirect_uri = %v, want InvalidArgument", err)
+    }
+
+    provider.userInfoURL = srv.URL + "/missing"
+    if _, _, err := provider.exchange(ctx, "code-ada", "https://shop.example/callback"); status.Code(err) != codes.Unavailable {
+        t.Errorf("exchange without user info = %v, want Unavailable", err)
+    }
+}
+
+func TestLinkSocialAccount(t *testing.T) {
+    srv := fakeOAuthServer(t, map[string]int64{"first": 583231, "again": 583231, "other": 9001})
+    db, mock := newMockDB(t)
+    s := &server{db: db, socialProviders: fakeGitHub(srv)}
+    ctx := context.Background()
+    link := func(code string) error {
+        _, err := s.LinkSocialAccount(ctx, &pb.LinkSocialAccountRequest{
+            UserId:            "1",
+            Provider:          "github",
+            AuthorizationCode: code,
+            RedirectUri:       "https://shop.example/callback",
+        })
+        return err
+    }
+    expectUser := func() {
+        mock.ExpectQuery(`SELECT \* FROM "users"`).WithArgs(1).
+            WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "Ada", "ada@example.com"))
+    }
+
+    expectUser()
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "social_accounts" WHERE provider = \$1 AND \(provider_user_id = \$2 OR user_id = \$3\)`).
+        WithArgs("github", "583231", 1).
+        WillReturnRows(socialAccountRows())
+    mock.ExpectQuery(`INSERT INTO "social_accounts"`).
+        WithArgs(1, "github", "583231", "gho_first", "ghr_first", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    expectAudit(mock, "link_social_account", "user")
+    mock.ExpectCommit()
+    if err := link("first"); err != nil {
+        t.Fatal(err)
+    }
+
+    // Linking the same account again refreshes the tokens.
+    expectUser()
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "social_accounts"`).
+        WillReturnRows(socialAccountRows().AddRow(1, 1, "github", "583231", "gho_first", "ghr_first"))
+    mock.ExpectExec(`UPDATE "social_accounts" SET "access_token"=\$1,"refresh_token"=\$2,"expires_at"=\$3,"updated_at"=\$4 WHERE "id" = \$5`).
+        WithArgs("gho_again", "ghr_again", sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    expectAudit(mock, "link_social_account", "user")
+    mock.ExpectCommit()
+    if err := link("again"); err != nil {
+        t.Fatal(err)
+    }
+
+    // A second GitHub account for the same user is refused.
+    expectUser()
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "social_accounts"`).
+        WillReturnRows(socialAccountRows().AddRow(1, 1, "github", "583231", "gho_again", "ghr_again"))
+    mock.ExpectRollback()
+    if err := link("other"); status.Code(err) != codes.AlreadyExists {
+        t.Errorf("linking a second account = %v, want AlreadyExists", err)
+    }
+
+    // A rejected code never reaches the database after the user lookup.
+    expectUser()
+    if err := link("forged"); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("linking with a forged code = %v, want InvalidArgument", err)
+    }
+}
+
+func TestLinkSocialAccountRejectsUnknownProviders(t *testing.T) {
+    s := &server{socialProviders: map[string]*socialProvider{}}
+    for provider, want := range map[string]codes.Code{"myspace": codes.InvalidArgument, "google": codes.FailedPrecondition} {
+        _, err := s.LinkSocialAccount(context.Background(), &pb.LinkSocialAccountRequest{UserId: "1", Provider: provider, AuthorizationCode: "code"})
+        if status.Code(err) != want {
+            t.Errorf("provider %s: %v, want %v", provider, err, want)
+        }
+    }
+}
+
+func TestLoadSocialProviders(t *testing.T) {
+    t.Setenv("OAUTH_GITHUB_CLIENT_ID", "client-id")
+    t.Setenv("OAUTH_GITHUB_CLIENT_SECRET", "client-secret")
+    t.Setenv("OAUTH_GITHUB_TOKEN_URL", "http://fake/token")
+    t.Setenv("OAUTH_GOOGLE_CLIENT_ID", "")
+    t.Setenv("OAUTH_FACEBOOK_CLIENT_ID", "")
+    providers := loadSocialProviders()
+    if len(providers) != 1 || providers["github"] == nil {
+        t.Fatalf("got providers %v, want github only", providers)
+    }
+    github := providers["github"]
+    if github.config.ClientSecret != "client-secret" || github.config.Endpoint.TokenURL != "http://fake/token" ||
+        github.config.Endpoint.AuthURL != "https://github.com/login/oauth/authorize" {
+        t.Errorf("github config = %+v", github.config)
+    }
+    // Overrides must not leak into the defaults.
+    if defaultSocialProviders["github"].config.ClientID != "" {
+        t.Error("loadSocialProviders changed the default providers")
+    }
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+    "container/heap"
+    "context"
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+
+    "golang.org/x/sync/errgroup"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    "shared/audit"
+    pb "users-service/proto/gen/proto"
+)
+
+// timelineSourceService is the source_service of events read from this
+// service's own tables.
+const timelineSourceService = "users-service"
+
+// timelineSummaries describes the audited actions a support agent is most
+// likely to look for. Other events are summarized from their event type.
+var timelineSummaries = map[string]string{
+    "user.create":                       "Account created",
+    "user.merge":                        "Duplicate account merged in",
+    "user.delete":                       "Account merged into another and deleted",
+    "user.set_preference":               "Preference changed",
+    "user.link_social_account":          "Social account linked",
+    "user.unlink_social_account":        "Social account unlinked",
+    "user.enroll_totp":                  "Two-factor authentication enrolled",
+    "user.use_backup_code":              "Two-factor backup code used",
+    "user.set_communication_preference": "Communication preference changed",
+    "user_address.create":               "Address added",
+    "user_address.update":               "Address updated",
+    "user_address.delete":               "Address deleted",
+    "user_address.set_default":          "Default address changed",
+}
+
+// timelineQuery is what a timeline source is asked for. eventTypes is empty
+// for every type.
+type timelineQuery struct {
+    userID     uint
+    from, to   time.Time
+    eventTypes []string
+}
+
+// timelineSource returns a user's events in a time range, oldest first.
+type timelineSource func(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error)
+
+// timelineSources lists where GetUserTimeline gathers events from.
+func (s *server) timelineSources() []timelineSource {
+    return []timelineSource{s.userAuditTimeline, s.addressAuditTimeline}
+}
+
+// userAuditTimeline reads the audit log entries about the user themselves.
+func (s *server) userAuditTimeline(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error) {
+    query := s.db.WithContext(ctx).Where("entity_type = ? AND entity_id = ?", "user", fmt.Sprint(q.userID))
+    return s.auditTimeline(query, q)
+}
+
+// addressAuditTimeline reads the audit log entries about the user's
+// addresses, which are keyed by address and name the user in their details.
+func (s *server) addressAuditTimeline(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error) {
+    query := s.db.WithContext(ctx).Where("entity_type = ? AND details->>'user_id' = ?", "user_address", fmt.Sprint(q.userID))
+    return s.auditTimeline(query, q)
+}
+
+func (s *server) auditTimeline(query *gorm.DB, q timelineQuery) ([]*pb.TimelineEvent, error) {
+    query = query.Where("timestamp >= ? AND timestamp < ?", q.from, q.to)
+    if len(q.eventTypes) > 0 {
+        query = query.Where("entity_type || '.' || action IN ?", q.eventTypes)
+    }
+    var entries []audit.Entry
+    if err := query.Order("timestamp, id").Find(&entries).Error; err != nil {
+        return nil, err
+    }
+    events := make([]*pb.TimelineEvent, len(entries))
+    for i := range entries {
+        events[i] = auditEntryToTimelineEvent(&entries[i])
+    }
+    return events, nil
+}
+
+func auditEntryToTimelineEvent(a *audit.Entry) *pb.TimelineEvent {
+    eventType := a.EntityType + "." + a.Action
+    summary, ok := timelineSummaries[eventType]
+    if !ok {
+        summary = strings.ReplaceAll(eventType, "_", " ")
+    }
+    details := map[string]string{"actor": a.Actor}
+    var fields map[string]interface{}
+    if json.Unmarshal([]byte(a.Details), &fields) == nil {
+        for key, value := range fields {
+            details[key] = fmt.Sprint(value)
+        }
+    }
+    return &pb.TimelineEvent{
+        Timestamp:     timestamppb.New(a.Timestamp),
+        EventType:     eventType,
+        Summary:       summary,
+        Details:       details,
+        SourceService: timelineSourceService,
+    }
+}
+
+// GetUserTimeline streams a user's history for customer support, oldest
+// first. Every source is read concurrently, and their events are merged by
+// time. from defaults to the beginning and to, which is exclusive, to now.
+func (s *server) GetUserTimeline(req *pb.GetUserTimelineRequest, stream pb.UserService_GetUserTimelineServer) error {
+    ctx := stream.Context()
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return err
+    }
+    q := timelineQuery{userID: user.ID, to: time.Now(), eventTypes: req.EventTypes}
+    if req.From != nil {
+        q.from = req.From.AsTime()
+    }
+    if req.To != nil {
+        q.to = req.To.AsTime()
+    }
+    if q.to.Before(q.from) {
+        return status.Error(codes.InvalidArgument, "to must not be before from")
+    }
+
+    return mergeTimeline(ctx, s.timelineSources(), q, stream.Send)
+}
+
+// mergeTimeline reads every source concurrently and passes their events to
+// send in time order. Nothing is sent if any source fails.
+func mergeTimeline(ctx context.Context, sources []timelineSource, q timelineQuery, send func(*pb.TimelineEvent) error) error {
+    results := make([][]*pb.TimelineEvent, len(sources))
+    group, groupCtx := errgroup.WithContext(ctx)
+    for i, source := range sources {
+        i, source := i, source
+        group.Go(func() error {
+            events, err := source(groupCtx, q)
+            results[i] = events
+            return err
+        })
+    }
+    if err := group.Wait(); err != nil {
+        return err
+    }
+
+    merged := make(timelineHeap, 0, len(results))
+    for _, events := range results {
+        if len(events) > 0 {
+            merged = append(merged, events)
+        }
+    }
+    heap.Init(&merged)
+    for merged.Len() > 0 {
+        events := merged[0]
+        if err := send(events[0]); err != nil {
+            return err
+        }
+        if len(events) == 1 {
+            heap.Pop(&merged)
+        } else {
+            merged[0] = events[1:]
+            heap.Fix(&merged, 0)
+        }
+    }
+    return nil
+}
+
+// timelineHeap is a min-heap of each source's remaining events, ordered by
+// the time of their first event.
+type timelineHeap [][]*pb.TimelineEvent
+
+func (h timelineHeap) Len() int { return len(h) }
+
+func (h timelineHeap) Less(i, j int) bool {
+    return h[i][0].Timestamp.AsTime().Before(h[j][0].Timestamp.AsTime())
+}
+
+func (h timelineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *timelineHeap) Push(x interface{}) { *h = append(*h, x.([]*pb.TimelineEvent)) }
+
+func (h *timelineHeap) Pop() interface{} {
+    old := *h
+    events := old[len(old)-1]
+    *h = old[:len(old)-1]
+    return events
+}
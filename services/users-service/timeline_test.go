@@ -0,0 +1,150 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// timelineStream records the events GetUserTimeline sends.
+type timelineStream struct {
+    grpc.ServerStream
+    events []*pb.TimelineEvent
+}
+
+func (s *timelineStream) Context() context.Context { return context.Background() }
+
+func (s *timelineStream) Send(event *pb.TimelineEvent) error {
+    s.events = append(s.events, event)
+    return nil
+}
+
+var timelineStart = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// fakeTimelineSource stands in for another service, answering with an event
+// of eventType at each of the given minutes after timelineStart.
+func fakeTimelineSource(service, eventType string, minutes ...int) timelineSource {
+    return func(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error) {
+        var events []*pb.TimelineEvent
+        for _, minute := range minutes {
+            events = append(events, &pb.TimelineEvent{
+                Timestamp:     timestamppb.New(timelineStart.Add(time.Duration(minute) * time.Minute)),
+                EventType:     eventType,
+                SourceService: service,
+            })
+        }
+        return events, nil
+    }
+}
+
+func TestMergeTimelineInterleavesSourcesByTime(t *testing.T) {
+    sources := []timelineSource{
+        fakeTimelineSource("users-service", "user.create", 0, 30),
+        fakeTimelineSource("orders-service", "order.placed", 10, 20, 40),
+        fakeTimelineSource("email-service", "email.verified", 5),
+        fakeTimelineSource("users-service", "user_address.create"),
+    }
+    var got []string
+    err := mergeTimeline(context.Background(), sources, timelineQuery{}, func(event *pb.TimelineEvent) error {
+        got = append(got, event.SourceService+" "+event.EventType)
+        return nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []string{
+        "users-service user.create",
+        "email-service email.verified",
+        "orders-service order.placed",
+        "orders-service order.placed",
+        "users-service user.create",
+        "orders-service order.placed",
+    }
+    if len(got) != len(want) {
+        t.Fatalf("got %v, want %v", got, want)
+    }
+    for i := range want {
+        if got[i] != want[i] {
+            t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+        }
+    }
+}
+
+func TestMergeTimelineFailsWhenASourceFails(t *testing.T) {
+    unavailable := status.Error(codes.Unavailable, "orders-service is down")
+    sources := []timelineSource{
+        fakeTimelineSource("users-service", "user.create", 0),
+        func(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error) {
+            return nil, unavailable
+        },
+        // A slow source is cancelled rather than waited for.
+        func(ctx context.Context, q timelineQuery) ([]*pb.TimelineEvent, error) {
+            <-ctx.Done()
+            return nil, ctx.Err()
+        },
+    }
+    sent := 0
+    err := mergeTimeline(context.Background(), sources, timelineQuery{}, func(*pb.TimelineEvent) error {
+        sent++
+        return nil
+    })
+    if !errors.Is(err, unavailable) || sent != 0 {
+        t.Errorf("got %v after sending %d events, want the source's error and none sent", err, sent)
+    }
+}
+
+func TestGetUserTimelineReadsTheAuditLog(t *testing.T) {
+    db, mock := newMockDB(t)
+    // The sources query concurrently.
+    mock.MatchExpectationsInOrder(false)
+    mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1`).WithArgs(4).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "Pema"))
+    auditColumns := []string{"id", "action", "entity_type", "entity_id", "actor", "timestamp", "details"}
+    mock.ExpectQuery(`SELECT \* FROM "audit_logs" WHERE \(entity_type = \$1 AND entity_id = \$2\) AND \(timestamp >= \$3 AND timestamp < \$4\) AND entity_type \|\| '.' \|\| action IN \(\$5,\$6\) ORDER BY timestamp, id`).
+        WithArgs("user", "4", sqlmock.AnyArg(), sqlmock.AnyArg(), "user.create", "user_address.create").
+        WillReturnRows(sqlmock.NewRows(auditColumns).
+            AddRow(1, "create", "user", "4", "api-key:support", timelineStart, `{"email":"pema@example.com"}`))
+    mock.ExpectQuery(`SELECT \* FROM "audit_logs" WHERE \(entity_type = \$1 AND details->>'user_id' = \$2\)`).
+        WithArgs("user_address", "4", sqlmock.AnyArg(), sqlmock.AnyArg(), "user.create", "user_address.create").
+        WillReturnRows(sqlmock.NewRows(auditColumns).
+            AddRow(2, "create", "user_address", "9", "api-key:support", timelineStart.Add(time.Minute), `{"user_id":4}`))
+
+    stream := &timelineStream{}
+    req := &pb.GetUserTimelineRequest{UserId: "4", EventTypes: []string{"user.create", "user_address.create"}}
+    if err := (&server{db: db}).GetUserTimeline(req, stream); err != nil {
+        t.Fatal(err)
+    }
+    if len(stream.events) != 2 {
+        t.Fatalf("sent %d events, want 2", len(stream.events))
+    }
+    created := stream.events[0]
+    if created.EventType != "user.create" || created.Summary != "Account created" || created.SourceService != "users-service" ||
+        created.Details["email"] != "pema@example.com" || created.Details["actor"] != "api-key:support" {
+        t.Errorf("first event = %v", created)
+    }
+    if address := stream.events[1]; address.EventType != "user_address.create" || address.Details["user_id"] != "4" {
+        t.Errorf("second event = %v", address)
+    }
+}
+
+func TestGetUserTimelineRejectsAnInvertedRange(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+    req := &pb.GetUserTimelineRequest{
+        UserId: "4",
+        From:   timestamppb.New(timelineStart),
+        To:     timestamppb.New(timelineStart.Add(-time.Hour)),
+    }
+    if err := (&server{db: db}).GetUserTimeline(req, &timelineStream{}); status.Code(err) != codes.InvalidArgument {
+        t.Errorf("to before from = %v, want InvalidArgument", err)
+    }
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+    "context"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha1"
+    "crypto/subtle"
+    "encoding/base32"
+    "encoding/base64"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "log"
+    "net/url"
+    "os"
+    "strings"
+    "time"
+
+    "golang.org/x/crypto/bcrypt"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// TOTP parameters, the RFC 6238 defaults every authenticator app supports.
+const (
+    totpPeriod     = 30 * time.Second
+    totpDigits     = 6
+    totpSecretSize = 20
+    // totpSkew is how many periods either side of the current one a code is
+    // accepted from, to allow for clock drift.
+    totpSkew = 1
+)
+
+const (
+    backupCodeCount = 10
+    // backupCodeLength is the number of base32 characters in a backup code,
+    // shown to users in two hyphenated halves.
+    backupCodeLength = 10
+)
+
+// defaultTOTPIssuer is used when TOTP_ISSUER is not set.
+const defaultTOTPIssuer = "web303"
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// UserBackupCode is a single-use code that stands in for a TOTP code when
+// the user has lost their authenticator. Only its bcrypt hash is kept.
+type UserBackupCode struct {
+    ID        uint   `gorm:"primaryKey"`
+    UserID    uint   `gorm:"not null;index"`
+    CodeHash  string `gorm:"not null"`
+    UsedAt    *time.Time
+    CreatedAt time.Time
+}
+
+// loadTOTPKey reads TOTP_ENCRYPTION_KEY, the base64 AES-256 key TOTP secrets
+// are encrypted with. Without it TOTP is disabled.
+func loadTOTPKey() []byte {
+    value := os.Getenv("TOTP_ENCRYPTION_KEY")
+    if value == "" {
+        return nil
+    }
+    key, err := base64.StdEncoding.DecodeString(value)
+    if err != nil || len(key) != 32 {
+        log.Fatalf("TOTP_ENCRYPTION_KEY must be 32 bytes of base64")
+    }
+    return key
+}
+
+// sealTOTPSecret encrypts a secret with AES-GCM under key. TOTP secrets
+// cannot be hashed like passwords, since verifying a code needs the secret.
+func sealTOTPSecret(key, secret []byte) (string, error) {
+    gcm, err := newTOTPCipher(key)
+    if err != nil {
+        return "", err
+    }
+    nonce := make([]byte, gcm.NonceSize())
+    if _, err := rand.Read(nonce); err != nil {
+        return "", err
+    }
+    return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, secret, nil)), nil
+}
+
+func openTOTPSecret(key []byte, sealed string) ([]byte, error) {
+    gcm, err := newTOTPCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    data, err := base64.StdEncoding.DecodeString(sealed)
+    if err != nil || len(data) < gcm.NonceSize() {
+        return nil, errors.New("malformed TOTP secret")
+    }
+    return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func newTOTPCipher(key []byte) (cipher.AEAD, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    return cipher.NewGCM(block)
+}
+
+// totpCode computes the RFC 6238 code for a time step: an HMAC-SHA1 of the
+// step, dynamically truncated to totpDigits digits.
+func totpCode(secret []byte, step int64) string {
+    var msg [8]byte
+    binary.BigEndian.PutUint64(msg[:], uint64(step))
+    mac := hmac.New(sha1.New, secret)
+    mac.Write(msg[:])
+    sum := mac.Sum(nil)
+    offset := sum[len(sum)-1] & 0x0f
+    value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+    return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// matchTOTPStep returns the time step within totpSkew of now whose code is
+// code, if there is one.
+func matchTOTPStep(secret []byte, code string, now time.Time) (int64, bool) {
+    current := now.Unix() / int64(totpPeriod/time.Second)
+    for step := current - totpSkew; step <= current+totpSkew; step++ {
+        if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+            return step, true
+        }
+    }
+    return 0, false
+}
+
+// totpURI is the otpauth:// provisioning URI authenticator apps enroll
+// from, usually shown as a QR code.
+func totpURI(secret, account string) string {
+    issuer := os.Getenv("TOTP_ISSUER")
+    if issuer == "" {
+        issuer = defaultTOTPIssuer
+    }
+    query := url.Values{}
+    query.Set("secret", secret)
+    query.Set("issuer", issuer)
+    query.Set("algorithm", "SHA1")
+    query.Set("digits", fmt.Sprint(totpDigits))
+    query.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+    return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// newBackupCode returns a random backup code such as "k3f9a-q2m7x".
+func newBackupCode() (string, error) {
+    raw := make([]byte, backupCodeLength*5/8)
+    if _, err := rand.Read(raw); err != nil {
+        return "", err
+    }
+    code := strings.ToLower(totpEncoding.EncodeToString(raw))
+    return code[:backupCodeLength/2] + "-" + code[backupCodeLength/2:], nil
+}
+
+// normalizeBackupCode undoes the formatting users may add or drop when they
+// type a backup code.
+func normalizeBackupCode(code string) string {
+    return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// EnrollTOTP gives the user a new TOTP secret and backup codes, replacing
+// any they had. The secret and codes are only ever returned here.
+func (s *server) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+    if s.totpKey == nil {
+        return nil, status.Error(codes.FailedPrecondition, "TOTP is not configured")
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+
+    secret := make([]byte, totpSecretSize)
+    if _, err := rand.Read(secret); err != nil {
+        return nil, err
+    }
+    sealed, err := sealTOTPSecret(s.totpKey, secret)
+    if err != nil {
+        return nil, err
+    }
+    res := &pb.EnrollTOTPResponse{Secret: totpEncoding.EncodeToString(secret)}
+    res.QrCodeUri = totpURI(res.Secret, user.Email)
+    backupCodes := make([]UserBackupCode, backupCodeCount)
+    for i := range backupCodes {
+        code, err := newBackupCode()
+        if err != nil {
+            return nil, err
+        }
+        hash, err := bcrypt.GenerateFromPassword([]byte(normalizeBackupCode(code)), bcrypt.DefaultCost)
+        if err != nil {
+            return nil, err
+        }
+        res.BackupCodes = append(res.BackupCodes, code)
+        backupCodes[i] = UserBackupCode{UserID: user.ID, CodeHash: string(hash)}
+    }
+
+    err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(user).UpdateColumns(map[string]interface{}{"totp_secret": sealed, "totp_last_step": nil}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("user_id = ?", user.ID).Delete(&UserBackupCode{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Create(&backupCodes).Error; err != nil {
+            return err
+        }
+        return recordAudit(ctx, tx, "enroll_totp", "user", user.ID, map[string]int{"backup_codes": backupCodeCount})
+    })
+    if err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+// VerifyTOTP checks a 6-digit code from the user's authenticator, or one of
+// their backup codes. Each TOTP code is accepted once, and only if it is
+// newer than the last one accepted; each backup code is used up.
+// users-service keeps no passwords, so there is no password check here to
+// require the code: whatever authenticates the user's first factor calls
+// VerifyTOTP after it for users who have enrolled.
+func (s *server) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPRequest) (*pb.VerifyTOTPResponse, error) {
+    if s.totpKey == nil {
+        return nil, status.Error(codes.FailedPrecondition, "TOTP is not configured")
+    }
+    user, err := s.findUser(ctx, req.UserId)
+    if err != nil {
+        return nil, err
+    }
+    if user.TOTPSecret == nil {
+        return nil, status.Errorf(codes.FailedPrecondition, "user %s has not enrolled in TOTP", req.UserId)
+    }
+    code := strings.TrimSpace(req.Code)
+    if len(code) != totpDigits || strings.Trim(code, "0123456789") != "" {
+        valid, err := s.useBackupCode(ctx, user.ID, normalizeBackupCode(code))
+        return &pb.VerifyTOTPResponse{Valid: valid}, err
+    }
+
+    secret, err := openTOTPSecret(s.totpKey, *user.TOTPSecret)
+    if err != nil {
+        return nil, err
+    }
+    step, ok := matchTOTPStep(secret, code, time.Now())
+    if !ok {
+        return &pb.VerifyTOTPResponse{}, nil
+    }
+    // Recording the step in the same statement that checks it stops a code,
+    // or an older one, being replayed, even by a concurrent request.
+    result := s.db.WithContext(ctx).Model(&User{}).
+        Where("id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)", user.ID, step).
+        UpdateColumn("totp_last_step", step)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return &pb.VerifyTOTPResponse{Valid: result.RowsAffected == 1}, nil
+}
+
+// useBackupCode marks the user's unused backup code matching code as used,
+// reporting whether there was one.
+func (s *server) useBackupCode(ctx context.Context, userID uint, code string) (bool, error) {
+    if len(code) != backupCodeLength {
+        return false, nil
+    }
+    used := false
+    err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var backupCodes []UserBackupCode
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ? AND used_at IS NULL", userID).Find(&backupCodes).Error; err != nil {
+            return err
+        }
+        for _, backupCode := range backupCodes {
+            if bcrypt.CompareHashAndPassword([]byte(backupCode.CodeHash), []byte(code)) != nil {
+                continue
+            }
+            if err := tx.Model(&backupCode).UpdateColumn("used_at", time.Now()).Error; err != nil {
+                return err
+            }
+            used = true
+            return recordAudit(ctx, tx, "use_backup_code", "user", userID, map[string]uint{"backup_code_id": backupCode.ID})
+        }
+        return nil
+    })
+    return used, err
+}
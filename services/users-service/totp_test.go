@@ -0,0 +1,274 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "net/url"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "golang.org/x/crypto/bcrypt"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "users-service/proto/gen/proto"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 4226 and RFC 6238 test vectors.
+var rfcSecret = []byte("12345678901234567890")
+
+var testTOTPKey = bytes.Repeat([]byte{7}, 32)
+
+func TestTOTPCodeHOTPVectors(t *testing.T) {
+    // RFC 4226 appendix D: HOTP values for counters 0 to 9.
+    want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+    for counter, code := range want {
+        if got := totpCode(rfcSecret, int64(counter)); got != code {
+            t.Errorf("counter %d: code = %s, want %s", counter, got, code)
+        }
+    }
+}
+
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+    // RFC 6238 appendix B, SHA-1 column. The RFC lists 8-digit codes; a
+    // 6-digit code is their last six digits.
+    tests := []struct {
+        unix int64
+        want string
+    }{
+        {59, "94287082"},
+        {1111111109, "07081804"},
+        {1111111111, "14050471"},
+        {1234567890, "89005924"},
+        {2000000000, "69279037"},
+        {20000000000, "65353130"},
+    }
+    for _, tt := range tests {
+        step := tt.unix / int64(totpPeriod/time.Second)
+        if got := totpCode(rfcSecret, step); got != tt.want[2:] {
+            t.Errorf("T=%d: code = %s, want %s", tt.unix, got, tt.want[2:])
+        }
+        if _, ok := matchTOTPStep(rfcSecret, tt.want[2:], time.Unix(tt.unix, 0)); !ok {
+            t.Errorf("T=%d: code %s not accepted", tt.unix, tt.want[2:])
+        }
+    }
+}
+
+func TestMatchTOTPStepRotation(t *testing.T) {
+    now := time.Unix(1111111111, 0)
+    current := now.Unix() / int64(totpPeriod/time.Second)
+    for offset := int64(-3); offset <= 3; offset++ {
+        step, ok := matchTOTPStep(rfcSecret, totpCode(rfcSecret, current+offset), now)
+        wantOK := offset >= -totpSkew && offset <= totpSkew
+        if ok != wantOK {
+            t.Errorf("code from %d steps away: accepted = %v, want %v", offset, ok, wantOK)
+        }
+        if ok && step != current+offset {
+            t.Errorf("code from %d steps away matched step %d, want %d", offset, step, current+offset)
+        }
+    }
+    // The code rotates every period.
+    if totpCode(rfcSecret, current) == totpCode(rfcSecret, current+1) {
+        t.Error("consecutive steps have the same code")
+    }
+}
+
+func TestSealTOTPSecret(t *testing.T) {
+    sealed, err := sealTOTPSecret(testTOTPKey, rfcSecret)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if strings.Contains(sealed, string(rfcSecret)) {
+        t.Error("sealed secret contains the plaintext")
+    }
+    opened, err := openTOTPSecret(testTOTPKey, sealed)
+    if err != nil || !bytes.Equal(opened, rfcSecret) {
+        t.Fatalf("openTOTPSecret = %q, %v; want the secret", opened, err)
+    }
+    if _, err := openTOTPSecret(bytes.Repeat([]byte{8}, 32), sealed); err == nil {
+        t.Error("secret opened with the wrong key")
+    }
+    if _, err := openTOTPSecret(testTOTPKey, "AAAA"); err == nil {
+        t.Error("truncated secret opened")
+    }
+}
+
+func TestTOTPURI(t *testing.T) {
+    t.Setenv("TOTP_ISSUER", "Acme Shop")
+    u, err := url.Parse(totpURI("GEZDGNBV", "pema@example.com"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Acme Shop:pema@example.com" {
+        t.Errorf("URI = %s, want otpauth://totp/Acme Shop:pema@example.com", u)
+    }
+    query := u.Query()
+    for key, want := range map[string]string{"secret": "GEZDGNBV", "issuer": "Acme Shop", "algorithm": "SHA1", "digits": "6", "period": "30"} {
+        if got := query.Get(key); got != want {
+            t.Errorf("%s = %q, want %q", key, got, want)
+        }
+    }
+}
+
+func TestBackupCodeFormat(t *testing.T) {
+    code, err := newBackupCode()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(code) != backupCodeLength+1 || code[backupCodeLength/2] != '-' {
+        t.Errorf("backup code %q is not two hyphenated halves", code)
+    }
+    if got := normalizeBackupCode(" " + strings.ToUpper(code) + " "); got != strings.Replace(code, "-", "", 1) {
+        t.Errorf("normalizeBackupCode = %q", got)
+    }
+}
+
+func expectTOTPUser(t *testing.T, mock sqlmock.Sqlmock) {
+    t.Helper()
+    sealed, err := sealTOTPSecret(testTOTPKey, rfcSecret)
+    if err != nil {
+        t.Fatal(err)
+    }
+    mock.ExpectQuery(`SELECT \* FROM "users"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "email", "totp_secret"}).AddRow(1, "pema@example.com", sealed))
+}
+
+func TestVerifyTOTPReplay(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, totpKey: testTOTPKey}
+    step := time.Now().Unix() / int64(totpPeriod/time.Second)
+    req := &pb.VerifyTOTPRequest{UserId: "1", Code: totpCode(rfcSecret, step)}
+    // The code could belong to the next step too if the period turns
+    // during the test, so the step recorded is not checked.
+    update := `UPDATE "users" SET "totp_last_step"=\$1 WHERE \(id = \$2 AND \(totp_last_step IS NULL OR totp_last_step < \$3\)\)`
+
+    expectTOTPUser(t, mock)
+    mock.ExpectBegin()
+    mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+    res, err := s.VerifyTOTP(context.Background(), req)
+    if err != nil || !res.Valid {
+        t.Fatalf("first use: VerifyTOTP = %v, %v; want valid", res, err)
+    }
+
+    // The step is already recorded, so the conditional update matches no
+    // row and the replayed code is refused.
+    expectTOTPUser(t, mock)
+    mock.ExpectBegin()
+    mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectCommit()
+    if res, err = s.VerifyTOTP(context.Background(), req); err != nil || res.Valid {
+        t.Fatalf("replay: VerifyTOTP = %v, %v; want invalid", res, err)
+    }
+}
+
+func TestVerifyTOTPWrongCode(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, totpKey: testTOTPKey}
+    // A code from outside the window is refused without touching the user.
+    step := time.Now().Unix()/int64(totpPeriod/time.Second) - 5
+    expectTOTPUser(t, mock)
+    res, err := s.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{UserId: "1", Code: totpCode(rfcSecret, step)})
+    if err != nil || res.Valid {
+        t.Fatalf("VerifyTOTP = %v, %v; want invalid", res, err)
+    }
+}
+
+func TestVerifyTOTPBackupCode(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, totpKey: testTOTPKey}
+    hash, err := bcrypt.GenerateFromPassword([]byte("k3f9aq2m7x"), bcrypt.MinCost)
+    if err != nil {
+        t.Fatal(err)
+    }
+    other, err := bcrypt.GenerateFromPassword([]byte("aaaaabbbbb"), bcrypt.MinCost)
+    if err != nil {
+        t.Fatal(err)
+    }
+    req := &pb.VerifyTOTPRequest{UserId: "1", Code: "K3F9A-Q2M7X"}
+    selectCodes := `SELECT \* FROM "user_backup_codes" WHERE user_id = \$1 AND used_at IS NULL FOR UPDATE`
+
+    expectTOTPUser(t, mock)
+    mock.ExpectBegin()
+    mock.ExpectQuery(selectCodes).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "code_hash"}).AddRow(4, 1, other).AddRow(5, 1, hash))
+    mock.ExpectExec(`UPDATE "user_backup_codes" SET "used_at"=\$1 WHERE "id" = \$2`).
+        WithArgs(sqlmock.AnyArg(), 5).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("use_backup_code", "user", "1", sqlmock.AnyArg(), sqlmock.AnyArg(), `{"backup_code_id":5}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+    res, err := s.VerifyTOTP(context.Background(), req)
+    if err != nil || !res.Valid {
+        t.Fatalf("first use: VerifyTOTP = %v, %v; want valid", res, err)
+    }
+
+    // Once used, the code is no longer among the user's unused codes.
+    expectTOTPUser(t, mock)
+    mock.ExpectBegin()
+    mock.ExpectQuery(selectCodes).WithArgs(1).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "code_hash"}).AddRow(4, 1, other))
+    mock.ExpectCommit()
+    if res, err = s.VerifyTOTP(context.Background(), req); err != nil || res.Valid {
+        t.Fatalf("second use: VerifyTOTP = %v, %v; want invalid", res, err)
+    }
+}
+
+func TestVerifyTOTPNotEnrolled(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, totpKey: testTOTPKey}
+    mock.ExpectQuery(`SELECT \* FROM "users"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "pema@example.com"))
+    if _, err := s.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{UserId: "1", Code: "123456"}); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("err = %v, want FailedPrecondition", err)
+    }
+
+    s.totpKey = nil
+    if _, err := s.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{UserId: "1", Code: "123456"}); status.Code(err) != codes.FailedPrecondition {
+        t.Errorf("without a key: err = %v, want FailedPrecondition", err)
+    }
+}
+
+func TestEnrollTOTP(t *testing.T) {
+    db, mock := newMockDB(t)
+    s := &server{db: db, totpKey: testTOTPKey}
+    mock.ExpectQuery(`SELECT \* FROM "users"`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "pema@example.com"))
+    mock.ExpectBegin()
+    mock.ExpectExec(`UPDATE "users" SET "totp_last_step"=\$1,"totp_secret"=\$2 WHERE "users"."deleted_at" IS NULL AND "id" = \$3`).
+        WithArgs(nil, sqlmock.AnyArg(), 1).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec(`DELETE FROM "user_backup_codes" WHERE user_id = \$1`).WithArgs(1).
+        WillReturnResult(sqlmock.NewResult(0, 10))
+    rows := sqlmock.NewRows([]string{"id"})
+    for i := 1; i <= backupCodeCount; i++ {
+        rows.AddRow(i)
+    }
+    mock.ExpectQuery(`INSERT INTO "user_backup_codes"`).WillReturnRows(rows)
+    mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+        WithArgs("enroll_totp", "user", "1", sqlmock.AnyArg(), sqlmock.AnyArg(), `{"backup_codes":10}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    res, err := s.EnrollTOTP(context.Background(), &pb.EnrollTOTPRequest{UserId: "1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    secret, err := totpEncoding.DecodeString(res.Secret)
+    if err != nil || len(secret) != totpSecretSize {
+        t.Errorf("secret %q does not decode to %d bytes", res.Secret, totpSecretSize)
+    }
+    if !strings.HasPrefix(res.QrCodeUri, "otpauth://totp/") || !strings.Contains(res.QrCodeUri, "secret="+res.Secret) {
+        t.Errorf("QrCodeUri = %q", res.QrCodeUri)
+    }
+    seen := make(map[string]bool)
+    for _, code := range res.BackupCodes {
+        seen[code] = true
+    }
+    if len(res.BackupCodes) != backupCodeCount || len(seen) != backupCodeCount {
+        t.Errorf("got backup codes %v, want %d distinct codes", res.BackupCodes, backupCodeCount)
+    }
+}
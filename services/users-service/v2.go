@@ -0,0 +1,73 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/google/uuid"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "gorm.io/gorm"
+
+    pbv2 "users-service/proto/gen/proto/v2"
+)
+
+// maxDisplayNameLength caps a user's display name, in characters.
+const maxDisplayNameLength = 200
+
+// serverV2 implements users.v2.UserService on top of the same business logic
+// as the v1 server. Users are identified by their UUID.
+type serverV2 struct {
+    pbv2.UnimplementedUserServiceServer
+    core *server
+}
+
+func (s *serverV2) CreateUser(ctx context.Context, req *pbv2.CreateUserRequest) (*pbv2.UserResponse, error) {
+    user, err := s.createUser(ctx, req)
+    if err != nil {
+        return nil, err
+    }
+    return &pbv2.UserResponse{User: user.toProtoV2()}, nil
+}
+
+// createUser validates req and stores the user. The v1 CreateUser handler
+// converts its request and comes through here too, so both versions accept
+// exactly the same users.
+func (s *serverV2) createUser(ctx context.Context, req *pbv2.CreateUserRequest) (*User, error) {
+    if strings.TrimSpace(req.DisplayName) == "" {
+        return nil, status.Error(codes.InvalidArgument, "display_name must not be empty")
+    }
+    if n := utf8.RuneCountInString(req.DisplayName); n > maxDisplayNameLength {
+        return nil, status.Errorf(codes.InvalidArgument, "display_name is %d characters, more than the maximum of %d", n, maxDisplayNameLength)
+    }
+    return s.core.createUser(ctx, req.DisplayName, req.Email)
+}
+
+func (s *serverV2) GetUser(ctx context.Context, req *pbv2.GetUserRequest) (*pbv2.UserResponse, error) {
+    id, err := uuid.Parse(req.Id)
+    if err != nil {
+        return nil, status.Errorf(codes.InvalidArgument, "invalid user id %q", req.Id)
+    }
+
+    var user User
+    if err := s.core.db.WithContext(ctx).Where("uuid = ?", id.String()).First(&user).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, status.Errorf(codes.NotFound, "user %s not found", req.Id)
+        }
+        return nil, err
+    }
+    return &pbv2.UserResponse{User: user.toProtoV2()}, nil
+}
+
+func (u *User) toProtoV2() *pbv2.User {
+    return &pbv2.User{
+        Id:          u.UUID,
+        DisplayName: u.Name,
+        Email:       u.Email,
+        CreatedAt:   timestamppb.New(u.CreatedAt),
+        UpdatedAt:   timestamppb.New(u.UpdatedAt),
+    }
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    "shared/fakedata"
+    pb "users-service/proto/gen/proto"
+    pbv2 "users-service/proto/gen/proto/v2"
+)
+
+func TestCreateUserRejectsBadDisplayNames(t *testing.T) {
+    for _, name := range []string{"", "  ", strings.Repeat("é", maxDisplayNameLength+1)} {
+        // No statement is expected: both versions reject the user before
+        // touching the database.
+        db, _ := newMockDB(t)
+        s := &server{db: db}
+        gen := fakedata.NewGenerator(t)
+
+        if _, err := s.CreateUser(context.Background(), fakedata.User[*pb.CreateUserRequest](gen, fakedata.WithUserName(name))); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("v1 with name %q: %v, want InvalidArgument", name, err)
+        }
+        _, err := (&serverV2{core: s}).CreateUser(context.Background(), &pbv2.CreateUserRequest{DisplayName: name, Email: gen.Email()})
+        if status.Code(err) != codes.InvalidArgument {
+            t.Errorf("v2 with display_name %q: %v, want InvalidArgument", name, err)
+        }
+    }
+}
+
+func TestV1CreateUserWritesThroughV2(t *testing.T) {
+    db, mock := newMockDB(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "users"`).
+        WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Ada", " Ada@Example.com", "ada@example.com", nil, nil, sqlmock.AnyArg()).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+    expectAudit(mock, "create", "user")
+    mock.ExpectCommit()
+
+    res, err := (&server{db: db}).CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Ada", Email: " Ada@Example.com"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.User.Id != "3" || res.User.Name != "Ada" {
+        t.Errorf("created user %s %q, want 3 Ada", res.User.Id, res.User.Name)
+    }
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+    "context"
+    "log"
+    "sync"
+    "time"
+
+    "gorm.io/gorm"
+
+    "shared/healthcheck"
+    "shared/pagination"
+)
+
+// warmupQueries are the hot read paths, run before the instance reports
+// SERVING so the connection pool and Postgres' caches are warm when traffic
+// arrives. They run concurrently, which also opens several pool connections.
+var warmupQueries = []func(ctx context.Context, db *gorm.DB) error{
+    func(ctx context.Context, db *gorm.DB) error {
+        var users []User
+        return db.WithContext(ctx).Order("id").Limit(pagination.DefaultPageSize).Find(&users).Error
+    },
+    func(ctx context.Context, db *gorm.DB) error {
+        var count int64
+        return db.WithContext(ctx).Model(&User{}).Count(&count).Error
+    },
+}
+
+// warmUp runs the warmup queries and, once they have finished and delay has
+// passed, tells watcher the instance is warm. With no delay it is warm
+// straight away and nothing is run.
+func warmUp(db *gorm.DB, watcher *healthcheck.HealthWatcher, delay time.Duration) {
+    if delay <= 0 {
+        watcher.SetWarm()
+        return
+    }
+
+    go func() {
+        start := time.Now()
+        ctx, cancel := context.WithTimeout(context.Background(), delay+30*time.Second)
+        defer cancel()
+        var wg sync.WaitGroup
+        for _, query := range warmupQueries {
+            wg.Add(1)
+            go func(query func(context.Context, *gorm.DB) error) {
+                defer wg.Done()
+                if err := query(ctx, db); err != nil {
+                    log.Printf("Warmup query failed: %v", err)
+                }
+            }(query)
+        }
+        wg.Wait()
+        time.Sleep(time.Until(start.Add(delay)))
+        log.Printf("Warmed up after %v", time.Since(start))
+        watcher.SetWarm()
+    }()
+}
@@ -0,0 +1,86 @@
+// Package audit keeps an append-only log of mutations: who made each one, to
+// what, and with which values.
+package audit
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "gorm.io/gorm"
+
+    "shared/pagination"
+)
+
+// Entry records one mutation. Entries are written in the mutation's
+// transaction, so a change is never committed without its entry, and the
+// table rejects updates and deletes.
+type Entry struct {
+    ID         uint      `gorm:"primaryKey"`
+    Action     string    `gorm:"not null"`
+    EntityType string    `gorm:"not null;index:idx_audit_logs_entity,priority:1"`
+    EntityID   string    `gorm:"not null;index:idx_audit_logs_entity,priority:2"`
+    Actor      string    `gorm:"not null"`
+    Timestamp  time.Time `gorm:"not null"`
+    Details    string    `gorm:"type:jsonb;not null;default:'{}'"`
+}
+
+// TableName keeps the table named audit_logs.
+func (Entry) TableName() string {
+    return "audit_logs"
+}
+
+// MigrateTrigger makes audit_logs append-only. It must run after Entry is
+// migrated.
+func MigrateTrigger(db *gorm.DB) error {
+    statements := []string{
+        `CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+        BEGIN
+            RAISE EXCEPTION 'audit_logs is append-only';
+        END
+        $$ LANGUAGE plpgsql`,
+        `DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`,
+        `CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+            FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()`,
+    }
+    for _, statement := range statements {
+        if err := db.Exec(statement).Error; err != nil {
+            return fmt.Errorf("migrate audit_logs trigger: %w", err)
+        }
+    }
+    return nil
+}
+
+// Record writes an entry for a mutation made by actor. tx must be the
+// mutation's transaction. details is stored as JSON.
+func Record(tx *gorm.DB, actor, action, entityType string, entityID interface{}, details interface{}) error {
+    raw, err := json.Marshal(details)
+    if err != nil {
+        return err
+    }
+    return tx.Create(&Entry{
+        Action:     action,
+        EntityType: entityType,
+        EntityID:   fmt.Sprint(entityID),
+        Actor:      actor,
+        Timestamp:  time.Now(),
+        Details:    string(raw),
+    }).Error
+}
+
+// Page returns up to size entries after the cursor, oldest first, and the
+// token of the next page. entityType and entityID, when not empty, select
+// the entries for one entity type or entity.
+func Page(ctx context.Context, db *gorm.DB, entityType, entityID string, after pagination.PageToken, size int) ([]Entry, pagination.PageToken, error) {
+    query := db.WithContext(ctx)
+    if entityType != "" {
+        query = query.Where("entity_type = ?", entityType)
+    }
+    if entityID != "" {
+        query = query.Where("entity_id = ?", entityID)
+    }
+    var entries []Entry
+    next, _, err := pagination.NewCursorPaginator(query, "id").Page(ctx, &entries, after, size)
+    return entries, next, err
+}
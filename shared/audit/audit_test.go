@@ -0,0 +1,93 @@
+package audit
+
+import (
+    "context"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/gorm"
+
+    "shared/pagination"
+    "shared/testdb"
+)
+
+func TestRecord(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`INSERT INTO "audit_logs" \("action","entity_type","entity_id","actor","timestamp","details"\)`).
+        WithArgs("update", "product", "42", "key:0123456789ab", sqlmock.AnyArg(), `{"status":"archived"}`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    mock.ExpectCommit()
+
+    err := db.Transaction(func(tx *gorm.DB) error {
+        return Record(tx, "key:0123456789ab", "update", "product", uint(42), map[string]string{"status": "archived"})
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestPageFiltersByEntity(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    mock.ExpectQuery(`SELECT \* FROM "audit_logs" WHERE entity_type = \$1 AND entity_id = \$2 ORDER BY id LIMIT 3`).
+        WithArgs("user", "7").
+        WillReturnRows(sqlmock.NewRows([]string{"id", "action", "entity_type", "entity_id"}).
+            AddRow(3, "create", "user", "7").
+            AddRow(9, "set_preference", "user", "7"))
+
+    entries, next, err := Page(context.Background(), db, "user", "7", pagination.PageToken{}, 2)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(entries) != 2 || entries[0].Action != "create" || !next.IsZero() {
+        t.Errorf("got %v with next page %v, want both entries and no next page", entries, next)
+    }
+}
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&Entry{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := MigrateTrigger(db); err != nil {
+        t.Fatal(err)
+    }
+    ctx := context.Background()
+    for i := 0; i < 3; i++ {
+        if err := Record(db, "anonymous", "create", "product", i+1, map[string]int{"n": i}); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := Record(db, "anonymous", "create", "user", 1, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := db.Model(&Entry{}).Where("entity_id = ?", "1").Update("actor", "someone else").Error; err == nil {
+        t.Error("updating an entry succeeded")
+    }
+    if err := db.Where("1 = 1").Delete(&Entry{}).Error; err == nil {
+        t.Error("deleting entries succeeded")
+    }
+
+    var seen []string
+    var after pagination.PageToken
+    for {
+        entries, next, err := Page(ctx, db, "product", "", after, 2)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, e := range entries {
+            if e.Actor != "anonymous" {
+                t.Errorf("entry %d has actor %q after the failed update", e.ID, e.Actor)
+            }
+            seen = append(seen, e.EntityID)
+        }
+        if next.IsZero() {
+            break
+        }
+        after = next
+    }
+    if len(seen) != 3 || seen[0] != "1" || seen[2] != "3" {
+        t.Errorf("paged through product entries %v, want 1, 2 and 3 in order", seen)
+    }
+}
@@ -0,0 +1,55 @@
+// Package automigrate runs GORM's AutoMigrate and logs what it changed.
+package automigrate
+
+import (
+    "fmt"
+    "log"
+    "sort"
+    "strings"
+
+    "gorm.io/gorm"
+)
+
+// Run runs AutoMigrate for each model in turn and logs what it changed. GORM
+// does not report its changes, so tables, columns and indexes missing
+// beforehand are listed; altered column types are not detected.
+func Run(db *gorm.DB, models ...interface{}) error {
+    for _, model := range models {
+        stmt := &gorm.Statement{DB: db}
+        if err := stmt.Parse(model); err != nil {
+            return fmt.Errorf("parse %T: %w", model, err)
+        }
+        table := stmt.Schema.Table
+        migrator := db.Migrator()
+
+        existed := migrator.HasTable(model)
+        var columns, indexes []string
+        if existed {
+            for _, field := range stmt.Schema.Fields {
+                if field.DBName != "" && !migrator.HasColumn(model, field.DBName) {
+                    columns = append(columns, field.DBName)
+                }
+            }
+            for name := range stmt.Schema.ParseIndexes() {
+                if !migrator.HasIndex(model, name) {
+                    indexes = append(indexes, name)
+                }
+            }
+            sort.Strings(indexes)
+        }
+
+        if err := db.AutoMigrate(model); err != nil {
+            return fmt.Errorf("migrate %s: %w", table, err)
+        }
+
+        switch {
+        case !existed:
+            log.Printf("Migrated %s: created table", table)
+        case len(columns) > 0 || len(indexes) > 0:
+            log.Printf("Migrated %s: added columns [%s], indexes [%s]", table, strings.Join(columns, ", "), strings.Join(indexes, ", "))
+        default:
+            log.Printf("Migrated %s: no changes", table)
+        }
+    }
+    return nil
+}
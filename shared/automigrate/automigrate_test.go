@@ -0,0 +1,64 @@
+package automigrate
+
+import (
+    "bytes"
+    "log"
+    "strings"
+    "testing"
+
+    "shared/testdb"
+)
+
+type Product struct {
+    ID   uint
+    Name string `gorm:"index"`
+}
+
+type DiscountCode struct {
+    ID        uint
+    Code      string
+    ProductID uint `gorm:"index"`
+}
+
+func TestAutoMigrateLogsWhatChanged(t *testing.T) {
+    db := testdb.Postgres(t)
+    var out bytes.Buffer
+    defer log.SetOutput(log.Writer())
+    log.SetOutput(&out)
+
+    if err := db.Exec(`CREATE TABLE discount_codes (id bigserial PRIMARY KEY, code text)`).Error; err != nil {
+        t.Fatal(err)
+    }
+    if err := Run(db, &Product{}, &DiscountCode{}); err != nil {
+        t.Fatal(err)
+    }
+    if err := Run(db, &Product{}); err != nil {
+        t.Fatal(err)
+    }
+
+    lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+    want := []string{
+        "Migrated products: created table",
+        "Migrated discount_codes: added columns [",
+        "Migrated products: no changes",
+    }
+    if len(lines) != len(want) {
+        t.Fatalf("logged %q, want %d lines", lines, len(want))
+    }
+    for i := range want {
+        if !strings.Contains(lines[i], want[i]) {
+            t.Errorf("line %d = %q, want it to contain %q", i, lines[i], want[i])
+        }
+    }
+}
+
+func TestAutoMigrateReturnsErrors(t *testing.T) {
+    db := testdb.Postgres(t)
+    // A view named like the table makes CREATE TABLE fail.
+    if err := db.Exec(`CREATE VIEW products AS SELECT 1 AS id`).Error; err != nil {
+        t.Fatal(err)
+    }
+    if err := Run(db, &Product{}); err == nil {
+        t.Error("Run over a view succeeded")
+    }
+}
@@ -0,0 +1,217 @@
+// Package backfill runs resumable data migrations that rewrite a table in
+// small batches while the service keeps serving traffic.
+//
+// A job pages through its table by primary key. Each batch is transformed
+// and its progress recorded in backfill_progress in the same transaction, so
+// a restarted job resumes after the last committed batch and never applies a
+// batch twice. The progress row is locked for the batch, which also keeps
+// replicas from running the same batch concurrently.
+package backfill
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+var (
+    rowsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "backfill_rows_processed_total",
+        Help: "Rows processed by each backfill job.",
+    }, []string{"job"})
+    lastID = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+        Name: "backfill_last_id",
+        Help: "Highest primary key each backfill job has processed.",
+    }, []string{"job"})
+    done = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+        Name: "backfill_done",
+        Help: "1 once a backfill job has processed its whole table.",
+    }, []string{"job"})
+)
+
+func init() {
+    prometheus.MustRegister(rowsProcessed, lastID, done)
+}
+
+// Progress is a job's row in backfill_progress.
+type Progress struct {
+    Name        string `gorm:"primaryKey"`
+    Table       string `gorm:"not null"`
+    LastID      uint64 `gorm:"not null"`
+    Processed   int64  `gorm:"not null"`
+    Done        bool   `gorm:"not null"`
+    StartedAt   time.Time
+    UpdatedAt   time.Time
+    CompletedAt *time.Time
+}
+
+func (Progress) TableName() string {
+    return "backfill_progress"
+}
+
+// BatchFunc transforms the rows of a batch, given their primary keys in
+// ascending order. It runs in the transaction that records the batch's
+// progress and must only write through tx.
+type BatchFunc func(tx *gorm.DB, ids []uint64) error
+
+type job struct {
+    name  string
+    table string
+    batch BatchFunc
+}
+
+// Runner runs registered jobs one after another.
+type Runner struct {
+    db        *gorm.DB
+    batchSize int
+    pause     time.Duration
+
+    mu   sync.Mutex
+    jobs []job
+}
+
+// NewRunner returns a Runner that processes batchSize rows per transaction
+// and sleeps for pause between batches to leave room for live traffic.
+func NewRunner(db *gorm.DB, batchSize int, pause time.Duration) *Runner {
+    return &Runner{db: db, batchSize: batchSize, pause: pause}
+}
+
+// RegisterBackfill adds a job that applies batch to every row of table. name
+// identifies its progress, so it must not change once the job has started.
+func (r *Runner) RegisterBackfill(name, table string, batch BatchFunc) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.jobs = append(r.jobs, job{name: name, table: table, batch: batch})
+}
+
+// Start runs every job in the background until it is done or ctx ends. A
+// failed job is retried after a minute.
+func (r *Runner) Start(ctx context.Context) {
+    go func() {
+        for {
+            err := r.Run(ctx)
+            if err == nil || ctx.Err() != nil {
+                return
+            }
+            log.Printf("Backfill failed, retrying in a minute: %v", err)
+            select {
+            case <-time.After(time.Minute):
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+}
+
+// Run runs every job to completion in registration order.
+func (r *Runner) Run(ctx context.Context) error {
+    r.mu.Lock()
+    jobs := append([]job(nil), r.jobs...)
+    r.mu.Unlock()
+    for _, j := range jobs {
+        if err := r.run(ctx, j); err != nil {
+            return fmt.Errorf("backfill %s: %w", j.name, err)
+        }
+    }
+    return nil
+}
+
+func (r *Runner) run(ctx context.Context, j job) error {
+    err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+        Create(&Progress{Name: j.name, Table: j.table, StartedAt: time.Now()}).Error
+    if err != nil {
+        return err
+    }
+    for {
+        finished, err := r.step(ctx, j)
+        if err != nil || finished {
+            return err
+        }
+        select {
+        case <-time.After(r.pause):
+        case <-ctx.Done():
+            return ctx.Err()
+        }
+    }
+}
+
+// step processes one batch and reports whether the job has finished.
+func (r *Runner) step(ctx context.Context, j job) (bool, error) {
+    var finished bool
+    var processed int
+    var last uint64
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var progress Progress
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&progress, "name = ?", j.name).Error; err != nil {
+            return err
+        }
+        last = progress.LastID
+        if progress.Done {
+            finished = true
+            return nil
+        }
+
+        var ids []uint64
+        err := tx.Table(j.table).Where("id > ?", progress.LastID).Order("id").Limit(r.batchSize).Pluck("id", &ids).Error
+        if err != nil {
+            return err
+        }
+        now := time.Now()
+        if len(ids) == 0 {
+            finished = true
+            return tx.Model(&progress).Updates(map[string]interface{}{"done": true, "completed_at": now}).Error
+        }
+        if err := j.batch(tx, ids); err != nil {
+            return err
+        }
+        processed, last = len(ids), ids[len(ids)-1]
+        return tx.Model(&progress).Updates(map[string]interface{}{
+            "last_id":   last,
+            "processed": gorm.Expr("processed + ?", len(ids)),
+        }).Error
+    })
+    if err != nil {
+        return false, err
+    }
+    // Counted only once committed, so retried batches are not counted twice.
+    rowsProcessed.WithLabelValues(j.name).Add(float64(processed))
+    lastID.WithLabelValues(j.name).Set(float64(last))
+    if finished {
+        done.WithLabelValues(j.name).Set(1)
+    }
+    return finished, nil
+}
+
+// List returns the progress of every registered job, including jobs that
+// have not started yet, sorted by name.
+func (r *Runner) List(ctx context.Context) ([]Progress, error) {
+    var stored []Progress
+    if err := r.db.WithContext(ctx).Find(&stored).Error; err != nil {
+        return nil, err
+    }
+    byName := make(map[string]Progress, len(stored))
+    for _, p := range stored {
+        byName[p.Name] = p
+    }
+    r.mu.Lock()
+    for _, j := range r.jobs {
+        if _, ok := byName[j.name]; !ok {
+            byName[j.name] = Progress{Name: j.name, Table: j.table}
+        }
+    }
+    r.mu.Unlock()
+
+    list := make([]Progress, 0, len(byName))
+    for _, p := range byName {
+        list = append(list, p)
+    }
+    sort.Slice(list, func(i, k int) bool { return list[i].Name < list[k].Name })
+    return list, nil
+}
@@ -0,0 +1,126 @@
+package backfill
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/gorm"
+
+    "shared/testdb"
+)
+
+func progressRow(lastID uint64, done bool) *sqlmock.Rows {
+    return sqlmock.NewRows([]string{"name", "table", "last_id", "processed", "done"}).
+        AddRow("items.flag", "items", lastID, lastID, done)
+}
+
+func idRows(ids ...uint64) *sqlmock.Rows {
+    rows := sqlmock.NewRows([]string{"id"})
+    for _, id := range ids {
+        rows.AddRow(id)
+    }
+    return rows
+}
+
+// expectBatch expects one batch that finds ids after lastID.
+func expectBatch(mock sqlmock.Sqlmock, lastID uint64, ids ...uint64) {
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "backfill_progress" WHERE name = \$1 .* FOR UPDATE`).
+        WithArgs("items.flag").
+        WillReturnRows(progressRow(lastID, false))
+    mock.ExpectQuery(`SELECT "id" FROM "items" WHERE id > \$1 ORDER BY id LIMIT 2`).
+        WithArgs(lastID).
+        WillReturnRows(idRows(ids...))
+}
+
+// expectStart expects the job's progress row to be created if missing.
+func expectStart(mock sqlmock.Sqlmock) {
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO "backfill_progress" .* ON CONFLICT DO NOTHING`).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectCommit()
+}
+
+// TestBackfillResumesAfterInterruption interrupts a job mid-batch, as a
+// crash would, and checks that the restarted job resumes from the last
+// committed batch: every row is processed, and none of the committed rows
+// is processed again.
+func TestBackfillResumesAfterInterruption(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    var committed []uint64
+    interrupted := errors.New("interrupted")
+    fail := true
+    batch := func(tx *gorm.DB, ids []uint64) error {
+        if fail && ids[0] == 3 {
+            return interrupted
+        }
+        committed = append(committed, ids...)
+        return nil
+    }
+
+    expectStart(mock)
+    expectBatch(mock, 0, 1, 2)
+    mock.ExpectExec(`UPDATE "backfill_progress" SET "last_id"=\$1,"processed"=processed \+ \$2`).
+        WithArgs(2, 2, sqlmock.AnyArg(), "items.flag").
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+    expectBatch(mock, 2, 3, 4)
+    mock.ExpectRollback()
+
+    r := NewRunner(db, 2, 0)
+    r.RegisterBackfill("items.flag", "items", batch)
+    if err := r.Run(context.Background()); !errors.Is(err, interrupted) {
+        t.Fatalf("Run = %v, want the batch error", err)
+    }
+
+    // The restarted job finds its progress row at id 2, where the rolled
+    // back batch left it.
+    fail = false
+    expectStart(mock)
+    for _, b := range []struct{ last, next uint64 }{{2, 4}, {4, 5}} {
+        var ids []uint64
+        for id := b.last + 1; id <= b.next; id++ {
+            ids = append(ids, id)
+        }
+        expectBatch(mock, b.last, ids...)
+        mock.ExpectExec(`UPDATE "backfill_progress" SET "last_id"=\$1`).
+            WithArgs(b.next, len(ids), sqlmock.AnyArg(), "items.flag").
+            WillReturnResult(sqlmock.NewResult(0, 1))
+        mock.ExpectCommit()
+    }
+    expectBatch(mock, 5)
+    mock.ExpectExec(`UPDATE "backfill_progress" SET "completed_at"=\$1,"done"=\$2`).
+        WithArgs(sqlmock.AnyArg(), true, sqlmock.AnyArg(), "items.flag").
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    r = NewRunner(db, 2, 0)
+    r.RegisterBackfill("items.flag", "items", batch)
+    if err := r.Run(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    if want := []uint64{1, 2, 3, 4, 5}; !reflect.DeepEqual(committed, want) {
+        t.Errorf("processed %v, want %v each once", committed, want)
+    }
+}
+
+func TestBackfillSkipsFinishedJobs(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    expectStart(mock)
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT \* FROM "backfill_progress"`).WillReturnRows(progressRow(5, true))
+    mock.ExpectCommit()
+
+    r := NewRunner(db, 2, time.Hour)
+    r.RegisterBackfill("items.flag", "items", func(tx *gorm.DB, ids []uint64) error {
+        t.Errorf("finished job processed %v", ids)
+        return nil
+    })
+    if err := r.Run(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+}
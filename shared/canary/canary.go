@@ -0,0 +1,69 @@
+// Package canary marks service instances as canaries in their Consul
+// registration, so that the gateway sends them a configured share of the
+// traffic.
+package canary
+
+import (
+    "log"
+    "os"
+    "runtime/debug"
+    "strconv"
+)
+
+// DefaultWeight is used when CANARY_WEIGHT is not set or is invalid.
+const DefaultWeight = 10
+
+// Config marks an instance as a canary. The gateway sends a canary Weight
+// requests for every 90 it sends each other instance, so one canary of
+// weight 10 next to one stable instance takes 10% of the traffic.
+type Config struct {
+    IsCanary bool
+    Weight   int
+}
+
+// FromEnv reads CANARY and CANARY_WEIGHT. Values that do not parse, and
+// weights outside 1-100, are logged and replaced by the defaults.
+func FromEnv() Config {
+    c := Config{Weight: DefaultWeight}
+    if value := os.Getenv("CANARY"); value != "" {
+        isCanary, err := strconv.ParseBool(value)
+        if err != nil {
+            log.Printf("Invalid CANARY=%q, using default false", value)
+        }
+        c.IsCanary = isCanary
+    }
+    if value := os.Getenv("CANARY_WEIGHT"); value != "" {
+        weight, err := strconv.Atoi(value)
+        if err != nil || weight < 1 || weight > 100 {
+            log.Printf("Invalid CANARY_WEIGHT=%q, using default %d", value, DefaultWeight)
+        } else {
+            c.Weight = weight
+        }
+    }
+    return c
+}
+
+// ConsulMeta is the service metadata the gateway's resolver weights
+// instances by. Stable instances carry none.
+func (c Config) ConsulMeta() map[string]string {
+    if !c.IsCanary {
+        return nil
+    }
+    return map[string]string{"canary": "true", "weight": strconv.Itoa(c.Weight)}
+}
+
+// Version is SERVICE_VERSION, or else the VCS revision the binary was built
+// from.
+func Version() string {
+    if version := os.Getenv("SERVICE_VERSION"); version != "" {
+        return version
+    }
+    if info, ok := debug.ReadBuildInfo(); ok {
+        for _, setting := range info.Settings {
+            if setting.Key == "vcs.revision" {
+                return setting.Value
+            }
+        }
+    }
+    return "unknown"
+}
@@ -0,0 +1,50 @@
+package canary
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestFromEnv(t *testing.T) {
+    tests := []struct {
+        canary, weight string
+        want           Config
+    }{
+        {"", "", Config{Weight: DefaultWeight}},
+        {"true", "", Config{IsCanary: true, Weight: DefaultWeight}},
+        {"true", "25", Config{IsCanary: true, Weight: 25}},
+        {"true", "100", Config{IsCanary: true, Weight: 100}},
+        {"true", "0", Config{IsCanary: true, Weight: DefaultWeight}},
+        {"true", "101", Config{IsCanary: true, Weight: DefaultWeight}},
+        {"true", "lots", Config{IsCanary: true, Weight: DefaultWeight}},
+        {"maybe", "25", Config{Weight: 25}},
+    }
+    for _, tt := range tests {
+        t.Setenv("CANARY", tt.canary)
+        t.Setenv("CANARY_WEIGHT", tt.weight)
+        if got := FromEnv(); got != tt.want {
+            t.Errorf("CANARY=%q CANARY_WEIGHT=%q: got %+v, want %+v", tt.canary, tt.weight, got, tt.want)
+        }
+    }
+}
+
+func TestConsulMeta(t *testing.T) {
+    if meta := (Config{Weight: 25}).ConsulMeta(); meta != nil {
+        t.Errorf("stable instance meta = %v, want none", meta)
+    }
+    want := map[string]string{"canary": "true", "weight": "25"}
+    if meta := (Config{IsCanary: true, Weight: 25}).ConsulMeta(); !reflect.DeepEqual(meta, want) {
+        t.Errorf("canary meta = %v, want %v", meta, want)
+    }
+}
+
+func TestVersion(t *testing.T) {
+    t.Setenv("SERVICE_VERSION", "1.4.2")
+    if got := Version(); got != "1.4.2" {
+        t.Errorf("Version() = %q, want SERVICE_VERSION", got)
+    }
+    t.Setenv("SERVICE_VERSION", "")
+    if got := Version(); got == "" {
+        t.Error("Version() without SERVICE_VERSION is empty")
+    }
+}
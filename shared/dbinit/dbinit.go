@@ -0,0 +1,51 @@
+// Package dbinit opens connection pools that run a list of SQL statements,
+// such as SET search_path or SET TIME ZONE, on every new connection.
+package dbinit
+
+import (
+    "context"
+    "database/sql"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Statements splits a semicolon-separated list of SQL statements, e.g.
+// "SET search_path TO products,public; SET TIME ZONE 'UTC'", dropping empty
+// ones. Statements are split on every semicolon, so none may contain one.
+func Statements(list string) []string {
+    var statements []string
+    for _, statement := range strings.Split(list, ";") {
+        if statement = strings.TrimSpace(statement); statement != "" {
+            statements = append(statements, statement)
+        }
+    }
+    return statements
+}
+
+// FromEnv returns the statements in DB_INIT_SQL.
+func FromEnv() []string {
+    return Statements(os.Getenv("DB_INIT_SQL"))
+}
+
+// Open opens a pool for dsn that runs statements on every connection it
+// opens, before the connection is used. A failing statement is logged and the
+// connection is used anyway, so a typo in DB_INIT_SQL cannot take the service
+// down.
+func Open(dsn string, statements []string) (*sql.DB, error) {
+    config, err := pgx.ParseConfig(dsn)
+    if err != nil {
+        return nil, err
+    }
+    return stdlib.OpenDB(*config, stdlib.OptionAfterConnect(func(ctx context.Context, conn *pgx.Conn) error {
+        for _, statement := range statements {
+            if _, err := conn.Exec(ctx, statement); err != nil {
+                log.Printf("Connection init statement %q failed: %v", statement, err)
+            }
+        }
+        return nil
+    })), nil
+}
@@ -0,0 +1,53 @@
+package dbinit
+
+import (
+    "os"
+    "reflect"
+    "testing"
+
+    "shared/testdb"
+)
+
+func TestStatements(t *testing.T) {
+    for list, want := range map[string][]string{
+        "":    nil,
+        " ; ": nil,
+        "SET search_path TO app; SET TIME ZONE 'UTC';": {"SET search_path TO app", "SET TIME ZONE 'UTC'"},
+    } {
+        if got := Statements(list); !reflect.DeepEqual(got, want) {
+            t.Errorf("Statements(%q) = %q, want %q", list, got, want)
+        }
+    }
+}
+
+func TestOpenRejectsBadDSNs(t *testing.T) {
+    if _, err := Open("port=not-a-number", nil); err == nil {
+        t.Error("Open with a bad port succeeded")
+    }
+}
+
+// TestOpenRunsStatementsOnEveryConnection checks that each pooled connection
+// has the settings, and that a failing statement does not stop the others.
+func TestOpenRunsStatementsOnEveryConnection(t *testing.T) {
+    testdb.Postgres(t)
+    pool, err := Open(os.Getenv("TEST_DATABASE_DSN"), []string{
+        "SET TIME ZONE 'Asia/Thimphu'",
+        "SET no_such_setting TO 1",
+        "SET application_name TO 'dbinit-test'",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer pool.Close()
+    pool.SetMaxIdleConns(0)
+
+    for i := 0; i < 3; i++ {
+        var zone, name string
+        if err := pool.QueryRow("SELECT current_setting('TimeZone'), current_setting('application_name')").Scan(&zone, &name); err != nil {
+            t.Fatal(err)
+        }
+        if zone != "Asia/Thimphu" || name != "dbinit-test" {
+            t.Errorf("connection %d has time zone %q and application %q", i, zone, name)
+        }
+    }
+}
@@ -0,0 +1,41 @@
+// Package debug reports the runtime state of a service instance, for
+// diagnosing incidents without restarting it.
+package debug
+
+import (
+    "database/sql"
+    "runtime"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// processStart is when the process started, for Info's uptime.
+var processStart = time.Now()
+
+// Info is the runtime state of this instance.
+type Info struct {
+    Goroutines    int
+    MemoryAllocMB float64
+    // DBPool is the state of the database connection pool.
+    DBPool    sql.DBStats
+    Uptime    time.Duration
+    GoVersion string
+}
+
+// Read reads the runtime state of this instance and of db's connection pool.
+func Read(db *gorm.DB) (*Info, error) {
+    sqlDB, err := db.DB()
+    if err != nil {
+        return nil, err
+    }
+    var mem runtime.MemStats
+    runtime.ReadMemStats(&mem)
+    return &Info{
+        Goroutines:    runtime.NumGoroutine(),
+        MemoryAllocMB: float64(mem.Alloc) / (1 << 20),
+        DBPool:        sqlDB.Stats(),
+        Uptime:        time.Since(processStart),
+        GoVersion:     runtime.Version(),
+    }, nil
+}
@@ -0,0 +1,41 @@
+package debug
+
+import (
+    "runtime"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+func TestRead(t *testing.T) {
+    conn, _, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+    conn.SetMaxOpenConns(7)
+    db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    info, err := Read(db)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if info.Goroutines < 1 {
+        t.Errorf("goroutine count = %d, want at least 1", info.Goroutines)
+    }
+    if info.MemoryAllocMB <= 0 || info.Uptime <= 0 {
+        t.Errorf("heap = %vMB, uptime = %v, want both positive", info.MemoryAllocMB, info.Uptime)
+    }
+    if info.DBPool.MaxOpenConnections != 7 {
+        t.Errorf("pool allows %d connections, want 7", info.DBPool.MaxOpenConnections)
+    }
+    if info.GoVersion != runtime.Version() {
+        t.Errorf("Go version = %q, want %q", info.GoVersion, runtime.Version())
+    }
+}
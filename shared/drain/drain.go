@@ -0,0 +1,82 @@
+// Package drain takes an instance out of rotation ahead of a deploy without
+// stopping the process.
+package drain
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/health"
+)
+
+// DefaultTimeout is used when a drain request does not set a timeout.
+const DefaultTimeout = 30 * time.Second
+
+const pollInterval = 100 * time.Millisecond
+
+// Result describes how a drain ended.
+type Result struct {
+    // Idle is true if no requests were left in flight.
+    Idle     bool
+    InFlight int
+    Waited   time.Duration
+}
+
+// Drain marks every service of healthServer NOT_SERVING, so Consul stops
+// routing to this instance, then waits for inFlight to report no requests or
+// the timeout to pass. Draining cannot be undone; the instance is expected
+// to be stopped. name identifies the instance in log messages. Drain returns
+// ctx's error if ctx is done first.
+func Drain(ctx context.Context, name string, healthServer *health.Server, inFlight func() int, timeout time.Duration) (Result, error) {
+    log.Printf("Draining %s: marking NOT_SERVING and waiting up to %v for in-flight requests", name, timeout)
+    healthServer.Shutdown()
+
+    start := time.Now()
+    deadline := time.NewTimer(timeout)
+    defer deadline.Stop()
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+
+    for {
+        n := inFlight()
+        if n <= 0 {
+            log.Printf("Drained %s after %v", name, time.Since(start))
+            return Result{Idle: true, Waited: time.Since(start)}, nil
+        }
+
+        select {
+        case <-ticker.C:
+        case <-deadline.C:
+            log.Printf("Drain of %s timed out with %d requests in flight", name, n)
+            return Result{InFlight: n, Waited: time.Since(start)}, nil
+        case <-ctx.Done():
+            return Result{}, ctx.Err()
+        }
+    }
+}
+
+// Stop stops s once the requests in flight finish, or forcibly after
+// timeout. Streams such as watches never finish by themselves, so without the
+// timeout a single watcher would hold up the shutdown. It reports whether s
+// stopped gracefully. name identifies the instance in log messages.
+func Stop(s *grpc.Server, name string, inFlight func() int, timeout time.Duration) bool {
+    stopped := make(chan struct{})
+    go func() {
+        s.GracefulStop()
+        close(stopped)
+    }()
+
+    deadline := time.NewTimer(timeout)
+    defer deadline.Stop()
+    select {
+    case <-stopped:
+        log.Printf("Stopped %s gracefully", name)
+        return true
+    case <-deadline.C:
+        log.Printf("Forcing %s to stop after %v with %d requests still in flight", name, timeout, inFlight())
+        s.Stop()
+        return false
+    }
+}
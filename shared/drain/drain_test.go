@@ -0,0 +1,116 @@
+package drain
+
+import (
+    "context"
+    "errors"
+    "net"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/test/bufconn"
+)
+
+func TestDrainWaitsForInFlightRequests(t *testing.T) {
+    h := health.NewServer()
+    h.SetServingStatus("catalog.CatalogService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+    // One request is in flight when the drain starts and finishes 300ms later.
+    var inFlight atomic.Int64
+    inFlight.Store(1)
+    go func() {
+        time.Sleep(300 * time.Millisecond)
+        inFlight.Store(0)
+    }()
+
+    res, err := Drain(context.Background(), "catalog-service", h, func() int { return int(inFlight.Load()) }, 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !res.Idle || res.InFlight != 0 {
+        t.Errorf("idle = %v with %d in flight, want idle", res.Idle, res.InFlight)
+    }
+    if res.Waited < 300*time.Millisecond {
+        t.Errorf("waited %v, want at least the 300ms the request ran for", res.Waited)
+    }
+    check, err := h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "catalog.CatalogService"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if check.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+        t.Errorf("status after drain = %v, want NOT_SERVING", check.Status)
+    }
+}
+
+func TestDrainTimesOut(t *testing.T) {
+    res, err := Drain(context.Background(), "catalog-service", health.NewServer(), func() int { return 2 }, 200*time.Millisecond)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if res.Idle || res.InFlight != 2 || res.Waited < 200*time.Millisecond {
+        t.Errorf("got %+v, want 2 requests still running after 200ms", res)
+    }
+}
+
+func TestDrainStopsWithContext(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+    defer cancel()
+    if _, err := Drain(ctx, "catalog-service", health.NewServer(), func() int { return 1 }, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+        t.Errorf("Drain = %v, want the context's error", err)
+    }
+}
+
+// serveHealth serves a health service over an in-memory listener and returns
+// the server and a client of it.
+func serveHealth(t *testing.T) (*grpc.Server, grpc_health_v1.HealthClient) {
+    t.Helper()
+    lis := bufconn.Listen(1 << 20)
+    s := grpc.NewServer()
+    grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+    go s.Serve(lis)
+    conn, err := grpc.Dial("bufnet",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return s, grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestStopIdleServerGracefully(t *testing.T) {
+    s, client := serveHealth(t)
+    if _, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+        t.Fatal(err)
+    }
+    if !Stop(s, "catalog-service", func() int { return 0 }, 5*time.Second) {
+        t.Error("idle server was stopped forcibly")
+    }
+}
+
+func TestStopForcesOpenStreams(t *testing.T) {
+    s, client := serveHealth(t)
+    // A health watch, like a product watch, stays open until it is cancelled.
+    watch, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := watch.Recv(); err != nil {
+        t.Fatal(err)
+    }
+
+    start := time.Now()
+    if Stop(s, "catalog-service", func() int { return 1 }, 200*time.Millisecond) {
+        t.Error("server with an open stream stopped gracefully")
+    }
+    if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > 2*time.Second {
+        t.Errorf("Stop took %v, want about the 200ms timeout", elapsed)
+    }
+    if _, err := watch.Recv(); err == nil {
+        t.Error("stream is still open after Stop")
+    }
+}
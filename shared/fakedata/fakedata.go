@@ -0,0 +1,186 @@
+// Package fakedata generates realistic-looking test data that is the same on
+// every run of a test, so failures reproduce, but differs between tests.
+//
+//	gen := fakedata.NewGenerator(t)
+//	req := fakedata.Product[*pb.CreateProductRequest](gen, fakedata.WithPrice(0.99))
+//
+// Every value is derived from the test's name and the number of values
+// generated before it. Product and User fill the request type of the
+// caller's own generated proto package, so every module can use them.
+package fakedata
+
+import (
+    "fmt"
+    "hash/fnv"
+    "math"
+    "math/rand/v2"
+    "strings"
+    "sync/atomic"
+    "testing"
+
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+    adjectives = []string{"Classic", "Compact", "Deluxe", "Ergonomic", "Handmade", "Lightweight", "Organic", "Portable", "Rustic", "Vintage", "Wireless", "Sleek"}
+    materials  = []string{"Bamboo", "Ceramic", "Cotton", "Granite", "Leather", "Linen", "Maple", "Steel", "Walnut", "Wool"}
+    nouns      = []string{"Backpack", "Blanket", "Bottle", "Chair", "Desk Lamp", "Headphones", "Kettle", "Mug", "Notebook", "Speaker", "Teapot", "Wallet"}
+    firstNames = []string{"Aisha", "Bjorn", "Carmen", "Dechen", "Elena", "Farid", "Grace", "Hiro", "Ingrid", "Jamal", "Karma", "Lucia", "Mateo", "Nima", "Olivia", "Pema", "Quinn", "Ravi", "Sonam", "Tenzin"}
+    lastNames  = []string{"Anderson", "Bhutia", "Chen", "Dorji", "Garcia", "Gurung", "Kim", "Lama", "Mensah", "Novak", "Okafor", "Patel", "Rossi", "Sherpa", "Silva", "Tamang", "Wangchuk", "Yilmaz"}
+    domains    = []string{"example.com", "example.net", "example.org"}
+)
+
+// phoneFormats are phone number layouts by ISO 3166-1 alpha-2 country code.
+// Each # is replaced with a digit.
+var phoneFormats = map[string]string{
+    "US": "+1 2##-555-####",
+    "CA": "+1 2##-555-####",
+    "GB": "+44 7700 9#####",
+    "DE": "+49 151 ########",
+    "FR": "+33 6 ## ## ## ##",
+    "IN": "+91 9#### #####",
+    "BT": "+975 17 ## ## ##",
+    "NP": "+977 98########",
+    "AU": "+61 4## ### ###",
+}
+
+// Generator produces test data seeded from a test's name. It is safe for
+// concurrent use: each call draws from a random source of its own.
+type Generator struct {
+    seed  uint64
+    calls atomic.Uint64
+}
+
+// NewGenerator returns a generator seeded from the FNV-1a hash of the
+// test's name.
+func NewGenerator(t testing.TB) *Generator {
+    h := fnv.New64a()
+    h.Write([]byte(t.Name()))
+    return &Generator{seed: h.Sum64()}
+}
+
+// rand returns the random source for the next call. Concurrent callers get
+// distinct sources, and a test that generates values in a fixed order gets
+// the same values on every run.
+func (g *Generator) rand() *rand.Rand {
+    return rand.New(rand.NewPCG(g.seed, g.calls.Add(1)))
+}
+
+func pick(r *rand.Rand, values []string) string {
+    return values[r.IntN(len(values))]
+}
+
+// ProductName returns a name such as "Rustic Walnut Desk Lamp".
+func (g *Generator) ProductName() string {
+    r := g.rand()
+    return pick(r, adjectives) + " " + pick(r, materials) + " " + pick(r, nouns)
+}
+
+// Price returns a price in [min, max], rounded to whole cents.
+func (g *Generator) Price(min, max float64) float64 {
+    r := g.rand()
+    price := math.Round((min+r.Float64()*(max-min))*100) / 100
+    return math.Min(math.Max(price, min), max)
+}
+
+// UserName returns a full name such as "Pema Sherpa".
+func (g *Generator) UserName() string {
+    r := g.rand()
+    return pick(r, firstNames) + " " + pick(r, lastNames)
+}
+
+// Email returns an address at a reserved example domain, with a number so
+// that the addresses of one test rarely collide.
+func (g *Generator) Email() string {
+    r := g.rand()
+    return fmt.Sprintf("%s.%s%d@%s", strings.ToLower(pick(r, firstNames)), strings.ToLower(pick(r, lastNames)), r.IntN(1000), pick(r, domains))
+}
+
+// PhoneNumber returns a number laid out the way countryCode, an ISO 3166-1
+// alpha-2 code, writes them. Unknown countries get a US number.
+func (g *Generator) PhoneNumber(countryCode string) string {
+    format, ok := phoneFormats[strings.ToUpper(countryCode)]
+    if !ok {
+        format = phoneFormats["US"]
+    }
+    r := g.rand()
+    var b strings.Builder
+    for _, c := range format {
+        if c == '#' {
+            b.WriteByte(byte('0' + r.IntN(10)))
+        } else {
+            b.WriteRune(c)
+        }
+    }
+    return b.String()
+}
+
+// setField sets the field called name, panicking if the message has none:
+// that is a mistake in the test, not in the code under test.
+func setField(m protoreflect.Message, name string, value protoreflect.Value) {
+    field := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+    if field == nil {
+        panic(fmt.Sprintf("fakedata: %s has no field %q", m.Descriptor().FullName(), name))
+    }
+    m.Set(field, value)
+}
+
+// newMessage returns an empty message of type M, which is a pointer to a
+// generated message struct.
+func newMessage[M proto.Message]() protoreflect.Message {
+    var zero M
+    return zero.ProtoReflect().New()
+}
+
+// ProductOption overrides a field of a generated CreateProductRequest.
+type ProductOption func(protoreflect.Message)
+
+func WithProductName(name string) ProductOption {
+    return func(m protoreflect.Message) { setField(m, "name", protoreflect.ValueOfString(name)) }
+}
+
+func WithPrice(price float64) ProductOption {
+    return func(m protoreflect.Message) { setField(m, "price", protoreflect.ValueOfFloat64(price)) }
+}
+
+func WithDescription(description string) ProductOption {
+    return func(m protoreflect.Message) { setField(m, "description", protoreflect.ValueOfString(description)) }
+}
+
+// Product returns a valid CreateProductRequest of the caller's proto
+// package, with the options applied in order.
+func Product[M proto.Message](g *Generator, overrides ...ProductOption) M {
+    m := newMessage[M]()
+    name := g.ProductName()
+    setField(m, "name", protoreflect.ValueOfString(name))
+    setField(m, "price", protoreflect.ValueOfFloat64(g.Price(1, 500)))
+    setField(m, "description", protoreflect.ValueOfString("A "+strings.ToLower(name)+" for testing."))
+    for _, override := range overrides {
+        override(m)
+    }
+    return m.Interface().(M)
+}
+
+// UserOption overrides a field of a generated CreateUserRequest.
+type UserOption func(protoreflect.Message)
+
+func WithUserName(name string) UserOption {
+    return func(m protoreflect.Message) { setField(m, "name", protoreflect.ValueOfString(name)) }
+}
+
+func WithEmail(email string) UserOption {
+    return func(m protoreflect.Message) { setField(m, "email", protoreflect.ValueOfString(email)) }
+}
+
+// User returns a valid CreateUserRequest of the caller's proto package, with
+// the options applied in order.
+func User[M proto.Message](g *Generator, overrides ...UserOption) M {
+    m := newMessage[M]()
+    setField(m, "name", protoreflect.ValueOfString(g.UserName()))
+    setField(m, "email", protoreflect.ValueOfString(g.Email()))
+    for _, override := range overrides {
+        override(m)
+    }
+    return m.Interface().(M)
+}
@@ -0,0 +1,99 @@
+package fakedata
+
+import (
+    "math"
+    "regexp"
+    "sort"
+    "sync"
+    "testing"
+)
+
+func values(g *Generator) []string {
+    return []string{g.ProductName(), g.UserName(), g.Email(), g.PhoneNumber("BT")}
+}
+
+func TestGeneratorIsReproducible(t *testing.T) {
+    first := values(NewGenerator(t))
+    second := values(NewGenerator(t))
+    for i := range first {
+        if first[i] != second[i] {
+            t.Errorf("value %d = %q, then %q with the same seed", i, first[i], second[i])
+        }
+    }
+}
+
+func TestGeneratorDiffersBetweenTests(t *testing.T) {
+    var a, b []string
+    t.Run("a", func(t *testing.T) { a = values(NewGenerator(t)) })
+    t.Run("b", func(t *testing.T) { b = values(NewGenerator(t)) })
+    same := 0
+    for i := range a {
+        if a[i] == b[i] {
+            same++
+        }
+    }
+    if same == len(a) {
+        t.Errorf("tests with different names generated the same values %q", a)
+    }
+}
+
+func TestGeneratorConcurrentUse(t *testing.T) {
+    const n = 200
+    want := make([]string, n)
+    sequential := NewGenerator(t)
+    for i := range want {
+        want[i] = sequential.ProductName()
+    }
+
+    concurrent := NewGenerator(t)
+    got := make([]string, n)
+    var wg sync.WaitGroup
+    for i := range got {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            got[i] = concurrent.ProductName()
+        }(i)
+    }
+    wg.Wait()
+
+    // Calls are numbered, so concurrent callers see the same values in
+    // some order.
+    sort.Strings(want)
+    sort.Strings(got)
+    for i := range want {
+        if got[i] != want[i] {
+            t.Fatalf("concurrent names differ from sequential ones at %d: %q != %q", i, got[i], want[i])
+        }
+    }
+}
+
+func TestPrice(t *testing.T) {
+    g := NewGenerator(t)
+    for i := 0; i < 1000; i++ {
+        price := g.Price(0.5, 2)
+        if price < 0.5 || price > 2 {
+            t.Fatalf("Price(0.5, 2) = %v, out of range", price)
+        }
+        if cents := price * 100; math.Abs(cents-math.Round(cents)) > 1e-9 {
+            t.Fatalf("Price(0.5, 2) = %v, not whole cents", price)
+        }
+    }
+}
+
+func TestPhoneNumber(t *testing.T) {
+    g := NewGenerator(t)
+    tests := []struct {
+        country string
+        pattern string
+    }{
+        {"BT", `^\+975 17 \d\d \d\d \d\d$`},
+        {"gb", `^\+44 7700 9\d{5}$`},
+        {"ZZ", `^\+1 2\d\d-555-\d{4}$`},
+    }
+    for _, tt := range tests {
+        if got := g.PhoneNumber(tt.country); !regexp.MustCompile(tt.pattern).MatchString(got) {
+            t.Errorf("PhoneNumber(%q) = %q, want a match for %s", tt.country, got, tt.pattern)
+        }
+    }
+}
@@ -0,0 +1,352 @@
+// Package grpc keeps a bounded pool of gRPC connections per upstream target,
+// so a busy service's calls are spread over several HTTP/2 connections rather
+// than queued on one.
+//
+//	pool := sharedgrpc.New(4, 5*time.Minute, grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	defer pool.Close()
+//
+//	conn, err := pool.Acquire(ctx, "products-service:50052")
+//	if err != nil {
+//		return err
+//	}
+//	defer pool.Release(conn)
+//
+// Connections are multiplexed, so callers share them: Acquire hands them out
+// round-robin, each to up to DefaultMaxCallersPerConn callers at once. It only
+// waits when every connection to a target is that busy and the target already
+// has its maximum of connections.
+package grpc
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/connectivity"
+)
+
+// DefaultMaxCallersPerConn is how many callers may hold one connection at
+// once. It matches the concurrent stream limit HTTP/2 servers commonly
+// advertise, past which calls would queue inside the connection anyway.
+const DefaultMaxCallersPerConn = 100
+
+// ErrClosed is returned by Acquire once the pool has been closed.
+var ErrClosed = errors.New("grpc: connection pool is closed")
+
+// PoolStats counts the pool's connections and callers across all targets.
+type PoolStats struct {
+    // Active is the number of connections held by at least one caller.
+    Active int
+    // Idle is the number of open connections no caller holds.
+    Idle int
+    // Waiting is the number of Acquire calls blocked on a connection.
+    Waiting int
+}
+
+// ConnectionPool holds up to maxConns connections to each target. Idle
+// connections are closed after idleTimeout.
+type ConnectionPool struct {
+    maxConns          int
+    maxCallersPerConn int
+    idleTimeout       time.Duration
+    dialOpts          []grpc.DialOption
+
+    mu      sync.Mutex
+    targets map[string]*targetPool
+    // owners maps each connection the pool opened to its state.
+    owners map[*grpc.ClientConn]*pooledConn
+    closed bool
+    done   chan struct{}
+}
+
+// targetPool is the state of one target's connections. Connections being
+// dialed are counted, so a target never has more than maxConns connections
+// open or opening.
+type targetPool struct {
+    conns   []*pooledConn
+    dialing int
+    // next is where the round-robin over conns resumes.
+    next int
+    // waiters are closed to wake the blocked callers when a connection
+    // frees up, and each tries again.
+    waiters []chan struct{}
+}
+
+type pooledConn struct {
+    conn    *grpc.ClientConn
+    target  string
+    callers int
+    // idleSince is when callers last dropped to 0.
+    idleSince time.Time
+}
+
+// New returns a pool of up to maxConns connections per target, dialed with
+// opts. A maxConns below 1 is treated as 1, and an idleTimeout of 0 keeps
+// idle connections open until Close.
+func New(maxConns int, idleTimeout time.Duration, opts ...grpc.DialOption) *ConnectionPool {
+    if maxConns < 1 {
+        maxConns = 1
+    }
+    p := &ConnectionPool{
+        maxConns:          maxConns,
+        maxCallersPerConn: DefaultMaxCallersPerConn,
+        idleTimeout:       idleTimeout,
+        dialOpts:          opts,
+        targets:           make(map[string]*targetPool),
+        owners:            make(map[*grpc.ClientConn]*pooledConn),
+        done:              make(chan struct{}),
+    }
+    if idleTimeout > 0 {
+        go p.closeIdle()
+    }
+    return p
+}
+
+// Acquire returns a connection to target, which the caller must pass to
+// Release when its calls are finished. An idle connection is reused first;
+// while the target has fewer than maxConns connections a new one is dialed
+// rather than doubling up on a busy one; after that the connections are
+// shared round-robin. Acquire waits until ctx is done only when every
+// connection already has its maximum of callers.
+func (p *ConnectionPool) Acquire(ctx context.Context, target string) (*grpc.ClientConn, error) {
+    for {
+        p.mu.Lock()
+        if p.closed {
+            p.mu.Unlock()
+            return nil, ErrClosed
+        }
+        tp := p.target(target)
+        if pc := p.pick(tp); pc != nil {
+            pc.callers++
+            p.mu.Unlock()
+            return pc.conn, nil
+        }
+        if len(tp.conns)+tp.dialing < p.maxConns {
+            tp.dialing++
+            p.mu.Unlock()
+            return p.dial(target)
+        }
+
+        ch := make(chan struct{})
+        tp.waiters = append(tp.waiters, ch)
+        p.mu.Unlock()
+
+        select {
+        case <-ch:
+        case <-ctx.Done():
+            p.mu.Lock()
+            for i, waiter := range tp.waiters {
+                if waiter == ch {
+                    tp.waiters = append(tp.waiters[:i], tp.waiters[i+1:]...)
+                    break
+                }
+            }
+            p.forget(target, tp)
+            p.mu.Unlock()
+            return nil, ctx.Err()
+        }
+    }
+}
+
+// pick returns the connection the next caller of tp should share, or nil if
+// a new one should be dialed or the caller must wait. p.mu must be held.
+func (p *ConnectionPool) pick(tp *targetPool) *pooledConn {
+    for i := 0; i < len(tp.conns); {
+        pc := tp.conns[i]
+        if pc.conn.GetState() == connectivity.Shutdown && pc.callers == 0 {
+            // Closed by its last caller; its slot is free.
+            p.remove(tp, pc)
+            continue
+        }
+        if pc.callers == 0 {
+            return pc
+        }
+        i++
+    }
+    if len(tp.conns)+tp.dialing < p.maxConns {
+        return nil
+    }
+    for i := range tp.conns {
+        pc := tp.conns[(tp.next+i)%len(tp.conns)]
+        if pc.callers < p.maxCallersPerConn && pc.conn.GetState() != connectivity.Shutdown {
+            tp.next = (tp.next + i + 1) % len(tp.conns)
+            return pc
+        }
+    }
+    return nil
+}
+
+// dial opens a connection already counted in its target's dialing.
+func (p *ConnectionPool) dial(target string) (*grpc.ClientConn, error) {
+    conn, err := grpc.Dial(target, p.dialOpts...)
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    tp := p.target(target)
+    tp.dialing--
+    if err != nil {
+        p.wake(tp)
+        p.forget(target, tp)
+        return nil, err
+    }
+    pc := &pooledConn{conn: conn, target: target, callers: 1}
+    tp.conns = append(tp.conns, pc)
+    p.owners[conn] = pc
+    // The new connection has room for more callers than this one.
+    p.wake(tp)
+    return conn, nil
+}
+
+// Release gives up the caller's hold on a connection obtained from Acquire.
+// A connection that has been closed is dropped once no caller holds it, and
+// one the pool did not open is closed.
+func (p *ConnectionPool) Release(conn *grpc.ClientConn) {
+    p.mu.Lock()
+    pc, ok := p.owners[conn]
+    if !ok {
+        p.mu.Unlock()
+        conn.Close()
+        return
+    }
+    pc.callers--
+    tp := p.target(pc.target)
+    if pc.callers > 0 {
+        p.wake(tp)
+        p.mu.Unlock()
+        return
+    }
+    pc.idleSince = time.Now()
+    if p.closed || conn.GetState() == connectivity.Shutdown {
+        p.remove(tp, pc)
+        p.wake(tp)
+        p.forget(pc.target, tp)
+        p.mu.Unlock()
+        conn.Close()
+        return
+    }
+    p.wake(tp)
+    p.mu.Unlock()
+}
+
+// Stats returns the pool's current connection counts, e.g. to export as
+// Prometheus gauges.
+func (p *ConnectionPool) Stats() PoolStats {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    var stats PoolStats
+    for _, tp := range p.targets {
+        for _, pc := range tp.conns {
+            if pc.callers > 0 {
+                stats.Active++
+            } else {
+                stats.Idle++
+            }
+        }
+        stats.Waiting += len(tp.waiters)
+    }
+    return stats
+}
+
+// Close closes the idle connections and makes further calls to Acquire
+// fail. Connections still held are closed when their last caller releases
+// them, and callers waiting in Acquire get ErrClosed.
+func (p *ConnectionPool) Close() error {
+    p.mu.Lock()
+    if p.closed {
+        p.mu.Unlock()
+        return nil
+    }
+    p.closed = true
+    close(p.done)
+    var conns []*grpc.ClientConn
+    for target, tp := range p.targets {
+        for _, pc := range append([]*pooledConn(nil), tp.conns...) {
+            if pc.callers == 0 {
+                conns = append(conns, pc.conn)
+                p.remove(tp, pc)
+            }
+        }
+        p.wake(tp)
+        p.forget(target, tp)
+    }
+    p.mu.Unlock()
+
+    var err error
+    for _, conn := range conns {
+        if closeErr := conn.Close(); closeErr != nil && err == nil {
+            err = closeErr
+        }
+    }
+    return err
+}
+
+// closeIdle closes connections that have been idle for longer than
+// idleTimeout, checking at a fraction of it.
+func (p *ConnectionPool) closeIdle() {
+    ticker := time.NewTicker(p.idleTimeout / 4)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-p.done:
+            return
+        case now := <-ticker.C:
+            var expired []*grpc.ClientConn
+            p.mu.Lock()
+            for target, tp := range p.targets {
+                for _, pc := range append([]*pooledConn(nil), tp.conns...) {
+                    if pc.callers == 0 && now.Sub(pc.idleSince) > p.idleTimeout {
+                        expired = append(expired, pc.conn)
+                        p.remove(tp, pc)
+                    }
+                }
+                p.forget(target, tp)
+            }
+            p.mu.Unlock()
+            for _, conn := range expired {
+                conn.Close()
+            }
+        }
+    }
+}
+
+// target returns the state of target's connections, creating it if needed.
+// p.mu must be held.
+func (p *ConnectionPool) target(target string) *targetPool {
+    tp, ok := p.targets[target]
+    if !ok {
+        tp = &targetPool{}
+        p.targets[target] = tp
+    }
+    return tp
+}
+
+// remove drops pc from the pool without closing it. p.mu must be held.
+func (p *ConnectionPool) remove(tp *targetPool, pc *pooledConn) {
+    for i, c := range tp.conns {
+        if c == pc {
+            tp.conns = append(tp.conns[:i], tp.conns[i+1:]...)
+            break
+        }
+    }
+    if tp.next >= len(tp.conns) {
+        tp.next = 0
+    }
+    delete(p.owners, pc.conn)
+}
+
+// wake wakes every caller waiting on tp to try again. p.mu must be held.
+func (p *ConnectionPool) wake(tp *targetPool) {
+    for _, ch := range tp.waiters {
+        close(ch)
+    }
+    tp.waiters = nil
+}
+
+// forget drops the state of a target with no connections or waiters, so
+// targets that are no longer used do not accumulate. p.mu must be held.
+func (p *ConnectionPool) forget(target string, tp *targetPool) {
+    if len(tp.conns) == 0 && tp.dialing == 0 && len(tp.waiters) == 0 && p.targets[target] == tp {
+        delete(p.targets, target)
+    }
+}
@@ -0,0 +1,304 @@
+package grpc
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/connectivity"
+    "google.golang.org/grpc/credentials/insecure"
+)
+
+// Connections are dialed lazily, so the pool can be tested without a server
+// behind its targets.
+const target = "passthrough:///users-service:50051"
+
+// newPool returns a pool that shares each connection among at most
+// maxCallersPerConn callers, so tests reach the limit quickly.
+func newPool(t *testing.T, maxConns, maxCallersPerConn int, idleTimeout time.Duration) *ConnectionPool {
+    t.Helper()
+    p := New(maxConns, idleTimeout, grpc.WithTransportCredentials(insecure.NewCredentials()))
+    p.maxCallersPerConn = maxCallersPerConn
+    t.Cleanup(func() { p.Close() })
+    return p
+}
+
+func acquire(t *testing.T, p *ConnectionPool, target string) *grpc.ClientConn {
+    t.Helper()
+    conn, err := p.Acquire(context.Background(), target)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return conn
+}
+
+// waitForWaiters waits until n callers are blocked in Acquire.
+func waitForWaiters(t *testing.T, p *ConnectionPool, n int) {
+    t.Helper()
+    deadline := time.Now().Add(5 * time.Second)
+    for p.Stats().Waiting != n {
+        if time.Now().After(deadline) {
+            t.Fatalf("stats = %+v, want %d waiting", p.Stats(), n)
+        }
+        time.Sleep(time.Millisecond)
+    }
+}
+
+func TestReuse(t *testing.T) {
+    p := newPool(t, 2, 1, 0)
+    first := acquire(t, p, target)
+    p.Release(first)
+    if stats := p.Stats(); stats != (PoolStats{Idle: 1}) {
+        t.Errorf("after release: stats = %+v, want 1 idle", stats)
+    }
+    if conn := acquire(t, p, target); conn != first {
+        t.Error("Acquire dialed a new connection while one was idle")
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 1}) {
+        t.Errorf("after reacquire: stats = %+v, want 1 active", stats)
+    }
+}
+
+func TestTargetsHaveSeparateConnections(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    users := acquire(t, p, target)
+    // The users-service connection is in use, but that does not hold up
+    // products-service.
+    products := acquire(t, p, "passthrough:///products-service:50052")
+    if users == products {
+        t.Error("targets share a connection")
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 2}) {
+        t.Errorf("stats = %+v, want 2 active", stats)
+    }
+}
+
+func TestSpreadsCallersRoundRobin(t *testing.T) {
+    p := newPool(t, 2, 10, 0)
+    // A busy connection is not shared while another may still be dialed.
+    first, second := acquire(t, p, target), acquire(t, p, target)
+    if first == second {
+        t.Fatal("second caller shared a connection while the pool had room for another")
+    }
+    // At maxConns, further callers take turns on the connections.
+    for i, want := range []*grpc.ClientConn{first, second, first, second} {
+        if conn := acquire(t, p, target); conn != want {
+            t.Errorf("caller %d got connection %p, want %p", i+3, conn, want)
+        }
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 2}) {
+        t.Errorf("stats = %+v, want 2 active", stats)
+    }
+}
+
+func TestAcquireBlocksWhenConnectionsAreFull(t *testing.T) {
+    p := newPool(t, 1, 2, 0)
+    first, second := acquire(t, p, target), acquire(t, p, target)
+    if first != second {
+        t.Fatal("callers did not share the only connection")
+    }
+
+    got := make(chan *grpc.ClientConn)
+    go func() {
+        conn, err := p.Acquire(context.Background(), target)
+        if err != nil {
+            t.Error(err)
+        }
+        got <- conn
+    }()
+    waitForWaiters(t, p, 1)
+    if stats := p.Stats(); stats != (PoolStats{Active: 1, Waiting: 1}) {
+        t.Errorf("stats = %+v, want 1 active and 1 waiting", stats)
+    }
+
+    // The waiter takes the place the release frees up.
+    p.Release(second)
+    if conn := <-got; conn != first {
+        t.Error("waiter did not get the shared connection")
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 1}) {
+        t.Errorf("stats = %+v, want 1 active", stats)
+    }
+}
+
+func TestAcquireRespectsContext(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    acquire(t, p, target)
+
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    if _, err := p.Acquire(ctx, target); !errors.Is(err, context.DeadlineExceeded) {
+        t.Errorf("err = %v, want DeadlineExceeded", err)
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 1}) {
+        t.Errorf("stats = %+v, want the cancelled waiter gone", stats)
+    }
+}
+
+func TestNeverExceedsMaxConns(t *testing.T) {
+    const maxConns, maxCallersPerConn, callers = 3, 2, 50
+    p := newPool(t, maxConns, maxCallersPerConn, 0)
+
+    var mu sync.Mutex
+    holders := make(map[*grpc.ClientConn]int)
+    maxHolders := 0
+    var wg sync.WaitGroup
+    for i := 0; i < callers; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for j := 0; j < 20; j++ {
+                conn, err := p.Acquire(context.Background(), target)
+                if err != nil {
+                    t.Error(err)
+                    return
+                }
+                mu.Lock()
+                holders[conn]++
+                maxHolders = max(maxHolders, holders[conn])
+                mu.Unlock()
+                time.Sleep(10 * time.Microsecond)
+                mu.Lock()
+                holders[conn]--
+                mu.Unlock()
+                p.Release(conn)
+            }
+        }()
+    }
+    wg.Wait()
+
+    if len(holders) > maxConns || maxHolders > maxCallersPerConn {
+        t.Errorf("%d connections opened with up to %d callers each, want at most %d with %d", len(holders), maxHolders, maxConns, maxCallersPerConn)
+    }
+    if stats := p.Stats(); stats.Active != 0 || stats.Waiting != 0 || stats.Idle != len(holders) {
+        t.Errorf("stats = %+v, want all %d connections idle", stats, len(holders))
+    }
+}
+
+func TestMaxConnsBelowOne(t *testing.T) {
+    p := newPool(t, 0, 1, 0)
+    acquire(t, p, target)
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+    defer cancel()
+    if _, err := p.Acquire(ctx, target); err == nil {
+        t.Error("a pool of 0 connections opened a second one")
+    }
+}
+
+func TestIdleConnectionsClosed(t *testing.T) {
+    p := newPool(t, 2, 1, 20*time.Millisecond)
+    conn := acquire(t, p, target)
+    p.Release(conn)
+
+    deadline := time.Now().Add(5 * time.Second)
+    for p.Stats().Idle != 0 {
+        if time.Now().After(deadline) {
+            t.Fatal("idle connection was not closed")
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+    if state := conn.GetState(); state != connectivity.Shutdown {
+        t.Errorf("expired connection is %s, want Shutdown", state)
+    }
+    if next := acquire(t, p, target); next == conn {
+        t.Error("Acquire returned a closed connection")
+    }
+}
+
+func TestReleaseClosedConnection(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    conn := acquire(t, p, target)
+    conn.Close()
+    p.Release(conn)
+    // The connection is dropped, and the next caller dials a new one.
+    if stats := p.Stats(); stats != (PoolStats{}) {
+        t.Errorf("stats = %+v, want none", stats)
+    }
+    if next := acquire(t, p, target); next == conn {
+        t.Error("Acquire returned a closed connection")
+    }
+}
+
+func TestReleaseClosedConnectionToWaiter(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    conn := acquire(t, p, target)
+    got := make(chan *grpc.ClientConn)
+    go func() {
+        next, err := p.Acquire(context.Background(), target)
+        if err != nil {
+            t.Error(err)
+        }
+        got <- next
+    }()
+    waitForWaiters(t, p, 1)
+
+    conn.Close()
+    p.Release(conn)
+    // The waiter dials a connection in place of the closed one.
+    if next := <-got; next == nil || next == conn {
+        t.Error("waiter did not get a new connection")
+    }
+    if stats := p.Stats(); stats != (PoolStats{Active: 1}) {
+        t.Errorf("stats = %+v, want 1 active", stats)
+    }
+}
+
+func TestReleaseForeignConnection(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    p.Release(conn)
+    if state := conn.GetState(); state != connectivity.Shutdown {
+        t.Errorf("connection the pool did not open is %s, want Shutdown", state)
+    }
+    if stats := p.Stats(); stats != (PoolStats{}) {
+        t.Errorf("stats = %+v, want none", stats)
+    }
+}
+
+func TestClose(t *testing.T) {
+    p := newPool(t, 1, 1, 0)
+    idle := acquire(t, p, "passthrough:///products-service:50052")
+    p.Release(idle)
+    active := acquire(t, p, target)
+
+    waited := make(chan error)
+    go func() {
+        _, err := p.Acquire(context.Background(), target)
+        waited <- err
+    }()
+    waitForWaiters(t, p, 1)
+
+    if err := p.Close(); err != nil {
+        t.Fatal(err)
+    }
+    if err := <-waited; !errors.Is(err, ErrClosed) {
+        t.Errorf("waiter err = %v, want ErrClosed", err)
+    }
+    if _, err := p.Acquire(context.Background(), target); !errors.Is(err, ErrClosed) {
+        t.Errorf("Acquire after Close: err = %v, want ErrClosed", err)
+    }
+    if state := idle.GetState(); state != connectivity.Shutdown {
+        t.Errorf("idle connection is %s after Close, want Shutdown", state)
+    }
+
+    // A connection held across Close is closed when released.
+    if state := active.GetState(); state == connectivity.Shutdown {
+        t.Error("connection held by a caller was closed by Close")
+    }
+    p.Release(active)
+    if state := active.GetState(); state != connectivity.Shutdown {
+        t.Errorf("connection released after Close is %s, want Shutdown", state)
+    }
+    if stats := p.Stats(); stats != (PoolStats{}) {
+        t.Errorf("stats = %+v, want none", stats)
+    }
+    if err := p.Close(); err != nil {
+        t.Errorf("second Close: %v", err)
+    }
+}
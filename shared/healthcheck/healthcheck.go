@@ -0,0 +1,112 @@
+// Package healthcheck ties a gRPC health server to the database, so an
+// instance that loses its database leaves rotation.
+package healthcheck
+
+import (
+    "context"
+    "log"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/health/grpc_health_v1"
+    "gorm.io/gorm"
+)
+
+// DefaultInterval is how often services ping their database unless
+// configured otherwise.
+const DefaultInterval = 5 * time.Second
+
+// HealthWatcher reports the server and its services SERVING only while the
+// database answers pings and warmup has finished. Consul's gRPC check reads
+// the same status, so an instance that loses its database leaves rotation
+// until the database is back. Updates after a drain are ignored by the
+// health server.
+type HealthWatcher struct {
+    name     string
+    db       *gorm.DB
+    health   *health.Server
+    services []string
+    interval time.Duration
+
+    mu      sync.Mutex
+    warm    bool
+    dbUp    bool
+    serving bool
+}
+
+// NewHealthWatcher reports NOT_SERVING until SetWarm is called. name
+// identifies the instance in log messages.
+func NewHealthWatcher(name string, db *gorm.DB, healthServer *health.Server, interval time.Duration, services ...string) *HealthWatcher {
+    w := &HealthWatcher{name: name, db: db, health: healthServer, services: services, interval: interval, dbUp: true}
+    w.setStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+    return w
+}
+
+// Run pings the database every interval until ctx is done.
+func (w *HealthWatcher) Run(ctx context.Context) {
+    ticker := time.NewTicker(w.interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ticker.C:
+            w.setDBUp(w.ping(ctx))
+        case <-ctx.Done():
+            return
+        }
+    }
+}
+
+func (w *HealthWatcher) ping(ctx context.Context) bool {
+    sqlDB, err := w.db.DB()
+    if err == nil {
+        ctx, cancel := context.WithTimeout(ctx, w.interval)
+        defer cancel()
+        err = sqlDB.PingContext(ctx)
+    }
+    if err != nil && ctx.Err() == nil {
+        log.Printf("Database health check failed: %v", err)
+        return false
+    }
+    return true
+}
+
+// SetWarm marks warmup as finished.
+func (w *HealthWatcher) SetWarm() {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    w.warm = true
+    w.update()
+}
+
+func (w *HealthWatcher) setDBUp(up bool) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    w.dbUp = up
+    w.update()
+}
+
+// update must be called with w.mu held.
+func (w *HealthWatcher) update() {
+    serving := w.warm && w.dbUp
+    if serving == w.serving {
+        return
+    }
+    w.serving = serving
+    if serving {
+        log.Printf("Reporting %s SERVING", w.name)
+        w.setStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+        return
+    }
+    if w.warm {
+        log.Printf("Database unreachable, reporting %s NOT_SERVING", w.name)
+    }
+    w.setStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+}
+
+func (w *HealthWatcher) setStatus(status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+    w.health.SetServingStatus("", status)
+    for _, service := range w.services {
+        w.health.SetServingStatus(service, status)
+    }
+}
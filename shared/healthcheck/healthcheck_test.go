@@ -0,0 +1,90 @@
+package healthcheck
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/health"
+    "google.golang.org/grpc/health/grpc_health_v1"
+
+    "shared/testdb"
+)
+
+func servingStatus(t *testing.T, h *health.Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+    t.Helper()
+    res, err := h.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return res.Status
+}
+
+// TestHealthWatcherFollowsDatabase fails the database ping and checks that
+// the server and its services go NOT_SERVING, then SERVING again once the
+// database answers.
+func TestHealthWatcherFollowsDatabase(t *testing.T) {
+    db, mock := testdb.PingMock(t)
+    healthServer := health.NewServer()
+    w := NewHealthWatcher("catalog-service", db, healthServer, time.Second, "catalog.CatalogService")
+    ctx := context.Background()
+    check := func() { w.setDBUp(w.ping(ctx)) }
+    want := func(step string, status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+        t.Helper()
+        for _, service := range []string{"", "catalog.CatalogService"} {
+            if got := servingStatus(t, healthServer, service); got != status {
+                t.Errorf("%s: %q is %v, want %v", step, service, got, status)
+            }
+        }
+    }
+
+    want("before warmup", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+    mock.ExpectPing()
+    check()
+    want("database up before warmup", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+    w.SetWarm()
+    want("after warmup", grpc_health_v1.HealthCheckResponse_SERVING)
+
+    mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+    check()
+    want("database down", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+    mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+    check()
+    want("database still down", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+    mock.ExpectPing()
+    check()
+    want("database back", grpc_health_v1.HealthCheckResponse_SERVING)
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Error(err)
+    }
+}
+
+func TestHealthWatcherRunStopsWithContext(t *testing.T) {
+    db, mock := testdb.PingMock(t)
+    healthServer := health.NewServer()
+    w := NewHealthWatcher("catalog-service", db, healthServer, 10*time.Millisecond)
+    w.SetWarm()
+    mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        w.Run(ctx)
+        close(done)
+    }()
+    deadline := time.Now().Add(5 * time.Second)
+    for servingStatus(t, healthServer, "") != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+        if time.Now().After(deadline) {
+            t.Fatal("Run never reported the failed ping")
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+    cancel()
+    select {
+    case <-done:
+    case <-time.After(5 * time.Second):
+        t.Fatal("Run did not return after its context was cancelled")
+    }
+}
@@ -0,0 +1,67 @@
+// Package loadreport attaches an instance's load to every RPC response as
+// trailers, for autoscalers and for the gateway to shed load before it
+// reaches a saturated instance.
+package loadreport
+
+import (
+    "context"
+    "strconv"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+)
+
+// Trailers hinting at the instance's load.
+const (
+    LoadHeader         = "x-server-load"
+    BackpressureHeader = "x-backpressure"
+)
+
+// HighLoad is the load above which responses carry x-backpressure: high.
+const HighLoad = 0.8
+
+// LoadReporter reports how busy the instance is from the concurrency
+// limiter's slots.
+type LoadReporter struct {
+    inFlight func() int
+    limit    float64
+}
+
+// New returns a LoadReporter for a limiter allowing limit concurrent RPCs,
+// of which inFlight reports the number in use.
+func New(inFlight func() int, limit int) *LoadReporter {
+    return &LoadReporter{inFlight: inFlight, limit: float64(limit)}
+}
+
+// CurrentLoad returns the fraction of the concurrency limit in use, from 0
+// to 1.
+func (r *LoadReporter) CurrentLoad() float64 {
+    return float64(r.inFlight()) / r.limit
+}
+
+// Trailer returns the load trailers for a response sent now.
+func (r *LoadReporter) Trailer() metadata.MD {
+    load := r.CurrentLoad()
+    md := metadata.MD{LoadHeader: {strconv.FormatFloat(load, 'f', 2, 64)}}
+    if load > HighLoad {
+        md[BackpressureHeader] = []string{"high"}
+    }
+    return md
+}
+
+// UnaryServerInterceptor sets the trailers on a unary RPC. It belongs
+// outside the limiter's interceptor and measures the load as the response
+// is sent, so calls the limiter rejects report a full instance.
+func (r *LoadReporter) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    resp, err := handler(ctx, req)
+    grpc.SetTrailer(ctx, r.Trailer())
+    return resp, err
+}
+
+// StreamServerInterceptor sets the trailers on a streaming RPC like
+// UnaryServerInterceptor.
+func (r *LoadReporter) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    err := handler(srv, ss)
+    ss.SetTrailer(r.Trailer())
+    return err
+}
@@ -0,0 +1,93 @@
+package loadreport
+
+import (
+    "context"
+    "net"
+    "sync/atomic"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/health"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/test/bufconn"
+)
+
+func TestTrailersFollowTheLoad(t *testing.T) {
+    var inFlight atomic.Int64
+    r := New(func() int { return int(inFlight.Load()) }, 10)
+    lis := bufconn.Listen(1 << 16)
+    s := grpc.NewServer(grpc.UnaryInterceptor(r.UnaryServerInterceptor), grpc.StreamInterceptor(r.StreamServerInterceptor))
+    healthpb.RegisterHealthServer(s, health.NewServer())
+    go s.Serve(lis)
+    t.Cleanup(s.Stop)
+
+    conn, err := grpc.NewClient("passthrough:///bufnet",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    client := healthpb.NewHealthClient(conn)
+
+    for _, tc := range []struct {
+        inFlight     int64
+        load         string
+        backpressure []string
+    }{
+        {0, "0.00", nil},
+        {8, "0.80", nil},
+        {9, "0.90", []string{"high"}},
+        {10, "1.00", []string{"high"}},
+    } {
+        inFlight.Store(tc.inFlight)
+        var trailer metadata.MD
+        if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Trailer(&trailer)); err != nil {
+            t.Fatal(err)
+        }
+        if got := trailer.Get(LoadHeader); len(got) != 1 || got[0] != tc.load {
+            t.Errorf("%d in flight: %s = %v, want %s", tc.inFlight, LoadHeader, got, tc.load)
+        }
+        if got := trailer.Get(BackpressureHeader); len(got) != len(tc.backpressure) || len(got) > 0 && got[0] != tc.backpressure[0] {
+            t.Errorf("%d in flight: %s = %v, want %v", tc.inFlight, BackpressureHeader, got, tc.backpressure)
+        }
+    }
+}
+
+// trailerStream stands in for the server transport stream a unary handler
+// runs on, keeping the trailer the interceptor sets.
+type trailerStream struct {
+    trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string                  { return "/grpc.health.v1.Health/Check" }
+func (s *trailerStream) SetHeader(metadata.MD) error     { return nil }
+func (s *trailerStream) SendHeader(metadata.MD) error    { return nil }
+func (s *trailerStream) SetTrailer(md metadata.MD) error { s.trailer = md; return nil }
+
+// BenchmarkUnaryServerInterceptor measures what reporting the load adds to
+// a unary RPC.
+func BenchmarkUnaryServerInterceptor(b *testing.B) {
+    r := New(func() int { return 9 }, 10)
+    ctx := grpc.NewContextWithServerTransportStream(context.Background(), &trailerStream{})
+    info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        r.UnaryServerInterceptor(ctx, nil, info, handler)
+    }
+}
+
+// TestReportingCostsUnderAMicrosecond keeps the trailers cheap enough to set
+// on every response.
+func TestReportingCostsUnderAMicrosecond(t *testing.T) {
+    if testing.Short() {
+        t.Skip("benchmarks in -short mode")
+    }
+    res := testing.Benchmark(BenchmarkUnaryServerInterceptor)
+    if ns := res.NsPerOp(); ns >= 1000 {
+        t.Errorf("reporting the load costs %dns per RPC, want under 1µs", ns)
+    }
+}
@@ -0,0 +1,72 @@
+// Package metrics holds the Prometheus metrics every service exports, and
+// the HTTP server that exports them.
+package metrics
+
+import (
+    "expvar"
+    "fmt"
+    "log"
+    "net/http"
+    "net/http/pprof"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "gorm.io/gorm"
+
+    "shared/slo"
+)
+
+// InFlightRequests is the number of gRPC requests currently being handled.
+var InFlightRequests = prometheus.NewGauge(prometheus.GaugeOpts{
+    Name: "grpc_server_in_flight_requests",
+    Help: "Number of gRPC requests currently being handled.",
+})
+
+func init() {
+    prometheus.MustRegister(InFlightRequests)
+}
+
+// RegisterDBStatsCollector exports the connection pool's sql.DBStats (open,
+// in-use and idle connections, waits and time spent waiting) as go_sql_*
+// metrics labelled with name. They are read at scrape time, so they are
+// never stale.
+func RegisterDBStatsCollector(db *gorm.DB, name string) error {
+    sqlDB, err := db.DB()
+    if err != nil {
+        return err
+    }
+    return prometheus.Register(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+// StartServer serves /metrics, monitor's SLO endpoints, the pprof and expvar
+// endpoints under /debug/, and /readyz when readyz is not nil, on port. name
+// identifies the instance in log messages. The port must not be reachable
+// from outside the deployment: profiles reveal the service's internals.
+func StartServer(name string, port int, readyz http.HandlerFunc, monitor *slo.Monitor) {
+    mux := newServeMux(readyz, monitor)
+    go func() {
+        log.Printf("%s metrics listening on port %d", name, port)
+        if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+            log.Printf("Metrics server stopped: %v", err)
+        }
+    }()
+}
+
+// newServeMux routes the endpoints StartServer serves.
+func newServeMux(readyz http.HandlerFunc, monitor *slo.Monitor) *http.ServeMux {
+    mux := http.NewServeMux()
+    mux.Handle("/metrics", promhttp.Handler())
+    mux.HandleFunc("/slo/alerts.yaml", monitor.AlertsHandler)
+    mux.HandleFunc("/slo/status", monitor.StatusHandler)
+    mux.HandleFunc("/debug/pprof/", pprof.Index)
+    mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+    mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+    mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+    mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+    mux.Handle("/debug/vars", expvar.Handler())
+    if readyz != nil {
+        mux.HandleFunc("/readyz", readyz)
+    }
+    return mux
+}
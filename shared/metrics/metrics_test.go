@@ -0,0 +1,39 @@
+package metrics
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/prometheus/client_golang/prometheus"
+
+    "shared/slo"
+)
+
+func TestServeMux(t *testing.T) {
+    monitor, err := slo.NewMonitor(slo.SLOConfig{ServiceName: "products-service", AvailabilitySLO: 0.999, LatencyP99TargetMs: 250}, prometheus.NewRegistry())
+    if err != nil {
+        t.Fatal(err)
+    }
+    readyz := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) }
+    mux := newServeMux(readyz, monitor)
+
+    for path, want := range map[string]struct {
+        code int
+        body string
+    }{
+        "/metrics":                       {http.StatusOK, "grpc_server_in_flight_requests"},
+        "/slo/alerts.yaml":               {http.StatusOK, "groups:"},
+        "/debug/pprof/":                  {http.StatusOK, "goroutine"},
+        "/debug/pprof/goroutine?debug=1": {http.StatusOK, "goroutine profile"},
+        "/debug/vars":                    {http.StatusOK, `"memstats"`},
+        "/readyz":                        {http.StatusServiceUnavailable, ""},
+    } {
+        rec := httptest.NewRecorder()
+        mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+        if rec.Code != want.code || !strings.Contains(rec.Body.String(), want.body) {
+            t.Errorf("GET %s = %d with %.80q, want %d with %q", path, rec.Code, rec.Body.String(), want.code, want.body)
+        }
+    }
+}
@@ -0,0 +1,210 @@
+// Package pagination pages through gorm queries, so that every list RPC of
+// a service encodes its page tokens the same way.
+//
+// CursorPaginator pages by the sort key of the last row returned, so rows
+// inserted or deleted while a client pages do not shift the later pages. Its
+// tokens are base64-encoded JSON holding that row's id and created_at, and
+// are opaque to clients. OffsetPaginator pages by number, for callers that need
+// totals and random access more than stability.
+package pagination
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "reflect"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// DefaultPageSize is the page size used when a request does not set one.
+const DefaultPageSize = 50
+
+// DefaultMaxPageSize is the largest page size a list RPC returns, unless the
+// service is configured with another.
+const DefaultMaxPageSize = 100
+
+// ErrNegativePageSize is returned by ClampPageSize for a negative page size.
+var ErrNegativePageSize = errors.New("pagination: page size must not be negative")
+
+// ClampPageSize returns the number of rows to return for a request's
+// page_size: DefaultPageSize if it is 0, and maxSize if it is larger than
+// maxSize. A maxSize of 0 or less means DefaultMaxPageSize.
+func ClampPageSize(pageSize int32, maxSize int) (int, error) {
+    if maxSize <= 0 {
+        maxSize = DefaultMaxPageSize
+    }
+    switch {
+    case pageSize < 0:
+        return 0, ErrNegativePageSize
+    case pageSize == 0:
+        if DefaultPageSize > maxSize {
+            return maxSize, nil
+        }
+        return DefaultPageSize, nil
+    case int(pageSize) > maxSize:
+        return maxSize, nil
+    }
+    return int(pageSize), nil
+}
+
+// ErrInvalidToken is returned for a page token that was not produced by
+// PageToken.String.
+var ErrInvalidToken = errors.New("pagination: invalid page token")
+
+// PageToken identifies the last row of a page. The zero PageToken asks for
+// the first page, and is returned once there are no more rows.
+type PageToken struct {
+    ID        uint      `json:"id"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// IsZero reports whether t is the zero PageToken.
+func (t PageToken) IsZero() bool {
+    return t.ID == 0 && t.CreatedAt.IsZero()
+}
+
+// String encodes t for a next_page_token field. The zero PageToken encodes
+// as "".
+func (t PageToken) String() string {
+    if t.IsZero() {
+        return ""
+    }
+    raw, _ := json.Marshal(t)
+    return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParsePageToken decodes a page_token field. "" decodes to the zero
+// PageToken.
+func ParsePageToken(s string) (PageToken, error) {
+    var t PageToken
+    if s == "" {
+        return t, nil
+    }
+    raw, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return PageToken{}, ErrInvalidToken
+    }
+    if err := json.Unmarshal(raw, &t); err != nil || t.IsZero() {
+        return PageToken{}, ErrInvalidToken
+    }
+    return t, nil
+}
+
+// CursorPaginator pages through a query in order of a column, with id
+// breaking ties.
+type CursorPaginator struct {
+    db       *gorm.DB
+    orderCol string
+}
+
+// NewCursorPaginator pages through db, which may already be filtered, in
+// ascending order of orderCol. orderCol must be "id" or "created_at", the
+// columns a PageToken records.
+func NewCursorPaginator(db *gorm.DB, orderCol string) *CursorPaginator {
+    return &CursorPaginator{db: db.Session(&gorm.Session{}), orderCol: orderCol}
+}
+
+// Page fills dest, a pointer to a slice of models with ID and CreatedAt
+// fields, with up to limit rows after the row after identifies. It returns
+// the token for the next page, which is zero if this page is the last, and
+// the number of rows in this page.
+func (p *CursorPaginator) Page(ctx context.Context, dest interface{}, after PageToken, limit int) (PageToken, int64, error) {
+    if limit <= 0 {
+        return PageToken{}, 0, fmt.Errorf("pagination: limit must be positive, got %d", limit)
+    }
+    query := p.db.WithContext(ctx)
+    switch p.orderCol {
+    case "id":
+        query = query.Order("id")
+        if !after.IsZero() {
+            query = query.Where("id > ?", after.ID)
+        }
+    case "created_at":
+        query = query.Order("created_at, id")
+        if !after.IsZero() {
+            query = query.Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID)
+        }
+    default:
+        return PageToken{}, 0, fmt.Errorf("pagination: cannot order by %q, only by id or created_at", p.orderCol)
+    }
+
+    // Fetch one extra row to tell whether another page follows.
+    if err := query.Limit(limit + 1).Find(dest).Error; err != nil {
+        return PageToken{}, 0, err
+    }
+    rows := reflect.ValueOf(dest).Elem()
+    if rows.Len() <= limit {
+        return PageToken{}, int64(rows.Len()), nil
+    }
+    rows.Set(rows.Slice(0, limit))
+    next, err := tokenFor(rows.Index(limit - 1))
+    if err != nil {
+        return PageToken{}, 0, err
+    }
+    return next, int64(limit), nil
+}
+
+// tokenFor builds the token identifying row.
+func tokenFor(row reflect.Value) (PageToken, error) {
+    row = reflect.Indirect(row)
+    if row.Kind() != reflect.Struct {
+        return PageToken{}, fmt.Errorf("pagination: cannot page through %s", row.Type())
+    }
+    var t PageToken
+    id := row.FieldByName("ID")
+    switch id.Kind() {
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        t.ID = uint(id.Uint())
+    default:
+        return PageToken{}, fmt.Errorf("pagination: %s has no unsigned ID field", row.Type())
+    }
+    if createdAt := row.FieldByName("CreatedAt"); createdAt.IsValid() {
+        t.CreatedAt, _ = createdAt.Interface().(time.Time)
+    }
+    return t, nil
+}
+
+// PageInfo describes a page returned by OffsetPaginator.
+type PageInfo struct {
+    // Page is 1-based.
+    Page       int
+    Size       int
+    TotalCount int64
+    TotalPages int
+    HasNext    bool
+}
+
+// OffsetPaginator pages through a query by page number.
+type OffsetPaginator struct {
+    db *gorm.DB
+}
+
+// NewOffsetPaginator pages through db, which may already be filtered and
+// ordered. Rows are ordered by id after any existing ordering, so pages are
+// stable while the table does not change.
+func NewOffsetPaginator(db *gorm.DB) *OffsetPaginator {
+    return &OffsetPaginator{db: db.Session(&gorm.Session{})}
+}
+
+// Page fills dest, a pointer to a slice of models, with the rows of the
+// given 1-based page of size rows. A page past the end leaves dest empty.
+func (p *OffsetPaginator) Page(ctx context.Context, dest interface{}, page, size int) (*PageInfo, error) {
+    if page < 1 || size < 1 {
+        return nil, fmt.Errorf("pagination: page and size must be positive, got page %d size %d", page, size)
+    }
+    info := &PageInfo{Page: page, Size: size}
+    if err := p.db.WithContext(ctx).Model(dest).Count(&info.TotalCount).Error; err != nil {
+        return nil, err
+    }
+    info.TotalPages = int((info.TotalCount + int64(size) - 1) / int64(size))
+    info.HasNext = page < info.TotalPages
+
+    if err := p.db.WithContext(ctx).Order("id").Offset((page - 1) * size).Limit(size).Find(dest).Error; err != nil {
+        return nil, err
+    }
+    return info, nil
+}
@@ -0,0 +1,211 @@
+package pagination
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+
+    "shared/testdb"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+    tokens := []PageToken{
+        {ID: 1},
+        {ID: 42, CreatedAt: time.Date(2024, 6, 1, 12, 30, 0, 123456789, time.UTC)},
+        {ID: ^uint(0), CreatedAt: time.Date(1999, 12, 31, 23, 59, 59, 0, time.FixedZone("NPT", 5*3600+45*60))},
+        {CreatedAt: time.Unix(0, 1).UTC()},
+    }
+    for _, want := range tokens {
+        s := want.String()
+        if s == "" {
+            t.Fatalf("%+v encoded as the empty token", want)
+        }
+        got, err := ParsePageToken(s)
+        if err != nil {
+            t.Fatalf("ParsePageToken(%q): %v", s, err)
+        }
+        if got.ID != want.ID || !got.CreatedAt.Equal(want.CreatedAt) {
+            t.Errorf("round trip of %+v = %+v", want, got)
+        }
+    }
+}
+
+func TestZeroPageToken(t *testing.T) {
+    if s := (PageToken{}).String(); s != "" {
+        t.Errorf("zero token encoded as %q, want \"\"", s)
+    }
+    got, err := ParsePageToken("")
+    if err != nil || !got.IsZero() {
+        t.Errorf("ParsePageToken(\"\") = %+v, %v; want the zero token", got, err)
+    }
+}
+
+func TestParsePageTokenRejectsGarbage(t *testing.T) {
+    for _, s := range []string{
+        "not base64!",
+        "bm90IGpzb24",                     // "not json"
+        "e30",                             // "{}", a zero token
+        "eyJpZCI6MH0",                     // {"id":0}
+        "eyJpZCI6LTF9",                    // {"id":-1}
+        (PageToken{ID: 7}).String() + "=", // padding is not used
+    } {
+        if _, err := ParsePageToken(s); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("ParsePageToken(%q) = %v, want ErrInvalidToken", s, err)
+        }
+    }
+}
+
+func TestClampPageSize(t *testing.T) {
+    tests := []struct {
+        pageSize int32
+        maxSize  int
+        want     int
+    }{
+        {0, 100, DefaultPageSize},
+        {0, 20, 20},
+        {1, 100, 1},
+        {100, 100, 100},
+        {101, 100, 100},
+        {1 << 30, 100, 100},
+        {1 << 30, 0, DefaultMaxPageSize},
+    }
+    for _, tt := range tests {
+        got, err := ClampPageSize(tt.pageSize, tt.maxSize)
+        if err != nil || got != tt.want {
+            t.Errorf("ClampPageSize(%d, %d) = %d, %v; want %d", tt.pageSize, tt.maxSize, got, err, tt.want)
+        }
+    }
+    if _, err := ClampPageSize(-1, 100); !errors.Is(err, ErrNegativePageSize) {
+        t.Errorf("ClampPageSize(-1) = %v, want ErrNegativePageSize", err)
+    }
+}
+
+func TestTokenFor(t *testing.T) {
+    type model struct {
+        ID        uint
+        CreatedAt time.Time
+    }
+    createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+    got, err := tokenFor(reflect.ValueOf(&model{ID: 9, CreatedAt: createdAt}))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.ID != 9 || !got.CreatedAt.Equal(createdAt) {
+        t.Errorf("tokenFor = %+v", got)
+    }
+
+    if _, err := tokenFor(reflect.ValueOf(struct{ ID string }{"9"})); err == nil {
+        t.Error("tokenFor accepted a string ID")
+    }
+    if _, err := tokenFor(reflect.ValueOf(9)); err == nil {
+        t.Error("tokenFor accepted a non-struct")
+    }
+}
+
+type item struct {
+    ID        uint
+    CreatedAt time.Time
+}
+
+func TestEmptyResults(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    ctx := context.Background()
+
+    mock.ExpectQuery(`SELECT \* FROM "items" ORDER BY created_at, id LIMIT 11`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+    var items []item
+    next, n, err := NewCursorPaginator(db.Model(&item{}), "created_at").Page(ctx, &items, PageToken{}, 10)
+    if err != nil || !next.IsZero() || n != 0 || len(items) != 0 {
+        t.Errorf("cursor page of an empty table = %+v, %d, %v with %d items", next, n, err, len(items))
+    }
+
+    mock.ExpectQuery(`SELECT count\(\*\) FROM "items"`).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+    mock.ExpectQuery(`SELECT \* FROM "items" ORDER BY id LIMIT 10`).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+    info, err := NewOffsetPaginator(db).Page(ctx, &items, 1, 10)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if info.TotalCount != 0 || info.TotalPages != 0 || info.HasNext || len(items) != 0 {
+        t.Errorf("offset page of an empty table = %+v with %d items", info, len(items))
+    }
+}
+
+// TestCursorPagesSkipNothingDuringConcurrentInserts pages through a table
+// while another goroutine keeps inserting, and checks that every row present
+// before paging started is returned exactly once.
+func TestCursorPagesSkipNothingDuringConcurrentInserts(t *testing.T) {
+    db := testdb.Postgres(t)
+    if err := db.AutoMigrate(&item{}); err != nil {
+        t.Fatal(err)
+    }
+    // Several rows share each created_at, so ties are broken by id.
+    start := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+    var existing []item
+    for i := 0; i < 100; i++ {
+        existing = append(existing, item{CreatedAt: start.Add(time.Duration(i/3) * time.Second)})
+    }
+    if err := db.Create(&existing).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    for _, orderCol := range []string{"id", "created_at"} {
+        t.Run(orderCol, func(t *testing.T) {
+            ctx, cancel := context.WithCancel(context.Background())
+            inserted := make(chan error, 1)
+            go func() {
+                for ctx.Err() == nil {
+                    if err := db.Create(&item{CreatedAt: time.Now()}).Error; err != nil {
+                        inserted <- err
+                        return
+                    }
+                }
+                inserted <- nil
+            }()
+
+            seen := make(map[uint]int)
+            paginator := NewCursorPaginator(db.Model(&item{}), orderCol)
+            var after PageToken
+            for pages := 0; ; pages++ {
+                if pages > 1000 {
+                    t.Fatal("paging never reached the end of the table")
+                }
+                var page []item
+                next, _, err := paginator.Page(ctx, &page, after, 7)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                for _, it := range page {
+                    seen[it.ID]++
+                }
+                // Inserted rows sort after the original ones in either
+                // order, so stop at the last original row rather than
+                // chase the inserts.
+                if next.IsZero() || next.ID >= existing[len(existing)-1].ID {
+                    break
+                }
+                after = next
+            }
+            cancel()
+            if err := <-inserted; err != nil {
+                t.Fatal(err)
+            }
+
+            for _, it := range existing {
+                if seen[it.ID] != 1 {
+                    t.Errorf("row %d returned %d times, want once", it.ID, seen[it.ID])
+                }
+            }
+            for id, n := range seen {
+                if n > 1 {
+                    t.Errorf("row %d returned %d times", id, n)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,100 @@
+// Package ratelimit limits request rates with a sliding-window log, either
+// per process or shared between replicas through Redis.
+package ratelimit
+
+import (
+    "context"
+    _ "embed"
+    "log"
+    "sync"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/redis/go-redis/v9"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// RateLimitStore decides whether one more request under key fits in limit
+// requests per window, and records it if so.
+type RateLimitStore interface {
+    Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+// LocalRateLimiter keeps the log in memory, so each replica enforces the
+// limit on its own.
+type LocalRateLimiter struct {
+    mu   sync.Mutex
+    logs map[string][]time.Time
+}
+
+func NewLocalRateLimiter() *LocalRateLimiter {
+    return &LocalRateLimiter{logs: make(map[string][]time.Time)}
+}
+
+func (l *LocalRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    now := time.Now()
+    entries := l.logs[key]
+    expired := 0
+    for expired < len(entries) && !entries[expired].After(now.Add(-window)) {
+        expired++
+    }
+    entries = entries[expired:]
+    if len(entries) >= limit {
+        l.logs[key] = entries
+        return false, nil
+    }
+    l.logs[key] = append(entries, now)
+    return true, nil
+}
+
+//go:embed sliding_window.lua
+var slidingWindowScript string
+
+// RedisRateLimiter keeps the log in a Redis sorted set per key, so the limit
+// is shared by every replica using the same Redis.
+type RedisRateLimiter struct {
+    client *redis.Client
+    script *redis.Script
+}
+
+func NewRedisRateLimiter(client *redis.Client) *RedisRateLimiter {
+    return &RedisRateLimiter{client: client, script: redis.NewScript(slidingWindowScript)}
+}
+
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+    allowed, err := r.script.Run(ctx, r.client, []string{"ratelimit:" + key}, window.Microseconds(), limit, uuid.NewString()).Int()
+    if err != nil {
+        return false, err
+    }
+    return allowed == 1, nil
+}
+
+// KeyFunc names the bucket a request counts against. An empty key exempts
+// the request from the limit.
+type KeyFunc func(ctx context.Context, fullMethod string) string
+
+// NewRateLimitInterceptor allows at most limit requests per window for each
+// key, rejecting the rest with ResourceExhausted. If the store fails the
+// request is let through.
+func NewRateLimitInterceptor(store RateLimitStore, limit int, window time.Duration, key KeyFunc) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        bucket := key(ctx, info.FullMethod)
+        if bucket == "" {
+            return handler(ctx, req)
+        }
+        allowed, err := store.Allow(ctx, bucket, limit, window)
+        if err != nil {
+            log.Printf("Rate limit check failed, allowing request: %v", err)
+            return handler(ctx, req)
+        }
+        if !allowed {
+            return nil, status.Errorf(codes.ResourceExhausted, "rate limit of %d requests per %v exceeded", limit, window)
+        }
+        return handler(ctx, req)
+    }
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/redis/go-redis/v9"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+func TestLocalRateLimiterSlidesTheWindow(t *testing.T) {
+    l := NewLocalRateLimiter()
+    ctx := context.Background()
+    for i := 0; i < 3; i++ {
+        if ok, _ := l.Allow(ctx, "acme", 3, 100*time.Millisecond); !ok {
+            t.Fatalf("request %d rejected under the limit", i)
+        }
+    }
+    if ok, _ := l.Allow(ctx, "acme", 3, 100*time.Millisecond); ok {
+        t.Error("fourth request allowed with a limit of 3")
+    }
+    if ok, _ := l.Allow(ctx, "globex", 3, 100*time.Millisecond); !ok {
+        t.Error("another key shares acme's budget")
+    }
+    time.Sleep(120 * time.Millisecond)
+    if ok, _ := l.Allow(ctx, "acme", 3, 100*time.Millisecond); !ok {
+        t.Error("request rejected after the window passed")
+    }
+}
+
+func newRedisRateLimiter(t *testing.T, m *miniredis.Miniredis) *RedisRateLimiter {
+    t.Helper()
+    client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+    t.Cleanup(func() { client.Close() })
+    return NewRedisRateLimiter(client)
+}
+
+// TestRedisRateLimiterConcurrentCallers runs 10 callers, spread over two
+// replicas sharing one Redis, against a single budget.
+func TestRedisRateLimiterConcurrentCallers(t *testing.T) {
+    m := miniredis.RunT(t)
+    replicas := []*RedisRateLimiter{newRedisRateLimiter(t, m), newRedisRateLimiter(t, m)}
+    const limit, callers, requests = 50, 10, 20
+
+    var allowed atomic.Int64
+    var wg sync.WaitGroup
+    for i := 0; i < callers; i++ {
+        wg.Add(1)
+        go func(limiter *RedisRateLimiter) {
+            defer wg.Done()
+            for j := 0; j < requests; j++ {
+                ok, err := limiter.Allow(context.Background(), "acme", limit, time.Minute)
+                if err != nil {
+                    t.Error(err)
+                    return
+                }
+                if ok {
+                    allowed.Add(1)
+                }
+            }
+        }(replicas[i%len(replicas)])
+    }
+    wg.Wait()
+
+    if got := allowed.Load(); got != limit {
+        t.Errorf("%d of %d requests allowed, want exactly %d", got, callers*requests, limit)
+    }
+}
+
+func TestRedisRateLimiterSlidesTheWindow(t *testing.T) {
+    m := miniredis.RunT(t)
+    l := newRedisRateLimiter(t, m)
+    ctx := context.Background()
+    start := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+    allow := func(at time.Duration) bool {
+        t.Helper()
+        m.SetTime(start.Add(at))
+        ok, err := l.Allow(ctx, "acme", 2, time.Second)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return ok
+    }
+    if !allow(0) || !allow(600*time.Millisecond) {
+        t.Fatal("requests rejected under the limit")
+    }
+    if allow(900 * time.Millisecond) {
+        t.Error("third request in one second allowed")
+    }
+    // The first request has left the window, the second has not.
+    if !allow(1100 * time.Millisecond) {
+        t.Error("request rejected after the oldest one expired")
+    }
+    if allow(1200 * time.Millisecond) {
+        t.Error("request allowed while two are in the window")
+    }
+}
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (bool, error) {
+    return false, errors.New("connection refused")
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+    info := &grpc.UnaryServerInfo{FullMethod: "/products.ProductService/GetProduct"}
+    key := func(ctx context.Context, fullMethod string) string { return "acme" }
+
+    limited := NewRateLimitInterceptor(NewLocalRateLimiter(), 1, time.Minute, key)
+    if _, err := limited(context.Background(), nil, info, handler); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := limited(context.Background(), nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("second request: %v, want ResourceExhausted", err)
+    }
+
+    exempt := NewRateLimitInterceptor(NewLocalRateLimiter(), 1, time.Minute, func(context.Context, string) string { return "" })
+    for i := 0; i < 3; i++ {
+        if _, err := exempt(context.Background(), nil, info, handler); err != nil {
+            t.Errorf("exempt request %d: %v", i, err)
+        }
+    }
+
+    failOpen := NewRateLimitInterceptor(failingStore{}, 1, time.Minute, key)
+    if _, err := failOpen(context.Background(), nil, info, handler); err != nil {
+        t.Errorf("request with the store down: %v, want it let through", err)
+    }
+}
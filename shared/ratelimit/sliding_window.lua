@@ -0,0 +1,18 @@
+-- Sliding-window log rate limiter.
+-- KEYS[1]: sorted set of request ids scored by arrival time in microseconds
+-- ARGV[1]: window in microseconds
+-- ARGV[2]: limit
+-- ARGV[3]: unique id for this request
+-- Returns 1 if the request is allowed, 0 if not. Rejected requests are not
+-- recorded. Redis's own clock is used so replicas with skewed clocks agree.
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local window = tonumber(ARGV[1])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+    return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+return 1
@@ -0,0 +1,103 @@
+// Package recovery turns a panicking gRPC handler into an Internal error, so
+// one bad request does not take the server down with it.
+package recovery
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "runtime/debug"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+)
+
+// redactedHeaders are left out of the logged request metadata.
+var redactedHeaders = []string{"x-api-key", "authorization"}
+
+var panicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+    Name: "grpc_panics_total",
+    Help: "Number of gRPC handlers that panicked, by method.",
+}, []string{"method"})
+
+// AlertFunc is told about each recovered panic, e.g. to page someone. It is
+// called on its own goroutine, so it may block.
+type AlertFunc func(panicValue interface{}, stack []byte)
+
+// NewRecoveryInterceptor recovers a panicking handler: it logs the panic
+// with its stack trace and the request metadata, counts it in
+// grpc_panics_total, calls alerter if not nil, and fails the request with
+// Internal. It should be first in the chain, so panics in other interceptors
+// are recovered too.
+func NewRecoveryInterceptor(alerter AlertFunc) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+        defer func() {
+            if p := recover(); p != nil {
+                err = recovered(ctx, info.FullMethod, p, alerter)
+            }
+        }()
+        return handler(ctx, req)
+    }
+}
+
+// NewStreamRecoveryInterceptor is NewRecoveryInterceptor for streaming RPCs.
+func NewStreamRecoveryInterceptor(alerter AlertFunc) grpc.StreamServerInterceptor {
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+        defer func() {
+            if p := recover(); p != nil {
+                err = recovered(ss.Context(), info.FullMethod, p, alerter)
+            }
+        }()
+        return handler(srv, ss)
+    }
+}
+
+func recovered(ctx context.Context, method string, p interface{}, alerter AlertFunc) error {
+    stack := debug.Stack()
+    log.Printf("ERROR: panic in %s: %v\nmetadata: %v\n%s", method, p, loggedMetadata(ctx), stack)
+    panicsTotal.WithLabelValues(method).Inc()
+    if alerter != nil {
+        go alerter(p, stack)
+    }
+    return status.Error(codes.Internal, "internal error")
+}
+
+func loggedMetadata(ctx context.Context) metadata.MD {
+    md, _ := metadata.FromIncomingContext(ctx)
+    md = md.Copy()
+    for _, key := range redactedHeaders {
+        if len(md.Get(key)) > 0 {
+            md.Set(key, "[redacted]")
+        }
+    }
+    return md
+}
+
+// WebhookAlerter posts each panic to url as a JSON object whose text field
+// describes it, which Slack incoming webhooks and most alerting tools accept.
+// source names the service in the message.
+func WebhookAlerter(url, source string) AlertFunc {
+    client := &http.Client{Timeout: 10 * time.Second}
+    return func(panicValue interface{}, stack []byte) {
+        body, _ := json.Marshal(map[string]string{
+            "text": fmt.Sprintf("%s recovered from a panic: %v\n%s", source, panicValue, stack),
+        })
+        res, err := client.Post(url, "application/json", bytes.NewReader(body))
+        if err != nil {
+            log.Printf("Failed to send panic alert: %v", err)
+            return
+        }
+        res.Body.Close()
+        if res.StatusCode >= 300 {
+            log.Printf("Failed to send panic alert: %s", res.Status)
+        }
+    }
+}
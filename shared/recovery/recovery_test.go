@@ -0,0 +1,158 @@
+package recovery
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus/testutil"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+)
+
+// alertRecorder counts the alerts it is sent and passes each panic value on.
+type alertRecorder struct {
+    calls  atomic.Int32
+    values chan interface{}
+}
+
+func newAlertRecorder() *alertRecorder {
+    return &alertRecorder{values: make(chan interface{}, 10)}
+}
+
+func (a *alertRecorder) alert(panicValue interface{}, stack []byte) {
+    a.calls.Add(1)
+    a.values <- panicValue
+}
+
+// waitForOne waits for an alert, then makes sure no second one follows.
+func (a *alertRecorder) waitForOne(t *testing.T) interface{} {
+    t.Helper()
+    var value interface{}
+    select {
+    case value = <-a.values:
+    case <-time.After(time.Second):
+        t.Fatal("alerter was not called")
+    }
+    time.Sleep(50 * time.Millisecond)
+    if n := a.calls.Load(); n != 1 {
+        t.Errorf("alerter called %d times, want once", n)
+    }
+    return value
+}
+
+// captureLog returns what is logged while the test runs.
+func captureLog(t *testing.T) *bytes.Buffer {
+    var buf bytes.Buffer
+    out := log.Writer()
+    log.SetOutput(&buf)
+    t.Cleanup(func() { log.SetOutput(out) })
+    return &buf
+}
+
+func TestPanickingHandlerReturnsInternal(t *testing.T) {
+    logged := captureLog(t)
+    alerts := newAlertRecorder()
+    const method = "/product.ProductService/GetProduct"
+    before := testutil.ToFloat64(panicsTotal.WithLabelValues(method))
+
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "sk_live_secret", "x-request-id", "req-42"))
+    res, err := NewRecoveryInterceptor(alerts.alert)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, interface{}) (interface{}, error) {
+        var m map[string]int
+        m["boom"]++
+        return "unreachable", nil
+    })
+    if status.Code(err) != codes.Internal || res != nil {
+        t.Errorf("panicking handler returned %v, %v; want Internal", res, err)
+    }
+    if value := alerts.waitForOne(t); !strings.Contains(value.(error).Error(), "nil map") {
+        t.Errorf("alerted panic value %v, want the nil map assignment", value)
+    }
+    if got := testutil.ToFloat64(panicsTotal.WithLabelValues(method)) - before; got != 1 {
+        t.Errorf("grpc_panics_total{method=%q} rose by %v, want 1", method, got)
+    }
+
+    out := logged.String()
+    for _, want := range []string{"ERROR: panic in " + method, "req-42", "[redacted]", "recovery_test.go"} {
+        if !strings.Contains(out, want) {
+            t.Errorf("log is missing %q:\n%s", want, out)
+        }
+    }
+    if strings.Contains(out, "sk_live_secret") {
+        t.Errorf("log contains the API key:\n%s", out)
+    }
+}
+
+func TestHandlerWithoutPanicIsUntouched(t *testing.T) {
+    alerts := newAlertRecorder()
+    wantErr := status.Error(codes.NotFound, "no such product")
+    res, err := NewRecoveryInterceptor(alerts.alert)(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+        return "product", wantErr
+    })
+    if res != "product" || err != wantErr {
+        t.Errorf("got %v, %v; want the handler's result", res, err)
+    }
+    if n := alerts.calls.Load(); n != 0 {
+        t.Errorf("alerter called %d times without a panic", n)
+    }
+}
+
+// fakeStream is a server stream with an incoming context.
+type fakeStream struct {
+    grpc.ServerStream
+}
+
+func (fakeStream) Context() context.Context { return context.Background() }
+
+func TestPanickingStreamReturnsInternal(t *testing.T) {
+    captureLog(t)
+    alerts := newAlertRecorder()
+    err := NewStreamRecoveryInterceptor(alerts.alert)(nil, fakeStream{}, &grpc.StreamServerInfo{FullMethod: "/product.ProductService/WatchProducts"}, func(interface{}, grpc.ServerStream) error {
+        panic("watcher list corrupted")
+    })
+    if status.Code(err) != codes.Internal {
+        t.Errorf("panicking stream returned %v, want Internal", err)
+    }
+    if value := alerts.waitForOne(t); value != "watcher list corrupted" {
+        t.Errorf("alerted panic value %v", value)
+    }
+}
+
+func TestPanicWithoutAlerter(t *testing.T) {
+    captureLog(t)
+    _, err := NewRecoveryInterceptor(nil)(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+        panic("boom")
+    })
+    if status.Code(err) != codes.Internal {
+        t.Errorf("got %v, want Internal", err)
+    }
+}
+
+func TestWebhookAlerter(t *testing.T) {
+    texts := make(chan string, 1)
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var body struct{ Text string }
+        if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&body) != nil {
+            http.Error(w, "bad request", http.StatusBadRequest)
+        }
+        texts <- body.Text
+    }))
+    defer srv.Close()
+
+    WebhookAlerter(srv.URL, "products-service-7f9c")("boom", []byte("goroutine 1 [running]:"))
+    text := <-texts
+    for _, want := range []string{"products-service-7f9c", "boom", "goroutine 1 [running]:"} {
+        if !strings.Contains(text, want) {
+            t.Errorf("alert text %q is missing %q", text, want)
+        }
+    }
+}
@@ -0,0 +1,29 @@
+// Package servedby names the instance that handled each RPC in a trailer, so
+// a client-side error can be matched with that instance's logs.
+package servedby
+
+import (
+    "context"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+)
+
+// Header is the trailer naming the instance that handled an RPC.
+const Header = "x-served-by"
+
+// Trailer is the instance's Consul registration ID, sent as the x-served-by
+// trailer of every RPC, including those that fail.
+type Trailer string
+
+// UnaryServerInterceptor sets the trailer on a unary RPC.
+func (id Trailer) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    grpc.SetTrailer(ctx, metadata.Pairs(Header, string(id)))
+    return handler(ctx, req)
+}
+
+// StreamServerInterceptor sets the trailer on a streaming RPC.
+func (id Trailer) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    ss.SetTrailer(metadata.Pairs(Header, string(id)))
+    return handler(srv, ss)
+}
@@ -0,0 +1,73 @@
+package servedby
+
+import (
+    "context"
+    "net"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/health"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/grpc/test/bufconn"
+)
+
+func TestUnaryTrailerIsSentOnEveryRPC(t *testing.T) {
+    id := Trailer("products-service-7f9c")
+    lis := bufconn.Listen(1 << 16)
+    s := grpc.NewServer(
+        grpc.UnaryInterceptor(id.UnaryServerInterceptor),
+        grpc.StreamInterceptor(id.StreamServerInterceptor),
+    )
+    healthServer := health.NewServer()
+    healthServer.SetServingStatus("products", healthpb.HealthCheckResponse_SERVING)
+    healthpb.RegisterHealthServer(s, healthServer)
+    go s.Serve(lis)
+    t.Cleanup(s.Stop)
+
+    conn, err := grpc.NewClient("passthrough:///bufnet",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    client := healthpb.NewHealthClient(conn)
+    ctx := context.Background()
+
+    for service, want := range map[string]codes.Code{"products": codes.OK, "missing": codes.NotFound} {
+        var trailer metadata.MD
+        _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service}, grpc.Trailer(&trailer))
+        if status.Code(err) != want {
+            t.Fatalf("Check(%s) = %v, want %v", service, err, want)
+        }
+        if got := trailer.Get(Header); len(got) != 1 || got[0] != string(id) {
+            t.Errorf("Check(%s) trailer %s = %v, want %s", service, Header, got, id)
+        }
+    }
+}
+
+// recordingStream records the trailer set on it.
+type recordingStream struct {
+    grpc.ServerStream
+    trailer metadata.MD
+}
+
+func (s *recordingStream) SetTrailer(md metadata.MD) { s.trailer = metadata.Join(s.trailer, md) }
+
+func TestStreamTrailerIsSetWhenTheHandlerFails(t *testing.T) {
+    id := Trailer("users-service-2b1e")
+    ss := &recordingStream{}
+    err := id.StreamServerInterceptor(nil, ss, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+        return status.Error(codes.Unavailable, "database is down")
+    })
+    if status.Code(err) != codes.Unavailable {
+        t.Errorf("interceptor returned %v, want the handler's error", err)
+    }
+    if got := ss.trailer.Get(Header); len(got) != 1 || got[0] != string(id) {
+        t.Errorf("trailer %s = %v, want %s", Header, got, id)
+    }
+}
@@ -0,0 +1,326 @@
+// Package slo records the request metrics a service's Service Level
+// Objectives are measured by, generates the Prometheus alerting rules that
+// watch them, and reports how the service is doing against them.
+//
+// Only unary RPCs are recorded: a streaming RPC lasts as long as its client
+// keeps watching, which says nothing about the service's latency.
+package slo
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "math"
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    dto "github.com/prometheus/client_model/go"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gopkg.in/yaml.v3"
+)
+
+// alertWindow is the window the generated rules measure over.
+const alertWindow = "5m"
+
+// errorCodes are the status codes that count against availability. The
+// others report a problem with the request rather than the service.
+var errorCodes = []codes.Code{
+    codes.Unknown,
+    codes.DeadlineExceeded,
+    codes.Unimplemented,
+    codes.Internal,
+    codes.Unavailable,
+    codes.DataLoss,
+}
+
+// SLOConfig holds a service's objectives.
+type SLOConfig struct {
+    ServiceName string
+    // AvailabilitySLO is the fraction of requests that must not fail, e.g.
+    // 0.999.
+    AvailabilitySLO float64
+    // LatencyP99TargetMs is the 99th percentile latency requests must stay
+    // under, in milliseconds.
+    LatencyP99TargetMs float64
+}
+
+// PrometheusAlertRule is an alerting rule as written in a Prometheus rule
+// file.
+type PrometheusAlertRule struct {
+    Alert       string            `yaml:"alert"`
+    Expr        string            `yaml:"expr"`
+    For         string            `yaml:"for"`
+    Labels      map[string]string `yaml:"labels"`
+    Annotations map[string]string `yaml:"annotations"`
+}
+
+// ruleFile is a Prometheus rule file holding a single group.
+type ruleFile struct {
+    Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+    Name  string                `yaml:"name"`
+    Rules []PrometheusAlertRule `yaml:"rules"`
+}
+
+// GeneratePrometheusAlertRules returns rules that fire when, over five
+// minutes, more than 1 - AvailabilitySLO of the service's requests fail or
+// its 99th percentile latency exceeds LatencyP99TargetMs.
+func GeneratePrometheusAlertRules(slo SLOConfig) []PrometheusAlertRule {
+    name := alertName(slo.ServiceName)
+    selector := fmt.Sprintf(`service=%q`, slo.ServiceName)
+    errorBudget := 1 - slo.AvailabilitySLO
+    return []PrometheusAlertRule{
+        {
+            Alert: name + "HighErrorRate",
+            Expr: fmt.Sprintf(`sum(rate(grpc_server_handled_total{%s,grpc_code=~%q}[%s])) / sum(rate(grpc_server_handled_total{%s}[%s])) > %s`,
+                selector, errorCodePattern(), alertWindow, selector, alertWindow, formatFloat(errorBudget)),
+            For:    alertWindow,
+            Labels: map[string]string{"severity": "page", "service": slo.ServiceName},
+            Annotations: map[string]string{
+                "summary":     fmt.Sprintf("%s error rate is above %s%%", slo.ServiceName, formatFloat(errorBudget*100)),
+                "description": fmt.Sprintf("More than %s%% of %s requests failed over the last %s, against an availability SLO of %s%%.", formatFloat(errorBudget*100), slo.ServiceName, alertWindow, formatFloat(slo.AvailabilitySLO*100)),
+            },
+        },
+        {
+            Alert: name + "HighLatency",
+            Expr: fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(grpc_server_handling_seconds_bucket{%s}[%s]))) > %s`,
+                selector, alertWindow, formatFloat(slo.LatencyP99TargetMs/1000)),
+            For:    alertWindow,
+            Labels: map[string]string{"severity": "page", "service": slo.ServiceName},
+            Annotations: map[string]string{
+                "summary":     fmt.Sprintf("%s p99 latency is above %sms", slo.ServiceName, formatFloat(slo.LatencyP99TargetMs)),
+                "description": fmt.Sprintf("The 99th percentile latency of %s requests over the last %s is above the %sms target.", slo.ServiceName, alertWindow, formatFloat(slo.LatencyP99TargetMs)),
+            },
+        },
+    }
+}
+
+// RuleFileYAML encodes rules as a Prometheus rule file, in a group named
+// after the service.
+func RuleFileYAML(serviceName string, rules []PrometheusAlertRule) ([]byte, error) {
+    return yaml.Marshal(ruleFile{Groups: []ruleGroup{{Name: serviceName + "-slo", Rules: rules}}})
+}
+
+// alertName turns a service name such as "products-service" into
+// "ProductsService".
+func alertName(serviceName string) string {
+    var b strings.Builder
+    for _, part := range strings.FieldsFunc(serviceName, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+        b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+    }
+    return b.String()
+}
+
+func errorCodePattern() string {
+    names := make([]string, len(errorCodes))
+    for i, code := range errorCodes {
+        names[i] = code.String()
+    }
+    return strings.Join(names, "|")
+}
+
+func formatFloat(f float64) string {
+    // Round away the float noise of e.g. 1 - 0.999.
+    return strconv.FormatFloat(math.Round(f*1e9)/1e9, 'f', -1, 64)
+}
+
+// Monitor records request counts and latencies for a service and serves its
+// SLO endpoints.
+type Monitor struct {
+    slo     SLOConfig
+    handled *prometheus.CounterVec
+    latency *prometheus.HistogramVec
+}
+
+// NewMonitor registers the grpc_server_handled_total and
+// grpc_server_handling_seconds metrics with registerer, labelled with the
+// service name.
+func NewMonitor(slo SLOConfig, registerer prometheus.Registerer) (*Monitor, error) {
+    m := &Monitor{
+        slo: slo,
+        handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Name:        "grpc_server_handled_total",
+            Help:        "Number of unary gRPC requests completed, by method and status code.",
+            ConstLabels: prometheus.Labels{"service": slo.ServiceName},
+        }, []string{"grpc_method", "grpc_code"}),
+        latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+            Name:        "grpc_server_handling_seconds",
+            Help:        "Time taken to handle unary gRPC requests, by method.",
+            ConstLabels: prometheus.Labels{"service": slo.ServiceName},
+            Buckets:     prometheus.DefBuckets,
+        }, []string{"grpc_method"}),
+    }
+    for _, collector := range []prometheus.Collector{m.handled, m.latency} {
+        if err := registerer.Register(collector); err != nil {
+            return nil, err
+        }
+    }
+    return m, nil
+}
+
+// UnaryInterceptor records each request's status code and latency. It should
+// be the outermost interceptor, so requests rejected by the others are
+// recorded too.
+func (m *Monitor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    start := time.Now()
+    res, err := handler(ctx, req)
+    m.handled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+    m.latency.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+    return res, err
+}
+
+// AlertsHandler serves the generated alerting rules as a Prometheus rule
+// file, at GET /slo/alerts.yaml.
+func (m *Monitor) AlertsHandler(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    out, err := RuleFileYAML(m.slo.ServiceName, GeneratePrometheusAlertRules(m.slo))
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/yaml")
+    w.Write(out)
+}
+
+// Status is how the service is doing against its objectives.
+type Status struct {
+    Service            string  `json:"service"`
+    Requests           uint64  `json:"requests"`
+    Errors             uint64  `json:"errors"`
+    Availability       float64 `json:"availability"`
+    AvailabilitySLO    float64 `json:"availability_slo"`
+    AvailabilityOK     bool    `json:"availability_ok"`
+    LatencyP99Ms       float64 `json:"latency_p99_ms"`
+    LatencyP99TargetMs float64 `json:"latency_p99_target_ms"`
+    LatencyOK          bool    `json:"latency_ok"`
+    OK                 bool    `json:"ok"`
+}
+
+// Status evaluates the requests recorded since the process started. Unlike
+// the alerting rules, it does not look at a recent window, so a recent
+// problem shows up more slowly.
+func (m *Monitor) Status() (*Status, error) {
+    s := &Status{Service: m.slo.ServiceName, Availability: 1, AvailabilitySLO: m.slo.AvailabilitySLO, LatencyP99TargetMs: m.slo.LatencyP99TargetMs}
+
+    isError := make(map[string]bool, len(errorCodes))
+    for _, code := range errorCodes {
+        isError[code.String()] = true
+    }
+    handled, err := collect(m.handled)
+    if err != nil {
+        return nil, err
+    }
+    for _, metric := range handled {
+        count := uint64(metric.GetCounter().GetValue())
+        s.Requests += count
+        for _, label := range metric.GetLabel() {
+            if label.GetName() == "grpc_code" && isError[label.GetValue()] {
+                s.Errors += count
+            }
+        }
+    }
+    if s.Requests > 0 {
+        s.Availability = 1 - float64(s.Errors)/float64(s.Requests)
+    }
+
+    latency, err := collect(m.latency)
+    if err != nil {
+        return nil, err
+    }
+    s.LatencyP99Ms = quantile(0.99, latency) * 1000
+
+    s.AvailabilityOK = s.Availability >= m.slo.AvailabilitySLO
+    s.LatencyOK = s.LatencyP99Ms <= m.slo.LatencyP99TargetMs
+    s.OK = s.AvailabilityOK && s.LatencyOK
+    return s, nil
+}
+
+// StatusHandler serves Status as JSON, at GET /slo/status. It responds 503
+// when an objective is missed, so it can be probed directly.
+func (m *Monitor) StatusHandler(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    s, err := m.Status()
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    if !s.OK {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }
+    if err := json.NewEncoder(w).Encode(s); err != nil {
+        log.Printf("Failed to write SLO status: %v", err)
+    }
+}
+
+// collect reads the current values of collector's metrics.
+func collect(collector prometheus.Collector) ([]*dto.Metric, error) {
+    ch := make(chan prometheus.Metric)
+    go func() {
+        collector.Collect(ch)
+        close(ch)
+    }()
+    var metrics []*dto.Metric
+    var err error
+    for metric := range ch {
+        var m dto.Metric
+        if writeErr := metric.Write(&m); writeErr != nil {
+            err = writeErr
+            continue
+        }
+        metrics = append(metrics, &m)
+    }
+    return metrics, err
+}
+
+// quantile estimates the q quantile, in seconds, of the histograms summed
+// together, interpolating within buckets as Prometheus' histogram_quantile
+// does. Observations beyond the largest bucket are reported as its bound.
+func quantile(q float64, histograms []*dto.Metric) float64 {
+    counts := make(map[float64]uint64)
+    var total uint64
+    for _, metric := range histograms {
+        h := metric.GetHistogram()
+        total += h.GetSampleCount()
+        for _, bucket := range h.GetBucket() {
+            counts[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+        }
+    }
+    if total == 0 {
+        return 0
+    }
+    bounds := make([]float64, 0, len(counts))
+    for bound := range counts {
+        bounds = append(bounds, bound)
+    }
+    sort.Float64s(bounds)
+
+    rank := q * float64(total)
+    lower, below := 0.0, uint64(0)
+    for _, bound := range bounds {
+        if float64(counts[bound]) >= rank {
+            inBucket := counts[bound] - below
+            if inBucket == 0 {
+                return bound
+            }
+            return lower + (bound-lower)*(rank-float64(below))/float64(inBucket)
+        }
+        lower, below = bound, counts[bound]
+    }
+    return lower
+}
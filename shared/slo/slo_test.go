@@ -0,0 +1,214 @@
+package slo
+
+import (
+    "context"
+    "encoding/json"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "regexp"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gopkg.in/yaml.v3"
+)
+
+var testSLO = SLOConfig{ServiceName: "products-service", AvailabilitySLO: 0.999, LatencyP99TargetMs: 250}
+
+// alertNamePattern is what Prometheus accepts as an alert name, which must be
+// a valid metric name.
+var alertNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// balanced reports whether the brackets of a PromQL expression pair up,
+// ignoring those inside quoted label values.
+func balanced(expr string) bool {
+    var stack []rune
+    pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+    quoted := false
+    for i, r := range expr {
+        switch {
+        case r == '"' && (i == 0 || expr[i-1] != '\\'):
+            quoted = !quoted
+        case quoted:
+        case r == '(' || r == '[' || r == '{':
+            stack = append(stack, r)
+        case pairs[r] != 0:
+            if len(stack) == 0 || stack[len(stack)-1] != pairs[r] {
+                return false
+            }
+            stack = stack[:len(stack)-1]
+        }
+    }
+    return len(stack) == 0 && !quoted
+}
+
+func TestRuleFileYAMLIsAPrometheusRuleFile(t *testing.T) {
+    out, err := RuleFileYAML(testSLO.ServiceName, GeneratePrometheusAlertRules(testSLO))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Decode strictly into the rule file schema, so unknown or misspelt
+    // keys fail the test as they would fail promtool.
+    var file struct {
+        Groups []struct {
+            Name  string `yaml:"name"`
+            Rules []struct {
+                Alert       string            `yaml:"alert"`
+                Expr        string            `yaml:"expr"`
+                For         string            `yaml:"for"`
+                Labels      map[string]string `yaml:"labels"`
+                Annotations map[string]string `yaml:"annotations"`
+            } `yaml:"rules"`
+        } `yaml:"groups"`
+    }
+    decoder := yaml.NewDecoder(strings.NewReader(string(out)))
+    decoder.KnownFields(true)
+    if err := decoder.Decode(&file); err != nil {
+        t.Fatalf("decode rule file: %v\n%s", err, out)
+    }
+    if len(file.Groups) != 1 || file.Groups[0].Name != "products-service-slo" {
+        t.Fatalf("groups = %+v, want one named products-service-slo", file.Groups)
+    }
+
+    alerts := make(map[string]string)
+    for _, rule := range file.Groups[0].Rules {
+        if !alertNamePattern.MatchString(rule.Alert) {
+            t.Errorf("invalid alert name %q", rule.Alert)
+        }
+        if _, err := time.ParseDuration(rule.For); err != nil {
+            t.Errorf("%s: invalid for %q: %v", rule.Alert, rule.For, err)
+        }
+        if !balanced(rule.Expr) {
+            t.Errorf("%s: unbalanced expression %s", rule.Alert, rule.Expr)
+        }
+        if rule.Labels["severity"] == "" || rule.Annotations["summary"] == "" {
+            t.Errorf("%s: missing severity label or summary annotation", rule.Alert)
+        }
+        alerts[rule.Alert] = rule.Expr
+    }
+
+    errorRate, ok := alerts["ProductsServiceHighErrorRate"]
+    if !ok {
+        t.Fatalf("no error rate alert in %v", alerts)
+    }
+    for _, want := range []string{`service="products-service"`, `grpc_code=~"Unknown|DeadlineExceeded|Unimplemented|Internal|Unavailable|DataLoss"`, "[5m]", "> 0.001"} {
+        if !strings.Contains(errorRate, want) {
+            t.Errorf("error rate expression %s does not contain %s", errorRate, want)
+        }
+    }
+    latency, ok := alerts["ProductsServiceHighLatency"]
+    if !ok {
+        t.Fatalf("no latency alert in %v", alerts)
+    }
+    for _, want := range []string{"histogram_quantile(0.99,", "grpc_server_handling_seconds_bucket", "[5m]", "> 0.25"} {
+        if !strings.Contains(latency, want) {
+            t.Errorf("latency expression %s does not contain %s", latency, want)
+        }
+    }
+}
+
+func TestAlertName(t *testing.T) {
+    for in, want := range map[string]string{"products-service": "ProductsService", "api_gateway": "ApiGateway", "users.v2": "UsersV2"} {
+        if got := alertName(in); got != want {
+            t.Errorf("alertName(%q) = %q, want %q", in, got, want)
+        }
+    }
+}
+
+func record(t *testing.T, m *Monitor, n int, code codes.Code) {
+    t.Helper()
+    info := &grpc.UnaryServerInfo{FullMethod: "/products.ProductService/GetProduct"}
+    handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+        return nil, status.Error(code, code.String())
+    }
+    for i := 0; i < n; i++ {
+        m.UnaryInterceptor(context.Background(), nil, info, handler)
+    }
+}
+
+func TestStatus(t *testing.T) {
+    m, err := NewMonitor(testSLO, prometheus.NewRegistry())
+    if err != nil {
+        t.Fatal(err)
+    }
+    record(t, m, 998, codes.OK)
+    // Client errors do not count against availability.
+    record(t, m, 1, codes.NotFound)
+    record(t, m, 1, codes.Internal)
+
+    s, err := m.Status()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if s.Requests != 1000 || s.Errors != 1 || s.Availability != 0.999 || !s.AvailabilityOK {
+        t.Errorf("status = %+v, want 1 error in 1000 requests, meeting 99.9%%", s)
+    }
+    if !s.LatencyOK || !s.OK {
+        t.Errorf("status = %+v, want instant handlers to meet the latency target", s)
+    }
+
+    record(t, m, 1, codes.Unavailable)
+    rec := httptest.NewRecorder()
+    m.StatusHandler(rec, httptest.NewRequest(http.MethodGet, "/slo/status", nil))
+    if rec.Code != http.StatusServiceUnavailable {
+        t.Errorf("status code = %d, want 503 once availability is below the SLO", rec.Code)
+    }
+    var body Status
+    if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+        t.Fatal(err)
+    }
+    if body.AvailabilityOK || body.Errors != 2 {
+        t.Errorf("body = %+v", body)
+    }
+}
+
+func TestAlertsHandler(t *testing.T) {
+    m, err := NewMonitor(testSLO, prometheus.NewRegistry())
+    if err != nil {
+        t.Fatal(err)
+    }
+    rec := httptest.NewRecorder()
+    m.AlertsHandler(rec, httptest.NewRequest(http.MethodGet, "/slo/alerts.yaml", nil))
+    if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/yaml" {
+        t.Errorf("GET: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+    }
+    rec = httptest.NewRecorder()
+    m.AlertsHandler(rec, httptest.NewRequest(http.MethodPost, "/slo/alerts.yaml", nil))
+    if rec.Code != http.StatusMethodNotAllowed {
+        t.Errorf("POST: %d, want 405", rec.Code)
+    }
+}
+
+func TestQuantile(t *testing.T) {
+    h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_seconds", Buckets: []float64{0.01, 0.1, 1}})
+    for i := 0; i < 90; i++ {
+        h.Observe(0.005)
+    }
+    for i := 0; i < 10; i++ {
+        h.Observe(0.05)
+    }
+    metrics, err := collect(h)
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct{ q, want float64 }{
+        {0.5, 0.005 * 100 / 90},
+        {0.9, 0.01},
+        {0.95, 0.01 + 0.09*0.5},
+        {0.99, 0.01 + 0.09*0.9},
+    }
+    for _, tt := range tests {
+        if got := quantile(tt.q, metrics); math.Abs(got-tt.want) > 1e-9 {
+            t.Errorf("quantile(%v) = %v, want %v", tt.q, got, tt.want)
+        }
+    }
+    if got := quantile(0.99, nil); got != 0 {
+        t.Errorf("quantile of no observations = %v, want 0", got)
+    }
+}
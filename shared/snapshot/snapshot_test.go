@@ -0,0 +1,53 @@
+package snapshot
+
+import (
+    "bytes"
+    "compress/gzip"
+    "context"
+    "encoding/json"
+    "errors"
+    "testing"
+)
+
+func gzipLines(t *testing.T, lines ...interface{}) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    gz := gzip.NewWriter(&buf)
+    enc := json.NewEncoder(gz)
+    for _, line := range lines {
+        if err := enc.Encode(line); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := gz.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return buf.Bytes()
+}
+
+// TestRestoreRejectsForeignSnapshots checks that snapshots of another
+// service or format fail before the database is touched.
+func TestRestoreRejectsForeignSnapshots(t *testing.T) {
+    tests := map[string][]byte{
+        "not gzip":       []byte(`{"format_version":1}`),
+        "empty":          gzipLines(t),
+        "format version": gzipLines(t, Header{FormatVersion: FormatVersion + 1, Service: "catalog-service"}),
+        "service":        gzipLines(t, Header{FormatVersion: FormatVersion, Service: "orders-service"}),
+    }
+    for name, data := range tests {
+        // A nil database would panic if Restore got as far as using it.
+        _, err := Restore(context.Background(), nil, bytes.NewReader(data), "catalog-service", []string{"items"})
+        if !errors.Is(err, ErrMismatch) {
+            t.Errorf("%s: Restore = %v, want ErrMismatch", name, err)
+        }
+    }
+}
+
+func TestNumberKeepsIntegersExact(t *testing.T) {
+    if got := number("9007199254740993"); got != int64(9007199254740993) {
+        t.Errorf("number(2^53+1) = %v (%T)", got, got)
+    }
+    if got := number("12.5"); got != 12.5 {
+        t.Errorf("number(12.5) = %v (%T)", got, got)
+    }
+}
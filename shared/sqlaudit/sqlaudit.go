@@ -0,0 +1,191 @@
+// Package sqlaudit looks for request data that was interpolated into SQL
+// instead of being passed as a parameter.
+package sqlaudit
+
+import (
+    "context"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "google.golang.org/grpc"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "gorm.io/gorm"
+)
+
+var risks = prometheus.NewCounter(prometheus.CounterOpts{
+    Name: "sql_injection_risk_total",
+    Help: "Number of SQL statements found to contain interpolated request data.",
+})
+
+func init() {
+    prometheus.MustRegister(risks)
+}
+
+// Plugin is a GORM plugin that looks for request data that was interpolated
+// into SQL instead of being passed as a parameter. GORM sends parameters
+// separately, so a value from the current request showing up as a quoted
+// literal in the statement means someone built the SQL by hand, e.g.
+//
+//	db.Where(fmt.Sprintf("name = '%s'", req.Name))    // flagged
+//	db.First(&product, req.Id)                         // flagged for "1 OR 1=1"
+//	db.Where("name = ?", req.Name)                     // not flagged
+//	db.First(&product, id)                             // not flagged, id is a uint64
+//	db.Where("deleted_at IS NULL AND kind = 'sale'")   // not flagged
+//
+// A statement is flagged when one of its non-numeric quoted literals equals a
+// string field of the request, or when a request value containing SQL syntax,
+// such as a space, quote or "=", appears verbatim in it. Flagged statements
+// are logged and counted in sql_injection_risk_total; they are not blocked.
+// Only statements run under UnaryServerInterceptor are checked.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+    return "sql_audit"
+}
+
+func (p Plugin) Initialize(db *gorm.DB) error {
+    callbacks := db.Callback()
+    if err := callbacks.Query().After("gorm:query").Register("sql_audit:check", p.check); err != nil {
+        return err
+    }
+    if err := callbacks.Row().After("gorm:row").Register("sql_audit:check", p.check); err != nil {
+        return err
+    }
+    if err := callbacks.Raw().After("gorm:raw").Register("sql_audit:check", p.check); err != nil {
+        return err
+    }
+    if err := callbacks.Update().After("gorm:update").Register("sql_audit:check", p.check); err != nil {
+        return err
+    }
+    return callbacks.Delete().After("gorm:delete").Register("sql_audit:check", p.check)
+}
+
+type auditedRequestKey struct{}
+
+type auditedRequest struct {
+    method string
+    values []string
+}
+
+// UnaryServerInterceptor records the request's string fields so that
+// Plugin can look for them in the SQL it runs.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    if msg, ok := req.(proto.Message); ok {
+        var values []string
+        collectStrings(msg.ProtoReflect(), &values)
+        if len(values) > 0 {
+            ctx = context.WithValue(ctx, auditedRequestKey{}, &auditedRequest{method: info.FullMethod, values: values})
+        }
+    }
+    return handler(ctx, req)
+}
+
+func collectStrings(msg protoreflect.Message, values *[]string) {
+    msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+        switch {
+        case fd.IsList():
+            list := v.List()
+            for i := 0; i < list.Len(); i++ {
+                collectValue(fd, list.Get(i), values)
+            }
+        case fd.IsMap():
+            v.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+                if fd.MapKey().Kind() == protoreflect.StringKind {
+                    appendValue(key.String(), values)
+                }
+                collectValue(fd.MapValue(), value, values)
+                return true
+            })
+        default:
+            collectValue(fd, v, values)
+        }
+        return true
+    })
+}
+
+func collectValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, values *[]string) {
+    switch fd.Kind() {
+    case protoreflect.StringKind:
+        appendValue(v.String(), values)
+    case protoreflect.MessageKind, protoreflect.GroupKind:
+        collectStrings(v.Message(), values)
+    }
+}
+
+func appendValue(value string, values *[]string) {
+    if value != "" && !isNumeric(value) {
+        *values = append(*values, value)
+    }
+}
+
+func (Plugin) check(db *gorm.DB) {
+    if db.Statement == nil || db.Statement.Context == nil {
+        return
+    }
+    req, ok := db.Statement.Context.Value(auditedRequestKey{}).(*auditedRequest)
+    if !ok {
+        return
+    }
+    sql := db.Statement.SQL.String()
+    if value, ok := interpolatedValue(sql, req.values); ok {
+        risks.Inc()
+        log.Printf("ERROR: possible SQL injection in %s: request value %q is interpolated into %q", req.method, value, sql)
+    }
+}
+
+// sqlSyntax holds the characters a value needs to change a statement's
+// meaning when it is interpolated unquoted, as in "1 OR 1=1", or breaks out
+// of a literal, as in "x' OR 'a'='a".
+const sqlSyntax = " \t\n'\";=()"
+
+// interpolatedValue returns the first of values that appears in sql as data
+// rather than as a parameter.
+func interpolatedValue(sql string, values []string) (string, bool) {
+    literals := quotedLiterals(sql)
+    for _, value := range values {
+        if strings.ContainsAny(value, sqlSyntax) && strings.Contains(sql, value) {
+            return value, true
+        }
+        for _, literal := range literals {
+            if literal == value {
+                return value, true
+            }
+        }
+    }
+    return "", false
+}
+
+// quotedLiterals returns the non-numeric single-quoted string literals in sql,
+// with doubled quotes unescaped.
+func quotedLiterals(sql string) []string {
+    var literals []string
+    for i := 0; i < len(sql); i++ {
+        if sql[i] != '\'' {
+            continue
+        }
+        var literal strings.Builder
+        for i++; i < len(sql); i++ {
+            if sql[i] == '\'' {
+                if i+1 < len(sql) && sql[i+1] == '\'' {
+                    literal.WriteByte('\'')
+                    i++
+                    continue
+                }
+                break
+            }
+            literal.WriteByte(sql[i])
+        }
+        if s := literal.String(); s != "" && !isNumeric(s) {
+            literals = append(literals, s)
+        }
+    }
+    return literals
+}
+
+func isNumeric(s string) bool {
+    _, err := strconv.ParseFloat(s, 64)
+    return err == nil
+}
@@ -0,0 +1,85 @@
+package sqlaudit
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+    "google.golang.org/grpc"
+    "google.golang.org/protobuf/types/known/wrapperspb"
+
+    "shared/testdb"
+)
+
+func TestInterpolatedValueExamples(t *testing.T) {
+    tests := []struct {
+        name   string
+        sql    string
+        values []string
+        want   string
+    }{
+        // Flagged: request data built into the statement.
+        {"Sprintf into a literal", `SELECT * FROM "products" WHERE name = 'Mug'`, []string{"Mug"}, "Mug"},
+        {"quote breaking out of a literal", `SELECT * FROM "products" WHERE name = 'x' OR 'a'='a'`, []string{"x' OR 'a'='a"}, "x' OR 'a'='a"},
+        {"escaped quote in a literal", `SELECT * FROM "users" WHERE name = 'O''Brien'`, []string{"O'Brien"}, "O'Brien"},
+        {"string id used as a condition", `SELECT * FROM "products" WHERE (1 OR 1=1) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT 1`, []string{"1 OR 1=1"}, "1 OR 1=1"},
+        {"stacked statement", `SELECT * FROM "products" WHERE id = 1; DROP TABLE products`, []string{"1; DROP TABLE products"}, "1; DROP TABLE products"},
+
+        // Not flagged.
+        {"bound parameter", `SELECT * FROM "products" WHERE name = $1`, []string{"Mug"}, ""},
+        {"constant literal", `SELECT * FROM "products" WHERE deleted_at IS NULL AND kind = 'sale'`, []string{"Mug"}, ""},
+        {"plain word matching a column", `SELECT * FROM "products" WHERE "products"."id" = $1`, []string{"products", "id"}, ""},
+        {"value with syntax but not in the statement", `SELECT * FROM "products" WHERE name = $1`, []string{"Blue Mug"}, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, flagged := interpolatedValue(tt.sql, tt.values)
+            if got != tt.want || flagged != (tt.want != "") {
+                t.Errorf("interpolatedValue = %q, %v, want %q", got, flagged, tt.want)
+            }
+        })
+    }
+}
+
+func TestSQLAuditPluginCountsInterpolatedRequests(t *testing.T) {
+    db, mock := testdb.Mock(t)
+    if err := db.Use(Plugin{}); err != nil {
+        t.Fatal(err)
+    }
+    mock.MatchExpectationsInOrder(false)
+    for i := 0; i < 3; i++ {
+        mock.ExpectQuery(`SELECT \* FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+    }
+
+    info := &grpc.UnaryServerInfo{FullMethod: "/products.ProductService/GetProduct"}
+    run := func(query func(ctx context.Context, id string) error) float64 {
+        before := testutil.ToFloat64(risks)
+        _, err := UnaryServerInterceptor(context.Background(), wrapperspb.String("1 OR 1=1"), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+            return nil, query(ctx, req.(*wrapperspb.StringValue).Value)
+        })
+        if err != nil {
+            t.Fatal(err)
+        }
+        return testutil.ToFloat64(risks) - before
+    }
+
+    type Product struct{ ID uint }
+    var products []Product
+    if n := run(func(ctx context.Context, id string) error {
+        return db.WithContext(ctx).Find(&products, id).Error
+    }); n != 1 {
+        t.Errorf("string id as a condition counted %v times, want 1", n)
+    }
+    if n := run(func(ctx context.Context, id string) error {
+        return db.WithContext(ctx).Where(fmt.Sprint("uuid = '", id, "'")).Find(&products).Error
+    }); n != 1 {
+        t.Errorf("hand-built condition counted %v times, want 1", n)
+    }
+    if n := run(func(ctx context.Context, id string) error {
+        return db.WithContext(ctx).Where("uuid = ?", id).Find(&products).Error
+    }); n != 0 {
+        t.Errorf("bound condition counted %v times, want 0", n)
+    }
+}
@@ -0,0 +1,52 @@
+// Package statementtimeout lets Postgres cancel runaway statements itself,
+// even if the application never cancels the request's context, and reports
+// the statements it cancels as DeadlineExceeded.
+package statementtimeout
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5/pgconn"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// queryCanceled is the SQLSTATE Postgres reports for a statement it cancelled,
+// e.g. because it ran past statement_timeout.
+const queryCanceled = "57014"
+
+// DSN adds statement_timeout to a key=value DSN. pgx sends it as a runtime
+// parameter on every new connection. A timeout of zero or less leaves the DSN
+// unchanged.
+func DSN(dsn string, timeout time.Duration) string {
+    if timeout <= 0 {
+        return dsn
+    }
+    return fmt.Sprintf("%s statement_timeout=%d", dsn, timeout.Milliseconds())
+}
+
+// Error turns a statement Postgres cancelled into DeadlineExceeded, rather
+// than the Unknown status a raw error becomes. Other errors are returned
+// unchanged.
+func Error(err error) error {
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) && pgErr.Code == queryCanceled {
+        return status.Error(codes.DeadlineExceeded, "database statement timed out")
+    }
+    return err
+}
+
+// UnaryServerInterceptor applies Error to a unary RPC's error.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    res, err := handler(ctx, req)
+    return res, Error(err)
+}
+
+// StreamServerInterceptor applies Error to a streaming RPC's error.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    return Error(handler(srv, ss))
+}
@@ -0,0 +1,79 @@
+package statementtimeout
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/jackc/pgx/v5/pgconn"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+
+    "shared/testdb"
+)
+
+func TestDSN(t *testing.T) {
+    const dsn = "host=products-db port=5432"
+    if got := DSN(dsn, 0); got != dsn {
+        t.Errorf("DSN without a timeout = %q, want it unchanged", got)
+    }
+    if got, want := DSN(dsn, 2500*time.Millisecond), dsn+" statement_timeout=2500"; got != want {
+        t.Errorf("DSN = %q, want %q", got, want)
+    }
+}
+
+func TestError(t *testing.T) {
+    cancelled := &pgconn.PgError{Code: queryCanceled, Message: "canceling statement due to statement timeout"}
+    for name, tt := range map[string]struct {
+        err  error
+        want codes.Code
+    }{
+        "cancelled":          {cancelled, codes.DeadlineExceeded},
+        "wrapped":            {fmt.Errorf("list products: %w", cancelled), codes.DeadlineExceeded},
+        "unique violation":   {&pgconn.PgError{Code: "23505"}, codes.Unknown},
+        "status":             {status.Error(codes.NotFound, "no such product"), codes.NotFound},
+        "not a driver error": {errors.New("boom"), codes.Unknown},
+        "nil":                {nil, codes.OK},
+    } {
+        if got := status.Code(Error(tt.err)); got != tt.want {
+            t.Errorf("%s: Error(%v) has code %v, want %v", name, tt.err, got, tt.want)
+        }
+    }
+}
+
+// TestSlowStatementIsCancelledByPostgres opens a connection with a 100ms
+// statement timeout and runs a statement that would take a second, under a
+// context that is never cancelled.
+func TestSlowStatementIsCancelledByPostgres(t *testing.T) {
+    testdb.Postgres(t)
+    db, err := gorm.Open(postgres.Open(DSN(os.Getenv("TEST_DATABASE_DSN"), 100*time.Millisecond)), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() {
+        if conn, err := db.DB(); err == nil {
+            conn.Close()
+        }
+    })
+
+    start := time.Now()
+    _, err = UnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+        return nil, db.WithContext(ctx).Exec("SELECT pg_sleep(1)").Error
+    })
+    if status.Code(err) != codes.DeadlineExceeded {
+        t.Errorf("slow statement = %v, want DeadlineExceeded", err)
+    }
+    if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+        t.Errorf("slow statement took %v, want it cancelled after about 100ms", elapsed)
+    }
+    if err := db.Exec("SELECT 1").Error; err != nil {
+        t.Errorf("fast statement after the timeout: %v", err)
+    }
+}
@@ -0,0 +1,97 @@
+// Package testdb opens the databases that tests run against.
+package testdb
+
+import (
+    "fmt"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "gorm.io/driver/postgres"
+    "gorm.io/gorm"
+    "gorm.io/gorm/logger"
+)
+
+// Mock returns a GORM connection whose SQL is checked against the
+// expectations set on mock. Unexpected statements fail the test, as do
+// expectations left unmet when it ends.
+func Mock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+    t.Helper()
+    conn, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    db := open(t, conn)
+    t.Cleanup(func() {
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Error(err)
+        }
+    })
+    return db, mock
+}
+
+// PingMock is Mock with pings checked against the expectations too. GORM's
+// own ping on open is skipped.
+func PingMock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+    t.Helper()
+    conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return open(t, conn), mock
+}
+
+func open(t *testing.T, conn gorm.ConnPool) *gorm.DB {
+    t.Helper()
+    db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+        Logger:               logger.Default.LogMode(logger.Silent),
+        DisableAutomaticPing: true,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    return db
+}
+
+// Postgres connects to the PostgreSQL server in TEST_DATABASE_DSN, e.g.
+// "host=localhost user=user password=password dbname=products_test
+// port=5432 sslmode=disable", and returns a connection to a schema of its
+// own that is dropped when the test ends. The test is skipped when
+// TEST_DATABASE_DSN is not set.
+func Postgres(t *testing.T) *gorm.DB {
+    t.Helper()
+    dsn := os.Getenv("TEST_DATABASE_DSN")
+    if dsn == "" {
+        t.Skip("TEST_DATABASE_DSN is not set")
+    }
+    config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+    admin, err := gorm.Open(postgres.Open(dsn), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+    if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+        t.Fatal(err)
+    }
+
+    db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if conn, err := db.DB(); err == nil {
+        conn.SetMaxOpenConns(20)
+    }
+    t.Cleanup(func() {
+        if conn, err := db.DB(); err == nil {
+            conn.Close()
+        }
+        if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+            t.Errorf("dropping test schema %s: %v", schema, err)
+        }
+        if conn, err := admin.DB(); err == nil {
+            conn.Close()
+        }
+    })
+    return db
+}
@@ -0,0 +1,173 @@
+// Package tlsconfig builds the TLS settings of the services' gRPC servers,
+// and of the gateway's connections to them, from the environment, so that
+// old protocol versions and weak cipher suites are refused instead of taking
+// Go's defaults.
+package tlsconfig
+
+import (
+    "crypto/tls"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "google.golang.org/grpc/credentials"
+    "google.golang.org/grpc/credentials/insecure"
+)
+
+// Protocol versions accepted in TLSConfig.MinVersion.
+const (
+    TLS12 = "TLS12"
+    TLS13 = "TLS13"
+)
+
+// DefaultCipherSuites are the TLS 1.2 suites offered when TLS_CIPHER_SUITES
+// is not set: ECDHE key exchange for forward secrecy with AES-GCM.
+var DefaultCipherSuites = []uint16{
+    tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+    tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+    tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+    tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// curves maps the names accepted in TLS_CURVE_PREFERENCES to their IDs.
+var curves = map[string]tls.CurveID{
+    "X25519": tls.X25519,
+    "P256":   tls.CurveP256,
+    "P384":   tls.CurveP384,
+    "P521":   tls.CurveP521,
+}
+
+// TLSConfig names the protocol version, cipher suites and curves the server
+// accepts. Empty fields take the defaults: TLS 1.2 or later, with
+// DefaultCipherSuites and Go's curve preferences.
+type TLSConfig struct {
+    // MinVersion is TLS12 or TLS13.
+    MinVersion string
+    // CipherSuites are names such as TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+    CipherSuites []string
+    // CurvePreferences are X25519, P256, P384 or P521, most preferred first.
+    CurvePreferences []string
+}
+
+// FromEnv reads TLS_MIN_VERSION, TLS_CIPHER_SUITES and
+// TLS_CURVE_PREFERENCES, the latter two comma-separated lists.
+func FromEnv() TLSConfig {
+    return TLSConfig{
+        MinVersion:       strings.TrimSpace(os.Getenv("TLS_MIN_VERSION")),
+        CipherSuites:     envList("TLS_CIPHER_SUITES"),
+        CurvePreferences: envList("TLS_CURVE_PREFERENCES"),
+    }
+}
+
+func envList(key string) []string {
+    var values []string
+    for _, value := range strings.Split(os.Getenv(key), ",") {
+        if value = strings.TrimSpace(value); value != "" {
+            values = append(values, value)
+        }
+    }
+    return values
+}
+
+// BuildTLSConfig turns cfg into a tls.Config without certificates. Unknown
+// versions, cipher suites and curves are errors, as are suites Go considers
+// insecure. TLS 1.3 suites are accepted but ignored: Go does not let them be
+// chosen.
+func BuildTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+    config := &tls.Config{MinVersion: tls.VersionTLS12}
+    switch strings.ToUpper(cfg.MinVersion) {
+    case "", TLS12:
+    case TLS13:
+        config.MinVersion = tls.VersionTLS13
+    default:
+        return nil, fmt.Errorf("unknown TLS minimum version %q, want %s or %s", cfg.MinVersion, TLS12, TLS13)
+    }
+
+    if len(cfg.CipherSuites) == 0 {
+        config.CipherSuites = DefaultCipherSuites
+    } else {
+        suites, err := cipherSuites(cfg.CipherSuites)
+        if err != nil {
+            return nil, err
+        }
+        if len(suites) == 0 && config.MinVersion < tls.VersionTLS13 {
+            return nil, fmt.Errorf("no TLS 1.2 cipher suites given; use TLS minimum version %s to allow only TLS 1.3", TLS13)
+        }
+        config.CipherSuites = suites
+    }
+
+    for _, name := range cfg.CurvePreferences {
+        curve, ok := curves[strings.ToUpper(name)]
+        if !ok {
+            return nil, fmt.Errorf("unknown curve %q", name)
+        }
+        config.CurvePreferences = append(config.CurvePreferences, curve)
+    }
+    return config, nil
+}
+
+// cipherSuites looks up the TLS 1.2 suites among names. TLS 1.3 suites are
+// left out.
+func cipherSuites(names []string) ([]uint16, error) {
+    known := make(map[string]*tls.CipherSuite)
+    for _, suite := range tls.CipherSuites() {
+        known[suite.Name] = suite
+    }
+    insecure := make(map[string]bool)
+    for _, suite := range tls.InsecureCipherSuites() {
+        insecure[suite.Name] = true
+    }
+
+    var ids []uint16
+    for _, name := range names {
+        name = strings.ToUpper(name)
+        suite, ok := known[name]
+        if !ok {
+            if insecure[name] {
+                return nil, fmt.Errorf("cipher suite %s is insecure", name)
+            }
+            return nil, fmt.Errorf("unknown cipher suite %q", name)
+        }
+        if supportsTLS12(suite) {
+            ids = append(ids, suite.ID)
+        }
+    }
+    return ids, nil
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+    for _, version := range suite.SupportedVersions {
+        if version == tls.VersionTLS12 {
+            return true
+        }
+    }
+    return false
+}
+
+// Enabled reports whether TLS_CERT_FILE is set, in which case the gRPC
+// server accepts only TLS connections.
+func Enabled() bool {
+    return os.Getenv("TLS_CERT_FILE") != ""
+}
+
+// ServerCredentials loads the certificate in TLS_CERT_FILE and TLS_KEY_FILE
+// and applies the TLS_MIN_VERSION, TLS_CIPHER_SUITES and
+// TLS_CURVE_PREFERENCES settings. Without a certificate the server accepts
+// plaintext connections.
+func ServerCredentials() (credentials.TransportCredentials, error) {
+    if !Enabled() {
+        log.Println("TLS_CERT_FILE not set, serving gRPC without TLS")
+        return insecure.NewCredentials(), nil
+    }
+    config, err := BuildTLSConfig(FromEnv())
+    if err != nil {
+        return nil, err
+    }
+    cert, err := tls.LoadX509KeyPair(os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE"))
+    if err != nil {
+        return nil, fmt.Errorf("loading certificate: %w", err)
+    }
+    config.Certificates = []tls.Certificate{cert}
+    return credentials.NewTLS(config), nil
+}
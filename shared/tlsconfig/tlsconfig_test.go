@@ -0,0 +1,220 @@
+package tlsconfig
+
+import (
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "math/big"
+    "net"
+    "strings"
+    "testing"
+    "time"
+)
+
+// selfSigned returns a certificate for localhost and the pool that trusts
+// it.
+func selfSigned(t *testing.T) (tls.Certificate, *x509.CertPool) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(1),
+        Subject:      pkix.Name{CommonName: "localhost"},
+        DNSNames:     []string{"localhost"},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+        KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+        ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+        IsCA:         true,
+
+        BasicConstraintsValid: true,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+    pool := x509.NewCertPool()
+    pool.AddCert(cert)
+    return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// serve accepts TLS connections configured by cfg, reporting the outcome of
+// each handshake on the returned channel.
+func serve(t *testing.T, cfg TLSConfig) (string, *x509.CertPool, <-chan error) {
+    t.Helper()
+    config, err := BuildTLSConfig(cfg)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, pool := selfSigned(t)
+    config.Certificates = []tls.Certificate{cert}
+    lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { lis.Close() })
+
+    handshakes := make(chan error, 8)
+    go func() {
+        for {
+            conn, err := lis.Accept()
+            if err != nil {
+                return
+            }
+            handshakes <- conn.(*tls.Conn).Handshake()
+            conn.Close()
+        }
+    }()
+    return lis.Addr().String(), pool, handshakes
+}
+
+// dial handshakes with addr, offering versions min to max and, if given,
+// only the cipher suite.
+func dial(addr string, pool *x509.CertPool, min, max uint16, suites ...uint16) error {
+    conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, &tls.Config{
+        RootCAs:      pool,
+        ServerName:   "localhost",
+        MinVersion:   min,
+        MaxVersion:   max,
+        CipherSuites: suites,
+    })
+    if err != nil {
+        return err
+    }
+    return conn.Close()
+}
+
+func TestHandshakeRefusesOldVersions(t *testing.T) {
+    addr, pool, handshakes := serve(t, TLSConfig{})
+    tests := []struct {
+        name     string
+        min, max uint16
+        ok       bool
+    }{
+        {"TLS 1.0", tls.VersionTLS10, tls.VersionTLS10, false},
+        {"TLS 1.1", tls.VersionTLS11, tls.VersionTLS11, false},
+        {"TLS 1.0 to 1.1", tls.VersionTLS10, tls.VersionTLS11, false},
+        {"TLS 1.2", tls.VersionTLS12, tls.VersionTLS12, true},
+        {"TLS 1.3", tls.VersionTLS13, tls.VersionTLS13, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            clientErr := dial(addr, pool, tt.min, tt.max)
+            serverErr := <-handshakes
+            if tt.ok {
+                if clientErr != nil || serverErr != nil {
+                    t.Errorf("handshake failed: client %v, server %v", clientErr, serverErr)
+                }
+                return
+            }
+            if clientErr == nil {
+                t.Fatal("handshake succeeded, want it refused")
+            }
+            // The server is the side that refuses.
+            if serverErr == nil || !strings.Contains(serverErr.Error(), "unsupported versions") {
+                t.Errorf("server handshake error = %v, want unsupported versions", serverErr)
+            }
+        })
+    }
+}
+
+func TestHandshakeTLS13Only(t *testing.T) {
+    addr, pool, handshakes := serve(t, TLSConfig{MinVersion: TLS13})
+    if err := dial(addr, pool, tls.VersionTLS12, tls.VersionTLS12); err == nil {
+        t.Error("TLS 1.2 handshake succeeded against a TLS 1.3 minimum")
+    }
+    <-handshakes
+    if err := dial(addr, pool, tls.VersionTLS12, tls.VersionTLS13); err != nil {
+        t.Errorf("TLS 1.3 handshake: %v", err)
+    }
+}
+
+func TestHandshakeRefusesOtherCipherSuites(t *testing.T) {
+    addr, pool, handshakes := serve(t, TLSConfig{})
+    // RSA key exchange has no forward secrecy, so it is not a default.
+    if err := dial(addr, pool, tls.VersionTLS12, tls.VersionTLS12, tls.TLS_RSA_WITH_AES_128_GCM_SHA256); err == nil {
+        t.Error("handshake with a suite outside the defaults succeeded")
+    }
+    <-handshakes
+    if err := dial(addr, pool, tls.VersionTLS12, tls.VersionTLS12, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384); err != nil {
+        t.Errorf("handshake with a default suite: %v", err)
+    }
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+    config, err := BuildTLSConfig(TLSConfig{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if config.MinVersion != tls.VersionTLS12 || len(config.CipherSuites) != len(DefaultCipherSuites) || config.CurvePreferences != nil {
+        t.Errorf("default config = min %x, suites %v, curves %v", config.MinVersion, config.CipherSuites, config.CurvePreferences)
+    }
+
+    config, err = BuildTLSConfig(TLSConfig{
+        MinVersion:       "tls13",
+        CipherSuites:     []string{"TLS_AES_128_GCM_SHA256", "tls_ecdhe_rsa_with_chacha20_poly1305_sha256"},
+        CurvePreferences: []string{"x25519", "P384"},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if config.MinVersion != tls.VersionTLS13 {
+        t.Errorf("min version = %x, want TLS 1.3", config.MinVersion)
+    }
+    // The TLS 1.3 suite is dropped, as Go chooses those itself.
+    if len(config.CipherSuites) != 1 || config.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 {
+        t.Errorf("suites = %v, want only the ChaCha20 suite", config.CipherSuites)
+    }
+    if len(config.CurvePreferences) != 2 || config.CurvePreferences[0] != tls.X25519 || config.CurvePreferences[1] != tls.CurveP384 {
+        t.Errorf("curves = %v, want X25519 then P-384", config.CurvePreferences)
+    }
+}
+
+func TestBuildTLSConfigErrors(t *testing.T) {
+    tests := map[string]struct {
+        cfg  TLSConfig
+        want string
+    }{
+        "TLS 1.1":              {TLSConfig{MinVersion: "TLS11"}, "unknown TLS minimum version"},
+        "TLS 1.0":              {TLSConfig{MinVersion: "TLS10"}, "unknown TLS minimum version"},
+        "insecure suite":       {TLSConfig{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, "is insecure"},
+        "CBC suite":            {TLSConfig{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256"}}, "is insecure"},
+        "unknown suite":        {TLSConfig{CipherSuites: []string{"TLS_MADE_UP"}}, "unknown cipher suite"},
+        "only TLS 1.3 suites":  {TLSConfig{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}, "no TLS 1.2 cipher suites"},
+        "unknown curve":        {TLSConfig{CurvePreferences: []string{"P224"}}, "unknown curve"},
+        "unknown curve casing": {TLSConfig{CurvePreferences: []string{"curve25519"}}, "unknown curve"},
+    }
+    for name, tt := range tests {
+        t.Run(name, func(t *testing.T) {
+            _, err := BuildTLSConfig(tt.cfg)
+            if err == nil || !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("err = %v, want one containing %q", err, tt.want)
+            }
+        })
+    }
+    if _, err := BuildTLSConfig(TLSConfig{MinVersion: TLS13, CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}); err != nil {
+        t.Errorf("only TLS 1.3 suites with a TLS 1.3 minimum: %v", err)
+    }
+}
+
+func TestFromEnv(t *testing.T) {
+    t.Setenv("TLS_MIN_VERSION", " TLS13 ")
+    t.Setenv("TLS_CIPHER_SUITES", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, ,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+    t.Setenv("TLS_CURVE_PREFERENCES", "")
+    cfg := FromEnv()
+    if cfg.MinVersion != TLS13 || len(cfg.CipherSuites) != 2 || cfg.CipherSuites[1] != "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384" || cfg.CurvePreferences != nil {
+        t.Errorf("FromEnv = %+v", cfg)
+    }
+    if _, err := BuildTLSConfig(cfg); err != nil {
+        t.Error(err)
+    }
+}
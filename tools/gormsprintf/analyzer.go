@@ -0,0 +1,109 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer flags SQL built with fmt.Sprintf and handed to GORM, which skips
+// parameter binding and is open to SQL injection:
+//
+//	db.Where(fmt.Sprintf("name = '%s'", name))  // flagged
+//	query := fmt.Sprintf("name = '%s'", name)
+//	db.Raw(query)                               // flagged
+//	db.Where("name = ?", name)                  // not flagged
+//	db.Order(fmt.Sprint("id"))                  // not flagged
+//
+// Only direct calls and local variables assigned from fmt.Sprintf are
+// tracked.
+var Analyzer = &analysis.Analyzer{
+	Name:     "gormsprintf",
+	Doc:      "report fmt.Sprintf results passed to gorm.DB methods",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const gormPath = "gorm.io/gorm"
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Variables assigned from fmt.Sprintf anywhere in the package.
+	formatted := make(map[types.Object]bool)
+	inspect.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return
+			}
+			for i, rhs := range n.Rhs {
+				if ident, ok := n.Lhs[i].(*ast.Ident); ok && isSprintf(pass, rhs) {
+					if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+						formatted[obj] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) != len(n.Values) {
+				return
+			}
+			for i, value := range n.Values {
+				if isSprintf(pass, value) {
+					if obj := pass.TypesInfo.ObjectOf(n.Names[i]); obj != nil {
+						formatted[obj] = true
+					}
+				}
+			}
+		}
+	})
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		method, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || !isGormDBMethod(method) {
+			return
+		}
+		for _, arg := range call.Args {
+			if isSprintf(pass, arg) {
+				pass.Reportf(arg.Pos(), "fmt.Sprintf result passed to gorm.DB.%s; use ? placeholders instead", method.Name())
+				continue
+			}
+			if ident, ok := ast.Unparen(arg).(*ast.Ident); ok && formatted[pass.TypesInfo.ObjectOf(ident)] {
+				pass.Reportf(arg.Pos(), "%s is built with fmt.Sprintf and passed to gorm.DB.%s; use ? placeholders instead", ident.Name, method.Name())
+			}
+		}
+	})
+	return nil, nil
+}
+
+func isSprintf(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.FullName() == "fmt.Sprintf"
+}
+
+// isGormDBMethod reports whether fn is a method of gorm.DB or *gorm.DB.
+func isGormDBMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "DB" && obj.Pkg() != nil && obj.Pkg().Path() == gormPath
+}
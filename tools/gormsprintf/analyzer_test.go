@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer runs the examples in testdata/src/examples. Lines marked
+// "want" must be reported and nothing else may be.
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "examples")
+}
@@ -0,0 +1,11 @@
+// Command gormsprintf reports fmt.Sprintf results passed to gorm.DB methods.
+// Run it through go vet from a service directory:
+//
+//	go vet -vettool=$(which gormsprintf) ./...
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
@@ -0,0 +1,31 @@
+package examples
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type Product struct{}
+
+func flagged(db *gorm.DB, name string) {
+	var product Product
+	db.Where(fmt.Sprintf("name = '%s'", name)).First(&product) // want `fmt.Sprintf result passed to gorm.DB.Where`
+
+	query := fmt.Sprintf("name = '%s'", name)
+	db.Raw(query) // want `query is built with fmt.Sprintf and passed to gorm.DB.Raw`
+
+	var condition = fmt.Sprintf("name = '%s'", name)
+	db.First(&product, (condition)) // want `condition is built with fmt.Sprintf and passed to gorm.DB.First`
+}
+
+func notFlagged(db *gorm.DB, name string) {
+	var product Product
+	db.Where("name = ?", name).First(&product)
+	db.Order(fmt.Sprint("id"))
+	db.Where("deleted_at IS NULL AND kind = 'sale'")
+
+	// Only values passed to gorm.DB are checked.
+	message := fmt.Sprintf("looking up %s", name)
+	fmt.Println(message)
+}
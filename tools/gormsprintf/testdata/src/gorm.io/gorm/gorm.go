@@ -0,0 +1,13 @@
+// Package gorm is a stand-in for gorm.io/gorm with just the methods the
+// examples call.
+package gorm
+
+type DB struct{}
+
+func (db *DB) Where(query interface{}, args ...interface{}) *DB { return db }
+
+func (db *DB) Raw(sql string, values ...interface{}) *DB { return db }
+
+func (db *DB) Order(value interface{}) *DB { return db }
+
+func (db *DB) First(dest interface{}, conds ...interface{}) *DB { return db }
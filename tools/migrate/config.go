@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// service is one database managed by the tool.
+type service struct {
+	Name           string   `yaml:"name"`
+	DatabaseURL    string   `yaml:"database_url"`
+	DatabaseURLEnv string   `yaml:"database_url_env"`
+	Migrations     string   `yaml:"migrations"`
+	DependsOn      []string `yaml:"depends_on"`
+}
+
+// databaseURL returns the URL to migrate, preferring DatabaseURLEnv if set.
+func (s service) databaseURL() string {
+	if s.DatabaseURLEnv != "" {
+		if url := os.Getenv(s.DatabaseURLEnv); url != "" {
+			return url
+		}
+	}
+	return s.DatabaseURL
+}
+
+type config struct {
+	Services []service `yaml:"services"`
+}
+
+// loadConfig reads path and resolves migrations directories relative to it.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	names := make(map[string]bool, len(c.Services))
+	for i, s := range c.Services {
+		if s.Name == "" || s.Migrations == "" || (s.DatabaseURL == "" && s.DatabaseURLEnv == "") {
+			return nil, fmt.Errorf("%s: service %d needs name, migrations and database_url", path, i+1)
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("%s: service %s is listed twice", path, s.Name)
+		}
+		names[s.Name] = true
+		if !filepath.IsAbs(s.Migrations) {
+			c.Services[i].Migrations = filepath.Join(filepath.Dir(path), s.Migrations)
+		}
+	}
+	for _, s := range c.Services {
+		for _, dep := range s.DependsOn {
+			if !names[dep] {
+				return nil, fmt.Errorf("%s: %s depends on unknown service %s", path, s.Name, dep)
+			}
+		}
+	}
+	return &c, nil
+}
+
+// ordered returns the services in dependency order, or only the named one.
+// Services with no ordering between them keep their order in the file.
+func (c *config) ordered(name string) ([]service, error) {
+	if name != "" {
+		for _, s := range c.Services {
+			if s.Name == name {
+				return []service{s}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown service %s", name)
+	}
+
+	byName := make(map[string]service, len(c.Services))
+	for _, s := range c.Services {
+		byName[s.Name] = s
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(c.Services))
+	var order []service
+	var visit func(s service, path []string) error
+	visit = func(s service, path []string) error {
+		switch state[s.Name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %v", append(path, s.Name))
+		}
+		state[s.Name] = visiting
+		for _, dep := range s.DependsOn {
+			if err := visit(byName[dep], append(path, s.Name)); err != nil {
+				return err
+			}
+		}
+		state[s.Name] = done
+		order = append(order, s)
+		return nil
+	}
+	for _, s := range c.Services {
+		if err := visit(s, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func reversed(services []service) []service {
+	out := make([]service, len(services))
+	for i, s := range services {
+		out[len(services)-1-i] = s
+	}
+	return out
+}
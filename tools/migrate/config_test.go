@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "migrate.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func names(services []service) []string {
+	var names []string
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestRepositoryConfig(t *testing.T) {
+	c, err := loadConfig("../../migrate.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := c.ordered("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(services), []string{"users-service", "products-service"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	for _, s := range services {
+		if want := filepath.Join("..", "..", "services", s.Name, "migrations"); s.Migrations != want {
+			t.Errorf("%s: migrations = %s, want %s relative to the config", s.Name, s.Migrations, want)
+		}
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	c, err := loadConfig(writeConfig(t, `
+services:
+  - {name: orders, database_url: postgres://orders, migrations: orders, depends_on: [users, products]}
+  - {name: products, database_url: postgres://products, migrations: products, depends_on: [users]}
+  - {name: users, database_url: postgres://users, migrations: users}
+  - {name: audit, database_url: postgres://audit, migrations: audit}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := c.ordered("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(services), []string{"users", "products", "orders", "audit"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if got, want := names(reversed(services)), []string{"audit", "orders", "products", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reversed = %v, want %v", got, want)
+	}
+
+	services, err = c.ordered("products")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(services), []string{"products"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ordered(products) = %v, want %v", got, want)
+	}
+	if _, err := c.ordered("billing"); err == nil {
+		t.Error("ordered(billing): want an error for an unknown service")
+	}
+}
+
+func TestDependencyCycle(t *testing.T) {
+	c, err := loadConfig(writeConfig(t, `
+services:
+  - {name: a, database_url: postgres://a, migrations: a, depends_on: [b]}
+  - {name: b, database_url: postgres://b, migrations: b, depends_on: [a]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ordered(""); err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("err = %v, want a dependency cycle", err)
+	}
+}
+
+func TestDatabaseURLEnv(t *testing.T) {
+	s := service{DatabaseURL: "postgres://default", DatabaseURLEnv: "MIGRATE_TEST_URL"}
+	t.Setenv("MIGRATE_TEST_URL", "")
+	if got := s.databaseURL(); got != "postgres://default" {
+		t.Errorf("unset: databaseURL() = %s", got)
+	}
+	t.Setenv("MIGRATE_TEST_URL", "postgres://override")
+	if got := s.databaseURL(); got != "postgres://override" {
+		t.Errorf("set: databaseURL() = %s", got)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	tests := map[string]string{
+		"missing migrations": `services: [{name: a, database_url: postgres://a}]`,
+		"missing database":   `services: [{name: a, migrations: a}]`,
+		"listed twice":       `services: [{name: a, database_url: postgres://a, migrations: a}, {name: a, database_url: postgres://a, migrations: a}]`,
+		"unknown dependency": `services: [{name: a, database_url: postgres://a, migrations: a, depends_on: [b]}]`,
+	}
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadConfig(writeConfig(t, yaml)); err == nil {
+				t.Error("want an error")
+			}
+		})
+	}
+}
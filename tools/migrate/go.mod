@@ -0,0 +1,10 @@
+module migrate
+
+go 1.26.0
+
+require (
+	github.com/golang-migrate/migrate/v4 v4.20.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/lib/pq v1.10.9 // indirect
@@ -0,0 +1,299 @@
+// Command migrate applies the SQL migrations of every service database in
+// the order given by migrate.yaml. Run it from the repository root:
+//
+//	migrate [-config migrate.yaml] [-dry-run] up [service]
+//	migrate [-config migrate.yaml] [-dry-run] down [service] [steps]
+//	migrate [-config migrate.yaml] status
+//	migrate [-config migrate.yaml] [-dry-run] reset
+//
+// Without a service, up migrates every service after its dependencies and
+// down rolls every service back in the reverse order. reset rolls everything
+// back and then applies every migration again. With -dry-run the SQL that
+// would run is printed instead of executed. The tool stops at the first
+// failure and exits 1, naming the service that failed.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+func main() {
+	configPath := flag.String("config", "migrate.yaml", "path to the migration config")
+	dryRun := flag.Bool("dry-run", false, "print the SQL that would run instead of running it")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [flags] up [service] | down [service] [steps] | status | reset")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	c, err := loadConfig(*configPath)
+	if err != nil {
+		fatal("", err)
+	}
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "up" && len(args) <= 1:
+		services, err := c.ordered(arg(args, 0))
+		if err != nil {
+			fatal("", err)
+		}
+		for _, s := range services {
+			run(s, *dryRun, up)
+		}
+	case cmd == "down" && len(args) <= 2:
+		steps := 0
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				fatal("", fmt.Errorf("steps must be a positive number, not %q", args[1]))
+			}
+		}
+		services, err := c.ordered(arg(args, 0))
+		if err != nil {
+			fatal("", err)
+		}
+		for _, s := range reversed(services) {
+			run(s, *dryRun, func(m *migrator) error { return m.down(steps) })
+		}
+	case cmd == "status" && len(args) == 0:
+		services, err := c.ordered("")
+		if err != nil {
+			fatal("", err)
+		}
+		for _, s := range services {
+			run(s, false, printStatus)
+		}
+	case cmd == "reset" && len(args) == 0:
+		services, err := c.ordered("")
+		if err != nil {
+			fatal("", err)
+		}
+		for _, s := range reversed(services) {
+			run(s, *dryRun, func(m *migrator) error { return m.down(0) })
+		}
+		for _, s := range services {
+			run(s, *dryRun, up)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func fatal(service string, err error) {
+	if service != "" {
+		fmt.Fprintf(os.Stderr, "migrate: %s: %v\n", service, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+	}
+	os.Exit(1)
+}
+
+// run opens s and applies fn to it, exiting if either fails.
+func run(s service, dryRun bool, fn func(*migrator) error) {
+	m, err := openMigrator(s, dryRun)
+	if err != nil {
+		fatal(s.Name, err)
+	}
+	defer m.close()
+	if err := fn(m); err != nil {
+		m.close()
+		fatal(s.Name, err)
+	}
+}
+
+// migrator wraps a golang-migrate instance with a second handle on the
+// migration files, used to list pending migrations and print their SQL.
+type migrator struct {
+	service service
+	dryRun  bool
+	m       *migrate.Migrate
+	files   source.Driver
+	out     io.Writer
+}
+
+func openMigrator(s service, dryRun bool) (*migrator, error) {
+	dir, err := filepath.Abs(s.Migrations)
+	if err != nil {
+		return nil, err
+	}
+	sourceURL := "file://" + filepath.ToSlash(dir)
+	files, err := source.Open(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("open migrations: %w", err)
+	}
+	m, err := migrate.New(sourceURL, s.databaseURL())
+	if err != nil {
+		files.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	return &migrator{service: s, dryRun: dryRun, m: m, files: files, out: os.Stdout}, nil
+}
+
+func (m *migrator) close() {
+	m.files.Close()
+	m.m.Close()
+}
+
+// version returns the applied version, or ok false if nothing is applied.
+func (m *migrator) version() (version uint, ok bool, err error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if dirty {
+		return 0, false, fmt.Errorf("version %d is dirty; fix the database and force the version before migrating", version)
+	}
+	return version, true, nil
+}
+
+// pending returns the versions after the applied one, oldest first.
+func (m *migrator) pending() ([]uint, error) {
+	current, applied, err := m.version()
+	if err != nil {
+		return nil, err
+	}
+	var versions []uint
+	v, err := m.files.First()
+	for err == nil {
+		if !applied || v > current {
+			versions = append(versions, v)
+		}
+		v, err = m.files.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// applied returns up to steps applied versions, newest first; 0 means all.
+func (m *migrator) applied(steps int) ([]uint, error) {
+	current, ok, err := m.version()
+	if err != nil || !ok {
+		return nil, err
+	}
+	versions := []uint{current}
+	for v := current; steps == 0 || len(versions) < steps; {
+		v, err = m.files.Prev(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func up(m *migrator) error {
+	versions, err := m.pending()
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Fprintf(m.out, "%s: up to date\n", m.service.Name)
+		return nil
+	}
+	if m.dryRun {
+		return m.printSQL(versions, m.files.ReadUp)
+	}
+	if err := m.m.Up(); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "%s: applied %d migration(s)\n", m.service.Name, len(versions))
+	return nil
+}
+
+// down rolls back steps migrations, or all of them if steps is 0.
+func (m *migrator) down(steps int) error {
+	versions, err := m.applied(steps)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Fprintf(m.out, "%s: nothing to roll back\n", m.service.Name)
+		return nil
+	}
+	if m.dryRun {
+		return m.printSQL(versions, m.files.ReadDown)
+	}
+	if steps == 0 {
+		err = m.m.Down()
+	} else {
+		err = m.m.Steps(-len(versions))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "%s: rolled back %d migration(s)\n", m.service.Name, len(versions))
+	return nil
+}
+
+func (m *migrator) printSQL(versions []uint, read func(uint) (io.ReadCloser, string, error)) error {
+	for _, v := range versions {
+		r, identifier, err := read(v)
+		if err != nil {
+			return fmt.Errorf("read migration %d: %w", v, err)
+		}
+		sql, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("read migration %d: %w", v, err)
+		}
+		fmt.Fprintf(m.out, "-- %s: %d %s\n%s\n", m.service.Name, v, identifier, sql)
+	}
+	return nil
+}
+
+func printStatus(m *migrator) error {
+	current, applied, err := m.version()
+	if err != nil {
+		return err
+	}
+	pending, err := m.pending()
+	if err != nil {
+		return err
+	}
+	version := "none"
+	if applied {
+		version = strconv.FormatUint(uint64(current), 10)
+	}
+	fmt.Fprintf(m.out, "%s: version %s, %d pending\n", m.service.Name, version, len(pending))
+	for _, v := range pending {
+		r, identifier, err := m.files.ReadUp(v)
+		if err != nil {
+			return err
+		}
+		r.Close()
+		fmt.Fprintf(m.out, "  %d %s\n", v, identifier)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// openFiles opens the migrations of the named service in migrate.yaml.
+func openFiles(t *testing.T, name string) (service, source.Driver) {
+	t.Helper()
+	c, err := loadConfig("../../migrate.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	services, err := c.ordered(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Abs(services[0].Migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := source.Open("file://" + filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { files.Close() })
+	return services[0], files
+}
+
+func TestBaselineMigrations(t *testing.T) {
+	tables := map[string][]string{
+		"users-service":    {"users", "user_preferences", "self_test_probes"},
+		"products-service": {"products", "discount_codes", "product_outbox", "price_alerts", "quota_usages", "tags", "product_tags", "product_tag_bitmaps"},
+	}
+	for name, want := range tables {
+		t.Run(name, func(t *testing.T) {
+			s, files := openFiles(t, name)
+			first, err := files.First()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first != 1 {
+				t.Fatalf("first version = %d, want 1", first)
+			}
+
+			var out bytes.Buffer
+			m := &migrator{service: s, dryRun: true, files: files, out: &out}
+			if err := m.printSQL([]uint{first}, files.ReadUp); err != nil {
+				t.Fatal(err)
+			}
+			up := out.String()
+			if !strings.HasPrefix(up, "-- "+name+": 1 baseline\n") {
+				t.Errorf("dry run does not start with the service and version:\n%.80s", up)
+			}
+			for _, table := range want {
+				if !strings.Contains(up, `CREATE TABLE IF NOT EXISTS "`+table+`"`) && !strings.Contains(up, "CREATE TABLE IF NOT EXISTS "+table+" ") {
+					t.Errorf("baseline does not create %s", table)
+				}
+			}
+			// The baseline also runs against databases GORM already
+			// migrated, so it must not create anything unconditionally.
+			for _, line := range strings.Split(up, "\n") {
+				if strings.HasPrefix(line, "CREATE ") && !strings.Contains(line, "IF NOT EXISTS") {
+					t.Errorf("not idempotent: %s", line)
+				}
+			}
+
+			out.Reset()
+			if err := m.printSQL([]uint{first}, files.ReadDown); err != nil {
+				t.Fatal(err)
+			}
+			for _, table := range want {
+				if !strings.Contains(out.String(), "DROP TABLE IF EXISTS "+table+";") {
+					t.Errorf("down migration does not drop %s", table)
+				}
+			}
+		})
+	}
+}